@@ -0,0 +1,248 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/export"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/logging"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+// maxBundleScreenshots 是证据包中最多下载的截图数量
+const maxBundleScreenshots = 20
+
+// maxScreenshotSize 是单个截图的大小上限
+const maxScreenshotSize = 5 << 20
+
+var (
+	advisoryBundleOutput string
+	bundleNoScreenshots  bool
+)
+
+// runAdvisoryBundle 获取漏洞详情页并保存为ZIP证据包
+func runAdvisoryBundle(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return cmd.Help()
+	}
+	wlbID, err := model.ParseWLBID(args[0])
+	if err != nil {
+		return err
+	}
+
+	rec := &pageRecorder{HTTPClient: crawler.NewClient(clientOptions()...)}
+	c := newCrawler(crawler.WithHTTPClient(rec))
+	result, err := c.CrawlExploit(wlbID.String(), "", "")
+	if err != nil {
+		return fmt.Errorf(T("爬取失败: %v"), err)
+	}
+	vuln := result.(*model.Vulnerability)
+	attachAISummary(vuln, "")
+
+	bundle := &export.AdvisoryBundle{
+		Vulnerability: vuln,
+		HTML:          rec.pages[wlbID.Path()],
+		Snippets:      advisorySnippets(vuln.Content),
+		Created:       time.Now(),
+		Dates:         displayDates,
+	}
+	if !bundleNoScreenshots {
+		fetcher := crawler.NewReferenceFetcher(crawler.WithReferenceTimeout(clientTimeout), crawler.WithReferenceMaxSize(maxScreenshotSize))
+		bundle.Screenshots, bundle.Missing = fetchScreenshots(fetcher.Fetch, advisoryImages(bundle.HTML, vuln))
+		for _, m := range bundle.Missing {
+			logging.Printf(T("下载截图 %s 失败: %s\n"), m.Source, m.Reason)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := bundle.WriteZip(&buf); err != nil {
+		return err
+	}
+	output := advisoryBundleOutput
+	if output == "" {
+		output = wlbID.String() + ".zip"
+	}
+	if err := c.SaveFile(buf.Bytes(), output); err != nil {
+		return fmt.Errorf(T("保存结果失败: %v"), err)
+	}
+	if quietOutput {
+		return nil
+	}
+	fmt.Printf("%s %s %s\n",
+		text.Colors{text.FgHiGreen}.Sprint(T("✅ 已保存:")),
+		text.Colors{text.FgHiCyan, text.Underline}.Sprint(output),
+		text.Colors{text.FgHiBlack}.Sprintf(T("(%d 个代码片段, %d 张截图)"), len(bundle.Snippets), len(bundle.Screenshots)))
+	return nil
+}
+
+// pageRecorder 包装HTTPClient，保存获取到的页面，用于把原始HTML放进证据包
+type pageRecorder struct {
+	crawler.HTTPClient
+	pages map[string]string
+}
+
+// GetPage 获取页面并按路径保存内容
+func (r *pageRecorder) GetPage(path string) (string, error) {
+	content, err := r.HTTPClient.GetPage(path)
+	if err == nil {
+		if r.pages == nil {
+			r.pages = make(map[string]string)
+		}
+		r.pages[path] = content
+	}
+	return content, err
+}
+
+// Stats 返回被包装客户端的请求和限速状态
+func (r *pageRecorder) Stats() crawler.ClientStats {
+	if inner, ok := r.HTTPClient.(crawler.StatsClient); ok {
+		return inner.Stats()
+	}
+	return crawler.ClientStats{Remaining: -1}
+}
+
+// advisorySnippets 提取公告正文中的代码段，按识别出的语言确定扩展名，无法识别时保存为 .txt
+func advisorySnippets(content string) []export.BundleFile {
+	var snippets []export.BundleFile
+	for _, block := range splitAdvisoryBlocks(content) {
+		if !block.Code {
+			continue
+		}
+		lang, ext := "text", ".txt"
+		if lexer := snippetLexer(block.Text); lexer != nil {
+			config := lexer.Config()
+			lang = strings.ToLower(config.Name)
+			for _, pattern := range config.Filenames {
+				// 只使用 *.py 这样的简单模式，跳过 *.[ch] 等
+				if e := path.Ext(pattern); strings.HasPrefix(pattern, "*.") && !strings.ContainsAny(e, "[]*?") {
+					ext = e
+					break
+				}
+			}
+		}
+		snippets = append(snippets, export.BundleFile{
+			Name:   fmt.Sprintf("snippet-%d%s", len(snippets)+1, ext),
+			Source: lang,
+			Data:   []byte(strings.Trim(block.Text, "\n") + "\n"),
+		})
+	}
+	return snippets
+}
+
+// snippetMarkers 是自动识别失败时按开头判断语言的标记，PoC代码大多是这几种语言
+var snippetMarkers = []struct {
+	prefix string
+	lexer  string
+}{
+	{"<?php", "php"},
+	{"#!/usr/bin/env python", "python"},
+	{"#!/usr/bin/python", "python"},
+	{"#!/usr/bin/env perl", "perl"},
+	{"#!/usr/bin/perl", "perl"},
+	{"#!/usr/bin/env ruby", "ruby"},
+	{"#!/bin/bash", "bash"},
+	{"#!/bin/sh", "bash"},
+	{"#include", "c"},
+}
+
+// snippetLexer 识别代码段的语言，无法识别时返回nil
+func snippetLexer(code string) chroma.Lexer {
+	if lexer := lexers.Analyse(code); lexer != nil {
+		return lexer
+	}
+	trimmed := strings.TrimSpace(code)
+	for _, m := range snippetMarkers {
+		if strings.HasPrefix(trimmed, m.prefix) {
+			return lexers.Get(m.lexer)
+		}
+	}
+	return nil
+}
+
+// imageURLPattern 匹配正文中指向图片的链接
+var imageURLPattern = regexp.MustCompile(`(?i)https?://[^\s"'<>()]+\.(?:png|jpe?g|gif|webp|bmp)(?:\?[^\s"'<>()]*)?`)
+
+// advisoryImages 返回公告中引用的图片：详情页正文中的 <img> 和正文里指向图片的链接
+// 相对地址按漏洞详情页的URL解析，重复的只保留一个，最多返回 maxBundleScreenshots 个
+func advisoryImages(html string, v *model.Vulnerability) []string {
+	var images []string
+	seen := make(map[string]bool)
+	add := func(raw string) {
+		if len(images) >= maxBundleScreenshots {
+			return
+		}
+		u, err := url.Parse(strings.TrimSpace(raw))
+		if err != nil {
+			return
+		}
+		if base, err := url.Parse(v.URL); err == nil {
+			u = base.ResolveReference(u)
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || seen[u.String()] {
+			return
+		}
+		seen[u.String()] = true
+		images = append(images, u.String())
+	}
+
+	if doc, err := goquery.NewDocumentFromReader(strings.NewReader(html)); err == nil {
+		doc.Find("div.premex img[src]").Each(func(_ int, s *goquery.Selection) {
+			add(s.AttrOr("src", ""))
+		})
+	}
+	for _, link := range imageURLPattern.FindAllString(v.Content, -1) {
+		add(link)
+	}
+	return images
+}
+
+// imageExtensions 是截图的内容类型对应的扩展名
+var imageExtensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+	"image/bmp":  ".bmp",
+}
+
+// fetchScreenshots 下载图片，不是图片的内容和下载失败的链接记录在第二个返回值中
+func fetchScreenshots(fetch func(string) ([]byte, error), links []string) ([]export.BundleFile, []export.BundleMissing) {
+	var files []export.BundleFile
+	var missing []export.BundleMissing
+	for _, link := range links {
+		data, err := fetch(link)
+		if err != nil {
+			missing = append(missing, export.BundleMissing{Source: link, Reason: err.Error()})
+			continue
+		}
+		contentType := http.DetectContentType(data)
+		if !strings.HasPrefix(contentType, "image/") {
+			missing = append(missing, export.BundleMissing{Source: link, Reason: fmt.Sprintf(T("不是图片(%s)"), contentType)})
+			continue
+		}
+		ext, ok := imageExtensions[contentType]
+		if !ok {
+			ext = ".img"
+		}
+		files = append(files, export.BundleFile{Name: fmt.Sprintf("screenshot-%d%s", len(files)+1, ext), Source: link, Data: data})
+	}
+	return files, missing
+}
+
+func init() {
+	bundleCmd.Flags().StringVarP(&advisoryBundleOutput, "output", "o", "", T("证据包的保存路径，默认为 <WLB-ID>.zip"))
+	bundleCmd.Flags().BoolVar(&bundleNoScreenshots, "no-screenshots", false, T("不下载公告中引用的图片"))
+}
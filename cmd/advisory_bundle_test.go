@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"archive/zip"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/config"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/fixture"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+func TestAdvisorySnippets(t *testing.T) {
+	content := "Description of the issue.\n\n#!/usr/bin/env python3\nimport requests\ndef exploit(url):\n    requests.get(url)\n\nCredits: someone"
+	snippets := advisorySnippets(content)
+	require.Len(t, snippets, 1)
+	assert.Equal(t, "snippet-1.py", snippets[0].Name)
+	assert.Equal(t, "python", snippets[0].Source)
+	assert.True(t, strings.HasPrefix(string(snippets[0].Data), "#!/usr/bin/env python3\n"))
+
+	assert.Empty(t, advisorySnippets("只有文字的公告"))
+}
+
+func TestAdvisoryImages(t *testing.T) {
+	html := `<div class="premex"><img src="/images/poc.png"><img src="javascript:alert(1)"><img src="https://example.com/a.jpg"></div><img src="/logo.png">`
+	v := &model.Vulnerability{
+		URL:     "https://cxsecurity.com/issue/WLB-2024040015",
+		Content: "Screenshot: https://example.com/a.jpg and (https://imgur.com/x.PNG?raw=1).",
+	}
+	assert.Equal(t, []string{
+		"https://cxsecurity.com/images/poc.png",
+		"https://example.com/a.jpg",
+		"https://imgur.com/x.PNG?raw=1",
+	}, advisoryImages(html, v))
+}
+
+func TestFetchScreenshots(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n0000")
+	fetch := func(link string) ([]byte, error) {
+		switch link {
+		case "https://example.com/a.png":
+			return png, nil
+		case "https://example.com/page.png":
+			return []byte("<html>not found</html>"), nil
+		}
+		return nil, errors.New("请求失败: 404 Not Found")
+	}
+	files, missing := fetchScreenshots(fetch, []string{"https://example.com/a.png", "https://example.com/page.png", "https://example.com/gone.png"})
+	require.Len(t, files, 1)
+	assert.Equal(t, "screenshot-1.png", files[0].Name)
+	assert.Equal(t, "https://example.com/a.png", files[0].Source)
+	require.Len(t, missing, 2)
+	assert.Contains(t, missing[0].Reason, "text/html")
+	assert.Equal(t, "https://example.com/gone.png", missing[1].Source)
+}
+
+func TestAdvisoryBundleCommand(t *testing.T) {
+	server, err := fixture.NewServer("../docs/response-examples")
+	require.NoError(t, err)
+	defer server.Close()
+
+	dir := t.TempDir()
+	t.Setenv(config.DirEnv, dir)
+	oldOptions, oldQuiet := globalClientOptions, quietOutput
+	defer func() {
+		globalClientOptions, quietOutput = oldOptions, oldQuiet
+		advisoryBundleOutput, bundleNoScreenshots = "", false
+	}()
+	globalClientOptions = []crawler.ClientOption{crawler.WithBaseURL(server.URL), crawler.WithRetry(0, 0)}
+	quietOutput, bundleNoScreenshots = true, true
+	advisoryBundleOutput = filepath.Join(dir, "evidence", "bundle.zip")
+
+	require.NoError(t, bundleCmd.RunE(bundleCmd, []string{"WLB-2024040015"}))
+	r, err := zip.OpenReader(advisoryBundleOutput)
+	require.NoError(t, err)
+	defer r.Close()
+	names := make(map[string]bool)
+	for _, f := range r.File {
+		names[f.Name] = true
+	}
+	assert.True(t, names["WLB-2024040015/README.md"])
+	assert.True(t, names["WLB-2024040015/advisory.json"])
+	assert.True(t, names["WLB-2024040015/advisory.html"])
+
+	assert.Error(t, bundleCmd.RunE(bundleCmd, []string{"not-an-id"}))
+}
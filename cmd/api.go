@@ -3,17 +3,20 @@ package cmd
 import (
 	"crypto/rand"
 	"encoding/hex"
-	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/spf13/cobra"
 
 	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/logging"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
 )
 
 var (
@@ -27,10 +30,21 @@ var (
 // success: 表示请求是否成功
 // data: 成功时返回的数据
 // error: 失败时的错误信息
+// meta: 成功时数据的获取时间(fetched_at)、是否命中缓存(cache_hit)和来源(source: live/cache/store)
 type APIResponse struct {
 	Success bool        `json:"success"`
 	Data    interface{} `json:"data,omitempty"`
 	Error   string      `json:"error,omitempty"`
+	// Meta 必须是最后一个字段，etagMiddleware 计算ETag时会去掉它
+	Meta *crawler.Freshness `json:"meta,omitempty"`
+}
+
+// encodeData 写入成功的响应，meta描述数据是什么时候、从哪里获取的
+func encodeData(w http.ResponseWriter, data interface{}, meta crawler.Freshness) {
+	if buf, ok := w.(*bufferedResponseWriter); ok {
+		buf.hasMeta = true
+	}
+	encodeJSON(w, APIResponse{Success: true, Data: data, Meta: &meta})
 }
 
 // generateRandomToken 生成一个随机的API Token
@@ -64,9 +78,9 @@ func authMiddleware(next http.HandlerFunc) http.HandlerFunc {
 
 		if token != apiToken {
 			w.WriteHeader(http.StatusUnauthorized)
-			json.NewEncoder(w).Encode(APIResponse{
+			encodeJSON(w, APIResponse{
 				Success: false,
-				Error:   "无效的API Token",
+				Error:   T("无效的API Token"),
 			})
 			return
 		}
@@ -88,7 +102,8 @@ func corsMiddleware(next http.HandlerFunc) http.HandlerFunc {
 		if enableCORS {
 			w.Header().Set("Access-Control-Allow-Origin", "*")
 			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-API-Token")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-API-Token, If-None-Match")
+			w.Header().Set("Access-Control-Expose-Headers", "ETag, Deprecation, Sunset, Link, X-Cache, X-Data-Source")
 		}
 
 		if r.Method == "OPTIONS" {
@@ -100,6 +115,25 @@ func corsMiddleware(next http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
+// securityHeadersMiddleware 为API响应设置安全相关的HTTP头
+// 漏洞标题、描述等字段可能包含 <script> 等PoC代码，明确声明JSON内容类型并禁止
+// 浏览器嗅探和执行脚本，防止直接在浏览器中打开API响应时触发存储型XSS
+//
+// 参数:
+//   - next: 下一个要执行的处理函数
+//
+// 返回值:
+//   - http.HandlerFunc: 包装后的处理函数
+func securityHeadersMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Set("Content-Security-Policy", "default-src 'none'; frame-ancestors 'none'")
+
+		next.ServeHTTP(w, r)
+	}
+}
+
 /**
  * @api {get} /api/exploit 获取漏洞列表
  * @apiName GetExploitList
@@ -113,6 +147,8 @@ func corsMiddleware(next http.HandlerFunc) http.HandlerFunc {
  *     }
  *
  * @apiParam {String} [token] API认证Token(URL参数方式)
+ * @apiParam {String} [section=exploit] 列表栏目，可选值：exploit、wlb
+ * @apiParam {Number} [page=1] 页码
  *
  * @apiSuccess {Boolean} success 是否成功
  * @apiSuccess {Object} data 返回数据
@@ -159,7 +195,7 @@ func corsMiddleware(next http.HandlerFunc) http.HandlerFunc {
  *     curl "http://localhost:8080/api/exploit?token=your-token"
  */
 // handleExploitList 处理漏洞列表请求
-// 获取最新的漏洞列表，不需要额外的参数
+// 获取最新的漏洞列表，可以通过section和page参数指定栏目和页码
 // 参数:
 //   - c: Crawler实例，用于执行爬虫操作
 // 返回值:
@@ -183,19 +219,39 @@ func corsMiddleware(next http.HandlerFunc) http.HandlerFunc {
 //   }
 func handleExploitList(c *crawler.Crawler) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		result, err := c.CrawlExploit("", "", "all")
+		// 只允许已知栏目，不接受自定义路径
+		section := crawler.SectionExploit
+		if s := r.URL.Query().Get("section"); s != "" {
+			section = crawler.Section(s)
+			if strings.HasPrefix(s, "/") {
+				section = ""
+			}
+		}
+		page := 1
+		if pageStr := r.URL.Query().Get("page"); pageStr != "" {
+			if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
+				page = p
+			}
+		}
+
+		if _, err := crawler.SectionPath(section, page); err != nil {
+			encodeJSON(w, APIResponse{
+				Success: false,
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		result, err := c.CrawlSection(section, page, "")
 		if err != nil {
-			json.NewEncoder(w).Encode(APIResponse{
+			encodeJSON(w, APIResponse{
 				Success: false,
 				Error:   err.Error(),
 			})
 			return
 		}
 
-		json.NewEncoder(w).Encode(APIResponse{
-			Success: true,
-			Data:    result,
-		})
+		encodeData(w, result, crawler.LiveFreshness(time.Now()))
 	}
 }
 
@@ -219,7 +275,9 @@ func handleExploitList(c *crawler.Crawler) http.HandlerFunc {
  * @apiSuccess {String} data.risk_level 风险等级
  * @apiSuccess {String} data.author 作者
  * @apiSuccess {String} data.author_url 作者主页
- * @apiSuccess {String} data.description 漏洞描述
+ * @apiSuccess {String} data.description 漏洞描述，从正文中提取，正文没有描述时省略
+ * @apiSuccess {String[]} data.affected_versions 受影响的版本
+ * @apiSuccess {String} data.platform 平台
  * @apiSuccess {String[]} data.tags 标签列表
  *
  * @apiSuccessExample {json} 成功响应:
@@ -235,6 +293,8 @@ func handleExploitList(c *crawler.Crawler) http.HandlerFunc {
  *         "author": "Security Researcher",
  *         "author_url": "https://cxsecurity.com/author/researcher",
  *         "description": "详细的漏洞描述...",
+ *         "affected_versions": ["<= 2.1.0"],
+ *         "platform": "PHP",
  *         "tags": ["wordpress", "plugin", "xss"]
  *       }
  *     }
@@ -273,32 +333,35 @@ func handleExploitList(c *crawler.Crawler) http.HandlerFunc {
 //       "author": "Security Researcher",
 //       "author_url": "https://cxsecurity.com/author/researcher",
 //       "description": "详细的漏洞描述...",
+//       "affected_versions": ["<= 2.1.0"],
+//       "platform": "PHP",
 //       "tags": ["wordpress", "plugin", "xss"]
 //     }
 //   }
 func handleExploitDetail(c *crawler.Crawler) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
-		id := vars["id"]
 
-		// 确保ID格式正确
-		if !strings.HasPrefix(id, "WLB-") {
-			id = "WLB-" + id
+		// 规范化ID，不带WLB-前缀也可以
+		id, err := model.ParseWLBID(vars["id"])
+		if err != nil {
+			encodeJSON(w, APIResponse{
+				Success: false,
+				Error:   err.Error(),
+			})
+			return
 		}
 
-		result, err := c.CrawlExploit(id, "", "all")
+		result, freshness, err := c.FetchExploit(id.String())
 		if err != nil {
-			json.NewEncoder(w).Encode(APIResponse{
+			encodeJSON(w, APIResponse{
 				Success: false,
 				Error:   err.Error(),
 			})
 			return
 		}
 
-		json.NewEncoder(w).Encode(APIResponse{
-			Success: true,
-			Data:    result,
-		})
+		encodeData(w, result, freshness)
 	}
 }
 
@@ -384,21 +447,27 @@ func handleExploitDetail(c *crawler.Crawler) http.HandlerFunc {
 func handleCveDetail(c *crawler.Crawler) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
-		cveID := vars["id"]
 
-		result, err := c.CrawlCveDetail(cveID, "")
+		// 规范化CVE编号，不区分大小写
+		cveID, err := model.ParseCVEID(vars["id"])
 		if err != nil {
-			json.NewEncoder(w).Encode(APIResponse{
+			encodeJSON(w, APIResponse{
 				Success: false,
 				Error:   err.Error(),
 			})
 			return
 		}
 
-		json.NewEncoder(w).Encode(APIResponse{
-			Success: true,
-			Data:    result,
-		})
+		result, freshness, err := c.FetchCveDetail(cveID.String())
+		if err != nil {
+			encodeJSON(w, APIResponse{
+				Success: false,
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		encodeData(w, result, freshness)
 	}
 }
 
@@ -411,6 +480,7 @@ func handleCveDetail(c *crawler.Crawler) http.HandlerFunc {
  * @apiHeader {String} X-API-Token API认证Token
  *
  * @apiParam {String} id 作者ID
+ * @apiParam {Boolean} [proxy_assets] 为true时头像和国旗图片地址改为通过 /api/v1/assets 加载
  * @apiParam {String} [token] API认证Token(URL参数方式)
  *
  * @apiSuccess {Boolean} success 是否成功
@@ -474,6 +544,7 @@ func handleCveDetail(c *crawler.Crawler) http.HandlerFunc {
 //   - c: Crawler实例，用于执行爬虫操作
 // URL参数:
 //   - id: 作者ID
+//   - proxy_assets: 为true时头像和国旗图片地址改为通过图片代理加载
 // 返回值:
 //   - http.HandlerFunc: HTTP处理函数
 // 响应示例:
@@ -507,17 +578,17 @@ func handleAuthorProfile(c *crawler.Crawler) http.HandlerFunc {
 
 		result, err := c.CrawlAuthor(authorID, "")
 		if err != nil {
-			json.NewEncoder(w).Encode(APIResponse{
+			encodeJSON(w, APIResponse{
 				Success: false,
 				Error:   err.Error(),
 			})
 			return
 		}
+		if proxy, _ := strconv.ParseBool(r.URL.Query().Get("proxy_assets")); proxy {
+			proxyProfileAssets(result)
+		}
 
-		json.NewEncoder(w).Encode(APIResponse{
-			Success: true,
-			Data:    result,
-		})
+		encodeData(w, result, crawler.LiveFreshness(time.Now()))
 	}
 }
 
@@ -532,7 +603,12 @@ func handleAuthorProfile(c *crawler.Crawler) http.HandlerFunc {
  * @apiParam {String} keyword 搜索关键词
  * @apiParam {Number} [page=1] 页码
  * @apiParam {Number} [per_page=10] 每页记录数(10或30)
- * @apiParam {String} [sort_order=DESC] 排序顺序(ASC或DESC)
+ * @apiParam {String} [sort_order=DESC] 排序顺序(ASC、DESC或RELEVANCE)
+ * @apiParam {String} [risk] 只返回指定风险级别的漏洞(High、Med、Low)，多个用逗号分隔
+ * @apiParam {Boolean} [remote] 只返回可远程利用的漏洞
+ * @apiParam {Boolean} [local] 只返回本地利用的漏洞
+ * @apiParam {String} [after] 只搜索该日期及之后发布的漏洞(YYYY-MM-DD)
+ * @apiParam {String} [before] 只搜索该日期及之前发布的漏洞(YYYY-MM-DD)
  * @apiParam {String} [token] API认证Token(URL参数方式)
  *
  * @apiSuccess {Boolean} success 是否成功
@@ -596,7 +672,12 @@ func handleAuthorProfile(c *crawler.Crawler) http.HandlerFunc {
 //   - keyword: 搜索关键词（必填）
 //   - page: 页码，默认1
 //   - per_page: 每页数量，默认10
-//   - sort_order: 排序方式，可选值：ASC/DESC，默认DESC
+//   - sort_order: 排序方式，可选值：ASC/DESC/RELEVANCE，默认DESC
+//   - risk: 风险级别过滤，可选值：High/Med/Low，多个用逗号分隔
+//   - remote: 为true时只返回可远程利用的漏洞
+//   - local: 为true时只返回本地利用的漏洞
+//   - after/before: 发布日期范围，格式为YYYY-MM-DD
+// 指定过滤条件而没有指定per_page时每页获取30条，过滤在获取当前页后进行
 // 返回值:
 //   - http.HandlerFunc: HTTP处理函数
 // 响应示例:
@@ -626,9 +707,9 @@ func handleSearch(c *crawler.Crawler) http.HandlerFunc {
 		// 获取查询参数
 		keyword := r.URL.Query().Get("keyword")
 		if keyword == "" {
-			json.NewEncoder(w).Encode(APIResponse{
+			encodeJSON(w, APIResponse{
 				Success: false,
-				Error:   "搜索关键词不能为空",
+				Error:   T("搜索关键词不能为空"),
 			})
 			return
 		}
@@ -641,8 +722,46 @@ func handleSearch(c *crawler.Crawler) http.HandlerFunc {
 			}
 		}
 
-		// 获取每页记录数
+		// 获取风险级别和利用方式过滤条件
+		var risks []string
+		if riskStr := r.URL.Query().Get("risk"); riskStr != "" {
+			for _, risk := range strings.Split(riskStr, ",") {
+				if crawler.NormalizeRiskLevel(risk) == "" {
+					encodeJSON(w, APIResponse{
+						Success: false,
+						Error:   fmt.Sprintf(T("无效的风险级别 %q，可选值：High、Med、Low"), risk),
+					})
+					return
+				}
+				risks = append(risks, risk)
+			}
+		}
+		remote, _ := strconv.ParseBool(r.URL.Query().Get("remote"))
+		local, _ := strconv.ParseBool(r.URL.Query().Get("local"))
+
+		// 获取日期范围
+		after, err := parseDateFlag(r.URL.Query().Get("after"))
+		if err != nil {
+			encodeJSON(w, APIResponse{
+				Success: false,
+				Error:   fmt.Sprintf(T("无效的开始日期 %q，格式应为 YYYY-MM-DD"), r.URL.Query().Get("after")),
+			})
+			return
+		}
+		before, err := parseDateFlag(r.URL.Query().Get("before"))
+		if err != nil {
+			encodeJSON(w, APIResponse{
+				Success: false,
+				Error:   fmt.Sprintf(T("无效的结束日期 %q，格式应为 YYYY-MM-DD"), r.URL.Query().Get("before")),
+			})
+			return
+		}
+
+		// 获取每页记录数，有过滤条件时默认由Search选择
 		perPage := 10
+		if len(risks) > 0 || remote || local {
+			perPage = 0
+		}
 		if perPageStr := r.URL.Query().Get("per_page"); perPageStr != "" {
 			if pp, err := strconv.Atoi(perPageStr); err == nil && (pp == 10 || pp == 30) {
 				perPage = pp
@@ -651,72 +770,123 @@ func handleSearch(c *crawler.Crawler) http.HandlerFunc {
 
 		// 获取排序顺序
 		sortOrder := "DESC"
-		if so := strings.ToUpper(r.URL.Query().Get("sort_order")); so == "ASC" || so == "DESC" {
+		if so := strings.ToUpper(r.URL.Query().Get("sort_order")); so == "ASC" || so == "DESC" || so == crawler.SortRelevance {
 			sortOrder = so
 		}
 
 		// 执行搜索
-		result, err := c.SearchVulnerabilitiesAdvanced(keyword, page, perPage, sortOrder, "")
+		result, err := c.Search(crawler.SearchOptions{
+			Keyword:   keyword,
+			Page:      page,
+			PerPage:   perPage,
+			SortOrder: sortOrder,
+			Risks:     risks,
+			Remote:    remote,
+			Local:     local,
+			After:     after,
+			Before:    before,
+		}, "")
 		if err != nil {
-			json.NewEncoder(w).Encode(APIResponse{
+			encodeJSON(w, APIResponse{
 				Success: false,
 				Error:   err.Error(),
 			})
 			return
 		}
 
-		json.NewEncoder(w).Encode(APIResponse{
-			Success: true,
-			Data:    result,
-		})
+		encodeData(w, result, crawler.LiveFreshness(time.Now()))
 	}
 }
 
 var apiCmd = &cobra.Command{
 	Use:   "api",
-	Short: "启动HTTP API服务",
-	Long:  `启动HTTP API服务，将爬虫功能以RESTful API的形式提供`,
+	Short: T("启动HTTP API服务"),
+	Long:  T(`启动HTTP API服务，将爬虫功能以RESTful API的形式提供`),
 	Run: func(cmd *cobra.Command, args []string) {
 		// 如果未指定token，生成随机token
 		if apiToken == "" {
 			apiToken = generateRandomToken()
-			fmt.Printf("已生成随机API Token: %s\n", apiToken)
+			// 随机生成的Token只在这里完整显示一次，之后的输出都会脱敏
+			fmt.Printf(T("已生成随机API Token: %s\n"), apiToken)
+		}
+		logging.AddSecret(apiToken)
+		if botSlackSigningSecret == "" {
+			botSlackSigningSecret = os.Getenv("CXCRAWLER_SLACK_SIGNING_SECRET")
+		}
+		if botTelegramSecret == "" {
+			botTelegramSecret = os.Getenv("CXCRAWLER_TELEGRAM_SECRET")
+		}
+		logging.AddSecret(botSlackSigningSecret)
+		logging.AddSecret(botTelegramSecret)
+
+		if err := validateAPITimeouts(); err != nil {
+			log.Fatal(err)
 		}
 
 		// 创建爬虫实例
-		c := crawler.NewCrawler()
+		c := newCrawler()
 
 		// 创建路由器
 		r := mux.NewRouter()
 
 		// 注册API路由
-		r.HandleFunc("/api/exploit", corsMiddleware(authMiddleware(handleExploitList(c)))).Methods("GET", "OPTIONS")
-		r.HandleFunc("/api/exploit/{id}", corsMiddleware(authMiddleware(handleExploitDetail(c)))).Methods("GET", "OPTIONS")
-		r.HandleFunc("/api/cve/{id}", corsMiddleware(authMiddleware(handleCveDetail(c)))).Methods("GET", "OPTIONS")
-		r.HandleFunc("/api/author/{id}", corsMiddleware(authMiddleware(handleAuthorProfile(c)))).Methods("GET", "OPTIONS")
-		r.HandleFunc("/api/search", corsMiddleware(authMiddleware(handleSearch(c)))).Methods("GET", "OPTIONS")
+		sunset, err := parseLegacySunset(apiLegacySunset)
+		if err != nil {
+			log.Fatal(err)
+		}
+		feeds := newAuthorFeedCache(c, apiFeedInterval)
+		registerAPIRoutes(r, apiRoutes(c, feeds, newAPIAssetProxy()), sunset)
+
+		// 聊天机器人使用各平台的签名或密钥认证，不使用API Token
+		if botSlackSigningSecret != "" {
+			r.HandleFunc("/bot/slack", apiRequests.middleware("/bot/slack", securityHeadersMiddleware(handleSlackCommand(c, botSlackSigningSecret)))).Methods("POST")
+		}
+		if botTelegramSecret != "" {
+			r.HandleFunc("/bot/telegram", apiRequests.middleware("/bot/telegram", securityHeadersMiddleware(handleTelegramUpdate(c, botTelegramSecret)))).Methods("POST")
+		}
+
+		r.HandleFunc("/metrics", securityHeadersMiddleware(authMiddleware(handleMetrics()))).Methods("GET")
 
 		// 添加API文档路由
 		r.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 			fmt.Fprintf(w, "CXSecurity Crawler API\n")
-			fmt.Fprintf(w, "可用的API端点：\n")
-			fmt.Fprintf(w, "GET /api/exploit - 获取漏洞列表\n")
-			fmt.Fprintf(w, "GET /api/exploit/{id} - 获取漏洞详情\n")
-			fmt.Fprintf(w, "GET /api/cve/{id} - 获取CVE详情\n")
-			fmt.Fprintf(w, "GET /api/author/{id} - 获取作者信息\n")
-			fmt.Fprintf(w, "GET /api/search - 搜索漏洞\n")
-			fmt.Fprintf(w, "  参数：\n")
-			fmt.Fprintf(w, "    - keyword: 搜索关键词（必填）\n")
-			fmt.Fprintf(w, "    - page: 页码，默认1\n")
-			fmt.Fprintf(w, "    - per_page: 每页数量，默认10\n")
-			fmt.Fprintf(w, "    - sort_order: 排序方式，可选值：ASC/DESC，默认DESC\n")
+			fmt.Fprint(w, T("可用的API端点（以下路径均可加上 /api/v1 前缀，未带版本的 /api/ 路径已废弃）：\n"))
+			fmt.Fprint(w, T("GET /api/exploit - 获取漏洞列表\n"))
+			fmt.Fprint(w, T("GET /api/exploit/latest?since={id}&limit=N - 获取上次之后的新漏洞\n"))
+			fmt.Fprint(w, T("GET /api/exploit/{id} - 获取漏洞详情\n"))
+			fmt.Fprint(w, T("GET /api/cve/{id} - 获取CVE详情\n"))
+			fmt.Fprint(w, T("GET /api/author/{id} - 获取作者信息\n"))
+			fmt.Fprint(w, T("GET /api/author/{id}/feed - 作者新发布的RSS订阅\n"))
+			fmt.Fprint(w, T("GET /api/assets?url={url} - 代理作者头像和国旗图片\n"))
+			fmt.Fprint(w, T("GET /api/search - 搜索漏洞\n"))
+			fmt.Fprint(w, T("  参数：\n"))
+			fmt.Fprint(w, T("    - keyword: 搜索关键词（必填）\n"))
+			fmt.Fprint(w, T("    - page: 页码，默认1\n"))
+			fmt.Fprint(w, T("    - per_page: 每页数量，默认10\n"))
+			fmt.Fprint(w, T("    - sort_order: 排序方式，可选值：ASC/DESC/RELEVANCE，默认DESC\n"))
+			fmt.Fprint(w, T("GET /api/local/authors/{id}/vulnerabilities - 查询存储中作者的所有漏洞\n"))
+			fmt.Fprint(w, T("GET /api/status - 请求数、剩余预算和限速状态\n"))
+			fmt.Fprint(w, T("GET /metrics - Prometheus格式的公告统计\n"))
+			if botSlackSigningSecret != "" {
+				fmt.Fprint(w, T("POST /bot/slack - Slack斜杠命令\n"))
+			}
+			if botTelegramSecret != "" {
+				fmt.Fprint(w, T("POST /bot/telegram - Telegram机器人Webhook\n"))
+			}
 		})
 
+		// 定期按保留策略清理数据目录
+		startPruning(apiPruneDirs, apiPruneInterval)
+
+		// 定期爬取 --feed-author 指定的作者，订阅请求直接使用缓存
+		feeds.start(apiFeedAuthors)
+
 		// 启动服务器
 		addr := fmt.Sprintf(":%d", apiPort)
-		fmt.Printf("API服务器正在监听 http://localhost%s\n", addr)
-		fmt.Printf("API Token: %s\n", apiToken)
-		fmt.Printf("使用方式：在请求头中添加 X-API-Token: %s 或在URL中添加 ?token=%s\n", apiToken, apiToken)
+		fmt.Printf(T("API服务器正在监听 http://localhost%s\n"), addr)
+		fmt.Printf("API Token: %s\n", logging.MaskSecret(apiToken))
+		fmt.Printf(T("JSON编码器: %s\n"), apiJSONEncoder)
+		fmt.Print(T("使用方式：在请求头中添加 X-API-Token: <token> 或在URL中添加 ?token=<token>\n"))
 
 		log.Fatal(http.ListenAndServe(addr, r))
 	},
@@ -726,7 +896,21 @@ func init() {
 	rootCmd.AddCommand(apiCmd)
 
 	// 添加命令行参数
-	apiCmd.Flags().IntVarP(&apiPort, "port", "p", 8080, "API服务器监听端口")
-	apiCmd.Flags().StringVarP(&apiToken, "token", "t", "", "API认证Token（不指定则随机生成）")
-	apiCmd.Flags().BoolVarP(&enableCORS, "cors", "c", false, "启用CORS支持")
+	apiCmd.Flags().IntVarP(&apiPort, "port", "p", 8080, T("API服务器监听端口"))
+	apiCmd.Flags().StringVarP(&apiToken, "token", "t", "", T("API认证Token（不指定则随机生成）"))
+	apiCmd.Flags().BoolVarP(&enableCORS, "cors", "c", false, T("启用CORS支持"))
+	apiCmd.Flags().StringSliceVar(&apiMetricsData, "metrics-data", nil, T("/metrics 统计的结果文件，支持通配符，每次抓取时重新读取"))
+	apiCmd.Flags().IntVar(&apiMetricsDays, "metrics-days", 30, T("/metrics 按天统计的天数"))
+	apiCmd.Flags().StringVar(&storeFile, "store", "", T("/api/local 查询的存储文件，默认为配置目录下的 vulnerabilities.json"))
+	apiCmd.Flags().StringVar(&watchAuthorState, "author-state", "", T("/api/local/authors 查询的 watch-author 状态文件，默认为配置目录下的 authors.json"))
+	apiCmd.Flags().StringSliceVar(&apiPruneDirs, "prune-dir", nil, T("按保存的保留策略定期清理的数据目录，参见 prune 命令"))
+	apiCmd.Flags().StringVar(&botSlackSigningSecret, "slack-signing-secret", "", T("Slack应用的签名密钥，指定后在 /bot/slack 响应斜杠命令，也可以通过环境变量 CXCRAWLER_SLACK_SIGNING_SECRET 设置"))
+	apiCmd.Flags().StringVar(&botTelegramSecret, "telegram-secret", "", T("Telegram机器人Webhook的secret_token，指定后在 /bot/telegram 响应命令，也可以通过环境变量 CXCRAWLER_TELEGRAM_SECRET 设置"))
+	apiCmd.Flags().StringVar(&apiLegacySunset, "legacy-sunset", "", T("未带版本的 /api/ 路径计划停用的日期（YYYY-MM-DD），通过 Sunset 响应头告知客户端"))
+	apiCmd.Flags().DurationVar(&apiPruneInterval, "prune-interval", 24*time.Hour, T("清理 --prune-dir 的间隔"))
+	apiCmd.Flags().StringSliceVar(&apiFeedAuthors, "feed-author", nil, T("在后台定期爬取的作者ID，/api/author/{id}/feed 直接使用爬取结果，多个用逗号分隔"))
+	apiCmd.Flags().DurationVar(&apiListTimeout, "list-timeout", 30*time.Second, T("列表类接口(/exploit、/exploit/latest、作者订阅)等待cxsecurity的最长时间，超时返回504，0表示不限制"))
+	apiCmd.Flags().DurationVar(&apiDetailTimeout, "detail-timeout", 60*time.Second, T("详情类接口(/exploit/{id}、/cve/{id}、/author/{id})等待cxsecurity的最长时间，超时返回504，0表示不限制"))
+	apiCmd.Flags().DurationVar(&apiSearchTimeout, "search-timeout", 45*time.Second, T("/search 等待cxsecurity的最长时间，超时返回504，0表示不限制"))
+	apiCmd.Flags().DurationVar(&apiFeedInterval, "feed-interval", time.Hour, T("作者订阅的更新间隔，同一作者的页面在间隔内最多爬取一次"))
 }
@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+const (
+	// assetMaxSize 是单个图片的最大字节数，超过时不转发
+	assetMaxSize = 1 << 20
+	// assetCacheSize 是图片缓存的总字节数，超过时淘汰最早缓存的图片
+	assetCacheSize = 32 << 20
+	// assetCacheTTL 是图片缓存的有效期，头像和国旗很少变化
+	assetCacheTTL = 24 * time.Hour
+	// gravatarURL 是作者头像所在的站点
+	gravatarURL = "https://www.gravatar.com"
+)
+
+// cachedAsset 是缓存的一张图片
+type cachedAsset struct {
+	contentType string
+	data        []byte
+	fetchedAt   time.Time
+}
+
+// assetProxy 代理作者资料中引用的头像和国旗图片并缓存在内存中
+// 网页界面通过API加载图片，浏览器不需要直接访问cxsecurity和Gravatar。
+// 只转发允许的站点上的图片，不能用作通用的代理。
+type assetProxy struct {
+	clients map[string]crawler.HTTPClient // 允许的主机 -> 获取图片使用的客户端
+
+	mu    sync.Mutex
+	cache map[string]cachedAsset
+	order []string // 缓存的顺序，超过容量时从最早的开始淘汰
+	size  int
+}
+
+// newAssetProxy 创建图片代理
+// site用于获取cxsecurity上的图片，遵循 --base-url、--proxy 等全局选项；gravatar用于获取头像
+func newAssetProxy(site, gravatar crawler.HTTPClient) *assetProxy {
+	return &assetProxy{
+		clients: map[string]crawler.HTTPClient{
+			"cxsecurity.com":      site,
+			"www.cxsecurity.com":  site,
+			"gravatar.com":        gravatar,
+			"www.gravatar.com":    gravatar,
+			"secure.gravatar.com": gravatar,
+		},
+		cache: make(map[string]cachedAsset),
+	}
+}
+
+// newAPIAssetProxy 创建使用全局HTTP选项的图片代理
+// 客户端最多读取 assetMaxSize 字节，过大的图片不会完整下载到内存中
+func newAPIAssetProxy() *assetProxy {
+	return newAssetProxy(
+		crawler.NewClient(clientOptions(crawler.WithMaxResponseSize(assetMaxSize))...),
+		crawler.NewClient(clientOptions(crawler.WithBaseURL(gravatarURL), crawler.WithMaxResponseSize(assetMaxSize))...),
+	)
+}
+
+// resolve 检查图片地址，返回解析后的地址和获取该站点图片使用的客户端
+func (p *assetProxy) resolve(rawURL string) (*url.URL, crawler.HTTPClient, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, nil, fmt.Errorf(T("无效的图片地址: %s"), rawURL)
+	}
+	client, ok := p.clients[strings.ToLower(u.Hostname())]
+	if !ok {
+		return nil, nil, fmt.Errorf(T("不允许代理该站点的图片: %s"), u.Hostname())
+	}
+	return u, client, nil
+}
+
+// get 返回图片，缓存中没有或已过期时重新获取
+func (p *assetProxy) get(u *url.URL, client crawler.HTTPClient, now time.Time) (cachedAsset, error) {
+	key := strings.ToLower(u.Hostname()) + u.RequestURI()
+
+	p.mu.Lock()
+	asset, ok := p.cache[key]
+	p.mu.Unlock()
+	if ok && now.Sub(asset.fetchedAt) < assetCacheTTL {
+		return asset, nil
+	}
+
+	content, err := client.GetPage(u.RequestURI())
+	if errors.Is(err, crawler.ErrResponseTooLarge) {
+		return cachedAsset{}, errors.New(T("图片过大"))
+	}
+	if err != nil {
+		return cachedAsset{}, fmt.Errorf(T("获取图片失败: %w"), err)
+	}
+	if len(content) > assetMaxSize {
+		return cachedAsset{}, errors.New(T("图片过大"))
+	}
+	// 客户端不返回响应头，按内容判断类型；404等错误页面不是图片，不会被转发
+	contentType := http.DetectContentType([]byte(content))
+	if !strings.HasPrefix(contentType, "image/") {
+		return cachedAsset{}, fmt.Errorf(T("获取到的内容不是图片: %s"), contentType)
+	}
+	asset = cachedAsset{contentType: contentType, data: []byte(content), fetchedAt: now}
+	p.put(key, asset)
+	return asset, nil
+}
+
+// put 缓存图片，超过总容量时淘汰最早缓存的图片
+func (p *assetProxy) put(key string, asset cachedAsset) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if old, ok := p.cache[key]; ok {
+		p.size -= len(old.data)
+		for i, k := range p.order {
+			if k == key {
+				p.order = append(p.order[:i], p.order[i+1:]...)
+				break
+			}
+		}
+	}
+	p.cache[key] = asset
+	p.order = append(p.order, key)
+	p.size += len(asset.data)
+	for p.size > assetCacheSize && len(p.order) > 1 {
+		oldest := p.order[0]
+		p.order = p.order[1:]
+		p.size -= len(p.cache[oldest].data)
+		delete(p.cache, oldest)
+	}
+}
+
+// assetPath 返回通过代理加载图片的地址，url为空时返回空字符串
+func assetPath(rawURL string) string {
+	if rawURL == "" {
+		return ""
+	}
+	return "/api/" + currentAPIVersion + "/assets?url=" + url.QueryEscape(rawURL)
+}
+
+// proxyProfileAssets 将作者资料中的图片地址改为通过代理加载
+func proxyProfileAssets(profile *model.AuthorProfile) {
+	profile.AvatarURL = assetPath(profile.AvatarURL)
+	profile.FlagURL = assetPath(profile.FlagURL)
+}
+
+/**
+ * @api {get} /api/assets 代理作者头像和国旗图片
+ * @apiName GetAsset
+ * @apiGroup Author
+ * @apiVersion 1.0.0
+ *
+ * @apiHeader {String} X-API-Token API认证Token
+ *
+ * @apiParam {String} url 图片地址，只允许cxsecurity和Gravatar上的图片
+ * @apiParam {String} [token] API认证Token，<img>标签无法设置请求头时使用
+ *
+ * @apiSuccessExample {binary} 成功响应:
+ *     HTTP/1.1 200 OK
+ *     Content-Type: image/png
+ *
+ * @apiExample {curl} 示例:
+ *     curl -H "X-API-Token: your-token" "http://localhost:8080/api/v1/assets?url=https%3A%2F%2Fcxsecurity.com%2Fimages%2Fflags%2Fpl.png"
+ */
+// handleAsset 返回代理的图片，图片在内存中缓存24小时
+// 与其他接口不同，失败时返回HTTP错误状态码，浏览器会显示为加载失败的图片
+func handleAsset(p *assetProxy) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rawURL := r.URL.Query().Get("url")
+		if rawURL == "" {
+			http.Error(w, T("缺少url参数"), http.StatusBadRequest)
+			return
+		}
+		u, client, err := p.resolve(rawURL)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		asset, err := p.get(u, client, time.Now())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", asset.contentType)
+		w.Write(asset.data)
+	}
+}
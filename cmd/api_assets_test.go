@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+// pngHeader 是PNG文件的签名，足以被识别为图片
+const pngHeader = "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"
+
+func TestHandleAsset(t *testing.T) {
+	site := &countingClient{fixtureClient: fixtureClient{content: pngHeader}}
+	gravatar := &countingClient{fixtureClient: fixtureClient{content: "<html>not found</html>"}}
+	handler := handleAsset(newAssetProxy(site, gravatar))
+	get := func(rawURL string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodGet, "/api/v1/assets?url="+url.QueryEscape(rawURL), nil))
+		return rec
+	}
+
+	rec := get("https://cxsecurity.com/images/flags/pl.png")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "image/png", rec.Header().Get("Content-Type"))
+	assert.Equal(t, pngHeader, rec.Body.String())
+
+	// 第二次请求使用缓存
+	get("https://cxsecurity.com/images/flags/pl.png")
+	assert.Equal(t, 1, site.requests)
+
+	// 不是图片的内容不转发
+	rec = get("https://www.gravatar.com/avatar/0123?s=80")
+	assert.Equal(t, http.StatusBadGateway, rec.Code)
+	assert.Equal(t, 1, gravatar.requests)
+
+	// 只允许cxsecurity和Gravatar，不能用作通用代理
+	for _, rawURL := range []string{"https://evil.example.com/a.png", "file:///etc/passwd", "", "http://127.0.0.1:8080/metrics"} {
+		assert.Equal(t, http.StatusBadRequest, get(rawURL).Code, rawURL)
+	}
+}
+
+func TestAssetProxyMaxSize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 不设置Content-Length，逐块发送直到客户端停止读取
+		chunk := []byte(pngHeader + strings.Repeat("\x00", 64<<10))
+		for i := 0; i < 64; i++ {
+			if _, err := w.Write(chunk); err != nil {
+				return
+			}
+			w.(http.Flusher).Flush()
+		}
+	}))
+	defer server.Close()
+
+	client := crawler.NewClient(crawler.WithBaseURL(server.URL), crawler.WithRetry(0, 0), crawler.WithMaxResponseSize(assetMaxSize))
+	p := newAssetProxy(client, client)
+	u, c, err := p.resolve("https://cxsecurity.com/images/big.png")
+	require.NoError(t, err)
+	_, err = p.get(u, c, time.Now())
+	assert.EqualError(t, err, T("图片过大"))
+	assert.Empty(t, p.cache)
+}
+
+func TestAssetProxyEviction(t *testing.T) {
+	p := newAssetProxy(nil, nil)
+	big := make([]byte, assetCacheSize/2+1)
+	p.put("a", cachedAsset{data: big})
+	p.put("b", cachedAsset{data: big})
+	assert.NotContains(t, p.cache, "a", "超过容量时应淘汰最早缓存的图片")
+	assert.Contains(t, p.cache, "b")
+	assert.Equal(t, len(big), p.size)
+
+	// 重新缓存同一张图片不重复计算大小
+	p.put("b", cachedAsset{data: big})
+	assert.Equal(t, len(big), p.size)
+	assert.Equal(t, []string{"b"}, p.order)
+}
+
+func TestProxyProfileAssets(t *testing.T) {
+	profile := &model.AuthorProfile{FlagURL: "https://cxsecurity.com/images/flags/pl.png"}
+	proxyProfileAssets(profile)
+	assert.Equal(t, "/api/v1/assets?url=https%3A%2F%2Fcxsecurity.com%2Fimages%2Fflags%2Fpl.png", profile.FlagURL)
+	assert.Empty(t, profile.AvatarURL)
+}
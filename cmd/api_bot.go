@@ -0,0 +1,361 @@
+package cmd
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/logging"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/sink"
+)
+
+// botMaxResults 是机器人回复中最多列出的搜索结果数
+const botMaxResults = 5
+
+// slackRequestMaxAge 是Slack请求时间戳允许的最大偏差，超过时拒绝请求以防止重放
+const slackRequestMaxAge = 5 * time.Minute
+
+var (
+	botSlackSigningSecret string
+	botTelegramSecret     string
+)
+
+// botItem 是机器人回复中的一条漏洞
+type botItem struct {
+	ID    string
+	Title string
+	URL   string
+	Risk  string
+	Date  string
+}
+
+// botReply 是机器人对一条命令的回复，由各平台的格式化函数转换为消息
+type botReply struct {
+	Text  string
+	Items []botItem
+}
+
+// botHelp 返回机器人命令的帮助
+func botHelp() botReply {
+	return botReply{Text: T(`可用的命令：
+search <关键词> [risk:High] - 搜索漏洞，只显示前5条
+show <WLB-ID> - 查看漏洞详情
+cve <CVE编号> - 查看CVE详情
+help - 显示帮助
+不带命令时按关键词搜索，直接输入漏洞ID或CVE编号时查看详情`)}
+}
+
+// runBotCommand 执行一条机器人命令，例如 "search wordpress rce"、"show WLB-2024040015" 或 "cve CVE-2007-1411"
+// 出错时回复错误信息，不返回错误
+func runBotCommand(c *crawler.Crawler, input string) botReply {
+	fields := strings.Fields(input)
+	if len(fields) == 0 {
+		return botHelp()
+	}
+
+	command, args := strings.ToLower(fields[0]), fields[1:]
+	switch command {
+	case "help", "start", "/start", "/help":
+		return botHelp()
+	case "search", "s":
+	case "show":
+		if len(args) != 1 {
+			return botReply{Text: T("用法: show <WLB-ID>")}
+		}
+		return botShow(c, args[0])
+	case "cve":
+		if len(args) != 1 {
+			return botReply{Text: T("用法: cve <CVE编号>")}
+		}
+		return botCve(c, args[0])
+	default:
+		// 直接输入漏洞ID或CVE编号时查看详情，否则把整条消息作为关键词搜索
+		if len(fields) == 1 {
+			if _, err := model.ParseCVEID(fields[0]); err == nil {
+				return botCve(c, fields[0])
+			}
+			if strings.HasPrefix(strings.ToUpper(fields[0]), model.WLBPrefix) {
+				return botShow(c, fields[0])
+			}
+		}
+		args = fields
+	}
+	return botSearch(c, args)
+}
+
+// botSearch 搜索关键词，risk:级别 形式的参数作为风险级别过滤条件
+func botSearch(c *crawler.Crawler, args []string) botReply {
+	var keywords, risks []string
+	for _, arg := range args {
+		if v, ok := strings.CutPrefix(strings.ToLower(arg), "risk:"); ok {
+			for _, risk := range strings.Split(v, ",") {
+				if crawler.NormalizeRiskLevel(risk) == "" {
+					return botReply{Text: fmt.Sprintf(T("无效的风险级别 %q，可选值：High、Med、Low"), risk)}
+				}
+				risks = append(risks, risk)
+			}
+			continue
+		}
+		keywords = append(keywords, arg)
+	}
+	if len(keywords) == 0 {
+		return botReply{Text: T("用法: search <关键词> [risk:High]")}
+	}
+
+	keyword := strings.Join(keywords, " ")
+	result, err := c.Search(crawler.SearchOptions{Keyword: keyword, Risks: risks}, "")
+	if err != nil {
+		return botReply{Text: fmt.Sprintf(T("搜索失败: %v"), err)}
+	}
+	if len(result.Vulnerabilities) == 0 {
+		return botReply{Text: fmt.Sprintf(T("没有找到与 %q 相关的漏洞"), keyword)}
+	}
+
+	reply := botReply{Text: fmt.Sprintf(T("%q 的搜索结果(第1页共%d页)："), keyword, result.TotalPages)}
+	for i, v := range result.Vulnerabilities {
+		if i == botMaxResults {
+			reply.Text = fmt.Sprintf(T("%q 的搜索结果(第1页共%d页，显示前%d条)："), keyword, result.TotalPages, botMaxResults)
+			break
+		}
+		risk := v.RiskLevel
+		if risk == "" {
+			risk = v.DerivedRisk
+		}
+		reply.Items = append(reply.Items, botItem{ID: v.ID, Title: v.Title, URL: v.URL, Risk: risk, Date: formatSearchDate(v.Date)})
+	}
+	return reply
+}
+
+// botShow 查看漏洞详情
+func botShow(c *crawler.Crawler, id string) botReply {
+	wlbID, err := model.ParseWLBID(id)
+	if err != nil {
+		return botReply{Text: err.Error()}
+	}
+	result, err := c.CrawlExploit(wlbID.String(), "", "")
+	if err != nil {
+		return botReply{Text: fmt.Sprintf(T("获取漏洞详情失败: %v"), err)}
+	}
+	v, ok := result.(*model.Vulnerability)
+	if !ok {
+		return botReply{Text: T("获取漏洞详情失败")}
+	}
+
+	var details []string
+	if v.CVE != "" {
+		details = append(details, "CVE: "+v.CVE)
+	}
+	if v.CWE != "" {
+		details = append(details, "CWE: "+v.CWE)
+	}
+	if v.Author != "" {
+		details = append(details, T("作者")+": "+v.Author)
+	}
+	item := botItem{ID: v.ID, Title: v.Title, URL: v.URL, Risk: v.RiskLevel}
+	item.Date = formatDate(v.Date)
+	return botReply{Text: strings.Join(details, "\n"), Items: []botItem{item}}
+}
+
+// botCve 查看CVE详情，描述过长时截断
+func botCve(c *crawler.Crawler, id string) botReply {
+	cveID, err := model.ParseCVEID(id)
+	if err != nil {
+		return botReply{Text: err.Error()}
+	}
+	result, err := c.CrawlCveDetail(cveID.String(), "")
+	if err != nil {
+		return botReply{Text: fmt.Sprintf(T("获取CVE详情失败: %v"), err)}
+	}
+
+	title := truncateWidth(result.Description, 300)
+	risk := ""
+	switch {
+	case result.Cvss3 != nil && result.Cvss3.BaseScore > 0:
+		risk = fmt.Sprintf("CVSS %.1f", result.Cvss3.BaseScore)
+	case result.CvssBaseScore > 0:
+		risk = fmt.Sprintf("CVSS %.1f", result.CvssBaseScore)
+	}
+	item := botItem{ID: cveID.String(), Title: title, URL: cveID.URL(), Risk: risk}
+	item.Date = formatDate(result.Published)
+	return botReply{Items: []botItem{item}}
+}
+
+// slackEscape 转义Slack mrkdwn中的控制字符
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// slackMessage 将回复转换为Slack消息，结果对频道中的所有人可见
+func slackMessage(reply botReply) map[string]string {
+	lines := []string{}
+	if reply.Text != "" {
+		lines = append(lines, slackEscape(reply.Text))
+	}
+	for _, item := range reply.Items {
+		line := "• <" + item.URL + "|" + slackEscape(item.ID) + ">"
+		if item.Risk != "" {
+			line += " `" + slackEscape(item.Risk) + "`"
+		}
+		line += " " + slackEscape(item.Title)
+		if item.Date != "" {
+			line += " (" + item.Date + ")"
+		}
+		lines = append(lines, line)
+	}
+	return map[string]string{"response_type": "in_channel", "text": strings.Join(lines, "\n")}
+}
+
+// telegramText 将回复转换为Telegram的HTML格式消息
+func telegramText(reply botReply) string {
+	lines := []string{}
+	if reply.Text != "" {
+		lines = append(lines, html.EscapeString(reply.Text))
+	}
+	for _, item := range reply.Items {
+		line := `• <a href="` + html.EscapeString(item.URL) + `">` + html.EscapeString(item.ID) + "</a>"
+		if item.Risk != "" {
+			line += " <b>[" + html.EscapeString(item.Risk) + "]</b>"
+		}
+		line += " " + html.EscapeString(item.Title)
+		if item.Date != "" {
+			line += " (" + item.Date + ")"
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// verifySlackSignature 校验Slack请求的签名
+// 签名为 v0= 加上以签名密钥对 "v0:时间戳:请求体" 计算的HMAC-SHA256，时间戳与now相差超过5分钟时拒绝
+func verifySlackSignature(secret, timestamp, signature string, body []byte, now time.Time) error {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errors.New(T("缺少或无效的Slack请求时间戳"))
+	}
+	if d := now.Sub(time.Unix(ts, 0)); d > slackRequestMaxAge || d < -slackRequestMaxAge {
+		return errors.New(T("Slack请求已过期"))
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "v0:%s:", timestamp)
+	mac.Write(body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return errors.New(T("Slack请求签名无效"))
+	}
+	return nil
+}
+
+// isSlackResponseURL 判断是否是Slack的响应地址，只向Slack的地址发送结果，避免被用来请求任意地址
+func isSlackResponseURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && u.Scheme == "https" && u.Host == "hooks.slack.com"
+}
+
+// handleSlackCommand 处理Slack斜杠命令，例如 "/cx search wordpress rce"
+// 请求通过签名校验，不使用API Token。Slack要求3秒内响应，因此先回复 "正在查询"，
+// 查询完成后将结果POST到请求中的 response_url
+func handleSlackCommand(c *crawler.Crawler, secret string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := verifySlackSignature(secret, r.Header.Get("X-Slack-Request-Timestamp"), r.Header.Get("X-Slack-Signature"), body, time.Now()); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		input := form.Get("text")
+		responseURL := form.Get("response_url")
+		if !isSlackResponseURL(responseURL) {
+			encodeJSON(w, slackMessage(runBotCommand(c, input)))
+			return
+		}
+		encodeJSON(w, map[string]string{"response_type": "ephemeral", "text": T("正在查询，请稍候...")})
+		go func() {
+			if err := sink.NewWebhook(responseURL).Send(slackMessage(runBotCommand(c, input))); err != nil {
+				logging.Printf(T("发送Slack回复失败: %v")+"\n", err)
+			}
+		}()
+	}
+}
+
+// telegramUpdate 是Telegram Webhook推送的更新中用到的字段
+type telegramUpdate struct {
+	Message *struct {
+		Chat struct {
+			ID int64 `json:"id"`
+		} `json:"chat"`
+		Text string `json:"text"`
+	} `json:"message"`
+}
+
+// telegramCommand 从消息中取出 /cx 命令的参数，例如 "/cx search php" 和 "/cx@MyBot search php" 返回 "search php"
+// /start 和 /help 返回 help，其他消息返回false
+func telegramCommand(text string) (string, bool) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return "", false
+	}
+	command, _, _ := strings.Cut(fields[0], "@")
+	switch command {
+	case "/cx":
+		return strings.Join(fields[1:], " "), true
+	case "/start", "/help":
+		return "help", true
+	}
+	return "", false
+}
+
+// handleTelegramUpdate 处理Telegram机器人的Webhook更新
+// 请求通过 setWebhook 时设置的 secret_token 校验，回复直接作为 sendMessage 方法放在响应中返回，
+// 因此不需要Bot Token。只响应 /cx、/start 和 /help 命令，其他消息忽略
+func handleTelegramUpdate(c *crawler.Crawler, secret string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := r.Header.Get("X-Telegram-Bot-Api-Secret-Token")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+			http.Error(w, T("Telegram请求的secret_token无效"), http.StatusUnauthorized)
+			return
+		}
+		var update telegramUpdate
+		if err := json.NewDecoder(io.LimitReader(r.Body, 1<<20)).Decode(&update); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if update.Message == nil {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		input, ok := telegramCommand(update.Message.Text)
+		if !ok {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		encodeJSON(w, map[string]interface{}{
+			"method":                   "sendMessage",
+			"chat_id":                  update.Message.Chat.ID,
+			"text":                     telegramText(runBotCommand(c, input)),
+			"parse_mode":               "HTML",
+			"disable_web_page_preview": true,
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+)
+
+// failingClient 是所有请求都失败的HTTP客户端
+type failingClient struct{}
+
+func (failingClient) GetPage(path string) (string, error) { return "", errors.New("offline") }
+func (failingClient) GetBaseURL() string                  { return "https://cxsecurity.com" }
+
+func TestRunBotCommand(t *testing.T) {
+	c := crawler.NewCrawler(crawler.WithHTTPClient(failingClient{}))
+
+	assert.Equal(t, botHelp(), runBotCommand(c, ""))
+	assert.Equal(t, botHelp(), runBotCommand(c, "HELP"))
+	assert.Equal(t, "用法: show <WLB-ID>", runBotCommand(c, "show").Text)
+	assert.Equal(t, "用法: search <关键词> [risk:High]", runBotCommand(c, "search risk:High").Text)
+	assert.Contains(t, runBotCommand(c, "search php risk:urgent").Text, `无效的风险级别 "urgent"`)
+	assert.Contains(t, runBotCommand(c, "wordpress rce").Text, "搜索失败")
+	assert.Contains(t, runBotCommand(c, "WLB-2024040015").Text, "获取漏洞详情失败")
+	assert.Contains(t, runBotCommand(c, "cve-2007-1411").Text, "获取CVE详情失败")
+	assert.Contains(t, runBotCommand(c, "cve 1411").Text, "CVE")
+}
+
+func TestBotMessages(t *testing.T) {
+	reply := botReply{
+		Text:  `"<php>" 的搜索结果`,
+		Items: []botItem{{ID: "WLB-1", Title: "PHP <8.1 & RCE", URL: "https://cxsecurity.com/issue/WLB-1", Risk: "High", Date: "2024-04-01"}, {ID: "WLB-2", Title: "b", URL: "https://cxsecurity.com/issue/WLB-2"}},
+	}
+	assert.Equal(t, map[string]string{
+		"response_type": "in_channel",
+		"text":          "\"&lt;php&gt;\" 的搜索结果\n• <https://cxsecurity.com/issue/WLB-1|WLB-1> `High` PHP &lt;8.1 &amp; RCE (2024-04-01)\n• <https://cxsecurity.com/issue/WLB-2|WLB-2> b",
+	}, slackMessage(reply))
+	assert.Equal(t, "&#34;&lt;php&gt;&#34; 的搜索结果\n"+
+		`• <a href="https://cxsecurity.com/issue/WLB-1">WLB-1</a> <b>[High]</b> PHP &lt;8.1 &amp; RCE (2024-04-01)`+"\n"+
+		`• <a href="https://cxsecurity.com/issue/WLB-2">WLB-2</a> b`, telegramText(reply))
+}
+
+// signSlack 返回Slack请求的签名
+func signSlack(secret, timestamp, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "v0:%s:%s", timestamp, body)
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifySlackSignature(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	ts := strconv.FormatInt(now.Unix(), 10)
+	body := []byte("text=search+php")
+	sig := signSlack("secret", ts, string(body))
+
+	assert.NoError(t, verifySlackSignature("secret", ts, sig, body, now))
+	assert.ErrorContains(t, verifySlackSignature("other", ts, sig, body, now), "签名无效")
+	assert.ErrorContains(t, verifySlackSignature("secret", ts, sig, []byte("text=cve"), now), "签名无效")
+	assert.ErrorContains(t, verifySlackSignature("secret", ts, sig, body, now.Add(10*time.Minute)), "已过期")
+	assert.ErrorContains(t, verifySlackSignature("secret", "", sig, body, now), "时间戳")
+}
+
+func TestHandleSlackCommand(t *testing.T) {
+	handler := handleSlackCommand(crawler.NewCrawler(crawler.WithHTTPClient(failingClient{})), "secret")
+	body := "command=%2Fcx&text=help&response_url=https%3A%2F%2Fexample.com%2Fhook"
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req := httptest.NewRequest(http.MethodPost, "/bot/slack", strings.NewReader(body))
+	req.Header.Set("X-Slack-Request-Timestamp", ts)
+	req.Header.Set("X-Slack-Signature", signSlack("wrong", ts, body))
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// response_url 不是Slack的地址时直接在响应中回复
+	req = httptest.NewRequest(http.MethodPost, "/bot/slack", strings.NewReader(body))
+	req.Header.Set("X-Slack-Request-Timestamp", ts)
+	req.Header.Set("X-Slack-Signature", signSlack("secret", ts, body))
+	rec = httptest.NewRecorder()
+	handler(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var msg map[string]string
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &msg))
+	assert.Equal(t, "in_channel", msg["response_type"])
+	assert.Contains(t, msg["text"], "search &lt;关键词&gt;")
+
+	assert.True(t, isSlackResponseURL("https://hooks.slack.com/commands/T1/2/abc"))
+	assert.False(t, isSlackResponseURL("http://hooks.slack.com/commands/T1/2/abc"))
+	assert.False(t, isSlackResponseURL("https://hooks.slack.com.evil.com/x"))
+}
+
+func TestTelegramCommand(t *testing.T) {
+	tests := []struct {
+		text  string
+		input string
+		ok    bool
+	}{
+		{"/cx search php", "search php", true},
+		{"/cx@CxBot  cve CVE-2007-1411", "cve CVE-2007-1411", true},
+		{"/start", "help", true},
+		{"/cxx php", "", false},
+		{"hello", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		input, ok := telegramCommand(tt.text)
+		assert.Equal(t, tt.input, input, tt.text)
+		assert.Equal(t, tt.ok, ok, tt.text)
+	}
+}
+
+func TestHandleTelegramUpdate(t *testing.T) {
+	handler := handleTelegramUpdate(crawler.NewCrawler(crawler.WithHTTPClient(failingClient{})), "secret")
+	update := `{"update_id":1,"message":{"chat":{"id":42},"text":"/cx help"}}`
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodPost, "/bot/telegram", strings.NewReader(update)))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req := httptest.NewRequest(http.MethodPost, "/bot/telegram", strings.NewReader(update))
+	req.Header.Set("X-Telegram-Bot-Api-Secret-Token", "secret")
+	rec = httptest.NewRecorder()
+	handler(rec, req)
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, "sendMessage", resp["method"])
+	assert.Equal(t, float64(42), resp["chat_id"])
+	assert.Equal(t, "HTML", resp["parse_mode"])
+	assert.Contains(t, resp["text"], "search &lt;关键词&gt;")
+
+	// 其他消息不回复
+	req = httptest.NewRequest(http.MethodPost, "/bot/telegram", strings.NewReader(`{"message":{"chat":{"id":42},"text":"hi"}}`))
+	req.Header.Set("X-Telegram-Bot-Api-Secret-Token", "secret")
+	rec = httptest.NewRecorder()
+	handler(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Body.String())
+}
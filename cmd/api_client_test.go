@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/apiclient"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/config"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/store"
+)
+
+// TestAPIClient 用 pkg/apiclient 请求实际的处理函数，确保客户端的类型与服务端的响应一致
+func TestAPIClient(t *testing.T) {
+	content, err := os.ReadFile("../docs/response-examples/search-response.html")
+	if err != nil {
+		t.Skip("跳过测试，测试文件不存在：../docs/response-examples/search-response.html")
+	}
+	oldToken := apiToken
+	apiToken = "secret"
+	defer func() { apiToken = oldToken }()
+
+	path := filepath.Join(t.TempDir(), "vulnerabilities.json")
+	s, err := store.Open(path)
+	require.NoError(t, err)
+	s.Put(model.Vulnerability{
+		ID:        "WLB-2024040015",
+		Title:     "Foo",
+		Date:      time.Date(2024, 4, 9, 0, 0, 0, 0, time.UTC),
+		Author:    "hyp3rlinx",
+		AuthorURL: "https://cxsecurity.com/author/hyp3rlinx/1/",
+		RiskLevel: "High",
+	})
+	require.NoError(t, s.Save())
+	authorsPath := filepath.Join(t.TempDir(), "authors.json")
+	authors, err := config.NewAuthorStore(authorsPath)
+	require.NoError(t, err)
+	_, _, err = authors.Update(&model.AuthorProfile{ID: "hyp3rlinx", Country: "United States", CountryCode: "US"}, time.Now())
+	require.NoError(t, err)
+
+	c := crawler.NewCrawler(crawler.WithHTTPClient(&fixtureClient{content: string(content)}))
+	r := mux.NewRouter()
+	registerAPIRoutes(r, []apiRoute{
+		{"/exploit", handleExploitList(c)},
+		{"/exploit/latest", handleExploitLatest(c)},
+		{"/local/authors", handleLocalAuthors(authorsPath)},
+		{"/local/authors/{id}/vulnerabilities", handleLocalAuthor(path)},
+	}, time.Time{})
+	server := httptest.NewServer(r)
+	defer server.Close()
+	ctx := context.Background()
+	client := apiclient.NewClient(server.URL, "secret", apiclient.WithRetry(0, 0))
+
+	var freshness crawler.Freshness
+	list, err := client.ExploitList(apiclient.WithFreshness(ctx, &freshness), "", 1)
+	require.NoError(t, err)
+	require.NotEmpty(t, list.Items)
+	assert.NotEmpty(t, list.Items[0].ID)
+	assert.Equal(t, crawler.SourceLive, freshness.Source)
+	assert.False(t, freshness.CacheHit)
+	assert.WithinDuration(t, time.Now(), freshness.FetchedAt, time.Minute)
+
+	latest, err := client.Latest(ctx, crawler.LatestOptions{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, latest.Vulnerabilities, 2)
+	assert.Equal(t, latest.Vulnerabilities[0].ID, latest.Cursor)
+
+	local, err := client.LocalAuthorVulnerabilities(apiclient.WithFreshness(ctx, &freshness), "hyp3rlinx", apiclient.LocalAuthorOptions{Risks: []string{"High"}})
+	require.NoError(t, err)
+	assert.Equal(t, crawler.SourceStore, freshness.Source)
+	assert.True(t, freshness.CacheHit)
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.True(t, info.ModTime().Equal(freshness.FetchedAt), "本地存储的获取时间为文件最后保存的时间")
+	assert.Equal(t, 1, local.Total)
+	require.Len(t, local.Vulnerabilities, 1)
+	assert.Equal(t, "Foo", local.Vulnerabilities[0].Title)
+	assert.True(t, local.Vulnerabilities[0].Date.Equal(time.Date(2024, 4, 9, 0, 0, 0, 0, time.UTC)))
+
+	watched, err := client.LocalAuthors(ctx, "us")
+	require.NoError(t, err)
+	require.Len(t, watched.Authors, 1)
+	assert.Equal(t, "US", watched.Authors[0].CountryCode)
+	assert.Equal(t, []apiclient.LocalCountry{{CountryCode: "US", Country: "United States", Authors: 1}}, watched.Countries)
+
+	_, err = apiclient.NewClient(server.URL, "wrong", apiclient.WithRetry(0, 0)).ExploitList(ctx, "", 1)
+	assert.Error(t, err)
+}
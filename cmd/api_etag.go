@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// bufferedResponseWriter 缓存处理函数写入的状态码和响应体，响应头直接写入底层ResponseWriter
+type bufferedResponseWriter struct {
+	http.ResponseWriter
+	status  int
+	body    bytes.Buffer
+	hasMeta bool // 响应由 encodeData 写入，末尾有 meta 字段
+}
+
+func (b *bufferedResponseWriter) WriteHeader(status int) {
+	b.status = status
+}
+
+func (b *bufferedResponseWriter) Write(p []byte) (int, error) {
+	return b.body.Write(p)
+}
+
+// etagMiddleware 为GET请求的200响应计算ETag，并支持 If-None-Match 条件请求
+// ETag是响应体的SHA-256，内容不变时返回304且不发送响应体，减少频繁轮询的客户端的流量；
+// 服务端仍然会执行请求，条件请求只节省带宽。
+//
+// 参数:
+//   - next: 下一个要执行的处理函数
+//
+// 返回值:
+//   - http.HandlerFunc: 包装后的处理函数
+func etagMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		buf := &bufferedResponseWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(buf, r)
+		if buf.status != http.StatusOK {
+			w.WriteHeader(buf.status)
+			w.Write(buf.body.Bytes())
+			return
+		}
+
+		// meta 中的 fetched_at 每次请求都会变化，ETag只按数据计算，否则条件请求永远不会命中
+		tagged := buf.body.Bytes()
+		if buf.hasMeta {
+			if i := bytes.LastIndex(tagged, []byte(`,"meta":`)); i >= 0 {
+				tagged = tagged[:i]
+			}
+		}
+		sum := sha256.Sum256(tagged)
+		etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+		w.Header().Set("ETag", etag)
+		// 客户端可以缓存响应，但每次使用前需要用 If-None-Match 重新验证
+		w.Header().Set("Cache-Control", "private, no-cache")
+		if etagMatches(r.Header.Get("If-None-Match"), etag) {
+			w.Header().Del("Content-Type")
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Write(buf.body.Bytes())
+	}
+}
+
+// etagMatches 判断 If-None-Match 是否匹配etag
+// 按RFC 9110使用弱比较：忽略 W/ 前缀，"*" 匹配任意ETag
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
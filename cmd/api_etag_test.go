@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+)
+
+func TestEtagMiddleware(t *testing.T) {
+	data := "v1"
+	handler := securityHeadersMiddleware(etagMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		if data == "" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		encodeJSON(w, APIResponse{Success: true, Data: data})
+	}))
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/search", nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+
+	rec := get("")
+	require.Equal(t, http.StatusOK, rec.Code)
+	etag := rec.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.Contains(t, rec.Body.String(), `"v1"`)
+	assert.Equal(t, "private, no-cache", rec.Header().Get("Cache-Control"))
+
+	rec = get(etag)
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Empty(t, rec.Body.String())
+	assert.Equal(t, etag, rec.Header().Get("ETag"))
+	assert.Equal(t, http.StatusNotModified, get(`"other", W/`+etag).Code, "弱比较并支持多个ETag")
+	assert.Equal(t, http.StatusNotModified, get("*").Code)
+
+	// 内容变化后ETag随之变化
+	data = "v2"
+	rec = get(etag)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotEqual(t, etag, rec.Header().Get("ETag"))
+
+	// 非200响应不计算ETag
+	data = ""
+	rec = get("*")
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Empty(t, rec.Header().Get("ETag"))
+	assert.Contains(t, rec.Body.String(), "success")
+}
+
+func TestEtagIgnoresMeta(t *testing.T) {
+	data := "v1"
+	handler := etagMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		encodeData(w, data, crawler.LiveFreshness(time.Now()))
+	})
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/exploit", nil)
+		req.Header.Set("If-None-Match", ifNoneMatch)
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+
+	rec := get("")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"meta":{"fetched_at":`)
+	etag := rec.Header().Get("ETag")
+
+	// 每次请求的获取时间不同，数据不变时仍然返回304
+	time.Sleep(time.Millisecond)
+	assert.Equal(t, http.StatusNotModified, get(etag).Code)
+	data = "v2"
+	assert.Equal(t, http.StatusOK, get(etag).Code)
+}
@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/export"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/logging"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+var (
+	apiFeedAuthors  []string
+	apiFeedInterval time.Duration
+)
+
+// authorFeedEntry 是一个作者最近一次爬取的结果
+type authorFeedEntry struct {
+	profile   *model.AuthorProfile
+	crawledAt time.Time
+	cached    bool // 由get设置，是否使用了缓存的结果而没有请求网站
+}
+
+// freshness 返回结果的获取时间和来源
+func (e authorFeedEntry) freshness() crawler.Freshness {
+	if e.cached {
+		return crawler.Freshness{FetchedAt: e.crawledAt, CacheHit: true, Source: crawler.SourceCache}
+	}
+	return crawler.LiveFreshness(e.crawledAt)
+}
+
+// authorFeedCache 缓存作者页面的爬取结果，订阅阅读器频繁轮询时不会每次都请求网站
+// 超过interval的结果在下一次请求时重新爬取，--feed-author 指定的作者由后台定期爬取
+type authorFeedCache struct {
+	c        *crawler.Crawler
+	interval time.Duration
+
+	mu      sync.Mutex
+	entries map[string]authorFeedEntry
+}
+
+// newAuthorFeedCache 创建作者订阅的缓存，interval为0时每次请求都重新爬取
+func newAuthorFeedCache(c *crawler.Crawler, interval time.Duration) *authorFeedCache {
+	return &authorFeedCache{c: c, interval: interval, entries: make(map[string]authorFeedEntry)}
+}
+
+// get 返回作者的爬取结果，没有缓存或缓存过期时重新爬取
+// 重新爬取失败但有旧结果时返回旧结果，网站暂时不可用时阅读器中的订阅不会报错
+func (f *authorFeedCache) get(id string, now time.Time) (authorFeedEntry, error) {
+	f.mu.Lock()
+	entry, ok := f.entries[id]
+	f.mu.Unlock()
+	entry.cached = true
+	if ok && now.Sub(entry.crawledAt) < f.interval {
+		return entry, nil
+	}
+	fresh, err := f.refresh(id, now)
+	if err != nil {
+		if ok {
+			logging.Printf(T("更新作者 %s 的订阅失败，使用上次的结果: %v")+"\n", id, err)
+			return entry, nil
+		}
+		return authorFeedEntry{}, err
+	}
+	return fresh, nil
+}
+
+// refresh 爬取作者页面并更新缓存
+func (f *authorFeedCache) refresh(id string, now time.Time) (authorFeedEntry, error) {
+	profile, err := f.c.CrawlAuthor(url.PathEscape(id), "")
+	if err != nil {
+		return authorFeedEntry{}, err
+	}
+	entry := authorFeedEntry{profile: profile, crawledAt: now}
+	f.mu.Lock()
+	f.entries[id] = entry
+	f.mu.Unlock()
+	return entry, nil
+}
+
+// start 在后台定期爬取ids中的作者，启动时立即爬取一次
+// 失败只记录日志，下次请求订阅时会再次尝试
+func (f *authorFeedCache) start(ids []string) {
+	if len(ids) == 0 || f.interval <= 0 {
+		return
+	}
+	refreshAll := func(now time.Time) {
+		for _, id := range ids {
+			if _, err := f.refresh(id, now); err != nil {
+				logging.Printf(T("更新作者 %s 的订阅失败: %v")+"\n", id, err)
+			}
+		}
+	}
+	go func() {
+		refreshAll(time.Now())
+		ticker := time.NewTicker(f.interval)
+		defer ticker.Stop()
+		for now := range ticker.C {
+			refreshAll(now)
+		}
+	}()
+}
+
+/**
+ * @api {get} /api/author/:id/feed 作者新发布的RSS订阅
+ * @apiName GetAuthorFeed
+ * @apiGroup Author
+ * @apiVersion 1.0.0
+ *
+ * @apiParam {String} id 作者ID
+ * @apiParam {String} [token] API认证Token，阅读器通常无法设置请求头，可以放在订阅地址中
+ *
+ * @apiSuccessExample {xml} 成功响应:
+ *     HTTP/1.1 200 OK
+ *     Content-Type: application/rss+xml; charset=utf-8
+ *     <rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom">
+ *       <channel>
+ *         <title>hyp3rlinx 在CXSecurity发布的漏洞</title>
+ *         <link>https://cxsecurity.com/author/hyp3rlinx/1/</link>
+ *         <item>
+ *           <title>WordPress Plugin Vulnerability</title>
+ *           <link>https://cxsecurity.com/issue/WLB-2024040015</link>
+ *           <guid isPermaLink="false">WLB-2024040015</guid>
+ *           <pubDate>Tue, 09 Apr 2024 00:00:00 +0000</pubDate>
+ *         </item>
+ *       </channel>
+ *     </rss>
+ *
+ * @apiExample {curl} 示例:
+ *     curl "http://localhost:8080/api/v1/author/hyp3rlinx/feed?token=your-token"
+ */
+// handleAuthorFeed 以RSS 2.0格式返回作者最近发布的漏洞，用于在订阅阅读器中关注研究人员
+// 结果来自缓存，最多每 --feed-interval 爬取一次作者页面；失败时与其他接口一样返回JSON错误
+func handleAuthorFeed(feeds *authorFeedCache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := mux.Vars(r)["id"]
+		entry, err := feeds.get(id, time.Now())
+		if err != nil {
+			encodeJSON(w, APIResponse{
+				Success: false,
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		name := entry.profile.Name
+		if name == "" {
+			name = id
+		}
+		// 订阅地址不包含查询参数，避免Token出现在阅读器显示的地址中
+		scheme := "http"
+		if r.TLS != nil {
+			scheme = "https"
+		}
+		var buf bytes.Buffer
+		if err := export.WriteRSS(&buf, export.RSSChannel{
+			Title:       fmt.Sprintf(T("%s 在CXSecurity发布的漏洞"), name),
+			Link:        model.SiteURL + "/author/" + url.PathEscape(id) + "/1/",
+			Description: fmt.Sprintf(T("%s 最近发布的漏洞，每 %s 更新一次"), name, feeds.interval),
+			FeedURL:     scheme + "://" + r.Host + r.URL.Path,
+			Updated:     entry.crawledAt,
+		}, entry.profile.Vulnerabilities); err != nil {
+			encodeJSON(w, APIResponse{
+				Success: false,
+				Error:   err.Error(),
+			})
+			return
+		}
+		w.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
+		setFreshnessHeaders(w, entry.freshness())
+		w.Write(buf.Bytes())
+	}
+}
+
+// setFreshnessHeaders 为没有JSON信封的响应(例如RSS订阅)设置与 meta 相同含义的响应头
+func setFreshnessHeaders(w http.ResponseWriter, f crawler.Freshness) {
+	if !f.FetchedAt.IsZero() {
+		w.Header().Set("Last-Modified", f.FetchedAt.UTC().Format(http.TimeFormat))
+	}
+	cache := "MISS"
+	if f.CacheHit {
+		cache = "HIT"
+	}
+	w.Header().Set("X-Cache", cache)
+	w.Header().Set("X-Data-Source", string(f.Source))
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+)
+
+// countingClient 记录请求次数，用于检查缓存是否生效
+type countingClient struct {
+	fixtureClient
+	requests int
+}
+
+func (c *countingClient) GetPage(path string) (string, error) {
+	c.requests++
+	return c.fixtureClient.GetPage(path)
+}
+
+func TestHandleAuthorFeed(t *testing.T) {
+	content, err := os.ReadFile("../docs/response-examples/author-profile-response.html")
+	if err != nil {
+		t.Skip("跳过测试，测试文件不存在：../docs/response-examples/author-profile-response.html")
+	}
+	client := &countingClient{fixtureClient: fixtureClient{content: string(content)}}
+	feeds := newAuthorFeedCache(crawler.NewCrawler(crawler.WithHTTPClient(client)), time.Hour)
+	r := mux.NewRouter()
+	r.HandleFunc("/api/v1/author/{id}/feed", handleAuthorFeed(feeds))
+
+	get := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://api.example.com/api/v1/author/hyp3rlinx/feed?token=secret", nil))
+		return rec
+	}
+	rec := get()
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/rss+xml; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "MISS", rec.Header().Get("X-Cache"))
+	assert.Equal(t, "live", rec.Header().Get("X-Data-Source"))
+	assert.NotEmpty(t, rec.Header().Get("Last-Modified"))
+	var doc struct {
+		Channel struct {
+			Title string `xml:"title"`
+		} `xml:"channel"`
+	}
+	require.NoError(t, xml.Unmarshal(rec.Body.Bytes(), &doc))
+	assert.Contains(t, doc.Channel.Title, "m4xth0r")
+	assert.Contains(t, rec.Body.String(), "<link>https://cxsecurity.com/author/hyp3rlinx/1/</link>")
+	assert.Contains(t, rec.Body.String(), `href="http://api.example.com/api/v1/author/hyp3rlinx/feed"`)
+	assert.NotContains(t, rec.Body.String(), "secret", "订阅地址中不应包含Token")
+
+	// 间隔内使用缓存
+	rec = get()
+	assert.Equal(t, 1, client.requests)
+	assert.Equal(t, "HIT", rec.Header().Get("X-Cache"))
+	assert.Equal(t, "cache", rec.Header().Get("X-Data-Source"))
+
+	// 过期后重新爬取失败时使用上次的结果
+	feeds.c = crawler.NewCrawler(crawler.WithHTTPClient(failingClient{}))
+	entry, err := feeds.get("hyp3rlinx", time.Now().Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.NotNil(t, entry.profile)
+
+	// 没有缓存时返回错误
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/author/other/feed", nil))
+	assert.Contains(t, rec.Body.String(), `"success":false`)
+}
@@ -0,0 +1,17 @@
+//go:build !jsoniter
+
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// apiJSONEncoder 标识当前编译使用的JSON编码器
+const apiJSONEncoder = "encoding/json"
+
+// encodeJSON 使用标准库encoding/json将v编码后写入w
+// 使用 -tags jsoniter 编译时会被替换为基于jsoniter的实现，见 api_json_jsoniter.go
+func encodeJSON(w io.Writer, v interface{}) error {
+	return json.NewEncoder(w).Encode(v)
+}
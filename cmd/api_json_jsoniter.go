@@ -0,0 +1,29 @@
+//go:build jsoniter
+
+package cmd
+
+import (
+	"io"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// apiJSONEncoder 标识当前编译使用的JSON编码器
+const apiJSONEncoder = "jsoniter"
+
+// jsonAPI 与encoding/json行为兼容的jsoniter配置，会调用模型上的自定义MarshalJSON
+var jsonAPI = jsoniter.ConfigCompatibleWithStandardLibrary
+
+// encodeJSON 使用jsoniter将v编码后写入w
+// 编码流从池中借用并在结束后归还，适用于高QPS的API部署
+func encodeJSON(w io.Writer, v interface{}) error {
+	stream := jsonAPI.BorrowStream(w)
+	defer jsonAPI.ReturnStream(stream)
+
+	stream.WriteVal(v)
+	stream.WriteRaw("\n")
+	if err := stream.Flush(); err != nil {
+		return err
+	}
+	return stream.Error
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+// newBenchmarkResponse 构造一个与漏洞列表接口响应规模相当的数据
+func newBenchmarkResponse() APIResponse {
+	list := &model.VulnerabilityList{CurrentPage: 1, TotalPages: 15}
+	for i := 0; i < 60; i++ {
+		list.Items = append(list.Items, model.Vulnerability{
+			ID:        "WLB-2024040015",
+			Date:      time.Date(2024, 4, 9, 0, 0, 0, 0, time.UTC),
+			Title:     "WordPress Plugin Vulnerability",
+			URL:       "https://cxsecurity.com/issue/WLB-2024040015",
+			RiskLevel: "High",
+			CVE:       "CVE-2024-32113",
+			CWE:       "CWE-22",
+			IsRemote:  true,
+			Tags:      []string{"WordPress", "Plugin"},
+			Author:    "Security Researcher",
+			AuthorURL: "https://cxsecurity.com/author/researcher/1/",
+		})
+	}
+	return APIResponse{Success: true, Data: list}
+}
+
+func TestEncodeJSONCompatible(t *testing.T) {
+	resp := APIResponse{
+		Success: true,
+		Data: &crawler.SearchResult{
+			Keyword:     "xss",
+			CurrentPage: 1,
+			TotalPages:  2,
+			SortOrder:   "DESC",
+			PerPage:     10,
+		},
+	}
+
+	var got bytes.Buffer
+	if err := encodeJSON(&got, resp); err != nil {
+		t.Fatalf("编码失败: %v", err)
+	}
+
+	var want bytes.Buffer
+	if err := json.NewEncoder(&want).Encode(resp); err != nil {
+		t.Fatalf("标准库编码失败: %v", err)
+	}
+
+	if got.String() != want.String() {
+		t.Errorf("%s 的编码结果与encoding/json不一致:\n期望: %s\n实际: %s", apiJSONEncoder, want.String(), got.String())
+	}
+}
+
+// BenchmarkEncodeJSON 衡量当前编译所用编码器的性能，
+// 分别运行 go test -bench EncodeJSON ./cmd/ 与 go test -tags jsoniter -bench EncodeJSON ./cmd/ 对比两者差异
+func BenchmarkEncodeJSON(b *testing.B) {
+	resp := newBenchmarkResponse()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := encodeJSON(io.Discard, resp); err != nil {
+			b.Fatalf("编码失败: %v", err)
+		}
+	}
+}
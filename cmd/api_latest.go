@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+)
+
+// latestMaxLimit 是 /api/exploit/latest 每次最多返回的记录数
+const latestMaxLimit = 100
+
+/**
+ * @api {get} /api/exploit/latest 增量获取新漏洞
+ * @apiName GetExploitLatest
+ * @apiGroup Exploit
+ * @apiVersion 1.0.0
+ *
+ * @apiHeader {String} X-API-Token API认证Token
+ *
+ * @apiParam {String} [since] 上次调用返回的cursor，只返回比它新的漏洞；为空时返回最新的limit条
+ * @apiParam {Number} [limit=20] 最多返回的记录数，最多100
+ * @apiParam {String} [section=exploit] 列表栏目，可选 exploit 或 wlb
+ *
+ * @apiSuccess {String} data.cursor 下次调用时作为since传入
+ * @apiSuccess {Boolean} data.has_more 还有更新的漏洞没有返回，应立即用cursor再次调用
+ * @apiSuccess {Boolean} data.gap 最近的列表页中没有找到since，可能漏掉了漏洞
+ *
+ * @apiSuccessExample {json} 成功响应:
+ *     HTTP/1.1 200 OK
+ *     {
+ *       "success": true,
+ *       "data": {
+ *         "since": "WLB-2024040015",
+ *         "cursor": "WLB-2024040018",
+ *         "has_more": false,
+ *         "gap": false,
+ *         "pages_scanned": 1,
+ *         "vulnerabilities": [
+ *           {"id": "WLB-2024040018", "title": "WordPress Plugin Vulnerability", "date": "2024-04-10T00:00:00Z"}
+ *         ]
+ *       }
+ *     }
+ *
+ * @apiExample {curl} 示例:
+ *     curl -H "X-API-Token: your-token" "http://localhost:8080/api/exploit/latest?since=WLB-2024040015&limit=50"
+ */
+// handleExploitLatest 返回比since新的漏洞，用于只关心新条目的轮询程序
+func handleExploitLatest(c *crawler.Crawler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		limit := crawler.DefaultLatestLimit
+		if l, err := strconv.Atoi(query.Get("limit")); err == nil && l > 0 {
+			limit = min(l, latestMaxLimit)
+		}
+		// 只允许已知栏目，不接受自定义路径
+		section := crawler.SectionExploit
+		if s := query.Get("section"); s != "" {
+			section = crawler.Section(s)
+			if strings.HasPrefix(s, "/") {
+				section = ""
+			}
+		}
+		if _, err := crawler.SectionPath(section, 1); err != nil {
+			encodeJSON(w, APIResponse{
+				Success: false,
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		result, err := c.Latest(crawler.LatestOptions{
+			Section: section,
+			Since:   query.Get("since"),
+			Limit:   limit,
+		})
+		if err != nil {
+			encodeJSON(w, APIResponse{
+				Success: false,
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		encodeData(w, result, crawler.LiveFreshness(time.Now()))
+	}
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+)
+
+func TestHandleExploitLatest(t *testing.T) {
+	content, err := os.ReadFile("../docs/response-examples/search-response.html")
+	if err != nil {
+		t.Skip("跳过测试，测试文件不存在：../docs/response-examples/search-response.html")
+	}
+	handler := handleExploitLatest(crawler.NewCrawler(crawler.WithHTTPClient(&fixtureClient{content: string(content)})))
+	get := func(query string) (bool, crawler.LatestResult, string) {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodGet, "/api/exploit/latest"+query, nil))
+		var resp struct {
+			Success bool                 `json:"success"`
+			Data    crawler.LatestResult `json:"data"`
+			Error   string               `json:"error"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		return resp.Success, resp.Data, resp.Error
+	}
+
+	ok, result, _ := get("?limit=2")
+	require.True(t, ok)
+	require.Len(t, result.Vulnerabilities, 2)
+	assert.Equal(t, result.Vulnerabilities[0].ID, result.Cursor)
+
+	// 用cursor再次调用时没有新漏洞
+	ok, result, _ = get("?since=" + result.Cursor)
+	require.True(t, ok)
+	assert.Empty(t, result.Vulnerabilities)
+	assert.False(t, result.Gap)
+
+	ok, _, errText := get("?section=/admin/")
+	assert.False(t, ok)
+	assert.NotEmpty(t, errText)
+	ok, _, _ = get("?since=CVE-2024-0001")
+	assert.False(t, ok)
+
+	handler = handleExploitLatest(crawler.NewCrawler(crawler.WithHTTPClient(failingClient{})))
+	ok, _, errText = get("")
+	assert.False(t, ok)
+	assert.Contains(t, errText, "获取第 1 页失败")
+}
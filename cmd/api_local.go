@@ -0,0 +1,280 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"golang.org/x/text/language"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/config"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/store"
+)
+
+// 本地作者查询每页的默认和最大记录数
+const (
+	localDefaultPerPage = 50
+	localMaxPerPage     = 500
+)
+
+// localAuthorResult 是存储中某个作者的漏洞，按发布日期从新到旧分页
+type localAuthorResult struct {
+	AuthorID        string                `json:"author_id"`
+	AuthorName      string                `json:"author_name,omitempty"`
+	Total           int                   `json:"total"` // 过滤后的记录总数
+	Page            int                   `json:"page"`
+	PerPage         int                   `json:"per_page"`
+	TotalPages      int                   `json:"total_pages"`
+	Vulnerabilities []model.Vulnerability `json:"vulnerabilities"`
+}
+
+// localAuthorVulnerabilities 返回存储中作者的漏洞，risks为规范化的风险级别
+// perPage小于等于0时返回全部记录
+func localAuthorVulnerabilities(s *store.Store, author string, risks []string, remote, local bool, page, perPage int) localAuthorResult {
+	items := filterVulnerabilities(s.ByAuthor(author), risks, remote, local)
+	result := localAuthorResult{AuthorID: strings.TrimSpace(author), Total: len(items), Page: page, PerPage: perPage}
+	for _, v := range items {
+		if v.Author != "" {
+			result.AuthorName = v.Author
+			break
+		}
+	}
+
+	if perPage <= 0 {
+		result.Page, result.PerPage, result.TotalPages = 1, len(items), 1
+		result.Vulnerabilities = items
+		return result
+	}
+	result.TotalPages = (len(items) + perPage - 1) / perPage
+	start := (page - 1) * perPage
+	if start >= len(items) {
+		result.Vulnerabilities = []model.Vulnerability{}
+		return result
+	}
+	result.Vulnerabilities = items[start:min(start+perPage, len(items))]
+	return result
+}
+
+/**
+ * @api {get} /api/local/authors/:id/vulnerabilities 查询存储中作者的漏洞
+ * @apiName GetLocalAuthorVulnerabilities
+ * @apiGroup Local
+ * @apiVersion 1.0.0
+ *
+ * @apiHeader {String} X-API-Token API认证Token
+ *
+ * @apiParam {String} id 作者ID，即作者页面URL中的名称，不区分大小写
+ * @apiParam {Number} [page=1] 页码
+ * @apiParam {Number} [per_page=50] 每页记录数，最多500
+ * @apiParam {String} [risk] 只返回指定风险级别的漏洞(High、Med、Low)，多个用逗号分隔
+ * @apiParam {Boolean} [remote] 只返回可远程利用的漏洞
+ * @apiParam {Boolean} [local] 只返回本地利用的漏洞
+ *
+ * @apiSuccessExample {json} 成功响应:
+ *     HTTP/1.1 200 OK
+ *     {
+ *       "success": true,
+ *       "data": {
+ *         "author_id": "hyp3rlinx",
+ *         "author_name": "hyp3rlinx",
+ *         "total": 312,
+ *         "page": 1,
+ *         "per_page": 50,
+ *         "total_pages": 7,
+ *         "vulnerabilities": [
+ *           {"id": "WLB-2024040015", "title": "WordPress Plugin Vulnerability", "date": "2024-04-09T00:00:00Z"}
+ *         ]
+ *       }
+ *     }
+ *
+ * @apiExample {curl} 示例:
+ *     curl -H "X-API-Token: your-token" "http://localhost:8080/api/local/authors/hyp3rlinx/vulnerabilities?risk=High&page=2"
+ */
+// handleLocalAuthor 从 --store 指定的存储中查询作者的所有漏洞，不请求cxsecurity
+// 每次请求时重新读取存储文件，import 等命令更新存储后不需要重启服务
+func handleLocalAuthor(storePath string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		page, perPage := 1, localDefaultPerPage
+		if p, err := strconv.Atoi(query.Get("page")); err == nil && p > 0 {
+			page = p
+		}
+		if pp, err := strconv.Atoi(query.Get("per_page")); err == nil && pp > 0 {
+			perPage = min(pp, localMaxPerPage)
+		}
+
+		var risks []string
+		if riskStr := query.Get("risk"); riskStr != "" {
+			for _, risk := range strings.Split(riskStr, ",") {
+				normalized := crawler.NormalizeRiskLevel(risk)
+				if normalized == "" {
+					encodeJSON(w, APIResponse{
+						Success: false,
+						Error:   fmt.Sprintf(T("无效的风险级别 %q，可选值：High、Med、Low"), risk),
+					})
+					return
+				}
+				risks = append(risks, normalized)
+			}
+		}
+		remote, _ := strconv.ParseBool(query.Get("remote"))
+		local, _ := strconv.ParseBool(query.Get("local"))
+
+		s, err := openStore(storePath)
+		if err != nil {
+			encodeJSON(w, APIResponse{
+				Success: false,
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		// 存储中的记录来自不同时间的爬取，获取时间使用存储文件最后保存的时间
+		meta := crawler.Freshness{CacheHit: true, Source: crawler.SourceStore}
+		if info, err := os.Stat(s.Path()); err == nil {
+			meta.FetchedAt = info.ModTime()
+		}
+		encodeData(w, localAuthorVulnerabilities(s, mux.Vars(r)["id"], risks, remote, local, page, perPage), meta)
+	}
+}
+
+// localAuthor 是 watch-author 监控的一个作者
+type localAuthor struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name,omitempty"`
+	Country     string    `json:"country,omitempty"`      // 国家英文名称
+	CountryCode string    `json:"country_code,omitempty"` // ISO 3166-1国家代码
+	Seen        int       `json:"seen"`                   // 见过的漏洞数
+	LastChecked time.Time `json:"last_checked"`
+}
+
+// localCountry 是一个国家的作者数
+type localCountry struct {
+	CountryCode string `json:"country_code"` // 为空表示作者页面上没有国家
+	Country     string `json:"country,omitempty"`
+	Authors     int    `json:"authors"`
+}
+
+// localAuthorsResult 是监控的作者列表和按国家的统计
+type localAuthorsResult struct {
+	Total     int            `json:"total"` // 过滤后的作者数
+	Authors   []localAuthor  `json:"authors"`
+	Countries []localCountry `json:"countries"` // 所有作者按国家统计，不受country过滤，按作者数从多到少排列
+}
+
+// authorCountryMatcher 返回判断作者是否属于指定国家的函数
+// country可以是ISO 3166-1国家代码(不区分大小写，cxsecurity使用的 "UK" 等同于 "GB")或英文国家名称
+func authorCountryMatcher(country string) func(config.AuthorState) bool {
+	country = strings.TrimSpace(country)
+	if code, _ := crawler.CountryName(country, language.English); code != "" {
+		return func(s config.AuthorState) bool { return s.CountryCode == code }
+	}
+	return func(s config.AuthorState) bool { return strings.EqualFold(s.Country, country) }
+}
+
+// localAuthors 返回按ID排列的作者，country为空时不过滤
+func localAuthors(states []config.AuthorState, country string) localAuthorsResult {
+	result := localAuthorsResult{Authors: []localAuthor{}, Countries: []localCountry{}}
+	counts := make(map[string]*localCountry)
+	match := authorCountryMatcher(country)
+	for _, s := range states {
+		c, ok := counts[s.CountryCode]
+		if !ok {
+			c = &localCountry{CountryCode: s.CountryCode, Country: s.Country}
+			counts[s.CountryCode] = c
+		}
+		c.Authors++
+
+		if country != "" && !match(s) {
+			continue
+		}
+		result.Authors = append(result.Authors, localAuthor{
+			ID:          s.ID,
+			Name:        s.Name,
+			Country:     s.Country,
+			CountryCode: s.CountryCode,
+			Seen:        len(s.Seen),
+			LastChecked: s.LastChecked,
+		})
+	}
+	result.Total = len(result.Authors)
+
+	for _, c := range counts {
+		result.Countries = append(result.Countries, *c)
+	}
+	sort.Slice(result.Countries, func(i, j int) bool {
+		a, b := result.Countries[i], result.Countries[j]
+		if a.Authors != b.Authors {
+			return a.Authors > b.Authors
+		}
+		return a.CountryCode < b.CountryCode
+	})
+	return result
+}
+
+/**
+ * @api {get} /api/local/authors 查询监控的作者
+ * @apiName GetLocalAuthors
+ * @apiGroup Local
+ * @apiVersion 1.0.0
+ *
+ * @apiHeader {String} X-API-Token API认证Token
+ *
+ * @apiParam {String} [country] 只返回该国家的作者，可以是ISO 3166-1国家代码(例如 PL、UK)或英文国家名称，不区分大小写
+ *
+ * @apiSuccess {Number} data.total 过滤后的作者数
+ * @apiSuccess {Object[]} data.authors 作者列表，按ID排列
+ * @apiSuccess {String} data.authors.country 国家英文名称
+ * @apiSuccess {String} data.authors.country_code ISO 3166-1国家代码
+ * @apiSuccess {Object[]} data.countries 所有作者按国家统计，不受country过滤
+ *
+ * @apiSuccessExample {json} 成功响应:
+ *     HTTP/1.1 200 OK
+ *     {
+ *       "success": true,
+ *       "data": {
+ *         "total": 1,
+ *         "authors": [
+ *           {"id": "m4xth0r", "name": "m4xth0r", "country": "Poland", "country_code": "PL", "seen": 35, "last_checked": "2024-05-01T08:00:00Z"}
+ *         ],
+ *         "countries": [
+ *           {"country_code": "PL", "country": "Poland", "authors": 1},
+ *           {"country_code": "US", "country": "United States", "authors": 1}
+ *         ]
+ *       }
+ *     }
+ *
+ * @apiExample {curl} 示例:
+ *     curl -H "X-API-Token: your-token" "http://localhost:8080/api/local/authors?country=PL"
+ */
+// handleLocalAuthors 返回 watch-author 保存在状态文件中的作者及其国家，不请求cxsecurity
+// 国家在每次检查作者时更新，升级前保存的作者在下一次检查后才有国家
+func handleLocalAuthors(statePath string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		authors, err := config.NewAuthorStore(statePath)
+		var states []config.AuthorState
+		if err == nil {
+			states, err = authors.List()
+		}
+		if err != nil {
+			encodeJSON(w, APIResponse{
+				Success: false,
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		meta := crawler.Freshness{CacheHit: true, Source: crawler.SourceStore}
+		if info, err := os.Stat(authors.Path()); err == nil {
+			meta.FetchedAt = info.ModTime()
+		}
+		encodeData(w, localAuthors(states, r.URL.Query().Get("country")), meta)
+	}
+}
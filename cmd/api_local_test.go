@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/config"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/store"
+)
+
+func TestLocalAuthorVulnerabilities(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vulnerabilities.json")
+	s, err := store.Open(path)
+	require.NoError(t, err)
+	for i := 1; i <= 5; i++ {
+		risk := "Low"
+		if i%2 == 1 {
+			risk = "High"
+		}
+		s.Put(model.Vulnerability{
+			ID:        fmt.Sprintf("WLB-%d", i),
+			Date:      time.Date(2024, 4, i, 0, 0, 0, 0, time.UTC),
+			Author:    "hyp3rlinx",
+			AuthorURL: "https://cxsecurity.com/author/hyp3rlinx/1/",
+			RiskLevel: risk,
+		})
+	}
+	s.Put(model.Vulnerability{ID: "WLB-9", Author: "rgod", AuthorURL: "https://cxsecurity.com/author/rgod/1/"})
+	require.NoError(t, s.Save())
+
+	result := localAuthorVulnerabilities(s, "HYP3RLINX", nil, false, false, 2, 2)
+	assert.Equal(t, "hyp3rlinx", result.AuthorName)
+	assert.Equal(t, 5, result.Total)
+	assert.Equal(t, 3, result.TotalPages)
+	require.Len(t, result.Vulnerabilities, 2)
+	assert.Equal(t, "WLB-3", result.Vulnerabilities[0].ID)
+
+	result = localAuthorVulnerabilities(s, "hyp3rlinx", []string{"High"}, false, false, 1, 0)
+	assert.Equal(t, 3, result.Total)
+	assert.Equal(t, 1, result.TotalPages)
+	assert.Len(t, result.Vulnerabilities, 3)
+
+	result = localAuthorVulnerabilities(s, "hyp3rlinx", nil, false, false, 9, 2)
+	assert.NotNil(t, result.Vulnerabilities)
+	assert.Empty(t, result.Vulnerabilities)
+
+	// HTTP接口每次请求时读取存储文件
+	handler := handleLocalAuthor(path)
+	req := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/api/local/authors/hyp3rlinx/vulnerabilities?risk=low&per_page=1", nil), map[string]string{"id": "hyp3rlinx"})
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	var resp struct {
+		Success bool              `json:"success"`
+		Data    localAuthorResult `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.True(t, resp.Success)
+	assert.Equal(t, 2, resp.Data.Total)
+	assert.Equal(t, 2, resp.Data.TotalPages)
+	require.Len(t, resp.Data.Vulnerabilities, 1)
+	assert.Equal(t, "WLB-4", resp.Data.Vulnerabilities[0].ID)
+
+	req = mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/api/local/authors/rgod/vulnerabilities?risk=critical", nil), map[string]string{"id": "rgod"})
+	rec = httptest.NewRecorder()
+	handler(rec, req)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.False(t, resp.Success)
+}
+
+func TestLocalAuthors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "authors.json")
+	authors, err := config.NewAuthorStore(path)
+	require.NoError(t, err)
+	now := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	for _, p := range []model.AuthorProfile{
+		{ID: "m4xth0r", Country: "Poland", CountryCode: "PL", Vulnerabilities: []model.Vulnerability{{ID: "WLB-1"}, {ID: "WLB-2"}}},
+		{ID: "hyp3rlinx", Country: "United States", CountryCode: "US"},
+		{ID: "rgod", Country: "United Kingdom", CountryCode: "GB"},
+		{ID: "indoushka", Country: "Poland", CountryCode: "PL"},
+		{ID: "anonymous"},
+	} {
+		_, _, err := authors.Update(&p, now)
+		require.NoError(t, err)
+	}
+	states, err := authors.List()
+	require.NoError(t, err)
+
+	result := localAuthors(states, "")
+	assert.Equal(t, 5, result.Total)
+	assert.Equal(t, []localCountry{
+		{CountryCode: "PL", Country: "Poland", Authors: 2},
+		{CountryCode: "", Authors: 1},
+		{CountryCode: "GB", Country: "United Kingdom", Authors: 1},
+		{CountryCode: "US", Country: "United States", Authors: 1},
+	}, result.Countries)
+
+	result = localAuthors(states, "pl")
+	require.Equal(t, 2, result.Total)
+	assert.Equal(t, localAuthor{ID: "indoushka", Country: "Poland", CountryCode: "PL", LastChecked: now}, result.Authors[0])
+	assert.Equal(t, 2, result.Authors[1].Seen)
+	assert.Len(t, result.Countries, 4, "统计不受过滤影响")
+
+	for _, country := range []string{"UK", "united kingdom"} {
+		result = localAuthors(states, country)
+		require.Equal(t, 1, result.Total, country)
+		assert.Equal(t, "rgod", result.Authors[0].ID)
+	}
+	result = localAuthors(states, "XX")
+	assert.Equal(t, 0, result.Total)
+	assert.NotNil(t, result.Authors)
+
+	// HTTP接口每次请求时读取状态文件
+	rec := httptest.NewRecorder()
+	handleLocalAuthors(path)(rec, httptest.NewRequest(http.MethodGet, "/api/local/authors?country=US", nil))
+	var resp struct {
+		Success bool               `json:"success"`
+		Data    localAuthorsResult `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.True(t, resp.Success)
+	require.Len(t, resp.Data.Authors, 1)
+	assert.Equal(t, "United States", resp.Data.Authors[0].Country)
+}
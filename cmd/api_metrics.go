@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/export"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/logging"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+var (
+	apiMetricsData []string
+	apiMetricsDays int
+)
+
+// requestCounter 按接口统计API服务处理的请求数
+type requestCounter struct {
+	mu     sync.Mutex
+	counts map[string]uint64
+}
+
+// apiRequests 是API服务的请求计数，作为counter类型的指标输出
+var apiRequests = &requestCounter{counts: make(map[string]uint64)}
+
+// middleware 在请求处理完成后为endpoint计数，OPTIONS预检请求不计数
+func (c *requestCounter) middleware(endpoint string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		next(w, r)
+		if r.Method == http.MethodOptions {
+			return
+		}
+		c.mu.Lock()
+		c.counts[endpoint]++
+		c.mu.Unlock()
+	}
+}
+
+// snapshot 返回按接口排序的计数
+func (c *requestCounter) snapshot() ([]string, map[string]uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	counts := make(map[string]uint64, len(c.counts))
+	endpoints := make([]string, 0, len(c.counts))
+	for endpoint, n := range c.counts {
+		counts[endpoint] = n
+		endpoints = append(endpoints, endpoint)
+	}
+	sort.Strings(endpoints)
+	return endpoints, counts
+}
+
+// loadMetricsData 读取 --metrics-data 指定的结果文件，支持通配符
+// 每次抓取指标时重新读取，定时任务更新结果文件后不需要重启服务；无法读取的文件会被跳过并记录日志
+func loadMetricsData(patterns []string) []model.Vulnerability {
+	var items []model.Vulnerability
+	for _, pattern := range patterns {
+		paths, err := filepath.Glob(pattern)
+		if err != nil {
+			logging.Printf(T("无效的文件模式 %s: %v\n"), pattern, err)
+			continue
+		}
+		for _, path := range paths {
+			loaded, err := crawler.LoadResultFile(path)
+			if err != nil {
+				logging.Printf("%v\n", err)
+				continue
+			}
+			items = append(items, loaded...)
+		}
+	}
+	return items
+}
+
+// handleMetrics 以Prometheus文本格式输出公告统计和API请求计数
+func handleMetrics() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+		items := loadMetricsData(apiMetricsData)
+		if err := export.WritePrometheus(w, items, export.MetricsOptions{Days: apiMetricsDays}); err != nil {
+			logging.Printf(T("输出指标失败: %v\n"), err)
+			return
+		}
+
+		endpoints, counts := apiRequests.snapshot()
+		fmt.Fprint(w, "# HELP cxsecurity_api_requests_total Number of API requests handled by endpoint.\n")
+		fmt.Fprint(w, "# TYPE cxsecurity_api_requests_total counter\n")
+		for _, endpoint := range endpoints {
+			fmt.Fprintf(w, "cxsecurity_api_requests_total{endpoint=%q} %d\n", endpoint, counts[endpoint])
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleMetrics(t *testing.T) {
+	dir := t.TempDir()
+	today := time.Now().Format("2006-01-02")
+	data := `[{"id":"WLB-1","risk_level":"High","date":"` + today + `T00:00:00Z","is_remote":true}]`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.json"), []byte(data), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{"), 0600))
+
+	oldData, oldDays := apiMetricsData, apiMetricsDays
+	apiMetricsData, apiMetricsDays = []string{filepath.Join(dir, "*.json")}, 7
+	defer func() { apiMetricsData, apiMetricsDays = oldData, oldDays }()
+
+	ok := func(w http.ResponseWriter, r *http.Request) {}
+	apiRequests.middleware("/api/test", ok)(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/test", nil))
+	apiRequests.middleware("/api/test", ok)(httptest.NewRecorder(), httptest.NewRequest(http.MethodOptions, "/api/test", nil))
+
+	rec := httptest.NewRecorder()
+	handleMetrics()(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rec.Body.String()
+
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/plain")
+	assert.Contains(t, body, `cxsecurity_advisories{risk="High"} 1`)
+	assert.Contains(t, body, `cxsecurity_advisories_daily{date="`+today+`",risk="High"} 1`)
+	assert.Contains(t, body, `cxsecurity_advisories_by_tag{tag="remote"} 1`)
+	assert.Contains(t, body, "# TYPE cxsecurity_api_requests_total counter")
+	assert.Contains(t, body, `cxsecurity_api_requests_total{endpoint="/api/test"} 1`)
+}
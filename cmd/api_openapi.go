@@ -0,0 +1,466 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/schema"
+)
+
+// openAPIVersion 是 /api/v1 接口的版本号，与apidoc注释中的 @apiVersion 相同
+// 生成的客户端使用发布的版本号，不使用这个版本
+const openAPIVersion = "1.0.0"
+
+var openAPIOutput string
+
+// apiParam 是接口的一个参数，出现在路径中的参数为路径参数，其他为查询参数
+type apiParam struct {
+	name     string
+	typ      string // string、integer 或 boolean
+	desc     string
+	required bool
+	def      any      // 默认值，nil表示没有
+	enum     []string // 可选值
+}
+
+// apiOperation 描述一个 /api/v1 接口，用于生成OpenAPI文档
+type apiOperation struct {
+	id      string // operationId，生成的客户端用作方法名
+	tag     string
+	summary string
+	params  []apiParam
+	data    string // 成功响应中data字段的Schema名称，为空时响应不是JSON
+	media   string // 响应不是JSON时的内容类型
+	timeout bool   // 是否请求cxsecurity，受 --list-timeout 等上游超时限制
+}
+
+// apiOperations 是 apiRoutes 中每个接口的说明，键为不含 /api 和版本前缀的路径
+// 新增接口时需要同时在这里添加说明，TestOpenAPICoversRoutes 会检查两者一致
+var apiOperations = map[string]apiOperation{
+	"/exploit": {
+		id: "getExploitList", tag: "Exploit", summary: "获取漏洞列表",
+		params: []apiParam{
+			{name: "section", typ: "string", desc: "列表栏目", def: "exploit", enum: []string{"exploit", "wlb"}},
+			{name: "page", typ: "integer", desc: "页码", def: 1},
+		},
+		data:    "VulnerabilityList",
+		timeout: true,
+	},
+	"/exploit/latest": {
+		id: "getExploitLatest", tag: "Exploit", summary: "增量获取新漏洞",
+		params: []apiParam{
+			{name: "since", typ: "string", desc: "上次调用返回的cursor，只返回比它新的漏洞；为空时返回最新的limit条"},
+			{name: "limit", typ: "integer", desc: "最多返回的记录数，最多100", def: crawler.DefaultLatestLimit},
+			{name: "section", typ: "string", desc: "列表栏目", def: "exploit", enum: []string{"exploit", "wlb"}},
+		},
+		data:    "LatestResult",
+		timeout: true,
+	},
+	"/exploit/{id}": {
+		id: "getExploit", tag: "Exploit", summary: "获取漏洞详情",
+		params: []apiParam{
+			{name: "id", typ: "string", desc: "漏洞ID(WLB-XXXXXXXX格式,不带WLB-前缀也可以)", required: true},
+		},
+		data:    "Vulnerability",
+		timeout: true,
+	},
+	"/cve/{id}": {
+		id: "getCveDetail", tag: "CVE", summary: "获取CVE详情",
+		params: []apiParam{
+			{name: "id", typ: "string", desc: "CVE编号(CVE-YYYY-XXXXX格式)", required: true},
+		},
+		data:    "CveDetail",
+		timeout: true,
+	},
+	"/author/{id}": {
+		id: "getAuthorProfile", tag: "Author", summary: "获取作者信息",
+		params: []apiParam{
+			{name: "id", typ: "string", desc: "作者ID", required: true},
+			{name: "proxy_assets", typ: "boolean", desc: "为true时头像和国旗图片地址改为通过 /api/v1/assets 加载"},
+		},
+		data:    "AuthorProfile",
+		timeout: true,
+	},
+	"/author/{id}/feed": {
+		id: "getAuthorFeed", tag: "Author", summary: "作者新发布的RSS订阅",
+		params: []apiParam{
+			{name: "id", typ: "string", desc: "作者ID", required: true},
+		},
+		media:   "application/rss+xml",
+		timeout: true,
+	},
+	"/search": {
+		id: "searchVulnerabilities", tag: "Search", summary: "搜索漏洞",
+		params: []apiParam{
+			{name: "keyword", typ: "string", desc: "搜索关键词", required: true},
+			{name: "page", typ: "integer", desc: "页码", def: 1},
+			{name: "per_page", typ: "integer", desc: "每页记录数(10或30)", def: 10},
+			{name: "sort_order", typ: "string", desc: "排序顺序", def: "DESC", enum: []string{"ASC", "DESC", "RELEVANCE"}},
+			{name: "risk", typ: "string", desc: "只返回指定风险级别的漏洞(High、Med、Low)，多个用逗号分隔"},
+			{name: "remote", typ: "boolean", desc: "只返回可远程利用的漏洞"},
+			{name: "local", typ: "boolean", desc: "只返回本地利用的漏洞"},
+			{name: "after", typ: "string", desc: "只搜索该日期及之后发布的漏洞(YYYY-MM-DD)"},
+			{name: "before", typ: "string", desc: "只搜索该日期及之前发布的漏洞(YYYY-MM-DD)"},
+		},
+		data:    "SearchResult",
+		timeout: true,
+	},
+	"/assets": {
+		id: "getAsset", tag: "Author", summary: "代理作者头像和国旗图片",
+		params: []apiParam{
+			{name: "url", typ: "string", desc: "图片地址，只允许cxsecurity和Gravatar上的图片", required: true},
+		},
+		media: "image/*",
+	},
+	"/local/authors": {
+		id: "getLocalAuthors", tag: "Local", summary: "查询监控的作者",
+		params: []apiParam{
+			{name: "country", typ: "string", desc: "只返回该国家的作者，可以是ISO 3166-1国家代码(例如 PL、UK)或英文国家名称，不区分大小写"},
+		},
+		data: "LocalAuthorsResult",
+	},
+	"/local/authors/{id}/vulnerabilities": {
+		id: "getLocalAuthorVulnerabilities", tag: "Local", summary: "查询存储中作者的漏洞",
+		params: []apiParam{
+			{name: "id", typ: "string", desc: "作者ID，即作者页面URL中的名称，不区分大小写", required: true},
+			{name: "page", typ: "integer", desc: "页码", def: 1},
+			{name: "per_page", typ: "integer", desc: "每页记录数，最多500", def: localDefaultPerPage},
+			{name: "risk", typ: "string", desc: "只返回指定风险级别的漏洞(High、Med、Low)，多个用逗号分隔"},
+			{name: "remote", typ: "boolean", desc: "只返回可远程利用的漏洞"},
+			{name: "local", typ: "boolean", desc: "只返回本地利用的漏洞"},
+		},
+		data: "LocalAuthorResult",
+	},
+	"/status": {
+		id: "getStatus", tag: "Status", summary: "获取爬虫的请求和限速状态",
+		data: "Status",
+	},
+}
+
+// apiSchemaTypes 是只在API中使用、没有 pkg/schema 文档的响应类型，Schema由字段的json标签生成
+var apiSchemaTypes = map[string]reflect.Type{
+	"LatestResult":       reflect.TypeOf(crawler.LatestResult{}),
+	"LocalAuthorResult":  reflect.TypeOf(localAuthorResult{}),
+	"LocalAuthorsResult": reflect.TypeOf(localAuthorsResult{}),
+	"LocalAuthor":        reflect.TypeOf(localAuthor{}),
+	"LocalCountry":       reflect.TypeOf(localCountry{}),
+	"Status":             reflect.TypeOf(apiStatus{}),
+	"ThrottleStatus":     reflect.TypeOf(apiThrottleStatus{}),
+}
+
+// openAPIDocument 生成 /api/v1 接口的OpenAPI 3.0文档
+// 输出文件的Schema来自 pkg/schema，接口说明来自 apiOperations，用于生成其他语言的客户端
+func openAPIDocument() (map[string]any, error) {
+	schemas, err := openAPISchemas()
+	if err != nil {
+		return nil, err
+	}
+	schemas["Freshness"] = map[string]any{
+		"type":     "object",
+		"required": []string{"cache_hit", "source"},
+		"properties": map[string]any{
+			"fetched_at": map[string]any{"type": "string", "format": "date-time", "description": "从网站获取的时间，本地存储中没有获取时间的记录时省略"},
+			"cache_hit":  map[string]any{"type": "boolean", "description": "是否没有请求网站，直接使用了缓存或本地存储"},
+			"source": map[string]any{"type": "string", "description": "结果的来源",
+				"enum": []string{string(crawler.SourceLive), string(crawler.SourceCache), string(crawler.SourceStore)}},
+		},
+	}
+	schemas["ErrorResponse"] = map[string]any{
+		"type":     "object",
+		"required": []string{"success", "error"},
+		"properties": map[string]any{
+			"success": map[string]any{"type": "boolean", "description": "始终为false"},
+			"error":   map[string]any{"type": "string", "description": "错误信息"},
+		},
+	}
+
+	paths := make(map[string]any, len(apiOperations))
+	for path, op := range apiOperations {
+		var params []any
+		for _, p := range op.params {
+			in := "query"
+			if strings.Contains(path, "{"+p.name+"}") {
+				in = "path"
+			}
+			s := map[string]any{"type": p.typ}
+			if p.def != nil {
+				s["default"] = p.def
+			}
+			if p.enum != nil {
+				s["enum"] = p.enum
+			}
+			param := map[string]any{"name": p.name, "in": in, "description": p.desc, "schema": s}
+			if p.required {
+				param["required"] = true
+			}
+			params = append(params, param)
+		}
+
+		ok := map[string]any{"description": op.summary}
+		if op.data != "" {
+			name := op.data + "Response"
+			schemas[name] = map[string]any{
+				"type":     "object",
+				"required": []string{"success"},
+				"properties": map[string]any{
+					"success": map[string]any{"type": "boolean", "description": "是否成功，失败时error为错误信息"},
+					"data":    map[string]any{"$ref": "#/components/schemas/" + op.data},
+					"error":   map[string]any{"type": "string", "description": "错误信息"},
+					"meta":    map[string]any{"$ref": "#/components/schemas/Freshness"},
+				},
+			}
+			ok["content"] = map[string]any{"application/json": map[string]any{"schema": map[string]any{"$ref": "#/components/schemas/" + name}}}
+		} else {
+			ok["content"] = map[string]any{op.media: map[string]any{"schema": map[string]any{"type": "string", "format": "binary"}}}
+		}
+
+		operation := map[string]any{
+			"operationId": op.id,
+			"tags":        []string{op.tag},
+			"summary":     op.summary,
+			"responses": map[string]any{
+				"200": ok,
+				"401": map[string]any{
+					"description": "无效的API Token",
+					"content":     map[string]any{"application/json": map[string]any{"schema": map[string]any{"$ref": "#/components/schemas/ErrorResponse"}}},
+				},
+			},
+		}
+		if op.timeout {
+			operation["responses"].(map[string]any)["504"] = map[string]any{
+				"description": "等待cxsecurity响应超时",
+				"content":     map[string]any{"application/json": map[string]any{"schema": map[string]any{"$ref": "#/components/schemas/ErrorResponse"}}},
+			}
+		}
+		if params != nil {
+			operation["parameters"] = params
+		}
+		paths[path] = map[string]any{"get": operation}
+	}
+
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":       "cxsecurity-crawler API",
+			"description": "cxcrawler api 提供的HTTP API。每个请求需要通过 X-API-Token 请求头或 token 参数认证。",
+			"version":     openAPIVersion,
+		},
+		"servers": []any{map[string]any{"url": "http://localhost:8080/api/" + currentAPIVersion}},
+		"security": []any{
+			map[string]any{"tokenHeader": []string{}},
+			map[string]any{"tokenQuery": []string{}},
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": schemas,
+			"securitySchemes": map[string]any{
+				"tokenHeader": map[string]any{"type": "apiKey", "in": "header", "name": "X-API-Token"},
+				"tokenQuery":  map[string]any{"type": "apiKey", "in": "query", "name": "token"},
+			},
+		},
+	}, nil
+}
+
+// openAPISchemas 将 pkg/schema 的文档和 apiSchemaTypes 转换为OpenAPI的组件Schema
+// 文档中的 $defs 提升为独立的组件，名称为 <文档标题><定义名>，例如 VulnerabilityAiSummary
+func openAPISchemas() (map[string]any, error) {
+	titles := make(map[string]string)
+	docs := make(map[string]map[string]any)
+	for _, name := range schema.Names() {
+		data, err := schema.Get(name)
+		if err != nil {
+			return nil, err
+		}
+		var doc map[string]any
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("解析Schema %s 失败: %w", name, err)
+		}
+		title, _ := doc["title"].(string)
+		titles[name+".json"] = title
+		docs[title] = doc
+	}
+
+	schemas := make(map[string]any)
+	for title, doc := range docs {
+		defs, _ := doc["$defs"].(map[string]any)
+		delete(doc, "$schema")
+		delete(doc, "$id")
+		delete(doc, "$defs")
+		ref := func(r string) string {
+			if def, ok := strings.CutPrefix(r, "#/$defs/"); ok {
+				return "#/components/schemas/" + title + strings.ToUpper(def[:1]) + def[1:]
+			}
+			return "#/components/schemas/" + titles[r]
+		}
+		schemas[title] = toOpenAPISchema(doc, ref)
+		for def, s := range defs {
+			schemas[title+strings.ToUpper(def[:1])+def[1:]] = toOpenAPISchema(s, ref)
+		}
+	}
+
+	refs := map[reflect.Type]string{reflect.TypeOf(crawler.Freshness{}): "Freshness"}
+	for t, title := range schemaModelTypes {
+		refs[t] = title
+	}
+	for name, t := range apiSchemaTypes {
+		refs[t] = name
+	}
+	for name, t := range apiSchemaTypes {
+		schemas[name] = reflectSchema(t, refs, true)
+	}
+	return schemas, nil
+}
+
+// toOpenAPISchema 将JSON Schema(draft 2020-12)转换为OpenAPI 3.0的Schema
+// 改写 $ref，并将 "type": ["array", "null"] 转换为 "type": "array" 和 "nullable": true
+func toOpenAPISchema(v any, ref func(string) string) any {
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, val := range v {
+			switch {
+			case k == "$ref":
+				out[k] = ref(val.(string))
+			case k == "type":
+				types, ok := val.([]any)
+				if !ok {
+					out[k] = val
+					continue
+				}
+				for _, t := range types {
+					if t == "null" {
+						out["nullable"] = true
+					} else {
+						out[k] = t
+					}
+				}
+			case k == "properties":
+				// 属性名不是Schema关键字，只转换属性的值
+				props := make(map[string]any)
+				for name, s := range val.(map[string]any) {
+					props[name] = toOpenAPISchema(s, ref)
+				}
+				out[k] = props
+			default:
+				out[k] = toOpenAPISchema(val, ref)
+			}
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, val := range v {
+			out[i] = toOpenAPISchema(val, ref)
+		}
+		return out
+	}
+	return v
+}
+
+// schemaModelTypes 是 pkg/schema 文档对应的Go类型，生成的Schema中引用这些类型时使用文档的组件
+var schemaModelTypes = map[reflect.Type]string{
+	reflect.TypeOf(model.Vulnerability{}):       "Vulnerability",
+	reflect.TypeOf(model.VulnerabilityList{}):   "VulnerabilityList",
+	reflect.TypeOf(model.CveDetail{}):           "CveDetail",
+	reflect.TypeOf(model.AuthorProfile{}):       "AuthorProfile",
+	reflect.TypeOf(crawler.SearchResult{}):      "SearchResult",
+	reflect.TypeOf(crawler.MultiSearchResult{}): "MultiSearchResult",
+}
+
+// reflectSchema 根据字段的json标签生成Schema，没有omitempty的字段为必填字段
+// refs中的类型引用对应的组件；top为false时结构体类型也必须在refs中
+func reflectSchema(t reflect.Type, refs map[reflect.Type]string, top bool) map[string]any {
+	if name, ok := refs[t]; ok && !top {
+		return map[string]any{"$ref": "#/components/schemas/" + name}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return reflectSchema(t.Elem(), refs, false)
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": reflectSchema(t.Elem(), refs, false)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": reflectSchema(t.Elem(), refs, false)}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int64, reflect.Int32:
+		return map[string]any{"type": "integer"}
+	case reflect.Float64, reflect.Float32:
+		return map[string]any{"type": "number"}
+	case reflect.Struct:
+		if t == reflect.TypeOf(time.Time{}) {
+			return map[string]any{"type": "string", "format": "date-time"}
+		}
+	default:
+		panic(fmt.Sprintf("OpenAPI文档不支持的类型: %s", t))
+	}
+
+	props := make(map[string]any)
+	var required []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		name, opts, _ := strings.Cut(tag, ",")
+		if !f.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		s := reflectSchema(f.Type, refs, false)
+		if opts != "omitempty" {
+			required = append(required, name)
+			if k := f.Type.Kind(); k == reflect.Slice || k == reflect.Map || k == reflect.Pointer {
+				if _, isRef := s["$ref"]; isRef {
+					s = map[string]any{"allOf": []any{s}, "nullable": true}
+				} else {
+					s["nullable"] = true
+				}
+			}
+		}
+		props[name] = s
+	}
+	out := map[string]any{"type": "object", "properties": props}
+	if required != nil {
+		out["required"] = required
+	}
+	return out
+}
+
+var apiOpenAPICmd = &cobra.Command{
+	Use:   "openapi",
+	Short: T("输出API的OpenAPI文档"),
+	Long: T(`输出 /api/v1 接口的OpenAPI 3.0文档(JSON格式)，可以用 openapi-generator 等工具生成其他语言的客户端。
+仓库中的 docs/openapi.json 由这个命令生成，make clients 使用它生成TypeScript和Python客户端。`),
+	Example: `  cxcrawler api openapi -o docs/openapi.json`,
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		doc, err := openAPIDocument()
+		if err != nil {
+			return err
+		}
+		data, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return err
+		}
+		data = append(data, '\n')
+		if openAPIOutput == "" {
+			_, err = os.Stdout.Write(data)
+			return err
+		}
+		return os.WriteFile(openAPIOutput, data, 0644)
+	},
+}
+
+func init() {
+	apiCmd.AddCommand(apiOpenAPICmd)
+	apiOpenAPICmd.Flags().StringVarP(&openAPIOutput, "output", "o", "", T("输出文件，默认输出到标准输出"))
+}
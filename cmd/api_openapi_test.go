@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAPICoversRoutes(t *testing.T) {
+	routes := apiRoutes(nil, nil, nil)
+	paths := make(map[string]bool)
+	for _, route := range routes {
+		paths[route.path] = true
+		op, ok := apiOperations[route.path]
+		if assert.True(t, ok, "接口 %s 没有OpenAPI说明", route.path) {
+			for _, p := range op.params {
+				assert.NotEmpty(t, p.desc, "%s 的参数 %s 没有说明", route.path, p.name)
+			}
+		}
+	}
+	for path := range apiOperations {
+		assert.True(t, paths[path], "OpenAPI说明中的接口 %s 不存在", path)
+	}
+}
+
+func TestOpenAPIDocumentRefs(t *testing.T) {
+	doc, err := openAPIDocument()
+	require.NoError(t, err)
+	data, err := json.Marshal(doc)
+	require.NoError(t, err)
+
+	var parsed map[string]any
+	require.NoError(t, json.Unmarshal(data, &parsed))
+	schemas := parsed["components"].(map[string]any)["schemas"].(map[string]any)
+
+	// 所有引用都必须指向存在的组件，转换后不能留下JSON Schema特有的写法
+	var walk func(v any)
+	walk = func(v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			if ref, ok := v["$ref"].(string); ok {
+				name, found := strings.CutPrefix(ref, "#/components/schemas/")
+				if assert.True(t, found, "无效的引用 %s", ref) {
+					assert.Contains(t, schemas, name)
+				}
+			}
+			// OpenAPI 3.0不支持类型数组，名为type的属性是对象，不受影响
+			if typ, ok := v["type"].([]any); ok {
+				assert.Fail(t, "type 应为字符串", "%v", typ)
+			}
+			assert.NotContains(t, v, "$defs")
+			assert.NotContains(t, v, "$schema")
+			for _, val := range v {
+				walk(val)
+			}
+		case []any:
+			for _, val := range v {
+				walk(val)
+			}
+		}
+	}
+	walk(parsed)
+
+	paths := parsed["paths"].(map[string]any)
+	assert.Len(t, paths, len(apiOperations))
+	get := paths["/exploit/{id}"].(map[string]any)["get"].(map[string]any)
+	assert.Equal(t, "getExploit", get["operationId"])
+	params := get["parameters"].([]any)
+	require.Len(t, params, 1)
+	assert.Equal(t, "path", params[0].(map[string]any)["in"])
+	assert.Equal(t, true, params[0].(map[string]any)["required"])
+
+	vuln := schemas["Vulnerability"].(map[string]any)
+	ai := vuln["properties"].(map[string]any)["ai_summary"].(map[string]any)
+	assert.Equal(t, "#/components/schemas/VulnerabilityAiSummary", ai["$ref"])
+}
+
+func TestOpenAPIDocumentUpToDate(t *testing.T) {
+	doc, err := openAPIDocument()
+	require.NoError(t, err)
+	data, err := json.MarshalIndent(doc, "", "  ")
+	require.NoError(t, err)
+
+	committed, err := os.ReadFile("../docs/openapi.json")
+	require.NoError(t, err)
+	assert.Equal(t, string(committed), string(data)+"\n", "docs/openapi.json 已过期，请运行 make openapi")
+}
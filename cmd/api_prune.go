@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/config"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/logging"
+)
+
+var (
+	apiPruneDirs     []string
+	apiPruneInterval time.Duration
+)
+
+// pruneOnce 按保存的保留策略清理一次 --prune-dir 指定的目录
+// 每次都重新读取策略，用 prune --save 修改策略后不需要重启服务；失败只记录日志，不影响API服务
+func pruneOnce(dirs []string, now time.Time) {
+	policy, err := config.LoadRetention("")
+	if err != nil {
+		logging.Printf(T("读取保留策略失败，跳过本次清理: %v")+"\n", err)
+		return
+	}
+	for _, dir := range dirs {
+		report, err := crawler.Prune(dir, policy, now, false)
+		if err != nil {
+			logging.Printf(T("清理 %s 失败: %v")+"\n", dir, err)
+			continue
+		}
+		if len(report.Removed) > 0 {
+			logging.Printf(T("%s: 已删除 %d 个文件 (%d 字节)，保留 %d 个文件")+"\n", dir, len(report.Removed), report.RemovedBytes, report.Kept)
+		}
+	}
+}
+
+// startPruning 在后台按 interval 定期清理目录，启动时立即清理一次
+func startPruning(dirs []string, interval time.Duration) {
+	if len(dirs) == 0 || interval <= 0 {
+		return
+	}
+	go func() {
+		pruneOnce(dirs, time.Now())
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for now := range ticker.C {
+			pruneOnce(dirs, now)
+		}
+	}()
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+)
+
+// apiStatus 是 /api/status 返回的爬虫请求和限速状态
+type apiStatus struct {
+	Requests       int                `json:"requests"`                // 已发出的请求数，包括重试
+	Failures       int                `json:"failures"`                // 失败的请求数
+	Budget         int                `json:"budget"`                  // 请求预算，0表示不限制
+	Remaining      int                `json:"remaining"`               // 剩余的请求数，-1表示不限制
+	RateLimit      float64            `json:"rate_limit"`              // 固定限速(请求/秒)，0表示不限速
+	BackoffUntil   *time.Time         `json:"backoff_until,omitempty"` // 下一个请求需要等到的时间
+	BackoffSeconds float64            `json:"backoff_seconds"`         // 距离下一个请求还需等待的秒数
+	Throttle       *apiThrottleStatus `json:"throttle,omitempty"`      // 自适应限速的状态，未启用时省略
+	BaseURL        string             `json:"base_url,omitempty"`      // 当前请求使用的站点，切换到镜像后为镜像地址
+	Failovers      int                `json:"failovers"`               // 切换站点的次数
+	Coalesced      int                `json:"coalesced"`               // 与相同的并发请求合并、没有单独请求网站的次数
+}
+
+// apiThrottleStatus 是自适应限速器的状态
+type apiThrottleStatus struct {
+	DelayMs      int64      `json:"delay_ms"`
+	Rate         float64    `json:"rate"`
+	Paused       bool       `json:"paused"`
+	PausedUntil  *time.Time `json:"paused_until,omitempty"`
+	Throttled    int        `json:"throttled"`
+	Spikes       int        `json:"spikes"`
+	Errors       int        `json:"errors"`
+	AvgLatencyMs int64      `json:"avg_latency_ms"`
+}
+
+// newAPIStatus 将客户端状态转换为接口返回的格式
+func newAPIStatus(stats crawler.ClientStats, now time.Time) apiStatus {
+	status := apiStatus{
+		Requests:  stats.Requests,
+		Failures:  stats.Failures,
+		Budget:    stats.Budget,
+		Remaining: stats.Remaining,
+		RateLimit: stats.RateLimit,
+		BaseURL:   stats.BaseURL,
+		Failovers: stats.Failovers,
+		Coalesced: stats.Coalesced,
+	}
+	if !stats.BackoffUntil.IsZero() {
+		until := stats.BackoffUntil.UTC()
+		status.BackoffUntil = &until
+		status.BackoffSeconds = math.Max(until.Sub(now).Seconds(), 0)
+	}
+	if stats.Adaptive {
+		t := stats.Throttle
+		status.Throttle = &apiThrottleStatus{
+			DelayMs:      t.Delay.Milliseconds(),
+			Rate:         t.Rate,
+			Paused:       t.Paused,
+			Throttled:    t.Throttled,
+			Spikes:       t.Spikes,
+			Errors:       t.Errors,
+			AvgLatencyMs: t.AvgLatency.Milliseconds(),
+		}
+		if t.Paused {
+			until := t.PausedUntil.UTC()
+			status.Throttle.PausedUntil = &until
+		}
+	}
+	return status
+}
+
+/**
+ * @api {get} /api/status 获取爬虫的请求和限速状态
+ * @apiName GetStatus
+ * @apiGroup Status
+ * @apiVersion 1.0.0
+ *
+ * @apiHeader {String} X-API-Token API认证Token
+ *
+ * @apiSuccess {Number} data.requests 服务启动后已发出的请求数，包括重试
+ * @apiSuccess {Number} data.remaining 剩余的请求数，-1表示不限制
+ * @apiSuccess {String} [data.backoff_until] 受限速或暂停影响，下一个请求需要等到的时间
+ * @apiSuccess {Object} [data.throttle] 自适应限速的状态，未启用时省略
+ * @apiSuccess {String} data.base_url 当前请求使用的站点，--base-url 不可用而切换到 --mirror 后为镜像地址
+ * @apiSuccess {Number} data.failovers 切换站点的次数
+ * @apiSuccess {Number} data.coalesced 同一漏洞或CVE的并发请求合并为一次爬取时，没有单独请求网站的次数
+ *
+ * @apiSuccessExample {json} 成功响应:
+ *     HTTP/1.1 200 OK
+ *     {
+ *       "success": true,
+ *       "data": {
+ *         "requests": 42,
+ *         "failures": 3,
+ *         "budget": 500,
+ *         "remaining": 458,
+ *         "rate_limit": 0.5,
+ *         "backoff_until": "2024-04-10T08:00:02Z",
+ *         "backoff_seconds": 1.6,
+ *         "base_url": "https://cxsecurity.com",
+ *         "failovers": 0,
+ *         "coalesced": 7
+ *       }
+ *     }
+ *
+ * @apiExample {curl} 示例:
+ *     curl -H "X-API-Token: your-token" http://localhost:8080/api/status
+ */
+// handleStatus 返回爬虫HTTP客户端当前的请求数、剩余预算和限速状态，用于了解爬取变慢的原因
+func handleStatus(c *crawler.Crawler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		encodeJSON(w, APIResponse{
+			Success: true,
+			Data:    newAPIStatus(c.Stats(), time.Now()),
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+)
+
+func TestHandleStatus(t *testing.T) {
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<html></html>"))
+	}))
+	defer site.Close()
+
+	c := crawler.NewCrawler(crawler.WithClientOptions(
+		crawler.WithBaseURL(site.URL),
+		crawler.WithRequestBudget(10),
+		crawler.WithRateLimit(0.5),
+	))
+	_, err := c.CrawlPage("/exploit/1", "")
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	handleStatus(c)(rec, httptest.NewRequest(http.MethodGet, "/api/status", nil))
+	var resp struct {
+		Success bool      `json:"success"`
+		Data    apiStatus `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.True(t, resp.Success)
+	assert.Equal(t, 1, resp.Data.Requests)
+	assert.Equal(t, 9, resp.Data.Remaining)
+	assert.Equal(t, 0.5, resp.Data.RateLimit)
+	require.NotNil(t, resp.Data.BackoffUntil, "固定限速下应返回下一个请求的时间")
+	assert.Greater(t, resp.Data.BackoffSeconds, 0.0)
+	assert.Nil(t, resp.Data.Throttle, "未启用自适应限速时省略")
+}
+
+func TestNewAPIStatus(t *testing.T) {
+	now := time.Date(2024, 4, 10, 8, 0, 0, 0, time.UTC)
+	status := newAPIStatus(crawler.ClientStats{
+		Requests:     5,
+		Remaining:    -1,
+		BackoffUntil: now.Add(-time.Second),
+		Adaptive:     true,
+		Throttle: crawler.ThrottleStats{
+			Delay:       1500 * time.Millisecond,
+			Paused:      true,
+			PausedUntil: now.Add(time.Minute),
+			Throttled:   2,
+		},
+	}, now)
+
+	assert.Equal(t, 0.0, status.BackoffSeconds, "已经过去的时间不需要等待")
+	require.NotNil(t, status.Throttle)
+	assert.Equal(t, int64(1500), status.Throttle.DelayMs)
+	require.NotNil(t, status.Throttle.PausedUntil)
+	assert.Equal(t, now.Add(time.Minute), *status.Throttle.PausedUntil)
+	assert.Equal(t, 2, status.Throttle.Throttled)
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var (
+	// 各类接口等待上游网站的最长时间，0表示不限制
+	apiListTimeout   time.Duration
+	apiDetailTimeout time.Duration
+	apiSearchTimeout time.Duration
+)
+
+// apiEndpointTimeout 返回接口等待上游网站的最长时间，不请求网站的接口返回0
+// 详情页通常比列表页慢：CVE详情还会获取相关漏洞的分页，搜索可能需要多页才能凑够过滤后的结果。
+func apiEndpointTimeout(path string) time.Duration {
+	switch path {
+	case "/exploit", "/exploit/latest", "/author/{id}/feed":
+		return apiListTimeout
+	case "/exploit/{id}", "/cve/{id}", "/author/{id}":
+		return apiDetailTimeout
+	case "/search":
+		return apiSearchTimeout
+	}
+	return 0
+}
+
+// validateAPITimeouts 检查 --list-timeout、--detail-timeout 和 --search-timeout
+func validateAPITimeouts() error {
+	for flag, timeout := range map[string]time.Duration{"--list-timeout": apiListTimeout, "--detail-timeout": apiDetailTimeout, "--search-timeout": apiSearchTimeout} {
+		if timeout < 0 {
+			return fmt.Errorf(T("无效的 %s %s，应大于等于0"), flag, timeout)
+		}
+	}
+	return nil
+}
+
+// timeoutWriter 缓存处理函数的响应，超时后丢弃之后的写入
+type timeoutWriter struct {
+	mu       sync.Mutex
+	header   http.Header
+	status   int
+	body     bytes.Buffer
+	timedOut bool
+}
+
+func (t *timeoutWriter) Header() http.Header {
+	return t.header
+}
+
+func (t *timeoutWriter) WriteHeader(status int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.timedOut && t.status == 0 {
+		t.status = status
+	}
+}
+
+func (t *timeoutWriter) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	if t.status == 0 {
+		t.status = http.StatusOK
+	}
+	return t.body.Write(p)
+}
+
+// timeoutMiddleware 限制处理函数的执行时间，超时后返回504，不让客户端一直等待
+// 爬虫请求网站时不支持取消，超时的请求会在后台继续执行到结束，结果被丢弃；
+// 请求的context在超时后取消，支持context的代码可以提前结束。
+//
+// 参数:
+//   - timeout: 最长执行时间，不大于0时不限制
+//   - next: 下一个要执行的处理函数
+//
+// 返回值:
+//   - http.HandlerFunc: 包装后的处理函数
+func timeoutMiddleware(timeout time.Duration, next http.HandlerFunc) http.HandlerFunc {
+	if timeout <= 0 {
+		return next
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		tw := &timeoutWriter{header: make(http.Header)}
+		done := make(chan struct{})
+		panicked := make(chan any, 1)
+		go func() {
+			defer func() {
+				if p := recover(); p != nil {
+					panicked <- p
+				}
+			}()
+			next.ServeHTTP(tw, r.WithContext(ctx))
+			close(done)
+		}()
+
+		select {
+		case p := <-panicked:
+			panic(p)
+		case <-done:
+			tw.mu.Lock()
+			defer tw.mu.Unlock()
+			for k, v := range tw.header {
+				w.Header()[k] = v
+			}
+			if tw.status == 0 {
+				tw.status = http.StatusOK
+			}
+			w.WriteHeader(tw.status)
+			w.Write(tw.body.Bytes())
+		case <-ctx.Done():
+			tw.mu.Lock()
+			tw.timedOut = true
+			tw.mu.Unlock()
+			// 客户端已经断开时不需要响应
+			if r.Context().Err() != nil {
+				return
+			}
+			w.WriteHeader(http.StatusGatewayTimeout)
+			encodeJSON(w, APIResponse{
+				Success: false,
+				Error:   fmt.Sprintf(T("等待cxsecurity响应超时(%s)，请稍后重试"), timeout),
+			})
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+)
+
+func TestTimeoutMiddleware(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	slow := timeoutMiddleware(20*time.Millisecond, func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		encodeJSON(w, APIResponse{Success: true, Data: "late"})
+	})
+	rec := httptest.NewRecorder()
+	slow(rec, httptest.NewRequest(http.MethodGet, "/api/v1/cve/CVE-2024-0001", nil))
+	assert.Equal(t, http.StatusGatewayTimeout, rec.Code)
+	var resp APIResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.False(t, resp.Success)
+	assert.Contains(t, resp.Error, "20ms")
+
+	// 按时完成的响应原样返回，包括处理函数设置的响应头和状态码
+	fast := timeoutMiddleware(time.Second, func(w http.ResponseWriter, r *http.Request) {
+		_, ok := r.Context().Deadline()
+		assert.True(t, ok, "请求的context应带有截止时间")
+		w.Header().Set("X-Cache", "HIT")
+		w.WriteHeader(http.StatusNotModified)
+	})
+	rec = httptest.NewRecorder()
+	fast(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Equal(t, "HIT", rec.Header().Get("X-Cache"))
+}
+
+func TestAPIEndpointTimeouts(t *testing.T) {
+	oldToken := apiToken
+	apiToken = "secret"
+	defer func() {
+		apiToken = oldToken
+		apiListTimeout, apiDetailTimeout, apiSearchTimeout = 30*time.Second, 60*time.Second, 45*time.Second
+	}()
+	apiListTimeout, apiDetailTimeout, apiSearchTimeout = time.Second, 20*time.Millisecond, 0
+
+	release := make(chan struct{})
+	defer close(release)
+	slow := func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-time.After(100 * time.Millisecond):
+		}
+		encodeData(w, "ok", crawler.LiveFreshness(time.Now()))
+	}
+	r := mux.NewRouter()
+	registerAPIRoutes(r, []apiRoute{
+		{"/exploit", slow},
+		{"/cve/{id}", slow},
+		{"/search", slow},
+	}, time.Time{})
+	get := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("X-API-Token", "secret")
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// 详情接口使用较短的超时，列表和不限制超时的搜索正常返回并计算ETag
+	assert.Equal(t, http.StatusGatewayTimeout, get("/api/v1/cve/CVE-2024-0001").Code)
+	list := get("/api/v1/exploit")
+	assert.Equal(t, http.StatusOK, list.Code)
+	assert.NotEmpty(t, list.Header().Get("ETag"))
+	assert.Equal(t, http.StatusOK, get("/api/v1/search").Code)
+
+	assert.NoError(t, validateAPITimeouts())
+	apiSearchTimeout = -time.Second
+	assert.Error(t, validateAPITimeouts())
+}
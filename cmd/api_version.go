@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+)
+
+// currentAPIVersion 是当前的API版本，新的集成应使用 /api/v1/ 下的路径
+const currentAPIVersion = "v1"
+
+// legacyAPIDeprecated 是未带版本的 /api/ 路径被废弃的日期
+var legacyAPIDeprecated = time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+
+// apiLegacySunset 是 --legacy-sunset 指定的未带版本路径的停用日期，为空表示未确定
+var apiLegacySunset string
+
+// apiRoute 是一个 /api/ 接口，path 不含 /api 和版本前缀，例如 /exploit/{id}
+type apiRoute struct {
+	path    string
+	handler http.HandlerFunc
+}
+
+// apiDeprecation 描述一个已废弃的接口，通过 Deprecation(RFC 9745)、Sunset(RFC 8594)
+// 和 Link 响应头告知客户端迁移到新版本
+type apiDeprecation struct {
+	Since     time.Time // 废弃日期
+	Sunset    time.Time // 计划停用的日期，零值表示未确定
+	Successor string    // 替代的API版本，Link头指向该版本下的同一路径
+}
+
+// deprecationMiddleware 为已废弃接口的响应添加废弃相关的响应头，不改变响应内容
+//
+// 参数:
+//   - d: 废弃信息
+//   - next: 下一个要执行的处理函数
+//
+// 返回值:
+//   - http.HandlerFunc: 包装后的处理函数
+func deprecationMiddleware(d apiDeprecation, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", fmt.Sprintf("@%d", d.Since.Unix()))
+		if !d.Sunset.IsZero() {
+			w.Header().Set("Sunset", d.Sunset.UTC().Format(http.TimeFormat))
+		}
+		if d.Successor != "" {
+			successor := "/api/" + d.Successor + strings.TrimPrefix(r.URL.Path, "/api")
+			w.Header().Add("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, successor))
+		}
+		next.ServeHTTP(w, r)
+	}
+}
+
+// parseLegacySunset 解析 --legacy-sunset 指定的日期，格式为 2006-01-02
+func parseLegacySunset(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	sunset, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf(T("无效的停用日期 %q，格式应为 YYYY-MM-DD"), value)
+	}
+	return sunset, nil
+}
+
+// apiRoutes 返回 api 命令提供的所有 /api/ 接口
+// feeds 是作者订阅使用的缓存，与后台定期爬取共享；assets 是图片代理
+func apiRoutes(c *crawler.Crawler, feeds *authorFeedCache, assets *assetProxy) []apiRoute {
+	return []apiRoute{
+		{"/exploit", handleExploitList(c)},
+		// 需要在 /exploit/{id} 之前注册，否则 latest 会被当作漏洞ID
+		{"/exploit/latest", handleExploitLatest(c)},
+		{"/exploit/{id}", handleExploitDetail(c)},
+		{"/cve/{id}", handleCveDetail(c)},
+		{"/author/{id}", handleAuthorProfile(c)},
+		{"/author/{id}/feed", handleAuthorFeed(feeds)},
+		{"/search", handleSearch(c)},
+		{"/assets", handleAsset(assets)},
+		{"/local/authors", handleLocalAuthors(watchAuthorState)},
+		{"/local/authors/{id}/vulnerabilities", handleLocalAuthor(storeFile)},
+		{"/status", handleStatus(c)},
+	}
+}
+
+// registerAPIRoutes 在 /api/v1/ 下注册接口，同时保留未带版本的 /api/ 路径
+// 两者由同一个处理函数响应，未带版本的路径额外返回废弃相关的响应头，
+// 集成方可以按自己的节奏迁移；按注册顺序匹配，固定路径需要排在同级的路径变量之前。
+//
+// 参数:
+//   - r: 路由器
+//   - routes: 要注册的接口
+//   - sunset: 未带版本路径的停用日期，零值表示未确定
+func registerAPIRoutes(r *mux.Router, routes []apiRoute, sunset time.Time) {
+	legacy := apiDeprecation{
+		Since:     legacyAPIDeprecated,
+		Sunset:    sunset,
+		Successor: currentAPIVersion,
+	}
+	for _, route := range routes {
+		// 超时放在ETag之外，超时后不再等待ETag计算
+		handler := authMiddleware(timeoutMiddleware(apiEndpointTimeout(route.path), etagMiddleware(route.handler)))
+
+		path := "/api/" + currentAPIVersion + route.path
+		r.HandleFunc(path, apiRequests.middleware(path, securityHeadersMiddleware(corsMiddleware(handler)))).Methods("GET", "OPTIONS")
+
+		path = "/api" + route.path
+		r.HandleFunc(path, apiRequests.middleware(path, securityHeadersMiddleware(deprecationMiddleware(legacy, corsMiddleware(handler))))).Methods("GET", "OPTIONS")
+	}
+}
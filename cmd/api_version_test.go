@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterAPIRoutes(t *testing.T) {
+	oldToken := apiToken
+	apiToken = "secret"
+	defer func() { apiToken = oldToken }()
+
+	r := mux.NewRouter()
+	registerAPIRoutes(r, []apiRoute{
+		{"/exploit/latest", func(w http.ResponseWriter, r *http.Request) {
+			encodeJSON(w, APIResponse{Success: true, Data: "latest"})
+		}},
+		{"/exploit/{id}", func(w http.ResponseWriter, r *http.Request) {
+			encodeJSON(w, APIResponse{Success: true, Data: mux.Vars(r)["id"]})
+		}},
+	}, time.Date(2027, 6, 30, 0, 0, 0, 0, time.UTC))
+	get := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("X-API-Token", "secret")
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := get("/api/v1/exploit/WLB-2024040015")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "WLB-2024040015")
+	assert.Empty(t, rec.Header().Get("Deprecation"))
+	assert.Empty(t, rec.Header().Get("Sunset"))
+	assert.Contains(t, get("/api/v1/exploit/latest").Body.String(), `"latest"`)
+
+	legacy := get("/api/exploit/WLB-2024040015")
+	require.Equal(t, http.StatusOK, legacy.Code)
+	assert.Equal(t, rec.Body.String(), legacy.Body.String(), "未带版本的路径应返回相同的内容")
+	assert.Equal(t, rec.Header().Get("ETag"), legacy.Header().Get("ETag"))
+	assert.Equal(t, "@1792108800", legacy.Header().Get("Deprecation"))
+	assert.Equal(t, "Wed, 30 Jun 2027 00:00:00 GMT", legacy.Header().Get("Sunset"))
+	assert.Equal(t, `</api/v1/exploit/WLB-2024040015>; rel="successor-version"`, legacy.Header().Get("Link"))
+
+	// 认证失败的响应同样带有废弃信息
+	req := httptest.NewRequest(http.MethodGet, "/api/exploit/latest", nil)
+	unauthorized := httptest.NewRecorder()
+	r.ServeHTTP(unauthorized, req)
+	assert.Equal(t, http.StatusUnauthorized, unauthorized.Code)
+	assert.NotEmpty(t, unauthorized.Header().Get("Deprecation"))
+}
+
+func TestParseLegacySunset(t *testing.T) {
+	sunset, err := parseLegacySunset("")
+	require.NoError(t, err)
+	assert.True(t, sunset.IsZero())
+
+	sunset, err = parseLegacySunset("2027-06-30")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2027, 6, 30, 0, 0, 0, 0, time.UTC), sunset)
+
+	_, err = parseLegacySunset("30/06/2027")
+	assert.Error(t, err)
+}
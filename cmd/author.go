@@ -7,75 +7,124 @@ import (
 
 	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/mattn/go-runewidth"
 	"github.com/spf13/cobra"
-	"golang.org/x/term"
 
 	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/logging"
 	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
 )
 
 var (
 	authorID         string
 	authorOutputFile string
-	authorSilent     bool
+	authorLocale     string
+	authorFromStore  bool
+	authorRisks      []string
 )
 
 var authorCmd = &cobra.Command{
 	Use:   "author",
-	Short: "爬取作者信息",
-	Long:  `爬取CXSecurity网站的作者信息，并将结果保存为JSON格式`,
+	Short: T("爬取作者信息"),
+	Long: T(`爬取CXSecurity网站的作者信息，并将结果保存为JSON格式。
+使用 --from-store 时不请求网站，而是从存储中查询历次爬取和导入的所有归属于该作者的漏洞，
+不受作者页面分页数量的限制。`),
+	Example: `  cxcrawler author -i hyp3rlinx
+  cxcrawler author -i hyp3rlinx --from-store -r High`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// 如果没有提供作者ID，显示使用帮助
 		if authorID == "" {
-			fmt.Println("请使用 -i 或 --id 参数指定作者ID")
+			fmt.Println(T("请使用 -i 或 --id 参数指定作者ID"))
 			cmd.Help()
 			return
 		}
 
+		if authorFromStore {
+			showStoredAuthor(cmd)
+			return
+		}
+
 		// 创建爬虫实例
-		c := crawler.NewCrawler()
+		c := newCrawler(crawler.WithLocale(authorLocale))
 
 		// 显示加载提示
-		if !authorSilent {
+		if humanOutput() {
 			fmt.Printf("\n%s %s\n",
-				text.Colors{text.FgHiBlue, text.Bold}.Sprint("👤 正在获取作者信息:"),
+				text.Colors{text.FgHiBlue, text.Bold}.Sprint(T("👤 正在获取作者信息:")),
 				text.Colors{text.FgHiWhite, text.Bold}.Sprint(authorID))
 		}
 
 		// 执行爬取
 		result, err := c.CrawlAuthor(authorID, authorOutputFile)
 		if err != nil {
-			fmt.Printf("\n%s %v\n",
-				text.Colors{text.FgRed, text.Bold}.Sprint("❌ 获取失败:"),
+			logging.Printf("\n%s %v\n",
+				text.Colors{text.FgRed, text.Bold}.Sprint(T("❌ 获取失败:")),
 				err)
 			return
 		}
 
 		// 只有在非静默模式下才输出结果
-		if !authorSilent {
+		if !printFormatted(result) && !quietOutput {
 			printAuthorResult(result, authorOutputFile)
 		}
+		handleOpenAndCopy(result)
 	},
 }
 
-// printAuthorResult 格式化输出作者信息结果
-func printAuthorResult(result *model.AuthorProfile, outputPath string) {
-	// 获取终端宽度
-	width, _, err := term.GetSize(int(os.Stdout.Fd()))
+// showStoredAuthor 输出存储中作者的所有漏洞，只有显式指定 -o 时才保存结果
+func showStoredAuthor(cmd *cobra.Command) {
+	risks := make([]string, 0, len(authorRisks))
+	for _, r := range authorRisks {
+		risk := crawler.NormalizeRiskLevel(r)
+		if risk == "" {
+			fmt.Printf(T("无效的风险级别 %q，可选值：High、Med、Low")+"\n", r)
+			return
+		}
+		risks = append(risks, risk)
+	}
+	s, err := openStore(storeFile)
 	if err != nil {
-		// 如果获取失败，使用默认宽度
-		width = 80
+		logging.Printf("%s %v\n", text.Colors{text.FgRed, text.Bold}.Sprint(T("❌ 获取失败:")), err)
+		return
+	}
+	result := localAuthorVulnerabilities(s, authorID, risks, false, false, 1, 0)
+
+	outputPath := ""
+	if cmd.Flags().Changed("output") {
+		outputPath = authorOutputFile
+		if err := newCrawler().SaveJSON(result, outputPath); err != nil {
+			logging.Printf(T("保存结果失败: %v\n"), err)
+			outputPath = ""
+		}
 	}
+	if printFormatted(result) || quietOutput {
+		return
+	}
+	if result.Total == 0 {
+		fmt.Println(text.Colors{text.FgHiBlack}.Sprintf(T("存储 %s 中没有作者 %s 的漏洞"), s.Path(), authorID))
+		return
+	}
+	fmt.Printf("\n%s %s\n", text.Colors{text.FgHiBlue, text.Bold}.Sprintf(T("👤 存储中作者 %s 的漏洞:"), authorID),
+		text.Colors{text.FgHiBlack}.Sprintf(T("(共 %d 条)"), result.Total))
+	printExploitResult(&model.VulnerabilityList{Items: result.Vulnerabilities, CurrentPage: 1, TotalPages: 1}, outputPath)
+}
+
+// printAuthorResult 格式化输出作者信息结果
+func printAuthorResult(result *model.AuthorProfile, outputPath string) {
+	// 获取终端宽度，获取失败时使用默认宽度
+	width := terminalWidth(80)
 
 	// 计算边框和内容宽度
-	borderWidth := width - 2              // 两侧各减1个字符给边框
-	titlePadding := (borderWidth - 8) / 2 // "作者信息"是4个汉字(8个字符宽度)，两侧填充
+	borderWidth := width - 2 // 两侧各减1个字符给边框
+	title := T("作者信息")
+	titleWidth := calculateDisplayWidth(title)
+	titlePadding := (borderWidth - titleWidth) / 2 // 标题两侧填充
 
 	// 构建顶部边框
-	topBorder := "┏" + strings.Repeat("━", borderWidth) + "┓"
-	titleLine := "┃" + strings.Repeat(" ", titlePadding) + text.Colors{text.FgHiCyan, text.Bold}.Sprint("作者信息") + strings.Repeat(" ", borderWidth-titlePadding-8) + "┃"
-	middleBorder := "┣" + strings.Repeat("━", borderWidth) + "┫"
-	bottomBorder := "┗" + strings.Repeat("━", borderWidth) + "┛"
+	topBorder := boxText("┏" + strings.Repeat("━", borderWidth) + "┓")
+	titleLine := boxText("┃" + strings.Repeat(" ", titlePadding) + text.Colors{text.FgHiCyan, text.Bold}.Sprint(title) + strings.Repeat(" ", borderWidth-titlePadding-titleWidth) + "┃")
+	middleBorder := boxText("┣" + strings.Repeat("━", borderWidth) + "┫")
+	bottomBorder := boxText("┗" + strings.Repeat("━", borderWidth) + "┛")
 
 	// 显示表头
 	fmt.Println()
@@ -108,25 +157,42 @@ func printAuthorResult(result *model.AuthorProfile, outputPath string) {
 		}
 
 		// 输出行，确保右边框对齐
-		fmt.Printf("┃ %s: %s%s ┃\n", labelText, valueText, strings.Repeat(" ", padding))
+		fmt.Printf(boxText("┃ %s: %s%s ┃\n"), labelText, valueText, strings.Repeat(" ", padding))
+	}
+
+	// 输出分节标题，按标题的实际显示宽度填充
+	printSection := func(title string) {
+		padding := contentWidth - calculateDisplayWidth(title)
+		if padding < 0 {
+			padding = 0
+		}
+		fmt.Printf(boxText("┃ %s%s ┃\n"), text.Colors{text.Bold, text.BgBlack, text.FgHiWhite}.Sprint(title), strings.Repeat(" ", padding))
 	}
 
 	// 输出基本信息
-	printLine("作者ID", result.ID, text.FgHiCyan)
-	printLine("作者名称", result.Name, text.FgHiWhite, text.Bold)
-	printLine("国家", fmt.Sprintf("%s (%s)", result.Country, result.CountryCode), text.FgYellow)
-	printLine("报告数量", fmt.Sprintf("%d", result.ReportedCount), text.FgHiGreen, text.Bold)
+	printLine(T("作者ID"), result.ID, text.FgHiCyan)
+	printLine(T("作者名称"), result.Name, text.FgHiWhite, text.Bold)
+	country := T("未知")
+	if result.Country != "" {
+		country = result.Country
+		if result.CountryLocalized != "" && result.CountryLocalized != result.Country {
+			country = fmt.Sprintf("%s / %s", result.CountryLocalized, result.Country)
+		}
+		country = fmt.Sprintf("%s (%s)", country, result.CountryCode)
+	}
+	printLine(T("国家"), country, text.FgYellow)
+	printLine(T("报告数量"), fmt.Sprintf("%d", result.ReportedCount), text.FgHiGreen, text.Bold)
 
 	// 如果有联系方式，输出联系信息
 	if result.Twitter != "" || result.Website != "" || result.ZoneH != "" {
-		fmt.Println("┣" + strings.Repeat("━", borderWidth) + "┫")
-		fmt.Printf("┃ %s%s ┃\n", text.Colors{text.Bold, text.BgBlack, text.FgHiWhite}.Sprint("联系方式"), strings.Repeat(" ", contentWidth-8))
+		fmt.Println(boxText("┣" + strings.Repeat("━", borderWidth) + "┫"))
+		printSection(T("联系方式"))
 
 		if result.Twitter != "" {
 			printLine("Twitter", result.Twitter, text.FgBlue, text.Underline)
 		}
 		if result.Website != "" {
-			printLine("网站", result.Website, text.FgBlue, text.Underline)
+			printLine(T("网站"), result.Website, text.FgBlue, text.Underline)
 		}
 		if result.ZoneH != "" {
 			printLine("Zone-H", result.ZoneH, text.FgBlue, text.Underline)
@@ -135,8 +201,8 @@ func printAuthorResult(result *model.AuthorProfile, outputPath string) {
 
 	// 如果有描述，输出描述信息
 	if result.Description != "" {
-		fmt.Println("┣" + strings.Repeat("━", borderWidth) + "┫")
-		fmt.Printf("┃ %s%s ┃\n", text.Colors{text.Bold, text.BgBlack, text.FgHiWhite}.Sprint("个人描述"), strings.Repeat(" ", contentWidth-8))
+		fmt.Println(boxText("┣" + strings.Repeat("━", borderWidth) + "┫"))
+		printSection(T("个人描述"))
 
 		// 处理可能的多行描述
 		descLines := strings.Split(result.Description, "\n")
@@ -146,23 +212,23 @@ func printAuthorResult(result *model.AuthorProfile, outputPath string) {
 			if padding < 0 {
 				padding = 0
 			}
-			fmt.Printf("┃ %s%s ┃\n", text.Colors{text.FgHiWhite}.Sprint(line), strings.Repeat(" ", padding))
+			fmt.Printf(boxText("┃ %s%s ┃\n"), text.Colors{text.FgHiWhite}.Sprint(line), strings.Repeat(" ", padding))
 		}
 	}
 
 	// 输出漏洞列表
 	if len(result.Vulnerabilities) > 0 {
-		fmt.Println("┣" + strings.Repeat("━", borderWidth) + "┫")
-		fmt.Printf("┃ %s%s ┃\n", text.Colors{text.Bold, text.BgBlack, text.FgHiWhite}.Sprint("发布的漏洞"), strings.Repeat(" ", contentWidth-8))
-		fmt.Println("┣" + strings.Repeat("━", borderWidth) + "┫")
+		fmt.Println(boxText("┣" + strings.Repeat("━", borderWidth) + "┫"))
+		printSection(T("发布的漏洞"))
+		fmt.Println(boxText("┣" + strings.Repeat("━", borderWidth) + "┫"))
 
 		// 创建并配置表格
 		t := table.NewWriter()
 		t.SetOutputMirror(os.Stdout)
-		t.SetStyle(table.StyleLight)
+		t.SetStyle(tableStyle(table.StyleLight))
 
 		// 设置表头
-		t.AppendHeader(table.Row{"#", "日期", "风险", "漏洞标题", "类型"})
+		t.AppendHeader(table.Row{"#", T("日期"), T("风险"), T("漏洞标题"), T("类型")})
 
 		// 设置表头样式
 		t.SetColumnConfigs([]table.ColumnConfig{
@@ -173,12 +239,15 @@ func printAuthorResult(result *model.AuthorProfile, outputPath string) {
 			{Number: 5, Align: text.AlignCenter, AlignHeader: text.AlignCenter, ColorsHeader: text.Colors{text.BgBlack, text.FgHiWhite, text.Bold}},
 		})
 
+		// 标题列宽度：内容宽度减去序号、日期、风险、类型列和分隔符
+		titleWidth := contentWidth - 40
+
 		// 添加数据行
 		for i, vuln := range result.Vulnerabilities {
 			// 格式化日期
-			date := "未知"
+			date := T("未知")
 			if !vuln.Date.IsZero() {
-				date = vuln.Date.Format("2006-01-02")
+				date = formatDate(vuln.Date)
 			}
 
 			// 格式化风险级别
@@ -200,8 +269,8 @@ func printAuthorResult(result *model.AuthorProfile, outputPath string) {
 				vulnType = text.Colors{text.FgBlue}.Sprint("Local")
 			}
 
-			// 高亮显示漏洞ID和标题
-			title := vuln.Title
+			// 高亮显示漏洞ID和标题，标题按剩余宽度截断
+			title := truncateCell(vuln.Title, titleWidth-stringDisplayWidth(vuln.ID)-2)
 			if vuln.ID != "" {
 				idPart := text.Colors{text.FgHiCyan}.Sprint(vuln.ID)
 				title = fmt.Sprintf("%s: %s", idPart, title)
@@ -227,29 +296,26 @@ func printAuthorResult(result *model.AuthorProfile, outputPath string) {
 	// 输出保存路径信息
 	if outputPath != "" {
 		fmt.Printf("%s %s\n",
-			text.Colors{text.FgHiGreen}.Sprint("✅ 已保存:"),
+			text.Colors{text.FgHiGreen}.Sprint(T("✅ 已保存:")),
 			text.Colors{text.FgHiCyan, text.Underline}.Sprint(outputPath))
 	}
 }
 
 // calculateDisplayWidth 计算字符串在终端中的显示宽度
 func calculateDisplayWidth(s string) int {
-	width := 0
-	for _, r := range s {
-		if r > 127 {
-			width += 2 // 假设所有非ASCII字符宽度为2
-		} else {
-			width += 1
-		}
-	}
-	return width
+	return runewidth.StringWidth(s)
 }
 
 func init() {
 	rootCmd.AddCommand(authorCmd)
 
 	// 添加命令行参数
-	authorCmd.Flags().StringVarP(&authorID, "id", "i", "", "要爬取的作者ID (必须)")
-	authorCmd.Flags().StringVarP(&authorOutputFile, "output", "o", "author_result.json", "结果输出的文件路径")
-	authorCmd.Flags().BoolVarP(&authorSilent, "silent", "s", false, "静默模式，不输出到标准输出")
+	authorCmd.Flags().StringVarP(&authorID, "id", "i", "", T("要爬取的作者ID (必须)"))
+	authorCmd.Flags().StringVarP(&authorOutputFile, "output", "o", "author_result.json", T("结果输出的文件路径"))
+	authorCmd.Flags().BoolVarP(&quietOutput, "silent", "s", false, T("静默模式，不输出到标准输出"))
+	deprecateSilent(authorCmd)
+	authorCmd.Flags().StringVarP(&authorLocale, "locale", "", "", T("国家名称的本地化语言，例如 zh、de；默认只输出英文名称"))
+	authorCmd.Flags().BoolVar(&authorFromStore, "from-store", false, T("从存储中查询该作者的所有漏洞，不请求网站"))
+	authorCmd.Flags().StringVar(&storeFile, "store", "", T("存储文件，默认为配置目录下的 vulnerabilities.json"))
+	authorCmd.Flags().StringSliceVarP(&authorRisks, "risk", "r", nil, T("使用 --from-store 时只显示指定风险级别的漏洞(High、Med、Low)，多个用逗号分隔"))
 }
@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+)
+
+var (
+	benchDir         string
+	benchDuration    time.Duration
+	benchMinPagesSec float64
+)
+
+// fixtureClient 是一个从内存返回固定页面内容的HTTP客户端
+// 用于在不访问网络的情况下衡量解析流水线的吞吐量
+type fixtureClient struct {
+	content string
+}
+
+func (f *fixtureClient) GetPage(path string) (string, error) {
+	return f.content, nil
+}
+
+func (f *fixtureClient) GetBaseURL() string {
+	return "https://cxsecurity.com"
+}
+
+// benchCase 描述一个基准测试用例：使用哪个样本页面，以及如何处理它
+type benchCase struct {
+	name string
+	file string
+	run  func(c *crawler.Crawler) error
+}
+
+// benchResult 记录单个用例的测量结果
+type benchResult struct {
+	name     string
+	pages    int
+	bytes    int64
+	duration time.Duration
+}
+
+// pagesPerSec 返回每秒处理的页面数
+func (r benchResult) pagesPerSec() float64 {
+	return float64(r.pages) / r.duration.Seconds()
+}
+
+// mbPerSec 返回每秒处理的数据量(MB)
+func (r benchResult) mbPerSec() float64 {
+	return float64(r.bytes) / r.duration.Seconds() / (1024 * 1024)
+}
+
+var benchCases = []benchCase{
+	{
+		name: T("漏洞列表"),
+		file: "list-response.html",
+		run: func(c *crawler.Crawler) error {
+			_, err := c.CrawlPage("/exploit/1", "")
+			return err
+		},
+	},
+	{
+		name: T("漏洞详情"),
+		file: "vul-detail-response.html",
+		run: func(c *crawler.Crawler) error {
+			_, err := c.CrawlVulnerabilityDetail("/issue/WLB-2007030137", "")
+			return err
+		},
+	},
+	{
+		name: T("CVE详情"),
+		file: "cve-show-detail-response.html",
+		run: func(c *crawler.Crawler) error {
+			_, err := c.CrawlCveDetail("CVE-2007-1411", "")
+			return err
+		},
+	},
+	{
+		name: T("作者信息"),
+		file: "author-profile-response.html",
+		run: func(c *crawler.Crawler) error {
+			_, err := c.CrawlAuthor("rgod", "")
+			return err
+		},
+	},
+	{
+		name: T("搜索流水线"),
+		file: "search-response.html",
+		run: func(c *crawler.Crawler) error {
+			_, err := c.SearchVulnerabilitiesAdvanced("php", 1, 10, "DESC", "")
+			return err
+		},
+	},
+}
+
+var benchCmd = &cobra.Command{
+	Use:   "bench",
+	Short: T("测试解析器性能"),
+	Long: T(`使用归档的页面样本反复运行各个解析器和搜索流水线，输出吞吐量(页面/秒、MB/秒)，
+便于发现性能回退。指定 --min-pages-per-sec 时，任一用例低于该阈值都会以非零状态码退出。`),
+	Run: func(cmd *cobra.Command, args []string) {
+		results := make([]benchResult, 0, len(benchCases))
+		for _, bc := range benchCases {
+			content, err := os.ReadFile(filepath.Join(benchDir, bc.file))
+			if err != nil {
+				fmt.Printf(T("读取样本文件失败: %v\n"), err)
+				os.Exit(1)
+			}
+
+			c := crawler.NewCrawler(crawler.WithHTTPClient(&fixtureClient{content: string(content)}))
+			result, err := runBenchCase(c, bc, int64(len(content)))
+			if err != nil {
+				fmt.Printf(T("%s 基准测试失败: %v\n"), bc.name, err)
+				os.Exit(1)
+			}
+			results = append(results, result)
+		}
+
+		printBenchResults(results)
+
+		if benchMinPagesSec > 0 {
+			failed := false
+			for _, r := range results {
+				if r.pagesPerSec() < benchMinPagesSec {
+					fmt.Printf(T("%s %s 吞吐量 %.1f 页面/秒 低于阈值 %.1f\n"), text.Colors{text.FgRed, text.Bold}.Sprint(T("❌ 性能回退:")),
+						r.name, r.pagesPerSec(), benchMinPagesSec)
+					failed = true
+				}
+			}
+			if failed {
+				os.Exit(1)
+			}
+		}
+	},
+}
+
+// runBenchCase 在指定时长内反复执行用例，并统计处理的页面数和字节数
+func runBenchCase(c *crawler.Crawler, bc benchCase, size int64) (benchResult, error) {
+	// 先执行一次预热，同时确认样本能被正确处理
+	if err := bc.run(c); err != nil {
+		return benchResult{}, err
+	}
+
+	result := benchResult{name: bc.name}
+	start := time.Now()
+	for time.Since(start) < benchDuration {
+		if err := bc.run(c); err != nil {
+			return benchResult{}, err
+		}
+		result.pages++
+		result.bytes += size
+	}
+	result.duration = time.Since(start)
+
+	return result, nil
+}
+
+// printBenchResults 以表格形式输出基准测试结果
+func printBenchResults(results []benchResult) {
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(tableStyle(table.StyleRounded))
+
+	t.AppendHeader(table.Row{T("用例"), T("页面数"), T("耗时"), T("页面/秒"), T("MB/秒")})
+	for _, r := range results {
+		t.AppendRow(table.Row{
+			r.name,
+			r.pages,
+			r.duration.Round(time.Millisecond),
+			fmt.Sprintf("%.1f", r.pagesPerSec()),
+			fmt.Sprintf("%.2f", r.mbPerSec()),
+		})
+	}
+
+	fmt.Printf("\n%s\n", text.Colors{text.Bold, text.FgHiGreen}.Sprint(T("⏱️ 解析器基准测试结果:")))
+	t.Render()
+}
+
+func init() {
+	rootCmd.AddCommand(benchCmd)
+
+	// 添加标志
+	benchCmd.Flags().StringVarP(&benchDir, "dir", "d", "docs/response-examples", T("归档页面样本所在目录"))
+	benchCmd.Flags().DurationVarP(&benchDuration, "duration", "t", 2*time.Second, T("每个用例的运行时长"))
+	benchCmd.Flags().Float64VarP(&benchMinPagesSec, "min-pages-per-sec", "", 0, T("吞吐量下限(页面/秒)，任一用例低于该值时以非零状态码退出"))
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/config"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+)
+
+var (
+	bundleOutput  string
+	bundleReplace bool
+	bundleDryRun  bool
+)
+
+var bundleCmd = &cobra.Command{
+	Use:   "bundle [WLB-ID]",
+	Short: T("生成漏洞证据包，或导出和导入配置包"),
+	Long: T(`指定漏洞ID时，获取漏洞详情页并生成ZIP证据包，可以直接作为附件提交到工单或报告中。
+证据包包含解析后的JSON、原始HTML、从正文中提取的代码片段、公告中引用的图片和Markdown摘要。
+
+export 和 import 子命令将关注列表、保存的搜索、通知设置和保留策略导出为可移植的YAML配置包，
+在另一台机器上导入，便于团队分享和迁移。配置包中不包含Token、API密钥等凭据。`),
+	Example: `  cxcrawler bundle WLB-2024040015
+  cxcrawler bundle WLB-2024040015 -o evidence/WLB-2024040015.zip --no-screenshots
+  cxcrawler bundle export -o team.yaml`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runAdvisoryBundle,
+}
+
+var bundleExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: T("导出配置包"),
+	Example: `  cxcrawler bundle export -o team.yaml
+  cxcrawler bundle export > team.yaml`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		bundle, err := config.ExportBundle(config.BundlePaths{}, time.Now())
+		if err != nil {
+			return err
+		}
+		data, err := bundle.YAML()
+		if err != nil {
+			return err
+		}
+		if bundleOutput == "" {
+			_, err := os.Stdout.Write(data)
+			return err
+		}
+		// 配置包中可能包含内部系统的地址，只允许当前用户访问
+		if err := crawler.WriteFileAtomic(bundleOutput, data, crawler.SecureFileMode, false); err != nil {
+			return err
+		}
+		fmt.Printf(T("已导出 %d 个关注的产品和 %d 条保存的搜索到 %s")+"\n", len(bundle.Watchlist), len(bundle.SavedSearches), bundleOutput)
+		return nil
+	},
+}
+
+var bundleImportCmd = &cobra.Command{
+	Use:   "import <file.yaml>",
+	Short: T("导入配置包"),
+	Long: T(`导入配置包中的关注列表、保存的搜索、通知设置和保留策略。
+本地已有的同名关注产品和搜索、已有的通知设置和保留策略默认保留不变，使用 --replace 覆盖。
+导入前会校验整个配置包，有无效的配置时不会修改任何文件。`),
+	Example: `  cxcrawler bundle import team.yaml --dry-run
+  cxcrawler bundle import team.yaml --replace`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			return fmt.Errorf(T("读取配置包失败: %w"), err)
+		}
+		bundle, err := config.ParseBundle(data)
+		if err != nil {
+			return err
+		}
+		items, err := config.ImportBundle(bundle, config.BundlePaths{}, bundleReplace, bundleDryRun)
+		if err != nil {
+			return err
+		}
+
+		if printFormatted(items) || quietOutput {
+			return nil
+		}
+		printBundleItems(items)
+		return nil
+	},
+}
+
+// printBundleItems 以表格形式输出导入结果
+func printBundleItems(items []config.BundleItem) {
+	if len(items) == 0 {
+		fmt.Println(text.Colors{text.FgHiYellow}.Sprint(T("配置包是空的")))
+		return
+	}
+
+	kinds := map[string]string{
+		"watchlist":     T("关注的产品"),
+		"saved_search":  T("保存的搜索"),
+		"notifications": T("通知设置"),
+		"retention":     T("保留策略"),
+	}
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(tableStyle(table.StyleRounded))
+	t.AppendHeader(table.Row{T("类型"), T("名称"), T("状态")})
+	for _, item := range items {
+		var status string
+		switch item.Status {
+		case config.BundleAdded:
+			status = text.Colors{text.FgHiGreen}.Sprint(T("已添加"))
+		case config.BundleReplaced:
+			status = text.Colors{text.FgHiCyan}.Sprint(T("已覆盖"))
+		default:
+			status = text.Colors{text.FgHiBlack}.Sprint(T("已存在，保留本地配置"))
+		}
+		if bundleDryRun && item.Status != config.BundleSkipped {
+			status += text.Colors{text.FgHiYellow}.Sprint(" (dry-run)")
+		}
+		t.AppendRow(table.Row{kinds[item.Kind], item.Name, status})
+	}
+	t.Render()
+}
+
+func init() {
+	rootCmd.AddCommand(bundleCmd)
+	bundleCmd.AddCommand(bundleExportCmd, bundleImportCmd)
+
+	bundleExportCmd.Flags().StringVarP(&bundleOutput, "output", "o", "", T("输出文件，默认输出到标准输出"))
+	bundleImportCmd.Flags().BoolVar(&bundleReplace, "replace", false, T("覆盖本地已有的同名配置"))
+	bundleImportCmd.Flags().BoolVar(&bundleDryRun, "dry-run", false, T("只显示将要导入的配置，不修改配置文件"))
+}
@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+)
+
+var (
+	clientTimeout   time.Duration
+	clientRetries   int
+	clientProxy     string
+	clientRateLimit float64
+	clientHeaders   []string
+	clientBaseURL   string
+	clientMirrors   []string
+	clientBudget    int
+	// 使用镜像时检查 --base-url 是否恢复的间隔
+	clientMirrorHealth time.Duration
+	// 按路径前缀添加HTTP头的JSON文件
+	clientRouteHeaders string
+	// 轮换使用的代理和轮换策略
+	clientProxyPool     []string
+	clientProxyRotation string
+	// 不根据429/503和响应时间自动调整请求速率
+	clientNoAdaptiveThrottle bool
+
+	// 解析后的HTTP客户端选项，由PersistentPreRunE设置
+	globalClientOptions []crawler.ClientOption
+)
+
+// parseClientFlags 校验HTTP客户端相关的全局标志并生成客户端选项
+// 没有指定 --proxy 时使用 HTTPS_PROXY 等环境变量中的代理
+func parseClientFlags() error {
+	if clientTimeout <= 0 {
+		return fmt.Errorf(T("无效的超时时间 %s，应大于0"), clientTimeout)
+	}
+	if clientRetries < 0 {
+		return fmt.Errorf(T("无效的重试次数 %d，不能小于0"), clientRetries)
+	}
+	if clientRateLimit < 0 {
+		return fmt.Errorf(T("无效的请求速率 %g，不能小于0"), clientRateLimit)
+	}
+
+	if clientBudget < 0 {
+		return fmt.Errorf(T("无效的请求预算 %d，不能小于0"), clientBudget)
+	}
+
+	options := []crawler.ClientOption{
+		crawler.WithTimeout(clientTimeout),
+		crawler.WithRetry(clientRetries, 0),
+		crawler.WithRateLimit(clientRateLimit),
+		crawler.WithRequestBudget(clientBudget),
+	}
+	if !clientNoAdaptiveThrottle {
+		options = append(options, crawler.WithAdaptiveThrottle(adaptiveThrottleMinDelay(), 30*time.Second, time.Minute))
+	}
+	logger := requestLogger()
+	options = append(options, crawler.WithRequestLogger(func(l crawler.RequestLog) {
+		if logger != nil {
+			logger(l)
+		}
+		recordRequest(l)
+	}))
+	if clientProxy != "" && len(clientProxyPool) > 0 {
+		return errors.New(T("--proxy 和 --proxy-pool 不能同时使用"))
+	}
+	if clientProxy != "" {
+		if err := validateProxyURL(clientProxy); err != nil {
+			return err
+		}
+		options = append(options, crawler.WithProxy(clientProxy))
+	}
+	if len(clientProxyPool) > 0 {
+		for _, p := range clientProxyPool {
+			if err := validateProxyURL(p); err != nil {
+				return err
+			}
+		}
+		strategy, err := crawler.ParseRotationStrategy(clientProxyRotation)
+		if err != nil {
+			return fmt.Errorf(T("不支持的代理轮换策略 %q，可选值：round-robin、random、sticky"), clientProxyRotation)
+		}
+		options = append(options, crawler.WithProxyPool(clientProxyPool, strategy))
+	}
+	if clientBaseURL != "" {
+		if err := validateSiteURL(clientBaseURL); err != nil {
+			return err
+		}
+		options = append(options, crawler.WithBaseURL(clientBaseURL))
+	}
+	if clientMirrorHealth < 0 {
+		return fmt.Errorf(T("无效的镜像健康检查间隔 %s，应大于等于0"), clientMirrorHealth)
+	}
+	if len(clientMirrors) > 0 {
+		mirrors := make([]crawler.Mirror, 0, len(clientMirrors))
+		for _, m := range clientMirrors {
+			if err := validateSiteURL(m); err != nil {
+				return err
+			}
+			mirrors = append(mirrors, crawler.Mirror{BaseURL: m})
+		}
+		options = append(options, crawler.WithMirrors(mirrors...), crawler.WithMirrorHealthCheck(clientMirrorHealth, "/"))
+	}
+	for _, h := range clientHeaders {
+		key, value, err := parseHeader(h)
+		if err != nil {
+			return err
+		}
+		options = append(options, crawler.WithHeader(key, value))
+	}
+	if clientRouteHeaders != "" {
+		routes, err := crawler.LoadRouteHeaders(clientRouteHeaders)
+		if err != nil {
+			return err
+		}
+		options = append(options, crawler.WithRouteHeaders(routes...))
+	}
+
+	globalClientOptions = options
+	return nil
+}
+
+// siteBaseURL 返回 --base-url 指定的网站地址，未指定时为默认地址，末尾不带 "/"
+// 结果中的链接总是基于该地址，即使请求因为 --mirror 切换到了镜像
+func siteBaseURL() string {
+	return crawler.NewClient(crawler.WithBaseURL(clientBaseURL)).GetBaseURL()
+}
+
+// adaptiveThrottleMinDelay 返回自适应限速的请求间隔下限
+// 指定了 --rate-limit 时与其间隔相同，否则返回0，使用限速器的默认下限
+func adaptiveThrottleMinDelay() time.Duration {
+	if clientRateLimit > 0 {
+		return time.Duration(float64(time.Second) / clientRateLimit)
+	}
+	return 0
+}
+
+// validateProxyURL 检查 --proxy 和 --proxy-pool 指定的代理地址
+func validateProxyURL(s string) error {
+	proxy, err := url.Parse(s)
+	if err != nil || proxy.Host == "" {
+		return fmt.Errorf(T("无效的代理地址 %q，例如 http://127.0.0.1:8080 或 socks5://127.0.0.1:1080"), s)
+	}
+	switch proxy.Scheme {
+	case "http", "https", "socks5", "socks5h":
+		return nil
+	}
+	return fmt.Errorf(T("不支持的代理协议 %q，可选值：http、https、socks5"), proxy.Scheme)
+}
+
+// validateSiteURL 检查 --base-url 和 --mirror 指定的网站地址
+func validateSiteURL(s string) error {
+	base, err := url.Parse(s)
+	if err != nil || base.Host == "" || (base.Scheme != "http" && base.Scheme != "https") {
+		return fmt.Errorf(T("无效的网站地址 %q，例如 https://cxsecurity.com"), s)
+	}
+	return nil
+}
+
+// parseHeader 解析 "名称: 值" 格式的请求头
+func parseHeader(h string) (string, string, error) {
+	key, value, ok := strings.Cut(h, ":")
+	if !ok || strings.TrimSpace(key) == "" {
+		return "", "", fmt.Errorf(T("无效的请求头: %s，格式应为 \"名称: 值\""), h)
+	}
+	return strings.TrimSpace(key), strings.TrimSpace(value), nil
+}
+
+// clientOptions 返回应用了全局标志的HTTP客户端选项，extra追加在全局选项之后
+// crawler.WithClientOptions 会重新创建客户端，需要额外选项的命令应通过这里合并全局选项
+func clientOptions(extra ...crawler.ClientOption) []crawler.ClientOption {
+	options := make([]crawler.ClientOption, 0, len(globalClientOptions)+len(extra))
+	options = append(options, globalClientOptions...)
+	return append(options, extra...)
+}
+
+func init() {
+	rootCmd.PersistentFlags().DurationVar(&clientTimeout, "timeout", 30*time.Second, T("每个HTTP请求的超时时间"))
+	rootCmd.PersistentFlags().IntVar(&clientRetries, "retries", 3, T("请求失败时的最大重试次数，0表示不重试"))
+	rootCmd.PersistentFlags().StringVar(&clientProxy, "proxy", "", T("HTTP或SOCKS5代理地址，默认使用 HTTPS_PROXY 等环境变量"))
+	rootCmd.PersistentFlags().StringArrayVar(&clientProxyPool, "proxy-pool", nil, T("轮换使用的代理地址，可以多次指定，连续失败的代理会被暂时剔除；不能与 --proxy 同时使用"))
+	rootCmd.PersistentFlags().StringVar(&clientProxyRotation, "proxy-rotation", "round-robin", T("--proxy-pool 的轮换策略：round-robin(依次使用)、random(随机)、sticky(同一站点固定使用一个代理)"))
+	rootCmd.PersistentFlags().Float64Var(&clientRateLimit, "rate-limit", 0, T("每秒最多发送的请求数，例如 0.5 表示每2秒一个请求，0表示不限速"))
+	rootCmd.PersistentFlags().BoolVar(&clientNoAdaptiveThrottle, "no-adaptive-throttle", false, T("不根据429/503响应和响应时间突增自动放慢请求速率"))
+	rootCmd.PersistentFlags().IntVar(&clientBudget, "max-requests", 0, T("最多发送的请求数(包括重试)，用完后停止请求，0表示不限制"))
+	rootCmd.PersistentFlags().StringVar(&clientBaseURL, "base-url", "", T("cxsecurity的地址，用于访问镜像或 selftest 使用的回放服务，默认为 https://cxsecurity.com"))
+	rootCmd.PersistentFlags().StringArrayVar(&clientMirrors, "mirror", nil, T("--base-url 持续请求失败时依次尝试的镜像地址，可以多次指定，结果中的链接仍然使用 --base-url"))
+	rootCmd.PersistentFlags().DurationVar(&clientMirrorHealth, "mirror-health-interval", 5*time.Minute, T("使用镜像时检查 --base-url 是否恢复的间隔，恢复后切换回来，0表示不检查"))
+	rootCmd.PersistentFlags().StringArrayVar(&clientHeaders, "header", nil, T("添加到每个请求的HTTP头，格式为 \"名称: 值\"，可以多次指定"))
+	rootCmd.PersistentFlags().StringVar(&clientRouteHeaders, "route-headers", "", T("JSON文件，按请求路径前缀添加HTTP头，例如访问漏洞详情时带上列表页的Referer"))
+}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+)
+
+func TestParseClientFlags(t *testing.T) {
+	defer func() {
+		clientTimeout, clientRetries, clientProxy, clientRateLimit, clientHeaders, clientBaseURL = 30*time.Second, 3, "", 0, nil, ""
+		clientBudget, clientMirrors, clientMirrorHealth = 0, nil, 5*time.Minute
+		clientProxyPool, clientProxyRotation = nil, "round-robin"
+		globalClientOptions = nil
+	}()
+
+	// 通过代理收到的CONNECT请求确认代理和重试次数生效
+	var connects []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodConnect {
+			connects = append(connects, r.Host)
+		}
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer proxy.Close()
+
+	clientTimeout, clientRetries, clientProxy, clientRateLimit = 5*time.Second, 1, proxy.URL, 100
+	clientHeaders = []string{"X-Team: red", "Cookie: a=b"}
+	require.NoError(t, parseClientFlags())
+	assert.Len(t, globalClientOptions, 9)
+	_, err := crawler.NewClient(clientOptions(crawler.WithRetry(-1, time.Millisecond))...).GetPage("/")
+	assert.Error(t, err)
+	assert.Equal(t, []string{"cxsecurity.com:443", "cxsecurity.com:443"}, connects, "重试1次共请求2次")
+
+	for name, set := range map[string]func(){
+		"超时":       func() { clientTimeout = 0 },
+		"重试次数":     func() { clientRetries = -1 },
+		"速率":       func() { clientRateLimit = -1 },
+		"请求预算":     func() { clientBudget = -1 },
+		"代理地址":     func() { clientProxy = "127.0.0.1:8080" },
+		"代理协议":     func() { clientProxy = "ftp://127.0.0.1" },
+		"请求头":      func() { clientHeaders = []string{"X-Team"} },
+		"网站地址":     func() { clientBaseURL = "cxsecurity.com" },
+		"镜像地址":     func() { clientMirrors = []string{"https://mirror.example.org", "ftp://mirror.example.org"} },
+		"镜像健康检查间隔": func() { clientMirrorHealth = -time.Second },
+		"代理池":      func() { clientProxyPool = []string{"http://127.0.0.1:8080"} },
+		"代理池地址":    func() { clientProxy, clientProxyPool = "", []string{"http://127.0.0.1:8080", "127.0.0.1:8081"} },
+		"代理轮换策略": func() {
+			clientProxy, clientProxyPool, clientProxyRotation = "", []string{"http://127.0.0.1:8080"}, "least-used"
+		},
+	} {
+		clientTimeout, clientRetries, clientProxy, clientRateLimit, clientHeaders, clientBaseURL = time.Second, 0, "socks5://127.0.0.1:1080", 0, nil, ""
+		clientBudget, clientMirrors, clientMirrorHealth = 0, nil, 5*time.Minute
+		clientProxyPool, clientProxyRotation = nil, "round-robin"
+		require.NoError(t, parseClientFlags())
+		set()
+		assert.Error(t, parseClientFlags(), "应拒绝无效的%s", name)
+	}
+
+	// --base-url 将请求发送到指定的地址
+	var paths []string
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+	}))
+	defer mirror.Close()
+	clientTimeout, clientRetries, clientProxy, clientRateLimit, clientHeaders, clientBaseURL = time.Second, 0, "", 0, nil, mirror.URL+"/"
+	clientBudget, clientMirrors, clientMirrorHealth = 0, nil, 5*time.Minute
+	clientProxyPool, clientProxyRotation = nil, "round-robin"
+	require.NoError(t, parseClientFlags())
+	_, err = crawler.NewClient(clientOptions()...).GetPage("/exploit/1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/exploit/1"}, paths)
+
+	// --base-url 不可用时使用 --mirror，结果中的链接仍然使用 --base-url
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer down.Close()
+	clientBaseURL, clientMirrors = down.URL, []string{mirror.URL}
+	require.NoError(t, parseClientFlags())
+	client := crawler.NewClient(clientOptions()...)
+	_, err = client.GetPage("/exploit/2")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/exploit/1", "/exploit/2"}, paths)
+	assert.Equal(t, down.URL, client.GetBaseURL())
+	assert.Equal(t, mirror.URL, client.Stats().BaseURL)
+
+	// --mirror-health-interval 到期后检查 --base-url，恢复后切换回来
+	broken := true
+	flaky := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if broken {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer flaky.Close()
+	clientBaseURL, clientMirrorHealth = flaky.URL, time.Nanosecond
+	require.NoError(t, parseClientFlags())
+	client = crawler.NewClient(clientOptions()...)
+	_, err = client.GetPage("/exploit/3")
+	require.NoError(t, err)
+	assert.Equal(t, mirror.URL, client.Stats().BaseURL)
+	broken = false
+	_, err = client.GetPage("/exploit/4")
+	require.NoError(t, err)
+	assert.Equal(t, flaky.URL, client.Stats().BaseURL)
+	assert.Equal(t, 2, client.Stats().Failovers)
+}
+
+func TestAdaptiveThrottleFlag(t *testing.T) {
+	defer func() {
+		clientTimeout, clientRetries, clientRateLimit, clientNoAdaptiveThrottle = 30*time.Second, 3, 0, false
+		globalClientOptions = nil
+	}()
+	clientTimeout, clientRetries, clientProxy, clientHeaders, clientBaseURL = time.Second, 0, "", nil, ""
+	clientBudget, clientMirrors, clientMirrorHealth = 0, nil, 5*time.Minute
+	clientProxyPool, clientProxyRotation = nil, "round-robin"
+
+	// 默认启用，列表页和详情页等所有爬取都经过自适应限速
+	clientRateLimit, clientNoAdaptiveThrottle = 0, false
+	require.NoError(t, parseClientFlags())
+	stats := newCrawler().Stats()
+	assert.True(t, stats.Adaptive)
+	assert.Equal(t, 200*time.Millisecond, stats.Throttle.Delay)
+
+	// 请求间隔下限与 --rate-limit 相同
+	clientRateLimit = 2
+	require.NoError(t, parseClientFlags())
+	assert.Equal(t, 500*time.Millisecond, newCrawler().Stats().Throttle.Delay)
+
+	clientNoAdaptiveThrottle = true
+	require.NoError(t, parseClientFlags())
+	assert.False(t, newCrawler().Stats().Adaptive)
+}
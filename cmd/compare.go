@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+var (
+	compareOutputFile string
+	compareIgnore     []string
+)
+
+var compareCmd = &cobra.Command{
+	Use:   "compare <snapshotA> <snapshotB>",
+	Short: T("比较两次保存的结果，列出新增、删除和变化的公告"),
+	Long: T(`比较两个快照中的漏洞，用于审计两次归档之间发生了什么变化。
+快照可以是JSON结果文件(包括本地数据库文件)、通配符模式或目录，目录中的所有JSON文件作为一个快照。
+漏洞按ID匹配，没有ID时按URL匹配；变化的公告会列出值不同的字段。
+使用 --ignore 忽略每次都会变化的字段，例如 views、comments。`),
+	Example: `  cxcrawler compare archive/2024-05-01 archive/2024-06-01
+  cxcrawler compare old.json new.json --ignore views,comments -o diff.json`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		old, err := loadSnapshot(args[0])
+		if err != nil {
+			return err
+		}
+		cur, err := loadSnapshot(args[1])
+		if err != nil {
+			return err
+		}
+
+		diff := crawler.CompareSnapshots(old, cur, compareIgnore...)
+		if compareOutputFile != "" {
+			if err := newCrawler().SaveJSON(diff, compareOutputFile); err != nil {
+				return fmt.Errorf(T("保存结果失败: %v"), err)
+			}
+		}
+
+		if printFormatted(diff) || quietOutput {
+			return nil
+		}
+		printSnapshotDiff(diff)
+		if compareOutputFile != "" {
+			fmt.Printf("\n%s %s\n",
+				text.Colors{text.FgHiGreen}.Sprint(T("✅ 已保存:")),
+				text.Colors{text.FgHiCyan, text.Underline}.Sprint(compareOutputFile))
+		}
+		return nil
+	},
+}
+
+// loadSnapshot 读取一个快照中的漏洞
+// 参数可以是结果文件、通配符模式或目录，目录中的JSON文件(包括子目录)都会被读取，
+// 保存结果时一起生成的 .provenance.json 不是结果文件，会被跳过
+func loadSnapshot(arg string) ([]model.Vulnerability, error) {
+	var paths []string
+	if info, err := os.Stat(arg); err == nil && info.IsDir() {
+		err := filepath.WalkDir(arg, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && strings.HasSuffix(path, ".json") && !strings.HasSuffix(path, ".provenance.json") {
+				paths = append(paths, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf(T("读取目录 %s 失败: %v"), arg, err)
+		}
+	} else {
+		if paths, err = filepath.Glob(arg); err != nil {
+			return nil, fmt.Errorf(T("无效的文件模式 %s: %v"), arg, err)
+		}
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf(T("没有匹配 %s 的文件"), arg)
+	}
+
+	var items []model.Vulnerability
+	for _, path := range paths {
+		loaded, err := crawler.LoadResultFile(path)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, loaded...)
+	}
+	return items, nil
+}
+
+// changedFieldNames 返回变化的字段名，用逗号分隔
+func changedFieldNames(change crawler.VulnerabilityChange) string {
+	names := make([]string, len(change.Fields))
+	for i, field := range change.Fields {
+		names[i] = field.Field
+	}
+	return strings.Join(names, ", ")
+}
+
+// printSnapshotDiff 以表格显示两个快照的差异
+func printSnapshotDiff(diff *crawler.SnapshotDiff) {
+	fmt.Printf("\n%s %s\n",
+		text.Colors{text.Bold, text.FgHiGreen}.Sprint(T("🔍 快照差异:")),
+		text.Colors{text.Bold, text.FgHiWhite}.Sprintf(T("新增 %d，删除 %d，变化 %d，未变 %d"),
+			len(diff.Added), len(diff.Removed), len(diff.Changed), diff.Unchanged))
+	if len(diff.Added)+len(diff.Removed)+len(diff.Changed) == 0 {
+		return
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(tableStyle(table.StyleRounded))
+	t.AppendHeader(table.Row{T("状态"), "ID", T("标题"), T("变化的字段")})
+	id := func(id, url string) string {
+		if id == "" {
+			id = url
+		}
+		return text.Colors{text.FgHiCyan}.Sprint(id)
+	}
+	for _, item := range diff.Added {
+		t.AppendRow(table.Row{text.Colors{text.FgHiGreen}.Sprint(T("新增")), id(item.ID, item.URL), truncateCell(item.Title, 60), ""})
+	}
+	for _, item := range diff.Removed {
+		t.AppendRow(table.Row{text.Colors{text.FgHiRed}.Sprint(T("删除")), id(item.ID, item.URL), truncateCell(item.Title, 60), ""})
+	}
+	for _, change := range diff.Changed {
+		t.AppendRow(table.Row{text.Colors{text.FgHiYellow}.Sprint(T("变化")), id(change.ID, change.URL), truncateCell(change.Title, 60), changedFieldNames(change)})
+	}
+	t.Render()
+}
+
+func init() {
+	rootCmd.AddCommand(compareCmd)
+
+	compareCmd.Flags().StringVarP(&compareOutputFile, "output", "o", "", T("将差异保存为JSON文件"))
+	compareCmd.Flags().StringSliceVar(&compareIgnore, "ignore", nil, T("不参与比较的字段(JSON字段名，可用逗号分隔)，例如 views,comments"))
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "2024-05"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.json"), []byte(`[{"id":"WLB-2024050001"}]`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "2024-05", "b.json"), []byte(`{"items":[{"id":"WLB-2024050002"}]}`), 0644))
+	// 字段来源调试文件不是结果文件
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.provenance.json"), []byte(`{"title":"h1"}`), 0644))
+
+	items, err := loadSnapshot(dir)
+	require.NoError(t, err)
+	assert.Len(t, items, 2)
+
+	items, err = loadSnapshot(filepath.Join(dir, "a*.json"))
+	require.NoError(t, err)
+	assert.Len(t, items, 1)
+
+	_, err = loadSnapshot(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/config"
+)
+
+var (
+	configDetailedExitCode bool
+	configAutoApprove      bool
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: T("以声明式配置文件管理本地配置"),
+	Long: T(`使用与 bundle export 相同格式的YAML文件声明完整的配置：关注列表、保存的搜索、通知设置和保留策略。
+plan 校验配置文件并显示与本地配置的差异，apply 使本地配置与配置文件一致。
+与 bundle import 合并配置不同，配置文件中没有的关注产品、搜索、通知设置和保留策略会被删除。`),
+}
+
+var configPlanCmd = &cobra.Command{
+	Use:   "plan <file.yaml>",
+	Short: T("显示应用配置文件将要进行的变更"),
+	Example: `  cxcrawler config plan cxcrawler.yaml
+  cxcrawler config plan cxcrawler.yaml --detailed-exitcode`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		plan, err := loadConfigPlan(args[0])
+		if err != nil {
+			return err
+		}
+		if !printFormatted(plan) && !quietOutput {
+			printConfigPlan(plan)
+		}
+		if configDetailedExitCode && !plan.Empty() {
+			os.Exit(2)
+		}
+		return nil
+	},
+}
+
+var configApplyCmd = &cobra.Command{
+	Use:   "apply <file.yaml>",
+	Short: T("使本地配置与配置文件一致"),
+	Example: `  cxcrawler config apply cxcrawler.yaml
+  cxcrawler config apply cxcrawler.yaml --auto-approve`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		plan, err := loadConfigPlan(args[0])
+		if err != nil {
+			return err
+		}
+		printConfigPlan(plan)
+		if plan.Empty() {
+			return nil
+		}
+		if !configAutoApprove {
+			fmt.Print(T("是否应用以上变更？只有输入 yes 才会继续: "))
+			answer, _ := stdinReader.ReadString('\n')
+			if strings.TrimSpace(answer) != "yes" {
+				fmt.Println(T("已取消，没有修改任何配置"))
+				return nil
+			}
+		}
+		if err := plan.Apply(time.Now()); err != nil {
+			return err
+		}
+		fmt.Println(text.Colors{text.FgHiGreen}.Sprint(T("✅ 已应用配置")))
+		return nil
+	},
+}
+
+// loadConfigPlan 读取并校验配置文件，与本地配置比较
+func loadConfigPlan(path string) (*config.Plan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf(T("读取配置文件失败: %w"), err)
+	}
+	desired, err := config.ParseBundle(data)
+	if err != nil {
+		return nil, err
+	}
+	return config.PlanBundle(desired, config.BundlePaths{})
+}
+
+// printConfigPlan 以 + ~ - 标记输出每项变更和变化的字段
+func printConfigPlan(plan *config.Plan) {
+	if plan.Empty() {
+		fmt.Println(text.Colors{text.FgHiGreen}.Sprint(T("本地配置与配置文件一致，没有需要进行的变更")))
+		return
+	}
+
+	kinds := map[string]string{
+		"watchlist":     T("关注的产品"),
+		"saved_search":  T("保存的搜索"),
+		"notifications": T("通知设置"),
+		"retention":     T("保留策略"),
+	}
+	counts := make(map[string]int)
+	for _, c := range plan.Changes {
+		counts[c.Action]++
+		var mark string
+		var color text.Colors
+		switch c.Action {
+		case config.PlanCreate:
+			mark, color = "+", text.Colors{text.FgHiGreen}
+		case config.PlanUpdate:
+			mark, color = "~", text.Colors{text.FgHiYellow}
+		default:
+			mark, color = "-", text.Colors{text.FgHiRed}
+		}
+		fmt.Printf("  %s %s %s\n", color.Sprint(mark), kinds[c.Kind], text.Colors{text.Bold}.Sprint(c.Name))
+		for _, line := range c.Diff {
+			fmt.Printf("      %s\n", text.Colors{text.FgHiBlack}.Sprint(line))
+		}
+	}
+	fmt.Printf("\n"+T("计划: 添加 %d 项，修改 %d 项，删除 %d 项")+"\n",
+		counts[config.PlanCreate], counts[config.PlanUpdate], counts[config.PlanDelete])
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configPlanCmd, configApplyCmd)
+
+	configPlanCmd.Flags().BoolVar(&configDetailedExitCode, "detailed-exitcode", false, T("有变更时以退出码2退出，没有变更时为0，用于检测配置漂移"))
+	configApplyCmd.Flags().BoolVar(&configAutoApprove, "auto-approve", false, T("不询问确认，直接应用变更"))
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/config"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/logging"
+)
+
+func TestDefectDojoTokenRedacted(t *testing.T) {
+	t.Setenv(config.DirEnv, t.TempDir())
+	t.Setenv("DEFECTDOJO_URL", "")
+	t.Setenv("DEFECTDOJO_TOKEN", "dd-token-5f2c9a7e41b3")
+	defer func() { defectDojoURL, defectDojoToken = "", "" }()
+
+	// 缺少地址时报错，但环境变量中的Token已经注册为敏感值
+	assert.Error(t, defectDojoCmd.RunE(defectDojoCmd, nil))
+	assert.NotContains(t, logging.Redact("401 Unauthorized: dd-token-5f2c9a7e41b3"), "dd-token-5f2c9a7e41b3")
+}
+
+func TestJiraTokenRedacted(t *testing.T) {
+	t.Setenv(config.DirEnv, t.TempDir())
+	t.Setenv("JIRA_URL", "")
+	t.Setenv("JIRA_TOKEN", "jira-token-8d41e6b0c2")
+	defer func() { jiraURL, jiraUser, jiraToken = "", "", "" }()
+
+	assert.Error(t, jiraCmd.RunE(jiraCmd, nil))
+	assert.NotContains(t, logging.Redact("401 Unauthorized: jira-token-8d41e6b0c2"), "jira-token-8d41e6b0c2")
+}
+
+func TestPageKeysRedacted(t *testing.T) {
+	t.Setenv(config.DirEnv, t.TempDir())
+	t.Setenv("PAGERDUTY_ROUTING_KEY", "pd-routing-3e9a71c5f0")
+	t.Setenv("OPSGENIE_API_KEY", "og-key-b27d04e8a6")
+	defer func() { pagePagerDutyKey, pageOpsgenieKey = "", "" }()
+
+	// 没有关注的产品时报错，但两个密钥已经注册为敏感值
+	assert.Error(t, pageCmd.RunE(pageCmd, []string{"missing.json"}))
+	redacted := logging.Redact("pd-routing-3e9a71c5f0 og-key-b27d04e8a6")
+	assert.NotContains(t, redacted, "pd-routing-3e9a71c5f0")
+	assert.NotContains(t, redacted, "og-key-b27d04e8a6")
+}
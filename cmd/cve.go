@@ -2,14 +2,13 @@ package cmd
 
 import (
 	"fmt"
-	"os"
 	"strings"
 
 	"github.com/jedib0t/go-pretty/v6/text"
 	"github.com/spf13/cobra"
-	"golang.org/x/term"
 
 	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/export"
 	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
 )
 
@@ -17,50 +16,87 @@ var (
 	cveOutputFile string
 	cveFields     string
 	cveID         string
+	cveRecord     bool
+	cveCSAF       bool
+	cveRelated    int
 )
 
 var cveCmd = &cobra.Command{
 	Use:   "cve",
-	Short: "爬取CVE详情",
-	Long:  `爬取CXSecurity网站的CVE详情页面，并将结果保存为JSON格式`,
+	Short: T("爬取CVE详情"),
+	Long:  T(`爬取CXSecurity网站的CVE详情页面，并将结果保存为JSON格式`),
 	Run: func(cmd *cobra.Command, args []string) {
 		// 创建爬虫实例
-		c := crawler.NewCrawler()
+		c := newCrawler(crawler.WithRelatedPages(cveRelated))
 
 		// 执行爬取
 		if cveID != "" {
-			result, err := c.CrawlCveDetail(cveID, cveOutputFile)
+			if cveRecord && cveCSAF {
+				cmd.PrintErr(T("--cve5 和 --csaf 不能同时使用"))
+				return
+			}
+			outputPath := cveOutputFile
+			if cveRecord || cveCSAF {
+				outputPath = ""
+			}
+			result, err := c.CrawlCveDetail(cveID, outputPath)
 			if err != nil {
-				cmd.PrintErr("爬取失败: ", err)
+				cmd.PrintErr(T("爬取失败: "), err)
+				return
+			}
+
+			// 转换为CVE JSON 5.0记录或CSAF 2.0文档后保存和输出
+			if cveRecord || cveCSAF {
+				var record any
+				if cveRecord {
+					record, err = export.ToCVERecord(result)
+				} else {
+					record, err = export.ToCSAF(result, export.CSAFOptions{})
+				}
+				if err != nil {
+					cmd.PrintErr(T("转换失败: "), err)
+					return
+				}
+				if cveOutputFile != "" {
+					if err := c.SaveJSON(record, cveOutputFile); err != nil {
+						cmd.PrintErr(T("保存结果失败: "), err)
+						return
+					}
+				}
+				if !printFormatted(record) && !quietOutput {
+					printCveResult(result, cveOutputFile)
+				}
+				handleOpenAndCopy(result)
 				return
 			}
 
 			// 打印详细信息
-			printCveResult(result, cveOutputFile)
+			if !printFormatted(result) && !quietOutput {
+				printCveResult(result, cveOutputFile)
+			}
+			handleOpenAndCopy(result)
 		} else {
-			cmd.PrintErr("请指定CVE编号")
+			cmd.PrintErr(T("请指定CVE编号"))
 		}
 	},
 }
 
 // printCveResult 美化输出CVE详情
 func printCveResult(result *model.CveDetail, outputPath string) {
-	// 获取终端宽度
-	width, _, err := term.GetSize(int(os.Stdout.Fd()))
-	if err != nil {
-		// 如果获取失败，使用默认宽度
-		width = 80
-	}
+	// 获取终端宽度，获取失败时使用默认宽度
+	width := terminalWidth(80)
 
 	// 计算边框和内容宽度
-	borderWidth := width - 2               // 两侧各减1个字符给边框
-	titlePadding := (borderWidth - 12) / 2 // "CVE详情信息"是6个汉字(12个字符宽度)，两侧填充
+	borderWidth := width - 2 // 两侧各减1个字符给边框
+	title := T("CVE详情信息")
+	titleWidth := stringDisplayWidth(title)
+	titlePadding := (borderWidth - titleWidth) / 2 // 标题两侧填充
 
 	// 构建顶部边框
-	topBorder := "┏" + strings.Repeat("━", borderWidth) + "┓"
-	titleLine := "┃" + strings.Repeat(" ", titlePadding) + "CVE详情信息" + strings.Repeat(" ", borderWidth-titlePadding-12) + "┃"
-	middleBorder := "┣" + strings.Repeat("━", borderWidth) + "┫"
-	bottomBorder := "┗" + strings.Repeat("━", borderWidth) + "┛"
+	topBorder := boxText("┏" + strings.Repeat("━", borderWidth) + "┓")
+	titleLine := boxText("┃" + strings.Repeat(" ", titlePadding) + title + strings.Repeat(" ", borderWidth-titlePadding-titleWidth) + "┃")
+	middleBorder := boxText("┣" + strings.Repeat("━", borderWidth) + "┫")
+	bottomBorder := boxText("┗" + strings.Repeat("━", borderWidth) + "┛")
 
 	// 显示表头
 	fmt.Println()
@@ -93,29 +129,30 @@ func printCveResult(result *model.CveDetail, outputPath string) {
 		}
 
 		// 输出行，确保右边框对齐
-		fmt.Printf("┃ %s: %s%s ┃\n", labelText, valueText, strings.Repeat(" ", padding))
+		fmt.Printf(boxText("┃ %s: %s%s ┃\n"), labelText, valueText, strings.Repeat(" ", padding))
 	}
 
 	// 输出CVE基本信息
-	printLine("CVE编号", result.CveID, text.FgHiYellow)
-	printLine("发布日期", result.Published.Format("2006-01-02"))
+	printLine(T("CVE编号"), result.CveID, text.FgHiYellow)
+	printLine(T("发布日期"), formatDate(result.Published))
 	if !result.Modified.IsZero() {
-		printLine("修改日期", result.Modified.Format("2006-01-02"))
+		printLine(T("修改日期"), formatDate(result.Modified))
 	}
 
 	// 输出描述信息（可能很长，需要进行分行处理）
 	if result.Description != "" {
 		// 截断字符串，超出部分用省略号代替
-		description := result.Description
-		if len(description) > contentWidth-10 { // 10是"漏洞描述: "的宽度
-			description = description[:contentWidth-13] + "..."
-		}
-		printLine("漏洞描述", description)
+		label := T("漏洞描述")
+		description := truncateWidth(result.Description, contentWidth-stringDisplayWidth(label)-2)
+		printLine(label, description)
 	}
 
 	// 输出CVE类型
 	if result.Type != "" {
-		printLine("漏洞类型", result.Type, text.FgHiGreen)
+		printLine(T("漏洞类型"), result.Type, text.FgHiGreen)
+	}
+	if result.CweURL != "" {
+		printLine(T("CWE链接"), result.CweURL, text.FgBlue)
 	}
 
 	// 输出CVSS评分
@@ -127,45 +164,61 @@ func printCveResult(result *model.CveDetail, outputPath string) {
 		} else if result.CvssBaseScore >= 4.0 {
 			scoreColor = text.FgYellow
 		}
-		printLine("CVSS评分", fmt.Sprintf("%.1f/10", result.CvssBaseScore), scoreColor, text.Bold)
+		printLine(T("CVSS评分"), fmt.Sprintf("%.1f/10", result.CvssBaseScore), scoreColor, text.Bold)
 	}
 
 	if result.CvssImpactScore > 0 {
-		printLine("影响评分", fmt.Sprintf("%.1f", result.CvssImpactScore))
+		printLine(T("影响评分"), fmt.Sprintf("%.1f", result.CvssImpactScore))
 	}
 
 	if result.CvssExploitScore > 0 {
-		printLine("利用评分", fmt.Sprintf("%.1f", result.CvssExploitScore))
+		printLine(T("利用评分"), fmt.Sprintf("%.1f", result.CvssExploitScore))
+	}
+
+	// 输出CVSS v3评分（页面提供时）
+	if v3 := result.Cvss3; v3 != nil {
+		if v3.BaseScore > 0 {
+			scoreColor := text.FgGreen
+			if v3.BaseScore >= 7.0 {
+				scoreColor = text.FgRed
+			} else if v3.BaseScore >= 4.0 {
+				scoreColor = text.FgYellow
+			}
+			printLine(T("CVSS v3评分"), fmt.Sprintf("%.1f/10 (%s)", v3.BaseScore, v3.Severity), scoreColor, text.Bold)
+		}
+		if v3.Vector != "" {
+			printLine(T("CVSS v3向量"), v3.Vector)
+		}
 	}
 
 	// 输出漏洞特性信息
 	if result.ExploitRange != "" {
-		printLine("利用范围", result.ExploitRange, text.FgHiCyan)
+		printLine(T("利用范围"), result.ExploitRange, text.FgHiCyan)
 	}
 
 	if result.AttackComplexity != "" {
-		printLine("攻击复杂度", result.AttackComplexity)
+		printLine(T("攻击复杂度"), result.AttackComplexity)
 	}
 
 	if result.Authentication != "" {
-		printLine("认证需求", result.Authentication)
+		printLine(T("认证需求"), result.Authentication)
 	}
 
 	if result.ConfidentialityImpact != "" {
-		printLine("机密性影响", result.ConfidentialityImpact)
+		printLine(T("机密性影响"), result.ConfidentialityImpact)
 	}
 
 	if result.IntegrityImpact != "" {
-		printLine("完整性影响", result.IntegrityImpact)
+		printLine(T("完整性影响"), result.IntegrityImpact)
 	}
 
 	if result.AvailabilityImpact != "" {
-		printLine("可用性影响", result.AvailabilityImpact)
+		printLine(T("可用性影响"), result.AvailabilityImpact)
 	}
 
 	// 输出受影响软件数量
 	if len(result.AffectedSoftware) > 0 {
-		printLine("受影响软件", fmt.Sprintf("%d个", len(result.AffectedSoftware)), text.FgHiMagenta)
+		printLine(T("受影响软件"), fmt.Sprintf(T("%d个"), len(result.AffectedSoftware)), text.FgHiMagenta)
 		// 最多显示前3个
 		showCount := len(result.AffectedSoftware)
 		if showCount > 3 {
@@ -175,17 +228,17 @@ func printCveResult(result *model.CveDetail, outputPath string) {
 		for i := 0; i < showCount; i++ {
 			software := result.AffectedSoftware[i]
 			softwareInfo := fmt.Sprintf("%s %s", software.VendorName, software.ProductName)
-			printLine(fmt.Sprintf("  软件%d", i+1), softwareInfo, text.FgHiMagenta)
+			printLine(fmt.Sprintf(T("  软件%d"), i+1), softwareInfo, text.FgHiMagenta)
 		}
 
 		if len(result.AffectedSoftware) > 3 {
-			printLine("  更多软件", fmt.Sprintf("... 共%d个", len(result.AffectedSoftware)-3))
+			printLine(T("  更多软件"), fmt.Sprintf(T("... 共%d个"), len(result.AffectedSoftware)-3))
 		}
 	}
 
 	// 输出参考链接数量
 	if len(result.References) > 0 {
-		printLine("参考链接", fmt.Sprintf("%d个", len(result.References)), text.FgBlue)
+		printLine(T("参考链接"), fmt.Sprintf(T("%d个"), len(result.References)), text.FgBlue)
 		// 最多显示前2个
 		showCount := len(result.References)
 		if showCount > 2 {
@@ -193,22 +246,24 @@ func printCveResult(result *model.CveDetail, outputPath string) {
 		}
 
 		for i := 0; i < showCount; i++ {
-			reference := result.References[i]
+			label := fmt.Sprintf(T("  链接%d"), i+1)
 			// 截断长URL
-			if len(reference) > contentWidth-15 {
-				reference = reference[:contentWidth-18] + "..."
-			}
-			printLine(fmt.Sprintf("  链接%d", i+1), reference, text.FgBlue)
+			reference := truncateWidth(result.References[i], contentWidth-stringDisplayWidth(label)-2)
+			printLine(label, reference, text.FgBlue)
 		}
 
 		if len(result.References) > 2 {
-			printLine("  更多链接", fmt.Sprintf("... 共%d个", len(result.References)-2))
+			printLine(T("  更多链接"), fmt.Sprintf(T("... 共%d个"), len(result.References)-2))
 		}
 	}
 
 	// 输出相关漏洞数量
 	if len(result.RelatedVulnerabilities) > 0 {
-		printLine("相关漏洞", fmt.Sprintf("%d个", len(result.RelatedVulnerabilities)), text.FgHiWhite)
+		count := fmt.Sprintf(T("%d个"), len(result.RelatedVulnerabilities))
+		if result.RelatedTruncated {
+			count += T("（还有更多，可以调大 --related-pages）")
+		}
+		printLine(T("相关漏洞"), count, text.FgHiWhite)
 	}
 
 	// 输出底部边框
@@ -216,7 +271,7 @@ func printCveResult(result *model.CveDetail, outputPath string) {
 
 	// 显示结果保存信息
 	if outputPath != "" {
-		fmt.Printf("结果已保存到 %s\n", outputPath)
+		fmt.Printf(T("结果已保存到 %s\n"), outputPath)
 	}
 }
 
@@ -224,7 +279,10 @@ func init() {
 	rootCmd.AddCommand(cveCmd)
 
 	// 添加标志
-	cveCmd.Flags().StringVarP(&cveOutputFile, "output", "o", "cve_output.json", "输出文件路径")
-	cveCmd.Flags().StringVarP(&cveID, "id", "i", "", "要爬取的CVE编号，例如：CVE-2007-1411")
-	cveCmd.Flags().StringVarP(&cveFields, "fields", "f", "all", "要输出的字段，用逗号分隔，或使用'all'获取所有字段")
+	cveCmd.Flags().StringVarP(&cveOutputFile, "output", "o", "cve_output.json", T("输出文件路径"))
+	cveCmd.Flags().StringVarP(&cveID, "id", "i", "", T("要爬取的CVE编号，例如：CVE-2007-1411"))
+	cveCmd.Flags().StringVarP(&cveFields, "fields", "f", "all", T("要输出的字段，用逗号分隔，或使用'all'获取所有字段"))
+	cveCmd.Flags().BoolVar(&cveRecord, "cve5", false, T("以CVE JSON 5.0 (CVE Record Format) 格式保存和输出结果"))
+	cveCmd.Flags().BoolVar(&cveCSAF, "csaf", false, T("以CSAF 2.0安全公告格式保存和输出结果"))
+	cveCmd.Flags().IntVar(&cveRelated, "related-pages", crawler.DefaultRelatedPages, T("相关漏洞较多时最多额外请求的分页数，0表示只获取第一页"))
 }
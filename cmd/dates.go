@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	// 内置时区数据库，没有系统时区数据的环境(例如Windows、精简的容器镜像)也能使用 --tz
+	_ "time/tzdata"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+var (
+	displayTZ         string
+	displayDateFormat string
+
+	// 日期的显示方式，由PersistentPreRunE根据 --tz 和 --date-format 设置
+	displayDates model.DateFormat
+)
+
+// parseDateFlags 解析 --tz 和 --date-format
+func parseDateFlags() error {
+	dates, err := model.ParseDateFormat(displayTZ, displayDateFormat)
+	if err != nil {
+		return fmt.Errorf(T("无效的日期显示方式: %v"), err)
+	}
+	displayDates = dates
+	return nil
+}
+
+// formatDate 按 --tz 和 --date-format 输出表格和报告中的日期，零值输出空字符串
+func formatDate(t time.Time) string {
+	return displayDates.Format(t)
+}
+
+// formatTimestamp 输出本地记录的时间(例如保存搜索、失败记录的时间)
+// 没有指定 --tz 时使用系统时区，格式固定为 2006-01-02 15:04
+func formatTimestamp(t time.Time) string {
+	if displayDates.Location == nil {
+		return t.Local().Format("2006-01-02 15:04")
+	}
+	return t.In(displayDates.Location).Format("2006-01-02 15:04")
+}
+
+// formatSearchDate 输出搜索结果中 2006-01-02 格式的日期，无法解析时(例如 "未知")原样输出
+func formatSearchDate(date string) string {
+	t, err := time.Parse(model.DefaultDateLayout, date)
+	if err != nil {
+		return date
+	}
+	return formatDate(t)
+}
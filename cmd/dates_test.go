@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDateFlags(t *testing.T) {
+	defer func() {
+		displayTZ, displayDateFormat = "", ""
+		require.NoError(t, parseDateFlags())
+	}()
+	ts := time.Date(2024, 3, 24, 22, 30, 0, 0, time.UTC)
+
+	require.NoError(t, parseDateFlags())
+	assert.Equal(t, "2024-03-24", formatDate(ts))
+
+	displayTZ, displayDateFormat = "Asia/Tokyo", "cn"
+	require.NoError(t, parseDateFlags())
+	assert.Equal(t, "2024年03月25日", formatDate(ts))
+	assert.Equal(t, "2024-03-25 07:30", formatTimestamp(ts))
+	assert.Equal(t, "07:30", templateFuncs["date"].(func(string, time.Time) string)("15:04", ts), "模板中的日期也使用 --tz")
+
+	assert.Equal(t, "2024年03月24日", formatSearchDate("2024-03-24"), "日期按UTC零点转换")
+	assert.Equal(t, "未知", formatSearchDate("未知"))
+
+	displayTZ = "Nowhere/Atlantis"
+	assert.Error(t, parseDateFlags())
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/logging"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/sink"
+)
+
+var (
+	defectDojoURL        string
+	defectDojoToken      string
+	defectDojoEngagement int
+	defectDojoTest       int
+	defectDojoTestTitle  string
+)
+
+var defectDojoCmd = &cobra.Command{
+	Use:   "defectdojo <file.json...>",
+	Short: T("将保存的结果推送到DefectDojo"),
+	Long: T(`读取之前保存的JSON结果文件(exploit、list、search、author等命令的输出)，
+以Generic Findings Import格式推送到DefectDojo。
+使用 --engagement 在指定engagement下新建test，使用 --test 重新导入到已有的test(按漏洞ID去重)。
+地址和Token也可以通过环境变量 DEFECTDOJO_URL 和 DEFECTDOJO_TOKEN 设置，
+地址、engagement和test标题的默认值可以保存在配置目录下的 notifications.json 中。`),
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if defectDojoURL == "" {
+			defectDojoURL = os.Getenv("DEFECTDOJO_URL")
+		}
+		if defectDojoToken == "" {
+			defectDojoToken = os.Getenv("DEFECTDOJO_TOKEN")
+		}
+		logging.AddSecret(defectDojoToken)
+		if err := applyDefectDojoDefaults(cmd); err != nil {
+			return err
+		}
+		if defectDojoURL == "" || defectDojoToken == "" {
+			return errors.New(T("请指定DefectDojo地址和Token"))
+		}
+		if defectDojoEngagement <= 0 && defectDojoTest <= 0 {
+			return errors.New(T("请使用 --engagement 或 --test 指定导入位置"))
+		}
+
+		var items []model.Vulnerability
+		for _, path := range args {
+			loaded, err := crawler.LoadResultFile(path)
+			if err != nil {
+				return err
+			}
+			items = append(items, loaded...)
+		}
+
+		dd := sink.NewDefectDojo(defectDojoURL, defectDojoToken,
+			sink.WithDefectDojoEngagement(defectDojoEngagement),
+			sink.WithDefectDojoTest(defectDojoTest),
+			sink.WithDefectDojoTestTitle(defectDojoTestTitle))
+		result, err := dd.Push(items)
+		if err != nil {
+			return fmt.Errorf(T("推送失败: %v"), err)
+		}
+
+		if printFormatted(result) || quietOutput {
+			return nil
+		}
+		fmt.Printf("%s %s\n",
+			text.Colors{text.FgHiGreen}.Sprintf(T("✅ 已推送 %d 条发现到DefectDojo"), result.Findings),
+			text.Colors{text.FgHiBlack}.Sprintf("(test %d)", result.Test))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(defectDojoCmd)
+
+	defectDojoCmd.Flags().StringVar(&defectDojoURL, "url", "", T("DefectDojo地址，例如 https://defectdojo.example.com"))
+	defectDojoCmd.Flags().StringVar(&defectDojoToken, "token", "", T("DefectDojo API v2 Token"))
+	defectDojoCmd.Flags().IntVar(&defectDojoEngagement, "engagement", 0, T("导入到的engagement ID，会新建一个test"))
+	defectDojoCmd.Flags().IntVar(&defectDojoTest, "test", 0, T("重新导入到的test ID，优先于 --engagement"))
+	defectDojoCmd.Flags().StringVar(&defectDojoTestTitle, "test-title", "cxsecurity", T("新建test的标题"))
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/logging"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+// desktopNotifyLimit 是桌面通知正文中最多列出的漏洞数
+const desktopNotifyLimit = 3
+
+// windowsToastAppID 是发送Windows通知使用的应用ID
+// 未注册的应用ID不会显示通知，这里使用系统自带的PowerShell的ID
+const windowsToastAppID = `{1AC14E77-02E7-4E5D-B744-2EB1AE5198B7}\WindowsPowerShell\v1.0\powershell.exe`
+
+var desktopNotifyEnabled bool
+
+// desktopNotify 发送一条桌面通知
+// Linux下使用 notify-send，macOS下使用通知中心，Windows下使用PowerShell发送toast通知。
+// 标题和正文作为参数传递或按PowerShell规则转义，不会被当作脚本执行
+func desktopNotify(title, message string) error {
+	switch runtime.GOOS {
+	case "darwin":
+		return runExternal("osascript", []string{
+			"-e", "on run argv",
+			"-e", "display notification (item 2 of argv) with title (item 1 of argv)",
+			"-e", "end run",
+			title, message,
+		}, "")
+	case "windows":
+		return runExternal("powershell", []string{"-NoProfile", "-NonInteractive", "-Command", "-"}, windowsToastScript(title, message))
+	}
+
+	if _, err := exec.LookPath("notify-send"); err != nil {
+		return errors.New(T("未找到 notify-send，请安装 libnotify"))
+	}
+	return runExternal("notify-send", []string{"--app-name=cxcrawler", "--", title, message}, "")
+}
+
+// windowsToastScript 返回显示toast通知的PowerShell脚本
+func windowsToastScript(title, message string) string {
+	quote := func(s string) string {
+		return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+	}
+	return strings.Join([]string{
+		"[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] | Out-Null",
+		"$template = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)",
+		"$texts = $template.GetElementsByTagName('text')",
+		"$texts.Item(0).AppendChild($template.CreateTextNode(" + quote(title) + ")) | Out-Null",
+		"$texts.Item(1).AppendChild($template.CreateTextNode(" + quote(message) + ")) | Out-Null",
+		"$toast = [Windows.UI.Notifications.ToastNotification]::new($template)",
+		"[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier(" + quote(windowsToastAppID) + ").Show($toast)",
+		"",
+	}, "\n")
+}
+
+// desktopNotifyMessage 返回列出漏洞的通知正文，最多列出 desktopNotifyLimit 条
+func desktopNotifyMessage(items []model.Vulnerability) string {
+	var lines []string
+	for i, v := range items {
+		if i == desktopNotifyLimit {
+			lines = append(lines, fmt.Sprintf(T("以及另外 %d 个"), len(items)-i))
+			break
+		}
+		line := v.Title
+		if v.RiskLevel != "" {
+			line = "[" + v.RiskLevel + "] " + line
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// notifyDesktop 在指定 --desktop-notify 时发送桌面通知
+// 桌面通知只是提醒，发送失败时输出提示，不影响检查结果和状态的保存
+func notifyDesktop(title string, items []model.Vulnerability) {
+	if !desktopNotifyEnabled || len(items) == 0 {
+		return
+	}
+	if err := desktopNotify(title, desktopNotifyMessage(items)); err != nil {
+		logging.Printf(T("发送桌面通知失败: %v")+"\n", err)
+	}
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+func TestDesktopNotifyMessage(t *testing.T) {
+	items := []model.Vulnerability{
+		{Title: "WordPress Plugin XSS", RiskLevel: "Med."},
+		{Title: "nginx RCE", RiskLevel: "High"},
+		{Title: "PHP bug"},
+		{Title: "a"},
+		{Title: "b"},
+	}
+	assert.Equal(t, "[Med.] WordPress Plugin XSS\n[High] nginx RCE\nPHP bug\n以及另外 2 个", desktopNotifyMessage(items))
+	assert.Equal(t, "PHP bug", desktopNotifyMessage(items[2:3]))
+}
+
+func TestWindowsToastScript(t *testing.T) {
+	script := windowsToastScript("it's new", "'; Remove-Item x; '")
+	assert.Contains(t, script, "CreateTextNode('it''s new')")
+	assert.Contains(t, script, "CreateTextNode('''; Remove-Item x; ''')")
+}
+
+func TestNotifyDesktop(t *testing.T) {
+	var calls [][]string
+	oldRun := runExternal
+	runExternal = func(name string, args []string, stdin string) error {
+		calls = append(calls, append([]string{name}, args...))
+		return errors.New("no display")
+	}
+	defer func() {
+		runExternal = oldRun
+		desktopNotifyEnabled = false
+	}()
+
+	items := []model.Vulnerability{{Title: "nginx RCE"}}
+	notifyDesktop("title", items)
+	assert.Empty(t, calls, "未指定 --desktop-notify 时不发送通知")
+
+	desktopNotifyEnabled = true
+	notifyDesktop("title", nil)
+	assert.Empty(t, calls, "没有漏洞时不发送通知")
+
+	// 发送失败只输出提示
+	notifyDesktop("title", items)
+	if runtime.GOOS == "darwin" {
+		if assert.Len(t, calls, 1) {
+			assert.Equal(t, []string{"title", "nginx RCE"}, calls[0][len(calls[0])-2:])
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/export"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+var (
+	digestSince      string
+	digestTop        int
+	digestFormat     string
+	digestOutputFile string
+)
+
+var digestCmd = &cobra.Command{
+	Use:   "digest [file.json...]",
+	Short: T("汇总一段时间内最值得关注的漏洞"),
+	Long: T(`汇总 --since 指定的时间段内发布的漏洞：风险最高的漏洞、被最多公告引用的CVE和发布最多的作者，
+可以输出为终端表格、Markdown或HTML，用于周报等定期报告。
+指定结果文件时从这些文件读取漏洞，否则读取存储(参见 import 命令)。没有发布日期的漏洞不会被统计。`),
+	Example: `  cxcrawler digest --since 7d
+  cxcrawler digest --since 30d --top 20 -f markdown -o weekly.md
+  cxcrawler digest results/*.json -f html -o digest.html`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if digestFormat != "terminal" && digestFormat != "markdown" && digestFormat != "html" {
+			return fmt.Errorf(T("不支持的摘要格式 %q，可选值：terminal、markdown、html"), digestFormat)
+		}
+		window, err := crawler.ParseRetention(digestSince)
+		if err != nil {
+			return err
+		}
+
+		var items []model.Vulnerability
+		if len(args) == 0 {
+			s, err := openStore(storeFile)
+			if err != nil {
+				return err
+			}
+			items = s.All()
+		}
+		for _, path := range args {
+			loaded, err := crawler.LoadResultFile(path)
+			if err != nil {
+				return err
+			}
+			items = append(items, loaded...)
+		}
+
+		now := time.Now()
+		opts := export.DigestOptions{Now: now, Top: digestTop, Dates: displayDates}
+		if window > 0 {
+			opts.Since = now.Add(-window)
+		}
+		d := export.BuildDigest(items, opts)
+
+		if digestFormat == "terminal" {
+			if !printFormatted(d) && !quietOutput {
+				printDigest(d)
+			}
+			return nil
+		}
+		var buf bytes.Buffer
+		if digestFormat == "markdown" {
+			err = d.WriteMarkdown(&buf)
+		} else {
+			err = d.WriteHTML(&buf)
+		}
+		if err != nil {
+			return err
+		}
+		if digestOutputFile == "" {
+			_, err = os.Stdout.Write(buf.Bytes())
+			return err
+		}
+		if err := newCrawler().SaveFile(buf.Bytes(), digestOutputFile); err != nil {
+			return fmt.Errorf(T("保存结果失败: %v"), err)
+		}
+		if quietOutput {
+			return nil
+		}
+		fmt.Printf("%s %s\n",
+			text.Colors{text.FgHiGreen}.Sprint(T("✅ 已保存:")),
+			text.Colors{text.FgHiCyan, text.Underline}.Sprint(digestOutputFile))
+		return nil
+	},
+}
+
+// printDigest 以终端表格输出摘要
+func printDigest(d *export.Digest) {
+	fmt.Printf("%s %s\n", text.Colors{text.FgHiBlue, text.Bold}.Sprint(T("📰 漏洞摘要")), d.Period())
+	fmt.Printf(T("共 %d 个漏洞: 高危 %d，中危 %d，低危 %d")+"\n\n", d.Total, d.ByRisk["High"], d.ByRisk["Med."], d.ByRisk["Low"])
+	if d.Total == 0 {
+		return
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(tableStyle(table.StyleRounded))
+	t.SetTitle(T("风险最高的漏洞"))
+	t.AppendHeader(table.Row{T("风险"), T("日期"), "ID", T("标题"), "CVE", T("作者")})
+	for _, v := range d.TopVulnerabilities {
+		t.AppendRow(table.Row{
+			riskColors(v.RiskLevel).Sprint(v.RiskLevel),
+			formatDate(v.Date),
+			text.Colors{text.FgHiCyan}.Sprint(v.ID),
+			truncateCell(v.Title, 50),
+			truncateCell(v.CVE, 30),
+			v.Author,
+		})
+	}
+	t.Render()
+
+	printDigestCounts(T("被引用最多的CVE"), "CVE", d.TopCVEs)
+	printDigestCounts(T("最活跃的作者"), T("作者"), d.TopAuthors)
+}
+
+// printDigestCounts 以两列表格输出一个排行榜，没有数据时不输出
+func printDigestCounts(title, name string, list []export.DigestCount) {
+	if len(list) == 0 {
+		return
+	}
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(tableStyle(table.StyleRounded))
+	t.SetTitle(title)
+	t.AppendHeader(table.Row{name, T("公告数")})
+	for _, c := range list {
+		t.AppendRow(table.Row{c.Name, c.Count})
+	}
+	t.Render()
+}
+
+func init() {
+	rootCmd.AddCommand(digestCmd)
+
+	digestCmd.Flags().StringVar(&digestSince, "since", "7d", T("统计最近多长时间内发布的漏洞，例如 7d、4w、36h，forever 表示不限制"))
+	digestCmd.Flags().IntVar(&digestTop, "top", 10, T("每个排行榜输出的条数"))
+	digestCmd.Flags().StringVarP(&digestFormat, "format", "f", "terminal", T("输出格式(terminal、markdown或html)"))
+	digestCmd.Flags().StringVarP(&digestOutputFile, "output", "o", "", T("markdown和html格式的输出文件路径，默认输出到标准输出"))
+	digestCmd.Flags().StringVar(&storeFile, "store", "", T("存储文件，默认为配置目录下的 vulnerabilities.json"))
+}
@@ -0,0 +1,339 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/config"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+)
+
+// doctor 检查的结果状态
+const (
+	doctorOK   = "ok"
+	doctorWarn = "warn"
+	doctorFail = "fail"
+	doctorSkip = "skip"
+)
+
+// doctorCheck 是一项诊断的结果
+type doctorCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`           // ok、warn、fail 或 skip
+	Detail string `json:"detail,omitempty"` // 检查到的情况
+	Hint   string `json:"hint,omitempty"`   // 警告或失败时的处理建议
+}
+
+// doctorProxy 返回访问网站使用的代理，--proxy 优先于 HTTPS_PROXY 等环境变量，没有代理时返回nil
+func doctorProxy(site string) (*url.URL, error) {
+	if clientProxy != "" {
+		return url.Parse(clientProxy)
+	}
+	req, err := http.NewRequest(http.MethodGet, site, nil)
+	if err != nil {
+		return nil, err
+	}
+	return http.ProxyFromEnvironment(req)
+}
+
+// checkDNS 解析网站的域名
+// 使用代理时域名可能由代理解析，本地解析失败只作为警告
+func checkDNS(site string, proxy *url.URL) doctorCheck {
+	check := doctorCheck{Name: T("DNS解析")}
+	u, err := url.Parse(site)
+	if err != nil {
+		check.Status, check.Detail = doctorFail, err.Error()
+		return check
+	}
+	if net.ParseIP(u.Hostname()) != nil {
+		check.Status, check.Detail = doctorOK, fmt.Sprintf(T("%s 是IP地址，不需要解析"), u.Hostname())
+		return check
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), clientTimeout)
+	defer cancel()
+	addrs, err := net.DefaultResolver.LookupHost(ctx, u.Hostname())
+	switch {
+	case err == nil:
+		check.Status, check.Detail = doctorOK, fmt.Sprintf("%s -> %v", u.Hostname(), addrs)
+	case proxy != nil:
+		check.Status, check.Detail = doctorWarn, err.Error()
+		check.Hint = T("本地无法解析域名，通过代理访问时可以忽略；否则请检查DNS设置")
+	default:
+		check.Status, check.Detail = doctorFail, err.Error()
+		check.Hint = T("检查网络和DNS设置，或者使用 --proxy 通过代理访问")
+	}
+	return check
+}
+
+// checkProxy 检查代理是否可以连接，没有使用代理时直接通过
+func checkProxy(proxy *url.URL, proxyErr error) doctorCheck {
+	check := doctorCheck{Name: T("代理")}
+	if proxyErr != nil {
+		check.Status, check.Detail = doctorFail, proxyErr.Error()
+		check.Hint = T("检查 --proxy 或 HTTPS_PROXY 等环境变量中的代理地址，例如 http://127.0.0.1:8080")
+		return check
+	}
+	if proxy == nil {
+		check.Status, check.Detail = doctorOK, T("未使用代理")
+		return check
+	}
+	check.Detail = proxy.Redacted()
+	host := proxy.Host
+	if proxy.Port() == "" {
+		port := "80"
+		switch proxy.Scheme {
+		case "https":
+			port = "443"
+		case "socks5", "socks5h":
+			port = "1080"
+		}
+		host = net.JoinHostPort(proxy.Hostname(), port)
+	}
+	conn, err := net.DialTimeout("tcp", host, clientTimeout)
+	if err != nil {
+		check.Status, check.Detail = doctorFail, check.Detail+": "+err.Error()
+		check.Hint = T("代理无法连接，检查代理服务是否运行、地址和端口是否正确")
+		return check
+	}
+	conn.Close()
+	check.Status = doctorOK
+	return check
+}
+
+// checkConnectivity 使用与其他命令相同的HTTP客户端设置请求漏洞列表页，检查是否能访问网站以及是否被拦截
+func checkConnectivity() doctorCheck {
+	check := doctorCheck{Name: T("网站连接")}
+	client := crawler.NewClient(clientOptions(crawler.WithRetry(0, 0))...)
+	start := time.Now()
+	content, err := client.GetPage("/exploit/")
+	elapsed := time.Since(start).Round(time.Millisecond)
+	switch {
+	case errors.Is(err, crawler.ErrBudgetExhausted):
+		check.Status, check.Detail = doctorFail, err.Error()
+		check.Hint = T("--max-requests 太小，诊断至少需要4个请求")
+	case err != nil:
+		check.Status, check.Detail = doctorFail, err.Error()
+		check.Hint = T("检查网络、--proxy 和 --base-url；网站暂时不可用时稍后再试，或使用 --mirror 指定镜像")
+	default:
+		if reason := crawler.BlockPageReason(content); reason != "" {
+			check.Status, check.Detail = doctorFail, fmt.Sprintf(T("请求被网站拦截(%s)"), reason)
+			check.Hint = T("降低 --rate-limit、更换代理或稍后再试")
+			return check
+		}
+		check.Status, check.Detail = doctorOK, fmt.Sprintf(T("%s，耗时 %s"), client.GetBaseURL(), elapsed)
+		if elapsed > clientTimeout/2 {
+			check.Status = doctorWarn
+			check.Hint = T("响应很慢，接近 --timeout，可以增大 --timeout 或使用 --rate-limit 降低请求频率")
+		}
+	}
+	return check
+}
+
+// checkStorage 检查配置目录是否可写，以及漏洞存储和CVE详情存储能否读取
+func checkStorage() []doctorCheck {
+	dirCheck := doctorCheck{Name: T("配置目录")}
+	dir, err := config.Dir()
+	if err == nil {
+		if err = os.MkdirAll(dir, crawler.SecureDirMode); err == nil {
+			var f *os.File
+			if f, err = os.CreateTemp(dir, ".doctor-*"); err == nil {
+				f.Close()
+				os.Remove(f.Name())
+			}
+		}
+	}
+	if err != nil {
+		dirCheck.Status, dirCheck.Detail = doctorFail, err.Error()
+		dirCheck.Hint = fmt.Sprintf(T("配置目录不可写，检查目录权限或设置 %s 指向可写的目录"), config.DirEnv)
+	} else {
+		dirCheck.Status, dirCheck.Detail = doctorOK, dir
+	}
+
+	storeCheck := doctorCheck{Name: T("漏洞存储")}
+	if s, err := openStore(storeFile); err != nil {
+		storeCheck.Status, storeCheck.Detail = doctorFail, err.Error()
+		storeCheck.Hint = T("存储文件无法读取，从备份恢复或移走该文件后用 import 重新导入")
+	} else {
+		storeCheck.Status, storeCheck.Detail = doctorOK, fmt.Sprintf(T("%s，%d 条记录"), s.Path(), s.Len())
+	}
+
+	cveCheck := doctorCheck{Name: T("CVE详情存储")}
+	if s, err := openCVEStore(); err != nil {
+		cveCheck.Status, cveCheck.Detail = doctorFail, err.Error()
+		cveCheck.Hint = T("存储文件无法读取，从备份恢复或移走该文件后用 enrich-cve 重新获取")
+	} else {
+		cveCheck.Status, cveCheck.Detail = doctorOK, fmt.Sprintf(T("%d 条记录"), s.Len())
+	}
+	return []doctorCheck{dirCheck, storeCheck, cveCheck}
+}
+
+// doctorCanary 是一个解析器金丝雀：请求一个内容不会再变化的页面并检查解析结果
+// 网站改版导致选择器失效时，解析通常不会报错，只是字段变为空，金丝雀可以尽早发现这种情况
+type doctorCanary struct {
+	name string
+	run  func(c *crawler.Crawler) error
+}
+
+// doctorCanaries 的期望值与 docs/response-examples 中归档的页面相同
+var doctorCanaries = []doctorCanary{
+	{T("解析器: 漏洞列表"), func(c *crawler.Crawler) error {
+		list, err := c.CrawlExploitList(1, "")
+		if err != nil {
+			return err
+		}
+		if len(list.Items) == 0 || list.Items[0].ID == "" || list.Items[0].Title == "" {
+			return errors.New(T("没有解析出漏洞的ID和标题"))
+		}
+		return nil
+	}},
+	{T("解析器: 漏洞详情"), func(c *crawler.Crawler) error {
+		v, err := c.CrawlVulnerabilityDetail("/issue/WLB-2007030137", "")
+		if err != nil {
+			return err
+		}
+		if v.Title == "" {
+			return errors.New(T("没有解析出标题"))
+		}
+		if err := selftestExpect(v.CVE, "CVE-2007-1475"); err != nil {
+			return err
+		}
+		return selftestExpect(v.Date.Format("2006-01-02"), "2007-03-21")
+	}},
+	{T("解析器: CVE详情"), func(c *crawler.Crawler) error {
+		d, err := c.CrawlCveDetail("CVE-2007-1411", "")
+		if err != nil {
+			return err
+		}
+		if d.Description == "" {
+			return errors.New(T("没有解析出描述"))
+		}
+		return selftestExpect(d.Published.Format("2006-01-02"), "2007-03-10")
+	}},
+}
+
+// checkParserCanaries 依次运行解析器金丝雀，不能访问网站时跳过
+func checkParserCanaries(reachable bool) []doctorCheck {
+	c := newCrawler(forceNetwork(), crawler.WithRelatedPages(0), crawler.WithClientOptions(clientOptions(crawler.WithRetry(0, 0))...))
+	checks := make([]doctorCheck, 0, len(doctorCanaries))
+	for _, canary := range doctorCanaries {
+		check := doctorCheck{Name: canary.name}
+		if !reachable {
+			check.Status, check.Detail = doctorSkip, T("无法访问网站")
+		} else if err := canary.run(c); err != nil {
+			check.Status, check.Detail = doctorFail, err.Error()
+			check.Hint = fmt.Sprintf(T("网站的页面结构可能已经改变(解析规则 %s)，请升级cxcrawler并附上 version -v 的输出报告问题"), crawler.ParserRulesVersion)
+		} else {
+			check.Status = doctorOK
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// runDoctor 依次进行所有诊断
+func runDoctor() []doctorCheck {
+	site := siteBaseURL()
+	var checks []doctorCheck
+	if len(clientProxyPool) > 0 {
+		// 使用代理池时逐个检查代理
+		var proxies []doctorCheck
+		var first *url.URL
+		for _, p := range clientProxyPool {
+			proxy, err := url.Parse(p)
+			if first == nil && err == nil {
+				first = proxy
+			}
+			proxies = append(proxies, checkProxy(proxy, err))
+		}
+		checks = append([]doctorCheck{checkDNS(site, first)}, proxies...)
+	} else {
+		proxy, proxyErr := doctorProxy(site)
+		checks = []doctorCheck{checkDNS(site, proxy), checkProxy(proxy, proxyErr)}
+	}
+	connectivity := checkConnectivity()
+	checks = append(checks, connectivity)
+	checks = append(checks, checkStorage()...)
+	return append(checks, checkParserCanaries(connectivity.Status != doctorFail)...)
+}
+
+// printDoctorChecks 以表格形式输出诊断结果，警告和失败的处理建议显示在表格之后
+func printDoctorChecks(checks []doctorCheck) {
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(tableStyle(table.StyleRounded))
+
+	t.AppendHeader(table.Row{T("检查"), T("结果"), T("说明")})
+	for _, c := range checks {
+		var status string
+		switch c.Status {
+		case doctorOK:
+			status = text.Colors{text.FgHiGreen}.Sprint(T("通过"))
+		case doctorWarn:
+			status = text.Colors{text.FgHiYellow}.Sprint(T("警告"))
+		case doctorSkip:
+			status = text.Colors{text.FgHiBlack}.Sprint(T("跳过"))
+		default:
+			status = text.Colors{text.FgRed, text.Bold}.Sprint(T("失败"))
+		}
+		t.AppendRow(table.Row{c.Name, status, truncateCell(c.Detail, 80)})
+	}
+	t.Render()
+
+	for _, c := range checks {
+		if c.Hint != "" {
+			fmt.Printf("• %s: %s\n", c.Name, c.Hint)
+		}
+	}
+}
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: T("诊断网络、代理、存储和解析器的问题"),
+	Long: T(`依次检查DNS解析、代理、能否访问网站(以及是否被拦截)、配置目录和本地存储，
+再请求几个内容固定的页面检查解析结果是否正确，发现网站改版导致的解析问题。
+使用与其他命令相同的全局选项，例如 --proxy、--base-url、--timeout 和 --header，
+对每个警告和失败给出处理建议。任一检查失败时以非零状态码退出。`),
+	Example: `  cxcrawler doctor
+  cxcrawler doctor --proxy socks5://127.0.0.1:1080
+  cxcrawler doctor --jq '.[] | select(.status == "fail")'`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		checks := runDoctor()
+		if !printFormatted(checks) && !quietOutput {
+			printDoctorChecks(checks)
+		}
+
+		failed, warned := 0, 0
+		for _, c := range checks {
+			switch c.Status {
+			case doctorFail:
+				failed++
+			case doctorWarn:
+				warned++
+			}
+		}
+		if failed > 0 {
+			fmt.Fprintf(os.Stderr, "%s %s\n", text.Colors{text.FgRed, text.Bold}.Sprint(T("❌ 诊断发现问题:")), fmt.Sprintf(T("%d 项检查失败"), failed))
+			exitWithRunStats(1, false)
+		}
+		if warned > 0 {
+			infof("%s\n", text.Colors{text.FgHiYellow}.Sprintf(T("⚠️ 没有失败的检查，%d 项检查有警告"), warned))
+			return
+		}
+		infof("%s\n", text.Colors{text.FgHiGreen}.Sprintf(T("✅ 全部 %d 项检查通过"), len(checks)))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
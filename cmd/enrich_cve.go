@@ -0,0 +1,252 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/logging"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/sink"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/store"
+)
+
+var (
+	cveStoreFile       string
+	enrichLimit        int
+	enrichDelay        time.Duration
+	enrichMaxAttempts  int
+	enrichRelatedPages int
+	enrichDryRun       bool
+	enrichRefresh      time.Duration
+	enrichWebhook      string
+	enrichHeaders      []string
+)
+
+var enrichCveCmd = &cobra.Command{
+	Use:   "enrich-cve",
+	Short: T("为存储中的漏洞补全CVE详情"),
+	Long: T(`遍历存储中引用了CVE编号、但还没有CVE详情的漏洞，逐个爬取CVE详情页面并保存到CVE详情存储。
+请求按 --delay 的间隔进行，遇到429/503时自动放慢。获取失败的CVE会记录下来，下次运行时重试，
+失败达到 --max-attempts 次后不再重试。CVE详情存储默认为配置目录下的 cve_details.json。
+指定 --refresh 后还会重新获取最后一次获取早于该时长的CVE，修改日期、评分、描述等发生变化时
+在引用它的漏洞上记录 cve_update，可以在 sql 中用 cve_updated_at 列查询，
+并通过 --webhook 或 --desktop-notify 发送通知。`),
+	Example: `  cxcrawler enrich-cve --dry-run
+  cxcrawler enrich-cve --limit 50 --delay 2s
+  cxcrawler enrich-cve --refresh 168h --webhook https://hooks.example.com/cx
+  cxcrawler import results/ --enrich-cve`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if enrichWebhook == "" {
+			enrichWebhook = os.Getenv("CXCRAWLER_WEBHOOK_URL")
+		}
+		options, err := webhookOptions(enrichHeaders)
+		if err != nil {
+			return err
+		}
+		s, err := openStore(storeFile)
+		if err != nil {
+			return err
+		}
+		results, cves, err := enrichStoredCVEs(s, enrichDryRun)
+		if err != nil {
+			return err
+		}
+		if !enrichDryRun {
+			if err := notifyCVEUpdates(s, cves, results, enrichWebhook, options); err != nil {
+				logging.Printf(T("发送CVE修改通知失败: %v")+"\n", err)
+			}
+		}
+		if printFormatted(results) || quietOutput {
+			return nil
+		}
+		printEnrichResults(results, cves, enrichDryRun)
+		return nil
+	},
+}
+
+// enrichStoredCVEs 为存储s中的漏洞补全CVE详情并保存CVE详情存储，dryRun时只列出需要补全的CVE
+// 指定 --refresh 且发现CVE被修改时，同时保存标记了 cve_update 的漏洞存储
+func enrichStoredCVEs(s *store.Store, dryRun bool) ([]store.EnrichResult, *store.CVEStore, error) {
+	cves, err := openCVEStore()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := newCrawler(
+		crawler.WithClientOptions(clientOptions(crawler.WithAdaptiveThrottle(enrichDelay, 30*time.Second, time.Minute))...),
+		crawler.WithRelatedPages(enrichRelatedPages),
+		forceNetwork(),
+	)
+	fetch := func(cveID string) (*model.CveDetail, error) {
+		infof(T("获取 %s")+"\n", cveID)
+		return c.CrawlCveDetail(cveID, "")
+	}
+	results := store.EnrichCVEs(s, cves, fetch, store.EnrichOptions{
+		Limit:        enrichLimit,
+		MaxAttempts:  enrichMaxAttempts,
+		DryRun:       dryRun,
+		RefreshAfter: enrichRefresh,
+	})
+	if !dryRun {
+		if err := cves.Save(); err != nil {
+			return nil, nil, err
+		}
+		for _, r := range results {
+			if r.Status == store.EnrichUpdated {
+				if err := s.Save(); err != nil {
+					return nil, nil, err
+				}
+				break
+			}
+		}
+	}
+	return results, cves, nil
+}
+
+// cveUpdateNotices 返回本次发现修改的CVE及引用它们的漏洞
+func cveUpdateNotices(s *store.Store, cves *store.CVEStore, results []store.EnrichResult) []sink.CVEUpdateNotice {
+	var notices []sink.CVEUpdateNotice
+	for _, r := range results {
+		if r.Status != store.EnrichUpdated {
+			continue
+		}
+		d, _ := cves.Get(r.CveID)
+		// 相关漏洞可能很多，通知中只需要引用了该CVE的漏洞
+		d.RelatedVulnerabilities, d.RelatedTruncated = nil, false
+		notice := sink.CVEUpdateNotice{Detail: d}
+		for _, v := range s.All() {
+			if v.CVEUpdate != nil && v.CVEUpdate.CveID == r.CveID {
+				notice.Vulnerabilities = append(notice.Vulnerabilities, v)
+			}
+		}
+		notices = append(notices, notice)
+	}
+	return notices
+}
+
+// notifyCVEUpdates 在发现CVE被修改时发送Webhook和桌面通知，url为空时不发送Webhook
+func notifyCVEUpdates(s *store.Store, cves *store.CVEStore, results []store.EnrichResult, url string, options []sink.WebhookOption) error {
+	notices := cveUpdateNotices(s, cves, results)
+	if len(notices) == 0 {
+		return nil
+	}
+	var items []model.Vulnerability
+	for _, n := range notices {
+		items = append(items, n.Vulnerabilities...)
+	}
+	notifyDesktop(fmt.Sprintf(T("%d 个CVE在上次获取后被修改"), len(notices)), items)
+	if url == "" {
+		return nil
+	}
+	return sink.NewWebhook(url, options...).Send(sink.CVEUpdates{
+		Event:     sink.CVEUpdatedEvent,
+		CheckedAt: time.Now().UTC(),
+		Updates:   notices,
+	})
+}
+
+// printEnrichResults 以表格形式输出补全结果和统计
+func printEnrichResults(results []store.EnrichResult, cves *store.CVEStore, dryRun bool) {
+	if len(results) == 0 {
+		fmt.Printf(T("所有引用的CVE都已有详情，CVE详情存储 %s 共 %d 条")+"\n", cves.Path(), cves.Len())
+		return
+	}
+
+	counts := make(map[string]int)
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(tableStyle(table.StyleRounded))
+	t.AppendHeader(table.Row{"CVE", T("状态"), T("失败次数"), T("错误或修改")})
+	for _, r := range results {
+		counts[r.Status]++
+		status := r.Status
+		switch r.Status {
+		case store.EnrichFetched:
+			status = text.Colors{text.FgHiGreen}.Sprint(T("已获取"))
+		case store.EnrichFailed:
+			status = text.Colors{text.FgHiRed}.Sprint(T("失败"))
+		case store.EnrichPending:
+			status = text.Colors{text.FgHiYellow}.Sprint(T("待获取"))
+		case store.EnrichGivenUp:
+			status = text.Colors{text.FgHiBlack}.Sprint(T("已放弃"))
+		case store.EnrichUpdated:
+			status = text.Colors{text.FgHiMagenta}.Sprint(T("已修改"))
+		case store.EnrichUnchanged:
+			status = text.Colors{text.FgHiBlack}.Sprint(T("没有变化"))
+		}
+		attempts := ""
+		if r.Attempts > 0 {
+			attempts = fmt.Sprint(r.Attempts)
+		}
+		detail := truncateCell(r.Error, 60)
+		if d, ok := cves.Get(r.CveID); ok && r.Change != nil {
+			detail = truncateCell(cveChangeSummary(d), 60)
+		}
+		t.AppendRow(table.Row{r.CveID, status, attempts, detail})
+	}
+	t.Render()
+
+	if dryRun {
+		fmt.Printf(T("dry-run: %d 个CVE需要补全，%d 个已放弃")+"\n", counts[store.EnrichPending], counts[store.EnrichGivenUp])
+		return
+	}
+	fmt.Printf(T("已获取 %d 个，失败 %d 个，待获取 %d 个，已放弃 %d 个，CVE详情存储 %s 共 %d 条")+"\n",
+		counts[store.EnrichFetched], counts[store.EnrichFailed], counts[store.EnrichPending], counts[store.EnrichGivenUp], cves.Path(), cves.Len())
+	if refreshed := counts[store.EnrichUpdated] + counts[store.EnrichUnchanged]; refreshed > 0 {
+		fmt.Printf(T("重新获取 %d 个，其中 %d 个在上次获取后被修改")+"\n", refreshed, counts[store.EnrichUpdated])
+	}
+}
+
+// cveChangeSummary 返回CVE最近一次修改的简要说明，例如 "modified 2024-03-01 -> 2024-05-02, cvss 5.0 -> 7.5, references"
+func cveChangeSummary(d model.CveDetail) string {
+	if d.Change == nil {
+		return ""
+	}
+	date := func(t time.Time) string {
+		if t.IsZero() {
+			return "-"
+		}
+		return formatDate(t)
+	}
+	parts := make([]string, 0, len(d.Change.Fields))
+	for _, field := range d.Change.Fields {
+		switch field {
+		case "modified":
+			parts = append(parts, fmt.Sprintf("modified %s -> %s", date(d.Change.PreviousModified), date(d.Modified)))
+		case "cvss":
+			parts = append(parts, fmt.Sprintf("cvss %.1f -> %.1f", d.Change.PreviousScore, d.Score()))
+		default:
+			parts = append(parts, field)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// addEnrichFlags 添加补全CVE详情的选项，enrich-cve 和 import --enrich-cve 共用
+func addEnrichFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&cveStoreFile, "cve-store", "", T("CVE详情存储文件，默认为配置目录下的 cve_details.json"))
+	cmd.Flags().IntVar(&enrichLimit, "limit", 0, T("本次最多请求的CVE数量，0表示不限制"))
+	cmd.Flags().DurationVar(&enrichDelay, "delay", time.Second, T("请求CVE详情页面的最小间隔"))
+	cmd.Flags().IntVar(&enrichMaxAttempts, "max-attempts", 5, T("同一CVE失败达到该次数后不再重试，0表示总是重试"))
+	cmd.Flags().DurationVar(&enrichRefresh, "refresh", 0, T("同时重新获取最后一次获取早于该时长的CVE并记录修改，例如 168h；为0时只补全缺少的CVE"))
+	cmd.Flags().IntVar(&enrichRelatedPages, "related-pages", 0, T("CVE页面相关漏洞最多额外请求的分页数，默认只获取第一页以减少请求"))
+}
+
+func init() {
+	rootCmd.AddCommand(enrichCveCmd)
+
+	enrichCveCmd.Flags().StringVar(&storeFile, "store", "", T("存储文件，默认为配置目录下的 vulnerabilities.json"))
+	enrichCveCmd.Flags().BoolVar(&enrichDryRun, "dry-run", false, T("只列出需要补全的CVE，不发送请求"))
+	enrichCveCmd.Flags().StringVar(&enrichWebhook, "webhook", "", T("发现CVE被修改时POST通知的地址"))
+	enrichCveCmd.Flags().StringArrayVar(&enrichHeaders, "webhook-header", nil, T("Webhook请求头，格式为 \"名称: 值\"，可以指定多次"))
+	enrichCveCmd.Flags().BoolVar(&desktopNotifyEnabled, "desktop-notify", false, T("发现CVE被修改时显示桌面通知"))
+	addEnrichFlags(enrichCveCmd)
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/sink"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/store"
+)
+
+func TestCveChangeSummary(t *testing.T) {
+	d := model.CveDetail{
+		Modified: time.Date(2024, 5, 8, 0, 0, 0, 0, time.UTC),
+		Cvss3:    &model.Cvss3{BaseScore: 9.8},
+		Change:   &model.CveChange{Fields: []string{"modified", "cvss", "references"}, PreviousScore: 5},
+	}
+	assert.Equal(t, "modified - -> 2024-05-08, cvss 5.0 -> 9.8, references", cveChangeSummary(d))
+	assert.Empty(t, cveChangeSummary(model.CveDetail{}))
+}
+
+func TestNotifyCVEUpdates(t *testing.T) {
+	var got sink.CVEUpdates
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	s, err := store.Open(filepath.Join(dir, store.DefaultFile))
+	require.NoError(t, err)
+	cves, err := store.OpenCVE(filepath.Join(dir, store.DefaultCVEFile))
+	require.NoError(t, err)
+	now := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+	s.Put(model.Vulnerability{ID: "WLB-1", CVE: "CVE-2024-0001"})
+	s.Put(model.Vulnerability{ID: "WLB-2", CVE: "CVE-2024-0002"})
+	cves.Sync(model.CveDetail{CveID: "CVE-2024-0001", CvssBaseScore: 5}, now.Add(-30*24*time.Hour))
+
+	fetch := func(id string) (*model.CveDetail, error) {
+		return &model.CveDetail{CveID: id, CvssBaseScore: 7.5, RelatedVulnerabilities: []model.Vulnerability{{ID: "WLB-9"}}}, nil
+	}
+	results := store.EnrichCVEs(s, cves, fetch, store.EnrichOptions{RefreshAfter: time.Hour, Now: func() time.Time { return now }})
+	options, err := webhookOptions([]string{"Authorization: Bearer x"})
+	require.NoError(t, err)
+	require.NoError(t, notifyCVEUpdates(s, cves, results, server.URL, options))
+
+	assert.Equal(t, "Bearer x", auth)
+	assert.Equal(t, sink.CVEUpdatedEvent, got.Event)
+	require.Len(t, got.Updates, 1)
+	assert.Equal(t, "CVE-2024-0001", got.Updates[0].Detail.CveID)
+	assert.Empty(t, got.Updates[0].Detail.RelatedVulnerabilities)
+	require.Len(t, got.Updates[0].Vulnerabilities, 1)
+	assert.Equal(t, "WLB-1", got.Updates[0].Vulnerabilities[0].ID)
+	assert.Equal(t, 7.5, got.Updates[0].Vulnerabilities[0].CVEUpdate.Score)
+
+	_, err = webhookOptions([]string{"no-colon"})
+	assert.Error(t, err)
+}
@@ -7,53 +7,65 @@ import (
 
 	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/mattn/go-runewidth"
 	"github.com/spf13/cobra"
-	"golang.org/x/term"
 
 	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/logging"
 	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/store"
 )
 
 var (
 	exploitOutputFile string
 	exploitFields     string
 	exploitIds        []string
-	exploitSilent     bool
+	exploitSection    string
 )
 
 var exploitCmd = &cobra.Command{
 	Use:   "exploit",
-	Short: "爬取漏洞列表",
-	Long:  `爬取CXSecurity网站的漏洞列表，并将结果保存为JSON格式`,
+	Short: T("爬取漏洞列表"),
+	Long:  T(`爬取CXSecurity网站的漏洞列表，并将结果保存为JSON格式`),
 	Run: func(cmd *cobra.Command, args []string) {
 		// 创建爬虫实例
-		c := crawler.NewCrawler()
+		c := newCrawler()
 
 		// 执行爬取
 		if len(exploitIds) > 0 {
+			// 获取或解析失败的漏洞记录到重试队列，之后可以用 retry-failed 重试
+			tracker := newRetryTracker()
+			defer tracker.save()
 			for _, id := range exploitIds {
+				wlbID, idErr := model.ParseWLBID(id)
 				result, err := c.CrawlExploit(id, exploitOutputFile, exploitFields)
 				if err != nil {
-					fmt.Printf("爬取失败: %v\n", err)
+					logging.Printf(T("爬取失败: %v\n"), err)
+					if idErr == nil {
+						tracker.fail(store.RetryExploit, wlbID.String(), exploitOutputFile, err)
+					}
 					continue
 				}
+				tracker.succeed(store.RetryExploit, wlbID.String())
 
 				// 只有在非静默模式下才输出结果
-				if !exploitSilent {
+				if !printFormatted(result) && !quietOutput {
 					printExploitResult(result, exploitOutputFile)
 				}
+				handleOpenAndCopy(result)
 			}
 		} else {
-			result, err := c.CrawlExploit("", exploitOutputFile, exploitFields)
+			result, err := c.CrawlSection(crawler.Section(exploitSection), 1, exploitOutputFile)
 			if err != nil {
-				fmt.Printf("爬取失败: %v\n", err)
+				logging.Printf(T("爬取失败: %v\n"), err)
 				return
 			}
 
 			// 只有在非静默模式下才输出结果
-			if !exploitSilent {
+			if !printFormatted(result) && !quietOutput {
 				printExploitResult(result, exploitOutputFile)
 			}
+			handleOpenAndCopy(result)
 		}
 	},
 }
@@ -63,22 +75,20 @@ func printExploitResult(result interface{}, outputPath string) {
 	// 判断结果类型
 	switch v := result.(type) {
 	case *model.Vulnerability:
-		// 获取终端宽度
-		width, _, err := term.GetSize(int(os.Stdout.Fd()))
-		if err != nil {
-			// 如果获取失败，使用默认宽度
-			width = 80
-		}
+		// 获取终端宽度，获取失败时使用默认宽度
+		width := terminalWidth(80)
 
 		// 计算边框和内容宽度
-		borderWidth := width - 2              // 两侧各减1个字符给边框
-		titlePadding := (borderWidth - 8) / 2 // "漏洞详情"是4个汉字(8个字符宽度)，两侧填充
+		borderWidth := width - 2 // 两侧各减1个字符给边框
+		title := T("漏洞详情")
+		titleWidth := stringDisplayWidth(title)
+		titlePadding := (borderWidth - titleWidth) / 2 // 标题两侧填充
 
 		// 构建顶部边框
-		topBorder := "┏" + strings.Repeat("━", borderWidth) + "┓"
-		titleLine := "┃" + strings.Repeat(" ", titlePadding) + "漏洞详情" + strings.Repeat(" ", borderWidth-titlePadding-8) + "┃"
-		middleBorder := "┣" + strings.Repeat("━", borderWidth) + "┫"
-		bottomBorder := "┗" + strings.Repeat("━", borderWidth) + "┛"
+		topBorder := boxText("┏" + strings.Repeat("━", borderWidth) + "┓")
+		titleLine := boxText("┃" + strings.Repeat(" ", titlePadding) + title + strings.Repeat(" ", borderWidth-titlePadding-titleWidth) + "┃")
+		middleBorder := boxText("┣" + strings.Repeat("━", borderWidth) + "┫")
+		bottomBorder := boxText("┗" + strings.Repeat("━", borderWidth) + "┛")
 
 		// 显示表头
 		fmt.Println()
@@ -87,12 +97,11 @@ func printExploitResult(result interface{}, outputPath string) {
 		fmt.Println(middleBorder)
 
 		// 获取ID（如果有）
-		id := "未知"
+		id := T("未知")
 		if v.ID != "" {
 			id = v.ID
-		} else if v.URL != "" && strings.Contains(v.URL, "WLB-") {
-			idx := strings.Index(v.URL, "WLB-")
-			id = v.URL[idx:]
+		} else if urlID := model.WLBIDFromURL(v.URL); urlID != "" {
+			id = urlID.String()
 		}
 
 		// 格式化风险级别并上色
@@ -107,9 +116,9 @@ func printExploitResult(result interface{}, outputPath string) {
 		}
 
 		// 格式化日期
-		date := "未知"
+		date := T("未知")
 		if !v.Date.IsZero() {
-			date = v.Date.Format("2006-01-02")
+			date = formatDate(v.Date)
 		}
 
 		// 计算内容区域宽度
@@ -137,47 +146,60 @@ func printExploitResult(result interface{}, outputPath string) {
 			}
 
 			// 输出行，确保右边框对齐
-			fmt.Printf("┃ %s: %s%s ┃\n", labelText, valueText, strings.Repeat(" ", padding))
+			fmt.Printf(boxText("┃ %s: %s%s ┃\n"), labelText, valueText, strings.Repeat(" ", padding))
 		}
 
 		// 输出详细信息
-		printLine("漏洞ID", id, text.FgHiCyan)
-		printLine("漏洞标题", v.Title)
-		printLine("风险级别", riskLevel) // 已经着色
-		printLine("发布日期", date)
+		printLine(T("漏洞ID"), id, text.FgHiCyan)
+		printLine(T("漏洞标题"), v.Title)
+		printLine(T("风险级别"), riskLevel) // 已经着色
+		printLine(T("发布日期"), date)
 
 		// 输出CVE编号（如果有）
 		if v.CVE != "" {
-			printLine("CVE编号", v.CVE, text.FgHiYellow)
+			printLine(T("CVE编号"), v.CVE, text.FgHiYellow)
 		}
 
 		// 输出CWE编号（如果有）
 		if v.CWE != "" {
-			printLine("CWE编号", v.CWE, text.FgHiYellow)
+			printLine(T("CWE编号"), v.CWE, text.FgHiYellow)
+		}
+
+		// 输出ATT&CK技术编号（启用 --attack 时）
+		if len(v.Techniques) > 0 {
+			printLine("ATT&CK", strings.Join(v.Techniques, ", "), text.FgHiRed)
 		}
 
 		// 输出漏洞位置信息
 		locationInfo := []string{}
 		if v.IsRemote {
-			locationInfo = append(locationInfo, "远程")
+			locationInfo = append(locationInfo, T("远程"))
 		}
 		if v.IsLocal {
-			locationInfo = append(locationInfo, "本地")
+			locationInfo = append(locationInfo, T("本地"))
 		}
 		if len(locationInfo) > 0 {
-			printLine("漏洞位置", strings.Join(locationInfo, ", "), text.FgHiGreen)
+			printLine(T("漏洞位置"), strings.Join(locationInfo, ", "), text.FgHiGreen)
+		}
+
+		// 输出受影响版本和平台（如果正文中有）
+		if len(v.AffectedVersions) > 0 {
+			printLine(T("受影响版本"), strings.Join(v.AffectedVersions, ", "), text.FgHiYellow)
+		}
+		if v.Platform != "" {
+			printLine(T("平台"), v.Platform, text.FgHiGreen)
 		}
 
 		// 输出其他标签（如果有）
 		if len(v.Tags) > 0 {
-			printLine("其他标签", strings.Join(v.Tags, ", "), text.FgHiGreen)
+			printLine(T("其他标签"), strings.Join(v.Tags, ", "), text.FgHiGreen)
 		}
 
-		printLine("作者", v.Author, text.FgHiMagenta)
+		printLine(T("作者"), v.Author, text.FgHiMagenta)
 
 		// 添加原始URL链接行
 		if v.URL != "" {
-			printLine("详情链接", v.URL, text.FgBlue)
+			printLine(T("详情链接"), v.URL, text.FgBlue)
 		}
 
 		// 输出底部边框
@@ -189,14 +211,10 @@ func printExploitResult(result interface{}, outputPath string) {
 		t.SetOutputMirror(os.Stdout)
 
 		// 设置表格样式
-		t.SetStyle(table.StyleRounded)
+		t.SetStyle(tableStyle(table.StyleRounded))
 
-		// 获取终端宽度
-		width, _, err := term.GetSize(int(os.Stdout.Fd()))
-		if err != nil {
-			// 如果获取失败，使用默认宽度
-			width = 120
-		}
+		// 获取终端宽度，获取失败时使用默认宽度
+		width := terminalWidth(120)
 
 		// 动态计算各列宽度
 		// 终端宽度减去表格边框和列分隔符所占用的空间（大约是每列2个字符和表边框4个字符）
@@ -223,7 +241,7 @@ func printExploitResult(result interface{}, outputPath string) {
 		authorWidth := max(12, int(float64(availableWidth)*authorRatio))
 
 		// 设置表头
-		t.AppendHeader(table.Row{"ID", "日期", "风险", "CVE", "CWE", "位置", "标题", "作者"})
+		t.AppendHeader(table.Row{"ID", T("日期"), T("风险"), "CVE", "CWE", T("位置"), T("标题"), T("作者")})
 
 		// 设置表头样式 - 深色背景
 		t.SetColumnConfigs([]table.ColumnConfig{
@@ -240,79 +258,43 @@ func printExploitResult(result interface{}, outputPath string) {
 		// 添加数据行
 		for _, item := range v.Items {
 			// 从ID或URL中提取ID
-			vulnID := "未知"
+			vulnID := T("未知")
 			if item.ID != "" {
 				vulnID = item.ID
-			} else if item.URL != "" {
-				if idx := strings.Index(item.URL, "WLB-"); idx != -1 {
-					vulnID = item.URL[idx:]
-				}
+			} else if urlID := model.WLBIDFromURL(item.URL); urlID != "" {
+				vulnID = urlID.String()
 			}
 
 			// 日期格式化
-			date := ""
-			if !item.Date.IsZero() {
-				date = item.Date.Format("2006-01-02")
-			}
+			date := formatDate(item.Date)
 
 			// 标题可能很长，需要截断
-			title := item.Title
-			if len(title) > titleWidth-3 {
-				// 截断标题部分，为省略号留出空间
-				maxTitleLen := titleWidth - 6
-				if maxTitleLen > 0 {
-					if len(title) > maxTitleLen {
-						title = title[:maxTitleLen] + "..."
-					}
-				}
-			}
+			title := truncateCell(item.Title, titleWidth-3)
 
 			// 作者名可能很长，需要截断
-			author := item.Author
-			if len(author) > authorWidth-3 {
-				// 安全截断，确保不会越界
-				maxAuthorLen := authorWidth - 6
-				if maxAuthorLen > 0 {
-					if len(author) > maxAuthorLen {
-						author = author[:maxAuthorLen] + "..."
-					}
-				}
-			}
+			author := truncateCell(item.Author, authorWidth-3)
 
 			// CVE编号处理
-			cve := item.CVE
-			if len(cve) > cveWidth-3 {
-				maxCveLen := cveWidth - 6
-				if maxCveLen > 0 {
-					if len(cve) > maxCveLen {
-						cve = cve[:maxCveLen] + "..."
-					}
-				}
-			}
+			cve := truncateCell(item.CVE, cveWidth-3)
 
 			// CWE编号处理
-			cwe := item.CWE
-			if len(cwe) > cweWidth-3 {
-				maxCweLen := cweWidth - 6
-				if maxCweLen > 0 {
-					if len(cwe) > maxCweLen {
-						cwe = cwe[:maxCweLen] + "..."
-					}
-				}
-			}
+			cwe := truncateCell(item.CWE, cweWidth-3)
 
 			// 位置信息处理
 			location := ""
 			if item.IsRemote && item.IsLocal {
-				location = "远程/本地"
+				location = T("远程/本地")
 			} else if item.IsRemote {
-				location = "远程"
+				location = T("远程")
 			} else if item.IsLocal {
-				location = "本地"
+				location = T("本地")
 			}
 
-			// 根据风险级别设置不同颜色
+			// 根据风险级别设置不同颜色，没有标签时显示推断的级别，前面加 ~ 表示估计
 			riskLevel := item.RiskLevel
+			if riskLevel == "" && item.RiskInferred {
+				riskLevel = item.DerivedRisk
+			}
 			var riskColor text.Colors
 
 			switch strings.ToLower(riskLevel) {
@@ -327,7 +309,7 @@ func printExploitResult(result interface{}, outputPath string) {
 			}
 
 			// 构建带颜色的风险等级文本
-			coloredRisk := riskColor.Sprint(riskLevel)
+			coloredRisk := riskPrefix(item.RiskLevel, item.RiskInferred) + riskColor.Sprint(riskLevel)
 
 			// 添加数据行
 			t.AppendRow(table.Row{
@@ -344,39 +326,29 @@ func printExploitResult(result interface{}, outputPath string) {
 
 		// 添加页码信息到表格底部
 		t.AppendFooter(table.Row{
-			fmt.Sprintf("总计: %d 条记录", len(v.Items)),
+			fmt.Sprintf(T("总计: %d 条记录"), len(v.Items)),
 			"",
 			"",
 			"",
 			"",
 			"",
-			fmt.Sprintf("页码: %d/%d", v.CurrentPage, v.TotalPages),
+			fmt.Sprintf(T("页码: %d/%d"), v.CurrentPage, v.TotalPages),
 			""})
 
 		// 渲染表格
-		fmt.Printf("\n爬取成功！\n")
+		fmt.Print(T("\n爬取成功！\n"))
 		t.Render()
 		fmt.Println()
 	}
 
 	if outputPath != "" {
-		fmt.Printf("结果已保存到 %s\n", outputPath)
+		fmt.Printf(T("结果已保存到 %s\n"), outputPath)
 	}
 }
 
 // stringDisplayWidth 计算字符串在显示终端的宽度(考虑中文等宽字符)
 func stringDisplayWidth(s string) int {
-	width := 0
-	for _, r := range s {
-		if r >= 0x4E00 && r <= 0x9FFF || // 中文字符
-			r >= 0x3000 && r <= 0x303F || // 中文符号
-			r >= 0xFF00 && r <= 0xFFEF { // 全角ASCII、全角标点
-			width += 2
-		} else {
-			width += 1
-		}
-	}
-	return width
+	return runewidth.StringWidth(s)
 }
 
 // max 返回两个整数中的较大值
@@ -391,8 +363,11 @@ func init() {
 	rootCmd.AddCommand(exploitCmd)
 
 	// 添加标志
-	exploitCmd.Flags().StringVarP(&exploitOutputFile, "output", "o", "exploit_result.json", "输出文件路径")
-	exploitCmd.Flags().StringVarP(&exploitFields, "fields", "f", "all", "要输出的字段，用逗号分隔，或使用'all'获取所有字段")
-	exploitCmd.Flags().StringArrayVarP(&exploitIds, "id", "i", []string{}, "要爬取的漏洞ID，例如：WLB-2024040035或简写为2024040035")
-	exploitCmd.Flags().BoolVarP(&exploitSilent, "silent", "s", false, "静默模式，不输出到标准输出，适用于API调用")
+	exploitCmd.Flags().StringVarP(&exploitOutputFile, "output", "o", "exploit_result.json", T("输出文件路径"))
+	exploitCmd.Flags().StringVarP(&exploitFields, "fields", "f", "all", T("要输出的字段，用逗号分隔，或使用'all'获取所有字段"))
+	exploitCmd.Flags().StringArrayVarP(&exploitIds, "id", "i", []string{}, T("要爬取的漏洞ID，例如：WLB-2024040035或简写为2024040035"))
+	exploitCmd.Flags().BoolVarP(&quietOutput, "silent", "s", false, T("静默模式，不输出到标准输出，适用于API调用"))
+	deprecateSilent(exploitCmd)
+	exploitCmd.Flags().StringVar(&exploitSection, "section", string(crawler.SectionExploit), sectionFlagUsage())
+	addRetryQueueFlags(exploitCmd)
 }
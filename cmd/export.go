@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/export"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+// splitIndexFile 是拆分导出目录中列出所有文件的索引
+const splitIndexFile = "index.json"
+
+// splitUnknownName 是没有作者或产品信息的组使用的文件名和厂商目录名
+const splitUnknownName = "_unknown"
+
+var (
+	exportBy        string
+	exportOutputDir string
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export [file.json...]",
+	Short: T("按作者或产品将漏洞拆分导出到目录"),
+	Long: T(`将漏洞拆分为每个作者或每个受影响产品一个JSON文件，便于按负责人分发报告材料。
+--by author 时保存为 <目录>/<作者>.json；
+--by product 时保存为 <目录>/<厂商>/<产品>.json，产品来自漏洞引用的CVE详情(参见 enrich-cve 命令)，
+影响多个产品的漏洞会出现在每个产品的文件中。没有作者或产品信息的漏洞保存在 _unknown.json 中。
+目录中同时生成列出所有文件的 index.json。指定结果文件时从这些文件读取漏洞，否则读取存储。`),
+	Example: `  cxcrawler export --by author -o reports/by-author
+  cxcrawler export results/*.json --by product -o reports/by-product`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if exportOutputDir == "" {
+			return errors.New(T("请使用 -o 指定输出目录"))
+		}
+		if exportBy != export.SplitByAuthor && exportBy != export.SplitByProduct {
+			return fmt.Errorf(T("不支持的分组方式 %q，可选值：author、product"), exportBy)
+		}
+
+		var items []model.Vulnerability
+		if len(args) == 0 {
+			s, err := openStore(storeFile)
+			if err != nil {
+				return err
+			}
+			items = s.All()
+		}
+		for _, arg := range args {
+			loaded, err := loadSnapshot(arg)
+			if err != nil {
+				return err
+			}
+			items = append(items, loaded...)
+		}
+
+		var lookup export.CVELookup
+		if exportBy == export.SplitByProduct {
+			cves, err := openCVEStore()
+			if err != nil {
+				return err
+			}
+			lookup = cves.Get
+		}
+		groups, err := export.SplitVulnerabilities(items, exportBy, lookup)
+		if err != nil {
+			return err
+		}
+
+		index := splitIndex{By: exportBy, Generated: time.Now().UTC(), Groups: make([]splitIndexEntry, 0, len(groups))}
+		c := newCrawler()
+		for i, file := range splitFileNames(groups, exportBy) {
+			g := groups[i]
+			if err := c.SaveJSON(g.Items, filepath.Join(exportOutputDir, filepath.FromSlash(file))); err != nil {
+				return fmt.Errorf(T("保存结果失败: %v"), err)
+			}
+			index.Groups = append(index.Groups, splitIndexEntry{Name: g.Name, Vendor: g.Vendor, Product: g.Product, File: file, Count: len(g.Items)})
+		}
+		if err := c.SaveJSON(index, filepath.Join(exportOutputDir, splitIndexFile)); err != nil {
+			return fmt.Errorf(T("保存结果失败: %v"), err)
+		}
+
+		if printFormatted(index) || quietOutput {
+			return nil
+		}
+		printSplitIndex(index)
+		fmt.Printf("\n%s %s\n",
+			text.Colors{text.FgHiGreen}.Sprintf(T("✅ 已保存 %d 个文件到"), len(index.Groups)),
+			text.Colors{text.FgHiCyan, text.Underline}.Sprint(exportOutputDir))
+		return nil
+	},
+}
+
+// splitIndex 是拆分导出目录中的 index.json
+type splitIndex struct {
+	By        string            `json:"by"`        // author 或 product
+	Generated time.Time         `json:"generated"` // 导出时间
+	Groups    []splitIndexEntry `json:"groups"`
+}
+
+// splitIndexEntry 是索引中的一个文件
+type splitIndexEntry struct {
+	Name    string `json:"name,omitempty"` // 作者或产品名称，没有作者或产品信息的组为空
+	Vendor  string `json:"vendor,omitempty"`
+	Product string `json:"product,omitempty"`
+	File    string `json:"file"` // 相对于输出目录的路径，使用 / 分隔
+	Count   int    `json:"count"`
+}
+
+// splitFileNames 返回每个组保存的文件路径，相对于输出目录并使用 / 分隔
+// 名称转换为合法的文件名；在不区分大小写的文件系统上会冲突的路径加上 -2、-3 等后缀
+func splitFileNames(groups []export.SplitGroup, by string) []string {
+	used := map[string]bool{splitIndexFile: true}
+	names := make([]string, len(groups))
+	for i, g := range groups {
+		base := splitUnknownName
+		switch {
+		case g.Name == "":
+		case by == export.SplitByProduct:
+			vendor := splitUnknownName
+			if g.Vendor != "" {
+				vendor = safeFileName(g.Vendor)
+			}
+			base = path.Join(vendor, safeFileName(g.Product))
+		default:
+			base = safeFileName(g.Name)
+		}
+
+		name := base + ".json"
+		for n := 2; used[strings.ToLower(name)]; n++ {
+			name = fmt.Sprintf("%s-%d.json", base, n)
+		}
+		used[strings.ToLower(name)] = true
+		names[i] = name
+	}
+	return names
+}
+
+// printSplitIndex 以表格显示导出的文件，最多显示20行
+func printSplitIndex(index splitIndex) {
+	const maxRows = 20
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(tableStyle(table.StyleRounded))
+	t.AppendHeader(table.Row{T("名称"), T("文件"), T("漏洞数")})
+	for i, entry := range index.Groups {
+		if i == maxRows {
+			break
+		}
+		name := entry.Name
+		if name == "" {
+			name = text.Colors{text.FgHiBlack}.Sprint(T("未知"))
+		}
+		t.AppendRow(table.Row{truncateCell(name, 40), entry.File, entry.Count})
+	}
+	t.Render()
+	if len(index.Groups) > maxRows {
+		fmt.Printf(T("... 还有 %d 个文件，完整列表见 %s")+"\n", len(index.Groups)-maxRows, splitIndexFile)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+
+	exportCmd.Flags().StringVar(&exportBy, "by", export.SplitByAuthor, T("分组方式(author或product)"))
+	exportCmd.Flags().StringVarP(&exportOutputDir, "output-dir", "o", "", T("输出目录"))
+	exportCmd.Flags().StringVar(&storeFile, "store", "", T("存储文件，默认为配置目录下的 vulnerabilities.json"))
+	exportCmd.Flags().StringVar(&cveStoreFile, "cve-store", "", T("CVE详情存储文件，默认为配置目录下的 cve_details.json"))
+}
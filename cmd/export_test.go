@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/export"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/store"
+)
+
+func TestSplitFileNames(t *testing.T) {
+	names := splitFileNames([]export.SplitGroup{
+		{Name: "hyp3rlinx"},
+		{Name: "HYP3RLINX"},
+		{Name: "a/b:c"},
+		{Name: "index"},
+		{},
+	}, export.SplitByAuthor)
+	assert.Equal(t, []string{"hyp3rlinx.json", "HYP3RLINX-2.json", "a_b_c.json", "index-2.json", "_unknown.json"}, names)
+
+	names = splitFileNames([]export.SplitGroup{
+		{Name: "Apache Struts", Vendor: "Apache", Product: "Struts"},
+		{Name: "libfoo", Product: "libfoo"},
+		{},
+	}, export.SplitByProduct)
+	assert.Equal(t, []string{"Apache/Struts.json", "_unknown/libfoo.json", "_unknown.json"}, names)
+}
+
+func TestExportCommand(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "results.json")
+	data, err := json.Marshal([]model.Vulnerability{
+		{ID: "WLB-1", Author: "hyp3rlinx", CVE: "CVE-2024-1111"},
+		{ID: "WLB-2", Author: "hyp3rlinx"},
+		{ID: "WLB-3", Author: "m4xth0r", CVE: "CVE-2024-1111"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(input, data, 0644))
+
+	cveStore := filepath.Join(dir, "cve_details.json")
+	cves, err := store.OpenCVE(cveStore)
+	require.NoError(t, err)
+	cves.Put(model.CveDetail{CveID: "CVE-2024-1111", AffectedSoftware: []model.AffectedSoftware{{VendorName: "Apache", ProductName: "Struts"}}})
+	require.NoError(t, cves.Save())
+
+	oldQuiet := quietOutput
+	defer func() {
+		exportBy, exportOutputDir, cveStoreFile, quietOutput = export.SplitByAuthor, "", "", oldQuiet
+	}()
+	quietOutput = true
+	cveStoreFile = cveStore
+
+	readIndex := func(out string) splitIndex {
+		data, err := os.ReadFile(filepath.Join(out, splitIndexFile))
+		require.NoError(t, err)
+		var index splitIndex
+		require.NoError(t, json.Unmarshal(data, &index))
+		return index
+	}
+
+	exportBy, exportOutputDir = export.SplitByAuthor, filepath.Join(dir, "by-author")
+	require.NoError(t, exportCmd.RunE(exportCmd, []string{input}))
+	index := readIndex(exportOutputDir)
+	require.Len(t, index.Groups, 2)
+	assert.Equal(t, splitIndexEntry{Name: "hyp3rlinx", File: "hyp3rlinx.json", Count: 2}, index.Groups[0])
+	items, err := loadSnapshot(filepath.Join(exportOutputDir, "m4xth0r.json"))
+	require.NoError(t, err)
+	assert.Equal(t, "WLB-3", items[0].ID)
+
+	exportBy, exportOutputDir = export.SplitByProduct, filepath.Join(dir, "by-product")
+	require.NoError(t, exportCmd.RunE(exportCmd, []string{input}))
+	index = readIndex(exportOutputDir)
+	require.Len(t, index.Groups, 2)
+	assert.Equal(t, splitIndexEntry{Name: "Apache Struts", Vendor: "Apache", Product: "Struts", File: "Apache/Struts.json", Count: 2}, index.Groups[0])
+	assert.Equal(t, splitIndexEntry{File: "_unknown.json", Count: 1}, index.Groups[1])
+	assert.FileExists(t, filepath.Join(exportOutputDir, "Apache", "Struts.json"))
+
+	exportBy = "cwe"
+	assert.Error(t, exportCmd.RunE(exportCmd, []string{input}))
+	exportBy, exportOutputDir = export.SplitByAuthor, ""
+	assert.Error(t, exportCmd.RunE(exportCmd, []string{input}), "需要指定输出目录")
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+)
+
+// exitNewFindings 是 --fail-on-new 或 --fail-on-risk 匹配到漏洞时的退出码，与运行出错时的1区分
+const exitNewFindings = 2
+
+var (
+	failOnNew  bool
+	failOnRisk string
+)
+
+// addFailOnFlags 添加在CI中根据结果决定退出码的选项，search 和 saved run 共用
+func addFailOnFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&failOnNew, "fail-on-new", false, T("有结果时以退出码2退出，配合 --diff-against 只统计新结果"))
+	cmd.Flags().StringVar(&failOnRisk, "fail-on-risk", "", T("有该风险级别及以上的结果时以退出码2退出(High、Med、Low)"))
+}
+
+// findingsGate 统计满足 --fail-on-new 或 --fail-on-risk 的结果，用于在CI中阻止发布
+type findingsGate struct {
+	onNew   bool
+	minRisk string
+	matched int
+}
+
+// newFindingsGate 根据命令行选项创建，风险级别无效时返回错误
+func newFindingsGate() (*findingsGate, error) {
+	g := &findingsGate{onNew: failOnNew}
+	if failOnRisk != "" {
+		if g.minRisk = crawler.NormalizeRiskLevel(failOnRisk); g.minRisk == "" {
+			return nil, fmt.Errorf(T("无效的风险级别 %q，可选值：High、Med、Low"), failOnRisk)
+		}
+	}
+	return g, nil
+}
+
+// gateRiskRank 返回风险级别的排序权重，越高越严重
+func gateRiskRank(risk string) int {
+	switch risk {
+	case "High":
+		return 3
+	case "Med.":
+		return 2
+	case "Low":
+		return 1
+	}
+	return 0
+}
+
+// add 统计满足条件的结果
+func (g *findingsGate) add(items []crawler.SearchVulnerability) {
+	for _, item := range items {
+		if g.onNew || (g.minRisk != "" && gateRiskRank(item.Risk()) >= gateRiskRank(g.minRisk)) {
+			g.matched++
+		}
+	}
+}
+
+// exit 有满足条件的结果时输出原因到标准错误并以 exitNewFindings 退出
+// 输出到标准错误，不影响 --jq 等输出到标准输出的结果
+func (g *findingsGate) exit() {
+	if g.matched == 0 {
+		return
+	}
+	if g.minRisk != "" && !g.onNew {
+		fmt.Fprintf(os.Stderr, T("发现 %d 个风险级别为 %s 及以上的漏洞")+"\n", g.matched, g.minRisk)
+	} else {
+		fmt.Fprintf(os.Stderr, T("发现 %d 个新漏洞")+"\n", g.matched)
+	}
+	exitWithRunStats(exitNewFindings, true)
+}
+
+// abort 在搜索失败时以退出码1退出，避免CI因为没有获取到结果而误以为没有新漏洞
+// 没有指定 --fail-on-new 和 --fail-on-risk 时不做任何处理，保持原来的行为
+func (g *findingsGate) abort() {
+	if g.onNew || g.minRisk != "" {
+		exitWithRunStats(1, false)
+	}
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+)
+
+func TestFindingsGate(t *testing.T) {
+	defer func() { failOnNew, failOnRisk = false, "" }()
+	items := []crawler.SearchVulnerability{
+		{ID: "WLB-1", RiskLevel: "Low"},
+		{ID: "WLB-2", RiskLevel: "Med."},
+		{ID: "WLB-3", DerivedRisk: "High", RiskInferred: true},
+	}
+
+	g, err := newFindingsGate()
+	require.NoError(t, err)
+	g.add(items)
+	assert.Zero(t, g.matched)
+
+	failOnRisk = "med"
+	g, err = newFindingsGate()
+	require.NoError(t, err)
+	g.add(items)
+	assert.Equal(t, 2, g.matched)
+
+	failOnNew = true
+	g, err = newFindingsGate()
+	require.NoError(t, err)
+	g.add(items)
+	assert.Equal(t, 3, g.matched)
+
+	failOnRisk = "critical"
+	_, err = newFindingsGate()
+	assert.Error(t, err)
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/store"
+)
+
+var (
+	fetchPolicyFlag string
+	fetchTTL        time.Duration
+
+	// 读穿透模式使用的选项，--fetch-policy 不是 network 时由PersistentPreRunE设置
+	readThroughOption crawler.CrawlerOption
+)
+
+// parseFetchPolicy 解析 --fetch-policy 和 --fetch-ttl，需要使用本地存储时打开漏洞存储和CVE详情存储
+func parseFetchPolicy() error {
+	readThroughOption = nil
+	policy, err := crawler.ParseFetchPolicy(fetchPolicyFlag)
+	if err != nil {
+		return err
+	}
+	if policy == crawler.FetchNetwork {
+		return nil
+	}
+	if fetchTTL < 0 {
+		return fmt.Errorf(T("无效的 --fetch-ttl %s，不能为负数"), fetchTTL)
+	}
+
+	vulns, err := openStore(storeFile)
+	if err != nil {
+		return err
+	}
+	cves, err := openCVEStore()
+	if err != nil {
+		return err
+	}
+	readThroughOption = crawler.WithReadThrough(policy, fetchTTL, store.NewReadThrough(vulns, cves))
+	return nil
+}
+
+// forceNetwork 返回总是从网络获取的选项，用于 enrich-cve、retry-failed 等必须获取最新页面的命令
+func forceNetwork() crawler.CrawlerOption {
+	return crawler.WithReadThrough(crawler.FetchNetwork, 0, nil)
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&fetchPolicyFlag, "fetch-policy", string(crawler.FetchNetwork), T("获取漏洞详情和CVE详情的方式：network(总是请求网站)、local(只读本地存储)、local-first(优先使用未过期的本地记录，否则请求网站并写入本地存储)"))
+	rootCmd.PersistentFlags().DurationVar(&fetchTTL, "fetch-ttl", 24*time.Hour, T("使用 local-first 时本地记录的有效期，0表示永不过期"))
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/config"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/fixture"
+)
+
+func TestParseFetchPolicy(t *testing.T) {
+	server, err := fixture.NewServer("../docs/response-examples")
+	require.NoError(t, err)
+	defer server.Close()
+
+	t.Setenv(config.DirEnv, t.TempDir())
+	oldOptions := globalClientOptions
+	defer func() {
+		globalClientOptions = oldOptions
+		fetchPolicyFlag, fetchTTL = string(crawler.FetchNetwork), 24*time.Hour
+		require.NoError(t, parseFetchPolicy())
+	}()
+	globalClientOptions = []crawler.ClientOption{crawler.WithBaseURL(server.URL), crawler.WithRetry(0, 0)}
+
+	require.NoError(t, parseFetchPolicy())
+	assert.Nil(t, readThroughOption, "默认从网络获取，不打开本地存储")
+
+	// local-first 获取后写入配置目录下的存储
+	fetchPolicyFlag = "local-first"
+	require.NoError(t, parseFetchPolicy())
+	_, err = newCrawler().CrawlExploit("WLB-2024040015", "", "")
+	require.NoError(t, err)
+	s, err := openStore("")
+	require.NoError(t, err)
+	v, ok := s.Get("WLB-2024040015")
+	require.True(t, ok)
+	assert.False(t, v.LastSeen.IsZero())
+
+	// local 不再请求网站，网站不可用时仍然可以读取
+	server.Close()
+	fetchPolicyFlag = "local"
+	require.NoError(t, parseFetchPolicy())
+	_, err = newCrawler().CrawlExploit("WLB-2024040015", "", "")
+	require.NoError(t, err)
+	_, err = newCrawler(forceNetwork()).CrawlExploit("WLB-2024040015", "", "")
+	assert.Error(t, err, "forceNetwork 应覆盖 --fetch-policy")
+
+	fetchPolicyFlag = "cache"
+	assert.Error(t, parseFetchPolicy())
+	fetchPolicyFlag, fetchTTL = "local-first", -1
+	assert.Error(t, parseFetchPolicy())
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/export"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/logging"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+var (
+	githubActions     bool
+	githubSummaryFile string
+)
+
+// addGitHubActionsFlags 添加输出GitHub Actions注释和任务摘要的选项，search 和 saved run 共用
+func addGitHubActionsFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&githubActions, "github-actions", false, T("为每个结果输出GitHub Actions注释(::error/::warning/::notice)，并将Markdown摘要追加到 $GITHUB_STEP_SUMMARY"))
+	cmd.Flags().StringVar(&githubSummaryFile, "github-summary", "", T("Markdown摘要追加到该文件，默认为 $GITHUB_STEP_SUMMARY"))
+}
+
+// githubReport 收集搜索到的漏洞，在运行结束时输出GitHub Actions注释和任务摘要
+// 同一漏洞在多页或多条搜索中出现时只输出一次
+type githubReport struct {
+	items []model.Vulnerability
+	seen  map[string]bool
+}
+
+// newGitHubReport 在指定了 --github-actions 或 --github-summary 时返回报告，否则返回nil
+func newGitHubReport() *githubReport {
+	if !githubActions && githubSummaryFile == "" {
+		return nil
+	}
+	return &githubReport{seen: make(map[string]bool)}
+}
+
+// add 收集搜索结果，nil报告不做任何处理
+func (r *githubReport) add(items []crawler.SearchVulnerability) {
+	if r == nil {
+		return
+	}
+	for _, item := range items {
+		key := item.ID + "|" + item.URL
+		if !r.seen[key] {
+			r.seen[key] = true
+			r.items = append(r.items, item.ToVulnerability())
+		}
+	}
+}
+
+// write 输出注释到标准输出，并将摘要追加到摘要文件，title为摘要的标题
+func (r *githubReport) write(title string) {
+	if r == nil {
+		return
+	}
+	if githubActions {
+		if err := export.WriteGitHubAnnotations(os.Stdout, r.items); err != nil {
+			logging.Printf(T("输出GitHub Actions注释失败: %v")+"\n", err)
+		}
+	}
+
+	path := githubSummaryFile
+	if path == "" {
+		path = os.Getenv("GITHUB_STEP_SUMMARY")
+	}
+	if path == "" {
+		return
+	}
+	// 同一任务中的多个步骤共用摘要文件，需要追加而不是覆盖
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, outputFileMode)
+	if err == nil {
+		err = export.WriteGitHubSummary(f, title, r.items, displayDates)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		logging.Printf(T("写入GitHub Actions摘要失败: %v")+"\n", err)
+	}
+}
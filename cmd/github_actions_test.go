@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+)
+
+func TestGitHubReport(t *testing.T) {
+	defer func() { githubActions, githubSummaryFile = false, "" }()
+	assert.Nil(t, newGitHubReport())
+
+	githubSummaryFile = filepath.Join(t.TempDir(), "summary.md")
+	require.NoError(t, os.WriteFile(githubSummaryFile, []byte("# Earlier step\n"), 0o644))
+
+	r := newGitHubReport()
+	require.NotNil(t, r)
+	r.add([]crawler.SearchVulnerability{{ID: "WLB-1", Title: "A", RiskLevel: "High"}, {ID: "WLB-2", Title: "B", RiskLevel: "Low"}})
+	r.add([]crawler.SearchVulnerability{{ID: "WLB-1", Title: "A", RiskLevel: "High"}})
+	assert.Len(t, r.items, 2)
+
+	r.write("CXSecurity: test")
+	data, err := os.ReadFile(githubSummaryFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "# Earlier step\n## CXSecurity: test\n\n2 new findings.")
+}
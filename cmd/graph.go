@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/export"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+var (
+	graphFormat     string
+	graphOutputFile string
+)
+
+var graphCmd = &cobra.Command{
+	Use:   "graph <file.json...>",
+	Short: T("将保存的结果导出为知识图谱"),
+	Long: T(`读取之前保存的JSON结果文件，导出由作者、漏洞、CVE和受影响产品组成的知识图谱：
+Author-PUBLISHED->Vulnerability-REFERENCES->CVE-AFFECTS->Product
+
+cypher 格式可以通过 cypher-shell 导入Neo4j，graphml 格式可以在Gephi等工具中打开。
+CVE详情文件(cve命令的输出)会同时导入受影响的产品和相关漏洞。`),
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if graphFormat != "cypher" && graphFormat != "graphml" {
+			return fmt.Errorf(T("不支持的图谱格式 %q，可选值：cypher、graphml"), graphFormat)
+		}
+
+		g := export.NewGraph()
+		for _, path := range args {
+			if err := addGraphFile(g, path); err != nil {
+				return err
+			}
+		}
+
+		var buf bytes.Buffer
+		var err error
+		if graphFormat == "cypher" {
+			err = g.WriteCypher(&buf)
+		} else {
+			err = g.WriteGraphML(&buf)
+		}
+		if err != nil {
+			return err
+		}
+
+		if graphOutputFile == "" {
+			_, err = os.Stdout.Write(buf.Bytes())
+			return err
+		}
+		if err := newCrawler().SaveFile(buf.Bytes(), graphOutputFile); err != nil {
+			return fmt.Errorf(T("保存结果失败: %v"), err)
+		}
+		if quietOutput {
+			return nil
+		}
+		fmt.Printf("%s %s %s\n",
+			text.Colors{text.FgHiGreen}.Sprint(T("✅ 已保存:")),
+			text.Colors{text.FgHiCyan, text.Underline}.Sprint(graphOutputFile),
+			text.Colors{text.FgHiBlack}.Sprintf(T("(%d 个节点, %d 条边)"), len(g.Nodes()), len(g.Edges())))
+		return nil
+	},
+}
+
+// addGraphFile 将结果文件中的数据加入图中，CVE详情文件按CveDetail导入
+func addGraphFile(g *export.Graph, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var probe struct {
+		CveID string `json:"cve_id"`
+	}
+	if json.Unmarshal(data, &probe) == nil && probe.CveID != "" {
+		var detail model.CveDetail
+		if err := json.Unmarshal(data, &detail); err != nil {
+			return fmt.Errorf(T("解析结果文件 %s 失败: %w"), path, err)
+		}
+		g.AddCveDetail(&detail)
+		return nil
+	}
+
+	items, err := crawler.ParseResultJSON(data)
+	if err != nil {
+		return fmt.Errorf(T("解析结果文件 %s 失败: %w"), path, err)
+	}
+	for _, item := range items {
+		g.AddVulnerability(item)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(graphCmd)
+
+	graphCmd.Flags().StringVarP(&graphFormat, "format", "f", "cypher", T("输出格式(cypher或graphml)"))
+	graphCmd.Flags().StringVarP(&graphOutputFile, "output", "o", "", T("输出文件路径，默认输出到标准输出"))
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// 支持的界面语言
+const (
+	langZH = "zh"
+	langEN = "en"
+)
+
+var (
+	// outputLang 是 --lang 标志的取值，只用于帮助信息和参数校验
+	outputLang string
+
+	// currentLang 是当前的界面语言
+	// 命令的Short/Long等在flag解析之前就已经初始化，因此在包初始化时直接从命令行参数和环境变量检测
+	currentLang = detectLang(os.Args[1:], os.Getenv)
+)
+
+// T 返回消息在当前界面语言下的文本
+// 源代码中的中文原文即为消息的键，英文目录中没有对应条目时返回原文；--ascii 模式下去掉其中的emoji
+func T(msg string) string {
+	if currentLang == langEN {
+		if s, ok := enMessages[msg]; ok {
+			msg = s
+		}
+	}
+	if asciiOutput {
+		return stripSymbols(msg)
+	}
+	return msg
+}
+
+// detectLang 检测界面语言
+// 优先使用 --lang 参数，其次依次检查 LC_ALL、LC_MESSAGES 和 LANG 环境变量；
+// 以zh开头的locale使用中文，其他locale使用英文，未设置或为C/POSIX时使用中文
+func detectLang(args []string, getenv func(string) string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if v, ok := strings.CutPrefix(arg, "--lang="); ok {
+			return normalizeLang(v)
+		}
+		if arg == "--lang" && i+1 < len(args) {
+			return normalizeLang(args[i+1])
+		}
+	}
+
+	for _, key := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		v := getenv(key)
+		if v == "" {
+			continue
+		}
+		if v == "C" || v == "POSIX" || strings.HasPrefix(v, "C.") {
+			return langZH
+		}
+		return normalizeLang(v)
+	}
+	return langZH
+}
+
+// normalizeLang 将 zh_CN.UTF-8、en-US 等形式规范为 zh 或 en
+func normalizeLang(v string) string {
+	if strings.HasPrefix(strings.ToLower(v), langZH) {
+		return langZH
+	}
+	return langEN
+}
+
+// validateLang 校验 --lang 的取值
+func validateLang() error {
+	switch outputLang {
+	case "", langZH, langEN:
+		return nil
+	}
+	return fmt.Errorf(T("不支持的语言 %q，可选值：zh、en"), outputLang)
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&outputLang, "lang", "", T("界面语言(zh或en)，默认根据LANG环境变量选择"))
+}
@@ -0,0 +1,800 @@
+package cmd
+
+// enMessages 是英文消息目录，键为源代码中的中文原文
+var enMessages = map[string]string{
+	"无效的API Token": "invalid API token",
+	"搜索关键词不能为空":    "search keyword must not be empty",
+	"启动HTTP API服务": "Start the HTTP API server",
+	"启动HTTP API服务，将爬虫功能以RESTful API的形式提供":                         "Start the HTTP API server and expose the crawler as a RESTful API",
+	"已生成随机API Token: %s\n":                                        "Generated random API token: %s\n",
+	"GET /api/exploit - 获取漏洞列表\n":                                 "GET /api/exploit - list vulnerabilities\n",
+	"GET /api/exploit/{id} - 获取漏洞详情\n":                            "GET /api/exploit/{id} - get vulnerability details\n",
+	"GET /api/cve/{id} - 获取CVE详情\n":                               "GET /api/cve/{id} - get CVE details\n",
+	"GET /api/author/{id} - 获取作者信息\n":                             "GET /api/author/{id} - get author profile\n",
+	"GET /api/search - 搜索漏洞\n":                                    "GET /api/search - search vulnerabilities\n",
+	"  参数：\n":                                                     "  Parameters:\n",
+	"    - keyword: 搜索关键词（必填）\n":                                  "    - keyword: search keyword (required)\n",
+	"    - page: 页码，默认1\n":                                        "    - page: page number, default 1\n",
+	"    - per_page: 每页数量，默认10\n":                                 "    - per_page: results per page, default 10\n",
+	"    - sort_order: 排序方式，可选值：ASC/DESC/RELEVANCE，默认DESC\n":      "    - sort_order: sort order, ASC, DESC or RELEVANCE, default DESC\n",
+	"API服务器正在监听 http://localhost%s\n":                             "API server listening on http://localhost%s\n",
+	"JSON编码器: %s\n":                                               "JSON encoder: %s\n",
+	"使用方式：在请求头中添加 X-API-Token: <token> 或在URL中添加 ?token=<token>\n": "Usage: send the header X-API-Token: <token> or append ?token=<token> to the URL\n",
+	"API服务器监听端口":                                                  "port the API server listens on",
+	"API认证Token（不指定则随机生成）":                                        "API authentication token (randomly generated if not set)",
+	"启用CORS支持":                                                    "enable CORS support",
+	"爬取作者信息":                                                      "Crawl an author profile",
+	"爬取CXSecurity网站的作者信息，并将结果保存为JSON格式。\n使用 --from-store 时不请求网站，而是从存储中查询历次爬取和导入的所有归属于该作者的漏洞，\n不受作者页面分页数量的限制。": "Crawl an author profile from CXSecurity and save the result as JSON.\nWith --from-store the site is not contacted; instead every vulnerability attributed to the author\nacross all stored crawls and imports is listed, independent of the author page pagination limits.",
+	"请使用 -i 或 --id 参数指定作者ID": "Please specify the author ID with -i or --id",
+	"👤 正在获取作者信息:":            "👤 Fetching author profile:",
+	"❌ 获取失败:":                "❌ Fetch failed:",
+	"作者信息":                   "Author Profile",
+	"作者ID":                   "Author ID",
+	"作者名称":                   "Name",
+	"未知":                     "Unknown",
+	"国家":                     "Country",
+	"报告数量":                   "Reports",
+	"联系方式":                   "Contact",
+	"网站":                     "Website",
+	"个人描述":                   "About",
+	"发布的漏洞":                  "Published Vulnerabilities",
+	"日期":                     "Date",
+	"风险":                     "Risk",
+	"漏洞标题":                   "Title",
+	"类型":                     "Type",
+	"✅ 已保存:":                 "✅ Saved:",
+	"要爬取的作者ID (必须)":          "author ID to crawl (required)",
+	"结果输出的文件路径":              "output file path for the result",
+	"静默模式，不输出到标准输出":                 "silent mode, do not print to stdout",
+	"国家名称的本地化语言，例如 zh、de；默认只输出英文名称": "language for localized country names, e.g. zh or de; only English names are shown by default",
+	"漏洞列表":    "Vulnerability List",
+	"漏洞详情":    "Vulnerability Details",
+	"CVE详情":   "CVE Details",
+	"搜索流水线":   "Search Pipeline",
+	"测试解析器性能": "Benchmark the parsers",
+	"使用归档的页面样本反复运行各个解析器和搜索流水线，输出吞吐量(页面/秒、MB/秒)，\n便于发现性能回退。指定 --min-pages-per-sec 时，任一用例低于该阈值都会以非零状态码退出。": "Repeatedly run each parser and the search pipeline against archived page samples and report\nthroughput (pages/s, MB/s) to catch performance regressions. With --min-pages-per-sec the command\nexits with a non-zero status if any case falls below the threshold.",
+	"读取样本文件失败: %v\n":                  "Failed to read sample file: %v\n",
+	"%s 基准测试失败: %v\n":                 "%s benchmark failed: %v\n",
+	"%s %s 吞吐量 %.1f 页面/秒 低于阈值 %.1f\n": "%s %s throughput %.1f pages/s is below the threshold %.1f\n",
+	"❌ 性能回退:":                         "❌ Performance regression:",
+	"用例":                              "Case",
+	"页面数":                             "Pages",
+	"耗时":                              "Elapsed",
+	"页面/秒":                            "Pages/s",
+	"MB/秒":                            "MB/s",
+	"⏱️ 解析器基准测试结果:":                   "⏱️ Parser benchmark results:",
+	"归档页面样本所在目录":                      "directory containing archived page samples",
+	"每个用例的运行时长":                       "how long to run each case",
+	"吞吐量下限(页面/秒)，任一用例低于该值时以非零状态码退出": "minimum throughput (pages/s); exit with a non-zero status if any case is below it",
+	"爬取CVE详情": "Crawl CVE details",
+	"爬取CXSecurity网站的CVE详情页面，并将结果保存为JSON格式": "Crawl a CVE details page from CXSecurity and save the result as JSON",
+	"爬取失败: ":      "Crawl failed: ",
+	"请指定CVE编号":    "Please specify a CVE ID",
+	"CVE详情信息":     "CVE Details",
+	"CVE编号":       "CVE ID",
+	"发布日期":        "Published",
+	"修改日期":        "Modified",
+	"漏洞描述":        "Description",
+	"漏洞类型":        "Type",
+	"CVSS评分":      "CVSS Score",
+	"影响评分":        "Impact Score",
+	"利用评分":        "Exploitability Score",
+	"利用范围":        "Exploit Range",
+	"攻击复杂度":       "Attack Complexity",
+	"认证需求":        "Authentication",
+	"机密性影响":       "Confidentiality Impact",
+	"完整性影响":       "Integrity Impact",
+	"可用性影响":       "Availability Impact",
+	"受影响软件":       "Affected Software",
+	"%d个":         "%d items",
+	"  软件%d":      "  Software %d",
+	"  更多软件":      "  More software",
+	"... 共%d个":    "... %d in total",
+	"参考链接":        "References",
+	"  链接%d":      "  Link %d",
+	"  更多链接":      "  More links",
+	"相关漏洞":        "Related Vulnerabilities",
+	"结果已保存到 %s\n": "Result saved to %s\n",
+	"输出文件路径":      "output file path",
+	"要爬取的CVE编号，例如：CVE-2007-1411":  "CVE ID to crawl, e.g. CVE-2007-1411",
+	"要输出的字段，用逗号分隔，或使用'all'获取所有字段": "comma-separated fields to output, or 'all' for every field",
+	"爬取漏洞列表": "Crawl vulnerabilities",
+	"爬取CXSecurity网站的漏洞列表，并将结果保存为JSON格式": "Crawl vulnerabilities from CXSecurity and save the result as JSON",
+	"爬取失败: %v\n": "Crawl failed: %v\n",
+	"漏洞ID":       "ID",
+	"风险级别":       "Risk",
+	"CWE编号":      "CWE",
+	"远程":         "Remote",
+	"本地":         "Local",
+	"漏洞位置":       "Location",
+	"其他标签":       "Tags",
+	"作者":         "Author",
+	"详情链接":       "URL",
+	"位置":         "Location",
+	"标题":         "Title",
+	"远程/本地":      "Remote/Local",
+	"总计: %d 条记录": "Total: %d records",
+	"页码: %d/%d":  "Page: %d/%d",
+	"\n爬取成功！\n":  "\nCrawl succeeded!\n",
+	"要爬取的漏洞ID，例如：WLB-2024040035或简写为2024040035": "vulnerability ID to crawl, e.g. WLB-2024040035 or just 2024040035",
+	"静默模式，不输出到标准输出，适用于API调用":                   "silent mode, do not print to stdout (useful for API calls)",
+	"解析jq表达式失败: %w":                            "failed to parse jq expression: %w",
+	"编译jq表达式失败: %w":                            "failed to compile jq expression: %w",
+	"编码JSON失败: %w":                             "failed to encode JSON: %w",
+	"解码JSON失败: %w":                             "failed to decode JSON: %w",
+	"执行jq表达式失败: %w":                            "failed to run jq expression: %w",
+	"使用jq表达式过滤输出，例如 '.vulnerabilities[].url'":  "filter the output with a jq expression, e.g. '.vulnerabilities[].url'",
+	"与 --jq 一起使用，字符串结果直接输出而不带引号":               "with --jq, print string results without quotes",
+	"结果中没有可打开的链接":                              "No link to open in the result",
+	"打开浏览器失败: %v\n":                            "Failed to open browser: %v\n",
+	"结果中没有可复制的链接或ID":                           "No link or ID to copy in the result",
+	"复制到剪贴板失败: %v\n":                           "Failed to copy to clipboard: %v\n",
+	"不支持的链接: %s":                               "unsupported link: %s",
+	"未找到剪贴板工具，请安装 wl-clipboard、xclip 或 xsel":   "no clipboard tool found, please install wl-clipboard, xclip or xsel",
+	"爬取成功后在默认浏览器中打开结果的链接":                      "open the result link in the default browser after crawling",
+	"爬取成功后将结果的链接(没有链接时为ID)复制到剪贴板":              "copy the result link (or ID when there is no link) to the clipboard after crawling",
+	"  没有匹配的结果":                                "  No matching results",
+	"输入编号查看详情，输入文字过滤，直接回车跳过":                   "Enter a number to view details, text to filter, or press Enter to skip",
+	"过滤: %q，直接回车选择第一条，输入 / 清除过滤":               "Filter: %q, press Enter to pick the first match, / to clear",
+	"编号超出范围: %d":                               "Number out of range: %d",
+	"CXSecurity爬虫工具":                           "CXSecurity crawler",
+	"CXSecurity爬虫工具是一个用于爬取CXSecurity网站数据的命令行工具，\n可以爬取漏洞列表页面和CVE详情页面，并将结果保存为JSON格式。": "CXSecurity crawler is a command line tool for crawling data from the CXSecurity website.\nIt crawls vulnerability lists and CVE details pages and saves the results as JSON.",
+	"--jq 和 --template 不能同时使用":                                 "--jq and --template cannot be used together",
+	"无效的文件权限 %q，应为八进制数，例如 0640":                                "invalid file mode %q, expected an octal number such as 0640",
+	"无效的目录权限 %q，应为八进制数，例如 0750":                                "invalid directory mode %q, expected an octal number such as 0750",
+	"输出文件权限(八进制)":                                              "output file permissions (octal)",
+	"新建输出目录的权限(八进制)":                                           "permissions for newly created output directories (octal)",
+	"只允许当前用户访问输出文件(文件0600、目录0700)，覆盖 --file-mode 和 --dir-mode": "restrict output to the current user (files 0600, directories 0700), overrides --file-mode and --dir-mode",
+	"保存结果时将文件刷到磁盘，防止系统崩溃后结果文件不完整":                              "fsync result files when saving so they survive a system crash intact",
+	"搜索漏洞信息": "Search vulnerabilities",
+	"使用关键词在CXSecurity网站上搜索漏洞，并将结果保存为JSON格式":    "Search CXSecurity for vulnerabilities by keyword and save the results as JSON",
+	"警告: 每页数量只能为10或30，已自动设置为10":                "Warning: results per page must be 10 or 30, using 10",
+	"警告: 排序顺序只能为ASC、DESC或RELEVANCE，已自动设置为DESC": "Warning: sort order must be ASC, DESC or RELEVANCE, using DESC",
+	"相关度优先":                "Most relevant first",
+	"🔍 正在搜索:":              "🔍 Searching:",
+	"(排序: %s, 每页: %d)":     "(sort: %s, per page: %d)",
+	"%s 第 %d 页...\r":       "%s page %d...\r",
+	"⏳ 加载中:":               "⏳ Loading:",
+	"❌ 搜索失败:":              "❌ Search failed:",
+	"当前第 %d/%d 页，是否查看下一页？": "Page %d/%d, show the next page?",
+	"📖 正在获取漏洞详情:":          "📖 Fetching vulnerability details:",
+	"🔎 搜索结果:":              "🔎 Search results:",
+	"⬆️ 排序:":               "⬆️ Sort:",
+	"📊 每页:":                "📊 Per page:",
+	"最新优先":                 "newest first",
+	"最早优先":                 "oldest first",
+	"搜索关键词":                "search keyword",
+	"搜索结果页码":               "result page number",
+	"每页记录数(10或30)":         "results per page (10 or 30)",
+	"排序顺序(ASC、DESC或RELEVANCE)，RELEVANCE在本地按相关度排序当前页": "sort order (ASC, DESC or RELEVANCE); RELEVANCE ranks the current page locally",
+	"禁用交互式分页，只显示指定页":                                 "disable interactive paging and only show the given page",
+	"显示结果后交互式选择一条记录并查看详情":                            "interactively pick a result and show its details",
+	"读取模板目录失败: %w":                                   "failed to read template directory: %w",
+	"解析模板目录失败: %w":                                   "failed to parse template directory: %w",
+	"读取模板文件失败: %w":                                   "failed to read template file: %w",
+	"模板错误: %w":                                       "Template error: %w",
+	"渲染模板失败: %w":                                     "Failed to render template: %w",
+	"使用Go text/template渲染输出，可以是模板名称、模板文件路径或模板内容": "render output with a Go text/template: a template name, a template file path or the template text",
+	"模板目录，其中的 *.tmpl 文件可以通过 --template <名称> 使用":  "template directory whose *.tmpl files can be used via --template <name>",
+	"测试HTML解析器":           "Test the HTML parser",
+	"使用本地HTML文件测试漏洞详情解析器": "Test the vulnerability details parser with a local HTML file",
+	"读取文件失败: %v\n":        "Failed to read file: %v\n",
+	"解析HTML失败: %v\n":      "Failed to parse HTML: %v\n",
+	"解析成功:\n":             "Parsed successfully:\n",
+	"标题: %s\n":            "Title: %s\n",
+	"风险级别: %s\n":          "Risk: %s\n",
+	"发布日期: %s\n":          "Published: %s\n",
+	"标签数量: %d\n":          "Tags: %d\n",
+	"标签: %v\n":            "Tag list: %v\n",
+	"作者: %s\n":            "Author: %s\n",
+	"作者URL: %s\n":         "Author URL: %s\n",
+	"序列化JSON失败: %v\n":     "Failed to marshal JSON: %v\n",
+	"写入文件失败: %v\n":        "Failed to write file: %v\n",
+	"不支持的语言 %q，可选值：zh、en": "unsupported language %q, expected zh or en",
+	"界面语言(zh或en)，默认根据LANG环境变量选择": "interface language (zh or en), chosen from the LANG environment variable by default",
+	"表格中显示完整的标题等长文本，不截断":         "show full titles and other long text in tables instead of truncating",
+	"在终端中显示完整的漏洞公告":              "Show a full advisory in the terminal",
+	"获取漏洞详情页并在终端中显示完整的公告：元数据按类型着色，\n正文按终端宽度自动换行，PoC等代码块语法高亮显示。": "Fetch a vulnerability page and show the full advisory in the terminal: colored metadata,\nbody text wrapped to the terminal width and syntax-highlighted PoC code blocks.",
+	"无效的漏洞ID: %q":    "invalid vulnerability ID: %q",
+	"同时将结果保存为JSON文件": "also save the result as a JSON file",
+	"以表格显示最新的漏洞列表":   "Show the latest vulnerabilities as a table",
+	"爬取CXSecurity网站最新发布的漏洞，以彩色表格显示，支持交互式翻页。\n可以使用 --risk、--remote 和 --local 按风险级别和利用方式过滤。": "Crawl the latest vulnerabilities from CXSecurity and show them as a colored table with interactive paging.\nUse --risk, --remote and --local to filter by risk level and attack vector.",
+	"无效的风险级别 %q，可选值：High、Med、Low": "invalid risk level %q, expected High, Med or Low",
+	"保存结果失败: %v\n":                "Failed to save result: %v\n",
+	"第 %d 页没有符合条件的漏洞":             "No matching vulnerabilities on page %d",
+	"起始页码":                        "page to start from",
+	"只显示指定风险级别的漏洞(High、Med、Low)，多个用逗号分隔": "only show vulnerabilities with these risk levels (High, Med, Low), comma-separated",
+	"只显示可远程利用的漏洞":                        "only show remotely exploitable vulnerabilities",
+	"只显示本地利用的漏洞":                         "only show locally exploitable vulnerabilities",
+	"将过滤后的结果保存为JSON文件":                   "save the filtered result as a JSON file",
+	"列表栏目(%s)，也可以是以 / 开头的列表页路径前缀":        "list section (%s), or a list page path prefix starting with /",
+	"无效的开始日期 %q，格式应为 YYYY-MM-DD":         "invalid start date %q, expected YYYY-MM-DD",
+	"无效的结束日期 %q，格式应为 YYYY-MM-DD":         "invalid end date %q, expected YYYY-MM-DD",
+	"开始日期不能晚于结束日期":                       "The start date cannot be later than the end date",
+	"只搜索该日期及之后发布的漏洞，格式为 YYYY-MM-DD":      "Only search vulnerabilities published on or after this date (YYYY-MM-DD)",
+	"只搜索该日期及之前发布的漏洞，格式为 YYYY-MM-DD":      "Only search vulnerabilities published on or before this date (YYYY-MM-DD)",
+	"管理保存的搜索":                            "Manage saved searches",
+	"保存命名的搜索条件，之后可以单独运行或一次运行全部。\n搜索条件保存在配置目录下的 saved_searches.json 中，可以通过环境变量 CXCRAWLER_CONFIG_DIR 指定配置目录。": "Save named search definitions and run them individually or all at once later.\nSearches are stored in saved_searches.json in the config directory, which can be set with the CXCRAWLER_CONFIG_DIR environment variable.",
+	"保存一条搜索":                   "Save a search",
+	"已保存搜索 %s":                 "Saved search %s",
+	"列出保存的搜索":                  "List saved searches",
+	"还没有保存的搜索，使用 saved add 添加": "No saved searches yet, add one with saved add",
+	"名称":       "Name",
+	"关键词":      "Keyword",
+	"过滤条件":     "Filters",
+	"创建时间":     "Created",
+	"删除保存的搜索":  "Remove a saved search",
+	"已删除搜索 %s": "Removed search %s",
+	"运行保存的搜索":  "Run saved searches",
+	"运行指定名称的搜索，使用 --all 运行全部保存的搜索。\n每条搜索只获取第1页的结果；某条搜索失败时继续运行其余的搜索。\n指定 --fail-on-new 或 --fail-on-risk 时，所有搜索都成功并且有满足条件的结果时以退出码2退出，用于CI。\n指定 --desktop-notify 时有结果的搜索会弹出桌面通知，配合 --diff-against 只通知新的结果。": "Run the named searches, or all saved searches with --all.\nOnly the first page of each search is fetched; if one search fails the rest still run.\nWith --fail-on-new or --fail-on-risk, exit with code 2 when all searches succeed and matching results exist, for use in CI.\nWith --desktop-notify, searches with results show a desktop notification; combine with --diff-against to be notified only about new results.",
+	"请指定搜索名称或使用 --all，两者不能同时使用": "specify search names or use --all, but not both",
+	"🔍 正在运行:":       "🔍 Running:",
+	"%d/%d 条搜索运行失败": "%d/%d searches failed",
+	"保存搜索的文件，默认为配置目录下的 saved_searches.json": "File holding saved searches, defaults to saved_searches.json in the config directory",
+	"每页结果数量，支持10或30，默认有过滤条件时为30，否则为10":      "Results per page, 10 or 30; defaults to 30 with filters, otherwise 10",
+	"排序顺序(ASC、DESC或RELEVANCE)，默认DESC":       "Sort order (ASC, DESC or RELEVANCE), defaults to DESC",
+	"覆盖同名的搜索":                    "Overwrite an existing search with the same name",
+	"运行全部保存的搜索":                  "Run all saved searches",
+	"将每条搜索的结果保存为该目录下的 <名称>.json": "Save each search's results as <name>.json in this directory",
+	"找出保存的结果中近似重复的公告":            "Find near-duplicate advisories in saved results",
+	"读取之前保存的JSON结果文件(exploit、list、search、author等命令的输出)，\n按标题相似度将同一漏洞被多次发布的公告分组，并显示包含多条记录的分组。\n使用 -o 保存带有 cluster_id 字段的结果，--collapse 时每组只保留一条。": "Read previously saved JSON result files (output of exploit, list, search, author, etc.),\ngroup advisories for the same vulnerability posted multiple times by title similarity, and show groups with more than one entry.\nUse -o to save the results with a cluster_id field; with --collapse only one entry per group is kept.",
+	"无效的相似度阈值 %v，范围应为 (0, 1]": "invalid similarity threshold %v, must be in (0, 1]",
+	"保存结果失败: %v":              "failed to save results: %v",
+	"🧬 近似重复:":                 "🧬 Near duplicates:",
+	"%d 条记录中有 %d 组":           "%[2]d groups in %[1]d entries",
+	"分组":                      "Group",
+	"标题相似度阈值(0到1)，越大越严格":      "title similarity threshold (0 to 1), higher is stricter",
+	"将带有分组ID的结果保存为JSON文件":     "save the results with group IDs as a JSON file",
+	"保存和输出时每组只保留第一条记录":        "keep only the first entry of each group when saving and printing",
+	"将保存的结果导出为知识图谱":           "Export saved results as a knowledge graph",
+	"读取之前保存的JSON结果文件，导出由作者、漏洞、CVE和受影响产品组成的知识图谱：\nAuthor-PUBLISHED->Vulnerability-REFERENCES->CVE-AFFECTS->Product\n\ncypher 格式可以通过 cypher-shell 导入Neo4j，graphml 格式可以在Gephi等工具中打开。\nCVE详情文件(cve命令的输出)会同时导入受影响的产品和相关漏洞。": "Read previously saved JSON result files and export a knowledge graph of authors, vulnerabilities, CVEs and affected products:\nAuthor-PUBLISHED->Vulnerability-REFERENCES->CVE-AFFECTS->Product\n\nThe cypher format can be loaded into Neo4j with cypher-shell; the graphml format opens in tools such as Gephi.\nCVE detail files (output of the cve command) also import affected products and related vulnerabilities.",
+	"不支持的图谱格式 %q，可选值：cypher、graphml":      "unsupported graph format %q, expected cypher or graphml",
+	"(%d 个节点, %d 条边)":                     "(%d nodes, %d edges)",
+	"解析结果文件 %s 失败: %w":                    "failed to parse result file %s: %w",
+	"输出格式(cypher或graphml)":                "output format (cypher or graphml)",
+	"输出文件路径，默认输出到标准输出":                    "output file path, defaults to standard output",
+	"根据标题、标签和正文将漏洞映射为MITRE ATT&CK技术编号":    "map vulnerabilities to MITRE ATT&CK technique IDs based on title, tags and content",
+	"ATT&CK分类规则JSON文件，代替内置规则，隐含 --attack": "JSON file with ATT&CK classification rules replacing the built-in ones; implies --attack",
+	"转换失败: ":   "Conversion failed: ",
+	"保存结果失败: ": "Failed to save results: ",
+	"以CVE JSON 5.0 (CVE Record Format) 格式保存和输出结果": "save and print the result in CVE JSON 5.0 (CVE Record Format)",
+	"根据关注列表生成CycloneDX VEX文档":                     "Generate a CycloneDX VEX document from the watchlist",
+	"将CVE详情中的受影响软件与关注列表(watch命令)中的产品匹配，生成CycloneDX 1.5 VEX文档。\nCVE详情可以来自cve命令保存的JSON文件，也可以用 -i 直接爬取。\ncxsecurity只给出受影响的产品而没有版本范围，默认分析状态为 in_triage，需要人工确认。": "Match the affected software of CVE details against the products in the watchlist (watch command) and generate a CycloneDX 1.5 VEX document.\nCVE details can come from JSON files saved by the cve command or be crawled directly with -i.\ncxsecurity lists affected products without version ranges, so the analysis state defaults to in_triage and needs manual review.",
+	"请指定CVE详情文件或使用 -i 指定CVE编号":                                            "specify CVE detail files or CVE IDs with -i",
+	"无效的分析状态 %q":                                                          "invalid analysis state %q",
+	"关注列表为空，请先使用 watch add 添加产品":                                          "the watchlist is empty, add products with watch add first",
+	"%s 不是CVE详情文件":                                                        "%s is not a CVE detail file",
+	"(%d 个漏洞, %d 个组件)":                                                    "(%d vulnerabilities, %d components)",
+	"要爬取的CVE编号，多个用逗号分隔":                                                   "CVE IDs to crawl, comma separated",
+	"VEX分析状态(in_triage、exploitable、not_affected、resolved、false_positive)": "VEX analysis state (in_triage, exploitable, not_affected, resolved, false_positive)",
+	"关注列表文件，默认为配置目录下的 watchlist.json":                                     "Watchlist file, defaults to watchlist.json in the config directory",
+	"管理关注的产品":                                                             "Manage watched products",
+	"维护关注的产品列表，用于将CVE详情中的受影响软件与自己使用的产品匹配。\n产品可以用厂商和产品名称指定，也可以用CPE 2.3名称指定。\n关注列表保存在配置目录下的 watchlist.json 中，可以通过环境变量 CXCRAWLER_CONFIG_DIR 指定配置目录。": "Maintain a list of watched products used to match the affected software in CVE details against the products you run.\nProducts are identified by vendor and product name, or by a CPE 2.3 name.\nThe watchlist is stored in watchlist.json in the config directory, which can be set with the CXCRAWLER_CONFIG_DIR environment variable.",
+	"添加关注的产品": "Add a watched product",
+	"已关注 %s":  "Now watching %s",
+	"列出关注的产品": "List watched products",
+	"还没有关注的产品，使用 watch add 添加": "No watched products yet, add one with watch add",
+	"厂商":       "Vendor",
+	"产品":       "Product",
+	"版本":       "Version",
+	"取消关注产品":   "Remove a watched product",
+	"已取消关注 %s": "Stopped watching %s",
+	"厂商名称，为空时匹配任意厂商": "vendor name; matches any vendor when empty",
+	"产品名称": "product name",
+	"CPE 2.3名称，例如 cpe:2.3:a:php:php:8.1.2:*:*:*:*:*:*:*": "CPE 2.3 name, e.g. cpe:2.3:a:php:php:8.1.2:*:*:*:*:*:*:*",
+	"正在使用的版本":                "version in use",
+	"覆盖同名的关注产品":              "Overwrite an existing watched product with the same name",
+	"--cve5 和 --csaf 不能同时使用": "--cve5 and --csaf cannot be used together",
+	"以CSAF 2.0安全公告格式保存和输出结果": "Save and print the result as a CSAF 2.0 security advisory",
+	"将保存的结果推送到DefectDojo":    "Push saved results to DefectDojo",
+	"读取之前保存的JSON结果文件(exploit、list、search、author等命令的输出)，\n以Generic Findings Import格式推送到DefectDojo。\n使用 --engagement 在指定engagement下新建test，使用 --test 重新导入到已有的test(按漏洞ID去重)。\n地址和Token也可以通过环境变量 DEFECTDOJO_URL 和 DEFECTDOJO_TOKEN 设置，\n地址、engagement和test标题的默认值可以保存在配置目录下的 notifications.json 中。": "Read previously saved JSON result files (output of exploit, list, search, author, etc.)\nand push them to DefectDojo in Generic Findings Import format.\nUse --engagement to create a new test in an engagement, or --test to reimport into an existing test (deduplicated by vulnerability ID).\nThe URL and token can also be set with the DEFECTDOJO_URL and DEFECTDOJO_TOKEN environment variables;\ndefaults for the URL, engagement and test title can be saved in notifications.json in the config directory.",
+	"请指定DefectDojo地址和Token":                          "Please specify the DefectDojo URL and token",
+	"请使用 --engagement 或 --test 指定导入位置":               "Please specify where to import with --engagement or --test",
+	"推送失败: %v":                                       "Push failed: %v",
+	"✅ 已推送 %d 条发现到DefectDojo":                        "✅ Pushed %d findings to DefectDojo",
+	"DefectDojo地址，例如 https://defectdojo.example.com": "DefectDojo URL, e.g. https://defectdojo.example.com",
+	"DefectDojo API v2 Token":                        "DefectDojo API v2 token",
+	"导入到的engagement ID，会新建一个test":                    "Engagement ID to import into; a new test is created",
+	"重新导入到的test ID，优先于 --engagement":                 "Test ID to reimport into; takes precedence over --engagement",
+	"新建test的标题":                                      "Title of the new test",
+	"为匹配关注列表的漏洞创建JIRA工单":                             "Create JIRA issues for vulnerabilities matching the watchlist",
+	"读取之前保存的JSON结果文件(exploit、list、search、author等命令的输出)，\n将标题中提到关注产品(watch命令)的漏洞创建为JIRA工单，工单中包含公告的元数据和链接。\n已创建的工单记录在配置目录下的 tickets.json 中，同一个漏洞不会重复创建工单。\n地址、用户和Token也可以通过环境变量 JIRA_URL、JIRA_USER 和 JIRA_TOKEN 设置；\n没有用户时使用Bearer认证(JIRA Server/Data Center的个人访问令牌)。\n地址、用户、项目、工单类型和标签的默认值可以保存在配置目录下的 notifications.json 中。": "Read previously saved JSON result files (output of exploit, list, search, author, etc.)\nand create a JIRA issue, with the advisory's metadata and links, for each vulnerability whose title mentions a watched product (see the watch command).\nCreated issues are recorded in tickets.json in the config directory, so the same vulnerability never gets more than one issue.\nThe URL, user and token can also be set with the JIRA_URL, JIRA_USER and JIRA_TOKEN environment variables;\nwithout a user, Bearer authentication is used (personal access token for JIRA Server/Data Center).\nDefaults for the URL, user, project, issue type and labels can be saved in notifications.json in the config directory.",
+	"请指定JIRA地址和Token":        "Please specify the JIRA URL and token",
+	"请使用 --project 指定JIRA项目": "Please specify the JIRA project with --project",
+	"为 %s 创建工单失败: %v":        "Failed to create an issue for %s: %v",
+	"没有匹配关注列表的漏洞":            "No vulnerabilities match the watchlist",
+	"关注的产品":                  "Watched product",
+	"状态":                     "Status",
+	"工单":                     "Issue",
+	"已创建":                    "Created",
+	"已存在":                    "Exists",
+	"未创建(dry-run)":           "Not created (dry-run)",
+	"JIRA地址，例如 https://example.atlassian.net": "JIRA URL, e.g. https://example.atlassian.net",
+	"JIRA用户名或邮箱":                              "JIRA username or email",
+	"JIRA API Token或个人访问令牌":                   "JIRA API token or personal access token",
+	"创建工单的项目Key，例如 SEC":                       "Project key to create issues in, e.g. SEC",
+	"工单类型":                                    "Issue type",
+	"工单标签，多个用逗号分隔":                            "Issue labels, comma separated",
+	"已创建工单的记录文件，默认为配置目录下的 tickets.json":       "File recording created issues; defaults to tickets.json in the config directory",
+	"只显示匹配的漏洞，不创建工单":                          "Only show matching vulnerabilities without creating issues",
+	"为匹配关注列表的高风险漏洞呼叫值班人员":                     "Page on-call engineers for high-risk vulnerabilities matching the watchlist",
+	"读取之前保存的JSON结果文件(exploit、list、search、author等命令的输出)，\n当标题中提到关注产品(watch命令)且风险级别为Critical或High的漏洞出现时，通过PagerDuty或Opsgenie告警。\n已告警的漏洞记录在配置目录下的 tickets.json 中，同一个漏洞不会重复告警。\n密钥也可以通过环境变量 PAGERDUTY_ROUTING_KEY 和 OPSGENIE_API_KEY 设置，Opsgenie地址的默认值可以保存在配置目录下的 notifications.json 中。": "Read previously saved JSON result files (output of exploit, list, search, author, etc.)\nand alert through PagerDuty or Opsgenie for each Critical or High vulnerability whose title mentions a watched product (see the watch command).\nAlerted vulnerabilities are recorded in tickets.json in the config directory, so the same vulnerability is never paged twice.\nThe keys can also be set with the PAGERDUTY_ROUTING_KEY and OPSGENIE_API_KEY environment variables, and a default Opsgenie URL can be saved in notifications.json in the config directory.",
+	"请指定PagerDuty集成密钥或Opsgenie API密钥": "Please specify a PagerDuty integration key or an Opsgenie API key",
+	"为 %s 发送告警失败: %v":                 "Failed to send an alert for %s: %v",
+	"没有需要告警的漏洞":                       "No vulnerabilities need alerting",
+	"告警系统":                            "Alerting system",
+	"已告警":                             "Paged",
+	"未告警(dry-run)":                    "Not paged (dry-run)",
+	"PagerDuty Events API v2集成密钥":     "PagerDuty Events API v2 integration key",
+	"Opsgenie API密钥":                  "Opsgenie API key",
+	"Opsgenie API地址，欧洲区域为 https://api.eu.opsgenie.com": "Opsgenie API URL; use https://api.eu.opsgenie.com for the EU region",
+	"已告警漏洞的记录文件，默认为配置目录下的 tickets.json":                "File recording alerted vulnerabilities; defaults to tickets.json in the config directory",
+	"只显示需要告警的漏洞，不发送告警":                                 "Only show vulnerabilities that would be paged without sending alerts",
+	"无效的文件模式 %s: %v\n":                                 "Invalid file pattern %s: %v\n",
+	"输出指标失败: %v\n":                                     "Failed to write metrics: %v\n",
+	"GET /metrics - Prometheus格式的公告统计\n":               "GET /metrics - Advisory statistics in Prometheus format\n",
+	"/metrics 统计的结果文件，支持通配符，每次抓取时重新读取":                 "Result files counted by /metrics; globs are supported and files are re-read on every scrape",
+	"/metrics 按天统计的天数":                                 "Number of days covered by the per-day statistics in /metrics",
+	"(%d 行)":                                           "(%d rows)",
+	"不支持的输出格式 %q，可选值：table、json、csv":                   "Unsupported output format %q, valid values: table, json, csv",
+	"列": "Column",
+	"对保存的结果执行只读SQL查询": "Run read-only SQL queries over saved results",
+	"将之前保存的JSON结果文件(exploit、list、search、author等命令的输出)作为 vulnerabilities 表，\n由嵌入的SQLite执行只读的SELECT查询，支持SQLite的全部查询语法和内置函数。\n使用 --schema 查看表中的列。": "Treat previously saved JSON result files (output of exploit, list, search, author, etc.) as the vulnerabilities table\nand run read-only SELECT queries on an embedded SQLite, with SQLite's full query syntax and built-in functions.\nUse --schema to list the table's columns.",
+	"无效的文件模式 %s: %v": "Invalid file pattern %s: %v",
+	"显示表中的列":         "Show the table's columns",
+	"查询失败: %v":       "Query failed: %v",
+	"没有匹配 %s 的文件":    "No files match %s",
+	"要查询的结果文件，支持通配符，可以指定多次": "Result files to query; globs are supported and the flag can be repeated",
+	"说明": "Description",
+	"请使用 -d 指定要查询的结果文件":    "Please specify the result files to query with -d",
+	"输出格式(table、json或csv)": "Output format (table, json or csv)",
+	"漏洞ID (WLB-XXXXXXXXX)": "Vulnerability ID (WLB-XXXXXXXXX)",
+	"发布日期 (YYYY-MM-DD)":    "Publication date (YYYY-MM-DD)",
+	"详情页URL":               "Detail page URL",
+	"风险级别 (High、Med.、Low)": "Risk level (High, Med., Low)",
+	"是否为远程漏洞":              "Whether the vulnerability is remote",
+	"是否为本地漏洞":              "Whether the vulnerability is local",
+	"作者页面URL":              "Author page URL",
+	"ATT&CK技术编号":           "ATT&CK technique IDs",
+	"近似重复分组ID":             "Near-duplicate cluster ID",
+	"将爬取到的原始HTML按日期保存到该目录，可以用 prune 命令按保留策略清理": "Save fetched raw HTML into this directory by date; clean it up with the prune command according to the retention policy",
+	"按保留策略清理过期的数据文件":                           "Delete expired data files according to the retention policy",
+	"按保留策略清理数据目录中过期的文件，避免长期运行的部署无限增长。\n原始HTML(.html/.htm，例如 --archive-html 保存的页面)默认保留90天，解析后的记录(.json/.jsonl)默认永久保留，其他文件不受影响。\n保留策略保存在配置目录下的 retention.json 中，可以用 --save 保存命令行指定的策略。\n保留时间支持 90d、4w、36h 这样的格式，forever 表示永久保留。": "Delete expired files from data directories according to the retention policy, keeping long-running deployments from growing unbounded.\nRaw HTML (.html/.htm, e.g. pages saved with --archive-html) is kept for 90 days by default, parsed records (.json/.jsonl) are kept forever, and other files are left alone.\nThe policy is stored in retention.json in the config directory; use --save to store the policy given on the command line.\nRetention periods accept formats like 90d, 4w and 36h; forever keeps files indefinitely.",
+	"清理 %s 失败: %w": "failed to prune %s: %w",
+	"%s: 将删除 %d 个文件 (%d 字节)，保留 %d 个文件":                "%s: would delete %d files (%d bytes), keeping %d files",
+	"%s: 已删除 %d 个文件 (%d 字节)，保留 %d 个文件":                "%s: deleted %d files (%d bytes), kept %d files",
+	"原始HTML的保留时间，例如 90d，覆盖保存的策略":                      "Retention for raw HTML, e.g. 90d; overrides the saved policy",
+	"解析后记录的保留时间，例如 365d 或 forever，覆盖保存的策略":            "Retention for parsed records, e.g. 365d or forever; overrides the saved policy",
+	"只列出将要删除的文件，不实际删除":                                "Only list the files that would be deleted, without deleting them",
+	"将生效的保留策略保存到配置文件，供之后的 prune 和 api --prune-dir 使用": "Save the effective retention policy to the config file for later prune runs and api --prune-dir",
+	"保留策略文件，默认为配置目录下的 retention.json":                 "Retention policy file (default: retention.json in the config directory)",
+	"读取保留策略失败，跳过本次清理: %v":                             "Failed to read the retention policy, skipping this prune: %v",
+	"清理 %s 失败: %v": "Failed to prune %s: %v",
+	"按保存的保留策略定期清理的数据目录，参见 prune 命令": "Data directories to prune periodically with the saved retention policy; see the prune command",
+	"清理 --prune-dir 的间隔":                         "Interval between prunes of --prune-dir",
+	"使用zstd压缩 --archive-html 保存的页面并记录内容的SHA-256": "Compress pages saved by --archive-html with zstd and record their SHA-256",
+	"输入HTML文件路径，支持zstd或gzip压缩的归档页面":              "Input HTML file path; zstd- or gzip-compressed archived pages are supported",
+	"将已有的JSON结果文件导入存储":                           "Import existing JSON result files into the store",
+	"将之前保存的JSON结果文件导入存储，按漏洞ID去重，从文件工作流升级时可以保留历史数据。\n支持exploit/list保存的漏洞列表、漏洞详情、search保存的搜索结果、作者信息、CVE详情和漏洞数组，目录会递归导入其中所有的 .json 文件。\n同一漏洞出现在多个文件中时合并为一条记录，非空的字段覆盖旧值。\n存储默认为配置目录下的 vulnerabilities.json，格式为漏洞数组，可以直接用于 sql -d 等命令。\n使用 --enrich-cve 在导入后为引用了CVE的漏洞补全CVE详情，与 enrich-cve 命令相同。": "Import previously saved JSON result files into the store, deduplicated by vulnerability ID, so history is kept when upgrading from file-based workflows.\nSupports vulnerability lists saved by exploit/list, vulnerability details, search results, author profiles, CVE details and vulnerability arrays; directories are imported recursively (all .json files).\nA vulnerability found in several files is merged into one record, with non-empty fields overriding older values.\nThe store defaults to vulnerabilities.json in the config directory. It is a vulnerability array and can be used directly with commands such as sql -d.\nUse --enrich-cve to backfill CVE details for vulnerabilities that reference a CVE after importing, same as the enrich-cve command.",
+	"导入 %s 失败: %w": "failed to import %s: %w",
+	"dry-run: 存储 %s 未修改，导入后将有 %d 条记录": "dry-run: store %s not modified; it would contain %d records after import",
+	"已导入到 %s，共 %d 条记录":                "Imported into %s, %d records in total",
+	"文件":                              "File",
+	"记录":                              "Records",
+	"新增":                              "Added",
+	"更新":                              "Updated",
+	"未变化":                             "Unchanged",
+	"跳过":                              "Skipped",
+	"存储文件，默认为配置目录下的 vulnerabilities.json": "Store file (default: vulnerabilities.json in the config directory)",
+	"只显示导入结果，不修改存储":                       "Only show the import results without modifying the store",
+	"导出和导入配置包":                            "Export and import configuration bundles",
+	"将关注列表、保存的搜索、通知设置和保留策略导出为可移植的YAML配置包，\n在另一台机器上导入，便于团队分享和迁移。配置包中不包含Token、API密钥等凭据。": "Export the watchlist, saved searches, notification settings and retention policy to a portable YAML bundle\nand import it on another machine, for team sharing and migration. Bundles never contain credentials such as tokens or API keys.",
+	"导出配置包": "Export a configuration bundle",
+	"已导出 %d 个关注的产品和 %d 条保存的搜索到 %s": "Exported %d watched products and %d saved searches to %s",
+	"导入配置包": "Import a configuration bundle",
+	"导入配置包中的关注列表、保存的搜索、通知设置和保留策略。\n本地已有的同名关注产品和搜索、已有的通知设置和保留策略默认保留不变，使用 --replace 覆盖。\n导入前会校验整个配置包，有无效的配置时不会修改任何文件。": "Import the watchlist, saved searches, notification settings and retention policy from a bundle.\nLocal watched products and searches with the same name, and existing notification settings and retention policy, are kept by default; use --replace to overwrite them.\nThe whole bundle is validated first, and no files are modified if anything in it is invalid.",
+	"读取配置包失败: %w": "failed to read bundle: %w",
+	"配置包是空的":      "The bundle is empty",
+	"保存的搜索":       "Saved search",
+	"通知设置":        "Notification settings",
+	"保留策略":        "Retention policy",
+	"已添加":         "Added",
+	"已覆盖":         "Replaced",
+	"已存在，保留本地配置":  "Exists, kept local",
+	"输出文件，默认输出到标准输出":     "Output file (default: standard output)",
+	"覆盖本地已有的同名配置":        "Overwrite existing local configuration with the same name",
+	"只显示将要导入的配置，不修改配置文件": "Only show what would be imported without modifying configuration files",
+	"受影响版本":     "Affected versions",
+	"平台":        "Platform",
+	"CVSS v3评分": "CVSS v3 score",
+	"CVSS v3向量": "CVSS v3 vector",
+	"CWE链接":     "CWE link",
+	"相关漏洞较多时最多额外请求的分页数，0表示只获取第一页": "Maximum number of extra pages to request when there are many related vulnerabilities, 0 fetches only the first page",
+	"（还有更多，可以调大 --related-pages）": " (more available, increase --related-pages)",
+	"输出结果的JSON Schema":            "Print the JSON Schema of outputs",
+	"输出各种结果的JSON Schema文档，供下游程序校验爬虫的输出。\n不指定类型时列出所有可用的类型。使用 --validate 校验JSON文件是否符合Schema，\"-\" 表示标准输入。": "Print the JSON Schema document of each output type so downstream programs can validate crawler output.\nLists all available types when no type is given. Use --validate to check whether a JSON file matches the schema, \"-\" means standard input.",
+	"校验时需要指定Schema类型，可选值：%v": "A schema type is required for validation, valid values: %v",
+	"读取文件失败: %w":             "failed to read file: %w",
+	"%s 符合Schema %s":         "%s matches schema %s",
+	"校验指定的JSON文件是否符合Schema，\"-\" 表示标准输入": "Validate that the given JSON file matches the schema, \"-\" means standard input",
+	"为存储中的漏洞补全CVE详情":                     "Backfill CVE details for stored vulnerabilities",
+	"遍历存储中引用了CVE编号、但还没有CVE详情的漏洞，逐个爬取CVE详情页面并保存到CVE详情存储。\n请求按 --delay 的间隔进行，遇到429/503时自动放慢。获取失败的CVE会记录下来，下次运行时重试，\n失败达到 --max-attempts 次后不再重试。CVE详情存储默认为配置目录下的 cve_details.json。\n指定 --refresh 后还会重新获取最后一次获取早于该时长的CVE，修改日期、评分、描述等发生变化时\n在引用它的漏洞上记录 cve_update，可以在 sql 中用 cve_updated_at 列查询，\n并通过 --webhook 或 --desktop-notify 发送通知。": "Walk stored vulnerabilities that reference a CVE but have no CVE details yet, crawl each CVE detail page and save it to the CVE detail store.\nRequests are spaced by --delay and slow down automatically on 429/503. Failed CVEs are recorded and retried on the next run,\nand are no longer retried after --max-attempts failures. The CVE detail store defaults to cve_details.json in the config directory.\nWith --refresh, CVEs last fetched longer ago than the given duration are fetched again; when the modified date, score, description or other fields change,\ncve_update is recorded on the vulnerabilities that reference the CVE, can be queried with the cve_updated_at column in sql,\nand notifications are sent via --webhook or --desktop-notify.",
+	"获取 %s": "Fetching %s",
+	"所有引用的CVE都已有详情，CVE详情存储 %s 共 %d 条": "All referenced CVEs already have details, CVE detail store %s has %d records",
+	"失败次数": "Attempts",
+	"错误":   "Error",
+	"已获取":  "Fetched",
+	"失败":   "Failed",
+	"待获取":  "Pending",
+	"已放弃":  "Given up",
+	"dry-run: %d 个CVE需要补全，%d 个已放弃":                         "dry-run: %d CVEs need backfilling, %d given up",
+	"已获取 %d 个，失败 %d 个，待获取 %d 个，已放弃 %d 个，CVE详情存储 %s 共 %d 条": "%d fetched, %d failed, %d pending, %d given up, CVE detail store %s has %d records",
+	"CVE详情存储文件，默认为配置目录下的 cve_details.json":                 "CVE detail store file, defaults to cve_details.json in the config directory",
+	"本次最多请求的CVE数量，0表示不限制":                                  "Maximum number of CVEs to request in this run, 0 means unlimited",
+	"请求CVE详情页面的最小间隔":                                       "Minimum interval between CVE detail page requests",
+	"同一CVE失败达到该次数后不再重试，0表示总是重试":                            "Stop retrying a CVE after this many failures, 0 means always retry",
+	"CVE页面相关漏洞最多额外请求的分页数，默认只获取第一页以减少请求":                    "Maximum number of extra related-vulnerability pages per CVE, defaults to the first page only to reduce requests",
+	"只列出需要补全的CVE，不发送请求":                                    "Only list the CVEs that need backfilling without sending requests",
+	"导入后为引用了CVE的漏洞补全CVE详情":                                 "Backfill CVE details for vulnerabilities that reference a CVE after importing",
+	"监控作者新发布的漏洞":                                           "Monitor authors for newly published vulnerabilities",
+	"重新爬取作者页面，将漏洞列表与上次保存的状态比较，只报告新发布的漏洞。\n第一次检查某个作者时只记录当前的漏洞作为基线，不会把历史发布当作新发布。\n状态保存在配置目录下的 authors.json 中。指定 --interval 后作为守护进程按间隔持续检查，\n收到中断信号时退出。指定 --webhook 后，有新发布时将通知以JSON格式POST到该地址，\n地址也可以通过环境变量 CXCRAWLER_WEBHOOK_URL 设置。在工作站上运行时可以指定 --desktop-notify 弹出桌面通知。": "Re-crawls author pages, compares their vulnerability lists with the previously saved state and reports only newly published items.\nThe first check of an author only records the current items as a baseline, so past publications are not reported as new.\nState is kept in authors.json under the config directory. With --interval the command runs as a daemon, checking at that interval\nuntil interrupted. With --webhook, new publications are POSTed as JSON to that URL;\nthe URL can also be set with the CXCRAWLER_WEBHOOK_URL environment variable. On a workstation, --desktop-notify shows desktop pop-ups.",
+	"无效的请求头: %s，格式应为 \"名称: 值\"":       "invalid header: %s, expected \"Name: value\"",
+	"部分作者检查失败":                        "some author checks failed",
+	"每 %s 检查一次 %d 个作者，按 Ctrl+C 退出":    "Checking %[2]d author(s) every %[1]s, press Ctrl+C to exit",
+	"检查作者 %s 失败: %v":                  "Failed to check author %s: %v",
+	"已记录基线":                           "baseline recorded",
+	"没有新发布":                           "no new publications",
+	"%d 个新发布":                         "%d new",
+	"检查间隔，例如 1h；为0时只检查一次":             "check interval, e.g. 1h; 0 checks once",
+	"作者状态文件，默认为配置目录下的 authors.json":   "author state file, defaults to authors.json in the config directory",
+	"有新发布时POST通知的地址":                  "URL to POST notifications to when there are new publications",
+	"Webhook请求头，格式为 \"名称: 值\"，可以指定多次": "webhook request header as \"Name: value\", can be repeated",
+	"只显示新发布的漏洞，不保存状态也不发送通知":           "only show new publications, without saving state or sending notifications",
+	"汇总一段时间内最值得关注的漏洞":                 "Summarize the most significant vulnerabilities in a period",
+	"汇总 --since 指定的时间段内发布的漏洞：风险最高的漏洞、被最多公告引用的CVE和发布最多的作者，\n可以输出为终端表格、Markdown或HTML，用于周报等定期报告。\n指定结果文件时从这些文件读取漏洞，否则读取存储(参见 import 命令)。没有发布日期的漏洞不会被统计。": "Summarizes vulnerabilities published within the --since period: the highest risk items, the CVEs referenced by the most advisories and the most active authors,\nas terminal tables, Markdown or HTML for weekly and other periodic reports.\nVulnerabilities are read from the given result files, or from the store (see the import command) when none are given. Items without a publication date are not counted.",
+	"不支持的摘要格式 %q，可选值：terminal、markdown、html": "unsupported digest format %q, valid values: terminal, markdown, html",
+	"📰 漏洞摘要": "📰 Vulnerability digest",
+	"共 %d 个漏洞: 高危 %d，中危 %d，低危 %d": "%d vulnerabilities: %d high, %d medium, %d low",
+	"风险最高的漏洞":                     "Highest risk",
+	"被引用最多的CVE":                   "Most referenced CVEs",
+	"最活跃的作者":                      "Most active authors",
+	"公告数":                         "Advisories",
+	"统计最近多长时间内发布的漏洞，例如 7d、4w、36h，forever 表示不限制": "period of publication to summarize, e.g. 7d, 4w, 36h; forever means no limit",
+	"每个排行榜输出的条数":                       "number of entries in each ranking",
+	"输出格式(terminal、markdown或html)":     "output format (terminal, markdown or html)",
+	"markdown和html格式的输出文件路径，默认输出到标准输出": "output file for the markdown and html formats, defaults to standard output",
+	"重新处理批量爬取中失败的条目":                   "Reprocess items that failed during bulk crawls",
+	"exploit -i 和 vex -i 批量爬取时获取或解析失败的条目会记录在重试队列中(配置目录下的 retry_queue.json)，\n该命令按失败次数从少到多重新爬取这些条目。成功的条目保存到存储中(漏洞详情保存到 --store，CVE详情保存到 --cve-store)，\n如果失败时指定了输出文件也会保存到该文件，然后从队列中删除。\n失败达到 --max-attempts 次的条目移到同一目录下的死信文件 dead_letter.json，不再重试；\n使用 --dead 查看死信文件中的条目，使用 --requeue 将它们放回队列。": "Items that failed to fetch or parse during exploit -i and vex -i bulk crawls are recorded in a retry queue (retry_queue.json in the config directory);\nthis command crawls them again, fewest failures first. Successful items are saved to the stores (vulnerability details to --store, CVE details to --cve-store),\nand to the output file given when they failed, if any, and are then removed from the queue.\nItems that fail --max-attempts times are moved to the dead-letter file dead_letter.json in the same directory and are no longer retried;\nuse --dead to list them and --requeue to put them back in the queue.",
+	"已将 %d 个条目放回重试队列":                   "Moved %d item(s) back to the retry queue",
+	"重试 %s %s":                          "Retrying %s %s",
+	"未知的条目类型: %s":                       "unknown item kind: %s",
+	"打开重试队列失败: %v":                      "Failed to open the retry queue: %v",
+	"%s 已失败 %d 次，移到死信文件 %s":             "%s has failed %d times, moved to dead-letter file %s",
+	"%s 已加入重试队列，可以稍后使用 retry-failed 重试": "%s was added to the retry queue, retry it later with retry-failed",
+	"保存重试队列失败: %v":                      "Failed to save the retry queue: %v",
+	"重试队列 %s 为空":                        "Retry queue %s is empty",
+	"成功":                                "succeeded",
+	"移到死信文件":                            "dead-lettered",
+	"待重试":                               "pending",
+	"成功 %d 个，失败 %d 个，移到死信文件 %d 个，待重试 %d 个": "%d succeeded, %d failed, %d dead-lettered, %d pending",
+	"死信文件 %s 为空": "Dead-letter file %s is empty",
+	"第一次失败":      "First failed",
+	"最后一次尝试":     "Last attempt",
+	"重试队列文件，默认为配置目录下的 retry_queue.json": "retry queue file, defaults to retry_queue.json in the config directory",
+	"同一条目失败达到该次数后移到死信文件，0表示总是重试":        "move an item to the dead-letter file after this many failures, 0 retries forever",
+	"本次最多重试的条目数，0表示不限制":                 "maximum number of items to retry in this run, 0 for no limit",
+	"请求的最小间隔":                "minimum interval between requests",
+	"只列出等待重试的条目，不发送请求":       "only list pending items without sending requests",
+	"列出死信文件中不再重试的条目":         "list items in the dead-letter file that are no longer retried",
+	"将死信文件中的条目放回重试队列并清零失败次数": "move dead-lettered items back to the retry queue and reset their failure counts",
+	"使用关键词在CXSecurity网站上搜索漏洞，并将结果保存为JSON格式。\n使用 --keywords-file 时并发搜索文件中的每个关键词(每行一个，# 开头的行为注释)，\n合并并去重结果，同时输出每个关键词搜索到的漏洞数。\n使用 --cve 时返回某个CVE对应的所有WLB公告：先从CVE详情页的相关公告列表获取，\nCVE页面没有列出公告时改为以CVE编号为关键词在网站上搜索，CVE编号的格式会先校验。\n使用 --all 时自动获取从 --page 开始的每一页(最多 --max-pages 页)，合并后保存为一个文件，\n代替交互式分页，适合在脚本中使用。": "Search CXSecurity for vulnerabilities by keyword and save the results as JSON.\nWith --keywords-file, every keyword in the file (one per line, lines starting with # are comments) is searched concurrently;\nthe results are merged and deduplicated, and the number of hits per keyword is reported.\nWith --cve, all WLB advisories for a CVE are returned: they are taken from the related advisories on the CVE detail page,\nfalling back to an on-site search for the CVE ID when the CVE page lists none. The CVE ID format is validated first.\nWith --all every page starting at --page (up to --max-pages pages) is fetched automatically and saved as one merged file,\nreplacing the interactive pagination for scripted use.",
+	"🔍 正在搜索 %d 个关键词:":      "🔍 Searching %d keywords:",
+	"(并发: %d, 每个关键词 %d 页)": "(concurrency: %d, %d page(s) per keyword)",
+	"每个关键词的结果":             "Results per keyword",
+	"漏洞数":                  "Hits",
+	"页数":                   "Pages",
+	"合并后的结果":               "Merged results",
+	"关键词列表文件，每行一个关键词，并发搜索后合并去重":            "keyword list file with one keyword per line, searched concurrently and merged without duplicates",
+	"使用 --keywords-file 时同时进行的搜索数":         "number of concurrent searches with --keywords-file",
+	"使用 --keywords-file 时每个关键词获取的页数":       "pages to fetch per keyword with --keywords-file",
+	"🆕 只显示 %s 中没有的结果":                      "🆕 Showing only results not in %s",
+	"只输出该结果文件中没有的漏洞，文件不存在时输出全部结果":          "only output vulnerabilities missing from this result file, all results if the file does not exist",
+	"有结果时以退出码2退出，配合 --diff-against 只统计新结果": "exit with code 2 when there are results; combine with --diff-against to count only new ones",
+	"有该风险级别及以上的结果时以退出码2退出(High、Med、Low)":   "exit with code 2 when there are results at or above this risk level (High, Med, Low)",
+	"发现 %d 个风险级别为 %s 及以上的漏洞":               "found %d vulnerabilities at risk level %s or above",
+	"发现 %d 个新漏洞": "found %d new vulnerabilities",
+	"为每个结果输出GitHub Actions注释(::error/::warning/::notice)，并将Markdown摘要追加到 $GITHUB_STEP_SUMMARY": "emit a GitHub Actions annotation (::error/::warning/::notice) per result and append a Markdown summary to $GITHUB_STEP_SUMMARY",
+	"Markdown摘要追加到该文件，默认为 $GITHUB_STEP_SUMMARY":                                                "append the Markdown summary to this file, defaults to $GITHUB_STEP_SUMMARY",
+	"输出GitHub Actions注释失败: %v": "Failed to write GitHub Actions annotations: %v",
+	"写入GitHub Actions摘要失败: %v": "Failed to write the GitHub Actions summary: %v",
+	"以声明式配置文件管理本地配置":           "Manage local configuration with a declarative config file",
+	"使用与 bundle export 相同格式的YAML文件声明完整的配置：关注列表、保存的搜索、通知设置和保留策略。\nplan 校验配置文件并显示与本地配置的差异，apply 使本地配置与配置文件一致。\n与 bundle import 合并配置不同，配置文件中没有的关注产品、搜索、通知设置和保留策略会被删除。": "Declare the complete configuration in a YAML file using the bundle export format: watchlist, saved searches, notification settings and retention policy.\nplan validates the file and shows how it differs from the local configuration; apply makes the local configuration match the file.\nUnlike bundle import, which merges, watched products, searches, notification settings and retention policies missing from the file are deleted.",
+	"显示应用配置文件将要进行的变更":                   "Show the changes applying the config file would make",
+	"使本地配置与配置文件一致":                      "Make the local configuration match the config file",
+	"是否应用以上变更？只有输入 yes 才会继续: ":          "Apply these changes? Only 'yes' will be accepted: ",
+	"已取消，没有修改任何配置":                      "Cancelled, no configuration was changed",
+	"✅ 已应用配置":                           "✅ Configuration applied",
+	"读取配置文件失败: %w":                      "failed to read the config file: %w",
+	"本地配置与配置文件一致，没有需要进行的变更":             "The local configuration matches the config file, no changes needed",
+	"计划: 添加 %d 项，修改 %d 项，删除 %d 项":       "Plan: %d to add, %d to change, %d to delete",
+	"有变更时以退出码2退出，没有变更时为0，用于检测配置漂移":      "exit with code 2 when there are changes and 0 when there are none, for drift detection",
+	"不询问确认，直接应用变更":                      "apply the changes without asking for confirmation",
+	"不输出颜色，也可以设置 NO_COLOR 环境变量":         "Disable colored output; setting the NO_COLOR environment variable has the same effect",
+	"只输出ASCII字符，表格边框使用 +-| 绘制，不显示emoji": "Output ASCII characters only: draw table borders with +-| and omit emoji",
+	"未找到 notify-send，请安装 libnotify":     "notify-send not found; please install libnotify",
+	"以及另外 %d 个":                         "and %d more",
+	"发送桌面通知失败: %v":                      "Failed to send desktop notification: %v",
+	"%s 发布了 %d 个新漏洞":                    "%s published %d new vulnerabilities",
+	"有新发布时显示桌面通知":                       "Show a desktop notification when there are new publications",
+	"保存的搜索 %s 有 %d 条结果":                 "Saved search %s has %d results",
+	"有结果时显示桌面通知":                        "Show a desktop notification for searches with results",
+	"可用的命令：\nsearch <关键词> [risk:High] - 搜索漏洞，只显示前5条\nshow <WLB-ID> - 查看漏洞详情\ncve <CVE编号> - 查看CVE详情\nhelp - 显示帮助\n不带命令时按关键词搜索，直接输入漏洞ID或CVE编号时查看详情": "Available commands:\nsearch <keywords> [risk:High] - search vulnerabilities, showing the first 5\nshow <WLB-ID> - show vulnerability details\ncve <CVE ID> - show CVE details\nhelp - show this help\nWithout a command the message is searched as keywords; a bare vulnerability ID or CVE ID shows its details",
+	"用法: show <WLB-ID>":                         "Usage: show <WLB-ID>",
+	"用法: cve <CVE编号>":                           "Usage: cve <CVE ID>",
+	"用法: search <关键词> [risk:High]":              "Usage: search <keywords> [risk:High]",
+	"搜索失败: %v":                                  "Search failed: %v",
+	"没有找到与 %q 相关的漏洞":                            "No vulnerabilities found for %q",
+	"%q 的搜索结果(第1页共%d页)：":                        "Results for %q (page 1 of %d):",
+	"%q 的搜索结果(第1页共%d页，显示前%d条)：":                 "Results for %q (page 1 of %d, first %d shown):",
+	"获取漏洞详情失败: %v":                              "Failed to fetch vulnerability details: %v",
+	"获取漏洞详情失败":                                  "Failed to fetch vulnerability details",
+	"获取CVE详情失败: %v":                             "Failed to fetch CVE details: %v",
+	"缺少或无效的Slack请求时间戳":                          "Missing or invalid Slack request timestamp",
+	"Slack请求已过期":                                "Slack request has expired",
+	"Slack请求签名无效":                               "Invalid Slack request signature",
+	"正在查询，请稍候...":                               "Looking that up, one moment...",
+	"发送Slack回复失败: %v":                           "Failed to send Slack reply: %v",
+	"Telegram请求的secret_token无效":                 "Invalid secret_token in Telegram request",
+	"POST /bot/slack - Slack斜杠命令\n":             "POST /bot/slack - Slack slash command\n",
+	"POST /bot/telegram - Telegram机器人Webhook\n": "POST /bot/telegram - Telegram bot webhook\n",
+	"Slack应用的签名密钥，指定后在 /bot/slack 响应斜杠命令，也可以通过环境变量 CXCRAWLER_SLACK_SIGNING_SECRET 设置":                "Slack app signing secret; when set, slash commands are answered at /bot/slack (can also be set with CXCRAWLER_SLACK_SIGNING_SECRET)",
+	"Telegram机器人Webhook的secret_token，指定后在 /bot/telegram 响应命令，也可以通过环境变量 CXCRAWLER_TELEGRAM_SECRET 设置": "secret_token of the Telegram bot webhook; when set, commands are answered at /bot/telegram (can also be set with CXCRAWLER_TELEGRAM_SECRET)",
+	"(由 %s 生成，未经人工核对)": "(generated by %s, not reviewed by a human)",
+	"AI摘要":             "AI summary",
+	"AI生成的摘要(模型: %s，未经人工核对)":                              "AI-generated summaries (model: %s, not reviewed by a human)",
+	"OpenAI兼容接口的地址，默认为本地Ollama http://localhost:11434/v1": "Base URL of an OpenAI-compatible API; defaults to local Ollama at http://localhost:11434/v1",
+	"使用大语言模型为存储中的漏洞生成摘要":                                  "Generate summaries for stored vulnerabilities with a large language model",
+	"使用的模型，默认为 llama3.1":                                  "Model to use; defaults to llama3.1",
+	"只列出需要生成摘要的漏洞，不调用模型":                                  "Only list vulnerabilities that need a summary without calling the model",
+	"只处理指定ID的漏洞，多个用逗号分隔":                                  "Only process vulnerabilities with these IDs, comma separated",
+	"将存储中漏洞的标题、描述和正文发送给OpenAI兼容的模型接口(例如OpenAI或本地的Ollama)，\n生成一段话的摘要并提取受影响的版本，保存在记录的 ai_summary 字段中。\n摘要标明为AI生成(ai_generated: true)并记录使用的模型，不会覆盖从页面解析出的字段。\n默认只处理还没有摘要且有正文或描述的记录，--force 重新生成已有的摘要。\n接口地址、模型和API Key也可以通过环境变量 CXCRAWLER_LLM_ENDPOINT、CXCRAWLER_LLM_MODEL 和 CXCRAWLER_LLM_API_KEY 设置。": "Sends the title, description and body of stored vulnerabilities to an OpenAI-compatible model API (such as OpenAI or a local Ollama)\nto produce a one-paragraph summary and extract affected versions, stored in the record's ai_summary field.\nSummaries are marked as AI-generated (ai_generated: true) with the model recorded, and never overwrite fields parsed from the page.\nBy default only records without a summary that have a body or description are processed; --force regenerates existing summaries.\nThe endpoint, model and API key can also be set with CXCRAWLER_LLM_ENDPOINT, CXCRAWLER_LLM_MODEL and CXCRAWLER_LLM_API_KEY.",
+	"已生成 %d 条，失败 %d 条，跳过 %d 条，待生成 %d 条": "%d generated, %d failed, %d skipped, %d pending",
+	"已生成":   "Generated",
+	"待生成":   "Pending",
+	"摘要或错误": "Summary or error",
+	"本次最多生成的摘要数量，0表示不限制":                         "Maximum number of summaries to generate in this run, 0 for no limit",
+	"模型接口的API Key，本地Ollama不需要":                   "API key for the model API; not needed for local Ollama",
+	"没有正文和描述":                                    "No body or description",
+	"没有需要生成摘要的漏洞":                                "No vulnerabilities need a summary",
+	"生成摘要 %s":                                    "Summarizing %s",
+	"部分漏洞生成摘要失败":                                 "Some summaries failed to generate",
+	"重新生成已有的摘要":                                  "Regenerate existing summaries",
+	"AI生成的摘要，未经人工核对，见 summarize 命令":              "AI-generated summary, not reviewed by a human; see the summarize command",
+	"读取AI摘要的存储文件，默认为配置目录下的 vulnerabilities.json": "store file to read AI summaries from, defaults to vulnerabilities.json in the config directory",
+	"%d 个CVE在上次获取后被修改":                           "%d CVEs were modified since last fetched",
+	"发现CVE被修改时POST通知的地址":                         "URL to POST a notification to when CVEs are modified",
+	"发现CVE被修改时显示桌面通知":                            "show a desktop notification when CVEs are modified",
+	"发送CVE修改通知失败: %v":                            "Failed to send CVE modification notification: %v",
+	"同时重新获取最后一次获取早于该时长的CVE并记录修改，例如 168h；为0时只补全缺少的CVE": "also re-fetch CVEs last fetched longer ago than this and record modifications, e.g. 168h; 0 only fills in missing CVEs",
+	"已修改":  "modified",
+	"没有变化": "unchanged",
+	"重新获取 %d 个，其中 %d 个在上次获取后被修改": "Re-fetched %d, %d of them modified since last fetched",
+	"错误或修改": "Error or changes",
+	"无效的CVE编号 %q，格式应为 CVE-YYYY-NNNN":                                       "Invalid CVE ID %q, expected CVE-YYYY-NNNN",
+	"返回该CVE对应的所有WLB公告，例如 CVE-2024-1234":                                    "return all WLB advisories for this CVE, e.g. CVE-2024-1234",
+	"🔍 正在查找CVE对应的公告:":                                                      "🔍 Looking up advisories for CVE:",
+	"(共 %d 条)":                                                             "(%d total)",
+	"/api/local 查询的存储文件，默认为配置目录下的 vulnerabilities.json":                    "Store file queried by /api/local, defaults to vulnerabilities.json in the config directory",
+	"/api/local/authors 查询的 watch-author 状态文件，默认为配置目录下的 authors.json":      "watch-author state file queried by /api/local/authors, defaults to authors.json in the config directory",
+	"GET /api/local/authors/{id}/vulnerabilities - 查询存储中作者的所有漏洞\n":         "GET /api/local/authors/{id}/vulnerabilities - List all stored vulnerabilities of an author\n",
+	"从存储中查询该作者的所有漏洞，不请求网站":                                                 "List all vulnerabilities of the author from the store without contacting the site",
+	"使用 --from-store 时只显示指定风险级别的漏洞(High、Med、Low)，多个用逗号分隔":                  "With --from-store only show vulnerabilities of these risk levels (High, Med, Low), comma separated",
+	"存储 %s 中没有作者 %s 的漏洞":                                                   "No vulnerabilities of author %[2]s in store %[1]s",
+	"👤 存储中作者 %s 的漏洞:":                                                      "👤 Stored vulnerabilities of author %s:",
+	"HTTP或SOCKS5代理地址，默认使用 HTTPS_PROXY 等环境变量":                               "HTTP or SOCKS5 proxy URL, defaults to HTTPS_PROXY and related environment variables",
+	"不支持的代理协议 %q，可选值：http、https、socks5":                                    "unsupported proxy scheme %q, valid values: http, https, socks5",
+	"无效的代理地址 %q，例如 http://127.0.0.1:8080 或 socks5://127.0.0.1:1080":        "invalid proxy URL %q, e.g. http://127.0.0.1:8080 or socks5://127.0.0.1:1080",
+	"--proxy 和 --proxy-pool 不能同时使用":                                        "--proxy and --proxy-pool cannot be used together",
+	"轮换使用的代理地址，可以多次指定，连续失败的代理会被暂时剔除；不能与 --proxy 同时使用":                      "Proxy URLs to rotate through, may be repeated; proxies that keep failing are temporarily ejected. Cannot be combined with --proxy",
+	"--proxy-pool 的轮换策略：round-robin(依次使用)、random(随机)、sticky(同一站点固定使用一个代理)": "Rotation strategy for --proxy-pool: round-robin (take turns), random, sticky (one proxy per site)",
+	"不支持的代理轮换策略 %q，可选值：round-robin、random、sticky":                          "unsupported proxy rotation strategy %q, valid values: round-robin, random, sticky",
+	"无效的请求速率 %g，不能小于0":                                                     "invalid rate limit %g, must not be negative",
+	"无效的超时时间 %s，应大于0":                                                      "invalid timeout %s, must be greater than 0",
+	"无效的重试次数 %d，不能小于0":                                                     "invalid retry count %d, must not be negative",
+	"每个HTTP请求的超时时间":                                                        "Timeout of each HTTP request",
+	"每秒最多发送的请求数，例如 0.5 表示每2秒一个请求，0表示不限速":                                   "Maximum requests per second, e.g. 0.5 means one request every 2 seconds; 0 means unlimited",
+	"添加到每个请求的HTTP头，格式为 \"名称: 值\"，可以多次指定":                                   "HTTP header added to every request in \"Name: Value\" form, can be repeated",
+	"JSON文件，按请求路径前缀添加HTTP头，例如访问漏洞详情时带上列表页的Referer":                         "JSON file of HTTP headers added by request path prefix, e.g. a list page Referer for exploit details",
+	"请求失败时的最大重试次数，0表示不重试":                                                  "Maximum retries of a failed request, 0 disables retries",
+	"  响应 %d 字节": "  response %d bytes",
+	"  错误: %v":   "  error: %v",
+	" (重试)":      " (retry)",
+	"-q/--quiet 和 -v/--verbose 不能同时使用":                 "-q/--quiet and -v/--verbose cannot be used together",
+	"只输出结果，不输出表格、提示和进度信息；--jq、--template 等机器格式的输出不受影响": "Only output results, without tables, hints or progress; machine formats such as --jq and --template are unaffected",
+	"向标准错误输出详细日志，-v 输出每个HTTP请求，-vv 同时输出响应大小和错误原因":      "Write detailed logs to stderr; -v logs every HTTP request, -vv also logs response sizes and error causes",
+	"请使用 -q/--quiet": "use -q/--quiet instead",
+	"(所有页, 最多 %d 页)": "(all pages, up to %d)",
+	"已获取 %d 页，共 %d 页，可以增大 --max-pages 获取更多结果":                   "Fetched %d of %d pages, increase --max-pages to get more results",
+	"自动获取从 --page 开始的所有页并合并为一个结果，代替交互式分页":                       "Fetch every page starting at --page and merge them into one result, replacing the interactive pagination",
+	"使用 --all 时最多获取的页数":                                         "Maximum number of pages fetched with --all",
+	"GET /api/exploit/latest?since={id}&limit=N - 获取上次之后的新漏洞\n": "GET /api/exploit/latest?since={id}&limit=N - Get vulnerabilities newer than the last call\n",
+	"可用的API端点（以下路径均可加上 /api/v1 前缀，未带版本的 /api/ 路径已废弃）：\n":        "Available API endpoints (every path also works under the /api/v1 prefix; unversioned /api/ paths are deprecated):\n",
+	"无效的停用日期 %q，格式应为 YYYY-MM-DD":                                "invalid sunset date %q, expected YYYY-MM-DD",
+	"未带版本的 /api/ 路径计划停用的日期（YYYY-MM-DD），通过 Sunset 响应头告知客户端":      "Date (YYYY-MM-DD) when the unversioned /api/ paths are scheduled to be removed, announced to clients in the Sunset header",
+	"%d 项检查失败":     "%d checks failed",
+	"%s 不是有效的JSON": "%s is not valid JSON",
+	"cxsecurity的地址，用于访问镜像或 selftest 使用的回放服务，默认为 https://cxsecurity.com": "cxsecurity address, for mirrors or the replay server used by selftest, defaults to https://cxsecurity.com",
+	"✅ 全部 %d 项检查通过":         "✅ All %d checks passed",
+	"❌ 自检失败:":               "❌ Selftest failed:",
+	"使用回放的归档页面端到端测试命令行和API": "End-to-end test the CLI and API against replayed archived pages",
+	"启动回放 docs/response-examples 中归档页面的本地服务，将爬虫指向该服务，\n以子进程运行 list、exploit、cve、author、search 命令并检查输出和保存的文件，\n再在进程内启动与 api 命令相同的路由，通过 pkg/apiclient 请求各个接口。\n整个过程不会访问cxsecurity，也不会修改配置目录，任一检查失败时以非零状态码退出。": "Starts a local server replaying the archived pages in docs/response-examples and points the crawler at it,\nruns the list, exploit, cve, author and search commands as subprocesses and checks their output and saved files,\nthen starts the same routes as the api command in-process and calls each endpoint through pkg/apiclient.\nNothing is fetched from cxsecurity and the config directory is not modified; exits non-zero if any check fails.",
+	"回放服务: %s\n": "Replay server: %s\n",
+	"无效的网站地址 %q，例如 https://cxsecurity.com": "invalid site address %q, e.g. https://cxsecurity.com",
+	"检查":          "Check",
+	"结果":          "Result",
+	"输出 %q，期望 %q": "output %q, expected %q",
+	"输出为空":        "output is empty",
+	"返回 %v，期望 %v": "returned %v, expected %v",
+	"通过":          "Passed",
+	"保存漏洞详情和CVE详情时，在结果文件旁写入记录每个字段来源选择器的 .provenance.json 调试文件":         "when saving vulnerability and CVE details, write a .provenance.json debug file next to the result recording the selector each field came from",
+	"规则JSON文件，按其中的CSS选择器从漏洞详情和CVE详情页提取字段到结果的 extras 中，并可以补充风险级别和标签的映射": "rules JSON file; fields matched by its CSS selectors on vulnerability and CVE detail pages are saved in the result's extras, and it can add risk level and tag mappings",
+	"%s 在CXSecurity发布的漏洞":                                 "Vulnerabilities published by %s on CXSecurity",
+	"%s 最近发布的漏洞，每 %s 更新一次":                                "Recent vulnerabilities published by %s, updated every %s",
+	"GET /api/author/{id}/feed - 作者新发布的RSS订阅\n":           "GET /api/author/{id}/feed - RSS feed of an author's new publications\n",
+	"作者订阅的更新间隔，同一作者的页面在间隔内最多爬取一次":                         "Author feed update interval; each author's page is crawled at most once per interval",
+	"在后台定期爬取的作者ID，/api/author/{id}/feed 直接使用爬取结果，多个用逗号分隔": "Author IDs crawled periodically in the background and served by /api/author/{id}/feed, comma-separated",
+	"更新作者 %s 的订阅失败: %v":                                   "Failed to update feed for author %s: %v",
+	"更新作者 %s 的订阅失败，使用上次的结果: %v":                           "Failed to update feed for author %s, serving the previous result: %v",
+	"GET /api/assets?url={url} - 代理作者头像和国旗图片\n":           "GET /api/assets?url={url} - Proxy author avatar and flag images\n",
+	"不允许代理该站点的图片: %s":                                     "Proxying images from this site is not allowed: %s",
+	"图片过大":                                                "Image is too large",
+	"无效的图片地址: %s":                                         "Invalid image URL: %s",
+	"缺少url参数":                                             "Missing url parameter",
+	"获取到的内容不是图片: %s":                                      "Fetched content is not an image: %s",
+	"获取图片失败: %w":                                          "Failed to fetch image: %w",
+	"原始出处":                                                "Original source",
+	"不参与比较的字段(JSON字段名，可用逗号分隔)，例如 views,comments":          "fields to leave out of the comparison (JSON field names, comma separated), e.g. views,comments",
+	"删除":           "Removed",
+	"变化":           "Changed",
+	"变化的字段":        "Changed fields",
+	"将差异保存为JSON文件": "save the differences as a JSON file",
+	"新增 %d，删除 %d，变化 %d，未变 %d": "%d added, %d removed, %d changed, %d unchanged",
+	"比较两个快照中的漏洞，用于审计两次归档之间发生了什么变化。\n快照可以是JSON结果文件(包括本地数据库文件)、通配符模式或目录，目录中的所有JSON文件作为一个快照。\n漏洞按ID匹配，没有ID时按URL匹配；变化的公告会列出值不同的字段。\n使用 --ignore 忽略每次都会变化的字段，例如 views、comments。": "Compare the vulnerabilities in two snapshots to audit what changed between two archive runs.\nA snapshot can be a JSON result file (including the local database file), a glob pattern or a directory; all JSON files in a directory form one snapshot.\nVulnerabilities are matched by ID, or by URL when there is no ID; changed advisories list the fields whose values differ.\nUse --ignore to skip fields that change on every run, such as views and comments.",
+	"比较两次保存的结果，列出新增、删除和变化的公告": "Compare two saved results and list added, removed and changed advisories",
+	"读取目录 %s 失败: %v": "failed to read directory %s: %v",
+	"🔍 快照差异:":        "🔍 Snapshot differences:",
+	"无效的日期显示方式: %v":  "invalid date display settings: %v",
+	"表格和报告中日期使用的时区，例如 Asia/Shanghai、Local，默认UTC；JSON结果中的日期始终为UTC": "time zone for dates in tables and reports, e.g. Asia/Shanghai or Local (default UTC); dates in JSON results are always UTC",
+	"表格和报告中日期的格式：iso、rfc3339、us、eu、long、cn 或Go时间格式，默认iso":         "date format for tables and reports: iso, rfc3339, us, eu, long, cn or a Go time layout (default iso)",
+	"已去掉 %d 条在多页中重复出现的记录":                                         "Removed %d records that appeared on more than one page",
+	"重复":         "Duplicates",
+	"网站上没有匹配的漏洞": "No matching vulnerabilities on the site",
+	"💡 网站返回了验证码或封禁页面，并不是没有结果；请用 --rate-limit 降低请求频率、用 --proxy 更换出口或稍后再试": "💡 The site returned a CAPTCHA or ban page, which does not mean there are no results; lower the request rate with --rate-limit, switch exit with --proxy or try again later",
+	"GET /api/status - 请求数、剩余预算和限速状态\n": "GET /api/status - request count, remaining budget and throttle state\n",
+	"无效的请求预算 %d，不能小于0":                  "invalid request budget %d, must not be negative",
+	"最多发送的请求数(包括重试)，用完后停止请求，0表示不限制":     "maximum number of requests to send (including retries); requests stop once it is used up, 0 means unlimited",
+	"%d 次运行": "%d runs",
+	"不将本次运行的请求统计记录到配置目录下的 runs.json":     "do not record request statistics for this run in runs.json in the config directory",
+	"保存运行统计失败: %v":                       "failed to save run statistics: %v",
+	"列出最近运行的请求统计":                        "list request statistics of recent runs",
+	"只显示指定命令的运行，例如 search、\"saved run\"": "only show runs of the given command, e.g. search, \"saved run\"",
+	"合计":   "Total",
+	"命令":   "Command",
+	"开始时间": "Started",
+	"最多显示的运行次数，0表示全部": "maximum number of runs to show, 0 shows all",
+	"查看之前的运行发出的HTTP请求，用于容量规划和检查爬取是否对网站足够友好。\n每次发出请求的命令结束后，请求数、流量、缓存命中和错误原因会追加到配置目录下的 runs.json 中，\n使用全局选项 --no-run-stats 可以不记录。": "Show the HTTP requests made by previous runs, for capacity planning and politeness audits.\nAfter every command that makes requests, the request count, bytes transferred, cache hits and error reasons are appended to runs.json in the config directory;\nuse the global option --no-run-stats to skip recording.",
+	"查看请求统计":        "Show request statistics",
+	"流量":            "Bytes",
+	"缓存命中":          "Cache hits",
+	"请求":            "Requests",
+	"运行记录 %s 中没有记录": "no runs recorded in %s",
+	"运行记录文件，默认为配置目录下的 runs.json": "run log file, defaults to runs.json in the config directory",
+	"重试": "Retries",
+	"... 还有 %d 个文件，完整列表见 %s":         "... %d more files, see %s for the full list",
+	"✅ 已保存 %d 个文件到":                  "✅ Saved %d files to",
+	"不支持的分组方式 %q，可选值：author、product": "unsupported grouping %q, valid values: author, product",
+	"分组方式(author或product)":           "how to group (author or product)",
+	"将漏洞拆分为每个作者或每个受影响产品一个JSON文件，便于按负责人分发报告材料。\n--by author 时保存为 <目录>/<作者>.json；\n--by product 时保存为 <目录>/<厂商>/<产品>.json，产品来自漏洞引用的CVE详情(参见 enrich-cve 命令)，\n影响多个产品的漏洞会出现在每个产品的文件中。没有作者或产品信息的漏洞保存在 _unknown.json 中。\n目录中同时生成列出所有文件的 index.json。指定结果文件时从这些文件读取漏洞，否则读取存储。": "Split vulnerabilities into one JSON file per author or per affected product, for handing evidence to each stakeholder.\nWith --by author files are saved as <dir>/<author>.json;\nwith --by product as <dir>/<vendor>/<product>.json, where products come from the CVE details referenced by each vulnerability (see the enrich-cve command);\na vulnerability affecting several products appears in each product's file. Vulnerabilities without author or product information are saved in _unknown.json.\nAn index.json listing all files is written to the directory as well. Reads the given result files, or the store when none are given.",
+	"按作者或产品将漏洞拆分导出到目录":   "Export vulnerabilities split per author or per product into a directory",
+	"请使用 -o 指定输出目录":      "please specify the output directory with -o",
+	"输出目录":               "output directory",
+	"(%d 个代码片段, %d 张截图)": "(%d code snippets, %d screenshots)",
+	"下载截图 %s 失败: %s\n":   "failed to download screenshot %s: %s\n",
+	"不下载公告中引用的图片":        "do not download images referenced by the advisory",
+	"不是图片(%s)":           "not an image (%s)",
+	"指定漏洞ID时，获取漏洞详情页并生成ZIP证据包，可以直接作为附件提交到工单或报告中。\n证据包包含解析后的JSON、原始HTML、从正文中提取的代码片段、公告中引用的图片和Markdown摘要。\n\nexport 和 import 子命令将关注列表、保存的搜索、通知设置和保留策略导出为可移植的YAML配置包，\n在另一台机器上导入，便于团队分享和迁移。配置包中不包含Token、API密钥等凭据。": "Given a vulnerability ID, fetches the advisory page and builds a ZIP evidence package that can be attached to tickets or reports as is.\nThe package contains the parsed JSON, the raw HTML, code snippets extracted from the body, images referenced by the advisory and a Markdown summary.\n\nThe export and import subcommands export the watchlist, saved searches, notification settings and retention policy as a portable YAML bundle\nto import on another machine for sharing and migration. Bundles contain no tokens, API keys or other credentials.",
+	"爬取失败: %v": "crawl failed: %v",
+	"生成漏洞证据包，或导出和导入配置包":            "Build an advisory evidence package, or export and import config bundles",
+	"证据包的保存路径，默认为 <WLB-ID>.zip":    "path of the evidence package, defaults to <WLB-ID>.zip",
+	"%d 个漏洞, %d 个CVE详情":            "%d vulnerabilities, %d CVE details",
+	"%s 的SHA-256与 %s 不一致":          "SHA-256 of %s does not match %s",
+	"⚠️ 未指定 --sign-key，发布的数据集没有签名": "⚠️ --sign-key not given, the published dataset is unsigned",
+	"✅ 校验通过，归档与清单一致":               "✅ Verified, the archive matches its manifest",
+	"✅ 校验通过，签名有效，归档与清单一致":          "✅ Verified, the signature is valid and the archive matches its manifest",
+	"发布日期范围: %s ~ %s":              "Published: %s ~ %s",
+	"大小":                           "Size",
+	"存储中没有漏洞，请先使用 import 导入结果":     "the store has no vulnerabilities, import results first with the import command",
+	"将存储中的漏洞和CVE详情打包为带版本号的数据集，用于作为开放数据集发布。\n每个版本在输出目录中生成以下文件：\n  cxsecurity-dataset-<版本>.tar.gz          JSONL格式的漏洞和CVE详情、对应的JSON Schema和清单\n  cxsecurity-dataset-<版本>.manifest.json   归档中的清单，列出每个文件的记录数和SHA-256\n  cxsecurity-dataset-<版本>.sha256          归档和清单的SHA-256，可以用 sha256sum -c 校验\n  cxsecurity-dataset-<版本>.sha256.sig      指定 --sign-key 时生成，校验和文件的Ed25519签名\n打包前会用JSON Schema校验所有记录；已发布的版本不会被覆盖，除非指定 --force。": "Packages the vulnerabilities and CVE details in the store into a versioned dataset for publishing as an open dataset.\nEach version produces these files in the output directory:\n  cxsecurity-dataset-<version>.tar.gz          vulnerabilities and CVE details as JSONL, their JSON Schemas and a manifest\n  cxsecurity-dataset-<version>.manifest.json   the manifest from the archive, listing record counts and SHA-256 of every file\n  cxsecurity-dataset-<version>.sha256          SHA-256 of the archive and manifest, checkable with sha256sum -c\n  cxsecurity-dataset-<version>.sha256.sig      Ed25519 signature of the checksum file, written when --sign-key is given\nAll records are validated against the JSON Schemas first; a published version is never overwritten unless --force is given.",
+	"将本地数据打包为可公开发布的数据集版本":            "Package the local corpus as a publishable dataset release",
+	"数据集版本，默认为当前UTC日期，例如 2024.05.01": "dataset version, defaults to the current UTC date, e.g. 2024.05.01",
+	"校验已发布的数据集":                      "Verify a published dataset",
+	"校验数据集归档：同目录下有 .sha256 文件时检查归档的SHA-256，\n指定 --public-key 时检查 .sha256.sig 签名，最后检查归档中每个文件与清单一致。": "Verifies a dataset archive: checks its SHA-256 when a .sha256 file sits next to it,\nchecks the .sha256.sig signature when --public-key is given, and finally checks every file in the archive against the manifest.",
+	"版本 %s 已发布(%s)，请指定新的 --version 或使用 --force 覆盖":                                                 "version %s is already published (%s), pass a new --version or --force to overwrite",
+	"用于校验签名的Ed25519公钥(PEM格式)":                                           "Ed25519 public key (PEM) to verify the signature with",
+	"用于签名的Ed25519私钥(PEM格式)，例如 openssl genpkey -algorithm ed25519 生成的密钥": "Ed25519 private key (PEM) to sign with, e.g. one generated by openssl genpkey -algorithm ed25519",
+	"覆盖已发布的同一版本":                                                        "overwrite an already published version",
+	"记录数":                                                               "Records",
+	"读取公钥失败: %w":                                                        "failed to read public key: %w",
+	"读取数据集归档失败: %w":                                                     "failed to read dataset archive: %w",
+	"读取校验和文件失败: %w":                                                     "failed to read checksum file: %w",
+	"读取签名失败: %w":                                                        "failed to read signature: %w",
+	"读取签名密钥失败: %w":                                                      "failed to read signing key: %w",
+	"使用 local-first 时本地记录的有效期，0表示永不过期":                                  "How long local records stay fresh with local-first, 0 means never expire",
+	"无效的 --fetch-ttl %s，不能为负数":                                          "invalid --fetch-ttl %s, must not be negative",
+	"获取漏洞详情和CVE详情的方式：network(总是请求网站)、local(只读本地存储)、local-first(优先使用未过期的本地记录，否则请求网站并写入本地存储)": "How vulnerability and CVE details are fetched: network (always request the site), local (read the local store only), local-first (use fresh local records, otherwise request the site and write to the local store)",
+	"--base-url 持续请求失败时依次尝试的镜像地址，可以多次指定，结果中的链接仍然使用 --base-url":                              "Mirror URL tried in order when --base-url keeps failing, can be repeated; links in results still use --base-url",
+	"无效的镜像健康检查间隔 %s，应大于等于0":                                                       "invalid mirror health check interval %s, must be at least 0",
+	"使用镜像时检查 --base-url 是否恢复的间隔，恢复后切换回来，0表示不检查":                                   "how often to check whether --base-url has recovered while using a mirror and switch back; 0 disables the check",
+	"等待cxsecurity响应超时(%s)，请稍后重试":                                                  "timed out waiting for cxsecurity (%s), please try again later",
+	"无效的 %s %s，应大于等于0":                                                            "invalid %s %s, must be at least 0",
+	"列表类接口(/exploit、/exploit/latest、作者订阅)等待cxsecurity的最长时间，超时返回504，0表示不限制":        "how long list endpoints (/exploit, /exploit/latest, author feeds) wait for cxsecurity before returning 504; 0 means no limit",
+	"详情类接口(/exploit/{id}、/cve/{id}、/author/{id})等待cxsecurity的最长时间，超时返回504，0表示不限制": "how long detail endpoints (/exploit/{id}, /cve/{id}, /author/{id}) wait for cxsecurity before returning 504; 0 means no limit",
+	"/search 等待cxsecurity的最长时间，超时返回504，0表示不限制":                                    "how long /search waits for cxsecurity before returning 504; 0 means no limit",
+	"显示版本和构建信息": "Show version and build information",
+	"显示cxcrawler的版本。使用 -v/--verbose 时同时显示构建的提交、Go版本、\n内置解析规则的版本和 --extract-rules 文件的摘要，报告问题时请附上这些信息。": "Show the cxcrawler version. With -v/--verbose also show the build commit, Go version,\nbuilt-in parser rules version and a digest of the --extract-rules file; please include them when reporting issues.",
+	" (有未提交的修改)": " (uncommitted changes)",
+	"提交":         "Commit",
+	"提交时间":       "Commit time",
+	"Go版本":       "Go version",
+	"解析规则":       "Parser rules",
+	"规则文件":       "Rules file",
+	"API版本":      "API version",
+	"诊断网络、代理、存储和解析器的问题": "Diagnose network, proxy, storage and parser problems",
+	"依次检查DNS解析、代理、能否访问网站(以及是否被拦截)、配置目录和本地存储，\n再请求几个内容固定的页面检查解析结果是否正确，发现网站改版导致的解析问题。\n使用与其他命令相同的全局选项，例如 --proxy、--base-url、--timeout 和 --header，\n对每个警告和失败给出处理建议。任一检查失败时以非零状态码退出。": "Check DNS resolution, the proxy, whether the site is reachable (and not blocking requests), the config directory and local storage,\nthen fetch a few pages with fixed content and verify the parsed results to catch parser breakage after site changes.\nUses the same global options as other commands, such as --proxy, --base-url, --timeout and --header,\nand suggests a fix for every warning and failure. Exits with a non-zero status if any check fails.",
+	"DNS解析":          "DNS",
+	"%s 是IP地址，不需要解析": "%s is an IP address, no lookup needed",
+	"本地无法解析域名，通过代理访问时可以忽略；否则请检查DNS设置": "the domain cannot be resolved locally; ignore this if the proxy resolves it, otherwise check your DNS settings",
+	"检查网络和DNS设置，或者使用 --proxy 通过代理访问":  "check your network and DNS settings, or use --proxy to go through a proxy",
+	"代理": "Proxy",
+	"检查 --proxy 或 HTTPS_PROXY 等环境变量中的代理地址，例如 http://127.0.0.1:8080": "check the proxy address in --proxy or HTTPS_PROXY and similar variables, e.g. http://127.0.0.1:8080",
+	"未使用代理": "no proxy",
+	"代理无法连接，检查代理服务是否运行、地址和端口是否正确": "cannot connect to the proxy; check that it is running and the host and port are correct",
+	"网站连接": "Site connectivity",
+	"--max-requests 太小，诊断至少需要4个请求":                             "--max-requests is too small, the diagnosis needs at least 4 requests",
+	"检查网络、--proxy 和 --base-url；网站暂时不可用时稍后再试，或使用 --mirror 指定镜像": "check your network, --proxy and --base-url; if the site is down, try again later or use --mirror",
+	"请求被网站拦截(%s)":               "requests are blocked by the site (%s)",
+	"降低 --rate-limit、更换代理或稍后再试": "lower --rate-limit, switch proxies or try again later",
+	"%s，耗时 %s":                  "%s, took %s",
+	"响应很慢，接近 --timeout，可以增大 --timeout 或使用 --rate-limit 降低请求频率": "responses are slow and close to --timeout; increase --timeout or lower the request rate with --rate-limit",
+	"配置目录": "Config directory",
+	"配置目录不可写，检查目录权限或设置 %s 指向可写的目录": "the config directory is not writable; check its permissions or point %s at a writable directory",
+	"漏洞存储": "Vulnerability store",
+	"存储文件无法读取，从备份恢复或移走该文件后用 import 重新导入": "the store file cannot be read; restore it from a backup, or move it away and re-import with import",
+	"%s，%d 条记录": "%s, %d records",
+	"CVE详情存储":   "CVE detail store",
+	"存储文件无法读取，从备份恢复或移走该文件后用 enrich-cve 重新获取": "the store file cannot be read; restore it from a backup, or move it away and fetch again with enrich-cve",
+	"%d 条记录":        "%d records",
+	"解析器: 漏洞列表":     "Parser: exploit list",
+	"没有解析出漏洞的ID和标题": "no exploit ID and title parsed",
+	"解析器: 漏洞详情":     "Parser: exploit detail",
+	"没有解析出标题":       "no title parsed",
+	"解析器: CVE详情":    "Parser: CVE detail",
+	"没有解析出描述":       "no description parsed",
+	"无法访问网站":        "site unreachable",
+	"网站的页面结构可能已经改变(解析规则 %s)，请升级cxcrawler并附上 version -v 的输出报告问题": "the site layout may have changed (parser rules %s); upgrade cxcrawler and report the issue with the output of version -v",
+	"警告":        "Warning",
+	"❌ 诊断发现问题:": "❌ Problems found:",
+	"⚠️ 没有失败的检查，%d 项检查有警告": "⚠️ No failed checks, %d checks with warnings",
+
+	"镜像列表栏目中所有漏洞的详情到本地存储": "Mirror the details of every vulnerability in a list section into the local store",
+	"爬取列表栏目的列表页和其中每个漏洞的详情页，保存到 --store 指定的存储中。\n列表页和详情页按优先级调度：新发布的High风险漏洞先爬取，越旧的回填页面越晚爬取；\n列表页和详情页分别遵守 --list-delay 和 --detail-delay 的礼貌延迟。\n已镜像的详情页记录在 --seen-dir 目录中(默认为配置目录下的 mirror_seen)，再次运行时跳过，\n因此中断后重新运行或定期运行只会爬取新的漏洞；删除该目录后会重新爬取全部详情页。\n单个详情页失败不会中断镜像；收到中断信号时保存已爬取的结果后退出。": "Crawls the list pages of a section and the detail page of every vulnerability on them, saving into the store given by --store.\nList and detail pages are scheduled by priority: newly published High-risk vulnerabilities are crawled first and older backfill pages later;\nlist and detail pages observe the --list-delay and --detail-delay politeness delays independently.\nMirrored detail pages are recorded in the --seen-dir directory (default: mirror_seen in the config directory) and skipped on later runs,\nso rerunning after an interruption or on a schedule only crawls new vulnerabilities; delete the directory to crawl every detail page again.\nA failed detail page does not stop the mirror; on interrupt the results crawled so far are saved before exiting.",
+	"已爬取 %s %s": "Crawled %s %s",
+	"爬取了 %d 个列表页、%d 个漏洞详情，失败 %d 个，跳过已镜像的 %d 个，已保存到 %s": "Crawled %d list pages and %d vulnerability details, %d failed, %d already mirrored skipped, saved to %s",
+	"记录已镜像详情页的目录，默认为配置目录下的 mirror_seen":                "Directory recording mirrored detail pages (default: mirror_seen in the config directory)",
+	"最多爬取的列表页数，0表示爬取到最后一页":                             "Maximum number of list pages to crawl, 0 crawls to the last page",
+	"两次请求列表页之间的礼貌延迟":                                   "Politeness delay between two list page requests",
+	"两次请求详情页之间的礼貌延迟":                                   "Politeness delay between two detail page requests",
+	"不根据429/503响应和响应时间突增自动放慢请求速率":                      "Do not automatically slow down requests on 429/503 responses or response-time spikes",
+	"输出API的OpenAPI文档":                                  "Print the OpenAPI document of the API",
+	"输出 /api/v1 接口的OpenAPI 3.0文档(JSON格式)，可以用 openapi-generator 等工具生成其他语言的客户端。\n仓库中的 docs/openapi.json 由这个命令生成，make clients 使用它生成TypeScript和Python客户端。": "Print the OpenAPI 3.0 document (JSON) of the /api/v1 endpoints, which tools such as openapi-generator can use to generate clients in other languages.\nThe docs/openapi.json file in the repository is generated by this command; make clients uses it to generate the TypeScript and Python clients.",
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectLang(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(key string) string { return vars[key] }
+	}
+
+	assert.Equal(t, langZH, detectLang(nil, env(nil)))
+	assert.Equal(t, langZH, detectLang(nil, env(map[string]string{"LANG": "C.UTF-8"})))
+	assert.Equal(t, langZH, detectLang(nil, env(map[string]string{"LANG": "zh_CN.UTF-8"})))
+	assert.Equal(t, langEN, detectLang(nil, env(map[string]string{"LANG": "en_US.UTF-8"})))
+	assert.Equal(t, langEN, detectLang(nil, env(map[string]string{"LANG": "zh_CN.UTF-8", "LC_ALL": "de_DE.UTF-8"})))
+
+	// --lang 优先于环境变量
+	assert.Equal(t, langEN, detectLang([]string{"search", "--lang", "en"}, env(map[string]string{"LANG": "zh_CN.UTF-8"})))
+	assert.Equal(t, langZH, detectLang([]string{"--lang=zh", "cve"}, env(map[string]string{"LANG": "en_US.UTF-8"})))
+	// -- 之后的参数不再解析
+	assert.Equal(t, langZH, detectLang([]string{"--", "--lang=en"}, env(nil)))
+}
+
+func TestT(t *testing.T) {
+	defer func(lang string) { currentLang = lang }(currentLang)
+
+	currentLang = langZH
+	assert.Equal(t, "作者信息", T("作者信息"))
+
+	currentLang = langEN
+	assert.Equal(t, "Author Profile", T("作者信息"))
+	// 目录中没有的消息返回原文
+	assert.Equal(t, "没有翻译的消息", T("没有翻译的消息"))
+}
+
+func TestValidateLang(t *testing.T) {
+	defer func(lang string) { outputLang = lang }(outputLang)
+
+	for _, lang := range []string{"", "zh", "en"} {
+		outputLang = lang
+		assert.NoError(t, validateLang())
+	}
+	outputLang = "fr"
+	assert.Error(t, validateLang())
+}
+
+// TestEnMessagesComplete 检查cmd包中所有 T("...") 的消息在英文目录中都有翻译
+func TestEnMessagesComplete(t *testing.T) {
+	files, err := filepath.Glob("*.go")
+	require.NoError(t, err)
+
+	fset := token.NewFileSet()
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, file, nil, 0)
+		require.NoError(t, err)
+
+		ast.Inspect(f, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) != 1 {
+				return true
+			}
+			if id, ok := call.Fun.(*ast.Ident); !ok || id.Name != "T" {
+				return true
+			}
+			lit, ok := call.Args[0].(*ast.BasicLit)
+			if !ok || lit.Kind != token.STRING {
+				return true
+			}
+			msg, err := strconv.Unquote(lit.Value)
+			require.NoError(t, err)
+			_, ok = enMessages[msg]
+			assert.True(t, ok, "%s: 缺少英文翻译: %q", fset.Position(lit.Pos()), msg)
+			return true
+		})
+	}
+}
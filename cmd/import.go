@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/config"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/store"
+)
+
+var (
+	storeFile       string
+	importDryRun    bool
+	importEnrichCVE bool
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import <dir|file...>",
+	Short: T("将已有的JSON结果文件导入存储"),
+	Long: T(`将之前保存的JSON结果文件导入存储，按漏洞ID去重，从文件工作流升级时可以保留历史数据。
+支持exploit/list保存的漏洞列表、漏洞详情、search保存的搜索结果、作者信息、CVE详情和漏洞数组，目录会递归导入其中所有的 .json 文件。
+同一漏洞出现在多个文件中时合并为一条记录，非空的字段覆盖旧值。
+存储默认为配置目录下的 vulnerabilities.json，格式为漏洞数组，可以直接用于 sql -d 等命令。
+使用 --enrich-cve 在导入后为引用了CVE的漏洞补全CVE详情，与 enrich-cve 命令相同。`),
+	Example: `  cxcrawler import results/
+  cxcrawler import exploits.json search_result.json --store data/vulnerabilities.json
+  cxcrawler import results/ --enrich-cve --limit 100`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := openStore(storeFile)
+		if err != nil {
+			return err
+		}
+
+		var reports []store.FileReport
+		for _, path := range args {
+			r, err := s.Import(path)
+			if err != nil {
+				return fmt.Errorf(T("导入 %s 失败: %w"), path, err)
+			}
+			reports = append(reports, r...)
+		}
+		if !importDryRun {
+			if err := s.Save(); err != nil {
+				return err
+			}
+		}
+
+		var enriched []store.EnrichResult
+		var cves *store.CVEStore
+		if importEnrichCVE {
+			if enriched, cves, err = enrichStoredCVEs(s, importDryRun); err != nil {
+				return err
+			}
+		}
+
+		if formattedOutputEnabled() {
+			if importEnrichCVE {
+				printFormatted(map[string]any{"files": reports, "cves": enriched})
+			} else {
+				printFormatted(reports)
+			}
+			return nil
+		}
+		if quietOutput {
+			return nil
+		}
+		printImportReports(reports)
+		if importDryRun {
+			fmt.Printf(T("dry-run: 存储 %s 未修改，导入后将有 %d 条记录")+"\n", s.Path(), s.Len())
+		} else {
+			fmt.Printf(T("已导入到 %s，共 %d 条记录")+"\n", s.Path(), s.Len())
+		}
+		if importEnrichCVE {
+			printEnrichResults(enriched, cves, importDryRun)
+		}
+		return nil
+	},
+}
+
+// openStore 打开存储，path为空时使用配置目录下的vulnerabilities.json
+func openStore(path string) (*store.Store, error) {
+	if path == "" {
+		var err error
+		if path, err = config.Path(store.DefaultFile); err != nil {
+			return nil, err
+		}
+	}
+	return store.Open(path, store.WithPermissions(outputFileMode, outputDirMode))
+}
+
+// printImportReports 以表格形式输出每个文件的导入结果
+func printImportReports(reports []store.FileReport) {
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(tableStyle(table.StyleRounded))
+	t.AppendHeader(table.Row{T("文件"), T("记录"), T("新增"), T("更新"), T("未变化"), T("跳过")})
+	for _, r := range reports {
+		if r.Error != "" {
+			t.AppendRow(table.Row{r.File, text.Colors{text.FgHiRed}.Sprint(truncateCell(r.Error, 60))})
+			continue
+		}
+		t.AppendRow(table.Row{
+			r.File,
+			r.Records,
+			text.Colors{text.FgHiGreen}.Sprint(r.Added),
+			text.Colors{text.FgHiCyan}.Sprint(r.Updated),
+			r.Unchanged,
+			r.Skipped,
+		})
+	}
+	t.Render()
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+
+	importCmd.Flags().StringVar(&storeFile, "store", "", T("存储文件，默认为配置目录下的 vulnerabilities.json"))
+	importCmd.Flags().BoolVar(&importDryRun, "dry-run", false, T("只显示导入结果，不修改存储"))
+	importCmd.Flags().BoolVar(&importEnrichCVE, "enrich-cve", false, T("导入后为引用了CVE的漏洞补全CVE详情"))
+	addEnrichFlags(importCmd)
+}
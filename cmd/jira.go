@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/config"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/logging"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/sink"
+)
+
+var (
+	jiraURL         string
+	jiraUser        string
+	jiraToken       string
+	jiraProject     string
+	jiraIssueType   string
+	jiraLabels      []string
+	jiraTicketsFile string
+	jiraDryRun      bool
+)
+
+// jiraResult 是一个匹配到关注产品的漏洞的处理结果
+type jiraResult struct {
+	ID      string `json:"id"`
+	Title   string `json:"title"`
+	Product string `json:"product"`
+	Status  string `json:"status"` // created、exists 或 dry-run
+	Key     string `json:"key,omitempty"`
+	URL     string `json:"url,omitempty"`
+}
+
+var jiraCmd = &cobra.Command{
+	Use:   "jira <file.json...>",
+	Short: T("为匹配关注列表的漏洞创建JIRA工单"),
+	Long: T(`读取之前保存的JSON结果文件(exploit、list、search、author等命令的输出)，
+将标题中提到关注产品(watch命令)的漏洞创建为JIRA工单，工单中包含公告的元数据和链接。
+已创建的工单记录在配置目录下的 tickets.json 中，同一个漏洞不会重复创建工单。
+地址、用户和Token也可以通过环境变量 JIRA_URL、JIRA_USER 和 JIRA_TOKEN 设置；
+没有用户时使用Bearer认证(JIRA Server/Data Center的个人访问令牌)。
+地址、用户、项目、工单类型和标签的默认值可以保存在配置目录下的 notifications.json 中。`),
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if jiraURL == "" {
+			jiraURL = os.Getenv("JIRA_URL")
+		}
+		if jiraUser == "" {
+			jiraUser = os.Getenv("JIRA_USER")
+		}
+		if jiraToken == "" {
+			jiraToken = os.Getenv("JIRA_TOKEN")
+		}
+		logging.AddSecret(jiraToken)
+		if err := applyJiraDefaults(cmd); err != nil {
+			return err
+		}
+		if !jiraDryRun && (jiraURL == "" || jiraToken == "") {
+			return errors.New(T("请指定JIRA地址和Token"))
+		}
+		if jiraProject == "" {
+			return errors.New(T("请使用 --project 指定JIRA项目"))
+		}
+
+		products, err := loadWatchlist()
+		if err != nil {
+			return err
+		}
+		var items []model.Vulnerability
+		for _, path := range args {
+			loaded, err := crawler.LoadResultFile(path)
+			if err != nil {
+				return err
+			}
+			items = append(items, loaded...)
+		}
+
+		store, err := config.NewTicketStore(jiraTicketsFile)
+		if err != nil {
+			return err
+		}
+		jira := sink.NewJira(jiraURL, jiraUser, jiraToken,
+			sink.WithJiraProject(jiraProject),
+			sink.WithJiraIssueType(jiraIssueType),
+			sink.WithJiraLabels(jiraLabels...))
+
+		var results []jiraResult
+		for _, m := range matchWatchlistTitles(products, items) {
+			v := m.Vulnerability
+			if v.ID == "" {
+				continue
+			}
+			result := jiraResult{ID: v.ID, Title: v.Title, Product: m.Product.Name}
+
+			ticket, ok, err := store.Get("jira", v.ID)
+			if err != nil {
+				return err
+			}
+			switch {
+			case ok:
+				result.Status, result.Key, result.URL = "exists", ticket.Key, ticket.URL
+			case jiraDryRun:
+				result.Status = "dry-run"
+			default:
+				issue, err := jira.CreateIssue(v, m.Product.Name)
+				if err != nil {
+					return fmt.Errorf(T("为 %s 创建工单失败: %v"), v.ID, err)
+				}
+				if err := store.Add(config.Ticket{System: "jira", VulnID: v.ID, Key: issue.Key, URL: issue.URL}); err != nil {
+					return err
+				}
+				result.Status, result.Key, result.URL = "created", issue.Key, issue.URL
+			}
+			results = append(results, result)
+		}
+
+		if printFormatted(results) || quietOutput {
+			return nil
+		}
+		printJiraResults(results)
+		return nil
+	},
+}
+
+// printJiraResults 以表格形式输出处理结果
+func printJiraResults(results []jiraResult) {
+	if len(results) == 0 {
+		fmt.Println(text.Colors{text.FgHiYellow}.Sprint(T("没有匹配关注列表的漏洞")))
+		return
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(tableStyle(table.StyleRounded))
+	t.AppendHeader(table.Row{"ID", T("关注的产品"), T("状态"), T("工单"), T("标题")})
+	for _, r := range results {
+		status := r.Status
+		switch r.Status {
+		case "created":
+			status = text.Colors{text.FgHiGreen}.Sprint(T("已创建"))
+		case "exists":
+			status = text.Colors{text.FgHiBlack}.Sprint(T("已存在"))
+		case "dry-run":
+			status = text.Colors{text.FgHiYellow}.Sprint(T("未创建(dry-run)"))
+		}
+		t.AppendRow(table.Row{
+			text.Colors{text.FgHiCyan}.Sprint(r.ID),
+			r.Product,
+			status,
+			r.Key,
+			truncateCell(r.Title, 50),
+		})
+	}
+	t.Render()
+}
+
+func init() {
+	rootCmd.AddCommand(jiraCmd)
+
+	jiraCmd.Flags().StringVar(&jiraURL, "url", "", T("JIRA地址，例如 https://example.atlassian.net"))
+	jiraCmd.Flags().StringVar(&jiraUser, "user", "", T("JIRA用户名或邮箱"))
+	jiraCmd.Flags().StringVar(&jiraToken, "token", "", T("JIRA API Token或个人访问令牌"))
+	jiraCmd.Flags().StringVar(&jiraProject, "project", "", T("创建工单的项目Key，例如 SEC"))
+	jiraCmd.Flags().StringVar(&jiraIssueType, "issue-type", "Bug", T("工单类型"))
+	jiraCmd.Flags().StringSliceVar(&jiraLabels, "labels", []string{"cxsecurity"}, T("工单标签，多个用逗号分隔"))
+	jiraCmd.Flags().StringVar(&watchFile, "watchlist", "", T("关注列表文件，默认为配置目录下的 watchlist.json"))
+	jiraCmd.Flags().StringVar(&jiraTicketsFile, "tickets", "", T("已创建工单的记录文件，默认为配置目录下的 tickets.json"))
+	jiraCmd.Flags().BoolVar(&jiraDryRun, "dry-run", false, T("只显示匹配的漏洞，不创建工单"))
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/itchyny/gojq"
+)
+
+var (
+	outputJQ    string
+	outputJQRaw bool
+)
+
+// compileJQ 解析并编译jq表达式
+func compileJQ(query string) (*gojq.Code, error) {
+	q, err := gojq.Parse(query)
+	if err != nil {
+		return nil, fmt.Errorf(T("解析jq表达式失败: %w"), err)
+	}
+	code, err := gojq.Compile(q)
+	if err != nil {
+		return nil, fmt.Errorf(T("编译jq表达式失败: %w"), err)
+	}
+	return code, nil
+}
+
+// validateOutputJQ 在命令开始执行前编译 --jq 指定的表达式
+// 表达式有误时返回错误，命令以非零状态退出，不会先爬取再失败
+func validateOutputJQ() error {
+	if outputJQ == "" {
+		return nil
+	}
+	_, err := compileJQ(outputJQ)
+	return err
+}
+
+// runJQ 对结果执行jq查询并将查询结果写入w
+// 结果先按JSON输出时的字段名转换，因此查询中使用的是JSON字段名，例如 '.vulnerabilities[].url'。
+// 每个查询结果输出一行缩进的JSON；raw为true时字符串结果直接输出，不带引号。
+func runJQ(w io.Writer, query string, raw bool, result interface{}) error {
+	code, err := compileJQ(query)
+	if err != nil {
+		return err
+	}
+
+	// 通过JSON编解码转换为gojq支持的map/slice类型
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf(T("编码JSON失败: %w"), err)
+	}
+	var input interface{}
+	if err := json.Unmarshal(data, &input); err != nil {
+		return fmt.Errorf(T("解码JSON失败: %w"), err)
+	}
+
+	iter := code.Run(input)
+	for {
+		v, ok := iter.Next()
+		if !ok {
+			return nil
+		}
+		if err, ok := v.(error); ok {
+			return fmt.Errorf(T("执行jq表达式失败: %w"), err)
+		}
+
+		if s, ok := v.(string); ok && raw {
+			fmt.Fprintln(w, s)
+			continue
+		}
+		out, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return fmt.Errorf(T("编码JSON失败: %w"), err)
+		}
+		fmt.Fprintln(w, string(out))
+	}
+}
+
+// printJQ 在指定了 --jq 时输出查询结果
+// 返回true表示已经处理了输出；查询失败时输出错误到标准错误并以状态1退出
+func printJQ(result interface{}) bool {
+	if outputJQ == "" {
+		return false
+	}
+	if err := runJQ(os.Stdout, outputJQ, outputJQRaw, result); err != nil {
+		exitFormattedError(err)
+	}
+	return true
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&outputJQ, "jq", "", T("使用jq表达式过滤输出，例如 '.vulnerabilities[].url'"))
+	rootCmd.PersistentFlags().BoolVar(&outputJQRaw, "raw-output", false, T("与 --jq 一起使用，字符串结果直接输出而不带引号"))
+}
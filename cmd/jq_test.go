@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+)
+
+func TestRunJQ(t *testing.T) {
+	result := &crawler.SearchResult{
+		Keyword: "php",
+		Vulnerabilities: []crawler.SearchVulnerability{
+			{ID: "WLB-1", URL: "https://cxsecurity.com/issue/WLB-1", RiskLevel: "High"},
+			{ID: "WLB-2", URL: "https://cxsecurity.com/issue/WLB-2", RiskLevel: "Low"},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, runJQ(&buf, ".vulnerabilities[].url", false, result))
+	assert.Equal(t, "\"https://cxsecurity.com/issue/WLB-1\"\n\"https://cxsecurity.com/issue/WLB-2\"\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, runJQ(&buf, `.vulnerabilities[] | select(.risk_level == "High") | .id`, true, result))
+	assert.Equal(t, "WLB-1\n", buf.String(), "raw模式应直接输出字符串")
+
+	buf.Reset()
+	require.NoError(t, runJQ(&buf, "{keyword, count: (.vulnerabilities | length)}", false, result))
+	assert.JSONEq(t, `{"keyword":"php","count":2}`, buf.String())
+
+	assert.Error(t, runJQ(&buf, ".vulnerabilities[", false, result), "语法错误应返回错误")
+	assert.Error(t, runJQ(&buf, ".keyword | error", false, result), "运行时错误应返回错误")
+}
+
+func TestValidateOutputJQ(t *testing.T) {
+	defer func() { outputJQ = "" }()
+
+	outputJQ = ".vulnerabilities["
+	assert.ErrorContains(t, validateOutputJQ(), T("解析jq表达式失败"), "语法错误应在命令执行前返回错误")
+	outputJQ = "undefined_func(1)"
+	assert.ErrorContains(t, validateOutputJQ(), T("编译jq表达式失败"), "未定义的函数应在命令执行前返回错误")
+	outputJQ = ".items[].id"
+	assert.NoError(t, validateOutputJQ())
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/logging"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+var (
+	listPage       int
+	listRisks      []string
+	listRemote     bool
+	listLocal      bool
+	listNoPaging   bool
+	listOutputFile string
+	listSection    string
+)
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: T("以表格显示最新的漏洞列表"),
+	Long: T(`爬取CXSecurity网站最新发布的漏洞，以彩色表格显示，支持交互式翻页。
+可以使用 --risk、--remote 和 --local 按风险级别和利用方式过滤。`),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		risks := make([]string, 0, len(listRisks))
+		for _, r := range listRisks {
+			risk := crawler.NormalizeRiskLevel(r)
+			if risk == "" {
+				return fmt.Errorf(T("无效的风险级别 %q，可选值：High、Med、Low"), r)
+			}
+			risks = append(risks, risk)
+		}
+
+		if _, err := crawler.SectionPath(crawler.Section(listSection), 1); err != nil {
+			return err
+		}
+
+		c := newCrawler()
+		page := listPage
+		for {
+			if humanOutput() {
+				fmt.Printf(T("%s 第 %d 页...\r"), text.Colors{text.FgHiCyan}.Sprint(T("⏳ 加载中:")), page)
+			}
+
+			result, err := c.CrawlSection(crawler.Section(listSection), page, "")
+			if err != nil {
+				logging.Printf("\n%s %v\n", text.Colors{text.FgRed, text.Bold}.Sprint(T("❌ 获取失败:")), err)
+				return nil
+			}
+
+			filtered := &model.VulnerabilityList{
+				Items:       filterVulnerabilities(result.Items, risks, listRemote, listLocal),
+				CurrentPage: result.CurrentPage,
+				TotalPages:  result.TotalPages,
+			}
+
+			// 保存的是过滤后的结果，翻页时为每页添加页码后缀
+			outputPath := listOutputFile
+			if outputPath != "" {
+				if page > listPage {
+					ext := filepath.Ext(listOutputFile)
+					outputPath = fmt.Sprintf("%s_page%d%s", strings.TrimSuffix(listOutputFile, ext), page, ext)
+				}
+				if err := c.SaveJSON(filtered, outputPath); err != nil {
+					logging.Printf(T("保存结果失败: %v\n"), err)
+					outputPath = ""
+				}
+			}
+
+			if !printFormatted(filtered) && !quietOutput {
+				fmt.Print("\r                                  \r")
+				if len(filtered.Items) == 0 {
+					fmt.Println(text.Colors{text.FgHiBlack}.Sprintf(T("第 %d 页没有符合条件的漏洞"), page))
+				} else {
+					printExploitResult(filtered, outputPath)
+				}
+			}
+			if page == listPage {
+				handleOpenAndCopy(filtered)
+			}
+
+			if listNoPaging || !humanOutput() || page >= result.TotalPages {
+				return nil
+			}
+			if !askForNextPage(page, result.TotalPages) {
+				return nil
+			}
+			page++
+		}
+	},
+}
+
+// filterVulnerabilities 按风险级别和利用方式过滤漏洞
+// risks为空时不按风险过滤，没有风险级别标签的漏洞按推断的级别过滤；remote和local都指定时保留远程或本地任一满足的记录
+func filterVulnerabilities(items []model.Vulnerability, risks []string, remote, local bool) []model.Vulnerability {
+	filtered := make([]model.Vulnerability, 0, len(items))
+	for _, item := range items {
+		if len(risks) > 0 {
+			matched := false
+			for _, r := range risks {
+				if crawler.EffectiveRisk(&item) == r {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				continue
+			}
+		}
+		if (remote || local) && !((remote && item.IsRemote) || (local && item.IsLocal)) {
+			continue
+		}
+		filtered = append(filtered, item)
+	}
+	return filtered
+}
+
+func init() {
+	rootCmd.AddCommand(listCmd)
+
+	listCmd.Flags().IntVarP(&listPage, "page", "p", 1, T("起始页码"))
+	listCmd.Flags().StringSliceVarP(&listRisks, "risk", "r", nil, T("只显示指定风险级别的漏洞(High、Med、Low)，多个用逗号分隔"))
+	listCmd.Flags().BoolVar(&listRemote, "remote", false, T("只显示可远程利用的漏洞"))
+	listCmd.Flags().BoolVar(&listLocal, "local", false, T("只显示本地利用的漏洞"))
+	listCmd.Flags().BoolVar(&listNoPaging, "no-paging", false, T("禁用交互式分页，只显示指定页"))
+	listCmd.Flags().StringVarP(&listOutputFile, "output", "o", "", T("将过滤后的结果保存为JSON文件"))
+	listCmd.Flags().BoolVarP(&quietOutput, "silent", "s", false, T("静默模式，不输出到标准输出"))
+	deprecateSilent(listCmd)
+	listCmd.Flags().StringVar(&listSection, "section", string(crawler.SectionExploit), sectionFlagUsage())
+}
+
+// sectionFlagUsage 返回 --section 的帮助信息，列出所有已知栏目
+func sectionFlagUsage() string {
+	names := make([]string, 0, len(crawler.Sections()))
+	for _, s := range crawler.Sections() {
+		names = append(names, string(s))
+	}
+	return fmt.Sprintf(T("列表栏目(%s)，也可以是以 / 开头的列表页路径前缀"), strings.Join(names, "、"))
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+func TestFilterVulnerabilities(t *testing.T) {
+	items := []model.Vulnerability{
+		{ID: "WLB-1", RiskLevel: "High", IsRemote: true},
+		{ID: "WLB-2", RiskLevel: "Med.", IsLocal: true},
+		{ID: "WLB-3", RiskLevel: "Low", IsRemote: true},
+		{ID: "WLB-4", RiskLevel: "High", IsLocal: true},
+		{ID: "WLB-5", Title: "Foo SQL Injection", DerivedRisk: "High", RiskInferred: true},
+	}
+	ids := func(vs []model.Vulnerability) []string {
+		var out []string
+		for _, v := range vs {
+			out = append(out, v.ID)
+		}
+		return out
+	}
+
+	assert.Len(t, filterVulnerabilities(items, nil, false, false), 5)
+	// 没有标签的漏洞按推断的级别过滤
+	assert.Equal(t, []string{"WLB-1", "WLB-4", "WLB-5"}, ids(filterVulnerabilities(items, []string{"High"}, false, false)))
+	assert.Equal(t, []string{"WLB-1", "WLB-2", "WLB-4", "WLB-5"}, ids(filterVulnerabilities(items, []string{"High", "Med."}, false, false)))
+	assert.Equal(t, []string{"WLB-1", "WLB-3"}, ids(filterVulnerabilities(items, nil, true, false)))
+	assert.Equal(t, []string{"WLB-4"}, ids(filterVulnerabilities(items, []string{"High"}, false, true)))
+	// 同时指定 --remote 和 --local 时两者都保留
+	assert.Len(t, filterVulnerabilities(items, nil, true, true), 4)
+}
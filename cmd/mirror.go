@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/config"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+var (
+	mirrorSection     string
+	mirrorMaxPages    int
+	mirrorListDelay   time.Duration
+	mirrorDetailDelay time.Duration
+	mirrorSeenDir     string
+)
+
+// mirrorSaveEvery 镜像时每爬取多少个漏洞详情保存一次存储
+const mirrorSaveEvery = 50
+
+var mirrorCmd = &cobra.Command{
+	Use:   "mirror",
+	Short: T("镜像列表栏目中所有漏洞的详情到本地存储"),
+	Long: T(`爬取列表栏目的列表页和其中每个漏洞的详情页，保存到 --store 指定的存储中。
+列表页和详情页按优先级调度：新发布的High风险漏洞先爬取，越旧的回填页面越晚爬取；
+列表页和详情页分别遵守 --list-delay 和 --detail-delay 的礼貌延迟。
+已镜像的详情页记录在 --seen-dir 目录中(默认为配置目录下的 mirror_seen)，再次运行时跳过，
+因此中断后重新运行或定期运行只会爬取新的漏洞；删除该目录后会重新爬取全部详情页。
+单个详情页失败不会中断镜像；收到中断信号时保存已爬取的结果后退出。`),
+	Example: `  cxcrawler mirror --max-pages 5
+  cxcrawler mirror --section wlb --list-delay 2s --detail-delay 5s --store mirror.json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if _, err := crawler.SectionPath(crawler.Section(mirrorSection), 1); err != nil {
+			return err
+		}
+		s, err := openStore(storeFile)
+		if err != nil {
+			return err
+		}
+		if mirrorSeenDir == "" {
+			if mirrorSeenDir, err = config.Path("mirror_seen"); err != nil {
+				return err
+			}
+		}
+		seen, err := crawler.OpenSeenSet(mirrorSeenDir, 1000000, 0.001)
+		if err != nil {
+			return err
+		}
+		defer seen.Close()
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		c := newCrawler()
+		saved := 0
+		stats, err := c.Mirror(ctx, crawler.MirrorOptions{
+			Section:     crawler.Section(mirrorSection),
+			MaxPages:    mirrorMaxPages,
+			ListDelay:   mirrorListDelay,
+			DetailDelay: mirrorDetailDelay,
+			Seen:        seen,
+		}, func(v *model.Vulnerability) error {
+			infof(T("已爬取 %s %s")+"\n", v.ID, v.Title)
+			s.Put(*v)
+			// 详情页交给回调后就记录为已镜像，定期保存存储，避免进程被杀死时丢失已记录的结果
+			saved++
+			if saved%mirrorSaveEvery == 0 {
+				return s.Save()
+			}
+			return nil
+		})
+		if stats != nil {
+			if saveErr := s.Save(); saveErr != nil {
+				return saveErr
+			}
+		}
+		if err != nil && !errors.Is(err, context.Canceled) {
+			return err
+		}
+
+		if printFormatted(stats) || quietOutput {
+			return nil
+		}
+		fmt.Printf(T("爬取了 %d 个列表页、%d 个漏洞详情，失败 %d 个，跳过已镜像的 %d 个，已保存到 %s")+"\n",
+			stats.ListPages, stats.Details, stats.Failed, stats.Skipped, s.Path())
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(mirrorCmd)
+
+	mirrorCmd.Flags().StringVar(&mirrorSection, "section", string(crawler.SectionExploit), sectionFlagUsage())
+	mirrorCmd.Flags().IntVar(&mirrorMaxPages, "max-pages", 0, T("最多爬取的列表页数，0表示爬取到最后一页"))
+	mirrorCmd.Flags().DurationVar(&mirrorListDelay, "list-delay", time.Second, T("两次请求列表页之间的礼貌延迟"))
+	mirrorCmd.Flags().DurationVar(&mirrorDetailDelay, "detail-delay", 2*time.Second, T("两次请求详情页之间的礼貌延迟"))
+	mirrorCmd.Flags().StringVar(&mirrorSeenDir, "seen-dir", "", T("记录已镜像详情页的目录，默认为配置目录下的 mirror_seen"))
+	mirrorCmd.Flags().StringVar(&storeFile, "store", "", T("存储文件，默认为配置目录下的 vulnerabilities.json"))
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/config"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/fixture"
+)
+
+func TestMirrorCommand(t *testing.T) {
+	server, err := fixture.NewServer("../docs/response-examples")
+	require.NoError(t, err)
+	defer server.Close()
+
+	t.Setenv(config.DirEnv, t.TempDir())
+	oldOptions, oldQuiet := globalClientOptions, quietOutput
+	defer func() {
+		globalClientOptions, quietOutput = oldOptions, oldQuiet
+		mirrorMaxPages, mirrorListDelay, mirrorDetailDelay, mirrorSeenDir, storeFile = 0, 0, 0, "", ""
+	}()
+	globalClientOptions = []crawler.ClientOption{crawler.WithBaseURL(server.URL), crawler.WithRetry(0, 0)}
+	quietOutput = true
+	mirrorSection, mirrorMaxPages, mirrorListDelay, mirrorDetailDelay = string(crawler.SectionExploit), 1, 0, 0
+
+	details := func() int {
+		n := 0
+		for _, path := range server.Requests() {
+			if strings.HasPrefix(path, "/issue/") {
+				n++
+			}
+		}
+		return n
+	}
+
+	require.NoError(t, mirrorCmd.RunE(mirrorCmd, nil))
+	mirrored := details()
+	require.Positive(t, mirrored)
+	s, err := openStore("")
+	require.NoError(t, err)
+	assert.Equal(t, mirrored, s.Len())
+
+	// 再次运行时跳过已经镜像过的详情页
+	require.NoError(t, mirrorCmd.RunE(mirrorCmd, nil))
+	assert.Equal(t, mirrored, details())
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/config"
+)
+
+// loadNotificationDefaults 读取配置目录下notifications.json中的通知设置
+// 这些设置是jira、page和defectdojo命令的默认值，优先级低于命令行参数和环境变量
+func loadNotificationDefaults() (config.NotificationSettings, error) {
+	return config.LoadNotifications("")
+}
+
+// applyJiraDefaults 用保存的设置填充jira命令中没有指定的参数
+func applyJiraDefaults(cmd *cobra.Command) error {
+	settings, err := loadNotificationDefaults()
+	if err != nil || settings.Jira == nil {
+		return err
+	}
+	s := settings.Jira
+	if jiraURL == "" {
+		jiraURL = s.URL
+	}
+	if jiraUser == "" {
+		jiraUser = s.User
+	}
+	if jiraProject == "" {
+		jiraProject = s.Project
+	}
+	if !cmd.Flags().Changed("issue-type") && s.IssueType != "" {
+		jiraIssueType = s.IssueType
+	}
+	if !cmd.Flags().Changed("labels") && len(s.Labels) > 0 {
+		jiraLabels = s.Labels
+	}
+	return nil
+}
+
+// applyOpsgenieDefaults 用保存的设置填充page命令中没有指定的Opsgenie地址
+func applyOpsgenieDefaults(cmd *cobra.Command) error {
+	settings, err := loadNotificationDefaults()
+	if err != nil || settings.Opsgenie == nil {
+		return err
+	}
+	if !cmd.Flags().Changed("opsgenie-url") && settings.Opsgenie.URL != "" {
+		pageOpsgenieURL = settings.Opsgenie.URL
+	}
+	return nil
+}
+
+// applyDefectDojoDefaults 用保存的设置填充defectdojo命令中没有指定的参数
+func applyDefectDojoDefaults(cmd *cobra.Command) error {
+	settings, err := loadNotificationDefaults()
+	if err != nil || settings.DefectDojo == nil {
+		return err
+	}
+	s := settings.DefectDojo
+	if defectDojoURL == "" {
+		defectDojoURL = s.URL
+	}
+	if defectDojoEngagement <= 0 && defectDojoTest <= 0 {
+		defectDojoEngagement = s.Engagement
+	}
+	if !cmd.Flags().Changed("test-title") && s.TestTitle != "" {
+		defectDojoTestTitle = s.TestTitle
+	}
+	return nil
+}
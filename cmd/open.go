@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/logging"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+var (
+	openInBrowser bool
+	copyToClip    bool
+)
+
+// runExternal 执行外部命令，stdin不为空时作为命令的标准输入
+// 定义为变量便于在测试中替换
+var runExternal = func(name string, args []string, stdin string) error {
+	c := exec.Command(name, args...)
+	if stdin != "" {
+		c.Stdin = strings.NewReader(stdin)
+	}
+	return c.Run()
+}
+
+// primaryLink 返回结果的主要链接和ID
+// 列表和搜索结果返回第一条记录；CVE和作者页面的链接基于 --base-url 构建
+func primaryLink(result interface{}) (link, id string) {
+	switch v := result.(type) {
+	case *model.Vulnerability:
+		return v.URL, v.ID
+	case *model.VulnerabilityList:
+		if len(v.Items) > 0 {
+			return v.Items[0].URL, v.Items[0].ID
+		}
+	case *model.CveDetail:
+		if v.CveID != "" {
+			return fmt.Sprintf("%s/cveshow/%s/", siteBaseURL(), v.CveID), v.CveID
+		}
+	case *model.AuthorProfile:
+		if v.ID != "" {
+			return fmt.Sprintf("%s/author/%s/1/", siteBaseURL(), v.ID), v.ID
+		}
+	case *crawler.SearchResult:
+		if len(v.Vulnerabilities) > 0 {
+			return v.Vulnerabilities[0].URL, v.Vulnerabilities[0].ID
+		}
+	}
+	return "", ""
+}
+
+// handleOpenAndCopy 处理 --open 和 --copy
+// 爬取成功后在浏览器中打开结果的链接，或将链接复制到剪贴板；
+// 结果没有链接时复制ID。失败时只输出提示，不影响命令的其他输出
+func handleOpenAndCopy(result interface{}) {
+	if !openInBrowser && !copyToClip {
+		return
+	}
+
+	link, id := primaryLink(result)
+	if openInBrowser {
+		if link == "" {
+			logging.Printf("%s\n", T("结果中没有可打开的链接"))
+		} else if err := openURL(link); err != nil {
+			logging.Printf(T("打开浏览器失败: %v\n"), err)
+		}
+	}
+	if copyToClip {
+		value := link
+		if value == "" {
+			value = id
+		}
+		if value == "" {
+			logging.Printf("%s\n", T("结果中没有可复制的链接或ID"))
+		} else if err := copyText(value); err != nil {
+			logging.Printf(T("复制到剪贴板失败: %v\n"), err)
+		}
+	}
+}
+
+// openURL 使用系统默认浏览器打开链接
+func openURL(link string) error {
+	// 只打开http(s)链接，避免把解析结果中的其他内容交给系统打开
+	if !strings.HasPrefix(link, "http://") && !strings.HasPrefix(link, "https://") {
+		return fmt.Errorf(T("不支持的链接: %s"), link)
+	}
+
+	switch runtime.GOOS {
+	case "darwin":
+		return runExternal("open", []string{link}, "")
+	case "windows":
+		return runExternal("rundll32", []string{"url.dll,FileProtocolHandler", link}, "")
+	default:
+		return runExternal("xdg-open", []string{link}, "")
+	}
+}
+
+// copyText 将文本复制到系统剪贴板
+// Linux下依次尝试 wl-copy、xclip 和 xsel
+func copyText(text string) error {
+	switch runtime.GOOS {
+	case "darwin":
+		return runExternal("pbcopy", nil, text)
+	case "windows":
+		return runExternal("clip", nil, text)
+	}
+
+	candidates := [][]string{
+		{"wl-copy"},
+		{"xclip", "-selection", "clipboard"},
+		{"xsel", "--clipboard", "--input"},
+	}
+	for _, c := range candidates {
+		if _, err := exec.LookPath(c[0]); err != nil {
+			continue
+		}
+		return runExternal(c[0], c[1:], text)
+	}
+	return errors.New(T("未找到剪贴板工具，请安装 wl-clipboard、xclip 或 xsel"))
+}
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&openInBrowser, "open", false, T("爬取成功后在默认浏览器中打开结果的链接"))
+	rootCmd.PersistentFlags().BoolVar(&copyToClip, "copy", false, T("爬取成功后将结果的链接(没有链接时为ID)复制到剪贴板"))
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+func TestPrimaryLink(t *testing.T) {
+	tests := []struct {
+		name   string
+		result interface{}
+		link   string
+		id     string
+	}{
+		{"漏洞详情", &model.Vulnerability{ID: "WLB-1", URL: "https://cxsecurity.com/issue/WLB-1"}, "https://cxsecurity.com/issue/WLB-1", "WLB-1"},
+		{"漏洞列表", &model.VulnerabilityList{Items: []model.Vulnerability{{ID: "WLB-2", URL: "https://cxsecurity.com/issue/WLB-2"}}}, "https://cxsecurity.com/issue/WLB-2", "WLB-2"},
+		{"空列表", &model.VulnerabilityList{}, "", ""},
+		{"CVE", &model.CveDetail{CveID: "CVE-2007-1411"}, "https://cxsecurity.com/cveshow/CVE-2007-1411/", "CVE-2007-1411"},
+		{"作者", &model.AuthorProfile{ID: "rgod"}, "https://cxsecurity.com/author/rgod/1/", "rgod"},
+		{"搜索", &crawler.SearchResult{Vulnerabilities: []crawler.SearchVulnerability{{ID: "WLB-3", URL: "https://cxsecurity.com/issue/WLB-3"}}}, "https://cxsecurity.com/issue/WLB-3", "WLB-3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			link, id := primaryLink(tt.result)
+			assert.Equal(t, tt.link, link)
+			assert.Equal(t, tt.id, id)
+		})
+	}
+}
+
+func TestPrimaryLinkUsesBaseURL(t *testing.T) {
+	defer func() { clientBaseURL = "" }()
+	clientBaseURL = "https://mirror.example.org/"
+
+	link, _ := primaryLink(&model.CveDetail{CveID: "CVE-2007-1411"})
+	assert.Equal(t, "https://mirror.example.org/cveshow/CVE-2007-1411/", link)
+	link, _ = primaryLink(&model.AuthorProfile{ID: "rgod"})
+	assert.Equal(t, "https://mirror.example.org/author/rgod/1/", link)
+}
+
+func TestHandleOpenAndCopy(t *testing.T) {
+	type call struct {
+		name  string
+		args  []string
+		stdin string
+	}
+	var calls []call
+	oldRun := runExternal
+	runExternal = func(name string, args []string, stdin string) error {
+		calls = append(calls, call{name, args, stdin})
+		return nil
+	}
+	defer func() {
+		runExternal = oldRun
+		openInBrowser = false
+	}()
+
+	handleOpenAndCopy(&model.Vulnerability{URL: "https://cxsecurity.com/issue/WLB-1"})
+	assert.Empty(t, calls, "未指定--open时不应执行外部命令")
+
+	openInBrowser = true
+	handleOpenAndCopy(&model.Vulnerability{URL: "https://cxsecurity.com/issue/WLB-1"})
+	if assert.Len(t, calls, 1) {
+		assert.Contains(t, calls[0].args, "https://cxsecurity.com/issue/WLB-1")
+	}
+
+	calls = nil
+	handleOpenAndCopy(&model.Vulnerability{URL: "javascript:alert(1)"})
+	assert.Empty(t, calls, "不应打开非http链接")
+}
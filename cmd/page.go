@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/config"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/logging"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/sink"
+)
+
+var (
+	pagePagerDutyKey string
+	pageOpsgenieKey  string
+	pageOpsgenieURL  string
+	pageTicketsFile  string
+	pageDryRun       bool
+)
+
+// pageResult 是一个漏洞在一个告警系统中的处理结果
+type pageResult struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Risk     string `json:"risk"`
+	Product  string `json:"product"`
+	System   string `json:"system"`
+	Status   string `json:"status"` // paged、exists 或 dry-run
+	DedupKey string `json:"dedup_key,omitempty"`
+}
+
+var pageCmd = &cobra.Command{
+	Use:   "page <file.json...>",
+	Short: T("为匹配关注列表的高风险漏洞呼叫值班人员"),
+	Long: T(`读取之前保存的JSON结果文件(exploit、list、search、author等命令的输出)，
+当标题中提到关注产品(watch命令)且风险级别为Critical或High的漏洞出现时，通过PagerDuty或Opsgenie告警。
+已告警的漏洞记录在配置目录下的 tickets.json 中，同一个漏洞不会重复告警。
+密钥也可以通过环境变量 PAGERDUTY_ROUTING_KEY 和 OPSGENIE_API_KEY 设置，Opsgenie地址的默认值可以保存在配置目录下的 notifications.json 中。`),
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if pagePagerDutyKey == "" {
+			pagePagerDutyKey = os.Getenv("PAGERDUTY_ROUTING_KEY")
+		}
+		if pageOpsgenieKey == "" {
+			pageOpsgenieKey = os.Getenv("OPSGENIE_API_KEY")
+		}
+		logging.AddSecret(pagePagerDutyKey)
+		logging.AddSecret(pageOpsgenieKey)
+		if err := applyOpsgenieDefaults(cmd); err != nil {
+			return err
+		}
+		var pagers []sink.Pager
+		if pagePagerDutyKey != "" {
+			pagers = append(pagers, sink.NewPagerDuty(pagePagerDutyKey))
+		}
+		if pageOpsgenieKey != "" {
+			pagers = append(pagers, sink.NewOpsgenie(pageOpsgenieKey, sink.WithOpsgenieURL(pageOpsgenieURL)))
+		}
+		if len(pagers) == 0 {
+			return errors.New(T("请指定PagerDuty集成密钥或Opsgenie API密钥"))
+		}
+
+		products, err := loadWatchlist()
+		if err != nil {
+			return err
+		}
+		var items []model.Vulnerability
+		for _, path := range args {
+			loaded, err := crawler.LoadResultFile(path)
+			if err != nil {
+				return err
+			}
+			items = append(items, loaded...)
+		}
+
+		store, err := config.NewTicketStore(pageTicketsFile)
+		if err != nil {
+			return err
+		}
+
+		var results []pageResult
+		for _, m := range matchWatchlistTitles(products, items) {
+			v := m.Vulnerability
+			if v.ID == "" || !sink.IsPageable(v.RiskLevel) {
+				continue
+			}
+			for _, pager := range pagers {
+				result := pageResult{ID: v.ID, Title: v.Title, Risk: v.RiskLevel, Product: m.Product.Name, System: pager.Name()}
+
+				ticket, ok, err := store.Get(pager.Name(), v.ID)
+				if err != nil {
+					return err
+				}
+				switch {
+				case ok:
+					result.Status, result.DedupKey = "exists", ticket.Key
+				case pageDryRun:
+					result.Status = "dry-run"
+				default:
+					key, err := pager.Page(v, m.Product.Name)
+					if err != nil {
+						return fmt.Errorf(T("为 %s 发送告警失败: %v"), v.ID, err)
+					}
+					if err := store.Add(config.Ticket{System: pager.Name(), VulnID: v.ID, Key: key}); err != nil {
+						return err
+					}
+					result.Status, result.DedupKey = "paged", key
+				}
+				results = append(results, result)
+			}
+		}
+
+		if printFormatted(results) || quietOutput {
+			return nil
+		}
+		printPageResults(results)
+		return nil
+	},
+}
+
+// printPageResults 以表格形式输出告警结果
+func printPageResults(results []pageResult) {
+	if len(results) == 0 {
+		fmt.Println(text.Colors{text.FgHiYellow}.Sprint(T("没有需要告警的漏洞")))
+		return
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(tableStyle(table.StyleRounded))
+	t.AppendHeader(table.Row{"ID", T("风险"), T("关注的产品"), T("告警系统"), T("状态"), T("标题")})
+	for _, r := range results {
+		status := r.Status
+		switch r.Status {
+		case "paged":
+			status = text.Colors{text.FgHiRed}.Sprint(T("已告警"))
+		case "exists":
+			status = text.Colors{text.FgHiBlack}.Sprint(T("已存在"))
+		case "dry-run":
+			status = text.Colors{text.FgHiYellow}.Sprint(T("未告警(dry-run)"))
+		}
+		t.AppendRow(table.Row{
+			text.Colors{text.FgHiCyan}.Sprint(r.ID),
+			r.Risk,
+			r.Product,
+			r.System,
+			status,
+			truncateCell(r.Title, 50),
+		})
+	}
+	t.Render()
+}
+
+func init() {
+	rootCmd.AddCommand(pageCmd)
+
+	pageCmd.Flags().StringVar(&pagePagerDutyKey, "pagerduty-key", "", T("PagerDuty Events API v2集成密钥"))
+	pageCmd.Flags().StringVar(&pageOpsgenieKey, "opsgenie-key", "", T("Opsgenie API密钥"))
+	pageCmd.Flags().StringVar(&pageOpsgenieURL, "opsgenie-url", sink.OpsgenieAPIURL, T("Opsgenie API地址，欧洲区域为 https://api.eu.opsgenie.com"))
+	pageCmd.Flags().StringVar(&watchFile, "watchlist", "", T("关注列表文件，默认为配置目录下的 watchlist.json"))
+	pageCmd.Flags().StringVar(&pageTicketsFile, "tickets", "", T("已告警漏洞的记录文件，默认为配置目录下的 tickets.json"))
+	pageCmd.Flags().BoolVar(&pageDryRun, "dry-run", false, T("只显示需要告警的漏洞，不发送告警"))
+}
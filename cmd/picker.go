@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/text"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+)
+
+// stdinReader 是所有交互式提示共用的标准输入读取器
+// 共用同一个bufio.Reader，避免多个读取器各自缓冲导致输入丢失
+var stdinReader = bufio.NewReader(os.Stdin)
+
+// fuzzyMatch 判断pattern的字符是否按顺序出现在s中(忽略大小写)
+// 与fzf的默认匹配方式类似，例如 "wpsql" 可以匹配 "WordPress Plugin SQL Injection"
+func fuzzyMatch(pattern, s string) bool {
+	pattern = strings.ToLower(strings.ReplaceAll(pattern, " ", ""))
+	s = strings.ToLower(s)
+
+	pr := []rune(pattern)
+	i := 0
+	for _, r := range s {
+		if i < len(pr) && r == pr[i] {
+			i++
+		}
+	}
+	return i == len(pr)
+}
+
+// pickSearchResult 交互式地从搜索结果中选择一条记录
+// 输入编号直接选择；输入其他文字按ID、标题和作者模糊过滤，过滤后直接回车选择第一条；
+// 没有过滤条件时直接回车或输入q跳过。
+//
+// 返回值:
+//   - *crawler.SearchVulnerability: 选中的记录，跳过时为nil
+func pickSearchResult(in *bufio.Reader, out io.Writer, items []crawler.SearchVulnerability) *crawler.SearchVulnerability {
+	if len(items) == 0 {
+		return nil
+	}
+
+	filter := ""
+	for {
+		// 记录过滤后的条目在原列表中的下标，编号始终使用原列表中的序号
+		var matched []int
+		for i, item := range items {
+			if filter == "" || fuzzyMatch(filter, item.ID+" "+item.Title+" "+item.Author) {
+				matched = append(matched, i)
+			}
+		}
+
+		fmt.Fprintln(out)
+		for _, i := range matched {
+			fmt.Fprintf(out, "%s %s %s\n",
+				text.Colors{text.FgHiYellow}.Sprintf("%3d)", i+1),
+				text.Colors{text.FgHiCyan}.Sprint(items[i].ID),
+				items[i].Title)
+		}
+		if len(matched) == 0 {
+			fmt.Fprintln(out, text.Colors{text.FgHiBlack}.Sprint(T("  没有匹配的结果")))
+		}
+
+		prompt := T("输入编号查看详情，输入文字过滤，直接回车跳过")
+		if filter != "" {
+			prompt = fmt.Sprintf(T("过滤: %q，直接回车选择第一条，输入 / 清除过滤"), filter)
+		}
+		fmt.Fprintf(out, "\n%s%s: ", text.Colors{text.FgHiYellow}.Sprint(icon("🔎")), prompt)
+
+		line, err := in.ReadString('\n')
+		input := strings.TrimSpace(line)
+		if err != nil && input == "" {
+			// 输入结束(例如非交互环境)，视为跳过
+			return nil
+		}
+
+		switch {
+		case input == "q":
+			return nil
+		case input == "/":
+			filter = ""
+		case input == "":
+			if filter == "" {
+				return nil
+			}
+			if len(matched) > 0 {
+				return &items[matched[0]]
+			}
+		default:
+			if n, err := strconv.Atoi(input); err == nil {
+				if n >= 1 && n <= len(items) {
+					return &items[n-1]
+				}
+				fmt.Fprintln(out, text.Colors{text.FgRed}.Sprintf(T("编号超出范围: %d"), n))
+				continue
+			}
+			filter = input
+		}
+	}
+}
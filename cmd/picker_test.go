@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	assert.True(t, fuzzyMatch("wpsql", "WordPress Plugin SQL Injection"))
+	assert.True(t, fuzzyMatch("WLB 0015", "WLB-2024040015"))
+	assert.True(t, fuzzyMatch("", "anything"))
+	assert.False(t, fuzzyMatch("sqlwp", "WordPress Plugin SQL Injection"), "字符顺序不同时不应匹配")
+}
+
+func TestPickSearchResult(t *testing.T) {
+	items := []crawler.SearchVulnerability{
+		{ID: "WLB-1", Title: "WordPress Plugin SQL Injection"},
+		{ID: "WLB-2", Title: "Joomla XSS"},
+		{ID: "WLB-3", Title: "WordPress Theme XSS"},
+	}
+	pick := func(input string) *crawler.SearchVulnerability {
+		return pickSearchResult(bufio.NewReader(strings.NewReader(input)), io.Discard, items)
+	}
+
+	assert.Equal(t, "WLB-2", pick("2\n").ID, "输入编号应直接选择")
+	assert.Equal(t, "WLB-3", pick("wp xss\n\n").ID, "过滤后回车应选择第一条匹配")
+	assert.Equal(t, "WLB-1", pick("joomla\n/\n1\n").ID, "清除过滤后仍可按编号选择")
+	assert.Equal(t, "WLB-3", pick("9\n3\n").ID, "编号超出范围时应重新输入")
+	assert.Nil(t, pick("\n"), "直接回车应跳过")
+	assert.Nil(t, pick("q\n"))
+	assert.Nil(t, pick(""), "输入结束时应跳过")
+	assert.Nil(t, pickSearchResult(bufio.NewReader(strings.NewReader("1\n")), io.Discard, nil))
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/config"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+)
+
+var (
+	pruneRawHTML       string
+	pruneRecords       string
+	pruneDryRun        bool
+	pruneSave          bool
+	pruneRetentionFile string
+)
+
+var pruneCmd = &cobra.Command{
+	Use:   "prune <dir...>",
+	Short: T("按保留策略清理过期的数据文件"),
+	Long: T(`按保留策略清理数据目录中过期的文件，避免长期运行的部署无限增长。
+原始HTML(.html/.htm，例如 --archive-html 保存的页面)默认保留90天，解析后的记录(.json/.jsonl)默认永久保留，其他文件不受影响。
+保留策略保存在配置目录下的 retention.json 中，可以用 --save 保存命令行指定的策略。
+保留时间支持 90d、4w、36h 这样的格式，forever 表示永久保留。`),
+	Example: `  cxcrawler prune data/html --dry-run
+  cxcrawler prune data --raw-html 30d --records 365d --save`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		policy, err := resolveRetention(cmd)
+		if err != nil {
+			return err
+		}
+		if pruneSave {
+			if err := config.SaveRetention(pruneRetentionFile, policy); err != nil {
+				return err
+			}
+		}
+
+		reports := make(map[string]*crawler.PruneReport, len(args))
+		for _, dir := range args {
+			report, err := crawler.Prune(dir, policy, time.Now(), pruneDryRun)
+			if err != nil {
+				return fmt.Errorf(T("清理 %s 失败: %w"), dir, err)
+			}
+			reports[dir] = report
+		}
+		if printFormatted(reports) || quietOutput {
+			return nil
+		}
+
+		for _, dir := range args {
+			report := reports[dir]
+			if pruneDryRun {
+				for _, file := range report.Removed {
+					fmt.Printf("  %s\n", text.FgHiBlack.Sprint(file))
+				}
+				fmt.Printf(T("%s: 将删除 %d 个文件 (%d 字节)，保留 %d 个文件")+"\n", dir, len(report.Removed), report.RemovedBytes, report.Kept)
+				continue
+			}
+			fmt.Printf(T("%s: 已删除 %d 个文件 (%d 字节)，保留 %d 个文件")+"\n", dir, len(report.Removed), report.RemovedBytes, report.Kept)
+		}
+		return nil
+	},
+}
+
+// resolveRetention 读取保存的保留策略，并用命令行中指定的 --raw-html 和 --records 覆盖
+func resolveRetention(cmd *cobra.Command) (crawler.RetentionPolicy, error) {
+	policy, err := config.LoadRetention(pruneRetentionFile)
+	if err != nil {
+		return policy, err
+	}
+	if cmd.Flags().Changed("raw-html") {
+		if policy.RawHTML, err = crawler.ParseRetention(pruneRawHTML); err != nil {
+			return policy, err
+		}
+	}
+	if cmd.Flags().Changed("records") {
+		if policy.Records, err = crawler.ParseRetention(pruneRecords); err != nil {
+			return policy, err
+		}
+	}
+	return policy, nil
+}
+
+func init() {
+	rootCmd.AddCommand(pruneCmd)
+
+	pruneCmd.Flags().StringVar(&pruneRawHTML, "raw-html", "", T("原始HTML的保留时间，例如 90d，覆盖保存的策略"))
+	pruneCmd.Flags().StringVar(&pruneRecords, "records", "", T("解析后记录的保留时间，例如 365d 或 forever，覆盖保存的策略"))
+	pruneCmd.Flags().BoolVar(&pruneDryRun, "dry-run", false, T("只列出将要删除的文件，不实际删除"))
+	pruneCmd.Flags().BoolVar(&pruneSave, "save", false, T("将生效的保留策略保存到配置文件，供之后的 prune 和 api --prune-dir 使用"))
+	pruneCmd.Flags().StringVar(&pruneRetentionFile, "retention", "", T("保留策略文件，默认为配置目录下的 retention.json"))
+}
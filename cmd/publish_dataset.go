@@ -0,0 +1,236 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/export"
+)
+
+// datasetName 是发布的数据集名称，也是归档文件名的前缀
+const datasetName = "cxsecurity-dataset"
+
+var (
+	datasetVersion   string
+	datasetOutputDir string
+	datasetSignKey   string
+	datasetForce     bool
+	datasetPublicKey string
+)
+
+var publishDatasetCmd = &cobra.Command{
+	Use:   "publish-dataset",
+	Short: T("将本地数据打包为可公开发布的数据集版本"),
+	Long: T(`将存储中的漏洞和CVE详情打包为带版本号的数据集，用于作为开放数据集发布。
+每个版本在输出目录中生成以下文件：
+  cxsecurity-dataset-<版本>.tar.gz          JSONL格式的漏洞和CVE详情、对应的JSON Schema和清单
+  cxsecurity-dataset-<版本>.manifest.json   归档中的清单，列出每个文件的记录数和SHA-256
+  cxsecurity-dataset-<版本>.sha256          归档和清单的SHA-256，可以用 sha256sum -c 校验
+  cxsecurity-dataset-<版本>.sha256.sig      指定 --sign-key 时生成，校验和文件的Ed25519签名
+打包前会用JSON Schema校验所有记录；已发布的版本不会被覆盖，除非指定 --force。`),
+	Example: `  cxcrawler publish-dataset -o releases
+  cxcrawler publish-dataset --version 2024.05.01 --sign-key release.pem -o releases
+  cxcrawler publish-dataset verify releases/cxsecurity-dataset-2024.05.01.tar.gz --public-key release.pub`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		now := time.Now().UTC()
+		version := datasetVersion
+		if version == "" {
+			version = now.Format("2006.01.02")
+		}
+		dataset := &export.Dataset{Name: datasetName, Version: version, Created: now}
+		base := filepath.Join(datasetOutputDir, dataset.Dir())
+		if _, err := os.Stat(base + ".tar.gz"); err == nil && !datasetForce {
+			return fmt.Errorf(T("版本 %s 已发布(%s)，请指定新的 --version 或使用 --force 覆盖"), version, base+".tar.gz")
+		}
+
+		s, err := openStore(storeFile)
+		if err != nil {
+			return err
+		}
+		cves, err := openCVEStore()
+		if err != nil {
+			return err
+		}
+		dataset.Vulnerabilities, dataset.CVEs = s.All(), cves.All()
+		if len(dataset.Vulnerabilities) == 0 {
+			return errors.New(T("存储中没有漏洞，请先使用 import 导入结果"))
+		}
+
+		var key ed25519.PrivateKey
+		if datasetSignKey != "" {
+			data, err := os.ReadFile(datasetSignKey)
+			if err != nil {
+				return fmt.Errorf(T("读取签名密钥失败: %w"), err)
+			}
+			if key, err = export.ParseSigningKey(data); err != nil {
+				return err
+			}
+		}
+
+		var archive bytes.Buffer
+		manifest, err := dataset.WriteArchive(&archive)
+		if err != nil {
+			return err
+		}
+		manifestData, err := json.MarshalIndent(manifest, "", "  ")
+		if err != nil {
+			return err
+		}
+		manifestData = append(manifestData, '\n')
+		files := []string{base + ".tar.gz", base + ".manifest.json", base + ".sha256"}
+		sums := map[string]string{
+			filepath.Base(files[0]): sha256Hex(archive.Bytes()),
+			filepath.Base(files[1]): sha256Hex(manifestData),
+		}
+		var checksums bytes.Buffer
+		if err := export.WriteChecksums(&checksums, sums); err != nil {
+			return err
+		}
+
+		c := newCrawler()
+		contents := [][]byte{archive.Bytes(), manifestData, checksums.Bytes()}
+		if key != nil {
+			files = append(files, base+".sha256.sig")
+			contents = append(contents, export.SignChecksums(key, checksums.Bytes()))
+		} else {
+			// 覆盖已签名的版本时删除旧的签名，避免留下与新校验和不匹配的签名
+			os.Remove(base + ".sha256.sig")
+		}
+		for i, file := range files {
+			if err := c.SaveFile(contents[i], file); err != nil {
+				return fmt.Errorf(T("保存结果失败: %v"), err)
+			}
+		}
+
+		if printFormatted(manifest) || quietOutput {
+			return nil
+		}
+		printDatasetManifest(manifest)
+		for _, file := range files {
+			fmt.Printf("%s %s\n", text.Colors{text.FgHiGreen}.Sprint(T("✅ 已保存:")), text.Colors{text.FgHiCyan, text.Underline}.Sprint(file))
+		}
+		if key == nil {
+			fmt.Println(text.Colors{text.FgHiYellow}.Sprint(T("⚠️ 未指定 --sign-key，发布的数据集没有签名")))
+		}
+		return nil
+	},
+}
+
+var verifyDatasetCmd = &cobra.Command{
+	Use:   "verify <cxsecurity-dataset-版本.tar.gz>",
+	Short: T("校验已发布的数据集"),
+	Long: T(`校验数据集归档：同目录下有 .sha256 文件时检查归档的SHA-256，
+指定 --public-key 时检查 .sha256.sig 签名，最后检查归档中每个文件与清单一致。`),
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		archivePath := args[0]
+		data, err := os.ReadFile(archivePath)
+		if err != nil {
+			return fmt.Errorf(T("读取数据集归档失败: %w"), err)
+		}
+		base := strings.TrimSuffix(archivePath, ".tar.gz")
+
+		checksums, err := os.ReadFile(base + ".sha256")
+		switch {
+		case err == nil:
+			sums, err := export.ParseChecksums(checksums)
+			if err != nil {
+				return err
+			}
+			if sums[filepath.Base(archivePath)] != sha256Hex(data) {
+				return fmt.Errorf(T("%s 的SHA-256与 %s 不一致"), archivePath, base+".sha256")
+			}
+		case !os.IsNotExist(err) || datasetPublicKey != "":
+			return fmt.Errorf(T("读取校验和文件失败: %w"), err)
+		}
+
+		if datasetPublicKey != "" {
+			keyData, err := os.ReadFile(datasetPublicKey)
+			if err != nil {
+				return fmt.Errorf(T("读取公钥失败: %w"), err)
+			}
+			public, err := export.ParseVerifyKey(keyData)
+			if err != nil {
+				return err
+			}
+			signature, err := os.ReadFile(base + ".sha256.sig")
+			if err != nil {
+				return fmt.Errorf(T("读取签名失败: %w"), err)
+			}
+			if err := export.VerifyChecksumsSignature(public, checksums, signature); err != nil {
+				return err
+			}
+		}
+
+		manifest, err := export.VerifyDatasetArchive(bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		if printFormatted(manifest) || quietOutput {
+			return nil
+		}
+		printDatasetManifest(manifest)
+		status := T("✅ 校验通过，归档与清单一致")
+		if datasetPublicKey != "" {
+			status = T("✅ 校验通过，签名有效，归档与清单一致")
+		}
+		fmt.Println(text.Colors{text.FgHiGreen}.Sprint(status))
+		return nil
+	},
+}
+
+// sha256Hex 返回数据的十六进制SHA-256
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// printDatasetManifest 以表格显示数据集中的文件
+func printDatasetManifest(m *export.DatasetManifest) {
+	fmt.Printf("%s %s  %s\n",
+		text.Colors{text.Bold}.Sprint(m.Name),
+		text.Colors{text.FgHiCyan}.Sprint(m.Version),
+		text.Colors{text.FgHiBlack}.Sprintf(T("%d 个漏洞, %d 个CVE详情"), m.Vulnerabilities, m.CVEs))
+	if m.FirstPublished != nil && m.LastPublished != nil {
+		fmt.Printf(T("发布日期范围: %s ~ %s")+"\n", formatDate(*m.FirstPublished), formatDate(*m.LastPublished))
+	}
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(tableStyle(table.StyleRounded))
+	t.AppendHeader(table.Row{T("文件"), T("记录数"), T("大小"), "SHA-256"})
+	for _, f := range m.Files {
+		records := ""
+		if f.Schema != "" {
+			records = fmt.Sprint(f.Records)
+		}
+		t.AppendRow(table.Row{f.Path, records, formatBytes(f.Size), f.SHA256[:16] + "…"})
+	}
+	t.Render()
+}
+
+func init() {
+	rootCmd.AddCommand(publishDatasetCmd)
+	publishDatasetCmd.AddCommand(verifyDatasetCmd)
+
+	publishDatasetCmd.Flags().StringVar(&datasetVersion, "version", "", T("数据集版本，默认为当前UTC日期，例如 2024.05.01"))
+	publishDatasetCmd.Flags().StringVarP(&datasetOutputDir, "output-dir", "o", "dataset", T("输出目录"))
+	publishDatasetCmd.Flags().StringVar(&datasetSignKey, "sign-key", "", T("用于签名的Ed25519私钥(PEM格式)，例如 openssl genpkey -algorithm ed25519 生成的密钥"))
+	publishDatasetCmd.Flags().BoolVar(&datasetForce, "force", false, T("覆盖已发布的同一版本"))
+	publishDatasetCmd.Flags().StringVar(&storeFile, "store", "", T("存储文件，默认为配置目录下的 vulnerabilities.json"))
+	publishDatasetCmd.Flags().StringVar(&cveStoreFile, "cve-store", "", T("CVE详情存储文件，默认为配置目录下的 cve_details.json"))
+	verifyDatasetCmd.Flags().StringVar(&datasetPublicKey, "public-key", "", T("用于校验签名的Ed25519公钥(PEM格式)"))
+}
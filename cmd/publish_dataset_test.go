@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/config"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/store"
+)
+
+func TestPublishDataset(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(config.DirEnv, dir)
+	s, err := store.Open(filepath.Join(dir, store.DefaultFile))
+	require.NoError(t, err)
+	s.Put(model.Vulnerability{ID: "WLB-2024040001", Title: "A", Date: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)})
+	require.NoError(t, s.Save())
+
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	require.NoError(t, err)
+	keyFile := filepath.Join(dir, "release.pem")
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600))
+	der, err = x509.MarshalPKIXPublicKey(public)
+	require.NoError(t, err)
+	publicFile := filepath.Join(dir, "release.pub")
+	require.NoError(t, os.WriteFile(publicFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0644))
+
+	oldQuiet := quietOutput
+	defer func() {
+		quietOutput = oldQuiet
+		datasetVersion, datasetOutputDir, datasetSignKey, datasetForce, datasetPublicKey = "", "dataset", "", false, ""
+	}()
+	quietOutput = true
+	out := filepath.Join(dir, "releases")
+	datasetVersion, datasetOutputDir, datasetSignKey = "1.0.0", out, keyFile
+
+	require.NoError(t, publishDatasetCmd.RunE(publishDatasetCmd, nil))
+	base := filepath.Join(out, "cxsecurity-dataset-1.0.0")
+	for _, suffix := range []string{".tar.gz", ".manifest.json", ".sha256", ".sha256.sig"} {
+		assert.FileExists(t, base+suffix)
+	}
+	assert.Error(t, publishDatasetCmd.RunE(publishDatasetCmd, nil), "已发布的版本不能覆盖")
+
+	datasetPublicKey = publicFile
+	require.NoError(t, verifyDatasetCmd.RunE(verifyDatasetCmd, []string{base + ".tar.gz"}))
+
+	// 不签名覆盖时删除旧签名，校验签名失败
+	datasetForce, datasetSignKey = true, ""
+	require.NoError(t, publishDatasetCmd.RunE(publishDatasetCmd, nil))
+	assert.NoFileExists(t, base+".sha256.sig")
+	assert.Error(t, verifyDatasetCmd.RunE(verifyDatasetCmd, []string{base + ".tar.gz"}))
+
+	// 归档被修改后校验和不一致
+	datasetPublicKey = ""
+	require.NoError(t, verifyDatasetCmd.RunE(verifyDatasetCmd, []string{base + ".tar.gz"}))
+	data, err := os.ReadFile(base + ".tar.gz")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(base+".tar.gz", append(data, 0), 0644))
+	assert.ErrorContains(t, verifyDatasetCmd.RunE(verifyDatasetCmd, []string{base + ".tar.gz"}), "SHA-256")
+}
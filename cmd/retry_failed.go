@@ -0,0 +1,275 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/config"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/logging"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/store"
+)
+
+var (
+	retryQueueFile   string
+	retryLimit       int
+	retryDelay       time.Duration
+	retryMaxAttempts int
+	retryDryRun      bool
+	retryShowDead    bool
+	retryRequeue     bool
+)
+
+var retryFailedCmd = &cobra.Command{
+	Use:   "retry-failed",
+	Short: T("重新处理批量爬取中失败的条目"),
+	Long: T(`exploit -i 和 vex -i 批量爬取时获取或解析失败的条目会记录在重试队列中(配置目录下的 retry_queue.json)，
+该命令按失败次数从少到多重新爬取这些条目。成功的条目保存到存储中(漏洞详情保存到 --store，CVE详情保存到 --cve-store)，
+如果失败时指定了输出文件也会保存到该文件，然后从队列中删除。
+失败达到 --max-attempts 次的条目移到同一目录下的死信文件 dead_letter.json，不再重试；
+使用 --dead 查看死信文件中的条目，使用 --requeue 将它们放回队列。`),
+	Example: `  cxcrawler retry-failed --dry-run
+  cxcrawler retry-failed --limit 20 --delay 2s
+  cxcrawler retry-failed --dead
+  cxcrawler retry-failed --requeue`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		q, err := openRetryQueue()
+		if err != nil {
+			return err
+		}
+
+		if retryShowDead {
+			dead := q.Dead()
+			if !printFormatted(dead) && !quietOutput {
+				printRetryItems(dead, q.DeadLetterPath())
+			}
+			return nil
+		}
+		if retryRequeue {
+			n := q.Requeue()
+			if err := q.Save(); err != nil {
+				return err
+			}
+			fmt.Printf(T("已将 %d 个条目放回重试队列")+"\n", n)
+			return nil
+		}
+
+		s, err := openStore(storeFile)
+		if err != nil {
+			return err
+		}
+		cves, err := openCVEStore()
+		if err != nil {
+			return err
+		}
+
+		c := newCrawler(crawler.WithClientOptions(clientOptions(crawler.WithAdaptiveThrottle(retryDelay, 30*time.Second, time.Minute))...), forceNetwork())
+		results := q.Retry(func(item store.RetryItem) error {
+			infof(T("重试 %s %s")+"\n", item.Kind, item.Key)
+			switch item.Kind {
+			case store.RetryExploit:
+				result, err := c.CrawlExploit(item.Key, item.Output, "")
+				if err != nil {
+					return err
+				}
+				if v, ok := result.(*model.Vulnerability); ok {
+					s.Put(*v)
+				}
+			case store.RetryCVE:
+				d, err := c.CrawlCveDetail(item.Key, item.Output)
+				if err != nil {
+					return err
+				}
+				cves.Sync(*d, time.Now())
+			default:
+				return fmt.Errorf(T("未知的条目类型: %s"), item.Kind)
+			}
+			return nil
+		}, store.RetryOptions{Limit: retryLimit, DryRun: retryDryRun})
+
+		if !retryDryRun {
+			if err := s.Save(); err != nil {
+				return err
+			}
+			if err := cves.Save(); err != nil {
+				return err
+			}
+			if err := q.Save(); err != nil {
+				return err
+			}
+		}
+		if printFormatted(results) || quietOutput {
+			return nil
+		}
+		printRetryResults(results, q)
+		return nil
+	},
+}
+
+// openRetryQueue 打开 --retry-queue 指定的重试队列，默认为配置目录下的 retry_queue.json
+func openRetryQueue() (*store.RetryQueue, error) {
+	path := retryQueueFile
+	if path == "" {
+		var err error
+		if path, err = config.Path(store.DefaultRetryFile); err != nil {
+			return nil, err
+		}
+	}
+	q, err := store.OpenRetryQueue(path, store.WithPermissions(outputFileMode, outputDirMode))
+	if err != nil {
+		return nil, err
+	}
+	q.MaxAttempts = retryMaxAttempts
+	return q, nil
+}
+
+// openCVEStore 打开 --cve-store 指定的CVE详情存储，默认为配置目录下的 cve_details.json
+func openCVEStore() (*store.CVEStore, error) {
+	path := cveStoreFile
+	if path == "" {
+		var err error
+		if path, err = config.Path(store.DefaultCVEFile); err != nil {
+			return nil, err
+		}
+	}
+	return store.OpenCVE(path, store.WithPermissions(outputFileMode, outputDirMode))
+}
+
+// retryTracker 在批量爬取中把失败的条目记录到重试队列，成功的条目从队列中删除
+// 重试队列无法打开时只记录日志，不影响爬取本身
+type retryTracker struct {
+	q       *store.RetryQueue
+	changed bool
+}
+
+// newRetryTracker 打开重试队列，失败时返回的tracker不做任何处理
+func newRetryTracker() *retryTracker {
+	q, err := openRetryQueue()
+	if err != nil {
+		logging.Printf(T("打开重试队列失败: %v")+"\n", err)
+		return &retryTracker{}
+	}
+	return &retryTracker{q: q}
+}
+
+// fail 记录一个失败的条目，output是结果原来的保存路径，记录为绝对路径以便在其他目录下重试
+func (t *retryTracker) fail(kind, key, output string, err error) {
+	if t.q == nil {
+		return
+	}
+	if output != "" {
+		if abs, absErr := filepath.Abs(output); absErr == nil {
+			output = abs
+		}
+	}
+	item, dead := t.q.Record(kind, key, output, err, time.Now())
+	t.changed = true
+	if dead {
+		logging.Printf(T("%s 已失败 %d 次，移到死信文件 %s")+"\n", key, item.Attempts, t.q.DeadLetterPath())
+		return
+	}
+	infof(T("%s 已加入重试队列，可以稍后使用 retry-failed 重试")+"\n", key)
+}
+
+// succeed 将处理成功的条目从重试队列中删除
+func (t *retryTracker) succeed(kind, key string) {
+	if t.q != nil && t.q.Resolve(kind, key) {
+		t.changed = true
+	}
+}
+
+// save 在队列有变化时保存
+func (t *retryTracker) save() {
+	if t.q == nil || !t.changed {
+		return
+	}
+	if err := t.q.Save(); err != nil {
+		logging.Printf(T("保存重试队列失败: %v")+"\n", err)
+	}
+}
+
+// printRetryResults 以表格形式输出重试结果和统计
+func printRetryResults(results []store.RetryResult, q *store.RetryQueue) {
+	if len(results) == 0 {
+		fmt.Printf(T("重试队列 %s 为空")+"\n", q.Path())
+		return
+	}
+
+	counts := make(map[string]int)
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(tableStyle(table.StyleRounded))
+	t.AppendHeader(table.Row{T("类型"), "ID", T("状态"), T("失败次数"), T("错误")})
+	for _, r := range results {
+		counts[r.Status]++
+		status := r.Status
+		switch r.Status {
+		case store.RetrySucceeded:
+			status = text.Colors{text.FgHiGreen}.Sprint(T("成功"))
+		case store.RetryFailed:
+			status = text.Colors{text.FgHiRed}.Sprint(T("失败"))
+		case store.RetryDead:
+			status = text.Colors{text.FgHiBlack}.Sprint(T("移到死信文件"))
+		case store.RetryPending:
+			status = text.Colors{text.FgHiYellow}.Sprint(T("待重试"))
+		}
+		attempts := ""
+		if r.Attempts > 0 {
+			attempts = fmt.Sprint(r.Attempts)
+		}
+		t.AppendRow(table.Row{r.Kind, r.Key, status, attempts, truncateCell(r.Error, 60)})
+	}
+	t.Render()
+	fmt.Printf(T("成功 %d 个，失败 %d 个，移到死信文件 %d 个，待重试 %d 个")+"\n",
+		counts[store.RetrySucceeded], counts[store.RetryFailed], counts[store.RetryDead], counts[store.RetryPending])
+}
+
+// printRetryItems 以表格形式输出死信文件中的条目
+func printRetryItems(items []store.RetryItem, path string) {
+	if len(items) == 0 {
+		fmt.Printf(T("死信文件 %s 为空")+"\n", path)
+		return
+	}
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(tableStyle(table.StyleRounded))
+	t.AppendHeader(table.Row{T("类型"), "ID", T("失败次数"), T("第一次失败"), T("最后一次尝试"), T("错误")})
+	for _, item := range items {
+		t.AppendRow(table.Row{
+			item.Kind,
+			item.Key,
+			item.Attempts,
+			formatTimestamp(item.FirstFailed),
+			formatTimestamp(item.LastAttempt),
+			truncateCell(item.Error, 60),
+		})
+	}
+	t.Render()
+}
+
+// addRetryQueueFlags 添加重试队列的选项，批量爬取的命令和 retry-failed 共用
+func addRetryQueueFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&retryQueueFile, "retry-queue", "", T("重试队列文件，默认为配置目录下的 retry_queue.json"))
+	cmd.Flags().IntVar(&retryMaxAttempts, "max-attempts", store.DefaultMaxRetries, T("同一条目失败达到该次数后移到死信文件，0表示总是重试"))
+}
+
+func init() {
+	rootCmd.AddCommand(retryFailedCmd)
+
+	addRetryQueueFlags(retryFailedCmd)
+	retryFailedCmd.Flags().IntVar(&retryLimit, "limit", 0, T("本次最多重试的条目数，0表示不限制"))
+	retryFailedCmd.Flags().DurationVar(&retryDelay, "delay", time.Second, T("请求的最小间隔"))
+	retryFailedCmd.Flags().BoolVar(&retryDryRun, "dry-run", false, T("只列出等待重试的条目，不发送请求"))
+	retryFailedCmd.Flags().BoolVar(&retryShowDead, "dead", false, T("列出死信文件中不再重试的条目"))
+	retryFailedCmd.Flags().BoolVar(&retryRequeue, "requeue", false, T("将死信文件中的条目放回重试队列并清零失败次数"))
+	retryFailedCmd.Flags().StringVar(&storeFile, "store", "", T("存储文件，默认为配置目录下的 vulnerabilities.json"))
+	retryFailedCmd.Flags().StringVar(&cveStoreFile, "cve-store", "", T("CVE详情存储文件，默认为配置目录下的 cve_details.json"))
+}
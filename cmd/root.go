@@ -1,27 +1,200 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"log"
 	"os"
+	"strconv"
 
 	"github.com/spf13/cobra"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/logging"
+)
+
+var (
+	outputFileModeFlag string
+	outputDirModeFlag  string
+	secureOutput       bool
+	syncOutput         bool
+	attackEnabled      bool
+	attackRulesFile    string
+	archiveHTMLDir     string
+	archiveCompress    bool
+	provenanceEnabled  bool
+	extractRulesFile   string
+
+	// 解析后的输出权限，由PersistentPreRunE设置
+	outputFileMode = crawler.DefaultFileMode
+	outputDirMode  = crawler.DefaultDirMode
+
+	// ATT&CK技术分类器，指定 --attack 或 --attack-rules 时由PersistentPreRunE设置
+	techniqueClassifier crawler.TechniqueClassifier
+
+	// 额外字段提取器和标签映射表，指定 --extract-rules 时由PersistentPreRunE设置
+	extraFieldExtractor *crawler.ExtraFieldExtractor
+	labelMapper         *crawler.LabelMapper
 )
 
 var rootCmd = &cobra.Command{
 	Use:   "cxcrawler",
-	Short: "CXSecurity爬虫工具",
-	Long: `CXSecurity爬虫工具是一个用于爬取CXSecurity网站数据的命令行工具，
-可以爬取漏洞列表页面和CVE详情页面，并将结果保存为JSON格式。`,
+	Short: T("CXSecurity爬虫工具"),
+	Long: T(`CXSecurity爬虫工具是一个用于爬取CXSecurity网站数据的命令行工具，
+可以爬取漏洞列表页面和CVE详情页面，并将结果保存为JSON格式。`),
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if outputJQ != "" && outputTemplate != "" {
+			return errors.New(T("--jq 和 --template 不能同时使用"))
+		}
+		setupTerminal()
+		startRunStats(cmd)
+		if err := validateVerbosity(); err != nil {
+			return err
+		}
+		if err := validateLang(); err != nil {
+			return err
+		}
+		if err := loadTechniqueClassifier(); err != nil {
+			return err
+		}
+		if err := loadExtractRules(); err != nil {
+			return err
+		}
+		if err := parseClientFlags(); err != nil {
+			return err
+		}
+		if err := parseDateFlags(); err != nil {
+			return err
+		}
+		if err := parseOutputModes(); err != nil {
+			return err
+		}
+		if err := validateOutputTemplate(); err != nil {
+			return err
+		}
+		if err := validateOutputJQ(); err != nil {
+			return err
+		}
+		return parseFetchPolicy()
+	},
+}
+
+// parseOutputModes 解析输出权限相关的全局标志
+// --secure-output 优先于 --file-mode 和 --dir-mode
+func parseOutputModes() error {
+	if secureOutput {
+		outputFileMode, outputDirMode = crawler.SecureFileMode, crawler.SecureDirMode
+		return nil
+	}
+
+	fileMode, err := strconv.ParseUint(outputFileModeFlag, 8, 32)
+	if err != nil || fileMode > 0777 {
+		return fmt.Errorf(T("无效的文件权限 %q，应为八进制数，例如 0640"), outputFileModeFlag)
+	}
+	dirMode, err := strconv.ParseUint(outputDirModeFlag, 8, 32)
+	if err != nil || dirMode > 0777 {
+		return fmt.Errorf(T("无效的目录权限 %q，应为八进制数，例如 0750"), outputDirModeFlag)
+	}
+
+	outputFileMode, outputDirMode = os.FileMode(fileMode), os.FileMode(dirMode)
+	return nil
+}
+
+// loadTechniqueClassifier 根据 --attack 和 --attack-rules 创建ATT&CK技术分类器
+// 指定 --attack-rules 时使用文件中的规则代替内置规则，同时隐含 --attack
+func loadTechniqueClassifier() error {
+	if attackRulesFile != "" {
+		rules, err := crawler.LoadTechniqueRules(attackRulesFile)
+		if err != nil {
+			return err
+		}
+		techniqueClassifier = crawler.NewKeywordClassifier(rules)
+		return nil
+	}
+	if attackEnabled {
+		techniqueClassifier = crawler.NewKeywordClassifier(nil)
+	}
+	return nil
+}
+
+// loadExtractRules 读取 --extract-rules 指定的额外字段规则和标签映射，规则无效时返回错误
+func loadExtractRules() error {
+	extraFieldExtractor, labelMapper = nil, nil
+	if extractRulesFile == "" {
+		return nil
+	}
+	rules, err := crawler.LoadRules(extractRulesFile)
+	if err != nil {
+		return err
+	}
+	if len(rules.Fields) > 0 {
+		if extraFieldExtractor, err = crawler.NewExtraFieldExtractor(rules.Fields); err != nil {
+			return err
+		}
+	}
+	if len(rules.Labels) > 0 {
+		if labelMapper, err = crawler.NewLabelMapper(rules.Labels); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newCrawler 创建应用了全局标志的爬虫实例
+func newCrawler(options ...crawler.CrawlerOption) *crawler.Crawler {
+	options = append([]crawler.CrawlerOption{
+		crawler.WithClientOptions(clientOptions()...),
+		crawler.WithOutputPermissions(outputFileMode, outputDirMode),
+		crawler.WithOutputSync(syncOutput),
+	}, options...)
+	if techniqueClassifier != nil {
+		options = append(options, crawler.WithTechniqueClassifier(techniqueClassifier))
+	}
+	if archiveHTMLDir != "" {
+		options = append(options, crawler.WithHTMLArchive(archiveHTMLDir), crawler.WithHTMLArchiveCompression(archiveCompress))
+	}
+	if provenanceEnabled {
+		options = append(options, crawler.WithProvenance())
+	}
+	if extraFieldExtractor != nil {
+		options = append(options, crawler.WithExtraFields(extraFieldExtractor))
+	}
+	if labelMapper != nil {
+		options = append(options, crawler.WithLabelMapper(labelMapper))
+	}
+	if readThroughOption != nil {
+		// 放在调用者的选项之前，forceNetwork 等选项可以覆盖
+		options = append([]crawler.CrawlerOption{readThroughOption}, options...)
+	}
+	return crawler.NewCrawler(options...)
 }
 
 // Execute 执行rootCmd
+// 错误已经由cobra脱敏后写入标准错误，这里不再输出，避免混入标准输出中的结果
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
+	err := rootCmd.Execute()
+	saveRunStats(err == nil)
+	if err != nil {
 		os.Exit(1)
 	}
 }
 
 func init() {
-	// 这里可以添加全局标志
+	// 日志和命令的错误输出都经过脱敏，避免Token、代理密码等出现在日志中
+	log.SetOutput(logging.NewWriter(os.Stderr))
+	rootCmd.SetErr(logging.NewWriter(os.Stderr))
+
+	// 全局标志
+	rootCmd.PersistentFlags().StringVar(&outputFileModeFlag, "file-mode", "0644", T("输出文件权限(八进制)"))
+	rootCmd.PersistentFlags().StringVar(&outputDirModeFlag, "dir-mode", "0755", T("新建输出目录的权限(八进制)"))
+	rootCmd.PersistentFlags().BoolVar(&secureOutput, "secure-output", false, T("只允许当前用户访问输出文件(文件0600、目录0700)，覆盖 --file-mode 和 --dir-mode"))
+	rootCmd.PersistentFlags().BoolVar(&syncOutput, "fsync", false, T("保存结果时将文件刷到磁盘，防止系统崩溃后结果文件不完整"))
+	rootCmd.PersistentFlags().BoolVar(&attackEnabled, "attack", false, T("根据标题、标签和正文将漏洞映射为MITRE ATT&CK技术编号"))
+	rootCmd.PersistentFlags().StringVar(&attackRulesFile, "attack-rules", "", T("ATT&CK分类规则JSON文件，代替内置规则，隐含 --attack"))
+	rootCmd.PersistentFlags().StringVar(&archiveHTMLDir, "archive-html", "", T("将爬取到的原始HTML按日期保存到该目录，可以用 prune 命令按保留策略清理"))
+	rootCmd.PersistentFlags().BoolVar(&archiveCompress, "archive-compress", false, T("使用zstd压缩 --archive-html 保存的页面并记录内容的SHA-256"))
+	rootCmd.PersistentFlags().StringVar(&extractRulesFile, "extract-rules", "", T("规则JSON文件，按其中的CSS选择器从漏洞详情和CVE详情页提取字段到结果的 extras 中，并可以补充风险级别和标签的映射"))
+	rootCmd.PersistentFlags().StringVar(&displayTZ, "tz", "", T("表格和报告中日期使用的时区，例如 Asia/Shanghai、Local，默认UTC；JSON结果中的日期始终为UTC"))
+	rootCmd.PersistentFlags().StringVar(&displayDateFormat, "date-format", "", T("表格和报告中日期的格式：iso、rfc3339、us、eu、long、cn 或Go时间格式，默认iso"))
+	rootCmd.PersistentFlags().BoolVar(&provenanceEnabled, "provenance", false, T("保存漏洞详情和CVE详情时，在结果文件旁写入记录每个字段来源选择器的 .provenance.json 调试文件"))
 }
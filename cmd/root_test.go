@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+)
+
+func TestParseOutputModes(t *testing.T) {
+	defer func() {
+		outputFileModeFlag, outputDirModeFlag, secureOutput = "0644", "0755", false
+		outputFileMode, outputDirMode = crawler.DefaultFileMode, crawler.DefaultDirMode
+	}()
+
+	outputFileModeFlag, outputDirModeFlag, secureOutput = "640", "0750", false
+	assert.NoError(t, parseOutputModes())
+	assert.Equal(t, os.FileMode(0640), outputFileMode)
+	assert.Equal(t, os.FileMode(0750), outputDirMode)
+
+	secureOutput = true
+	assert.NoError(t, parseOutputModes())
+	assert.Equal(t, crawler.SecureFileMode, outputFileMode, "--secure-output应覆盖--file-mode")
+	assert.Equal(t, crawler.SecureDirMode, outputDirMode)
+
+	secureOutput = false
+	for _, invalid := range []string{"rw-r--r--", "0999", "10000"} {
+		outputFileModeFlag = invalid
+		assert.Error(t, parseOutputModes(), "应拒绝无效权限 %q", invalid)
+	}
+}
+
+func TestLoadExtractRules(t *testing.T) {
+	defer func() {
+		extractRulesFile = ""
+		assert.NoError(t, loadExtractRules())
+	}()
+	extractRulesFile = filepath.Join(t.TempDir(), "rules.json")
+
+	// 只有标签映射时不创建额外字段提取器
+	require.NoError(t, os.WriteFile(extractRulesFile, []byte(`{"labels": [{"kind": "risk", "canonical": "High", "aliases": ["kritisch"]}]}`), 0644))
+	require.NoError(t, loadExtractRules())
+	assert.Nil(t, extraFieldExtractor)
+	require.NotNil(t, labelMapper)
+	assert.Equal(t, "High", labelMapper.Risk("Kritisch"))
+
+	require.NoError(t, os.WriteFile(extractRulesFile, []byte(`{"labels": [{"kind": "risk", "canonical": "Critical"}]}`), 0644))
+	assert.Error(t, loadExtractRules(), "风险级别只能映射到 High、Med.、Low")
+}
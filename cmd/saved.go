@@ -0,0 +1,259 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/config"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/logging"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+var (
+	savedFile string
+
+	savedAddSearch  config.SavedSearch
+	savedAddReplace bool
+
+	savedRunAll       bool
+	savedRunOutputDir string
+)
+
+var savedCmd = &cobra.Command{
+	Use:   "saved",
+	Short: T("管理保存的搜索"),
+	Long: T(`保存命名的搜索条件，之后可以单独运行或一次运行全部。
+搜索条件保存在配置目录下的 saved_searches.json 中，可以通过环境变量 CXCRAWLER_CONFIG_DIR 指定配置目录。`),
+}
+
+var savedAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: T("保存一条搜索"),
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := config.NewSavedSearchStore(savedFile)
+		if err != nil {
+			return err
+		}
+
+		search := savedAddSearch
+		search.Name = strings.TrimSpace(args[0])
+		if err := store.Add(search, savedAddReplace); err != nil {
+			return err
+		}
+		fmt.Printf(T("已保存搜索 %s")+"\n", text.Colors{text.FgHiWhite, text.Bold}.Sprint(search.Name))
+		return nil
+	},
+}
+
+var savedListCmd = &cobra.Command{
+	Use:   "list",
+	Short: T("列出保存的搜索"),
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := config.NewSavedSearchStore(savedFile)
+		if err != nil {
+			return err
+		}
+		searches, err := store.List()
+		if err != nil {
+			return err
+		}
+
+		if printFormatted(searches) || quietOutput {
+			return nil
+		}
+		if len(searches) == 0 {
+			fmt.Println(text.Colors{text.FgHiBlack}.Sprint(T("还没有保存的搜索，使用 saved add 添加")))
+			return nil
+		}
+
+		t := table.NewWriter()
+		t.SetOutputMirror(os.Stdout)
+		t.SetStyle(tableStyle(table.StyleRounded))
+		t.AppendHeader(table.Row{T("名称"), T("关键词"), T("过滤条件"), T("创建时间")})
+		for _, s := range searches {
+			t.AppendRow(table.Row{
+				text.Colors{text.FgHiCyan, text.Bold}.Sprint(s.Name),
+				s.Keyword,
+				savedSearchFilters(s),
+				formatTimestamp(s.CreatedAt),
+			})
+		}
+		t.Render()
+		return nil
+	},
+}
+
+var savedRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: T("删除保存的搜索"),
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := config.NewSavedSearchStore(savedFile)
+		if err != nil {
+			return err
+		}
+		if err := store.Remove(args[0]); err != nil {
+			return err
+		}
+		fmt.Printf(T("已删除搜索 %s")+"\n", args[0])
+		return nil
+	},
+}
+
+var savedRunCmd = &cobra.Command{
+	Use:   "run [name...]",
+	Short: T("运行保存的搜索"),
+	Long: T(`运行指定名称的搜索，使用 --all 运行全部保存的搜索。
+每条搜索只获取第1页的结果；某条搜索失败时继续运行其余的搜索。
+指定 --fail-on-new 或 --fail-on-risk 时，所有搜索都成功并且有满足条件的结果时以退出码2退出，用于CI。
+指定 --desktop-notify 时有结果的搜索会弹出桌面通知，配合 --diff-against 只通知新的结果。`),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if savedRunAll == (len(args) > 0) {
+			return errors.New(T("请指定搜索名称或使用 --all，两者不能同时使用"))
+		}
+		gate, err := newFindingsGate()
+		if err != nil {
+			return err
+		}
+		report := newGitHubReport()
+		var known crawler.KnownResults
+		if searchDiffAgainst != "" {
+			if known, err = crawler.LoadKnownResults(searchDiffAgainst); err != nil {
+				return err
+			}
+		}
+
+		store, err := config.NewSavedSearchStore(savedFile)
+		if err != nil {
+			return err
+		}
+
+		var searches []config.SavedSearch
+		if savedRunAll {
+			if searches, err = store.List(); err != nil {
+				return err
+			}
+		} else {
+			for _, name := range args {
+				search, err := store.Get(name)
+				if err != nil {
+					return err
+				}
+				searches = append(searches, search)
+			}
+		}
+
+		c := newCrawler()
+		failed := 0
+		for _, search := range searches {
+			opts, err := search.Options()
+			if err == nil {
+				opts.Exclude = known
+				outputPath := ""
+				if savedRunOutputDir != "" {
+					outputPath = filepath.Join(savedRunOutputDir, safeFileName(search.Name)+".json")
+				}
+
+				if humanOutput() {
+					fmt.Printf("\n%s %s %s\n",
+						text.Colors{text.FgHiBlue, text.Bold}.Sprint(T("🔍 正在运行:")),
+						text.Colors{text.FgHiWhite, text.Bold}.Sprint(search.Name),
+						text.Colors{text.FgHiBlack}.Sprintf("(%s)", search.Keyword))
+				}
+
+				result, searchErr := c.Search(opts, outputPath)
+				if searchErr == nil {
+					gate.add(result.Vulnerabilities)
+					report.add(result.Vulnerabilities)
+					if desktopNotifyEnabled && len(result.Vulnerabilities) > 0 {
+						items := make([]model.Vulnerability, 0, len(result.Vulnerabilities))
+						for _, v := range result.Vulnerabilities {
+							items = append(items, v.ToVulnerability())
+						}
+						notifyDesktop(fmt.Sprintf(T("保存的搜索 %s 有 %d 条结果"), search.Name, len(items)), items)
+					}
+					if !printFormatted(result) && !quietOutput {
+						printSearchResult(result, outputPath)
+					}
+					continue
+				}
+				err = searchErr
+			}
+
+			failed++
+			logging.Printf("%s %s: %v\n", text.Colors{text.FgRed, text.Bold}.Sprint(T("❌ 搜索失败:")), search.Name, err)
+			printBlockedHint(err)
+		}
+
+		report.write(fmt.Sprintf("CXSecurity: %d saved searches", len(searches)))
+		if failed > 0 {
+			return fmt.Errorf(T("%d/%d 条搜索运行失败"), failed, len(searches))
+		}
+		gate.exit()
+		return nil
+	},
+}
+
+// savedSearchFilters 返回保存的搜索中除关键词外的条件的简短描述
+func savedSearchFilters(s config.SavedSearch) string {
+	var parts []string
+	if len(s.Risks) > 0 {
+		parts = append(parts, "risk="+strings.Join(s.Risks, ","))
+	}
+	if s.Remote {
+		parts = append(parts, "remote")
+	}
+	if s.Local {
+		parts = append(parts, "local")
+	}
+	if s.After != "" {
+		parts = append(parts, "after="+s.After)
+	}
+	if s.Before != "" {
+		parts = append(parts, "before="+s.Before)
+	}
+	if s.PerPage != 0 {
+		parts = append(parts, fmt.Sprintf("perpage=%d", s.PerPage))
+	}
+	if s.SortOrder != "" {
+		parts = append(parts, "sort="+s.SortOrder)
+	}
+	return strings.Join(parts, " ")
+}
+
+func init() {
+	rootCmd.AddCommand(savedCmd)
+	savedCmd.AddCommand(savedAddCmd, savedListCmd, savedRemoveCmd, savedRunCmd)
+
+	savedCmd.PersistentFlags().StringVar(&savedFile, "file", "", T("保存搜索的文件，默认为配置目录下的 saved_searches.json"))
+
+	savedAddCmd.Flags().StringVarP(&savedAddSearch.Keyword, "keyword", "k", "", T("搜索关键词"))
+	savedAddCmd.Flags().StringSliceVarP(&savedAddSearch.Risks, "risk", "r", nil, T("只显示指定风险级别的漏洞(High、Med、Low)，多个用逗号分隔"))
+	savedAddCmd.Flags().BoolVar(&savedAddSearch.Remote, "remote", false, T("只显示可远程利用的漏洞"))
+	savedAddCmd.Flags().BoolVar(&savedAddSearch.Local, "local", false, T("只显示本地利用的漏洞"))
+	savedAddCmd.Flags().StringVar(&savedAddSearch.After, "after", "", T("只搜索该日期及之后发布的漏洞，格式为 YYYY-MM-DD"))
+	savedAddCmd.Flags().StringVar(&savedAddSearch.Before, "before", "", T("只搜索该日期及之前发布的漏洞，格式为 YYYY-MM-DD"))
+	savedAddCmd.Flags().IntVarP(&savedAddSearch.PerPage, "perpage", "n", 0, T("每页结果数量，支持10或30，默认有过滤条件时为30，否则为10"))
+	savedAddCmd.Flags().StringVarP(&savedAddSearch.SortOrder, "sort", "s", "", T("排序顺序(ASC、DESC或RELEVANCE)，默认DESC"))
+	savedAddCmd.Flags().BoolVar(&savedAddReplace, "replace", false, T("覆盖同名的搜索"))
+	_ = savedAddCmd.MarkFlagRequired("keyword")
+
+	savedRunCmd.Flags().BoolVar(&savedRunAll, "all", false, T("运行全部保存的搜索"))
+	savedRunCmd.Flags().StringVarP(&savedRunOutputDir, "output-dir", "o", "", T("将每条搜索的结果保存为该目录下的 <名称>.json"))
+	savedRunCmd.Flags().BoolVar(&quietOutput, "silent", false, T("静默模式，不输出到标准输出"))
+	deprecateSilent(savedRunCmd)
+	savedRunCmd.Flags().StringVar(&searchDiffAgainst, "diff-against", "", T("只输出该结果文件中没有的漏洞，文件不存在时输出全部结果"))
+	savedRunCmd.Flags().BoolVar(&desktopNotifyEnabled, "desktop-notify", false, T("有结果时显示桌面通知"))
+	addFailOnFlags(savedRunCmd)
+	addGitHubActionsFlags(savedRunCmd)
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/schema"
+)
+
+var schemaValidate string
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema [type]",
+	Short: T("输出结果的JSON Schema"),
+	Long: T(`输出各种结果的JSON Schema文档，供下游程序校验爬虫的输出。
+不指定类型时列出所有可用的类型。使用 --validate 校验JSON文件是否符合Schema，"-" 表示标准输入。`),
+	Example: `  cxcrawler schema cve-detail > cve-detail.schema.json
+  cxcrawler schema vulnerability-list --validate output.json
+  cxcrawler cve -i CVE-2007-1411 -o cve.json && cxcrawler schema cve-detail --validate cve.json`,
+	Args:      cobra.MaximumNArgs(1),
+	ValidArgs: schema.Names(),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			if schemaValidate != "" {
+				return fmt.Errorf(T("校验时需要指定Schema类型，可选值：%v"), schema.Names())
+			}
+			for _, name := range schema.Names() {
+				fmt.Println(name)
+			}
+			return nil
+		}
+
+		name := args[0]
+		if schemaValidate == "" {
+			data, err := schema.Get(name)
+			if err != nil {
+				return err
+			}
+			_, err = os.Stdout.Write(data)
+			return err
+		}
+
+		var data []byte
+		var err error
+		if schemaValidate == "-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(schemaValidate)
+		}
+		if err != nil {
+			return fmt.Errorf(T("读取文件失败: %w"), err)
+		}
+		if err := schema.Validate(name, data); err != nil {
+			return err
+		}
+		fmt.Printf(T("%s 符合Schema %s")+"\n", schemaValidate, name)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(schemaCmd)
+	schemaCmd.Flags().StringVar(&schemaValidate, "validate", "", T("校验指定的JSON文件是否符合Schema，\"-\" 表示标准输入"))
+}
@@ -1,18 +1,20 @@
 package cmd
 
 import (
-	"bufio"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/jedib0t/go-pretty/v6/text"
 	"github.com/spf13/cobra"
-	"golang.org/x/term"
 
 	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/logging"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
 )
 
 var (
@@ -21,40 +23,167 @@ var (
 	searchPage       int
 	searchPerPage    int
 	searchSortOrder  string
-	searchSilent     bool
 	searchNoPaging   bool
+	searchPick       bool
+	searchRisks      []string
+	searchRemote     bool
+	searchLocal      bool
+	searchAfter      string
+	searchBefore     string
+
+	searchCVE string
+
+	searchAllPages bool
+	searchMaxPages int
+
+	searchKeywordsFile string
+	searchConcurrency  int
+	searchPages        int
+	searchDiffAgainst  string
 )
 
 var searchCmd = &cobra.Command{
 	Use:   "search",
-	Short: "搜索漏洞信息",
-	Long:  `使用关键词在CXSecurity网站上搜索漏洞，并将结果保存为JSON格式`,
+	Short: T("搜索漏洞信息"),
+	Long: T(`使用关键词在CXSecurity网站上搜索漏洞，并将结果保存为JSON格式。
+使用 --keywords-file 时并发搜索文件中的每个关键词(每行一个，# 开头的行为注释)，
+合并并去重结果，同时输出每个关键词搜索到的漏洞数。
+使用 --cve 时返回某个CVE对应的所有WLB公告：先从CVE详情页的相关公告列表获取，
+CVE页面没有列出公告时改为以CVE编号为关键词在网站上搜索，CVE编号的格式会先校验。
+使用 --all 时自动获取从 --page 开始的每一页(最多 --max-pages 页)，合并后保存为一个文件，
+代替交互式分页，适合在脚本中使用。`),
+	Example: `  cxcrawler search -k "sql injection"
+  cxcrawler search --keywords-file products.txt --pages 2 -r High -o products.json
+  cxcrawler search --cve CVE-2024-1234 -r High
+  cxcrawler search -k wordpress --all --max-pages 50 --rate-limit 1 -o wordpress.json
+  cxcrawler search -k wordpress --no-paging --diff-against previous.json -o new.json
+  cxcrawler search --keywords-file stack.txt --diff-against known.json --fail-on-risk High -q`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// 创建爬虫实例
-		c := crawler.NewCrawler()
+		c := newCrawler()
 
 		// 检查每页数量和排序顺序的有效性
 		if searchPerPage != 10 && searchPerPage != 30 {
-			fmt.Println("警告: 每页数量只能为10或30，已自动设置为10")
+			fmt.Println(T("警告: 每页数量只能为10或30，已自动设置为10"))
 			searchPerPage = 10
 		}
 
 		sortOrder := "DESC"
 		if searchSortOrder != "" {
 			upperSortOrder := strings.ToUpper(searchSortOrder)
-			if upperSortOrder == "ASC" || upperSortOrder == "DESC" {
+			if upperSortOrder == "ASC" || upperSortOrder == "DESC" || upperSortOrder == crawler.SortRelevance {
 				sortOrder = upperSortOrder
 			} else {
-				fmt.Println("警告: 排序顺序只能为ASC或DESC，已自动设置为DESC")
+				fmt.Println(T("警告: 排序顺序只能为ASC、DESC或RELEVANCE，已自动设置为DESC"))
 			}
 		}
 
+		for _, r := range searchRisks {
+			if crawler.NormalizeRiskLevel(r) == "" {
+				fmt.Printf(T("无效的风险级别 %q，可选值：High、Med、Low")+"\n", r)
+				return
+			}
+		}
+		if searchCVE != "" {
+			id, err := model.ParseCVEID(searchCVE)
+			if err != nil {
+				fmt.Printf(T("无效的CVE编号 %q，格式应为 CVE-YYYY-NNNN")+"\n", searchCVE)
+				return
+			}
+			searchCVE = id.String()
+		}
+		after, err := parseDateFlag(searchAfter)
+		if err != nil {
+			fmt.Printf(T("无效的开始日期 %q，格式应为 YYYY-MM-DD")+"\n", searchAfter)
+			return
+		}
+		before, err := parseDateFlag(searchBefore)
+		if err != nil {
+			fmt.Printf(T("无效的结束日期 %q，格式应为 YYYY-MM-DD")+"\n", searchBefore)
+			return
+		}
+		if !after.IsZero() && !before.IsZero() && after.After(before) {
+			fmt.Println(T("开始日期不能晚于结束日期"))
+			return
+		}
+
+		gate, err := newFindingsGate()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		report := newGitHubReport()
+
+		var known crawler.KnownResults
+		if searchDiffAgainst != "" {
+			if known, err = crawler.LoadKnownResults(searchDiffAgainst); err != nil {
+				logging.Printf("%s %v\n", text.Colors{text.FgRed, text.Bold}.Sprint(T("❌ 搜索失败:")), err)
+				return
+			}
+		}
+
+		// 网站不支持按风险级别和利用方式搜索，过滤时默认使用每页30条，减少需要翻的页数
+		if (len(searchRisks) > 0 || searchRemote || searchLocal) && !cmd.Flags().Changed("perpage") {
+			searchPerPage = 30
+		}
+
+		if searchCVE != "" {
+			runCVESearch(c, crawler.SearchOptions{
+				PerPage: searchPerPage,
+				Risks:   searchRisks,
+				Remote:  searchRemote,
+				Local:   searchLocal,
+				After:   after,
+				Before:  before,
+				Exclude: known,
+			}, gate, report)
+			gate.exit()
+			return
+		}
+
+		if searchKeywordsFile != "" {
+			runMultiSearch(c, crawler.SearchOptions{
+				Page:      searchPage,
+				PerPage:   searchPerPage,
+				SortOrder: sortOrder,
+				Risks:     searchRisks,
+				Remote:    searchRemote,
+				Local:     searchLocal,
+				After:     after,
+				Before:    before,
+				Exclude:   known,
+			}, gate, report)
+			gate.exit()
+			return
+		}
+
+		if searchAllPages {
+			runAllPagesSearch(c, crawler.SearchOptions{
+				Keyword:   searchKeyword,
+				Page:      searchPage,
+				PerPage:   searchPerPage,
+				SortOrder: sortOrder,
+				Risks:     searchRisks,
+				Remote:    searchRemote,
+				Local:     searchLocal,
+				After:     after,
+				Before:    before,
+				Exclude:   known,
+			}, gate, report)
+			gate.exit()
+			return
+		}
+
 		// 显示搜索开始提示
-		if !searchSilent {
+		if humanOutput() {
 			fmt.Printf("\n%s %s %s\n\n",
-				text.Colors{text.FgHiBlue, text.Bold}.Sprint("🔍 正在搜索:"),
+				text.Colors{text.FgHiBlue, text.Bold}.Sprint(T("🔍 正在搜索:")),
 				text.Colors{text.FgHiWhite, text.Bold}.Sprint(searchKeyword),
-				text.Colors{text.FgHiBlack}.Sprintf("(排序: %s, 每页: %d)", sortOrder, searchPerPage))
+				text.Colors{text.FgHiBlack}.Sprintf(T("(排序: %s, 每页: %d)"), sortOrder, searchPerPage))
+		}
+		if searchDiffAgainst != "" && humanOutput() {
+			fmt.Printf(T("🆕 只显示 %s 中没有的结果")+"\n", searchDiffAgainst)
 		}
 
 		// 循环查询多页结果
@@ -69,29 +198,56 @@ var searchCmd = &cobra.Command{
 			}
 
 			// 显示加载提示
-			if !searchSilent {
-				fmt.Printf("%s 第 %d 页...\r",
-					text.Colors{text.FgHiCyan}.Sprint("⏳ 加载中:"),
+			if humanOutput() {
+				fmt.Printf(T("%s 第 %d 页...\r"), text.Colors{text.FgHiCyan}.Sprint(T("⏳ 加载中:")),
 					currentPage)
 			}
 
-			result, err := c.SearchVulnerabilitiesAdvanced(searchKeyword, currentPage, searchPerPage, sortOrder, outputPath)
+			result, err := c.Search(crawler.SearchOptions{
+				Keyword:   searchKeyword,
+				Page:      currentPage,
+				PerPage:   searchPerPage,
+				SortOrder: sortOrder,
+				Risks:     searchRisks,
+				Remote:    searchRemote,
+				Local:     searchLocal,
+				After:     after,
+				Before:    before,
+				Exclude:   known,
+			}, outputPath)
 			if err != nil {
-				fmt.Printf("\n%s %v\n",
-					text.Colors{text.FgRed, text.Bold}.Sprint("❌ 搜索失败:"),
+				logging.Printf("\n%s %v\n",
+					text.Colors{text.FgRed, text.Bold}.Sprint(T("❌ 搜索失败:")),
 					err)
+				printBlockedHint(err)
+				gate.abort()
 				return
 			}
+			gate.add(result.Vulnerabilities)
+			report.add(result.Vulnerabilities)
 
 			// 只有在非静默模式下才输出结果
-			if !searchSilent {
+			if !printFormatted(result) && !quietOutput {
 				// 清除加载提示
 				fmt.Print("\r                                  \r")
 				printSearchResult(result, outputPath)
 			}
+			// 只对第一页结果打开浏览器或复制链接，交互式选择时对选中的详情执行
+			if currentPage == searchPage && !searchPick {
+				handleOpenAndCopy(result)
+			}
+
+			// 交互式选择一条结果并查看详情
+			if searchPick && humanOutput() {
+				if picked := pickSearchResult(stdinReader, os.Stdout, result.Vulnerabilities); picked != nil {
+					showPickedDetail(c, picked)
+					return
+				}
+			}
 
 			// 如果启用了分页并且还有更多页，询问用户是否继续
-			if !searchNoPaging && currentPage < result.TotalPages {
+			// 使用模板输出时不进行交互式分页
+			if !searchNoPaging && humanOutput() && currentPage < result.TotalPages {
 				if !askForNextPage(currentPage, result.TotalPages) {
 					break
 				}
@@ -100,20 +256,203 @@ var searchCmd = &cobra.Command{
 				break
 			}
 		}
+		report.write("CXSecurity: " + searchKeyword)
+		gate.exit()
 	},
 }
 
+// runCVESearch 返回 --cve 指定的CVE对应的所有WLB公告，结果只有一页，不进行交互式分页
+func runCVESearch(c *crawler.Crawler, opts crawler.SearchOptions, gate *findingsGate, report *githubReport) {
+	if humanOutput() {
+		fmt.Printf("\n%s %s\n\n",
+			text.Colors{text.FgHiBlue, text.Bold}.Sprint(T("🔍 正在查找CVE对应的公告:")),
+			text.Colors{text.FgHiWhite, text.Bold}.Sprint(searchCVE))
+	}
+	result, err := c.SearchByCVE(searchCVE, opts, searchOutputFile)
+	if err != nil {
+		logging.Printf("%s %v\n", text.Colors{text.FgRed, text.Bold}.Sprint(T("❌ 搜索失败:")), err)
+		printBlockedHint(err)
+		gate.abort()
+		return
+	}
+	gate.add(result.Vulnerabilities)
+	report.add(result.Vulnerabilities)
+
+	if !printFormatted(result) && !quietOutput {
+		printSearchResult(result, searchOutputFile)
+	}
+	if !searchPick {
+		handleOpenAndCopy(result)
+	} else if humanOutput() {
+		if picked := pickSearchResult(stdinReader, os.Stdout, result.Vulnerabilities); picked != nil {
+			showPickedDetail(c, picked)
+		}
+	}
+	report.write("CXSecurity: " + result.Keyword)
+}
+
+// runAllPagesSearch 获取 --all 指定的所有页并合并输出，不进行交互式分页
+// 中途某一页失败时仍然输出已获取的结果，但不保存文件
+func runAllPagesSearch(c *crawler.Crawler, opts crawler.SearchOptions, gate *findingsGate, report *githubReport) {
+	if humanOutput() {
+		fmt.Printf("\n%s %s %s\n\n",
+			text.Colors{text.FgHiBlue, text.Bold}.Sprint(T("🔍 正在搜索:")),
+			text.Colors{text.FgHiWhite, text.Bold}.Sprint(opts.Keyword),
+			text.Colors{text.FgHiBlack}.Sprintf(T("(所有页, 最多 %d 页)"), searchMaxPages))
+	}
+	result, err := c.SearchAll(opts, searchMaxPages, searchOutputFile)
+	if err != nil {
+		logging.Printf("%s %v\n", text.Colors{text.FgRed, text.Bold}.Sprint(T("❌ 搜索失败:")), err)
+		printBlockedHint(err)
+		if result == nil {
+			gate.abort()
+			return
+		}
+	}
+	outputPath := searchOutputFile
+	if err != nil {
+		outputPath = ""
+	}
+	gate.add(result.Vulnerabilities)
+	report.add(result.Vulnerabilities)
+
+	if !printFormatted(result) && !quietOutput {
+		printSearchResult(result, outputPath)
+		if result.Duplicates > 0 {
+			fmt.Printf(T("已去掉 %d 条在多页中重复出现的记录")+"\n", result.Duplicates)
+		}
+		if result.CurrentPage+result.PagesFetched-1 < result.TotalPages {
+			fmt.Printf(T("已获取 %d 页，共 %d 页，可以增大 --max-pages 获取更多结果")+"\n", result.PagesFetched, result.TotalPages)
+		}
+	}
+	if !searchPick {
+		handleOpenAndCopy(result)
+	} else if humanOutput() {
+		if picked := pickSearchResult(stdinReader, os.Stdout, result.Vulnerabilities); picked != nil {
+			showPickedDetail(c, picked)
+		}
+	}
+	report.write("CXSecurity: " + opts.Keyword)
+	if err != nil {
+		gate.abort()
+	}
+}
+
+// runMultiSearch 并发搜索 --keywords-file 中的关键词，输出每个关键词的统计和合并后的结果
+func runMultiSearch(c *crawler.Crawler, opts crawler.SearchOptions, gate *findingsGate, report *githubReport) {
+	keywords, err := crawler.ReadKeywordsFile(searchKeywordsFile)
+	if err != nil {
+		logging.Printf("%s %v\n", text.Colors{text.FgRed, text.Bold}.Sprint(T("❌ 搜索失败:")), err)
+		printBlockedHint(err)
+		gate.abort()
+		return
+	}
+
+	if humanOutput() {
+		fmt.Printf("\n%s %s\n\n",
+			text.Colors{text.FgHiBlue, text.Bold}.Sprintf(T("🔍 正在搜索 %d 个关键词:"), len(keywords)),
+			text.Colors{text.FgHiBlack}.Sprintf(T("(并发: %d, 每个关键词 %d 页)"), searchConcurrency, searchPages))
+	}
+
+	result, err := c.MultiSearch(crawler.MultiSearchOptions{
+		Keywords:    keywords,
+		Search:      opts,
+		Pages:       searchPages,
+		Concurrency: searchConcurrency,
+	}, searchOutputFile)
+	if err != nil {
+		logging.Printf("%s %v\n", text.Colors{text.FgRed, text.Bold}.Sprint(T("❌ 搜索失败:")), err)
+		printBlockedHint(err)
+		gate.abort()
+		return
+	}
+	gate.add(result.Vulnerabilities)
+	report.add(result.Vulnerabilities)
+	if !printFormatted(result) && !quietOutput {
+		printMultiSearchResult(result, searchOutputFile)
+	}
+	report.write(fmt.Sprintf("CXSecurity: %d keywords", len(keywords)))
+}
+
+// printMultiSearchResult 打印每个关键词的统计和合并去重后的漏洞
+func printMultiSearchResult(result *crawler.MultiSearchResult, outputPath string) {
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(tableStyle(table.StyleRounded))
+	t.SetTitle(T("每个关键词的结果"))
+	t.AppendHeader(table.Row{T("关键词"), T("漏洞数"), T("页数"), T("重复"), T("错误")})
+	for _, k := range result.Keywords {
+		hits := text.Colors{text.FgHiGreen}.Sprint(k.Hits)
+		if k.Hits == 0 {
+			hits = text.Colors{text.FgHiBlack}.Sprint(k.Hits)
+		}
+		errText := ""
+		if k.Error != "" {
+			errText = text.Colors{text.FgRed}.Sprint(truncateCell(k.Error, 60))
+		}
+		duplicates := ""
+		if k.Duplicates > 0 {
+			duplicates = text.Colors{text.FgHiYellow}.Sprint(k.Duplicates)
+		}
+		t.AppendRow(table.Row{k.Keyword, hits, fmt.Sprintf("%d/%d", k.Pages, k.TotalPages), duplicates, errText})
+	}
+	t.Render()
+
+	v := table.NewWriter()
+	v.SetOutputMirror(os.Stdout)
+	v.SetStyle(tableStyle(table.StyleRounded))
+	v.SetTitle(T("合并后的结果"))
+	v.AppendHeader(table.Row{"ID", T("标题"), T("日期"), T("风险级别"), T("关键词")})
+	for _, item := range result.Vulnerabilities {
+		risk := item.RiskLevel
+		if risk == "" && item.RiskInferred {
+			risk = item.DerivedRisk
+		}
+		v.AppendRow(table.Row{
+			text.Colors{text.FgHiCyan}.Sprint(item.ID),
+			truncateCell(item.Title, 60),
+			formatSearchDate(item.Date),
+			riskPrefix(item.RiskLevel, item.RiskInferred) + riskColors(risk).Sprint(risk),
+			text.Colors{text.FgHiMagenta}.Sprint(truncateCell(strings.Join(item.Keywords, ", "), 40)),
+		})
+	}
+	v.AppendFooter(table.Row{fmt.Sprintf(T("总计: %d 条记录"), result.Total), "", "", "", ""})
+	v.Render()
+
+	if outputPath != "" {
+		fmt.Printf("\n%s %s\n",
+			text.Colors{text.FgHiGreen}.Sprint(T("✅ 已保存:")),
+			text.Colors{text.FgHiCyan, text.Underline}.Sprint(outputPath))
+	}
+}
+
 // askForNextPage 询问用户是否继续查看下一页
 func askForNextPage(currentPage, totalPages int) bool {
-	reader := bufio.NewReader(os.Stdin)
-	fmt.Printf("\n%s %s (y/n): ",
-		text.Colors{text.FgHiYellow}.Sprint("📄"),
-		text.Colors{text.FgHiWhite}.Sprintf("当前第 %d/%d 页，是否查看下一页？", currentPage, totalPages))
-	text, _ := reader.ReadString('\n')
+	fmt.Printf("\n%s%s (y/n): ",
+		text.Colors{text.FgHiYellow}.Sprint(icon("📄")),
+		text.Colors{text.FgHiWhite}.Sprintf(T("当前第 %d/%d 页，是否查看下一页？"), currentPage, totalPages))
+	text, _ := stdinReader.ReadString('\n')
 	text = strings.TrimSpace(strings.ToLower(text))
 	return text == "y" || text == "yes"
 }
 
+// showPickedDetail 爬取并显示交互式选择的漏洞详情
+func showPickedDetail(c *crawler.Crawler, picked *crawler.SearchVulnerability) {
+	fmt.Printf("\n%s %s\n",
+		text.Colors{text.FgHiBlue, text.Bold}.Sprint(T("📖 正在获取漏洞详情:")),
+		text.Colors{text.FgHiWhite, text.Bold}.Sprint(picked.ID))
+
+	result, err := c.CrawlExploit(picked.ID, "", "")
+	if err != nil {
+		logging.Printf("\n%s %v\n",
+			text.Colors{text.FgRed, text.Bold}.Sprint(T("❌ 获取失败:")),
+			err)
+		return
+	}
+	printExploitResult(result, "")
+	handleOpenAndCopy(result)
+}
+
 // printSearchResult 打印搜索结果
 func printSearchResult(result *crawler.SearchResult, outputPath string) {
 	// 使用go-pretty创建美观的表格
@@ -121,14 +460,10 @@ func printSearchResult(result *crawler.SearchResult, outputPath string) {
 	t.SetOutputMirror(os.Stdout)
 
 	// 设置表格样式
-	t.SetStyle(table.StyleRounded)
+	t.SetStyle(tableStyle(table.StyleRounded))
 
-	// 获取终端宽度
-	width, _, err := term.GetSize(int(os.Stdout.Fd()))
-	if err != nil {
-		// 如果获取失败，使用默认宽度
-		width = 120
-	}
+	// 获取终端宽度，获取失败时使用默认宽度
+	width := terminalWidth(120)
 
 	// 动态计算各列宽度
 	// 终端宽度减去表格边框和列分隔符所占用的空间
@@ -149,7 +484,7 @@ func printSearchResult(result *crawler.SearchResult, outputPath string) {
 	authorWidth := max(12, int(float64(availableWidth)*authorRatio))
 
 	// 设置表头
-	t.AppendHeader(table.Row{"ID", "标题", "日期", "风险级别", "作者"})
+	t.AppendHeader(table.Row{"ID", T("标题"), T("日期"), T("风险级别"), T("作者")})
 
 	// 设置表头样式 - 深色背景
 	t.SetColumnConfigs([]table.ColumnConfig{
@@ -163,28 +498,18 @@ func printSearchResult(result *crawler.SearchResult, outputPath string) {
 	// 添加数据行
 	for _, item := range result.Vulnerabilities {
 		// 标题可能很长，需要截断
-		title := item.Title
-		if len(title) > titleWidth-3 {
-			// 截断标题部分，为省略号留出空间
-			maxTitleLen := titleWidth - 6
-			if maxTitleLen > 0 && maxTitleLen < len(title) {
-				title = title[:maxTitleLen] + "..."
-			}
-		}
+		title := truncateCell(item.Title, titleWidth-3)
 
 		// 作者名可能很长，需要截断
-		author := item.Author
-		if len(author) > authorWidth-3 {
-			// 安全截断，确保不会越界
-			maxAuthorLen := authorWidth - 6
-			if maxAuthorLen > 0 && maxAuthorLen < len(author) {
-				author = author[:maxAuthorLen] + "..."
-			}
-		}
+		author := truncateCell(item.Author, authorWidth-3)
 
-		// 根据风险级别设置不同颜色
+		// 根据风险级别设置不同颜色，没有标签时显示推断的级别，前面加 ~ 表示估计
+		risk := item.RiskLevel
+		if risk == "" && item.RiskInferred {
+			risk = item.DerivedRisk
+		}
 		var riskColor text.Colors
-		switch item.RiskLevel {
+		switch risk {
 		case "High":
 			riskColor = text.Colors{text.FgRed, text.Bold}
 		case "Med.":
@@ -199,62 +524,106 @@ func printSearchResult(result *crawler.SearchResult, outputPath string) {
 		t.AppendRow(table.Row{
 			text.Colors{text.FgHiCyan}.Sprint(item.ID),
 			title,
-			item.Date,
-			riskColor.Sprint(item.RiskLevel),
+			formatSearchDate(item.Date),
+			riskPrefix(item.RiskLevel, item.RiskInferred) + riskColor.Sprint(risk),
 			text.Colors{text.FgHiMagenta}.Sprint(author),
 		})
 	}
 
 	// 添加页码信息到表格底部
 	t.AppendFooter(table.Row{
-		fmt.Sprintf("总计: %d 条记录", len(result.Vulnerabilities)),
+		fmt.Sprintf(T("总计: %d 条记录"), len(result.Vulnerabilities)),
 		"",
 		"",
-		fmt.Sprintf("页码: %d/%d", result.CurrentPage, result.TotalPages),
+		fmt.Sprintf(T("页码: %d/%d"), result.CurrentPage, result.TotalPages),
 		""})
 
 	// 渲染表格标题
 	fmt.Printf("\n%s %s\n",
-		text.Colors{text.Bold, text.FgHiGreen}.Sprint("🔎 搜索结果:"),
+		text.Colors{text.Bold, text.FgHiGreen}.Sprint(T("🔎 搜索结果:")),
 		text.Colors{text.Bold, text.FgHiWhite}.Sprint(result.Keyword))
 
 	fmt.Printf("%s %s | %s %d\n",
-		text.Colors{text.FgHiBlack}.Sprint("⬆️ 排序:"),
+		text.Colors{text.FgHiBlack}.Sprint(T("⬆️ 排序:")),
 		getSortOrderText(result.SortOrder),
-		text.Colors{text.FgHiBlack}.Sprint("📊 每页:"),
+		text.Colors{text.FgHiBlack}.Sprint(T("📊 每页:")),
 		result.PerPage)
 
 	// 渲染表格
 	t.Render()
+	if result.EmptyResult {
+		fmt.Println(text.Colors{text.FgHiBlack}.Sprint(T("网站上没有匹配的漏洞")))
+	}
 
 	// 显示保存信息
 	if outputPath != "" {
 		fmt.Printf("\n%s %s\n",
-			text.Colors{text.FgHiGreen}.Sprint("✅ 已保存:"),
+			text.Colors{text.FgHiGreen}.Sprint(T("✅ 已保存:")),
 			text.Colors{text.FgHiCyan, text.Underline}.Sprint(outputPath))
 	}
 }
 
+// printBlockedHint 在搜索被网站拦截时提示如何处理，与没有结果区分开
+func printBlockedHint(err error) {
+	if errors.Is(err, crawler.ErrBlocked) {
+		logging.Printf("%s\n", text.Colors{text.FgHiYellow}.Sprint(T("💡 网站返回了验证码或封禁页面，并不是没有结果；请用 --rate-limit 降低请求频率、用 --proxy 更换出口或稍后再试")))
+	}
+}
+
 // getSortOrderText 返回排序顺序的友好文本
 func getSortOrderText(sortOrder string) string {
-	if sortOrder == "DESC" {
-		return "最新优先"
+	switch sortOrder {
+	case "DESC":
+		return T("最新优先")
+	case crawler.SortRelevance:
+		return T("相关度优先")
 	}
-	return "最早优先"
+	return T("最早优先")
 }
 
 func init() {
 	rootCmd.AddCommand(searchCmd)
 
 	// 添加标志
-	searchCmd.Flags().StringVarP(&searchOutputFile, "output", "o", "search_result.json", "输出文件路径")
-	searchCmd.Flags().StringVarP(&searchKeyword, "keyword", "k", "", "搜索关键词")
-	searchCmd.Flags().IntVarP(&searchPage, "page", "p", 1, "搜索结果页码")
-	searchCmd.Flags().IntVarP(&searchPerPage, "perpage", "n", 10, "每页记录数(10或30)")
-	searchCmd.Flags().StringVarP(&searchSortOrder, "sort", "s", "DESC", "排序顺序(ASC或DESC)")
-	searchCmd.Flags().BoolVarP(&searchSilent, "silent", "", false, "静默模式，不输出到标准输出，适用于API调用")
-	searchCmd.Flags().BoolVarP(&searchNoPaging, "no-paging", "", false, "禁用交互式分页，只显示指定页")
-
-	// 设置必需标志
-	searchCmd.MarkFlagRequired("keyword")
+	searchCmd.Flags().StringVarP(&searchOutputFile, "output", "o", "search_result.json", T("输出文件路径"))
+	searchCmd.Flags().StringVarP(&searchKeyword, "keyword", "k", "", T("搜索关键词"))
+	searchCmd.Flags().StringVar(&searchCVE, "cve", "", T("返回该CVE对应的所有WLB公告，例如 CVE-2024-1234"))
+	searchCmd.Flags().IntVarP(&searchPage, "page", "p", 1, T("搜索结果页码"))
+	searchCmd.Flags().IntVarP(&searchPerPage, "perpage", "n", 10, T("每页记录数(10或30)"))
+	searchCmd.Flags().StringVarP(&searchSortOrder, "sort", "s", "DESC", T("排序顺序(ASC、DESC或RELEVANCE)，RELEVANCE在本地按相关度排序当前页"))
+	searchCmd.Flags().BoolVarP(&quietOutput, "silent", "", false, T("静默模式，不输出到标准输出，适用于API调用"))
+	deprecateSilent(searchCmd)
+	searchCmd.Flags().BoolVarP(&searchNoPaging, "no-paging", "", false, T("禁用交互式分页，只显示指定页"))
+	searchCmd.Flags().BoolVarP(&searchPick, "pick", "", false, T("显示结果后交互式选择一条记录并查看详情"))
+	searchCmd.Flags().StringSliceVarP(&searchRisks, "risk", "r", nil, T("只显示指定风险级别的漏洞(High、Med、Low)，多个用逗号分隔"))
+	searchCmd.Flags().BoolVar(&searchRemote, "remote", false, T("只显示可远程利用的漏洞"))
+	searchCmd.Flags().BoolVar(&searchLocal, "local", false, T("只显示本地利用的漏洞"))
+	searchCmd.Flags().StringVar(&searchAfter, "after", "", T("只搜索该日期及之后发布的漏洞，格式为 YYYY-MM-DD"))
+	searchCmd.Flags().StringVar(&searchBefore, "before", "", T("只搜索该日期及之前发布的漏洞，格式为 YYYY-MM-DD"))
+
+	searchCmd.Flags().StringVar(&searchKeywordsFile, "keywords-file", "", T("关键词列表文件，每行一个关键词，并发搜索后合并去重"))
+	searchCmd.Flags().IntVar(&searchConcurrency, "concurrency", crawler.DefaultSearchConcurrency, T("使用 --keywords-file 时同时进行的搜索数"))
+	searchCmd.Flags().IntVar(&searchPages, "pages", 1, T("使用 --keywords-file 时每个关键词获取的页数"))
+	addFailOnFlags(searchCmd)
+	addGitHubActionsFlags(searchCmd)
+	searchCmd.Flags().StringVar(&searchDiffAgainst, "diff-against", "", T("只输出该结果文件中没有的漏洞，文件不存在时输出全部结果"))
+	searchCmd.Flags().BoolVar(&searchAllPages, "all", false, T("自动获取从 --page 开始的所有页并合并为一个结果，代替交互式分页"))
+	searchCmd.Flags().IntVar(&searchMaxPages, "max-pages", crawler.DefaultSearchMaxPages, T("使用 --all 时最多获取的页数"))
+
+	// 必须指定关键词、关键词列表或CVE编号之一
+	searchCmd.MarkFlagsOneRequired("keyword", "keywords-file", "cve")
+	searchCmd.MarkFlagsMutuallyExclusive("keyword", "keywords-file", "cve")
+	// --all 只用于关键词搜索，--keywords-file 使用 --pages 指定页数
+	searchCmd.MarkFlagsMutuallyExclusive("all", "keywords-file")
+	searchCmd.MarkFlagsMutuallyExclusive("all", "cve")
+	searchCmd.MarkFlagsMutuallyExclusive("all", "no-paging")
+}
+
+// parseDateFlag 解析 YYYY-MM-DD 格式的日期参数，空字符串返回零值
+func parseDateFlag(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse("2006-01-02", s)
 }
@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDateFlag(t *testing.T) {
+	d, err := parseDateFlag("2021-03-04")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC), d)
+
+	d, err = parseDateFlag(" ")
+	assert.NoError(t, err)
+	assert.True(t, d.IsZero())
+
+	_, err = parseDateFlag("2021/03/04")
+	assert.Error(t, err)
+}
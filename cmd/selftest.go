@@ -0,0 +1,257 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/apiclient"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/fixture"
+)
+
+var selftestDir string
+
+// selftestCommand 创建运行命令行的子进程，测试中替换为运行测试二进制
+var selftestCommand = func(args ...string) (*exec.Cmd, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	return exec.Command(exe, args...), nil
+}
+
+// selftestResult 是一项检查的结果
+type selftestResult struct {
+	name     string
+	duration time.Duration
+	err      error
+}
+
+// selftestCLICase 以子进程运行一个命令，检查 --jq 的输出和保存的结果文件
+type selftestCLICase struct {
+	name   string
+	args   []string // 在输出目录中运行，-o 使用相对路径
+	output string   // 命令保存的结果文件
+	expect string   // 期望的 --jq 输出，为空时只要求非空
+}
+
+// selftestCLICases 的期望值与 docs/response-examples 中的页面对应
+var selftestCLICases = []selftestCLICase{
+	{"list", []string{"list", "--no-paging", "-o", "list.json", "--jq", ".items[0].id", "--raw-output"}, "list.json", "WLB-2007030137"},
+	{"exploit", []string{"exploit", "-i", "WLB-2007030137", "-o", "exploit.json", "--jq", ".id", "--raw-output"}, "exploit.json", "WLB-2007030137"},
+	{"cve", []string{"cve", "-i", "CVE-2007-1411", "-o", "cve.json", "--jq", ".cve_id", "--raw-output"}, "cve.json", "CVE-2007-1411"},
+	{"author", []string{"author", "-i", "rgod", "-o", "author.json", "--jq", ".name", "--raw-output"}, "author.json", ""},
+	{"search", []string{"search", "-k", "php", "--no-paging", "-o", "search.json", "--jq", ".vulnerabilities | length"}, "search.json", "10"},
+}
+
+// runSelftestCLI 依次运行命令行检查，命令的配置目录和输出文件都在dir中
+func runSelftestCLI(baseURL, dir string) []selftestResult {
+	results := make([]selftestResult, 0, len(selftestCLICases))
+	for _, tc := range selftestCLICases {
+		start := time.Now()
+		err := runSelftestCLICase(baseURL, dir, tc)
+		results = append(results, selftestResult{name: "cxcrawler " + tc.name, duration: time.Since(start), err: err})
+	}
+	return results
+}
+
+func runSelftestCLICase(baseURL, dir string, tc selftestCLICase) error {
+	args := append([]string{"--base-url", baseURL, "--retries", "0", "--lang", "zh"}, tc.args...)
+	cmd, err := selftestCommand(args...)
+	if err != nil {
+		return err
+	}
+	cmd.Dir = dir
+	cmd.Env = append(cmd.Environ(), "CXCRAWLER_CONFIG_DIR="+filepath.Join(dir, "config"), "NO_COLOR=1")
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(exitErr.Stderr)+string(out)))
+		}
+		return err
+	}
+
+	got := strings.TrimSpace(string(out))
+	switch {
+	case tc.expect != "" && got != tc.expect:
+		return fmt.Errorf(T("输出 %q，期望 %q"), got, tc.expect)
+	case got == "" || got == "null":
+		return errors.New(T("输出为空"))
+	}
+	data, err := os.ReadFile(filepath.Join(dir, tc.output))
+	if err != nil {
+		return err
+	}
+	if !json.Valid(data) {
+		return fmt.Errorf(T("%s 不是有效的JSON"), tc.output)
+	}
+	return nil
+}
+
+// selftestAPICase 通过 pkg/apiclient 请求一个API接口
+type selftestAPICase struct {
+	name string
+	run  func(ctx context.Context, c *apiclient.Client) error
+}
+
+// selftestExpect 比较实际值和期望值
+func selftestExpect(got, expect interface{}) error {
+	if got != expect {
+		return fmt.Errorf(T("返回 %v，期望 %v"), got, expect)
+	}
+	return nil
+}
+
+var selftestAPICases = []selftestAPICase{
+	{"GET /api/v1/exploit", func(ctx context.Context, c *apiclient.Client) error {
+		list, err := c.ExploitList(ctx, crawler.SectionExploit, 1)
+		if err != nil {
+			return err
+		}
+		if len(list.Items) == 0 {
+			return errors.New(T("输出为空"))
+		}
+		return selftestExpect(list.Items[0].ID, "WLB-2007030137")
+	}},
+	{"GET /api/v1/exploit/latest", func(ctx context.Context, c *apiclient.Client) error {
+		latest, err := c.Latest(ctx, crawler.LatestOptions{Limit: 5})
+		if err != nil {
+			return err
+		}
+		return selftestExpect(len(latest.Vulnerabilities), 5)
+	}},
+	{"GET /api/v1/exploit/{id}", func(ctx context.Context, c *apiclient.Client) error {
+		v, err := c.Exploit(ctx, "2007030137")
+		if err != nil {
+			return err
+		}
+		return selftestExpect(v.ID, "WLB-2007030137")
+	}},
+	{"GET /api/v1/cve/{id}", func(ctx context.Context, c *apiclient.Client) error {
+		d, err := c.CVE(ctx, "cve-2007-1411")
+		if err != nil {
+			return err
+		}
+		return selftestExpect(d.CveID, "CVE-2007-1411")
+	}},
+	{"GET /api/v1/author/{id}", func(ctx context.Context, c *apiclient.Client) error {
+		a, err := c.Author(ctx, "rgod")
+		if err != nil {
+			return err
+		}
+		if a.Name == "" {
+			return errors.New(T("输出为空"))
+		}
+		return nil
+	}},
+	{"GET /api/v1/search", func(ctx context.Context, c *apiclient.Client) error {
+		r, err := c.Search(ctx, crawler.SearchOptions{Keyword: "php"})
+		if err != nil {
+			return err
+		}
+		return selftestExpect(len(r.Vulnerabilities), 10)
+	}},
+}
+
+// runSelftestAPI 在进程内启动与 api 命令相同的路由，依次请求各个接口
+func runSelftestAPI(baseURL string) []selftestResult {
+	oldToken := apiToken
+	apiToken = generateRandomToken()
+	defer func() { apiToken = oldToken }()
+
+	c := crawler.NewCrawler(crawler.WithClientOptions(crawler.WithBaseURL(baseURL), crawler.WithRetry(0, 0)))
+	r := mux.NewRouter()
+	registerAPIRoutes(r, apiRoutes(c, newAuthorFeedCache(c, 0), newAPIAssetProxy()), time.Time{})
+	server := httptest.NewServer(r)
+	defer server.Close()
+	client := apiclient.NewClient(server.URL, apiToken, apiclient.WithRetry(0, 0))
+
+	results := make([]selftestResult, 0, len(selftestAPICases))
+	for _, tc := range selftestAPICases {
+		start := time.Now()
+		err := tc.run(context.Background(), client)
+		results = append(results, selftestResult{name: tc.name, duration: time.Since(start), err: err})
+	}
+	return results
+}
+
+// printSelftestResults 以表格形式输出检查结果
+func printSelftestResults(results []selftestResult) {
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(tableStyle(table.StyleRounded))
+
+	t.AppendHeader(table.Row{T("检查"), T("结果"), T("耗时"), T("说明")})
+	for _, r := range results {
+		status, detail := text.Colors{text.FgHiGreen}.Sprint(T("通过")), ""
+		if r.err != nil {
+			status, detail = text.Colors{text.FgRed, text.Bold}.Sprint(T("失败")), truncateCell(r.err.Error(), 80)
+		}
+		t.AppendRow(table.Row{r.name, status, r.duration.Round(time.Millisecond), detail})
+	}
+	t.Render()
+}
+
+var selftestCmd = &cobra.Command{
+	Use:   "selftest",
+	Short: T("使用回放的归档页面端到端测试命令行和API"),
+	Long: T(`启动回放 docs/response-examples 中归档页面的本地服务，将爬虫指向该服务，
+以子进程运行 list、exploit、cve、author、search 命令并检查输出和保存的文件，
+再在进程内启动与 api 命令相同的路由，通过 pkg/apiclient 请求各个接口。
+整个过程不会访问cxsecurity，也不会修改配置目录，任一检查失败时以非零状态码退出。`),
+	Example: `  cxcrawler selftest
+  cxcrawler selftest -d /path/to/response-examples`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		server, err := fixture.NewServer(selftestDir)
+		if err != nil {
+			return err
+		}
+		defer server.Close()
+		dir, err := os.MkdirTemp("", "cxcrawler-selftest-")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(dir)
+
+		infof(T("回放服务: %s\n"), server.URL)
+		results := append(runSelftestCLI(server.URL, dir), runSelftestAPI(server.URL)...)
+		if !quietOutput {
+			printSelftestResults(results)
+		}
+
+		failed := 0
+		for _, r := range results {
+			if r.err != nil {
+				failed++
+			}
+		}
+		if failed > 0 {
+			fmt.Fprintf(os.Stderr, "%s %s\n", text.Colors{text.FgRed, text.Bold}.Sprint(T("❌ 自检失败:")), fmt.Sprintf(T("%d 项检查失败"), failed))
+			os.RemoveAll(dir)
+			server.Close()
+			os.Exit(1)
+		}
+		infof("%s\n", text.Colors{text.FgHiGreen}.Sprintf(T("✅ 全部 %d 项检查通过"), len(results)))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(selftestCmd)
+
+	selftestCmd.Flags().StringVarP(&selftestDir, "dir", "d", "docs/response-examples", T("归档页面样本所在目录"))
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/fixture"
+)
+
+// TestMain 在设置了 CXCRAWLER_TEST_MAIN 时把测试二进制当作 cxcrawler 运行，
+// selftest 的命令行检查通过它启动子进程
+func TestMain(m *testing.M) {
+	if os.Getenv("CXCRAWLER_TEST_MAIN") == "1" {
+		Execute()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// useTestBinary 让 selftest 以当前的测试二进制作为 cxcrawler 启动子进程
+func useTestBinary(t *testing.T) {
+	oldCommand := selftestCommand
+	selftestCommand = func(args ...string) (*exec.Cmd, error) {
+		cmd := exec.Command(os.Args[0], args...)
+		cmd.Env = append(os.Environ(), "CXCRAWLER_TEST_MAIN=1")
+		return cmd, nil
+	}
+	t.Cleanup(func() { selftestCommand = oldCommand })
+}
+
+func TestSelftest(t *testing.T) {
+	server, err := fixture.NewServer("../docs/response-examples")
+	require.NoError(t, err)
+	defer server.Close()
+
+	useTestBinary(t)
+
+	results := append(runSelftestCLI(server.URL, t.TempDir()), runSelftestAPI(server.URL)...)
+	assert.Len(t, results, len(selftestCLICases)+len(selftestAPICases))
+	for _, r := range results {
+		assert.NoError(t, r.err, r.name)
+	}
+}
+
+func TestSelftestCLICaseMismatch(t *testing.T) {
+	server, err := fixture.NewServer("../docs/response-examples")
+	require.NoError(t, err)
+	defer server.Close()
+
+	useTestBinary(t)
+
+	tc := selftestCLICases[0]
+	tc.expect = "WLB-0000000000"
+	assert.Error(t, runSelftestCLICase(server.URL, t.TempDir(), tc))
+}
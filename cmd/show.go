@@ -0,0 +1,311 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/formatters"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/mattn/go-runewidth"
+	"github.com/spf13/cobra"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/logging"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+var (
+	showOutputFile string
+	showStore      string
+)
+
+var showCmd = &cobra.Command{
+	Use:   "show <WLB-ID>",
+	Short: T("在终端中显示完整的漏洞公告"),
+	Long: T(`获取漏洞详情页并在终端中显示完整的公告：元数据按类型着色，
+正文按终端宽度自动换行，PoC等代码块语法高亮显示。`),
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id := strings.TrimSpace(args[0])
+		if id == "" {
+			return fmt.Errorf(T("无效的漏洞ID: %q"), args[0])
+		}
+
+		c := newCrawler()
+		result, err := c.CrawlExploit(id, showOutputFile, "")
+		if err != nil {
+			logging.Printf(T("爬取失败: %v\n"), err)
+			return nil
+		}
+		vuln, ok := result.(*model.Vulnerability)
+		if !ok {
+			return fmt.Errorf(T("无效的漏洞ID: %q"), args[0])
+		}
+
+		attachAISummary(vuln, showStore)
+
+		if !printFormatted(vuln) && !quietOutput {
+			width := terminalWidth(80)
+			renderAdvisory(os.Stdout, vuln, width)
+		}
+		handleOpenAndCopy(vuln)
+		return nil
+	},
+}
+
+// advisoryBlock 是公告正文中的一段，代码段不换行并进行语法高亮
+type advisoryBlock struct {
+	Code bool
+	Text string
+}
+
+// codeLinePrefixes 是代码行常见的开头
+var codeLinePrefixes = []string{
+	"//", "/*", "*/", "#!", "#include", "#define", "<?", "?>", "$", "<script", "</", "import ", "from ", "def ", "function ", "use ", "my ", "var ", "const ",
+}
+
+// codeLineSuffixes 是代码行常见的结尾，`".` 和 `'.` 是PHP的字符串拼接
+var codeLineSuffixes = []string{";", "{", "}", "(", "\".", "'.", "\\"}
+
+// isCodeLine 粗略判断一行文本是否像代码
+func isCodeLine(line string) bool {
+	if strings.HasPrefix(line, "\t") || strings.HasPrefix(line, "    ") {
+		return true
+	}
+	trimmed := strings.TrimSpace(line)
+	for _, p := range codeLinePrefixes {
+		if strings.HasPrefix(trimmed, p) {
+			return true
+		}
+	}
+	// 去掉行尾注释后再判断结尾
+	if i := strings.Index(trimmed, " //"); i > 0 {
+		trimmed = strings.TrimSpace(trimmed[:i])
+	}
+	for _, s := range codeLineSuffixes {
+		if strings.HasSuffix(trimmed, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitAdvisoryBlocks 将公告正文按空行分段，超过一半的行像代码的段落视为代码段
+// 相邻的代码段合并为一段(保留中间的空行)，便于语法高亮识别语言
+func splitAdvisoryBlocks(content string) []advisoryBlock {
+	var blocks []advisoryBlock
+	for _, para := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n\n") {
+		if strings.TrimSpace(para) == "" {
+			continue
+		}
+
+		lines, codeLines := 0, 0
+		for _, line := range strings.Split(para, "\n") {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			lines++
+			if isCodeLine(line) {
+				codeLines++
+			}
+		}
+		code := codeLines*2 > lines
+
+		if n := len(blocks); n > 0 && code && blocks[n-1].Code {
+			blocks[n-1].Text += "\n\n" + para
+			continue
+		}
+		blocks = append(blocks, advisoryBlock{Code: code, Text: para})
+	}
+	return blocks
+}
+
+// highlightCode 对代码进行语法高亮，根据内容自动识别语言，无法识别时原样返回
+func highlightCode(code string) string {
+	lexer := lexers.Analyse(code)
+	if lexer == nil {
+		return code
+	}
+	iterator, err := chroma.Coalesce(lexer).Tokenise(nil, code)
+	if err != nil {
+		return code
+	}
+
+	var sb strings.Builder
+	if err := formatters.Get("terminal256").Format(&sb, styles.Get("monokai"), iterator); err != nil {
+		return code
+	}
+	return sb.String()
+}
+
+// wrapWords 按显示宽度在单词边界处自动换行，保留原有的换行
+// 中文等宽字符之间没有空格，可以在任意字符处换行；
+// 超过宽度的英文单词(例如长URL)单独占一行而不拆开，便于复制
+func wrapWords(s string, width int) string {
+	var sb strings.Builder
+	for i, line := range strings.Split(s, "\n") {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+		lineWidth := 0
+		for _, word := range strings.Fields(line) {
+			if lineWidth > 0 {
+				if lineWidth+1+runewidth.StringWidth(word) <= width {
+					sb.WriteByte(' ')
+					lineWidth++
+				} else {
+					sb.WriteByte('\n')
+					lineWidth = 0
+				}
+			}
+			if runewidth.StringWidth(word) <= width-lineWidth || len(word) == utf8.RuneCountInString(word) {
+				sb.WriteString(word)
+				lineWidth += runewidth.StringWidth(word)
+				continue
+			}
+			for _, r := range word {
+				rw := runewidth.RuneWidth(r)
+				if lineWidth+rw > width {
+					sb.WriteByte('\n')
+					lineWidth = 0
+				}
+				sb.WriteRune(r)
+				lineWidth += rw
+			}
+		}
+	}
+	return sb.String()
+}
+
+// riskColors 返回风险级别对应的颜色
+func riskColors(level string) text.Colors {
+	switch strings.ToLower(level) {
+	case "high":
+		return text.Colors{text.FgRed, text.Bold}
+	case "med.", "medium":
+		return text.Colors{text.FgYellow, text.Bold}
+	case "low":
+		return text.Colors{text.FgGreen, text.Bold}
+	}
+	return text.Colors{}
+}
+
+// renderAISummary 渲染AI生成的摘要，标题中标明生成的模型和内容未经核对
+func renderAISummary(w io.Writer, summary *model.AISummary, width int) {
+	fmt.Fprintf(w, "%s %s\n",
+		text.Colors{text.FgHiMagenta, text.Bold}.Sprint(T("AI摘要")),
+		text.Colors{text.FgHiBlack}.Sprintf(T("(由 %s 生成，未经人工核对)"), summary.Model))
+	fmt.Fprintln(w, wrapWords(summary.Summary, width))
+	if len(summary.AffectedVersions) > 0 {
+		fmt.Fprintf(w, "%s  %s\n", text.Colors{text.Bold}.Sprint(T("受影响版本")), text.Colors{text.FgHiYellow}.Sprint(strings.Join(summary.AffectedVersions, ", ")))
+	}
+}
+
+// attachAISummary 从存储中读取 summarize 生成的AI摘要附加到刚获取的漏洞上，存储不存在或没有摘要时不做处理
+func attachAISummary(v *model.Vulnerability, path string) {
+	s, err := openStore(path)
+	if err != nil {
+		return
+	}
+	if stored, ok := s.Get(v.ID); ok && stored.AISummary != nil {
+		v.AISummary = stored.AISummary
+	}
+}
+
+// riskPrefix 返回表格中风险级别前的标记，没有标签、显示的是推断的级别时为 "~"
+func riskPrefix(label string, inferred bool) string {
+	if label == "" && inferred {
+		return "~"
+	}
+	return ""
+}
+
+// renderAdvisory 在终端中渲染完整的漏洞公告
+// 标题和正文按width自动换行；代码段保留原有格式，不换行，加上左侧竖线并语法高亮
+func renderAdvisory(w io.Writer, v *model.Vulnerability, width int) {
+	if width < 20 {
+		width = 20
+	}
+	rule := text.Colors{text.FgHiBlack}.Sprint(boxText(strings.Repeat("─", width)))
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, text.Colors{text.FgHiWhite, text.Bold}.Sprint(wrapWords(v.Title, width)))
+	fmt.Fprintln(w, rule)
+
+	// 元数据，值为空的字段不显示
+	labels := []string{T("漏洞ID"), T("日期"), T("风险级别"), "CVE", "CWE", T("位置"), T("其他标签"), T("作者"), T("详情链接"), "ATT&CK", T("受影响版本"), T("平台"), T("原始出处")}
+	labelWidth := 0
+	for _, l := range labels {
+		labelWidth = max(labelWidth, stringDisplayWidth(l))
+	}
+	field := func(label, value string, colors text.Colors) {
+		if value == "" {
+			return
+		}
+		padding := strings.Repeat(" ", labelWidth-stringDisplayWidth(label))
+		fmt.Fprintf(w, "%s%s  %s\n", text.Colors{text.Bold}.Sprint(label), padding, colors.Sprint(value))
+	}
+
+	date := formatDate(v.Date)
+	location := ""
+	switch {
+	case v.IsRemote && v.IsLocal:
+		location = T("远程/本地")
+	case v.IsRemote:
+		location = T("远程")
+	case v.IsLocal:
+		location = T("本地")
+	}
+
+	field(labels[0], v.ID, text.Colors{text.FgHiCyan, text.Bold})
+	field(labels[1], date, text.Colors{text.FgHiWhite})
+	field(labels[2], v.RiskLevel, riskColors(v.RiskLevel))
+	field(labels[3], v.CVE, text.Colors{text.FgHiYellow})
+	field(labels[4], v.CWE, text.Colors{text.FgHiYellow})
+	field(labels[9], strings.Join(v.Techniques, ", "), text.Colors{text.FgHiRed})
+	field(labels[5], location, text.Colors{text.FgHiGreen})
+	field(labels[10], strings.Join(v.AffectedVersions, ", "), text.Colors{text.FgHiYellow})
+	field(labels[11], v.Platform, text.Colors{text.FgHiGreen})
+	field(labels[6], strings.Join(v.Tags, ", "), text.Colors{text.FgCyan})
+	field(labels[7], v.Author, text.Colors{text.FgHiMagenta})
+	field(labels[8], v.URL, text.Colors{text.FgBlue, text.Underline})
+	if v.Source != nil {
+		field(labels[12], v.Source.Name+" "+v.Source.URL, text.Colors{text.FgBlue})
+	}
+
+	fmt.Fprintln(w, rule)
+	if v.AISummary != nil {
+		renderAISummary(w, v.AISummary, width)
+		fmt.Fprintln(w, rule)
+	}
+	if strings.TrimSpace(v.Content) == "" {
+		return
+	}
+
+	gutter := text.Colors{text.FgHiBlack}.Sprint(boxText("│ "))
+	for i, block := range splitAdvisoryBlocks(v.Content) {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		if !block.Code {
+			fmt.Fprintln(w, wrapWords(block.Text, width))
+			continue
+		}
+		for _, line := range strings.Split(strings.TrimRight(highlightCode(block.Text), "\n"), "\n") {
+			fmt.Fprintln(w, gutter+line)
+		}
+	}
+	fmt.Fprintln(w, rule)
+}
+
+func init() {
+	rootCmd.AddCommand(showCmd)
+
+	showCmd.Flags().StringVar(&showStore, "store", "", T("读取AI摘要的存储文件，默认为配置目录下的 vulnerabilities.json"))
+	showCmd.Flags().StringVarP(&showOutputFile, "output", "o", "", T("同时将结果保存为JSON文件"))
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/mattn/go-runewidth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+func TestSplitAdvisoryBlocks(t *testing.T) {
+	content := "A remote attacker can inject SQL through the id parameter.\n\n" +
+		"<?php\n$id = $_GET['id'];\nmysql_query(\"SELECT * FROM users WHERE id=$id\");\n\n" +
+		"if ($x) {\n    echo $x;\n}\n\n" +
+		"Fixed in version 1.2."
+
+	blocks := splitAdvisoryBlocks(content)
+	require.Len(t, blocks, 3)
+	assert.False(t, blocks[0].Code)
+	// 相邻的代码段合并
+	assert.True(t, blocks[1].Code)
+	assert.True(t, strings.HasPrefix(blocks[1].Text, "<?php"))
+	assert.True(t, strings.HasSuffix(blocks[1].Text, "}"))
+	assert.False(t, blocks[2].Code)
+	assert.Equal(t, "Fixed in version 1.2.", blocks[2].Text)
+}
+
+func TestWrapWords(t *testing.T) {
+	s := "The vulnerability allows remote attackers to execute arbitrary code 通过构造的请求执行任意代码 via crafted requests."
+	for _, line := range strings.Split(wrapWords(s, 20), "\n") {
+		assert.LessOrEqual(t, runewidth.StringWidth(line), 20, line)
+		assert.Equal(t, strings.TrimSpace(line), line)
+	}
+
+	// 超长的单词不拆开
+	url := "http://example.com/a/very/long/path/to/an/advisory.html"
+	assert.Equal(t, "see\n"+url, wrapWords("see "+url, 20))
+	// 原有的换行保留
+	assert.Equal(t, "a\nb", wrapWords("a\nb", 20))
+}
+
+func TestRenderAdvisory(t *testing.T) {
+	v := &model.Vulnerability{
+		ID:        "WLB-2024040035",
+		Title:     "Example CMS 1.0 SQL Injection",
+		RiskLevel: "High",
+		CVE:       "CVE-2024-1234",
+		IsRemote:  true,
+		Author:    "researcher",
+		URL:       "https://cxsecurity.com/issue/WLB-2024040035",
+		Content: "The id parameter of index.php is not sanitized before it is used in an SQL query, " +
+			"which allows remote attackers to read arbitrary data.\n\n" +
+			"<?php\n$id = $_GET['id'];\nmysql_query(\"SELECT * FROM users WHERE id=$id\");\n?>",
+	}
+
+	var buf bytes.Buffer
+	renderAdvisory(&buf, v, 40)
+	out := text.StripEscape(buf.String())
+
+	assert.Contains(t, out, "WLB-2024040035")
+	assert.Contains(t, out, "CVE-2024-1234")
+	assert.Contains(t, out, "https://cxsecurity.com/issue/WLB-2024040035")
+	// 代码段原样保留并加上左侧竖线
+	assert.Contains(t, out, "│ $id = $_GET['id'];\n")
+	assert.Contains(t, out, "│ mysql_query(\"SELECT * FROM users WHERE id=$id\");\n")
+
+	// 正文按宽度换行
+	assert.Contains(t, out, "The id parameter of index.php is not\n")
+	for _, line := range strings.Split(out, "\n") {
+		if strings.HasPrefix(line, "│ ") || strings.Contains(line, "https://") {
+			continue
+		}
+		assert.LessOrEqual(t, runewidth.StringWidth(line), 40, line)
+	}
+}
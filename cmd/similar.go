@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+var (
+	similarThreshold  float64
+	similarOutputFile string
+	similarCollapse   bool
+)
+
+var similarCmd = &cobra.Command{
+	Use:   "similar <file.json...>",
+	Short: T("找出保存的结果中近似重复的公告"),
+	Long: T(`读取之前保存的JSON结果文件(exploit、list、search、author等命令的输出)，
+按标题相似度将同一漏洞被多次发布的公告分组，并显示包含多条记录的分组。
+使用 -o 保存带有 cluster_id 字段的结果，--collapse 时每组只保留一条。`),
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if similarThreshold <= 0 || similarThreshold > 1 {
+			return fmt.Errorf(T("无效的相似度阈值 %v，范围应为 (0, 1]"), similarThreshold)
+		}
+
+		var items []model.Vulnerability
+		for _, path := range args {
+			loaded, err := crawler.LoadResultFile(path)
+			if err != nil {
+				return err
+			}
+			items = append(items, loaded...)
+		}
+
+		clusters := crawler.AnnotateClusters(items, similarThreshold)
+		result := &model.VulnerabilityList{Items: items}
+		if similarCollapse {
+			result.Items = crawler.CollapseClusters(items)
+		}
+
+		if similarOutputFile != "" {
+			if err := newCrawler().SaveJSON(result, similarOutputFile); err != nil {
+				return fmt.Errorf(T("保存结果失败: %v"), err)
+			}
+		}
+
+		if printFormatted(result) || quietOutput {
+			return nil
+		}
+		printSimilarGroups(items, clusters)
+		if similarOutputFile != "" {
+			fmt.Printf("\n%s %s\n",
+				text.Colors{text.FgHiGreen}.Sprint(T("✅ 已保存:")),
+				text.Colors{text.FgHiCyan, text.Underline}.Sprint(similarOutputFile))
+		}
+		return nil
+	},
+}
+
+// groupByCluster 按ClusterID将漏洞分组，分组按首次出现的顺序排列，没有ClusterID的漏洞不在结果中
+func groupByCluster(items []model.Vulnerability) [][]model.Vulnerability {
+	index := make(map[string]int)
+	var groups [][]model.Vulnerability
+	for _, item := range items {
+		if item.ClusterID == "" {
+			continue
+		}
+		i, ok := index[item.ClusterID]
+		if !ok {
+			i = len(groups)
+			index[item.ClusterID] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], item)
+	}
+	return groups
+}
+
+// printSimilarGroups 以表格显示近似重复的分组
+func printSimilarGroups(items []model.Vulnerability, clusters int) {
+	fmt.Printf("\n%s %s\n",
+		text.Colors{text.Bold, text.FgHiGreen}.Sprint(T("🧬 近似重复:")),
+		text.Colors{text.Bold, text.FgHiWhite}.Sprintf(T("%d 条记录中有 %d 组"), len(items), clusters))
+	if clusters == 0 {
+		return
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(tableStyle(table.StyleRounded))
+	t.AppendHeader(table.Row{T("分组"), "ID", T("日期"), T("标题")})
+	for _, group := range groupByCluster(items) {
+		for _, item := range group {
+			date := formatDate(item.Date)
+			t.AppendRow(table.Row{
+				text.Colors{text.FgHiYellow}.Sprint(item.ClusterID),
+				text.Colors{text.FgHiCyan}.Sprint(item.ID),
+				date,
+				truncateCell(item.Title, 60),
+			})
+		}
+		t.AppendSeparator()
+	}
+	t.Render()
+}
+
+func init() {
+	rootCmd.AddCommand(similarCmd)
+
+	similarCmd.Flags().Float64VarP(&similarThreshold, "threshold", "t", crawler.DefaultSimilarityThreshold, T("标题相似度阈值(0到1)，越大越严格"))
+	similarCmd.Flags().StringVarP(&similarOutputFile, "output", "o", "", T("将带有分组ID的结果保存为JSON文件"))
+	similarCmd.Flags().BoolVar(&similarCollapse, "collapse", false, T("保存和输出时每组只保留第一条记录"))
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/query"
+)
+
+var (
+	sqlData   []string
+	sqlFormat string
+	sqlSchema bool
+)
+
+var sqlCmd = &cobra.Command{
+	Use:   `sql "SELECT ..."`,
+	Short: T("对保存的结果执行只读SQL查询"),
+	Long: T(`将之前保存的JSON结果文件(exploit、list、search、author等命令的输出)作为 vulnerabilities 表，
+由嵌入的SQLite执行只读的SELECT查询，支持SQLite的全部查询语法和内置函数。
+使用 --schema 查看表中的列。`),
+	Example: `  cxsecurity sql -d 'data/*.json' "SELECT risk_level, COUNT(*) AS n FROM vulnerabilities GROUP BY risk_level ORDER BY n DESC"
+  cxsecurity sql -d exploits.json -f csv "SELECT id, date, title FROM vulnerabilities WHERE title LIKE '%wordpress%'"`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if sqlSchema {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if sqlSchema {
+			printSQLSchema()
+			return nil
+		}
+		if sqlFormat != "table" && sqlFormat != "json" && sqlFormat != "csv" {
+			return fmt.Errorf(T("不支持的输出格式 %q，可选值：table、json、csv"), sqlFormat)
+		}
+		if len(sqlData) == 0 {
+			return errors.New(T("请使用 -d 指定要查询的结果文件"))
+		}
+
+		var items []model.Vulnerability
+		for _, pattern := range sqlData {
+			paths, err := filepath.Glob(pattern)
+			if err != nil {
+				return fmt.Errorf(T("无效的文件模式 %s: %v"), pattern, err)
+			}
+			if len(paths) == 0 {
+				return fmt.Errorf(T("没有匹配 %s 的文件"), pattern)
+			}
+			for _, path := range paths {
+				loaded, err := crawler.LoadResultFile(path)
+				if err != nil {
+					return err
+				}
+				items = append(items, loaded...)
+			}
+		}
+
+		result, err := query.Run(args[0], items)
+		if err != nil {
+			return fmt.Errorf(T("查询失败: %v"), err)
+		}
+
+		if printFormatted(result.Records()) {
+			return nil
+		}
+		switch sqlFormat {
+		case "json":
+			data, err := json.MarshalIndent(result.Records(), "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+		case "csv":
+			w := csv.NewWriter(os.Stdout)
+			if err := w.Write(result.Columns); err != nil {
+				return err
+			}
+			for _, row := range result.Rows {
+				record := make([]string, len(row))
+				for i, v := range row {
+					if v != nil {
+						record[i] = fmt.Sprint(v)
+					}
+				}
+				if err := w.Write(record); err != nil {
+					return err
+				}
+			}
+			w.Flush()
+			return w.Error()
+		default:
+			if !quietOutput {
+				printSQLResult(result)
+			}
+		}
+		return nil
+	},
+}
+
+// printSQLResult 以表格形式输出查询结果，NULL显示为灰色
+func printSQLResult(result *query.Result) {
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(tableStyle(table.StyleRounded))
+
+	header := make(table.Row, len(result.Columns))
+	for i, col := range result.Columns {
+		header[i] = col
+	}
+	t.AppendHeader(header)
+	for _, row := range result.Rows {
+		cells := make(table.Row, len(row))
+		for i, v := range row {
+			if v == nil {
+				cells[i] = text.Colors{text.FgHiBlack}.Sprint("NULL")
+				continue
+			}
+			cells[i] = truncateCell(fmt.Sprint(v), 80)
+		}
+		t.AppendRow(cells)
+	}
+	t.Render()
+	fmt.Println(text.Colors{text.FgHiBlack}.Sprintf(T("(%d 行)"), len(result.Rows)))
+}
+
+// printSQLSchema 输出vulnerabilities表的列
+func printSQLSchema() {
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(tableStyle(table.StyleRounded))
+	t.SetTitle(query.Table)
+	t.AppendHeader(table.Row{T("列"), T("类型"), T("说明")})
+	for _, col := range query.Columns {
+		t.AppendRow(table.Row{text.Colors{text.FgHiCyan}.Sprint(col.Name), col.Type, T(col.Description)})
+	}
+	t.Render()
+}
+
+func init() {
+	rootCmd.AddCommand(sqlCmd)
+
+	sqlCmd.Flags().StringSliceVarP(&sqlData, "data", "d", nil, T("要查询的结果文件，支持通配符，可以指定多次"))
+	sqlCmd.Flags().StringVarP(&sqlFormat, "format", "f", "table", T("输出格式(table、json或csv)"))
+	sqlCmd.Flags().BoolVar(&sqlSchema, "schema", false, T("显示表中的列"))
+}
@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/config"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/store"
+)
+
+var (
+	runStatsDisabled bool
+	statsRunsFile    string
+	statsRunsLimit   int
+	statsRunsCommand string
+
+	// runRecorder 统计本次运行的HTTP请求，由PersistentPreRunE创建，--no-run-stats 时为nil
+	runRecorder *store.RunRecorder
+)
+
+// startRunStats 开始统计本次运行的HTTP请求
+func startRunStats(cmd *cobra.Command) {
+	runRecorder = nil
+	if runStatsDisabled {
+		return
+	}
+	command := strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+	runRecorder = store.NewRunRecorder(command, time.Now())
+}
+
+// recordRequest 将请求计入本次运行的统计，未开始统计时不做任何处理
+func recordRequest(l crawler.RequestLog) {
+	if runRecorder != nil {
+		runRecorder.Observe(l)
+	}
+}
+
+// saveRunStats 将本次运行的统计追加到配置目录下的 runs.json，没有发出请求的运行不记录
+// 保存失败只输出警告，不影响命令的结果
+func saveRunStats(success bool) {
+	if runRecorder == nil || runRecorder.Requests() == 0 {
+		return
+	}
+	run := runRecorder.Finish(time.Now(), success)
+	runRecorder = nil
+
+	l, err := openRunLog("")
+	if err == nil {
+		l.Append(run)
+		err = l.Save()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, T("保存运行统计失败: %v")+"\n", err)
+	}
+}
+
+// exitWithRunStats 保存本次运行的统计后以指定的退出码退出，用于绕过 Execute 直接退出的命令
+// success 表示命令本身是否成功，例如 --fail-on-new 发现新漏洞时退出码为2但运行是成功的
+func exitWithRunStats(code int, success bool) {
+	saveRunStats(success)
+	os.Exit(code)
+}
+
+// openRunLog 打开运行记录，path为空时为配置目录下的 runs.json
+func openRunLog(path string) (*store.RunLog, error) {
+	if path == "" {
+		var err error
+		if path, err = config.Path(store.DefaultRunsFile); err != nil {
+			return nil, err
+		}
+	}
+	return store.OpenRunLog(path, store.WithPermissions(outputFileMode, outputDirMode))
+}
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: T("查看请求统计"),
+	Long: T(`查看之前的运行发出的HTTP请求，用于容量规划和检查爬取是否对网站足够友好。
+每次发出请求的命令结束后，请求数、流量、缓存命中和错误原因会追加到配置目录下的 runs.json 中，
+使用全局选项 --no-run-stats 可以不记录。`),
+}
+
+var statsRunsCmd = &cobra.Command{
+	Use:   "runs",
+	Short: T("列出最近运行的请求统计"),
+	Example: `  cxcrawler stats runs
+  cxcrawler stats runs --command "saved run" --limit 50
+  cxcrawler stats runs --jq '[.[] | .requests] | add'`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		l, err := openRunLog(statsRunsFile)
+		if err != nil {
+			return err
+		}
+		runs := filterRuns(l.Runs(), statsRunsCommand, statsRunsLimit)
+
+		if printFormatted(runs) || quietOutput {
+			return nil
+		}
+		if len(runs) == 0 {
+			fmt.Printf(T("运行记录 %s 中没有记录")+"\n", l.Path())
+			return nil
+		}
+		printRunStats(runs)
+		return nil
+	},
+}
+
+// filterRuns 返回指定命令最近的limit次运行，command为空时不过滤，limit小于等于0时不限制
+func filterRuns(runs []store.Run, command string, limit int) []store.Run {
+	filtered := make([]store.Run, 0, len(runs))
+	for _, run := range runs {
+		if command != "" && run.Command != command {
+			continue
+		}
+		filtered = append(filtered, run)
+		if limit > 0 && len(filtered) == limit {
+			break
+		}
+	}
+	return filtered
+}
+
+// formatBytes 以易读的单位输出字节数，例如 1.5 MiB
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// formatRunErrors 按次数从多到少列出错误原因，例如 "timeout×3, http_503×1"
+func formatRunErrors(errs map[string]int) string {
+	reasons := make([]string, 0, len(errs))
+	for reason := range errs {
+		reasons = append(reasons, reason)
+	}
+	sort.Slice(reasons, func(i, j int) bool {
+		if errs[reasons[i]] != errs[reasons[j]] {
+			return errs[reasons[i]] > errs[reasons[j]]
+		}
+		return reasons[i] < reasons[j]
+	})
+	parts := make([]string, len(reasons))
+	for i, reason := range reasons {
+		parts[i] = fmt.Sprintf("%s×%d", reason, errs[reason])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// printRunStats 以表格显示运行记录，最后一行为合计
+func printRunStats(runs []store.Run) {
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(tableStyle(table.StyleRounded))
+	// 合计行中的耗时和错误原因区分大小写，不转换为大写
+	t.Style().Format.Footer = text.FormatDefault
+	t.AppendHeader(table.Row{T("开始时间"), T("命令"), T("耗时"), T("请求"), T("重试"), T("失败"), T("流量"), T("缓存命中"), T("错误"), T("结果")})
+	row := func(start, command string, elapsed time.Duration, run store.Run) table.Row {
+		result := text.Colors{text.FgHiGreen}.Sprint("✓")
+		if !run.Success {
+			result = text.Colors{text.FgHiRed}.Sprint("✗")
+		}
+		return table.Row{
+			start,
+			command,
+			elapsed.Round(time.Second),
+			run.Requests,
+			run.Retries,
+			run.Failures,
+			formatBytes(run.Bytes),
+			fmt.Sprintf("%.0f%%", run.CacheHitRate()*100),
+			truncateCell(formatRunErrors(run.Errors), 40),
+			result,
+		}
+	}
+	var elapsed time.Duration
+	for _, run := range runs {
+		t.AppendRow(row(formatTimestamp(run.Started), run.Command, run.Duration(), run))
+		elapsed += run.Duration()
+	}
+	// 合计的耗时为各次运行的耗时之和，不是第一次开始到最后一次结束的时间
+	t.AppendFooter(row(T("合计"), fmt.Sprintf(T("%d 次运行"), len(runs)), elapsed, store.SumRuns(runs)))
+	t.Render()
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+	statsCmd.AddCommand(statsRunsCmd)
+
+	rootCmd.PersistentFlags().BoolVar(&runStatsDisabled, "no-run-stats", false, T("不将本次运行的请求统计记录到配置目录下的 runs.json"))
+	statsRunsCmd.Flags().StringVar(&statsRunsFile, "file", "", T("运行记录文件，默认为配置目录下的 runs.json"))
+	statsRunsCmd.Flags().IntVarP(&statsRunsLimit, "limit", "n", 20, T("最多显示的运行次数，0表示全部"))
+	statsRunsCmd.Flags().StringVar(&statsRunsCommand, "command", "", T("只显示指定命令的运行，例如 search、\"saved run\""))
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/config"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/store"
+)
+
+func TestSaveRunStats(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(config.DirEnv, dir)
+	defer func() { runRecorder, runStatsDisabled = nil, false }()
+
+	// 没有发出请求的运行不记录
+	startRunStats(statsRunsCmd)
+	saveRunStats(true)
+	l, err := openRunLog("")
+	require.NoError(t, err)
+	assert.Empty(t, l.Runs())
+
+	startRunStats(savedRunCmd)
+	recordRequest(crawler.RequestLog{Attempt: 1, StatusCode: 200, Size: 2048})
+	recordRequest(crawler.RequestLog{Attempt: 1, Err: errors.New("connection refused")})
+	saveRunStats(false)
+	assert.Nil(t, runRecorder)
+
+	l, err = openRunLog(filepath.Join(dir, store.DefaultRunsFile))
+	require.NoError(t, err)
+	runs := l.Runs()
+	require.Len(t, runs, 1)
+	assert.Equal(t, "saved run", runs[0].Command)
+	assert.Equal(t, 2, runs[0].Requests)
+	assert.Equal(t, int64(2048), runs[0].Bytes)
+	assert.Equal(t, map[string]int{"network": 1}, runs[0].Errors)
+	assert.False(t, runs[0].Success)
+
+	runStatsDisabled = true
+	startRunStats(savedRunCmd)
+	recordRequest(crawler.RequestLog{Attempt: 1, StatusCode: 200})
+	saveRunStats(true)
+	l, err = openRunLog("")
+	require.NoError(t, err)
+	assert.Len(t, l.Runs(), 1, "--no-run-stats 时不记录")
+}
+
+func TestFilterRuns(t *testing.T) {
+	start := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	runs := []store.Run{
+		{Command: "search", Started: start.Add(3 * time.Hour)},
+		{Command: "saved run", Started: start.Add(2 * time.Hour)},
+		{Command: "search", Started: start.Add(time.Hour)},
+		{Command: "search", Started: start},
+	}
+	assert.Len(t, filterRuns(runs, "", 0), 4)
+	filtered := filterRuns(runs, "search", 2)
+	require.Len(t, filtered, 2)
+	assert.Equal(t, start.Add(time.Hour), filtered[1].Started)
+	assert.Empty(t, filterRuns(runs, "exploit", 0))
+}
+
+func TestFormatRunStats(t *testing.T) {
+	assert.Equal(t, "512 B", formatBytes(512))
+	assert.Equal(t, "1.5 KiB", formatBytes(1536))
+	assert.Equal(t, "3.0 MiB", formatBytes(3<<20))
+
+	assert.Equal(t, "timeout×3, http_503×1, network×1", formatRunErrors(map[string]int{"network": 1, "timeout": 3, "http_503": 1}))
+	assert.Empty(t, formatRunErrors(nil))
+}
@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/llm"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/logging"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/store"
+)
+
+var (
+	summarizeEndpoint string
+	summarizeModel    string
+	summarizeAPIKey   string
+	summarizeIDs      []string
+	summarizeLimit    int
+	summarizeForce    bool
+	summarizeDryRun   bool
+)
+
+// 生成摘要的状态
+const (
+	summaryGenerated = "generated" // 已生成并保存
+	summaryFailed    = "failed"    // 调用模型失败
+	summarySkipped   = "skipped"   // 记录没有正文和描述
+	summaryPending   = "pending"   // 因 --dry-run 或 --limit 本次没有生成
+)
+
+// summaryResult 是为一条漏洞生成摘要的结果
+type summaryResult struct {
+	ID      string `json:"id"`
+	Status  string `json:"status"`
+	Summary string `json:"summary,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+var summarizeCmd = &cobra.Command{
+	Use:   "summarize",
+	Short: T("使用大语言模型为存储中的漏洞生成摘要"),
+	Long: T(`将存储中漏洞的标题、描述和正文发送给OpenAI兼容的模型接口(例如OpenAI或本地的Ollama)，
+生成一段话的摘要并提取受影响的版本，保存在记录的 ai_summary 字段中。
+摘要标明为AI生成(ai_generated: true)并记录使用的模型，不会覆盖从页面解析出的字段。
+默认只处理还没有摘要且有正文或描述的记录，--force 重新生成已有的摘要。
+接口地址、模型和API Key也可以通过环境变量 CXCRAWLER_LLM_ENDPOINT、CXCRAWLER_LLM_MODEL 和 CXCRAWLER_LLM_API_KEY 设置。`),
+	Example: `  cxcrawler summarize --dry-run
+  cxcrawler summarize --model llama3.1 --limit 20
+  cxcrawler summarize --endpoint https://api.openai.com/v1 --model gpt-4o-mini --id WLB-2024040015`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if summarizeEndpoint == "" {
+			summarizeEndpoint = os.Getenv("CXCRAWLER_LLM_ENDPOINT")
+		}
+		if summarizeModel == "" {
+			summarizeModel = os.Getenv("CXCRAWLER_LLM_MODEL")
+		}
+		if summarizeAPIKey == "" {
+			summarizeAPIKey = os.Getenv("CXCRAWLER_LLM_API_KEY")
+		}
+		logging.AddSecret(summarizeAPIKey)
+
+		s, err := openStore(storeFile)
+		if err != nil {
+			return err
+		}
+		summarizer := llm.NewSummarizer(summarizeEndpoint, llm.WithModel(summarizeModel), llm.WithAPIKey(summarizeAPIKey))
+		results := summarizeStored(s, summarizer, summarizeIDs, summarizeLimit, summarizeForce, summarizeDryRun)
+		if !summarizeDryRun {
+			if err := s.Save(); err != nil {
+				return err
+			}
+		}
+		if !printFormatted(results) && !quietOutput {
+			printSummaryResults(results, summarizer.Model())
+		}
+		for _, r := range results {
+			if r.Status == summaryFailed {
+				return errors.New(T("部分漏洞生成摘要失败"))
+			}
+		}
+		return nil
+	},
+}
+
+// summarizeStored 为存储中的漏洞生成摘要并写回存储，调用方负责保存
+// ids不为空时只处理指定的漏洞；force为false时跳过已有摘要的记录；limit大于0时最多调用limit次模型
+func summarizeStored(s *store.Store, summarizer *llm.Summarizer, ids []string, limit int, force, dryRun bool) []summaryResult {
+	items := s.All()
+	if len(ids) > 0 {
+		wanted := make(map[string]bool)
+		for _, id := range ids {
+			wanted[id] = true
+		}
+		selected := items[:0]
+		for _, v := range items {
+			if wanted[v.ID] {
+				selected = append(selected, v)
+			}
+		}
+		items = selected
+	}
+
+	results := []summaryResult{}
+	requests := 0
+	for _, v := range items {
+		if v.AISummary != nil && !force {
+			continue
+		}
+		result := summaryResult{ID: v.ID}
+		if v.ID == "" {
+			result.ID = v.URL
+		}
+		if v.Content == "" && v.Description == "" {
+			result.Status, result.Error = summarySkipped, llm.ErrNoContent.Error()
+			results = append(results, result)
+			continue
+		}
+		if dryRun || (limit > 0 && requests >= limit) {
+			result.Status = summaryPending
+			results = append(results, result)
+			continue
+		}
+
+		requests++
+		infof(T("生成摘要 %s")+"\n", result.ID)
+		summary, err := summarizer.Summarize(v)
+		if err != nil {
+			result.Status, result.Error = summaryFailed, err.Error()
+			results = append(results, result)
+			continue
+		}
+		v.AISummary = summary
+		s.Put(v)
+		result.Status, result.Summary = summaryGenerated, summary.Summary
+		results = append(results, result)
+	}
+	return results
+}
+
+// printSummaryResults 以表格形式输出生成结果，摘要标明为AI生成
+func printSummaryResults(results []summaryResult, model string) {
+	if len(results) == 0 {
+		fmt.Println(text.Colors{text.FgHiBlack}.Sprint(T("没有需要生成摘要的漏洞")))
+		return
+	}
+
+	counts := make(map[string]int)
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(tableStyle(table.StyleRounded))
+	t.SetTitle(fmt.Sprintf(T("AI生成的摘要(模型: %s，未经人工核对)"), model))
+	t.AppendHeader(table.Row{"ID", T("状态"), T("摘要或错误")})
+	for _, r := range results {
+		counts[r.Status]++
+		var status, detail string
+		switch r.Status {
+		case summaryGenerated:
+			status, detail = text.Colors{text.FgHiGreen}.Sprint(T("已生成")), truncateCell(r.Summary, 80)
+		case summaryFailed:
+			status, detail = text.Colors{text.FgHiRed}.Sprint(T("失败")), truncateCell(r.Error, 80)
+		case summarySkipped:
+			status, detail = text.Colors{text.FgHiBlack}.Sprint(T("跳过")), T("没有正文和描述")
+		case summaryPending:
+			status = text.Colors{text.FgHiYellow}.Sprint(T("待生成"))
+		}
+		t.AppendRow(table.Row{r.ID, status, detail})
+	}
+	t.Render()
+	fmt.Printf(T("已生成 %d 条，失败 %d 条，跳过 %d 条，待生成 %d 条")+"\n",
+		counts[summaryGenerated], counts[summaryFailed], counts[summarySkipped], counts[summaryPending])
+}
+
+func init() {
+	rootCmd.AddCommand(summarizeCmd)
+
+	summarizeCmd.Flags().StringVar(&storeFile, "store", "", T("存储文件，默认为配置目录下的 vulnerabilities.json"))
+	summarizeCmd.Flags().StringVar(&summarizeEndpoint, "endpoint", "", T("OpenAI兼容接口的地址，默认为本地Ollama http://localhost:11434/v1"))
+	summarizeCmd.Flags().StringVar(&summarizeModel, "model", "", T("使用的模型，默认为 llama3.1"))
+	summarizeCmd.Flags().StringVar(&summarizeAPIKey, "api-key", "", T("模型接口的API Key，本地Ollama不需要"))
+	summarizeCmd.Flags().StringSliceVar(&summarizeIDs, "id", nil, T("只处理指定ID的漏洞，多个用逗号分隔"))
+	summarizeCmd.Flags().IntVar(&summarizeLimit, "limit", 0, T("本次最多生成的摘要数量，0表示不限制"))
+	summarizeCmd.Flags().BoolVar(&summarizeForce, "force", false, T("重新生成已有的摘要"))
+	summarizeCmd.Flags().BoolVar(&summarizeDryRun, "dry-run", false, T("只列出需要生成摘要的漏洞，不调用模型"))
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/llm"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/store"
+)
+
+func TestSummarizeStored(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"choices": []map[string]interface{}{{"message": map[string]string{"content": `{"summary": "RCE in nginx.", "affected_versions": ["1.2"]}`}}},
+		})
+	}))
+	defer server.Close()
+
+	s, err := store.Open(filepath.Join(t.TempDir(), "vulnerabilities.json"))
+	require.NoError(t, err)
+	s.Put(model.Vulnerability{ID: "WLB-1", Content: "nginx advisory"})
+	s.Put(model.Vulnerability{ID: "WLB-2", Title: "list item only"})
+	s.Put(model.Vulnerability{ID: "WLB-3", Description: "php bug", AISummary: &model.AISummary{AIGenerated: true, Summary: "old"}})
+	s.Put(model.Vulnerability{ID: "WLB-4", Description: "struts bug"})
+	summarizer := llm.NewSummarizer(server.URL, llm.WithModel("test"))
+
+	results := summarizeStored(s, summarizer, nil, 0, false, true)
+	assert.Equal(t, []summaryResult{
+		{ID: "WLB-1", Status: summaryPending},
+		{ID: "WLB-2", Status: summarySkipped, Error: llm.ErrNoContent.Error()},
+		{ID: "WLB-4", Status: summaryPending},
+	}, results)
+	assert.Zero(t, calls, "dry-run 不调用模型")
+
+	results = summarizeStored(s, summarizer, nil, 1, false, false)
+	assert.Equal(t, summaryGenerated, results[0].Status)
+	assert.Equal(t, summaryPending, results[2].Status)
+	assert.Equal(t, 1, calls)
+	v, _ := s.Get("WLB-1")
+	require.NotNil(t, v.AISummary)
+	assert.Equal(t, "RCE in nginx.", v.AISummary.Summary)
+	assert.Equal(t, "test", v.AISummary.Model)
+	assert.Empty(t, v.AffectedVersions, "不覆盖解析出的受影响版本")
+
+	// --force 只重新生成指定的漏洞
+	results = summarizeStored(s, summarizer, []string{"WLB-3"}, 0, true, false)
+	assert.Equal(t, []summaryResult{{ID: "WLB-3", Status: summaryGenerated, Summary: "RCE in nginx."}}, results)
+}
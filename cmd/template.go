@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/logging"
+)
+
+var (
+	outputTemplate    string
+	outputTemplateDir string
+)
+
+// templateFuncs 是输出模板中可用的辅助函数
+var templateFuncs = template.FuncMap{
+	"join":    strings.Join,
+	"upper":   strings.ToUpper,
+	"lower":   strings.ToLower,
+	"trim":    strings.TrimSpace,
+	"replace": strings.ReplaceAll,
+	// csv 将参数格式化为一行CSV(不含换行)，自动处理逗号、引号和换行
+	"csv": func(fields ...interface{}) (string, error) {
+		record := make([]string, len(fields))
+		for i, f := range fields {
+			record[i] = fmt.Sprint(f)
+		}
+		var buf bytes.Buffer
+		w := csv.NewWriter(&buf)
+		if err := w.Write(record); err != nil {
+			return "", err
+		}
+		w.Flush()
+		return strings.TrimSuffix(buf.String(), "\n"), w.Error()
+	},
+	// json 将值编码为JSON
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	// date 按指定格式输出日期，转换到 --tz 指定的时区，零值输出空字符串
+	"date": func(layout string, t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return displayDates.In(t).Format(layout)
+	},
+}
+
+// formattedOutputEnabled 返回是否指定了 --template 或 --jq
+// 使用模板或jq输出时，各命令不再输出提示信息和交互式分页，便于重定向到文件或管道
+func formattedOutputEnabled() bool {
+	return outputTemplate != "" || outputJQ != ""
+}
+
+// loadOutputTemplate 根据 --template 和 --template-dir 加载输出模板
+// --template 的取值按以下顺序解析：
+// 1. 模板目录中的模板名称，例如 "csv" 或 "csv.tmpl"
+// 2. 模板文件路径
+// 3. 模板内容本身，例如 '{{range .Items}}{{.ID}}{{"\n"}}{{end}}'
+//
+// 模板目录中的所有 *.tmpl 文件都会被加载，模板之间可以通过 {{template "name.tmpl" .}} 互相引用
+func loadOutputTemplate() (*template.Template, error) {
+	root := template.New("output").Funcs(templateFuncs)
+
+	if outputTemplateDir != "" {
+		matches, err := filepath.Glob(filepath.Join(outputTemplateDir, "*.tmpl"))
+		if err != nil {
+			return nil, fmt.Errorf(T("读取模板目录失败: %w"), err)
+		}
+		if len(matches) > 0 {
+			if _, err := root.ParseFiles(matches...); err != nil {
+				return nil, fmt.Errorf(T("解析模板目录失败: %w"), err)
+			}
+		}
+
+		for _, name := range []string{outputTemplate, outputTemplate + ".tmpl"} {
+			if t := root.Lookup(name); t != nil {
+				return t, nil
+			}
+		}
+	}
+
+	if info, err := os.Stat(outputTemplate); err == nil && !info.IsDir() {
+		content, err := os.ReadFile(outputTemplate)
+		if err != nil {
+			return nil, fmt.Errorf(T("读取模板文件失败: %w"), err)
+		}
+		return root.Parse(string(content))
+	}
+
+	return root.Parse(outputTemplate)
+}
+
+// validateOutputTemplate 在命令开始执行前加载 --template 指定的模板
+// 模板不存在或有语法错误时返回错误，命令以非零状态退出，不会先爬取再失败
+func validateOutputTemplate() error {
+	if outputTemplate == "" {
+		return nil
+	}
+	if _, err := loadOutputTemplate(); err != nil {
+		return fmt.Errorf(T("模板错误: %w"), err)
+	}
+	return nil
+}
+
+// writeTemplate 使用 --template 指定的模板将结果渲染到w
+func writeTemplate(w io.Writer, result interface{}) error {
+	t, err := loadOutputTemplate()
+	if err != nil {
+		return fmt.Errorf(T("模板错误: %w"), err)
+	}
+	if err := t.Execute(w, result); err != nil {
+		return fmt.Errorf(T("渲染模板失败: %w"), err)
+	}
+	return nil
+}
+
+// exitFormattedError 将 --jq 或 --template 的错误输出到标准错误并以状态1退出
+// 标准输出可能已经写入了部分结果，错误信息不能混入其中
+func exitFormattedError(err error) {
+	fmt.Fprintln(os.Stderr, logging.Redact(err.Error()))
+	exitWithRunStats(1, false)
+}
+
+// printFormatted 在指定了 --jq 或 --template 时按指定格式输出结果
+// 返回true表示已经处理了输出，调用方不再输出默认格式；渲染失败时输出错误到标准错误并以状态1退出
+func printFormatted(result interface{}) bool {
+	if printJQ(result) {
+		return true
+	}
+	if outputTemplate == "" {
+		return false
+	}
+
+	if err := writeTemplate(os.Stdout, result); err != nil {
+		exitFormattedError(err)
+	}
+	return true
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&outputTemplate, "template", "", T("使用Go text/template渲染输出，可以是模板名称、模板文件路径或模板内容"))
+	rootCmd.PersistentFlags().StringVar(&outputTemplateDir, "template-dir", "", T("模板目录，其中的 *.tmpl 文件可以通过 --template <名称> 使用"))
+}
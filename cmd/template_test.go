@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+// renderTemplate 使用指定的 --template/--template-dir 取值渲染data
+func renderTemplate(t *testing.T, tmpl, dir string, data interface{}) string {
+	t.Helper()
+	outputTemplate, outputTemplateDir = tmpl, dir
+	defer func() { outputTemplate, outputTemplateDir = "", "" }()
+
+	tpl, err := loadOutputTemplate()
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, tpl.Execute(&buf, data))
+	return buf.String()
+}
+
+func TestLoadOutputTemplateInline(t *testing.T) {
+	list := &model.VulnerabilityList{Items: []model.Vulnerability{
+		{ID: "WLB-2024040015", Title: "SQL Injection, \"admin\"", Date: time.Date(2024, 4, 9, 0, 0, 0, 0, time.UTC)},
+		{ID: "WLB-2024040016", Title: "XSS"},
+	}}
+
+	out := renderTemplate(t, `{{range .Items}}{{csv .ID .Title (date "2006-01-02" .Date)}}{{"\n"}}{{end}}`, "", list)
+	assert.Equal(t, "WLB-2024040015,\"SQL Injection, \"\"admin\"\"\",2024-04-09\nWLB-2024040016,XSS,\n", out)
+}
+
+func TestLoadOutputTemplateFromDirAndFile(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "row.tmpl"), []byte(`{{.ID}}|{{upper .RiskLevel}}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "list.tmpl"),
+		[]byte(`{{range .Vulnerabilities}}{{template "row.tmpl" .}};{{end}}`), 0644))
+
+	result := &crawler.SearchResult{Vulnerabilities: []crawler.SearchVulnerability{
+		{ID: "WLB-1", RiskLevel: "High"},
+		{ID: "WLB-2", RiskLevel: "Low"},
+	}}
+
+	assert.Equal(t, "WLB-1|HIGH;WLB-2|LOW;", renderTemplate(t, "list", dir, result), "应按名称查找模板目录中的模板")
+	assert.Equal(t, "WLB-1|HIGH;WLB-2|LOW;", renderTemplate(t, "list.tmpl", dir, result))
+	assert.Equal(t, "WLB-1|HIGH", renderTemplate(t, filepath.Join(dir, "row.tmpl"), "", result.Vulnerabilities[0]), "应支持模板文件路径")
+}
+
+func TestBundledTemplates(t *testing.T) {
+	out := renderTemplate(t, "search-csv", "../docs/templates", &crawler.SearchResult{
+		Vulnerabilities: []crawler.SearchVulnerability{{ID: "WLB-1", Date: "2024-04-09", RiskLevel: "High", Title: "a,b", URL: "https://cxsecurity.com/issue/WLB-1"}},
+	})
+	assert.Equal(t, "id,date,risk,title,url\nWLB-1,2024-04-09,High,\"a,b\",https://cxsecurity.com/issue/WLB-1\n", out)
+
+	out = renderTemplate(t, "cve-jira", "../docs/templates", &model.CveDetail{CveID: "CVE-2007-1411", References: []string{"http://example.com"}})
+	assert.Contains(t, out, "h2. CVE-2007-1411")
+	assert.Contains(t, out, "* [http://example.com]")
+}
+
+func TestTemplateErrors(t *testing.T) {
+	defer func() { outputTemplate = "" }()
+
+	outputTemplate = "{{range .Items}"
+	assert.Error(t, validateOutputTemplate(), "模板语法错误应在命令执行前返回错误")
+
+	outputTemplate = "{{.Missing.Field}}"
+	require.NoError(t, validateOutputTemplate())
+	var buf bytes.Buffer
+	err := writeTemplate(&buf, &model.VulnerabilityList{})
+	assert.ErrorContains(t, err, "Missing", "渲染失败应返回错误而不是写入输出")
+
+	outputTemplate = ""
+	assert.NoError(t, validateOutputTemplate())
+}
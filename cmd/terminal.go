@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"golang.org/x/term"
+)
+
+var (
+	// noColor 为true时不输出ANSI颜色
+	noColor bool
+
+	// asciiOutput 为true时只输出ASCII字符：表格和边框使用 +-| 绘制，消息中不显示emoji
+	// 用于不支持Unicode的终端(例如使用旧代码页的Windows控制台)和只接受ASCII的日志系统
+	asciiOutput bool
+)
+
+// setupTerminal 根据 --no-color 和输出环境设置颜色，由PersistentPreRunE调用
+// go-pretty 已经处理了 NO_COLOR 和 FORCE_COLOR，并在Windows控制台中启用ANSI转义序列，
+// 这里额外在输出不是终端(重定向到文件或管道)时关闭颜色，避免结果中混入转义序列
+func setupTerminal() {
+	if noColor || !colorEnabled(os.Getenv, term.IsTerminal(int(os.Stdout.Fd()))) {
+		text.DisableColors()
+	}
+}
+
+// colorEnabled 判断是否输出颜色
+// FORCE_COLOR 不为空且不为0时总是输出颜色；设置了 NO_COLOR 或 TERM=dumb 时不输出颜色；
+// 否则只在标准输出是终端时输出颜色
+func colorEnabled(getenv func(string) string, tty bool) bool {
+	if v := getenv("FORCE_COLOR"); v != "" && v != "0" {
+		return true
+	}
+	if getenv("NO_COLOR") != "" || getenv("TERM") == "dumb" {
+		return false
+	}
+	return tty
+}
+
+// terminalWidth 返回标准输出所在终端的宽度
+// 无法获取时(输出被重定向、在CI或某些Windows终端中运行)依次使用 COLUMNS 环境变量和fallback
+func terminalWidth(fallback int) int {
+	if width, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && width > 0 {
+		return width
+	}
+	if width, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && width > 0 {
+		return width
+	}
+	return fallback
+}
+
+// tableStyle 返回表格样式，--ascii 模式下使用ASCII字符绘制边框
+func tableStyle(style table.Style) table.Style {
+	if asciiOutput {
+		ascii := table.StyleDefault
+		ascii.Title = style.Title
+		ascii.Format = style.Format
+		return ascii
+	}
+	return style
+}
+
+// asciiBoxReplacer 将制表符和常见的Unicode标点替换为ASCII字符
+var asciiBoxReplacer = strings.NewReplacer(
+	"┏", "+", "┓", "+", "┗", "+", "┛", "+", "┣", "+", "┫", "+",
+	"━", "=", "┃", "|", "─", "-", "│", "|",
+	"→", "->", "←", "<-", "…", "...", "•", "*", "·", "-",
+)
+
+// boxText 在 --ascii 模式下将文本中的制表符替换为ASCII字符
+func boxText(s string) string {
+	if !asciiOutput {
+		return s
+	}
+	return asciiBoxReplacer.Replace(s)
+}
+
+// icon 返回消息前的图标和一个空格，--ascii 模式下返回空字符串
+func icon(s string) string {
+	if asciiOutput {
+		return ""
+	}
+	return s + " "
+}
+
+// stripSymbols 去掉文本中的emoji等符号及其后的一个空格，并替换制表符，用于 --ascii 模式
+// 例如 "✅ 已保存:" 变为 "已保存:"
+func stripSymbols(s string) string {
+	s = asciiBoxReplacer.Replace(s)
+	var b strings.Builder
+	skipSpace := false
+	for _, r := range s {
+		if unicode.Is(unicode.So, r) || r == '\uFE0F' || r == '\u200D' {
+			skipSpace = true
+			continue
+		}
+		if skipSpace && r == ' ' {
+			skipSpace = false
+			continue
+		}
+		skipSpace = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// safeFileName 将名称转换为在所有平台上都合法的文件名
+// 替换Windows文件名中不允许的字符和控制字符，去掉结尾的点和空格
+func safeFileName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || strings.ContainsRune(`<>:"/\|?*`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+	name = strings.TrimRight(name, ". ")
+	if name == "" {
+		name = "_"
+	}
+	return name
+}
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, T("不输出颜色，也可以设置 NO_COLOR 环境变量"))
+	rootCmd.PersistentFlags().BoolVar(&asciiOutput, "ascii", false, T("只输出ASCII字符，表格边框使用 +-| 绘制，不显示emoji"))
+}
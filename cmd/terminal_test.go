@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColorEnabled(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(key string) string { return vars[key] }
+	}
+	assert.True(t, colorEnabled(env(nil), true))
+	assert.False(t, colorEnabled(env(nil), false), "输出重定向时不输出颜色")
+	assert.False(t, colorEnabled(env(map[string]string{"NO_COLOR": "1"}), true))
+	assert.False(t, colorEnabled(env(map[string]string{"TERM": "dumb"}), true))
+	assert.True(t, colorEnabled(env(map[string]string{"FORCE_COLOR": "1", "NO_COLOR": "1"}), false))
+	assert.False(t, colorEnabled(env(map[string]string{"FORCE_COLOR": "0"}), false))
+}
+
+func TestTerminalWidth(t *testing.T) {
+	// 测试中标准输出不是终端，依次使用 COLUMNS 和默认宽度
+	t.Setenv("COLUMNS", "100")
+	assert.Equal(t, 100, terminalWidth(80))
+	t.Setenv("COLUMNS", "abc")
+	assert.Equal(t, 80, terminalWidth(80))
+}
+
+func TestASCIIOutput(t *testing.T) {
+	assert.Equal(t, table.StyleRounded, tableStyle(table.StyleRounded))
+	assert.Equal(t, "┏━┓", boxText("┏━┓"))
+	assert.Equal(t, "🔎 ", icon("🔎"))
+
+	asciiOutput = true
+	defer func() { asciiOutput = false }()
+	assert.Equal(t, table.StyleDefault.Box, tableStyle(table.StyleRounded).Box)
+	assert.Equal(t, "+=+", boxText("┏━┓"))
+	assert.Equal(t, "| a |", boxText("┃ a ┃"))
+	assert.Equal(t, "", icon("🔎"))
+	assert.Equal(t, "已保存:", stripSymbols("✅ 已保存:"))
+	assert.Equal(t, "警告: a -> b", stripSymbols("⚠️ 警告: a → b"))
+	assert.Equal(t, "已保存:", T("✅ 已保存:"))
+}
+
+func TestSafeFileName(t *testing.T) {
+	assert.Equal(t, "wordpress", safeFileName("wordpress"))
+	assert.Equal(t, "a_b_c_", safeFileName("a/b:c?"))
+	assert.Equal(t, "name", safeFileName("name. "))
+	assert.Equal(t, "_", safeFileName(".."))
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"github.com/mattn/go-runewidth"
+)
+
+// noTruncate 为true时表格中的长文本不再截断，而是由表格自动换行显示完整内容
+var noTruncate bool
+
+// truncateWidth 按终端显示宽度截断文本，超出部分用省略号代替
+// 按字符而不是字节截断，中文、emoji等宽字符不会被截成乱码
+func truncateWidth(s string, width int) string {
+	if width <= 0 || runewidth.StringWidth(s) <= width {
+		return s
+	}
+	if width <= 3 {
+		// 宽度不足以放下省略号时直接截断
+		return runewidth.Truncate(s, width, "")
+	}
+	return runewidth.Truncate(s, width, "...")
+}
+
+// truncateCell 截断表格单元格中的文本，指定 --no-truncate 时原样返回
+func truncateCell(s string, width int) string {
+	if noTruncate {
+		return s
+	}
+	return truncateWidth(s, width)
+}
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&noTruncate, "no-truncate", false, T("表格中显示完整的标题等长文本，不截断"))
+}
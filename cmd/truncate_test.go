@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTruncateCell(t *testing.T) {
+	defer func(v bool) { noTruncate = v }(noTruncate)
+	noTruncate = false
+
+	// 不超过宽度时原样返回
+	assert.Equal(t, "WordPress SQLi", truncateCell("WordPress SQLi", 20))
+	assert.Equal(t, "WordPress SQL Injection", truncateCell("WordPress SQL Injection", 0))
+
+	assert.Equal(t, "WordPr...", truncateCell("WordPress SQL Injection", 9))
+
+	// 中文和emoji按显示宽度截断，不会截断在字符中间
+	for _, s := range []string{
+		"某某内容管理系统后台SQL注入漏洞",
+		"🔥🔥🔥 Remote Code Execution 🔥🔥🔥",
+		"Ünïcödé ｆｕｌｌｗｉｄｔｈ 标题",
+	} {
+		for width := 1; width < 30; width++ {
+			got := truncateCell(s, width)
+			assert.True(t, utf8.ValidString(got), "%q 截断到 %d", s, width)
+			assert.LessOrEqual(t, runewidth.StringWidth(got), width, "%q 截断到 %d", s, width)
+		}
+	}
+	assert.True(t, strings.HasSuffix(truncateCell("某某内容管理系统后台SQL注入漏洞", 12), "..."))
+
+	// --no-truncate 时不截断
+	noTruncate = true
+	assert.Equal(t, "某某内容管理系统后台SQL注入漏洞", truncateCell("某某内容管理系统后台SQL注入漏洞", 5))
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"errors"
+	"log"
+
+	"github.com/spf13/cobra"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/logging"
+)
+
+var (
+	// quietOutput 由 -q/--quiet 和各命令已废弃的 --silent 共同设置
+	quietOutput  bool
+	verboseLevel int
+)
+
+// validateVerbosity 检查 -q 和 -v 是否同时指定
+func validateVerbosity() error {
+	if quietOutput && verboseLevel > 0 {
+		return errors.New(T("-q/--quiet 和 -v/--verbose 不能同时使用"))
+	}
+	return nil
+}
+
+// humanOutput 判断是否输出面向人的表格、提示和进度信息
+// -q 或者使用 --jq、--template 输出时为false；机器格式的输出和保存的文件不受 -q 影响
+func humanOutput() bool {
+	return !quietOutput && !formattedOutputEnabled()
+}
+
+// infof 输出进度等提示信息，-q 时不输出
+func infof(format string, args ...interface{}) {
+	if !quietOutput {
+		logging.Printf(format, args...)
+	}
+}
+
+// debugf 在 -v 的次数不少于level时向标准错误输出详细日志
+// 日志经过脱敏，不会改变标准输出中的结果
+func debugf(level int, format string, args ...interface{}) {
+	if verboseLevel >= level {
+		log.Printf(format, args...)
+	}
+}
+
+// requestLogger 返回输出HTTP请求详细日志的回调，未指定 -v 时返回nil
+// -v 输出每个请求的URL、状态码和耗时，-vv 额外输出响应大小和失败的原因
+func requestLogger() func(crawler.RequestLog) {
+	if verboseLevel < 1 {
+		return nil
+	}
+	return func(l crawler.RequestLog) {
+		retry := ""
+		if l.Attempt > 1 {
+			retry = T(" (重试)")
+		}
+		debugf(1, "GET %s -> %d %s%s\n", l.URL, l.StatusCode, l.Duration.Round(1e6), retry)
+		debugf(2, T("  响应 %d 字节")+"\n", l.Size)
+		if l.Err != nil {
+			debugf(2, T("  错误: %v")+"\n", l.Err)
+		}
+	}
+}
+
+// deprecateSilent 将命令的 --silent 标志标记为废弃，改用全局的 -q/--quiet
+func deprecateSilent(cmd *cobra.Command) {
+	cmd.Flags().MarkDeprecated("silent", T("请使用 -q/--quiet"))
+}
+
+func init() {
+	rootCmd.PersistentFlags().BoolVarP(&quietOutput, "quiet", "q", false, T("只输出结果，不输出表格、提示和进度信息；--jq、--template 等机器格式的输出不受影响"))
+	rootCmd.PersistentFlags().CountVarP(&verboseLevel, "verbose", "v", T("向标准错误输出详细日志，-v 输出每个HTTP请求，-vv 同时输出响应大小和错误原因"))
+}
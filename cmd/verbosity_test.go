@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/logging"
+)
+
+func TestVerbosity(t *testing.T) {
+	defer func() { quietOutput, verboseLevel, outputJQ = false, 0, "" }()
+
+	assert.True(t, humanOutput())
+	quietOutput = true
+	assert.False(t, humanOutput())
+	assert.NoError(t, validateVerbosity())
+	verboseLevel = 1
+	assert.Error(t, validateVerbosity(), "-q 和 -v 不能同时使用")
+
+	quietOutput, verboseLevel, outputJQ = false, 0, ".id"
+	assert.False(t, humanOutput(), "--jq 输出时不输出表格")
+	assert.Nil(t, requestLogger(), "未指定 -v 时不记录请求")
+
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(logging.NewWriter(os.Stderr))
+	entry := crawler.RequestLog{URL: "https://cxsecurity.com/exploit/", Attempt: 2, StatusCode: http.StatusBadGateway, Size: 12, Duration: 1500 * time.Millisecond, Err: errors.New("服务器错误")}
+
+	verboseLevel = 1
+	requestLogger()(entry)
+	assert.Contains(t, buf.String(), "GET https://cxsecurity.com/exploit/ -> 502 1.5s")
+	assert.NotContains(t, buf.String(), "12")
+
+	buf.Reset()
+	verboseLevel = 2
+	requestLogger()(entry)
+	assert.Contains(t, buf.String(), "12")
+	assert.Contains(t, buf.String(), "服务器错误")
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+)
+
+// 构建时可以通过 -ldflags 设置，例如
+// -ldflags "-X github.com/scagogogo/cxsecurity-crawler/cmd.buildVersion=v1.2.0 -X github.com/scagogogo/cxsecurity-crawler/cmd.buildCommit=abc1234"
+// 未设置时使用Go记录在二进制中的模块版本和VCS信息
+var (
+	buildVersion string
+	buildCommit  string
+)
+
+// buildInfo 是 version 命令输出的构建信息
+type buildInfo struct {
+	Version      string `json:"version"`                 // 模块版本，本地构建时为 (devel)
+	Commit       string `json:"commit,omitempty"`        // 构建时的提交
+	CommitTime   string `json:"commit_time,omitempty"`   // 提交的时间
+	Modified     bool   `json:"modified,omitempty"`      // 构建时工作区有未提交的修改
+	GoVersion    string `json:"go_version"`              // 编译使用的Go版本
+	Platform     string `json:"platform"`                // 操作系统和CPU架构
+	ParserRules  string `json:"parser_rules"`            // 内置解析规则的版本
+	ExtractRules string `json:"extract_rules,omitempty"` // --extract-rules 文件及其SHA-256的前12位
+	APIVersion   string `json:"api_version"`             // api 命令提供的API版本
+}
+
+// newBuildInfo 根据Go记录的构建信息和 -ldflags 设置的变量生成构建信息，info可以为nil
+func newBuildInfo(info *debug.BuildInfo) buildInfo {
+	b := buildInfo{
+		Version:     "(devel)",
+		GoVersion:   runtime.Version(),
+		Platform:    runtime.GOOS + "/" + runtime.GOARCH,
+		ParserRules: crawler.ParserRulesVersion,
+		APIVersion:  currentAPIVersion,
+	}
+	if info != nil {
+		if info.Main.Version != "" {
+			b.Version = info.Main.Version
+		}
+		b.GoVersion = info.GoVersion
+		for _, s := range info.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				b.Commit = s.Value
+			case "vcs.time":
+				b.CommitTime = s.Value
+			case "vcs.modified":
+				b.Modified = s.Value == "true"
+			}
+		}
+	}
+	if buildVersion != "" {
+		b.Version = buildVersion
+	}
+	if buildCommit != "" {
+		b.Commit = buildCommit
+	}
+	if extractRulesFile != "" {
+		b.ExtractRules = extractRulesFile
+		if data, err := os.ReadFile(extractRulesFile); err == nil {
+			sum := sha256.Sum256(data)
+			b.ExtractRules += " (sha256:" + hex.EncodeToString(sum[:])[:12] + ")"
+		}
+	}
+	return b
+}
+
+// currentBuildInfo 返回当前二进制的构建信息
+func currentBuildInfo() buildInfo {
+	info, _ := debug.ReadBuildInfo()
+	return newBuildInfo(info)
+}
+
+// shortCommit 返回提交的前12位，便于显示
+func shortCommit(commit string) string {
+	if len(commit) > 12 {
+		return commit[:12]
+	}
+	return commit
+}
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: T("显示版本和构建信息"),
+	Long: T(`显示cxcrawler的版本。使用 -v/--verbose 时同时显示构建的提交、Go版本、
+内置解析规则的版本和 --extract-rules 文件的摘要，报告问题时请附上这些信息。`),
+	Example: `  cxcrawler version
+  cxcrawler version --verbose
+  cxcrawler version --jq .parser_rules`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		info := currentBuildInfo()
+		if printFormatted(info) {
+			return
+		}
+		if verboseLevel == 0 {
+			fmt.Printf("cxcrawler %s\n", info.Version)
+			return
+		}
+
+		commit := shortCommit(info.Commit)
+		if commit == "" {
+			commit = T("未知")
+		}
+		if info.Modified {
+			commit += T(" (有未提交的修改)")
+		}
+		rows := [][2]string{
+			{T("版本"), info.Version},
+			{T("提交"), commit},
+			{T("提交时间"), info.CommitTime},
+			{T("Go版本"), info.GoVersion},
+			{T("平台"), info.Platform},
+			{T("解析规则"), info.ParserRules},
+			{T("规则文件"), info.ExtractRules},
+			{T("API版本"), info.APIVersion},
+		}
+		width := 0
+		for _, row := range rows {
+			width = max(width, text.StringWidthWithoutEscSequences(row[0]))
+		}
+		for _, row := range rows {
+			if row[1] != "" {
+				fmt.Printf("%s  %s\n", text.Pad(row[0]+":", width+1, ' '), row[1])
+			}
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(versionCmd)
+}
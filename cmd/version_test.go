@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"runtime/debug"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+)
+
+func TestNewBuildInfo(t *testing.T) {
+	info := newBuildInfo(&debug.BuildInfo{
+		GoVersion: "go1.25.0",
+		Main:      debug.Module{Version: "v1.4.0"},
+		Settings: []debug.BuildSetting{
+			{Key: "vcs.revision", Value: "0123456789abcdef0123"},
+			{Key: "vcs.time", Value: "2026-10-01T08:00:00Z"},
+			{Key: "vcs.modified", Value: "true"},
+		},
+	})
+	assert.Equal(t, "v1.4.0", info.Version)
+	assert.Equal(t, "0123456789abcdef0123", info.Commit)
+	assert.Equal(t, "0123456789ab", shortCommit(info.Commit))
+	assert.True(t, info.Modified)
+	assert.Equal(t, crawler.ParserRulesVersion, info.ParserRules)
+	assert.Equal(t, currentAPIVersion, info.APIVersion)
+
+	// -ldflags 设置的版本优先
+	defer func() { buildVersion, buildCommit = "", "" }()
+	buildVersion, buildCommit = "v1.5.0-rc1", "fedcba9"
+	info = newBuildInfo(nil)
+	assert.Equal(t, "v1.5.0-rc1", info.Version)
+	assert.Equal(t, "fedcba9", info.Commit)
+	assert.NotEmpty(t, info.GoVersion)
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/config"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/export"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/logging"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/store"
+)
+
+var (
+	vexCveIDs     []string
+	vexState      string
+	vexOutputFile string
+)
+
+var vexCmd = &cobra.Command{
+	Use:   "vex [cve.json...]",
+	Short: T("根据关注列表生成CycloneDX VEX文档"),
+	Long: T(`将CVE详情中的受影响软件与关注列表(watch命令)中的产品匹配，生成CycloneDX 1.5 VEX文档。
+CVE详情可以来自cve命令保存的JSON文件，也可以用 -i 直接爬取。
+cxsecurity只给出受影响的产品而没有版本范围，默认分析状态为 in_triage，需要人工确认。`),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 && len(vexCveIDs) == 0 {
+			return errors.New(T("请指定CVE详情文件或使用 -i 指定CVE编号"))
+		}
+		switch vexState {
+		case export.VEXStateInTriage, export.VEXStateExploitable, export.VEXStateNotAffected,
+			export.VEXStateResolved, export.VEXStateFalsePositive:
+		default:
+			return fmt.Errorf(T("无效的分析状态 %q"), vexState)
+		}
+
+		products, err := loadWatchlist()
+		if err != nil {
+			return err
+		}
+
+		var details []*model.CveDetail
+		for _, path := range args {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			var detail model.CveDetail
+			if err := json.Unmarshal(data, &detail); err != nil || detail.CveID == "" {
+				return fmt.Errorf(T("%s 不是CVE详情文件"), path)
+			}
+			details = append(details, &detail)
+		}
+		if len(vexCveIDs) > 0 {
+			c := newCrawler()
+			tracker := newRetryTracker()
+			defer tracker.save()
+			for _, id := range vexCveIDs {
+				cveID, idErr := model.ParseCVEID(id)
+				detail, err := c.CrawlCveDetail(id, "")
+				if err != nil {
+					logging.Printf(T("爬取失败: %v\n"), err)
+					if idErr == nil {
+						tracker.fail(store.RetryCVE, cveID.String(), "", err)
+					}
+					continue
+				}
+				tracker.succeed(store.RetryCVE, cveID.String())
+				details = append(details, detail)
+			}
+		}
+
+		bom := export.ToVEX(matchWatchlist(products, details), export.VEXOptions{State: vexState})
+		if vexOutputFile != "" {
+			if err := newCrawler().SaveJSON(bom, vexOutputFile); err != nil {
+				return fmt.Errorf(T("保存结果失败: %v"), err)
+			}
+			if humanOutput() {
+				fmt.Printf("%s %s %s\n",
+					text.Colors{text.FgHiGreen}.Sprint(T("✅ 已保存:")),
+					text.Colors{text.FgHiCyan, text.Underline}.Sprint(vexOutputFile),
+					text.Colors{text.FgHiBlack}.Sprintf(T("(%d 个漏洞, %d 个组件)"), len(bom.Vulnerabilities), len(bom.Components)))
+			}
+		}
+		if !printFormatted(bom) && vexOutputFile == "" {
+			data, err := json.MarshalIndent(bom, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+		}
+		return nil
+	},
+}
+
+// matchWatchlist 将CVE详情的受影响软件与关注列表匹配，返回所有匹配的组件和CVE
+func matchWatchlist(products []config.WatchedProduct, details []*model.CveDetail) []export.VEXMatch {
+	var matches []export.VEXMatch
+	for _, detail := range details {
+		for _, p := range products {
+			for _, sw := range detail.AffectedSoftware {
+				if !p.Matches(sw.VendorName, sw.ProductName) {
+					continue
+				}
+				vendor, product, version := p.Identity()
+				matches = append(matches, export.VEXMatch{
+					Component: export.VEXComponent{Ref: p.Name, Name: product, Vendor: vendor, Version: version, CPE: p.CPE},
+					Detail:    detail,
+				})
+				break
+			}
+		}
+	}
+	return matches
+}
+
+func init() {
+	rootCmd.AddCommand(vexCmd)
+
+	vexCmd.Flags().StringSliceVarP(&vexCveIDs, "id", "i", nil, T("要爬取的CVE编号，多个用逗号分隔"))
+	vexCmd.Flags().StringVar(&vexState, "state", export.VEXStateInTriage, T("VEX分析状态(in_triage、exploitable、not_affected、resolved、false_positive)"))
+	vexCmd.Flags().StringVarP(&vexOutputFile, "output", "o", "", T("输出文件路径，默认输出到标准输出"))
+	vexCmd.Flags().StringVar(&watchFile, "watchlist", "", T("关注列表文件，默认为配置目录下的 watchlist.json"))
+	addRetryQueueFlags(vexCmd)
+}
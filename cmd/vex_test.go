@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/config"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+func TestMatchWatchlist(t *testing.T) {
+	products := []config.WatchedProduct{
+		{Name: "php", CPE: "cpe:2.3:a:php:php:8.1.2:*:*:*:*:*:*:*"},
+		{Name: "nginx", Product: "nginx"},
+	}
+	details := []*model.CveDetail{
+		{CveID: "CVE-1", AffectedSoftware: []model.AffectedSoftware{{VendorName: "PHP", ProductName: "PHP"}, {VendorName: "PHP", ProductName: "PHP"}}},
+		{CveID: "CVE-2", AffectedSoftware: []model.AffectedSoftware{{VendorName: "Apache", ProductName: "HTTP Server"}}},
+	}
+
+	matches := matchWatchlist(products, details)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "php", matches[0].Component.Ref)
+	assert.Equal(t, "8.1.2", matches[0].Component.Version)
+	assert.Equal(t, "CVE-1", matches[0].Detail.CveID)
+}
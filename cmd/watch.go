@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/config"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+var (
+	watchFile string
+
+	watchAddProduct config.WatchedProduct
+	watchAddReplace bool
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: T("管理关注的产品"),
+	Long: T(`维护关注的产品列表，用于将CVE详情中的受影响软件与自己使用的产品匹配。
+产品可以用厂商和产品名称指定，也可以用CPE 2.3名称指定。
+关注列表保存在配置目录下的 watchlist.json 中，可以通过环境变量 CXCRAWLER_CONFIG_DIR 指定配置目录。`),
+}
+
+var watchAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: T("添加关注的产品"),
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := config.NewWatchlistStore(watchFile)
+		if err != nil {
+			return err
+		}
+
+		product := watchAddProduct
+		product.Name = strings.TrimSpace(args[0])
+		if err := store.Add(product, watchAddReplace); err != nil {
+			return err
+		}
+		fmt.Printf(T("已关注 %s")+"\n", text.Colors{text.FgHiWhite, text.Bold}.Sprint(product.Name))
+		return nil
+	},
+}
+
+var watchListCmd = &cobra.Command{
+	Use:   "list",
+	Short: T("列出关注的产品"),
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := config.NewWatchlistStore(watchFile)
+		if err != nil {
+			return err
+		}
+		products, err := store.List()
+		if err != nil {
+			return err
+		}
+
+		if printFormatted(products) || quietOutput {
+			return nil
+		}
+		if len(products) == 0 {
+			fmt.Println(text.Colors{text.FgHiBlack}.Sprint(T("还没有关注的产品，使用 watch add 添加")))
+			return nil
+		}
+
+		t := table.NewWriter()
+		t.SetOutputMirror(os.Stdout)
+		t.SetStyle(tableStyle(table.StyleRounded))
+		t.AppendHeader(table.Row{T("名称"), T("厂商"), T("产品"), T("版本"), "CPE"})
+		for _, p := range products {
+			vendor, product, version := p.Identity()
+			t.AppendRow(table.Row{
+				text.Colors{text.FgHiCyan, text.Bold}.Sprint(p.Name),
+				vendor,
+				product,
+				version,
+				p.CPE,
+			})
+		}
+		t.Render()
+		return nil
+	},
+}
+
+var watchRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: T("取消关注产品"),
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := config.NewWatchlistStore(watchFile)
+		if err != nil {
+			return err
+		}
+		if err := store.Remove(args[0]); err != nil {
+			return err
+		}
+		fmt.Printf(T("已取消关注 %s")+"\n", args[0])
+		return nil
+	},
+}
+
+// loadWatchlist 读取关注列表，列表为空时返回错误
+func loadWatchlist() ([]config.WatchedProduct, error) {
+	store, err := config.NewWatchlistStore(watchFile)
+	if err != nil {
+		return nil, err
+	}
+	products, err := store.List()
+	if err != nil {
+		return nil, err
+	}
+	if len(products) == 0 {
+		return nil, errors.New(T("关注列表为空，请先使用 watch add 添加产品"))
+	}
+	return products, nil
+}
+
+// watchMatch 是标题中提到了关注产品的漏洞
+type watchMatch struct {
+	Product       config.WatchedProduct
+	Vulnerability model.Vulnerability
+}
+
+// matchWatchlistTitles 按标题将漏洞与关注列表匹配
+// 每个漏洞只匹配第一个关注的产品，重复的漏洞ID只保留第一条
+func matchWatchlistTitles(products []config.WatchedProduct, items []model.Vulnerability) []watchMatch {
+	var matches []watchMatch
+	seen := make(map[string]bool)
+	for _, item := range items {
+		if item.ID != "" && seen[item.ID] {
+			continue
+		}
+		for _, p := range products {
+			if p.MatchesTitle(item.Title) {
+				seen[item.ID] = true
+				matches = append(matches, watchMatch{Product: p, Vulnerability: item})
+				break
+			}
+		}
+	}
+	return matches
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+	watchCmd.AddCommand(watchAddCmd, watchListCmd, watchRemoveCmd)
+
+	watchCmd.PersistentFlags().StringVar(&watchFile, "file", "", T("关注列表文件，默认为配置目录下的 watchlist.json"))
+
+	watchAddCmd.Flags().StringVar(&watchAddProduct.Vendor, "vendor", "", T("厂商名称，为空时匹配任意厂商"))
+	watchAddCmd.Flags().StringVar(&watchAddProduct.Product, "product", "", T("产品名称"))
+	watchAddCmd.Flags().StringVar(&watchAddProduct.CPE, "cpe", "", T("CPE 2.3名称，例如 cpe:2.3:a:php:php:8.1.2:*:*:*:*:*:*:*"))
+	watchAddCmd.Flags().StringVar(&watchAddProduct.Version, "version", "", T("正在使用的版本"))
+	watchAddCmd.Flags().BoolVar(&watchAddReplace, "replace", false, T("覆盖同名的关注产品"))
+}
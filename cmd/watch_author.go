@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/config"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/logging"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/sink"
+)
+
+var (
+	watchAuthorInterval time.Duration
+	watchAuthorState    string
+	watchAuthorWebhook  string
+	watchAuthorHeaders  []string
+	watchAuthorDryRun   bool
+)
+
+// authorCheck 是检查一个作者的结果
+type authorCheck struct {
+	AuthorID        string                `json:"author_id"`
+	AuthorName      string                `json:"author_name,omitempty"`
+	Status          string                `json:"status"` // baseline、unchanged、new 或 failed
+	Vulnerabilities []model.Vulnerability `json:"vulnerabilities,omitempty"`
+	Error           string                `json:"error,omitempty"`
+}
+
+var watchAuthorCmd = &cobra.Command{
+	Use:   "watch-author <id...>",
+	Short: T("监控作者新发布的漏洞"),
+	Long: T(`重新爬取作者页面，将漏洞列表与上次保存的状态比较，只报告新发布的漏洞。
+第一次检查某个作者时只记录当前的漏洞作为基线，不会把历史发布当作新发布。
+状态保存在配置目录下的 authors.json 中。指定 --interval 后作为守护进程按间隔持续检查，
+收到中断信号时退出。指定 --webhook 后，有新发布时将通知以JSON格式POST到该地址，
+地址也可以通过环境变量 CXCRAWLER_WEBHOOK_URL 设置。在工作站上运行时可以指定 --desktop-notify 弹出桌面通知。`),
+	Example: `  cxcrawler watch-author hyp3rlinx
+  cxcrawler watch-author hyp3rlinx indoushka --interval 1h --webhook https://hooks.example.com/cx
+  cxcrawler watch-author hyp3rlinx --webhook-header "Authorization: Bearer TOKEN" --interval 30m
+  cxcrawler watch-author hyp3rlinx --interval 1h --desktop-notify`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if watchAuthorWebhook == "" {
+			watchAuthorWebhook = os.Getenv("CXCRAWLER_WEBHOOK_URL")
+		}
+		options, err := webhookOptions(watchAuthorHeaders)
+		if err != nil {
+			return err
+		}
+		var hook *sink.Webhook
+		if watchAuthorWebhook != "" && !watchAuthorDryRun {
+			hook = sink.NewWebhook(watchAuthorWebhook, options...)
+		}
+
+		store, err := config.NewAuthorStore(watchAuthorState)
+		if err != nil {
+			return err
+		}
+
+		if watchAuthorInterval <= 0 {
+			checks := checkAuthors(store, hook, args, time.Now())
+			if !printFormatted(checks) && !quietOutput {
+				printAuthorChecks(checks)
+			}
+			for _, c := range checks {
+				if c.Status == "failed" {
+					return errors.New(T("部分作者检查失败"))
+				}
+			}
+			return nil
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		logging.Printf(T("每 %s 检查一次 %d 个作者，按 Ctrl+C 退出")+"\n", watchAuthorInterval, len(args))
+		ticker := time.NewTicker(watchAuthorInterval)
+		defer ticker.Stop()
+		now := time.Now()
+		for {
+			checks := checkAuthors(store, hook, args, now)
+			if !printFormatted(checks) && !quietOutput {
+				printAuthorChecks(checks)
+			}
+			select {
+			case <-ctx.Done():
+				return nil
+			case now = <-ticker.C:
+			}
+		}
+	},
+}
+
+// webhookOptions 将 --webhook-header 指定的 "名称: 值" 转换为Webhook选项
+func webhookOptions(headers []string) ([]sink.WebhookOption, error) {
+	var options []sink.WebhookOption
+	for _, h := range headers {
+		key, value, err := parseHeader(h)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, sink.WithWebhookHeader(key, value))
+	}
+	return options, nil
+}
+
+// checkAuthors 依次检查每个作者，有新发布且指定了hook时发送通知
+// 单个作者失败不影响其他作者，失败记录在结果中
+func checkAuthors(store *config.AuthorStore, hook *sink.Webhook, ids []string, now time.Time) []authorCheck {
+	c := newCrawler()
+	checks := make([]authorCheck, 0, len(ids))
+	for _, id := range ids {
+		check := authorCheck{AuthorID: id}
+		profile, err := c.CrawlAuthor(url.PathEscape(id), "")
+		if err == nil {
+			// 状态按命令行指定的ID保存，与页面中解析出的ID无关
+			profile.ID = id
+			check.AuthorName = profile.Name
+			// 先发送通知再保存状态，发送失败时下次检查会重新通知
+			var added []model.Vulnerability
+			var first bool
+			added, first, err = store.Diff(profile)
+			if err == nil && len(added) > 0 && hook != nil {
+				err = hook.Send(sink.AuthorPublications{
+					Event:           sink.AuthorPublicationsEvent,
+					AuthorID:        id,
+					AuthorName:      profile.Name,
+					AuthorURL:       model.SiteURL + "/author/" + url.PathEscape(id) + "/1/",
+					CheckedAt:       now,
+					Vulnerabilities: added,
+				})
+			}
+			if err == nil && !watchAuthorDryRun {
+				_, _, err = store.Update(profile, now)
+			}
+			switch {
+			case err != nil:
+			case first:
+				check.Status = "baseline"
+			case len(added) == 0:
+				check.Status = "unchanged"
+			default:
+				check.Status, check.Vulnerabilities = "new", added
+				if !watchAuthorDryRun {
+					name := profile.Name
+					if name == "" {
+						name = id
+					}
+					notifyDesktop(fmt.Sprintf(T("%s 发布了 %d 个新漏洞"), name, len(added)), added)
+				}
+			}
+		}
+		if err != nil {
+			check.Status, check.Error = "failed", err.Error()
+			logging.Printf(T("检查作者 %s 失败: %v")+"\n", id, err)
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// printAuthorChecks 以表格形式输出检查结果，新发布的漏洞逐条列出
+func printAuthorChecks(checks []authorCheck) {
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(tableStyle(table.StyleRounded))
+	t.AppendHeader(table.Row{T("作者"), T("状态"), "ID", T("标题")})
+	for _, c := range checks {
+		author := text.Colors{text.FgHiCyan, text.Bold}.Sprint(c.AuthorID)
+		switch c.Status {
+		case "baseline":
+			t.AppendRow(table.Row{author, text.Colors{text.FgHiBlack}.Sprint(T("已记录基线")), "", ""})
+		case "unchanged":
+			t.AppendRow(table.Row{author, text.Colors{text.FgHiBlack}.Sprint(T("没有新发布")), "", ""})
+		case "failed":
+			t.AppendRow(table.Row{author, text.Colors{text.FgHiRed}.Sprint(T("失败")), "", truncateCell(c.Error, 50)})
+		case "new":
+			status := text.Colors{text.FgHiGreen}.Sprintf(T("%d 个新发布"), len(c.Vulnerabilities))
+			for _, v := range c.Vulnerabilities {
+				t.AppendRow(table.Row{author, status, v.ID, truncateCell(v.Title, 50)})
+				author, status = "", ""
+			}
+		}
+	}
+	t.Render()
+}
+
+func init() {
+	rootCmd.AddCommand(watchAuthorCmd)
+
+	watchAuthorCmd.Flags().DurationVar(&watchAuthorInterval, "interval", 0, T("检查间隔，例如 1h；为0时只检查一次"))
+	watchAuthorCmd.Flags().StringVar(&watchAuthorState, "state", "", T("作者状态文件，默认为配置目录下的 authors.json"))
+	watchAuthorCmd.Flags().StringVar(&watchAuthorWebhook, "webhook", "", T("有新发布时POST通知的地址"))
+	watchAuthorCmd.Flags().StringArrayVar(&watchAuthorHeaders, "webhook-header", nil, T("Webhook请求头，格式为 \"名称: 值\"，可以指定多次"))
+	watchAuthorCmd.Flags().BoolVar(&desktopNotifyEnabled, "desktop-notify", false, T("有新发布时显示桌面通知"))
+	watchAuthorCmd.Flags().BoolVar(&watchAuthorDryRun, "dry-run", false, T("只显示新发布的漏洞，不保存状态也不发送通知"))
+}
@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/config"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+func TestMatchWatchlistTitles(t *testing.T) {
+	products := []config.WatchedProduct{
+		{Name: "nginx", Product: "nginx"},
+		{Name: "php", CPE: "cpe:2.3:a:php:php:8.1.2:*:*:*:*:*:*:*"},
+	}
+	items := []model.Vulnerability{
+		{ID: "WLB-1", Title: "PHP 8.1 nginx FastCGI RCE"},
+		{ID: "WLB-2", Title: "phpMyAdmin XSS"},
+		{ID: "WLB-1", Title: "PHP 8.1 nginx FastCGI RCE"},
+		{ID: "WLB-3", Title: "PHP Object Injection"},
+	}
+
+	matches := matchWatchlistTitles(products, items)
+	require.Len(t, matches, 2)
+	assert.Equal(t, "nginx", matches[0].Product.Name, "每个漏洞只匹配第一个产品")
+	assert.Equal(t, "WLB-3", matches[1].Vulnerability.ID)
+}
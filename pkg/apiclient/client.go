@@ -0,0 +1,191 @@
+// Package apiclient 是 api 命令提供的REST API的Go客户端
+//
+// 响应直接解析为 pkg/crawler 和 pkg/model 中服务端使用的类型，不需要自己定义JSON结构体。
+//
+// 使用示例：
+//
+//	c := apiclient.NewClient("http://localhost:8080", token)
+//	result, err := c.Search(ctx, crawler.SearchOptions{Keyword: "wordpress"})
+package apiclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+)
+
+// APIVersion 是客户端请求的API版本
+const APIVersion = "v1"
+
+// Option 是设置客户端选项的函数类型
+type Option func(*Client)
+
+// Client 是REST API的客户端，可以在多个goroutine中同时使用
+type Client struct {
+	baseURL   string
+	token     string
+	client    *http.Client
+	retries   int
+	retryWait time.Duration
+}
+
+// WithHTTPClient 设置使用的HTTP客户端，默认超时时间为60秒
+// 服务端每次请求都会实时抓取cxsecurity，超时时间不宜过短
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Client) {
+		if client != nil {
+			c.client = client
+		}
+	}
+}
+
+// WithRetry 设置请求失败时的重试次数和首次重试的等待时间，之后每次等待时间翻倍
+// 只重试网络错误、429和5xx响应，默认重试2次，等待1秒；maxRetries为0时不重试
+func WithRetry(maxRetries int, wait time.Duration) Option {
+	return func(c *Client) {
+		if maxRetries >= 0 {
+			c.retries = maxRetries
+		}
+		if wait > 0 {
+			c.retryWait = wait
+		}
+	}
+}
+
+// NewClient 创建REST API客户端
+//
+// 参数:
+//   - baseURL: API服务的地址，例如 http://localhost:8080
+//   - token: API Token，即 api 命令的 --token
+//   - options: 可选配置
+func NewClient(baseURL, token string, options ...Option) *Client {
+	c := &Client{
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		token:     token,
+		client:    &http.Client{Timeout: 60 * time.Second},
+		retries:   2,
+		retryWait: time.Second,
+	}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+// APIError 是服务端返回的错误
+// 服务端对参数错误和抓取失败返回HTTP 200和 success=false，StatusCode为200；
+// 认证失败等HTTP错误的StatusCode为对应的状态码
+type APIError struct {
+	StatusCode int    // HTTP状态码
+	Message    string // 服务端返回的错误信息
+}
+
+func (e *APIError) Error() string {
+	if e.StatusCode != http.StatusOK {
+		return fmt.Sprintf("API返回错误 %d: %s", e.StatusCode, e.Message)
+	}
+	return "API返回错误: " + e.Message
+}
+
+// retryable 判断请求是否应该重试
+func (e *APIError) retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// response 是服务端的标准响应格式
+type response struct {
+	Success bool               `json:"success"`
+	Data    json.RawMessage    `json:"data"`
+	Error   string             `json:"error"`
+	Meta    *crawler.Freshness `json:"meta"`
+}
+
+// freshnessKey 是 WithFreshness 在context中使用的键
+type freshnessKey struct{}
+
+// WithFreshness 返回的ctx用于请求时，响应中数据的获取时间和来源会写入f
+// 发送多次请求的方法(例如 SearchAll)写入最后一次请求的结果；服务端没有返回时f不变。
+//
+// 示例:
+//
+//	var f crawler.Freshness
+//	v, err := c.Exploit(apiclient.WithFreshness(ctx, &f), "WLB-2024040015")
+//	if f.CacheHit && time.Since(f.FetchedAt) > 24*time.Hour {
+//		// 数据来自一天前的缓存或本地存储
+//	}
+func WithFreshness(ctx context.Context, f *crawler.Freshness) context.Context {
+	return context.WithValue(ctx, freshnessKey{}, f)
+}
+
+// get 请求 /api/v1 下的path，并将响应中的data解析到out
+// 失败时按WithRetry的设置重试，ctx取消时立即返回
+func (c *Client) get(ctx context.Context, path string, query url.Values, out interface{}) error {
+	endpoint := c.baseURL + "/api/" + APIVersion + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	wait := c.retryWait
+	for attempt := 0; ; attempt++ {
+		err := c.do(ctx, endpoint, out)
+		var apiErr *APIError
+		if err == nil || attempt >= c.retries || ctx.Err() != nil || (errors.As(err, &apiErr) && !apiErr.retryable()) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+}
+
+// do 发送一次请求
+func (c *Client) do(ctx context.Context, endpoint string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("X-API-Token", c.token)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("请求API失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 32<<20))
+	if err != nil {
+		return fmt.Errorf("读取响应失败: %w", err)
+	}
+	var r response
+	if err := json.Unmarshal(data, &r); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return &APIError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(data))}
+		}
+		return fmt.Errorf("解析响应失败: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK || !r.Success {
+		if r.Error == "" {
+			r.Error = http.StatusText(resp.StatusCode)
+		}
+		return &APIError{StatusCode: resp.StatusCode, Message: r.Error}
+	}
+	if err := json.Unmarshal(r.Data, out); err != nil {
+		return fmt.Errorf("解析响应失败: %w", err)
+	}
+	if f, ok := ctx.Value(freshnessKey{}).(*crawler.Freshness); ok && f != nil && r.Meta != nil {
+		*f = *r.Meta
+	}
+	return nil
+}
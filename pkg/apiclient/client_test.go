@@ -0,0 +1,153 @@
+package apiclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+)
+
+func TestClientSearch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/search", r.URL.Path)
+		assert.Equal(t, "secret", r.Header.Get("X-API-Token"))
+		q := r.URL.Query()
+		assert.Equal(t, "php", q.Get("keyword"))
+		assert.Equal(t, "30", q.Get("per_page"))
+		assert.Equal(t, "High,Med", q.Get("risk"))
+		assert.Equal(t, "true", q.Get("remote"))
+		assert.Equal(t, "2024-01-02", q.Get("after"))
+		assert.Empty(t, q.Get("before"))
+		w.Write([]byte(`{"success":true,"data":{"keyword":"php","current_page":1,"total_pages":3,"per_page":30,
+			"vulnerabilities":[{"id":"WLB-2024040015","title":"PHP RCE","risk_level":"High"}]}}`))
+	}))
+	defer server.Close()
+
+	result, err := NewClient(server.URL+"/", "secret").Search(context.Background(), crawler.SearchOptions{
+		Keyword: "php",
+		PerPage: 30,
+		Risks:   []string{"High", "Med"},
+		Remote:  true,
+		After:   time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.TotalPages)
+	require.Len(t, result.Vulnerabilities, 1)
+	assert.Equal(t, "WLB-2024040015", result.Vulnerabilities[0].ID)
+}
+
+func TestClientErrors(t *testing.T) {
+	calls := 0
+	status := http.StatusServiceUnavailable
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch {
+		case r.URL.Path == "/api/v1/cve/bad":
+			w.Write([]byte(`{"success":false,"error":"无效的CVE编号"}`))
+		case r.Header.Get("X-API-Token") != "secret":
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"success":false,"error":"无效的API Token"}`))
+		case calls < 3:
+			w.WriteHeader(status)
+		default:
+			w.Write([]byte(`{"success":true,"data":{"id":"WLB-2024040015","title":"Foo"}}`))
+		}
+	}))
+	defer server.Close()
+	ctx := context.Background()
+
+	// 5xx会重试
+	c := NewClient(server.URL, "secret", WithRetry(2, time.Millisecond))
+	v, err := c.Exploit(ctx, "2024040015")
+	require.NoError(t, err)
+	assert.Equal(t, "Foo", v.Title)
+	assert.Equal(t, 3, calls)
+
+	// success=false不重试
+	calls = 0
+	_, err = c.CVE(ctx, "bad")
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusOK, apiErr.StatusCode)
+	assert.Equal(t, "无效的CVE编号", apiErr.Message)
+	assert.Equal(t, 1, calls)
+
+	calls = 0
+	_, err = NewClient(server.URL, "wrong").Author(ctx, "alice")
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+	assert.Equal(t, 1, calls)
+
+	// 重试次数用完后返回最后一次的错误
+	calls = 0
+	_, err = NewClient(server.URL, "secret", WithRetry(0, 0)).Exploit(ctx, "2024040015")
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+	assert.Equal(t, 1, calls)
+}
+
+func TestClientSearchAll(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page == 0 {
+			page = 1
+		}
+		if page == 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		// 第2页重复第1页的最后一条
+		id := "WLB-202404000" + strconv.Itoa(page)
+		w.Write([]byte(`{"success":true,"data":{"keyword":"php","current_page":` + strconv.Itoa(page) + `,"total_pages":3,
+			"vulnerabilities":[{"id":"WLB-2024040001","url":"u1"},{"id":"` + id + `","url":"u` + strconv.Itoa(page) + `"}]}}`))
+	}))
+	defer server.Close()
+	c := NewClient(server.URL, "secret", WithRetry(0, 0))
+
+	result, err := c.SearchAll(context.Background(), crawler.SearchOptions{Keyword: "php"}, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.PagesFetched)
+	assert.Len(t, result.Vulnerabilities, 2)
+
+	result, err = c.SearchAll(context.Background(), crawler.SearchOptions{Keyword: "php"}, 0)
+	assert.Error(t, err)
+	require.NotNil(t, result, "后续页失败时应返回已获取的结果")
+	assert.Equal(t, 2, result.PagesFetched)
+}
+
+func TestClientLatestAll(t *testing.T) {
+	// 新漏洞为 5、4、3、2，每次最多返回2条
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/exploit/latest", r.URL.Path)
+		switch r.URL.Query().Get("since") {
+		case "WLB-2024040001":
+			w.Write([]byte(`{"success":true,"data":{"cursor":"WLB-2024040003","has_more":true,"pages_scanned":1,
+				"vulnerabilities":[{"id":"WLB-2024040003"},{"id":"WLB-2024040002"}]}}`))
+		case "WLB-2024040003":
+			w.Write([]byte(`{"success":true,"data":{"cursor":"WLB-2024040005","has_more":false,"pages_scanned":1,
+				"vulnerabilities":[{"id":"WLB-2024040005"},{"id":"WLB-2024040004"}]}}`))
+		default:
+			t.Errorf("意外的since: %s", r.URL.Query().Get("since"))
+		}
+	}))
+	defer server.Close()
+
+	result, err := NewClient(server.URL, "secret").LatestAll(context.Background(), crawler.LatestOptions{Since: "WLB-2024040001", Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, "WLB-2024040005", result.Cursor)
+	assert.False(t, result.HasMore)
+	assert.Equal(t, 2, result.PagesScanned)
+	var ids []string
+	for _, v := range result.Vulnerabilities {
+		ids = append(ids, v.ID)
+	}
+	assert.Equal(t, []string{"WLB-2024040005", "WLB-2024040004", "WLB-2024040003", "WLB-2024040002"}, ids)
+}
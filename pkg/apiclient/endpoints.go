@@ -0,0 +1,281 @@
+package apiclient
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+// ExploitList 获取列表栏目的指定页，section为空时为 crawler.SectionExploit
+func (c *Client) ExploitList(ctx context.Context, section crawler.Section, page int) (*model.VulnerabilityList, error) {
+	query := url.Values{}
+	if section != "" {
+		query.Set("section", string(section))
+	}
+	if page > 1 {
+		query.Set("page", strconv.Itoa(page))
+	}
+	result := &model.VulnerabilityList{}
+	if err := c.get(ctx, "/exploit", query, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Exploit 获取漏洞详情，id可以不带 WLB- 前缀
+func (c *Client) Exploit(ctx context.Context, id string) (*model.Vulnerability, error) {
+	result := &model.Vulnerability{}
+	if err := c.get(ctx, "/exploit/"+url.PathEscape(id), nil, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CVE 获取CVE详情，编号不区分大小写
+func (c *Client) CVE(ctx context.Context, id string) (*model.CveDetail, error) {
+	result := &model.CveDetail{}
+	if err := c.get(ctx, "/cve/"+url.PathEscape(id), nil, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Author 获取作者信息
+func (c *Client) Author(ctx context.Context, id string) (*model.AuthorProfile, error) {
+	result := &model.AuthorProfile{}
+	if err := c.get(ctx, "/author/"+url.PathEscape(id), nil, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Search 搜索漏洞，返回opts.Page指定的一页
+// opts.Exclude 只在本地搜索时使用，不会发送到服务端
+func (c *Client) Search(ctx context.Context, opts crawler.SearchOptions) (*crawler.SearchResult, error) {
+	result := &crawler.SearchResult{}
+	if err := c.get(ctx, "/search", searchQuery(opts), result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// searchQuery 将搜索条件转换为 /api/search 的查询参数
+func searchQuery(opts crawler.SearchOptions) url.Values {
+	query := url.Values{}
+	query.Set("keyword", opts.Keyword)
+	if opts.Page > 1 {
+		query.Set("page", strconv.Itoa(opts.Page))
+	}
+	if opts.PerPage > 0 {
+		query.Set("per_page", strconv.Itoa(opts.PerPage))
+	}
+	if opts.SortOrder != "" {
+		query.Set("sort_order", opts.SortOrder)
+	}
+	if len(opts.Risks) > 0 {
+		query.Set("risk", strings.Join(opts.Risks, ","))
+	}
+	if opts.Remote {
+		query.Set("remote", "true")
+	}
+	if opts.Local {
+		query.Set("local", "true")
+	}
+	if !opts.After.IsZero() {
+		query.Set("after", opts.After.Format("2006-01-02"))
+	}
+	if !opts.Before.IsZero() {
+		query.Set("before", opts.Before.Format("2006-01-02"))
+	}
+	return query
+}
+
+// SearchAll 从opts.Page开始依次获取搜索结果的每一页，合并为一个结果
+// 与 crawler.Crawler.SearchAll 相同：获取到最后一页或达到maxPages页时停止，同一漏洞只保留第一次出现的记录(去掉的记录数保存在 Duplicates 中)，
+// 某一页失败时返回已获取的结果和错误，第一页失败时结果为nil。
+//
+// 参数:
+//   - ctx: 取消时在下一次请求前返回
+//   - opts: 搜索条件
+//   - maxPages: 最多获取的页数，小于等于0时使用 crawler.DefaultSearchMaxPages
+func (c *Client) SearchAll(ctx context.Context, opts crawler.SearchOptions, maxPages int) (*crawler.SearchResult, error) {
+	if maxPages <= 0 {
+		maxPages = crawler.DefaultSearchMaxPages
+	}
+	if opts.Page < 1 {
+		opts.Page = 1
+	}
+
+	var result *crawler.SearchResult
+	seen := make(map[string]bool)
+	for page := opts.Page; page < opts.Page+maxPages; page++ {
+		pageOpts := opts
+		pageOpts.Page = page
+		pageResult, err := c.Search(ctx, pageOpts)
+		if err != nil {
+			if result == nil {
+				return nil, err
+			}
+			return result, fmt.Errorf("第 %d 页搜索失败: %w", page, err)
+		}
+		if result == nil {
+			result = &crawler.SearchResult{
+				Keyword:         pageResult.Keyword,
+				CurrentPage:     opts.Page,
+				SortOrder:       pageResult.SortOrder,
+				PerPage:         pageResult.PerPage,
+				EmptyResult:     pageResult.EmptyResult,
+				Vulnerabilities: []crawler.SearchVulnerability{},
+			}
+		}
+		result.TotalPages = pageResult.TotalPages
+		result.PagesFetched++
+		for _, item := range pageResult.Vulnerabilities {
+			key := item.DedupKey()
+			if seen[key] {
+				result.Duplicates++
+				continue
+			}
+			seen[key] = true
+			result.Vulnerabilities = append(result.Vulnerabilities, item)
+		}
+		if page >= pageResult.TotalPages {
+			break
+		}
+	}
+	return result, nil
+}
+
+// Latest 获取比opts.Since新的漏洞，opts.MaxPages由服务端决定，不会发送
+// 结果的HasMore为true时应该用Cursor立即再次调用，或者使用LatestAll
+func (c *Client) Latest(ctx context.Context, opts crawler.LatestOptions) (*crawler.LatestResult, error) {
+	query := url.Values{}
+	if opts.Since != "" {
+		query.Set("since", opts.Since)
+	}
+	if opts.Limit > 0 {
+		query.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if opts.Section != "" {
+		query.Set("section", string(opts.Section))
+	}
+	result := &crawler.LatestResult{}
+	if err := c.get(ctx, "/exploit/latest", query, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// LatestAll 重复调用Latest直到HasMore为false，返回所有比opts.Since新的漏洞
+// 合并后的Vulnerabilities从新到旧排列，Cursor为最后一次调用的Cursor，Gap为第一次调用的Gap。
+// 中途失败时返回nil和错误，调用方应继续使用原来的since，避免漏掉漏洞。
+func (c *Client) LatestAll(ctx context.Context, opts crawler.LatestOptions) (*crawler.LatestResult, error) {
+	result, err := c.Latest(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for result.HasMore {
+		opts.Since = result.Cursor
+		next, err := c.Latest(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		// 服务端的cursor没有前进时停止，避免死循环
+		if next.Cursor == result.Cursor {
+			break
+		}
+		result.Vulnerabilities = append(next.Vulnerabilities, result.Vulnerabilities...)
+		result.Cursor = next.Cursor
+		result.HasMore = next.HasMore
+		result.PagesScanned += next.PagesScanned
+	}
+	return result, nil
+}
+
+// LocalAuthorOptions 是LocalAuthorVulnerabilities的查询条件
+type LocalAuthorOptions struct {
+	Risks   []string // 只返回这些风险级别的漏洞(High、Med、Low)，为空时不过滤
+	Remote  bool     // 只返回可远程利用的漏洞
+	Local   bool     // 只返回本地利用的漏洞
+	Page    int      // 页码，从1开始
+	PerPage int      // 每页记录数，为0时使用服务端的默认值50，最多500
+}
+
+// LocalAuthorResult 是服务端存储中某个作者的漏洞，按发布日期从新到旧分页
+type LocalAuthorResult struct {
+	AuthorID        string                `json:"author_id"`
+	AuthorName      string                `json:"author_name,omitempty"`
+	Total           int                   `json:"total"` // 过滤后的记录总数
+	Page            int                   `json:"page"`
+	PerPage         int                   `json:"per_page"`
+	TotalPages      int                   `json:"total_pages"`
+	Vulnerabilities []model.Vulnerability `json:"vulnerabilities"`
+}
+
+// LocalAuthorVulnerabilities 查询服务端存储中作者的漏洞，不会请求cxsecurity
+func (c *Client) LocalAuthorVulnerabilities(ctx context.Context, id string, opts LocalAuthorOptions) (*LocalAuthorResult, error) {
+	query := url.Values{}
+	if len(opts.Risks) > 0 {
+		query.Set("risk", strings.Join(opts.Risks, ","))
+	}
+	if opts.Remote {
+		query.Set("remote", "true")
+	}
+	if opts.Local {
+		query.Set("local", "true")
+	}
+	if opts.Page > 1 {
+		query.Set("page", strconv.Itoa(opts.Page))
+	}
+	if opts.PerPage > 0 {
+		query.Set("per_page", strconv.Itoa(opts.PerPage))
+	}
+	result := &LocalAuthorResult{}
+	if err := c.get(ctx, "/local/authors/"+url.PathEscape(id)+"/vulnerabilities", query, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// LocalAuthor 是服务端 watch-author 监控的一个作者
+type LocalAuthor struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name,omitempty"`
+	Country     string    `json:"country,omitempty"`      // 国家英文名称
+	CountryCode string    `json:"country_code,omitempty"` // ISO 3166-1国家代码
+	Seen        int       `json:"seen"`                   // 见过的漏洞数
+	LastChecked time.Time `json:"last_checked"`
+}
+
+// LocalCountry 是一个国家的作者数
+type LocalCountry struct {
+	CountryCode string `json:"country_code"` // 为空表示作者页面上没有国家
+	Country     string `json:"country,omitempty"`
+	Authors     int    `json:"authors"`
+}
+
+// LocalAuthorsResult 是监控的作者列表和按国家的统计
+type LocalAuthorsResult struct {
+	Total     int            `json:"total"` // 过滤后的作者数
+	Authors   []LocalAuthor  `json:"authors"`
+	Countries []LocalCountry `json:"countries"` // 所有作者按国家统计，不受country过滤
+}
+
+// LocalAuthors 查询服务端监控的作者，country为ISO 3166-1国家代码或英文国家名称，为空时返回全部作者
+func (c *Client) LocalAuthors(ctx context.Context, country string) (*LocalAuthorsResult, error) {
+	query := url.Values{}
+	if country != "" {
+		query.Set("country", country)
+	}
+	result := &LocalAuthorsResult{}
+	if err := c.get(ctx, "/local/authors", query, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
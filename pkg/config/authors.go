@@ -0,0 +1,167 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+// AuthorsFile 是配置目录下记录监控的作者状态的文件名
+const AuthorsFile = "authors.json"
+
+// AuthorState 是一个监控的作者上次检查时的状态
+type AuthorState struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name,omitempty"`
+	Country     string    `json:"country,omitempty"`      // 国家英文名称
+	CountryCode string    `json:"country_code,omitempty"` // ISO 3166-1国家代码
+	Seen        []string  `json:"seen"`                   // 已经见过的漏洞ID，按字母排序
+	LastChecked time.Time `json:"last_checked"`           // 上次检查的时间
+}
+
+// AuthorStore 保存监控的作者已经见过的漏洞，用于找出新发布的漏洞
+// 文件格式为以作者ID为键的JSON对象。
+type AuthorStore struct {
+	path string
+}
+
+// NewAuthorStore 创建使用指定文件的存储
+// path为空时使用配置目录下的authors.json
+func NewAuthorStore(path string) (*AuthorStore, error) {
+	if path == "" {
+		var err error
+		if path, err = Path(AuthorsFile); err != nil {
+			return nil, err
+		}
+	}
+	return &AuthorStore{path: path}, nil
+}
+
+// Path 返回存储文件的路径
+func (s *AuthorStore) Path() string {
+	return s.path
+}
+
+// Get 返回作者的状态，从未检查过时第二个返回值为false
+func (s *AuthorStore) Get(id string) (AuthorState, bool, error) {
+	states, err := s.load()
+	if err != nil {
+		return AuthorState{}, false, err
+	}
+	state, ok := states[id]
+	return state, ok, nil
+}
+
+// List 返回所有监控的作者的状态，按ID排列
+func (s *AuthorStore) List() ([]AuthorState, error) {
+	states, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	list := make([]AuthorState, 0, len(states))
+	for _, state := range states {
+		list = append(list, state)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list, nil
+}
+
+// Diff 将作者最新的漏洞列表与上次的状态比较，返回新发布的漏洞，不保存状态
+//
+// 第一次检查某个作者时first为true，不返回任何漏洞，避免把作者的全部历史发布当作新发布。
+// 没有ID的漏洞无法比较，会被忽略。
+func (s *AuthorStore) Diff(profile *model.AuthorProfile) (added []model.Vulnerability, first bool, err error) {
+	state, ok, err := s.Get(strings.TrimSpace(profile.ID))
+	if err != nil || !ok {
+		return nil, !ok, err
+	}
+	seen := make(map[string]bool, len(state.Seen))
+	for _, vid := range state.Seen {
+		seen[vid] = true
+	}
+	for _, v := range profile.Vulnerabilities {
+		if v.ID == "" || seen[v.ID] {
+			continue
+		}
+		seen[v.ID] = true
+		added = append(added, v)
+	}
+	return added, false, nil
+}
+
+// Update 与 Diff 相同，但会将当前的漏洞列表合并到状态中并保存
+// 第一次检查某个作者时只记录当前的漏洞作为基线。已经从作者页面消失的漏洞仍然记为见过。
+func (s *AuthorStore) Update(profile *model.AuthorProfile, now time.Time) (added []model.Vulnerability, first bool, err error) {
+	id := strings.TrimSpace(profile.ID)
+	if id == "" {
+		return nil, false, errors.New("作者ID不能为空")
+	}
+	states, err := s.load()
+	if err != nil {
+		return nil, false, err
+	}
+	state, ok := states[id]
+	first = !ok
+
+	seen := make(map[string]bool, len(state.Seen))
+	for _, vid := range state.Seen {
+		seen[vid] = true
+	}
+	for _, v := range profile.Vulnerabilities {
+		if v.ID == "" || seen[v.ID] {
+			continue
+		}
+		seen[v.ID] = true
+		if !first {
+			added = append(added, v)
+		}
+	}
+
+	state.ID = id
+	if profile.Name != "" {
+		state.Name = profile.Name
+	}
+	if profile.CountryCode != "" {
+		state.Country, state.CountryCode = profile.Country, profile.CountryCode
+	}
+	state.Seen = state.Seen[:0]
+	for vid := range seen {
+		state.Seen = append(state.Seen, vid)
+	}
+	sort.Strings(state.Seen)
+	state.LastChecked = now
+	states[id] = state
+	if err := writeJSON(s.path, states); err != nil {
+		return nil, false, fmt.Errorf("保存作者状态失败: %w", err)
+	}
+	return added, first, nil
+}
+
+// Remove 删除作者的状态，下次检查时重新建立基线
+func (s *AuthorStore) Remove(id string) error {
+	states, err := s.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := states[id]; !ok {
+		return fmt.Errorf("没有监控作者: %s", id)
+	}
+	delete(states, id)
+	if err := writeJSON(s.path, states); err != nil {
+		return fmt.Errorf("保存作者状态失败: %w", err)
+	}
+	return nil
+}
+
+// load 读取所有作者的状态，文件不存在时返回空集合
+func (s *AuthorStore) load() (map[string]AuthorState, error) {
+	states := make(map[string]AuthorState)
+	if _, err := readJSON(s.path, &states); err != nil {
+		return nil, fmt.Errorf("读取作者状态失败: %w", err)
+	}
+	return states, nil
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+func TestAuthorStoreUpdate(t *testing.T) {
+	store, err := NewAuthorStore(filepath.Join(t.TempDir(), AuthorsFile))
+	require.NoError(t, err)
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	// 第一次检查只记录基线
+	profile := &model.AuthorProfile{ID: "alice", Name: "Alice", Country: "Poland", CountryCode: "PL", Vulnerabilities: []model.Vulnerability{
+		{ID: "WLB-2024010001"}, {ID: "WLB-2024010002"}, {Title: "no id"},
+	}}
+	added, first, err := store.Update(profile, now)
+	require.NoError(t, err)
+	assert.True(t, first)
+	assert.Empty(t, added)
+
+	state, ok, err := store.Get("alice")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, AuthorState{ID: "alice", Name: "Alice", Country: "Poland", CountryCode: "PL", Seen: []string{"WLB-2024010001", "WLB-2024010002"}, LastChecked: now}, state)
+
+	// 之后只返回新发布的漏洞，已经从列表中消失的漏洞仍然记为见过
+	profile.Vulnerabilities = []model.Vulnerability{{ID: "WLB-2024010003", Title: "new"}, {ID: "WLB-2024010002"}, {ID: "WLB-2024010003"}}
+	added, first, err = store.Diff(profile)
+	require.NoError(t, err)
+	assert.False(t, first)
+	assert.Equal(t, []model.Vulnerability{{ID: "WLB-2024010003", Title: "new"}}, added)
+
+	added, first, err = store.Update(profile, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.False(t, first)
+	assert.Equal(t, []model.Vulnerability{{ID: "WLB-2024010003", Title: "new"}}, added)
+
+	// 页面上没有国家时保留之前记录的国家
+	profile.Country, profile.CountryCode = "", ""
+	added, _, err = store.Update(profile, now.Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, added)
+
+	state, _, err = store.Get("alice")
+	require.NoError(t, err)
+	assert.Equal(t, "PL", state.CountryCode)
+	assert.Equal(t, []string{"WLB-2024010001", "WLB-2024010002", "WLB-2024010003"}, state.Seen)
+	assert.Equal(t, now.Add(2*time.Hour), state.LastChecked)
+
+	_, first, err = store.Diff(&model.AuthorProfile{ID: "bob"})
+	require.NoError(t, err)
+	assert.True(t, first)
+	_, _, err = store.Update(&model.AuthorProfile{}, now)
+	assert.Error(t, err)
+}
+
+func TestAuthorStoreListRemove(t *testing.T) {
+	store, err := NewAuthorStore(filepath.Join(t.TempDir(), AuthorsFile))
+	require.NoError(t, err)
+
+	list, err := store.List()
+	require.NoError(t, err)
+	assert.Empty(t, list)
+
+	for _, id := range []string{"bob", "alice"} {
+		_, _, err := store.Update(&model.AuthorProfile{ID: id}, time.Now())
+		require.NoError(t, err)
+	}
+	list, err = store.List()
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "alice", list[0].ID)
+
+	require.NoError(t, store.Remove("alice"))
+	assert.Error(t, store.Remove("alice"))
+	_, ok, err := store.Get("alice")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// 删除后重新建立基线
+	_, first, err := store.Update(&model.AuthorProfile{ID: "alice"}, time.Now())
+	require.NoError(t, err)
+	assert.True(t, first)
+}
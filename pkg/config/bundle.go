@@ -0,0 +1,232 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// BundleVersion 是当前的配置包格式版本
+const BundleVersion = 1
+
+// Bundle 是可移植的配置包，包含关注列表、保存的搜索、通知设置和保留策略
+// 以YAML格式导出，便于在团队中分享或迁移到另一台机器。通知设置中不包含凭据。
+type Bundle struct {
+	Version       int                   `yaml:"version"`
+	ExportedAt    time.Time             `yaml:"exported_at"`
+	Watchlist     []WatchedProduct      `yaml:"watchlist,omitempty"`
+	SavedSearches []SavedSearch         `yaml:"saved_searches,omitempty"`
+	Notifications *NotificationSettings `yaml:"notifications,omitempty"`
+	Retention     *RetentionSettings    `yaml:"retention,omitempty"`
+}
+
+// BundlePaths 指定配置包对应的各个配置文件，为空的字段使用配置目录下的默认文件
+type BundlePaths struct {
+	Watchlist     string
+	SavedSearches string
+	Notifications string
+	Retention     string
+}
+
+// 导入配置包时每一项的处理结果
+const (
+	BundleAdded    = "added"    // 本地没有，已添加
+	BundleReplaced = "replaced" // 本地已有，已覆盖
+	BundleSkipped  = "skipped"  // 本地已有，没有指定覆盖，保留本地的配置
+)
+
+// BundleItem 是导入配置包中的一项的结果
+type BundleItem struct {
+	Kind   string `json:"kind"` // watchlist、saved_search、notifications 或 retention
+	Name   string `json:"name,omitempty"`
+	Status string `json:"status"`
+}
+
+// ExportBundle 读取当前的配置，生成配置包
+// 没有的配置(例如没有保存过保留策略)不会出现在配置包中。
+func ExportBundle(paths BundlePaths, now time.Time) (*Bundle, error) {
+	bundle := &Bundle{Version: BundleVersion, ExportedAt: now.UTC().Truncate(time.Second)}
+
+	watchlist, err := NewWatchlistStore(paths.Watchlist)
+	if err != nil {
+		return nil, err
+	}
+	if bundle.Watchlist, err = watchlist.List(); err != nil {
+		return nil, err
+	}
+
+	searches, err := NewSavedSearchStore(paths.SavedSearches)
+	if err != nil {
+		return nil, err
+	}
+	if bundle.SavedSearches, err = searches.List(); err != nil {
+		return nil, err
+	}
+
+	notifications, err := LoadNotifications(paths.Notifications)
+	if err != nil {
+		return nil, err
+	}
+	if !notifications.IsZero() {
+		bundle.Notifications = &notifications
+	}
+
+	retention, found, err := loadRetentionSettings(paths.Retention)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		bundle.Retention = &retention
+	}
+	return bundle, nil
+}
+
+// YAML 将配置包序列化为YAML
+func (b *Bundle) YAML() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(b); err != nil {
+		return nil, fmt.Errorf("序列化配置包失败: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("序列化配置包失败: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// ParseBundle 解析YAML格式的配置包，并校验其中的每一项
+// 未知的字段和更新版本的配置包会返回错误，避免拼写错误的配置被悄悄忽略。
+func ParseBundle(data []byte) (*Bundle, error) {
+	var bundle Bundle
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&bundle); err != nil {
+		return nil, fmt.Errorf("解析配置包失败: %w", err)
+	}
+	if bundle.Version == 0 {
+		return nil, errors.New("配置包缺少版本号")
+	}
+	if bundle.Version > BundleVersion {
+		return nil, fmt.Errorf("不支持的配置包版本 %d，请升级cxcrawler", bundle.Version)
+	}
+
+	for _, p := range bundle.Watchlist {
+		if err := p.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	for _, s := range bundle.SavedSearches {
+		if _, err := s.Options(); err != nil {
+			return nil, err
+		}
+	}
+	if bundle.Retention != nil {
+		if _, err := bundle.Retention.Policy(); err != nil {
+			return nil, err
+		}
+	}
+	return &bundle, nil
+}
+
+// ImportBundle 将配置包导入本地配置
+// 本地已有的同名关注产品和搜索、已有的通知设置和保留策略，replace为false时保留本地的配置，为true时覆盖。
+// dryRun为true时只返回将要进行的操作，不修改配置文件。
+func ImportBundle(b *Bundle, paths BundlePaths, replace, dryRun bool) ([]BundleItem, error) {
+	var items []BundleItem
+	status := func(exists bool) string {
+		switch {
+		case !exists:
+			return BundleAdded
+		case replace:
+			return BundleReplaced
+		}
+		return BundleSkipped
+	}
+
+	watchlist, err := NewWatchlistStore(paths.Watchlist)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range b.Watchlist {
+		_, err := watchlist.Get(p.Name)
+		if err != nil && !errors.Is(err, ErrWatchNotFound) {
+			return nil, err
+		}
+		item := BundleItem{Kind: "watchlist", Name: p.Name, Status: status(err == nil)}
+		if !dryRun && item.Status != BundleSkipped {
+			if err := watchlist.Add(p, true); err != nil {
+				return nil, err
+			}
+		}
+		items = append(items, item)
+	}
+
+	searches, err := NewSavedSearchStore(paths.SavedSearches)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range b.SavedSearches {
+		_, err := searches.Get(s.Name)
+		if err != nil && !errors.Is(err, ErrSavedSearchNotFound) {
+			return nil, err
+		}
+		item := BundleItem{Kind: "saved_search", Name: s.Name, Status: status(err == nil)}
+		if !dryRun && item.Status != BundleSkipped {
+			if err := searches.Add(s, true); err != nil {
+				return nil, err
+			}
+		}
+		items = append(items, item)
+	}
+
+	if b.Notifications != nil {
+		current, err := LoadNotifications(paths.Notifications)
+		if err != nil {
+			return nil, err
+		}
+		item := BundleItem{Kind: "notifications", Status: status(!current.IsZero())}
+		if !dryRun && item.Status != BundleSkipped {
+			if err := SaveNotifications(paths.Notifications, *b.Notifications); err != nil {
+				return nil, err
+			}
+		}
+		items = append(items, item)
+	}
+
+	if b.Retention != nil {
+		_, found, err := loadRetentionSettings(paths.Retention)
+		if err != nil {
+			return nil, err
+		}
+		item := BundleItem{Kind: "retention", Status: status(found)}
+		if !dryRun && item.Status != BundleSkipped {
+			policy, err := b.Retention.Policy()
+			if err != nil {
+				return nil, err
+			}
+			if err := SaveRetention(paths.Retention, policy); err != nil {
+				return nil, err
+			}
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// loadRetentionSettings 读取保留策略文件的原始内容，文件不存在时第二个返回值为false
+func loadRetentionSettings(path string) (RetentionSettings, bool, error) {
+	var settings RetentionSettings
+	path, err := retentionPath(path)
+	if err != nil {
+		return settings, false, err
+	}
+	found, err := readJSON(path, &settings)
+	if err != nil {
+		return settings, false, fmt.Errorf("读取保留策略失败: %w", err)
+	}
+	return settings, found, nil
+}
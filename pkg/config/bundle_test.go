@@ -0,0 +1,93 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func bundlePaths(dir string) BundlePaths {
+	return BundlePaths{
+		Watchlist:     filepath.Join(dir, WatchlistFile),
+		SavedSearches: filepath.Join(dir, SavedSearchesFile),
+		Notifications: filepath.Join(dir, NotificationsFile),
+		Retention:     filepath.Join(dir, RetentionFile),
+	}
+}
+
+func TestBundleRoundTrip(t *testing.T) {
+	src := bundlePaths(t.TempDir())
+	watchlist, err := NewWatchlistStore(src.Watchlist)
+	require.NoError(t, err)
+	require.NoError(t, watchlist.Add(WatchedProduct{Name: "php", CPE: "cpe:2.3:a:php:php:8.1.2:*:*:*:*:*:*:*"}, false))
+	searches, err := NewSavedSearchStore(src.SavedSearches)
+	require.NoError(t, err)
+	require.NoError(t, searches.Add(SavedSearch{Name: "wp", Keyword: "wordpress", Risks: []string{"high"}}, false))
+	require.NoError(t, SaveNotifications(src.Notifications, NotificationSettings{Jira: &JiraSettings{URL: "https://jira.example.com", Project: "SEC"}}))
+	require.NoError(t, SaveRetention(src.Retention, crawler.RetentionPolicy{RawHTML: 30 * 24 * time.Hour}))
+
+	bundle, err := ExportBundle(src, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	data, err := bundle.YAML()
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "saved_searches:")
+	assert.Contains(t, string(data), "raw_html: 30d")
+
+	parsed, err := ParseBundle(data)
+	require.NoError(t, err)
+	dst := bundlePaths(t.TempDir())
+
+	// dry-run不修改配置
+	items, err := ImportBundle(parsed, dst, false, true)
+	require.NoError(t, err)
+	assert.Len(t, items, 4)
+	_, err = LoadNotifications(dst.Notifications)
+	require.NoError(t, err)
+	empty, err := NewWatchlistStore(dst.Watchlist)
+	require.NoError(t, err)
+	list, err := empty.List()
+	require.NoError(t, err)
+	assert.Empty(t, list)
+
+	items, err = ImportBundle(parsed, dst, false, false)
+	require.NoError(t, err)
+	for _, item := range items {
+		assert.Equal(t, BundleAdded, item.Status, item.Kind)
+	}
+	search, err := NewSavedSearchStore(dst.SavedSearches)
+	require.NoError(t, err)
+	got, err := search.Get("wp")
+	require.NoError(t, err)
+	assert.Equal(t, "wordpress", got.Keyword)
+	notifications, err := LoadNotifications(dst.Notifications)
+	require.NoError(t, err)
+	assert.Equal(t, "SEC", notifications.Jira.Project)
+	policy, err := LoadRetention(dst.Retention)
+	require.NoError(t, err)
+	assert.Equal(t, 30*24*time.Hour, policy.RawHTML)
+
+	// 再次导入时保留本地配置，指定replace时覆盖
+	items, err = ImportBundle(parsed, dst, false, false)
+	require.NoError(t, err)
+	assert.Equal(t, BundleSkipped, items[0].Status)
+	items, err = ImportBundle(parsed, dst, true, false)
+	require.NoError(t, err)
+	assert.Equal(t, BundleReplaced, items[0].Status)
+}
+
+func TestParseBundleInvalid(t *testing.T) {
+	_, err := ParseBundle([]byte("watchlist: []\n"))
+	assert.Error(t, err)
+	_, err = ParseBundle([]byte("version: 99\n"))
+	assert.Error(t, err)
+	_, err = ParseBundle([]byte("version: 1\nwatchlsit: []\n"))
+	assert.Error(t, err)
+	_, err = ParseBundle([]byte("version: 1\nwatchlist:\n  - name: x\n"))
+	assert.Error(t, err)
+	_, err = ParseBundle([]byte("version: 1\nretention:\n  raw_html: soon\n"))
+	assert.Error(t, err)
+}
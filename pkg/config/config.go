@@ -0,0 +1,63 @@
+// Package config 管理cxcrawler的本地配置，例如保存的搜索条件
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+)
+
+// DirEnv 是指定配置目录的环境变量
+const DirEnv = "CXCRAWLER_CONFIG_DIR"
+
+// Dir 返回配置目录
+// 优先使用环境变量CXCRAWLER_CONFIG_DIR，否则使用系统的用户配置目录下的cxcrawler目录，
+// 例如Linux上的 ~/.config/cxcrawler
+func Dir() (string, error) {
+	if dir := os.Getenv(DirEnv); dir != "" {
+		return dir, nil
+	}
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("获取用户配置目录失败: %w", err)
+	}
+	return filepath.Join(base, "cxcrawler"), nil
+}
+
+// Path 返回配置目录下指定文件的路径
+func Path(name string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// readJSON 读取JSON配置文件到v中，文件不存在时返回false且不报错
+func readJSON(path string, v interface{}) (bool, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, json.Unmarshal(data, v)
+}
+
+// writeJSON 将v格式化为JSON后原子地写入配置文件
+// 配置中可能包含敏感的关注对象，文件和新建的目录只允许当前用户访问
+func writeJSON(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化配置失败: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), crawler.SecureDirMode); err != nil {
+		return fmt.Errorf("创建配置目录失败: %w", err)
+	}
+	return crawler.WriteFileAtomic(path, data, crawler.SecureFileMode, false)
+}
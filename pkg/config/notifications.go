@@ -0,0 +1,74 @@
+package config
+
+import "fmt"
+
+// NotificationsFile 是配置目录下保存通知设置的文件名
+const NotificationsFile = "notifications.json"
+
+// NotificationSettings 是jira、page和defectdojo命令的默认设置
+// 命令行参数和环境变量优先于这里的设置。Token、API密钥等凭据不保存在文件中，
+// 只能通过命令行参数或环境变量指定，因此可以放心地导出和分享。
+type NotificationSettings struct {
+	Jira       *JiraSettings       `json:"jira,omitempty" yaml:"jira,omitempty"`
+	Opsgenie   *OpsgenieSettings   `json:"opsgenie,omitempty" yaml:"opsgenie,omitempty"`
+	DefectDojo *DefectDojoSettings `json:"defectdojo,omitempty" yaml:"defectdojo,omitempty"`
+}
+
+// JiraSettings 是jira命令的默认设置
+type JiraSettings struct {
+	URL       string   `json:"url,omitempty" yaml:"url,omitempty"`
+	User      string   `json:"user,omitempty" yaml:"user,omitempty"`
+	Project   string   `json:"project,omitempty" yaml:"project,omitempty"`
+	IssueType string   `json:"issue_type,omitempty" yaml:"issue_type,omitempty"`
+	Labels    []string `json:"labels,omitempty" yaml:"labels,omitempty"`
+}
+
+// OpsgenieSettings 是page命令发送Opsgenie告警的默认设置
+type OpsgenieSettings struct {
+	URL string `json:"url,omitempty" yaml:"url,omitempty"`
+}
+
+// DefectDojoSettings 是defectdojo命令的默认设置
+type DefectDojoSettings struct {
+	URL        string `json:"url,omitempty" yaml:"url,omitempty"`
+	Engagement int    `json:"engagement,omitempty" yaml:"engagement,omitempty"`
+	TestTitle  string `json:"test_title,omitempty" yaml:"test_title,omitempty"`
+}
+
+// IsZero 判断是否没有任何设置
+func (n NotificationSettings) IsZero() bool {
+	return n.Jira == nil && n.Opsgenie == nil && n.DefectDojo == nil
+}
+
+// notificationsPath 返回通知设置文件的路径，path为空时使用配置目录下的notifications.json
+func notificationsPath(path string) (string, error) {
+	if path != "" {
+		return path, nil
+	}
+	return Path(NotificationsFile)
+}
+
+// LoadNotifications 读取通知设置，path为空时使用配置目录下的notifications.json，文件不存在时返回空设置
+func LoadNotifications(path string) (NotificationSettings, error) {
+	var settings NotificationSettings
+	path, err := notificationsPath(path)
+	if err != nil {
+		return settings, err
+	}
+	if _, err := readJSON(path, &settings); err != nil {
+		return settings, fmt.Errorf("读取通知设置失败: %w", err)
+	}
+	return settings, nil
+}
+
+// SaveNotifications 保存通知设置，path为空时使用配置目录下的notifications.json
+func SaveNotifications(path string, settings NotificationSettings) error {
+	path, err := notificationsPath(path)
+	if err != nil {
+		return err
+	}
+	if err := writeJSON(path, settings); err != nil {
+		return fmt.Errorf("保存通知设置失败: %w", err)
+	}
+	return nil
+}
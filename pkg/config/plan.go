@@ -0,0 +1,301 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+// 计划中每项变更的操作
+const (
+	PlanCreate = "create" // 本地没有，将要添加
+	PlanUpdate = "update" // 本地已有但内容不同，将要修改
+	PlanDelete = "delete" // 期望的配置中没有，将要删除
+)
+
+// PlanChange 是计划中的一项变更
+type PlanChange struct {
+	Kind   string   `json:"kind"` // watchlist、saved_search、notifications 或 retention
+	Name   string   `json:"name,omitempty"`
+	Action string   `json:"action"`
+	Diff   []string `json:"diff,omitempty"` // 变化的字段，格式为 "字段: 旧值 -> 新值"
+}
+
+// Plan 是将本地配置变为期望的配置需要进行的变更
+//
+// 与 ImportBundle 合并配置不同，期望的配置是完整的状态：配置文件中没有的关注产品、搜索、
+// 通知设置和保留策略会被删除。先用 PlanBundle 查看变更，确认后调用 Apply。
+type Plan struct {
+	Changes []PlanChange `json:"changes"`
+
+	desired *Bundle
+	paths   BundlePaths
+}
+
+// Empty 返回本地配置是否已经与期望的配置一致
+func (p *Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// PlanBundle 比较期望的配置与本地配置，返回需要进行的变更，不修改任何文件
+// 比较时忽略 created_at，期望的配置应先经过 ParseBundle 校验。
+//
+// 示例:
+//
+//	desired, _ := config.ParseBundle(data)
+//	plan, err := config.PlanBundle(desired, config.BundlePaths{})
+//	if err == nil && !plan.Empty() {
+//	    err = plan.Apply(time.Now())
+//	}
+func PlanBundle(desired *Bundle, paths BundlePaths) (*Plan, error) {
+	current, err := ExportBundle(paths, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	plan := &Plan{Changes: []PlanChange{}, desired: desired, paths: paths}
+
+	currentProducts := make(map[string]WatchedProduct)
+	for _, p := range current.Watchlist {
+		currentProducts[p.Name] = p
+	}
+	desiredProducts := make(map[string]bool)
+	for _, p := range desired.Watchlist {
+		if desiredProducts[p.Name] {
+			return nil, fmt.Errorf("关注的产品重复: %s", p.Name)
+		}
+		desiredProducts[p.Name] = true
+		old, ok := currentProducts[p.Name]
+		var oldValue interface{}
+		if ok {
+			old.CreatedAt, p.CreatedAt = time.Time{}, time.Time{}
+			oldValue = old
+		}
+		plan.add("watchlist", p.Name, oldValue, p)
+	}
+	for _, p := range current.Watchlist {
+		if !desiredProducts[p.Name] {
+			plan.Changes = append(plan.Changes, PlanChange{Kind: "watchlist", Name: p.Name, Action: PlanDelete})
+		}
+	}
+
+	currentSearches := make(map[string]SavedSearch)
+	for _, s := range current.SavedSearches {
+		currentSearches[s.Name] = s
+	}
+	desiredSearches := make(map[string]bool)
+	for _, s := range desired.SavedSearches {
+		if desiredSearches[s.Name] {
+			return nil, fmt.Errorf("保存的搜索重复: %s", s.Name)
+		}
+		desiredSearches[s.Name] = true
+		old, ok := currentSearches[s.Name]
+		var oldValue interface{}
+		if ok {
+			old.CreatedAt, s.CreatedAt = time.Time{}, time.Time{}
+			oldValue = old
+		}
+		plan.add("saved_search", s.Name, oldValue, s)
+	}
+	for _, s := range current.SavedSearches {
+		if !desiredSearches[s.Name] {
+			plan.Changes = append(plan.Changes, PlanChange{Kind: "saved_search", Name: s.Name, Action: PlanDelete})
+		}
+	}
+
+	switch {
+	case desired.Notifications != nil && !desired.Notifications.IsZero():
+		var oldValue interface{}
+		if current.Notifications != nil {
+			oldValue = *current.Notifications
+		}
+		plan.add("notifications", "", oldValue, *desired.Notifications)
+	case current.Notifications != nil:
+		plan.Changes = append(plan.Changes, PlanChange{Kind: "notifications", Action: PlanDelete})
+	}
+
+	switch {
+	case desired.Retention != nil:
+		// 保存时会规范化保留时间，例如 4w 保存为 28d，比较规范化后的值
+		policy, err := desired.Retention.Policy()
+		if err != nil {
+			return nil, err
+		}
+		var oldValue interface{}
+		if current.Retention != nil {
+			oldValue = *current.Retention
+		}
+		plan.add("retention", "", oldValue, NewRetentionSettings(policy))
+	case current.Retention != nil:
+		plan.Changes = append(plan.Changes, PlanChange{Kind: "retention", Action: PlanDelete})
+	}
+	return plan, nil
+}
+
+// add 比较一项配置，old为nil表示本地没有，内容相同时不记录变更
+func (p *Plan) add(kind, name string, old, desired interface{}) {
+	if old == nil {
+		p.Changes = append(p.Changes, PlanChange{Kind: kind, Name: name, Action: PlanCreate, Diff: diffFields(nil, desired)})
+		return
+	}
+	if diff := diffFields(old, desired); len(diff) > 0 {
+		p.Changes = append(p.Changes, PlanChange{Kind: kind, Name: name, Action: PlanUpdate, Diff: diff})
+	}
+}
+
+// diffFields 返回两个配置中不同的字段，嵌套的字段用 "." 连接，例如 "jira.project: SEC -> OPS"
+// 字段按名称排序，old为nil时列出desired的所有字段
+func diffFields(old, desired interface{}) []string {
+	before, after := flattenFields(old), flattenFields(desired)
+	keys := make(map[string]bool)
+	for k := range before {
+		keys[k] = true
+	}
+	for k := range after {
+		keys[k] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	var diff []string
+	for _, k := range sorted {
+		a, aok := before[k]
+		b, bok := after[k]
+		switch {
+		case !aok:
+			diff = append(diff, fmt.Sprintf("%s: %s", k, b))
+		case !bok:
+			diff = append(diff, fmt.Sprintf("%s: %s -> (none)", k, a))
+		case a != b:
+			diff = append(diff, fmt.Sprintf("%s: %s -> %s", k, a, b))
+		}
+	}
+	return diff
+}
+
+// flattenFields 将配置按JSON字段展开为 字段 -> JSON值，忽略 created_at
+func flattenFields(v interface{}) map[string]string {
+	fields := make(map[string]string)
+	if v == nil {
+		return fields
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fields
+	}
+	var m map[string]interface{}
+	if json.Unmarshal(data, &m) != nil {
+		return fields
+	}
+	var walk func(prefix string, m map[string]interface{})
+	walk = func(prefix string, m map[string]interface{}) {
+		for k, value := range m {
+			if nested, ok := value.(map[string]interface{}); ok {
+				walk(prefix+k+".", nested)
+				continue
+			}
+			if k == "created_at" {
+				continue
+			}
+			encoded, _ := json.Marshal(value)
+			fields[prefix+k] = string(encoded)
+		}
+	}
+	walk("", m)
+	return fields
+}
+
+// Apply 按计划修改本地配置，使其与期望的配置一致
+// 新添加的关注产品和搜索的 created_at 为now，修改时保留本地原来的 created_at。
+func (p *Plan) Apply(now time.Time) error {
+	if p.Empty() {
+		return nil
+	}
+
+	watchlist, err := NewWatchlistStore(p.paths.Watchlist)
+	if err != nil {
+		return err
+	}
+	currentProducts, err := watchlist.List()
+	if err != nil {
+		return err
+	}
+	created := make(map[string]time.Time)
+	for _, product := range currentProducts {
+		created[product.Name] = product.CreatedAt
+	}
+	products := make([]WatchedProduct, 0, len(p.desired.Watchlist))
+	for _, product := range p.desired.Watchlist {
+		if t, ok := created[product.Name]; ok && !t.IsZero() {
+			product.CreatedAt = t
+		} else if product.CreatedAt.IsZero() {
+			product.CreatedAt = now
+		}
+		products = append(products, product)
+	}
+	if err := watchlist.write(products); err != nil {
+		return err
+	}
+
+	searches, err := NewSavedSearchStore(p.paths.SavedSearches)
+	if err != nil {
+		return err
+	}
+	currentSearches, err := searches.List()
+	if err != nil {
+		return err
+	}
+	created = make(map[string]time.Time)
+	for _, s := range currentSearches {
+		created[s.Name] = s.CreatedAt
+	}
+	list := make([]SavedSearch, 0, len(p.desired.SavedSearches))
+	for _, s := range p.desired.SavedSearches {
+		if t, ok := created[s.Name]; ok && !t.IsZero() {
+			s.CreatedAt = t
+		} else if s.CreatedAt.IsZero() {
+			s.CreatedAt = now
+		}
+		list = append(list, s)
+	}
+	if err := searches.write(list); err != nil {
+		return err
+	}
+
+	if p.desired.Notifications != nil && !p.desired.Notifications.IsZero() {
+		if err := SaveNotifications(p.paths.Notifications, *p.desired.Notifications); err != nil {
+			return err
+		}
+	} else if err := removeConfig(notificationsPath(p.paths.Notifications)); err != nil {
+		return fmt.Errorf("删除通知设置失败: %w", err)
+	}
+
+	if p.desired.Retention != nil {
+		policy, err := p.desired.Retention.Policy()
+		if err != nil {
+			return err
+		}
+		if err := SaveRetention(p.paths.Retention, policy); err != nil {
+			return err
+		}
+	} else if err := removeConfig(retentionPath(p.paths.Retention)); err != nil {
+		return fmt.Errorf("删除保留策略失败: %w", err)
+	}
+	return nil
+}
+
+// removeConfig 删除配置文件，文件不存在时不报错
+func removeConfig(path string, err error) error {
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
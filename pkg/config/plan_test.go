@@ -0,0 +1,79 @@
+package config
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+)
+
+func TestPlanBundle(t *testing.T) {
+	paths := bundlePaths(t.TempDir())
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	watchlist, err := NewWatchlistStore(paths.Watchlist)
+	require.NoError(t, err)
+	require.NoError(t, watchlist.Add(WatchedProduct{Name: "php", Vendor: "php", Product: "php", CreatedAt: created}, false))
+	require.NoError(t, watchlist.Add(WatchedProduct{Name: "old", Product: "struts"}, false))
+	searches, err := NewSavedSearchStore(paths.SavedSearches)
+	require.NoError(t, err)
+	require.NoError(t, searches.Add(SavedSearch{Name: "wp", Keyword: "wordpress", CreatedAt: created}, false))
+	require.NoError(t, SaveNotifications(paths.Notifications, NotificationSettings{Jira: &JiraSettings{URL: "https://jira.example.com", Project: "SEC"}}))
+
+	desired, err := ParseBundle([]byte(`version: 1
+watchlist:
+  - name: php
+    vendor: php
+    product: php
+  - name: nginx
+    product: nginx
+saved_searches:
+  - name: wp
+    keyword: wordpress
+    risks: [High]
+retention:
+  raw_html: 4w
+  records: forever
+`))
+	require.NoError(t, err)
+
+	plan, err := PlanBundle(desired, paths)
+	require.NoError(t, err)
+	assert.Equal(t, []PlanChange{
+		{Kind: "watchlist", Name: "nginx", Action: PlanCreate, Diff: []string{`name: "nginx"`, `product: "nginx"`}},
+		{Kind: "watchlist", Name: "old", Action: PlanDelete},
+		{Kind: "saved_search", Name: "wp", Action: PlanUpdate, Diff: []string{`risks: ["High"]`}},
+		{Kind: "notifications", Action: PlanDelete},
+		{Kind: "retention", Action: PlanCreate, Diff: []string{`raw_html: "28d"`, `records: "forever"`}},
+	}, plan.Changes)
+
+	require.NoError(t, plan.Apply(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)))
+	products, err := watchlist.List()
+	require.NoError(t, err)
+	require.Len(t, products, 2)
+	assert.Equal(t, "nginx", products[0].Name)
+	assert.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), products[0].CreatedAt.UTC())
+	assert.Equal(t, created, products[1].CreatedAt.UTC(), "修改时保留原来的 created_at")
+	wp, err := searches.Get("wp")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"High"}, wp.Risks)
+	_, err = os.Stat(paths.Notifications)
+	assert.True(t, os.IsNotExist(err))
+	policy, err := LoadRetention(paths.Retention)
+	require.NoError(t, err)
+	assert.Equal(t, crawler.RetentionPolicy{RawHTML: 28 * 24 * time.Hour}, policy)
+
+	// 应用后再次比较没有变更
+	plan, err = PlanBundle(desired, paths)
+	require.NoError(t, err)
+	assert.True(t, plan.Empty())
+}
+
+func TestPlanBundleDuplicate(t *testing.T) {
+	desired := &Bundle{Version: BundleVersion, Watchlist: []WatchedProduct{{Name: "php", Product: "php"}, {Name: "php", Product: "php"}}}
+	_, err := PlanBundle(desired, bundlePaths(t.TempDir()))
+	assert.ErrorContains(t, err, "关注的产品重复")
+}
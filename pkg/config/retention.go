@@ -0,0 +1,67 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+)
+
+// RetentionFile 是配置目录下保存数据保留策略的文件名
+const RetentionFile = "retention.json"
+
+// RetentionSettings 是保留策略在文件中的格式，使用 "90d"、"forever" 这样便于手工编辑的字符串
+type RetentionSettings struct {
+	RawHTML string `json:"raw_html" yaml:"raw_html"`
+	Records string `json:"records" yaml:"records"`
+}
+
+// NewRetentionSettings 将保留策略转换为文件中的格式
+func NewRetentionSettings(policy crawler.RetentionPolicy) RetentionSettings {
+	return RetentionSettings{
+		RawHTML: crawler.FormatRetention(policy.RawHTML),
+		Records: crawler.FormatRetention(policy.Records),
+	}
+}
+
+// Policy 解析保留时间，返回保留策略
+func (r RetentionSettings) Policy() (crawler.RetentionPolicy, error) {
+	var policy crawler.RetentionPolicy
+	var err error
+	if policy.RawHTML, err = crawler.ParseRetention(r.RawHTML); err != nil {
+		return policy, err
+	}
+	if policy.Records, err = crawler.ParseRetention(r.Records); err != nil {
+		return policy, err
+	}
+	return policy, nil
+}
+
+// retentionPath 返回保留策略文件的路径，path为空时使用配置目录下的retention.json
+func retentionPath(path string) (string, error) {
+	if path != "" {
+		return path, nil
+	}
+	return Path(RetentionFile)
+}
+
+// LoadRetention 读取保存的保留策略
+// path为空时使用配置目录下的retention.json，文件不存在时返回 crawler.DefaultRetentionPolicy
+func LoadRetention(path string) (crawler.RetentionPolicy, error) {
+	settings, found, err := loadRetentionSettings(path)
+	if err != nil || !found {
+		return crawler.DefaultRetentionPolicy, err
+	}
+	return settings.Policy()
+}
+
+// SaveRetention 保存保留策略，path为空时使用配置目录下的retention.json
+func SaveRetention(path string, policy crawler.RetentionPolicy) error {
+	path, err := retentionPath(path)
+	if err != nil {
+		return err
+	}
+	if err := writeJSON(path, NewRetentionSettings(policy)); err != nil {
+		return fmt.Errorf("保存保留策略失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetention(t *testing.T) {
+	path := filepath.Join(t.TempDir(), RetentionFile)
+
+	// 文件不存在时使用默认策略
+	policy, err := LoadRetention(path)
+	require.NoError(t, err)
+	assert.Equal(t, crawler.DefaultRetentionPolicy, policy)
+
+	want := crawler.RetentionPolicy{RawHTML: 30 * 24 * time.Hour, Records: 36 * time.Hour}
+	require.NoError(t, SaveRetention(path, want))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"raw_html": "30d"`)
+
+	policy, err = LoadRetention(path)
+	require.NoError(t, err)
+	assert.Equal(t, want, policy)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"raw_html": "soon"}`), 0600))
+	_, err = LoadRetention(path)
+	assert.Error(t, err)
+}
@@ -0,0 +1,180 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+)
+
+// SavedSearchesFile 是配置目录下保存搜索条件的文件名
+const SavedSearchesFile = "saved_searches.json"
+
+// ErrSavedSearchNotFound 表示指定名称的搜索条件不存在
+var ErrSavedSearchNotFound = errors.New("保存的搜索不存在")
+
+// SavedSearch 是一条命名的搜索条件
+// 日期使用 YYYY-MM-DD 格式的字符串保存，便于手工编辑配置文件
+type SavedSearch struct {
+	Name      string    `json:"name" yaml:"name"`
+	Keyword   string    `json:"keyword" yaml:"keyword"`
+	Risks     []string  `json:"risks,omitempty" yaml:"risks,omitempty"`
+	Remote    bool      `json:"remote,omitempty" yaml:"remote,omitempty"`
+	Local     bool      `json:"local,omitempty" yaml:"local,omitempty"`
+	After     string    `json:"after,omitempty" yaml:"after,omitempty"`
+	Before    string    `json:"before,omitempty" yaml:"before,omitempty"`
+	PerPage   int       `json:"per_page,omitempty" yaml:"per_page,omitempty"`
+	SortOrder string    `json:"sort_order,omitempty" yaml:"sort_order,omitempty"`
+	CreatedAt time.Time `json:"created_at" yaml:"created_at"`
+}
+
+// Options 将保存的搜索条件转换为crawler.SearchOptions，同时校验各字段
+//
+// 返回值:
+//   - crawler.SearchOptions: 第1页的搜索条件
+//   - error: 名称或关键词为空、风险级别无效、日期格式错误或日期范围颠倒时返回错误
+func (s SavedSearch) Options() (crawler.SearchOptions, error) {
+	opts := crawler.SearchOptions{
+		Keyword:   s.Keyword,
+		Page:      1,
+		PerPage:   s.PerPage,
+		SortOrder: strings.ToUpper(s.SortOrder),
+		Risks:     s.Risks,
+		Remote:    s.Remote,
+		Local:     s.Local,
+	}
+
+	if strings.TrimSpace(s.Name) == "" {
+		return opts, errors.New("搜索名称不能为空")
+	}
+	if strings.TrimSpace(s.Keyword) == "" {
+		return opts, fmt.Errorf("搜索 %s 的关键词不能为空", s.Name)
+	}
+	for _, r := range s.Risks {
+		if crawler.NormalizeRiskLevel(r) == "" {
+			return opts, fmt.Errorf("搜索 %s 的风险级别无效: %s", s.Name, r)
+		}
+	}
+
+	var err error
+	if opts.After, err = parseDate(s.After); err != nil {
+		return opts, fmt.Errorf("搜索 %s 的开始日期无效: %w", s.Name, err)
+	}
+	if opts.Before, err = parseDate(s.Before); err != nil {
+		return opts, fmt.Errorf("搜索 %s 的结束日期无效: %w", s.Name, err)
+	}
+	if !opts.After.IsZero() && !opts.Before.IsZero() && opts.After.After(opts.Before) {
+		return opts, fmt.Errorf("搜索 %s 的开始日期晚于结束日期", s.Name)
+	}
+	return opts, nil
+}
+
+// parseDate 解析 YYYY-MM-DD 格式的日期，空字符串返回零值
+func parseDate(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse("2006-01-02", s)
+}
+
+// SavedSearchStore 将命名的搜索条件保存在JSON文件中
+// 每次操作都重新读取文件，命令行和守护进程可以同时使用同一个文件
+type SavedSearchStore struct {
+	path string
+}
+
+// NewSavedSearchStore 创建使用指定文件的存储
+// path为空时使用配置目录下的saved_searches.json
+func NewSavedSearchStore(path string) (*SavedSearchStore, error) {
+	if path == "" {
+		var err error
+		if path, err = Path(SavedSearchesFile); err != nil {
+			return nil, err
+		}
+	}
+	return &SavedSearchStore{path: path}, nil
+}
+
+// Path 返回存储文件的路径
+func (s *SavedSearchStore) Path() string {
+	return s.path
+}
+
+// List 返回所有保存的搜索，按名称排序；文件不存在时返回空列表
+func (s *SavedSearchStore) List() ([]SavedSearch, error) {
+	var searches []SavedSearch
+	if _, err := readJSON(s.path, &searches); err != nil {
+		return nil, fmt.Errorf("读取保存的搜索失败: %w", err)
+	}
+	sort.Slice(searches, func(i, j int) bool { return searches[i].Name < searches[j].Name })
+	return searches, nil
+}
+
+// Get 返回指定名称的搜索，不存在时返回ErrSavedSearchNotFound
+func (s *SavedSearchStore) Get(name string) (SavedSearch, error) {
+	searches, err := s.List()
+	if err != nil {
+		return SavedSearch{}, err
+	}
+	for _, search := range searches {
+		if search.Name == name {
+			return search, nil
+		}
+	}
+	return SavedSearch{}, fmt.Errorf("%w: %s", ErrSavedSearchNotFound, name)
+}
+
+// Add 保存一条搜索
+// 同名搜索已存在时，replace为false则返回错误，为true则覆盖。CreatedAt为零值时设为当前时间。
+func (s *SavedSearchStore) Add(search SavedSearch, replace bool) error {
+	if _, err := search.Options(); err != nil {
+		return err
+	}
+	if search.CreatedAt.IsZero() {
+		search.CreatedAt = time.Now()
+	}
+
+	searches, err := s.List()
+	if err != nil {
+		return err
+	}
+	for i := range searches {
+		if searches[i].Name == search.Name {
+			if !replace {
+				return fmt.Errorf("保存的搜索已存在: %s", search.Name)
+			}
+			searches[i] = search
+			return s.write(searches)
+		}
+	}
+	return s.write(append(searches, search))
+}
+
+// Remove 删除指定名称的搜索，不存在时返回ErrSavedSearchNotFound
+func (s *SavedSearchStore) Remove(name string) error {
+	searches, err := s.List()
+	if err != nil {
+		return err
+	}
+	for i := range searches {
+		if searches[i].Name == name {
+			return s.write(append(searches[:i], searches[i+1:]...))
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrSavedSearchNotFound, name)
+}
+
+// write 按名称排序后原子地写入存储文件
+func (s *SavedSearchStore) write(searches []SavedSearch) error {
+	sort.Slice(searches, func(i, j int) bool { return searches[i].Name < searches[j].Name })
+	if searches == nil {
+		searches = []SavedSearch{}
+	}
+	if err := writeJSON(s.path, searches); err != nil {
+		return fmt.Errorf("保存搜索失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSavedSearchOptions(t *testing.T) {
+	opts, err := SavedSearch{
+		Name:      "wp",
+		Keyword:   "wordpress",
+		Risks:     []string{"high"},
+		Remote:    true,
+		After:     "2023-01-01",
+		SortOrder: "asc",
+	}.Options()
+	require.NoError(t, err)
+	assert.Equal(t, "wordpress", opts.Keyword)
+	assert.Equal(t, 1, opts.Page)
+	assert.Equal(t, "ASC", opts.SortOrder)
+	assert.Equal(t, []string{"high"}, opts.Risks)
+	assert.True(t, opts.Remote)
+	assert.Equal(t, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), opts.After)
+	assert.True(t, opts.Before.IsZero())
+
+	invalid := []SavedSearch{
+		{Keyword: "wordpress"},
+		{Name: "wp"},
+		{Name: "wp", Keyword: "wordpress", Risks: []string{"critical"}},
+		{Name: "wp", Keyword: "wordpress", After: "2023/01/01"},
+		{Name: "wp", Keyword: "wordpress", After: "2023-02-01", Before: "2023-01-01"},
+	}
+	for _, s := range invalid {
+		_, err := s.Options()
+		assert.Error(t, err, "%+v", s)
+	}
+}
+
+func TestSavedSearchStore(t *testing.T) {
+	store, err := NewSavedSearchStore(filepath.Join(t.TempDir(), "nested", SavedSearchesFile))
+	require.NoError(t, err)
+
+	// 文件不存在时返回空列表
+	searches, err := store.List()
+	require.NoError(t, err)
+	assert.Empty(t, searches)
+
+	require.NoError(t, store.Add(SavedSearch{Name: "xss", Keyword: "xss"}, false))
+	require.NoError(t, store.Add(SavedSearch{Name: "apache", Keyword: "apache", Risks: []string{"High"}}, false))
+	assert.Error(t, store.Add(SavedSearch{Name: "xss", Keyword: "cross site"}, false))
+	assert.Error(t, store.Add(SavedSearch{Name: "bad", Keyword: ""}, false))
+
+	require.NoError(t, store.Add(SavedSearch{Name: "xss", Keyword: "cross site"}, true))
+
+	searches, err = store.List()
+	require.NoError(t, err)
+	require.Len(t, searches, 2)
+	assert.Equal(t, "apache", searches[0].Name)
+	assert.Equal(t, "xss", searches[1].Name)
+	assert.Equal(t, "cross site", searches[1].Keyword)
+	assert.False(t, searches[1].CreatedAt.IsZero())
+
+	search, err := store.Get("apache")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"High"}, search.Risks)
+
+	require.NoError(t, store.Remove("apache"))
+	_, err = store.Get("apache")
+	assert.True(t, errors.Is(err, ErrSavedSearchNotFound))
+	assert.True(t, errors.Is(store.Remove("apache"), ErrSavedSearchNotFound))
+}
+
+func TestDir(t *testing.T) {
+	t.Setenv(DirEnv, "/tmp/cxcrawler-test")
+	dir, err := Dir()
+	require.NoError(t, err)
+	assert.Equal(t, "/tmp/cxcrawler-test", dir)
+
+	path, err := Path(SavedSearchesFile)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("/tmp/cxcrawler-test", SavedSearchesFile), path)
+}
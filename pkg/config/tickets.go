@@ -0,0 +1,85 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// TicketsFile 是配置目录下记录已创建工单的文件名
+const TicketsFile = "tickets.json"
+
+// Ticket 是为某个漏洞在外部系统(例如JIRA)中创建的工单
+type Ticket struct {
+	System    string    `json:"system"`        // 外部系统，例如 "jira"
+	VulnID    string    `json:"vuln_id"`       // 漏洞ID (WLB-XXXXXXXXX)
+	Key       string    `json:"key"`           // 工单编号，例如 SEC-42
+	URL       string    `json:"url,omitempty"` // 工单地址
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TicketStore 记录已经创建过工单的漏洞，避免同一个漏洞重复创建工单
+// 工单按 系统 + 漏洞ID 区分，文件格式为以 "system:vuln_id" 为键的JSON对象。
+type TicketStore struct {
+	path string
+}
+
+// NewTicketStore 创建使用指定文件的存储
+// path为空时使用配置目录下的tickets.json
+func NewTicketStore(path string) (*TicketStore, error) {
+	if path == "" {
+		var err error
+		if path, err = Path(TicketsFile); err != nil {
+			return nil, err
+		}
+	}
+	return &TicketStore{path: path}, nil
+}
+
+// Path 返回存储文件的路径
+func (s *TicketStore) Path() string {
+	return s.path
+}
+
+// Get 返回漏洞在指定系统中的工单，没有创建过时第二个返回值为false
+func (s *TicketStore) Get(system, vulnID string) (Ticket, bool, error) {
+	tickets, err := s.load()
+	if err != nil {
+		return Ticket{}, false, err
+	}
+	ticket, ok := tickets[ticketKey(system, vulnID)]
+	return ticket, ok, nil
+}
+
+// Add 记录一个新创建的工单，同一系统和漏洞ID的旧记录会被覆盖。CreatedAt为零值时设为当前时间。
+func (s *TicketStore) Add(ticket Ticket) error {
+	if ticket.System == "" || ticket.VulnID == "" {
+		return errors.New("工单的系统和漏洞ID不能为空")
+	}
+	if ticket.CreatedAt.IsZero() {
+		ticket.CreatedAt = time.Now()
+	}
+	tickets, err := s.load()
+	if err != nil {
+		return err
+	}
+	tickets[ticketKey(ticket.System, ticket.VulnID)] = ticket
+	if err := writeJSON(s.path, tickets); err != nil {
+		return fmt.Errorf("保存工单记录失败: %w", err)
+	}
+	return nil
+}
+
+// load 读取所有工单记录，文件不存在时返回空集合
+func (s *TicketStore) load() (map[string]Ticket, error) {
+	tickets := make(map[string]Ticket)
+	if _, err := readJSON(s.path, &tickets); err != nil {
+		return nil, fmt.Errorf("读取工单记录失败: %w", err)
+	}
+	return tickets, nil
+}
+
+// ticketKey 返回工单在文件中的键
+func ticketKey(system, vulnID string) string {
+	return system + ":" + vulnID
+}
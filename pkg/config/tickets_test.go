@@ -0,0 +1,32 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTicketStore(t *testing.T) {
+	store, err := NewTicketStore(filepath.Join(t.TempDir(), TicketsFile))
+	require.NoError(t, err)
+
+	_, ok, err := store.Get("jira", "WLB-2024010001")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, store.Add(Ticket{System: "jira", VulnID: "WLB-2024010001", Key: "SEC-1"}))
+	ticket, ok, err := store.Get("jira", "WLB-2024010001")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "SEC-1", ticket.Key)
+	assert.False(t, ticket.CreatedAt.IsZero())
+
+	// 不同系统的工单互不影响
+	_, ok, err = store.Get("pagerduty", "WLB-2024010001")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.Error(t, store.Add(Ticket{System: "jira"}))
+}
@@ -0,0 +1,211 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// WatchlistFile 是配置目录下保存关注产品的文件名
+const WatchlistFile = "watchlist.json"
+
+// ErrWatchNotFound 表示指定名称的关注产品不存在
+var ErrWatchNotFound = errors.New("关注的产品不存在")
+
+// WatchedProduct 是一个关注的产品，用于将CVE详情中的受影响软件与自己使用的产品匹配
+// 可以指定厂商和产品名称，也可以指定CPE 2.3名称，两者都指定时以CPE为准。
+type WatchedProduct struct {
+	Name      string    `json:"name" yaml:"name"`                           // 唯一名称，同时作为VEX文档中组件的bom-ref
+	Vendor    string    `json:"vendor,omitempty" yaml:"vendor,omitempty"`   // 厂商名称，为空时匹配任意厂商
+	Product   string    `json:"product,omitempty" yaml:"product,omitempty"` // 产品名称
+	CPE       string    `json:"cpe,omitempty" yaml:"cpe,omitempty"`         // CPE 2.3名称，例如 cpe:2.3:a:php:php:8.1.2:*:*:*:*:*:*:*
+	Version   string    `json:"version,omitempty" yaml:"version,omitempty"` // 正在使用的版本，仅用于输出
+	CreatedAt time.Time `json:"created_at" yaml:"created_at"`
+}
+
+// cpeFields 解析CPE 2.3名称，返回厂商、产品和版本
+// 格式为 cpe:2.3:part:vendor:product:version:...，"*" 和 "-" 表示任意值
+func cpeFields(cpe string) (vendor, product, version string, err error) {
+	parts := strings.Split(cpe, ":")
+	if len(parts) < 5 || parts[0] != "cpe" || parts[1] != "2.3" {
+		return "", "", "", fmt.Errorf("无效的CPE名称: %s", cpe)
+	}
+	value := func(i int) string {
+		if i >= len(parts) || parts[i] == "*" || parts[i] == "-" {
+			return ""
+		}
+		return strings.ReplaceAll(parts[i], `\`, "")
+	}
+	if value(4) == "" {
+		return "", "", "", fmt.Errorf("CPE名称中缺少产品: %s", cpe)
+	}
+	return value(3), value(4), value(5), nil
+}
+
+// normalizeCPEName 将名称转换为CPE风格：小写，空格和连字符替换为下划线
+func normalizeCPEName(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(s)
+}
+
+// Validate 检查名称不为空，并且指定了产品名称或有效的CPE名称
+func (w WatchedProduct) Validate() error {
+	if strings.TrimSpace(w.Name) == "" {
+		return errors.New("关注的产品名称不能为空")
+	}
+	if w.CPE != "" {
+		if _, _, _, err := cpeFields(w.CPE); err != nil {
+			return err
+		}
+		return nil
+	}
+	if strings.TrimSpace(w.Product) == "" {
+		return fmt.Errorf("关注的产品 %s 需要指定产品名称或CPE", w.Name)
+	}
+	return nil
+}
+
+// Identity 返回用于匹配的厂商、产品名称和版本，CPE中的值优先
+func (w WatchedProduct) Identity() (vendor, product, version string) {
+	vendor, product, version = w.Vendor, w.Product, w.Version
+	if w.CPE != "" {
+		if v, p, ver, err := cpeFields(w.CPE); err == nil {
+			vendor, product = v, p
+			if ver != "" && version == "" {
+				version = ver
+			}
+		}
+	}
+	return vendor, product, version
+}
+
+// Matches 判断受影响软件的厂商和产品是否为该关注的产品
+// 名称按CPE风格比较，不区分大小写，空格、连字符和下划线视为相同；没有指定厂商时只比较产品名称。
+func (w WatchedProduct) Matches(vendor, product string) bool {
+	wantVendor, wantProduct, _ := w.Identity()
+	if wantProduct == "" || normalizeCPEName(product) != normalizeCPEName(wantProduct) {
+		return false
+	}
+	return wantVendor == "" || normalizeCPEName(vendor) == normalizeCPEName(wantVendor)
+}
+
+// MatchesTitle 判断漏洞公告的标题中是否提到了该关注的产品
+// 产品名称按单词整体匹配，不区分大小写，标点、空格、连字符和下划线视为相同，
+// 例如 "HTTP Server" 匹配 "Apache HTTP-Server 2.4.49 Path Traversal"，而 "php" 不匹配 "phpMyAdmin"。
+// 公告标题中通常没有规范的厂商名称，因此不比较厂商。
+func (w WatchedProduct) MatchesTitle(title string) bool {
+	_, product, _ := w.Identity()
+	want := titleWords(product)
+	if len(want) == 0 {
+		return false
+	}
+	return strings.Contains(" "+strings.Join(titleWords(title), " ")+" ", " "+strings.Join(want, " ")+" ")
+}
+
+// titleWords 将文本转换为小写并按字母和数字以外的字符切分为单词
+func titleWords(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// WatchlistStore 将关注的产品保存在JSON文件中，用法与SavedSearchStore相同
+type WatchlistStore struct {
+	path string
+}
+
+// NewWatchlistStore 创建使用指定文件的存储
+// path为空时使用配置目录下的watchlist.json
+func NewWatchlistStore(path string) (*WatchlistStore, error) {
+	if path == "" {
+		var err error
+		if path, err = Path(WatchlistFile); err != nil {
+			return nil, err
+		}
+	}
+	return &WatchlistStore{path: path}, nil
+}
+
+// Path 返回存储文件的路径
+func (s *WatchlistStore) Path() string {
+	return s.path
+}
+
+// List 返回所有关注的产品，按名称排序；文件不存在时返回空列表
+func (s *WatchlistStore) List() ([]WatchedProduct, error) {
+	var products []WatchedProduct
+	if _, err := readJSON(s.path, &products); err != nil {
+		return nil, fmt.Errorf("读取关注列表失败: %w", err)
+	}
+	sort.Slice(products, func(i, j int) bool { return products[i].Name < products[j].Name })
+	return products, nil
+}
+
+// Get 返回指定名称的关注产品，不存在时返回ErrWatchNotFound
+func (s *WatchlistStore) Get(name string) (WatchedProduct, error) {
+	products, err := s.List()
+	if err != nil {
+		return WatchedProduct{}, err
+	}
+	for _, p := range products {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+	return WatchedProduct{}, fmt.Errorf("%w: %s", ErrWatchNotFound, name)
+}
+
+// Add 添加一个关注的产品
+// 同名产品已存在时，replace为false则返回错误，为true则覆盖。CreatedAt为零值时设为当前时间。
+func (s *WatchlistStore) Add(product WatchedProduct, replace bool) error {
+	if err := product.Validate(); err != nil {
+		return err
+	}
+	if product.CreatedAt.IsZero() {
+		product.CreatedAt = time.Now()
+	}
+
+	products, err := s.List()
+	if err != nil {
+		return err
+	}
+	for i := range products {
+		if products[i].Name == product.Name {
+			if !replace {
+				return fmt.Errorf("关注的产品已存在: %s", product.Name)
+			}
+			products[i] = product
+			return s.write(products)
+		}
+	}
+	return s.write(append(products, product))
+}
+
+// Remove 删除指定名称的关注产品，不存在时返回ErrWatchNotFound
+func (s *WatchlistStore) Remove(name string) error {
+	products, err := s.List()
+	if err != nil {
+		return err
+	}
+	for i := range products {
+		if products[i].Name == name {
+			return s.write(append(products[:i], products[i+1:]...))
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrWatchNotFound, name)
+}
+
+// write 按名称排序后原子地写入存储文件
+func (s *WatchlistStore) write(products []WatchedProduct) error {
+	sort.Slice(products, func(i, j int) bool { return products[i].Name < products[j].Name })
+	if products == nil {
+		products = []WatchedProduct{}
+	}
+	if err := writeJSON(s.path, products); err != nil {
+		return fmt.Errorf("保存关注列表失败: %w", err)
+	}
+	return nil
+}
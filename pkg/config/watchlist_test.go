@@ -0,0 +1,70 @@
+package config
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchedProductMatches(t *testing.T) {
+	byName := WatchedProduct{Name: "php", Vendor: "PHP", Product: "PHP"}
+	assert.True(t, byName.Matches("php", "php"))
+	assert.False(t, byName.Matches("Zend", "php"))
+
+	anyVendor := WatchedProduct{Name: "server", Product: "HTTP Server"}
+	assert.True(t, anyVendor.Matches("Apache", "http_server"))
+	assert.True(t, anyVendor.Matches("", "HTTP-Server"))
+
+	// CPE优先于厂商和产品名称
+	byCPE := WatchedProduct{Name: "tomcat", Product: "ignored", CPE: "cpe:2.3:a:apache:tomcat:9.0.1:*:*:*:*:*:*:*"}
+	assert.True(t, byCPE.Matches("Apache", "Tomcat"))
+	assert.False(t, byCPE.Matches("Apache", "ignored"))
+	vendor, product, version := byCPE.Identity()
+	assert.Equal(t, []string{"apache", "tomcat", "9.0.1"}, []string{vendor, product, version})
+}
+
+func TestWatchedProductValidate(t *testing.T) {
+	assert.NoError(t, WatchedProduct{Name: "php", Product: "PHP"}.Validate())
+	assert.NoError(t, WatchedProduct{Name: "php", CPE: "cpe:2.3:a:php:php:*:*:*:*:*:*:*:*"}.Validate())
+	assert.Error(t, WatchedProduct{Product: "PHP"}.Validate())
+	assert.Error(t, WatchedProduct{Name: "php", Vendor: "PHP"}.Validate())
+	assert.Error(t, WatchedProduct{Name: "php", CPE: "cpe:/a:php:php"}.Validate())
+	assert.Error(t, WatchedProduct{Name: "php", CPE: "cpe:2.3:a:php:*"}.Validate())
+}
+
+func TestWatchlistStore(t *testing.T) {
+	store, err := NewWatchlistStore(filepath.Join(t.TempDir(), WatchlistFile))
+	require.NoError(t, err)
+
+	products, err := store.List()
+	require.NoError(t, err)
+	assert.Empty(t, products)
+
+	require.NoError(t, store.Add(WatchedProduct{Name: "php", Product: "PHP"}, false))
+	require.NoError(t, store.Add(WatchedProduct{Name: "apache", Vendor: "Apache", Product: "HTTP Server"}, false))
+	assert.Error(t, store.Add(WatchedProduct{Name: "php", Product: "PHP"}, false))
+	require.NoError(t, store.Add(WatchedProduct{Name: "php", Product: "PHP", Version: "8.1"}, true))
+
+	products, err = store.List()
+	require.NoError(t, err)
+	require.Len(t, products, 2)
+	assert.Equal(t, "apache", products[0].Name)
+	assert.Equal(t, "8.1", products[1].Version)
+
+	require.NoError(t, store.Remove("php"))
+	_, err = store.Get("php")
+	assert.True(t, errors.Is(err, ErrWatchNotFound))
+}
+
+func TestWatchedProductMatchesTitle(t *testing.T) {
+	server := WatchedProduct{Name: "httpd", Vendor: "Apache", Product: "HTTP Server"}
+	assert.True(t, server.MatchesTitle("Apache HTTP-Server 2.4.49 Path Traversal"))
+	assert.False(t, server.MatchesTitle("Apache Tomcat HTTP/2 Server DoS"))
+
+	php := WatchedProduct{Name: "php", CPE: "cpe:2.3:a:php:php:8.1.2:*:*:*:*:*:*:*"}
+	assert.True(t, php.MatchesTitle("PHP 8.1.2 Remote Code Execution"))
+	assert.False(t, php.MatchesTitle("phpMyAdmin 5.2 XSS"))
+}
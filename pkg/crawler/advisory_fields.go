@@ -0,0 +1,219 @@
+package crawler
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// 公告正文的长度限制，避免把整段PoC当作描述
+const maxDescriptionRunes = 2000
+
+var (
+	// advisoryFieldPattern 匹配公告头部 "字段: 值" 格式的行
+	// 例如 "# Version: 4.4.6"、"[+] Tested on: Windows 10"、"Affected Versions : <= 2.1"
+	advisoryFieldPattern = regexp.MustCompile(`^[\s#*\[\]+\-|>]*([A-Za-z][A-Za-z ()/]{1,40}?)\s*[:\]]\s*(.*)$`)
+
+	// descriptionHeaderPattern 匹配描述小节的标题，冒号后面可以直接跟描述
+	descriptionHeaderPattern = regexp.MustCompile(`(?i)^[\s#*\[\]=\-]*(?:\d+\.?\s*)?(?:vulnerability\s+|technical\s+)?(?:description|summary|overview|details|abstract)\s*(?:\]\s*)?(?::\s*(.*))?[\s=\-#\]]*$`)
+
+	// sectionHeaderPattern 匹配其他小节的标题，用于判断描述在哪里结束
+	// 例如 "Proof of Concept:"、"[Solution]"、"## Timeline"、"3. Impact"
+	sectionHeaderPattern = regexp.MustCompile(`^(?:\s*#+\s*.+|\s*\[[^\]]{2,40}\]\s*|\s*(?:\d+\.\s*)?[A-Z][A-Za-z0-9 /()&-]{1,40}:\s*|\s*\d+\.\s+[A-Z][A-Za-z0-9 /()&-]{1,40})$`)
+
+	// titleVersionPattern 从标题中提取版本，例如 "<= 4.4.6"、"2.1.0"、"1.0 - 1.3"
+	titleVersionPattern = regexp.MustCompile(`(?:(?:<=|>=|<|>|≤)\s*)?\bv?\d+(?:\.\d+)+[a-z0-9]*(?:\s*(?:-|to|through)\s*v?\d+(?:\.\d+)+[a-z0-9]*)?`)
+)
+
+// versionFields 是公告头部中表示受影响版本的字段名(小写)
+var versionFields = map[string]bool{
+	"version":             true,
+	"versions":            true,
+	"affected version":    true,
+	"affected versions":   true,
+	"affected version(s)": true,
+	"vulnerable version":  true,
+	"vulnerable versions": true,
+	"software version":    true,
+	"product version":     true,
+}
+
+// platformFields 是公告头部中表示平台的字段名(小写)
+var platformFields = map[string]bool{
+	"platform":         true,
+	"tested on":        true,
+	"os":               true,
+	"operating system": true,
+}
+
+// knownPlatforms 是没有平台字段时在正文开头查找的平台名称，按优先级排列
+var knownPlatforms = []string{"Windows", "Linux", "macOS", "FreeBSD", "OpenBSD", "NetBSD", "Solaris", "AIX", "Android", "iOS", "Unix"}
+
+// advisoryFields 是从公告正文中提取的字段
+type advisoryFields struct {
+	Description      string
+	AffectedVersions []string
+	Platform         string
+}
+
+// parseAdvisoryFields 从漏洞详情页的正文和标题中提取描述、受影响版本和平台
+// 正文是作者提交的自由文本，没有固定格式，这里只识别常见的写法：
+//   - 描述：Description、Summary、Overview 等小节，没有时使用正文中第一段像文字(而不是代码)的段落
+//   - 受影响版本：Version、Affected Versions 等字段，没有时从标题中提取版本号
+//   - 平台：Platform、Tested on 等字段，没有时在正文开头查找常见的操作系统名称
+func parseAdvisoryFields(content, title string) advisoryFields {
+	return parseAdvisoryFieldsProvenance(content, title, nil)
+}
+
+// parseAdvisoryFieldsProvenance 与 parseAdvisoryFields 相同，prov不为nil时记录每个字段使用的规则
+func parseAdvisoryFieldsProvenance(content, title string, prov Provenance) advisoryFields {
+	lines := strings.Split(content, "\n")
+	var fields advisoryFields
+
+	for _, line := range lines {
+		m := advisoryFieldPattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		name, value := strings.ToLower(strings.TrimSpace(m[1])), strings.TrimSpace(m[2])
+		if value == "" {
+			continue
+		}
+		switch {
+		case versionFields[name] && fields.AffectedVersions == nil:
+			fields.AffectedVersions = splitVersions(value)
+			prov.set("affected_versions", "正文字段 "+strings.TrimSpace(m[1]))
+		case platformFields[name] && fields.Platform == "":
+			fields.Platform = value
+			prov.set("platform", "正文字段 "+strings.TrimSpace(m[1]))
+		}
+	}
+	if fields.AffectedVersions == nil {
+		if v := titleVersionPattern.FindString(title); v != "" {
+			fields.AffectedVersions = []string{v}
+			prov.set("affected_versions", "标题中的版本号")
+		}
+	}
+	if fields.Platform == "" {
+		if fields.Platform = findPlatform(lines); fields.Platform != "" {
+			prov.set("platform", "正文前20行中的操作系统名称")
+		}
+	}
+
+	if fields.Description = descriptionSection(lines); fields.Description != "" {
+		prov.set("description", "正文的描述小节")
+	} else if fields.Description = firstProseParagraph(content); fields.Description != "" {
+		prov.set("description", "正文中第一段文字")
+	}
+	fields.Description = truncateRunes(fields.Description, maxDescriptionRunes)
+	return fields
+}
+
+// splitVersions 将版本字段按逗号或分号拆分，只有每一部分都含有数字时才拆分，避免拆开 "4.x, all branches" 这样的描述
+func splitVersions(value string) []string {
+	parts := strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ';' })
+	var versions []string
+	for _, p := range parts {
+		p = strings.TrimSpace(p)
+		if !strings.ContainsAny(p, "0123456789") {
+			return []string{value}
+		}
+		versions = append(versions, p)
+	}
+	return versions
+}
+
+// findPlatform 在正文的前20行中查找常见的操作系统名称
+func findPlatform(lines []string) string {
+	head := strings.ToLower(strings.Join(lines[:min(len(lines), 20)], "\n"))
+	words := strings.FieldsFunc(head, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+	seen := make(map[string]bool, len(words))
+	for _, w := range words {
+		seen[w] = true
+	}
+	for _, p := range knownPlatforms {
+		if seen[strings.ToLower(p)] {
+			return p
+		}
+	}
+	return ""
+}
+
+// descriptionSection 返回描述小节的内容，直到下一个小节标题
+func descriptionSection(lines []string) string {
+	for i, line := range lines {
+		m := descriptionHeaderPattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		var collected []string
+		if inline := strings.TrimSpace(m[1]); inline != "" {
+			collected = append(collected, inline)
+		}
+		for _, next := range lines[i+1:] {
+			trimmed := strings.TrimSpace(next)
+			if isUnderline(trimmed) {
+				continue
+			}
+			if trimmed != "" && sectionHeaderPattern.MatchString(next) && len(collected) > 0 {
+				break
+			}
+			collected = append(collected, trimmed)
+		}
+		if text := joinParagraphs(collected); text != "" {
+			return text
+		}
+	}
+	return ""
+}
+
+// firstProseParagraph 返回正文中第一段像文字的段落：至少8个单词，并且不含代码常用的符号
+func firstProseParagraph(content string) string {
+	for _, para := range strings.Split(content, "\n\n") {
+		para = strings.TrimSpace(para)
+		if para == "" || strings.HasPrefix(para, "#") || strings.HasPrefix(para, "//") {
+			continue
+		}
+		if strings.ContainsAny(para, ";{}$<>=|\\") || len(strings.Fields(para)) < 8 {
+			continue
+		}
+		return joinParagraphs(strings.Split(para, "\n"))
+	}
+	return ""
+}
+
+// joinParagraphs 将行合并为段落：段落内的换行替换为空格，段落之间保留一个空行
+func joinParagraphs(lines []string) string {
+	var paragraphs []string
+	var current []string
+	flush := func() {
+		if len(current) > 0 {
+			paragraphs = append(paragraphs, strings.Join(current, " "))
+			current = nil
+		}
+	}
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			flush()
+			continue
+		}
+		current = append(current, line)
+	}
+	flush()
+	return strings.Join(paragraphs, "\n\n")
+}
+
+// isUnderline 判断是否为 "=====" 或 "-----" 这样的标题下划线
+func isUnderline(s string) bool {
+	return len(s) >= 3 && strings.Trim(s, "=-_*~") == ""
+}
+
+// truncateRunes 将s截断为最多n个字符，截断时末尾加上 "..."
+func truncateRunes(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return strings.TrimSpace(string(r[:n-3])) + "..."
+}
@@ -0,0 +1,89 @@
+package crawler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAdvisoryFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		title    string
+		expected advisoryFields
+	}{
+		{
+			name: "Exploit-DB风格的头部",
+			content: `# Exploit Title: Acme CMS 2.1 - SQL Injection
+# Date: 2024-04-01
+# Version: 2.0, 2.1
+# Tested on: Ubuntu 22.04
+# Description: The id parameter of news.php is not sanitized
+# before being used in a SQL query.
+
+GET /news.php?id=1' HTTP/1.1`,
+			title: "Acme CMS 2.1 - SQL Injection",
+			expected: advisoryFields{
+				Description:      "The id parameter of news.php is not sanitized",
+				AffectedVersions: []string{"2.0", "2.1"},
+				Platform:         "Ubuntu 22.04",
+			},
+		},
+		{
+			name: "带下划线的描述小节",
+			content: `Advisory: Foo Server Path Traversal
+
+Affected Versions: <= 3.4.1
+
+Description
+===========
+Foo Server does not normalize request paths,
+allowing attackers to read arbitrary files.
+
+The issue is reachable without authentication.
+
+Proof of Concept:
+curl http://host/../../etc/passwd`,
+			title: "Foo Server Path Traversal",
+			expected: advisoryFields{
+				Description:      "Foo Server does not normalize request paths, allowing attackers to read arbitrary files.\n\nThe issue is reachable without authentication.",
+				AffectedVersions: []string{"<= 3.4.1"},
+			},
+		},
+		{
+			name: "没有小节时使用第一段文字",
+			content: `<?php echo 1; ?>
+
+This plugin allows unauthenticated users to upload arbitrary files to the uploads directory.
+
+Run it on Linux.`,
+			title: "Bar Plugin 1.0 - 1.3 Arbitrary File Upload",
+			expected: advisoryFields{
+				Description:      "This plugin allows unauthenticated users to upload arbitrary files to the uploads directory.",
+				AffectedVersions: []string{"1.0 - 1.3"},
+				Platform:         "Linux",
+			},
+		},
+		{
+			name:     "没有可提取的字段",
+			content:  "int main() { return 0; }",
+			title:    "Crash PoC",
+			expected: advisoryFields{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, parseAdvisoryFields(tt.content, tt.title))
+		})
+	}
+}
+
+func TestParseAdvisoryFieldsTruncatesDescription(t *testing.T) {
+	content := "Description: " + strings.Repeat("word ", 1000)
+	fields := parseAdvisoryFields(content, "")
+	assert.Len(t, []rune(fields.Description), maxDescriptionRunes)
+	assert.True(t, strings.HasSuffix(fields.Description, "..."))
+}
@@ -0,0 +1,245 @@
+package crawler
+
+import (
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// HTMLArchiveClient 包装HTTPClient，将获取到的原始HTML按日期保存到归档目录中
+// 文件保存为 <目录>/<YYYY-MM-DD>/<路径>.html，同一天重复获取的页面会覆盖之前的文件。
+// 归档会持续增长，可以配合 Prune 和 RetentionPolicy.RawHTML 定期清理。
+// 开启压缩时保存为zstd格式的 <路径>.html.zst，文件开头的可跳过帧中记录原始内容的SHA-256，
+// 内容没有变化的页面不会重复写入；使用 ReadArchivedPage 读取时会自动解压并校验。
+type HTMLArchiveClient struct {
+	inner    HTTPClient
+	dir      string
+	compress bool
+	fileMode os.FileMode
+	dirMode  os.FileMode
+	now      func() time.Time
+}
+
+// NewHTMLArchiveClient 创建归档客户端，使用默认的文件和目录权限
+func NewHTMLArchiveClient(inner HTTPClient, dir string) *HTMLArchiveClient {
+	return &HTMLArchiveClient{inner: inner, dir: dir, fileMode: DefaultFileMode, dirMode: DefaultDirMode, now: time.Now}
+}
+
+// WithHTMLArchive 将爬取到的原始HTML保存到dir目录中，便于之后重新解析
+// 归档使用爬虫配置的输出权限，与其他选项的顺序无关。
+//
+// 示例:
+//
+//	c := NewCrawler(WithHTMLArchive("data/html"))
+func WithHTMLArchive(dir string) CrawlerOption {
+	return func(c *Crawler) {
+		c.archiveDir = dir
+	}
+}
+
+// WithHTMLArchiveCompression 使用zstd压缩归档的HTML并记录内容的SHA-256，需要同时使用 WithHTMLArchive
+// HTML的压缩率很高，归档通常可以缩小到原来的三分之一以下。
+func WithHTMLArchiveCompression(enabled bool) CrawlerOption {
+	return func(c *Crawler) {
+		c.archiveCompress = enabled
+	}
+}
+
+// GetPage 获取页面内容并保存到归档目录，保存失败时返回错误
+func (a *HTMLArchiveClient) GetPage(path string) (string, error) {
+	content, err := a.inner.GetPage(path)
+	if err != nil {
+		return content, err
+	}
+	return content, a.archive(path, content)
+}
+
+// GetPageMirror 获取页面内容和返回页面的镜像并保存到归档目录，被包装的客户端不支持镜像时镜像为空
+func (a *HTMLArchiveClient) GetPageMirror(path string) (string, string, error) {
+	inner, ok := a.inner.(MirrorClient)
+	if !ok {
+		content, err := a.GetPage(path)
+		return content, "", err
+	}
+	content, mirror, err := inner.GetPageMirror(path)
+	if err != nil {
+		return content, mirror, err
+	}
+	return content, mirror, a.archive(path, content)
+}
+
+// archive 将页面内容保存到当天的归档目录
+func (a *HTMLArchiveClient) archive(path, content string) error {
+	now := a.now()
+	file := filepath.Join(a.dir, now.Format("2006-01-02"), archiveFileName(path))
+	data := []byte(content)
+	if a.compress {
+		hash := contentHash(data)
+		file += ".zst"
+		// 当天已经归档过相同内容时不再重复写入
+		if existing, err := archivedHash(file); err == nil && existing == hash {
+			return nil
+		}
+		data = zstdPage(data, hash)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), a.dirMode); err != nil {
+		return fmt.Errorf("创建归档目录失败: %w", err)
+	}
+	if err := WriteFileAtomic(file, data, a.fileMode, false); err != nil {
+		return fmt.Errorf("归档页面失败: %w", err)
+	}
+	return nil
+}
+
+// GetBaseURL 返回被包装客户端的基础URL
+func (a *HTMLArchiveClient) GetBaseURL() string {
+	return a.inner.GetBaseURL()
+}
+
+// Stats 返回被包装客户端的请求和限速状态，被包装的客户端不支持时返回零值
+func (a *HTMLArchiveClient) Stats() ClientStats {
+	if inner, ok := a.inner.(StatsClient); ok {
+		return inner.Stats()
+	}
+	return ClientStats{Remaining: -1}
+}
+
+// archiveFileName 将请求路径转换为文件名，例如 /issue/WLB-2024040015 转换为 issue_WLB-2024040015.html
+func archiveFileName(path string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '.':
+			return r
+		}
+		return '_'
+	}, strings.Trim(path, "/"))
+	if name == "" {
+		name = "index"
+	}
+	if len(name) > 200 {
+		name = name[:200]
+	}
+	return name + ".html"
+}
+
+// hashPrefix 是归档文件中记录的内容哈希的前缀
+const hashPrefix = "sha256:"
+
+const (
+	// zstdMagic 是zstd帧的魔数
+	zstdMagic = 0xFD2FB528
+	// zstdHashFrameMagic 是记录内容哈希的可跳过帧的魔数，zstd规定 0x184D2A50~0x184D2A5F 为可跳过帧，
+	// zstd命令行工具和解码库读取时会忽略这些帧
+	zstdHashFrameMagic = 0x184D2A50
+)
+
+// zstdEncoder 和 zstdDecoder 可以并发使用，只用 EncodeAll 和 DecodeAll
+var (
+	zstdEncoder, _ = zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedBestCompression))
+	zstdDecoder, _ = zstd.NewReader(nil)
+)
+
+// contentHash 返回内容的SHA-256，格式为16进制字符串
+func contentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// zstdPage 压缩页面内容，在压缩数据前写入一个记录内容哈希的可跳过帧
+func zstdPage(data []byte, hash string) []byte {
+	meta := hashPrefix + hash
+	out := make([]byte, 8, 8+len(meta)+len(data)/4)
+	binary.LittleEndian.PutUint32(out, zstdHashFrameMagic)
+	binary.LittleEndian.PutUint32(out[4:], uint32(len(meta)))
+	out = append(out, meta...)
+	return zstdEncoder.EncodeAll(data, out)
+}
+
+// readZstdHash 读取可跳过帧中记录的内容哈希，没有记录时返回空字符串且不消耗数据
+func readZstdHash(r *bufio.Reader) (string, error) {
+	header, err := r.Peek(8)
+	if err != nil || binary.LittleEndian.Uint32(header) != zstdHashFrameMagic {
+		return "", nil
+	}
+	size := binary.LittleEndian.Uint32(header[4:])
+	if size > 1024 {
+		return "", fmt.Errorf("归档页面的哈希帧过大: %d 字节", size)
+	}
+	frame := make([]byte, 8+int(size))
+	if _, err := io.ReadFull(r, frame); err != nil {
+		return "", err
+	}
+	hash, _ := strings.CutPrefix(string(frame[8:]), hashPrefix)
+	return hash, nil
+}
+
+// archivedHash 返回压缩归档文件中记录的内容哈希，只读取文件头
+func archivedHash(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	return readZstdHash(bufio.NewReader(f))
+}
+
+// ReadArchivedPage 读取归档的HTML页面，用于重新解析
+// zstd和gzip压缩的文件(按文件内容判断，与扩展名无关)会自动解压，记录了内容哈希时会校验内容是否完整。
+// 旧版本保存的 .html.gz 归档(gzip头的注释中记录哈希)同样可以读取。
+//
+// 示例:
+//
+//	html, err := crawler.ReadArchivedPage("data/html/2024-04-01/issue_WLB-2024040015.html.zst")
+func ReadArchivedPage(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	magic, _ := r.Peek(4)
+	var data []byte
+	var hash string
+	switch {
+	case len(magic) >= 2 && magic[0] == 0x1f && magic[1] == 0x8b:
+		zr, err := gzip.NewReader(r)
+		if err != nil {
+			return "", fmt.Errorf("解压归档页面失败: %w", err)
+		}
+		if data, err = io.ReadAll(zr); err != nil {
+			return "", fmt.Errorf("解压归档页面失败: %w", err)
+		}
+		hash, _ = strings.CutPrefix(zr.Comment, hashPrefix)
+	case len(magic) == 4 && (binary.LittleEndian.Uint32(magic) == zstdMagic || binary.LittleEndian.Uint32(magic) == zstdHashFrameMagic):
+		if hash, err = readZstdHash(r); err != nil {
+			return "", fmt.Errorf("解压归档页面失败: %w", err)
+		}
+		compressed, err := io.ReadAll(r)
+		if err != nil {
+			return "", err
+		}
+		if data, err = zstdDecoder.DecodeAll(compressed, nil); err != nil {
+			return "", fmt.Errorf("解压归档页面失败: %w", err)
+		}
+	default:
+		data, err := io.ReadAll(r)
+		return string(data), err
+	}
+
+	if hash != "" && hash != contentHash(data) {
+		return "", fmt.Errorf("归档页面的内容哈希不匹配，文件可能已损坏: %s", path)
+	}
+	return string(data), nil
+}
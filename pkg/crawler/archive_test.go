@@ -0,0 +1,143 @@
+package crawler
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTMLArchiveClient(t *testing.T) {
+	dir := t.TempDir()
+	client := NewHTMLArchiveClient(&mockClient{getPageFunc: func(string) (string, error) { return "<html>ok</html>", nil }}, dir)
+	client.now = func() time.Time { return time.Date(2024, 4, 1, 12, 0, 0, 0, time.UTC) }
+
+	content, err := client.GetPage("/issue/WLB-2024040015")
+	require.NoError(t, err)
+	assert.Equal(t, "<html>ok</html>", content)
+
+	data, err := os.ReadFile(filepath.Join(dir, "2024-04-01", "issue_WLB-2024040015.html"))
+	require.NoError(t, err)
+	assert.Equal(t, "<html>ok</html>", string(data))
+	assert.Equal(t, "index.html", archiveFileName("/"))
+}
+
+func TestHTMLArchiveClientCompression(t *testing.T) {
+	dir := t.TempDir()
+	html := "<html>" + strings.Repeat("<div>exploit</div>", 200) + "</html>"
+	calls := 0
+	client := NewHTMLArchiveClient(&mockClient{getPageFunc: func(string) (string, error) {
+		calls++
+		return html, nil
+	}}, dir)
+	client.compress = true
+	client.now = func() time.Time { return time.Date(2024, 4, 1, 12, 0, 0, 0, time.UTC) }
+
+	_, err := client.GetPage("/issue/WLB-2024040015")
+	require.NoError(t, err)
+	file := filepath.Join(dir, "2024-04-01", "issue_WLB-2024040015.html.zst")
+	info, err := os.Stat(file)
+	require.NoError(t, err)
+	assert.Less(t, info.Size(), int64(len(html)/10))
+
+	hash, err := archivedHash(file)
+	require.NoError(t, err)
+	assert.Equal(t, contentHash([]byte(html)), hash)
+
+	content, err := ReadArchivedPage(file)
+	require.NoError(t, err)
+	assert.Equal(t, html, content)
+
+	// 内容相同时不重复写入
+	past := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(file, past, past))
+	_, err = client.GetPage("/issue/WLB-2024040015")
+	require.NoError(t, err)
+	info2, err := os.Stat(file)
+	require.NoError(t, err)
+	assert.WithinDuration(t, past, info2.ModTime(), time.Second)
+	assert.Equal(t, 2, calls)
+
+	// 未压缩的文件原样读取
+	plain := filepath.Join(dir, "plain.html")
+	require.NoError(t, os.WriteFile(plain, []byte(html), 0644))
+	content, err = ReadArchivedPage(plain)
+	require.NoError(t, err)
+	assert.Equal(t, html, content)
+
+	// 内容与记录的哈希不一致
+	bad := filepath.Join(dir, "bad.html.zst")
+	require.NoError(t, os.WriteFile(bad, zstdPage([]byte("tampered"), contentHash([]byte(html))), 0644))
+	_, err = ReadArchivedPage(bad)
+	assert.ErrorContains(t, err, "哈希不匹配")
+
+	// 没有哈希帧的zstd文件，例如用zstd命令行工具压缩的页面
+	noHash := filepath.Join(dir, "nohash.html.zst")
+	require.NoError(t, os.WriteFile(noHash, zstdEncoder.EncodeAll([]byte(html), nil), 0644))
+	content, err = ReadArchivedPage(noHash)
+	require.NoError(t, err)
+	assert.Equal(t, html, content)
+}
+
+func TestReadArchivedPageGzip(t *testing.T) {
+	html := "<html>" + strings.Repeat("<div>exploit</div>", 200) + "</html>"
+	// 旧版本的归档使用gzip压缩，gzip头的注释中记录内容哈希
+	writeGzip := func(name, content, hash string) string {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		zw.Comment = hashPrefix + hash
+		_, err := zw.Write([]byte(content))
+		require.NoError(t, err)
+		require.NoError(t, zw.Close())
+		path := filepath.Join(t.TempDir(), name)
+		require.NoError(t, os.WriteFile(path, buf.Bytes(), 0644))
+		return path
+	}
+
+	content, err := ReadArchivedPage(writeGzip("issue_WLB-2024040015.html.gz", html, contentHash([]byte(html))))
+	require.NoError(t, err)
+	assert.Equal(t, html, content)
+
+	_, err = ReadArchivedPage(writeGzip("bad.html.gz", "tampered", contentHash([]byte(html))))
+	assert.ErrorContains(t, err, "哈希不匹配")
+}
+
+func TestHTMLArchiveClientMirror(t *testing.T) {
+	page, err := os.ReadFile(filepath.Join(benchmarkFixtureDir, "vul-detail-response.html"))
+	if err != nil {
+		t.Skip("跳过测试，测试文件不存在")
+	}
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer down.Close()
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(page)
+	}))
+	defer mirror.Close()
+
+	// 归档客户端转发镜像信息，结果记录返回页面的镜像，链接仍然使用基础URL
+	dir := t.TempDir()
+	client := NewClient(WithBaseURL(down.URL), WithRetry(0, 0), WithMirrors(Mirror{BaseURL: mirror.URL}))
+	c := NewCrawler(WithHTTPClient(client), WithHTMLArchive(dir))
+	result, err := c.CrawlVulnerabilityDetail("/issue/WLB-2007030137", "")
+	require.NoError(t, err)
+	assert.Equal(t, mirror.URL, result.Mirror)
+	assert.True(t, strings.HasPrefix(result.URL, down.URL), result.URL)
+	files, err := filepath.Glob(filepath.Join(dir, "*", "issue_WLB-2007030137.html"))
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+
+	// 从基础URL获取时不记录镜像
+	result, err = NewCrawler(WithHTTPClient(NewClient(WithBaseURL(mirror.URL)))).CrawlVulnerabilityDetail("/issue/WLB-2007030137", "")
+	require.NoError(t, err)
+	assert.Empty(t, result.Mirror)
+}
@@ -0,0 +1,169 @@
+package crawler
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+// TechniqueClassifier 将漏洞映射为MITRE ATT&CK技术编号
+// 可以实现该接口接入其他分类方式，例如基于机器学习的分类器
+type TechniqueClassifier interface {
+	// Classify 返回漏洞对应的技术编号，例如 "T1190"，没有匹配时返回nil
+	Classify(v *model.Vulnerability) []string
+}
+
+// TechniqueRule 是关键词分类规则，标题、标签或正文中出现任一关键词时匹配该技术
+type TechniqueRule struct {
+	Technique string   `json:"technique"`      // ATT&CK技术编号，例如 "T1190" 或子技术 "T1505.003"
+	Name      string   `json:"name,omitempty"` // 技术名称，仅用于说明
+	Keywords  []string `json:"keywords"`       // 关键词，不区分大小写，按完整单词匹配
+}
+
+// DefaultTechniqueRules 是内置的分类规则，覆盖公告中最常见的漏洞类型
+var DefaultTechniqueRules = []TechniqueRule{
+	{Technique: "T1190", Name: "Exploit Public-Facing Application", Keywords: []string{
+		"sql injection", "sqli", "remote code execution", "rce", "path traversal", "directory traversal",
+		"file inclusion", "lfi", "rfi", "ssrf", "server side request forgery", "xxe", "deserialization",
+		"authentication bypass", "auth bypass", "arbitrary file upload", "unrestricted file upload",
+	}},
+	{Technique: "T1059", Name: "Command and Scripting Interpreter", Keywords: []string{
+		"command injection", "command execution", "os command", "code injection", "template injection", "ssti",
+	}},
+	{Technique: "T1189", Name: "Drive-by Compromise", Keywords: []string{
+		"xss", "cross site scripting", "cross-site scripting",
+	}},
+	{Technique: "T1068", Name: "Exploitation for Privilege Escalation", Keywords: []string{
+		"privilege escalation", "local privilege", "elevation of privilege", "privesc",
+	}},
+	{Technique: "T1203", Name: "Exploitation for Client Execution", Keywords: []string{
+		"buffer overflow", "heap overflow", "stack overflow", "use after free", "memory corruption",
+		"out of bounds write", "integer overflow", "format string",
+	}},
+	{Technique: "T1212", Name: "Exploitation for Credential Access", Keywords: []string{
+		"credential disclosure", "password disclosure", "hardcoded credentials", "hard coded credentials",
+		"hardcoded password", "hard coded password", "cleartext password", "plaintext password",
+	}},
+	{Technique: "T1078", Name: "Valid Accounts", Keywords: []string{
+		"default credentials", "default password", "weak password",
+	}},
+	{Technique: "T1505.003", Name: "Web Shell", Keywords: []string{
+		"web shell", "webshell", "shell upload", "arbitrary file upload", "unrestricted file upload",
+	}},
+	{Technique: "T1005", Name: "Data from Local System", Keywords: []string{
+		"arbitrary file read", "arbitrary file download", "file disclosure", "local file disclosure",
+		"information disclosure", "sensitive data exposure",
+	}},
+	{Technique: "T1499", Name: "Endpoint Denial of Service", Keywords: []string{
+		"denial of service", "dos", "resource exhaustion", "infinite loop", "crash",
+	}},
+	{Technique: "T1185", Name: "Browser Session Hijacking", Keywords: []string{
+		"csrf", "cross site request forgery", "cross-site request forgery", "session fixation", "session hijacking",
+	}},
+}
+
+// KeywordClassifier 是基于关键词规则的分类器
+type KeywordClassifier struct {
+	rules []TechniqueRule
+}
+
+// NewKeywordClassifier 使用指定的规则创建分类器，rules为空时使用DefaultTechniqueRules
+//
+// 示例:
+//
+//	c := NewCrawler(WithTechniqueClassifier(NewKeywordClassifier(nil)))
+func NewKeywordClassifier(rules []TechniqueRule) *KeywordClassifier {
+	if len(rules) == 0 {
+		rules = DefaultTechniqueRules
+	}
+	normalized := make([]TechniqueRule, 0, len(rules))
+	for _, r := range rules {
+		rule := TechniqueRule{Technique: strings.ToUpper(strings.TrimSpace(r.Technique)), Name: r.Name}
+		for _, kw := range r.Keywords {
+			if kw = normalizeTitle(kw); kw != "" {
+				rule.Keywords = append(rule.Keywords, kw)
+			}
+		}
+		if rule.Technique != "" && len(rule.Keywords) > 0 {
+			normalized = append(normalized, rule)
+		}
+	}
+	return &KeywordClassifier{rules: normalized}
+}
+
+// Classify 在漏洞的标题、标签和正文中查找关键词，返回按编号排序的技术编号
+func (k *KeywordClassifier) Classify(v *model.Vulnerability) []string {
+	// 前后加空格，按完整单词匹配，避免 "dos" 匹配到 "windows"
+	text := " " + normalizeTitle(strings.Join(append([]string{v.Title, v.Content}, v.Tags...), " ")) + " "
+
+	found := make(map[string]bool)
+	for _, rule := range k.rules {
+		if found[rule.Technique] {
+			continue
+		}
+		for _, kw := range rule.Keywords {
+			if strings.Contains(text, " "+kw+" ") {
+				found[rule.Technique] = true
+				break
+			}
+		}
+	}
+	if len(found) == 0 {
+		return nil
+	}
+
+	techniques := make([]string, 0, len(found))
+	for t := range found {
+		techniques = append(techniques, t)
+	}
+	sort.Strings(techniques)
+	return techniques
+}
+
+// LoadTechniqueRules 从JSON文件读取分类规则，文件内容为TechniqueRule数组
+//
+// 示例文件:
+//
+//	[{"technique": "T1190", "keywords": ["sql injection", "rce"]}]
+func LoadTechniqueRules(path string) ([]TechniqueRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取ATT&CK规则文件失败: %w", err)
+	}
+	var rules []TechniqueRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("解析ATT&CK规则文件失败: %w", err)
+	}
+	return rules, nil
+}
+
+// WithTechniqueClassifier 设置ATT&CK技术分类器
+// 设置后爬取到的漏洞(列表、详情、作者和CVE详情中的漏洞)都会填充Techniques字段，默认不分类。
+//
+// 示例:
+//
+//	c := NewCrawler(WithTechniqueClassifier(NewKeywordClassifier(nil)))
+func WithTechniqueClassifier(classifier TechniqueClassifier) CrawlerOption {
+	return func(c *Crawler) {
+		c.classifier = classifier
+	}
+}
+
+// classify 为漏洞填充DerivedRisk字段，配置了分类器时同时填充Techniques字段
+func (c *Crawler) classify(items []model.Vulnerability) {
+	for i := range items {
+		c.classifyOne(&items[i])
+	}
+}
+
+// classifyOne 为单条漏洞填充DerivedRisk字段，配置了分类器时同时填充Techniques字段
+func (c *Crawler) classifyOne(v *model.Vulnerability) {
+	DeriveRisk(v)
+	if c.classifier != nil {
+		v.Techniques = c.classifier.Classify(v)
+	}
+}
@@ -0,0 +1,56 @@
+package crawler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+func TestKeywordClassifier(t *testing.T) {
+	c := NewKeywordClassifier(nil)
+
+	assert.Equal(t, []string{"T1190"}, c.Classify(&model.Vulnerability{Title: "WordPress Plugin 1.2 SQL Injection"}))
+	assert.Equal(t, []string{"T1190", "T1505.003"}, c.Classify(&model.Vulnerability{Title: "Shop CMS Arbitrary File Upload"}))
+	assert.Equal(t, []string{"T1068"}, c.Classify(&model.Vulnerability{Title: "Linux Kernel", Tags: []string{"Local Privilege Escalation"}}))
+	assert.Equal(t, []string{"T1189"}, c.Classify(&model.Vulnerability{Title: "Joomla", Content: "A stored Cross-Site Scripting issue"}))
+	// 按完整单词匹配，"Windows" 不匹配 "dos"
+	assert.Nil(t, c.Classify(&model.Vulnerability{Title: "Windows Explorer Spoofing"}))
+}
+
+func TestCustomTechniqueRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[{"technique":"t1210","keywords":["SMB"]},{"technique":"","keywords":["x"]}]`), 0644))
+
+	rules, err := LoadTechniqueRules(path)
+	require.NoError(t, err)
+	c := NewKeywordClassifier(rules)
+	assert.Equal(t, []string{"T1210"}, c.Classify(&model.Vulnerability{Title: "Samba SMB Buffer Overflow"}))
+
+	_, err = LoadTechniqueRules(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestCrawlerTechniqueClassifier(t *testing.T) {
+	parser := &mockParser{
+		parseListPageFunc: func(string) (*model.VulnerabilityList, error) {
+			return &model.VulnerabilityList{Items: []model.Vulnerability{{Title: "Apache RCE"}, {Title: "Readme"}}}, nil
+		},
+	}
+	client := &mockClient{getPageFunc: func(string) (string, error) { return "", nil }}
+
+	// 默认不分类
+	list, err := NewCrawler(WithHTTPClient(client), WithCustomParser(parser)).CrawlPage("/exploit/1", "")
+	require.NoError(t, err)
+	assert.Nil(t, list.Items[0].Techniques)
+
+	c := NewCrawler(WithHTTPClient(client), WithCustomParser(parser), WithTechniqueClassifier(NewKeywordClassifier(nil)))
+	list, err = c.CrawlPage("/exploit/1", "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"T1190"}, list.Items[0].Techniques)
+	assert.Nil(t, list.Items[1].Techniques)
+}
@@ -7,32 +7,11 @@ import (
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/text/language"
 
 	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
 )
 
-// 国家代码映射
-var countryCodeMap = map[string]string{
-	"XX": "未知",
-	"US": "美国",
-	"CN": "中国",
-	"UK": "英国",
-	"RU": "俄罗斯",
-	"FR": "法国",
-	"DE": "德国",
-	"JP": "日本",
-	"CA": "加拿大",
-	"AU": "澳大利亚",
-	"BR": "巴西",
-	"IN": "印度",
-	"IT": "意大利",
-	"ES": "西班牙",
-	"NL": "荷兰",
-	"SE": "瑞典",
-	"KR": "韩国",
-	"CH": "瑞士",
-}
-
 // AuthorParser 用于解析作者信息页面的专用解析器
 // 负责从HTML页面中提取作者的详细信息和发布的漏洞列表
 //
@@ -56,11 +35,42 @@ var countryCodeMap = map[string]string{
 // 2. 支持多种日期格式
 // 3. 自动补全URL（如作者头像、漏洞链接等）
 type AuthorParser struct {
+	locale language.Tag // 本地化国家名称使用的语言，未设置时只输出英文名称
+	labels *LabelMapper // 标签映射表，为nil时使用内置映射表
+}
+
+// AuthorParserOption 是设置AuthorParser选项的函数类型
+type AuthorParserOption func(*AuthorParser)
+
+// WithAuthorLocale 设置本地化国家名称使用的语言
+// 设置后除英文国家名称外，还会在CountryLocalized中输出该语言的国家名称。
+// 无法识别的语言标记会被忽略。
+//
+// 示例:
+//
+//	parser := NewAuthorParser(WithAuthorLocale("zh"))
+func WithAuthorLocale(locale string) AuthorParserOption {
+	return func(p *AuthorParser) {
+		if tag, err := language.Parse(locale); err == nil {
+			p.locale = tag
+		}
+	}
+}
+
+// WithAuthorLabels 设置解析风险级别和标签时使用的映射表，为nil时使用内置映射表
+func WithAuthorLabels(m *LabelMapper) AuthorParserOption {
+	return func(p *AuthorParser) {
+		p.labels = m
+	}
 }
 
 // NewAuthorParser 创建一个新的作者页面解析器
-func NewAuthorParser() *AuthorParser {
-	return &AuthorParser{}
+func NewAuthorParser(options ...AuthorParserOption) *AuthorParser {
+	p := &AuthorParser{}
+	for _, option := range options {
+		option(p)
+	}
+	return p
 }
 
 // Parse 解析作者信息页面，提取作者详细信息和漏洞列表
@@ -129,6 +139,7 @@ func (p *AuthorParser) Parse(doc *goquery.Document) (*model.AuthorProfile, error
 	countryImg := doc.Find("img[src*='flags/']").First()
 	countryCode := ""
 	if src, exists := countryImg.Attr("src"); exists {
+		profile.FlagURL = imageURL(src)
 		// 从图片URL中提取国家代码
 		re := regexp.MustCompile(`flags/(\w+)\.png`)
 		if matches := re.FindStringSubmatch(src); len(matches) > 1 {
@@ -136,10 +147,19 @@ func (p *AuthorParser) Parse(doc *goquery.Document) (*model.AuthorProfile, error
 		}
 	}
 	profile.CountryCode = countryCode
-	if countryName, exists := countryCodeMap[countryCode]; exists {
-		profile.Country = countryName
-	} else {
-		profile.Country = "未知"
+
+	// 解析头像，网站使用Gravatar
+	if src, exists := doc.Find("img[src*='gravatar.com/avatar']").First().Attr("src"); exists {
+		profile.AvatarURL = imageURL(src)
+	}
+
+	// 国家名称默认使用英文，设置了语言时同时输出本地化名称
+	if isoCode, name := CountryName(countryCode, language.English); isoCode != "" {
+		profile.CountryCode = isoCode
+		profile.Country = name
+		if p.locale != language.Und {
+			_, profile.CountryLocalized = CountryName(isoCode, p.locale)
+		}
 	}
 
 	// 解析研究报告数量
@@ -196,37 +216,28 @@ func (p *AuthorParser) Parse(doc *goquery.Document) (*model.AuthorProfile, error
 		// 解析标题和URL
 		titleLink := cells.Eq(1).Find("h6 a")
 		vuln.Title = strings.TrimSpace(titleLink.Text())
-		vuln.URL, _ = titleLink.Attr("href")
+		vuln.URL = sanitizedHref(titleLink)
 		if vuln.URL != "" && !strings.HasPrefix(vuln.URL, "http") {
 			vuln.URL = "https://cxsecurity.com" + vuln.URL
 		}
 
 		// 从URL中提取漏洞ID
-		if vuln.URL != "" {
-			if idx := strings.Index(vuln.URL, "WLB-"); idx != -1 {
-				urlPart := vuln.URL[idx:]
-				endIdx := len(urlPart)
-				if slashIdx := strings.IndexByte(urlPart, '/'); slashIdx != -1 {
-					endIdx = slashIdx
-				}
-				vuln.ID = urlPart[:endIdx]
-			}
-		}
+		vuln.ID = model.WLBIDFromURL(vuln.URL).String()
 
 		// 解析风险等级
 		riskLevelSpan := cells.Eq(0).Find("span.label")
-		vuln.RiskLevel = strings.TrimSpace(riskLevelSpan.Text())
+		vuln.RiskLevel = p.labels.orDefault().Risk(riskLevelSpan.Text())
 
 		// 解析漏洞类型标签
 		cells.Eq(1).Find("font[color='#FF8C00']").Each(func(j int, tag *goquery.Selection) {
 			tagText := strings.TrimSpace(tag.Text())
 			if tagText != "" {
-				vuln.Tags = append(vuln.Tags, strings.Trim(tagText, "()"))
+				vuln.Tags = append(vuln.Tags, p.labels.orDefault().Tag(strings.Trim(tagText, "()")))
 			}
 		})
 
 		// 检查Remote/Local标记
-		if remoteText := cells.Eq(1).Find("div.col-md-3 h6 u").Text(); remoteText != "" {
+		if remoteText := p.labels.orDefault().Tag(cells.Eq(1).Find("div.col-md-3 h6 u").Text()); remoteText != "" {
 			if remoteText == "Remote" {
 				vuln.IsRemote = true
 			} else if remoteText == "Local" {
@@ -236,6 +247,7 @@ func (p *AuthorParser) Parse(doc *goquery.Document) (*model.AuthorProfile, error
 
 		// 只有标题不为空才添加该漏洞
 		if vuln.Title != "" {
+			vuln.Tags = model.SortTags(vuln.Tags)
 			vulnerabilities = append(vulnerabilities, vuln)
 		}
 	})
@@ -286,3 +298,20 @@ func (p *AuthorParser) Parse(doc *goquery.Document) (*model.AuthorProfile, error
 
 	return profile, nil
 }
+
+// imageURL 把图片地址补全为绝对地址，Gravatar常用的 //www.gravatar.com 这种地址补全为https
+func imageURL(src string) string {
+	src = strings.TrimSpace(src)
+	switch {
+	case src == "":
+		return ""
+	case strings.HasPrefix(src, "//"):
+		return "https:" + src
+	case strings.HasPrefix(src, "http://"), strings.HasPrefix(src, "https://"):
+		return src
+	case strings.HasPrefix(src, "/"):
+		return model.SiteURL + src
+	default:
+		return model.SiteURL + "/" + src
+	}
+}
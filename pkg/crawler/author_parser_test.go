@@ -0,0 +1,82 @@
+package crawler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func BenchmarkAuthorParser(b *testing.B) {
+	htmlContent := loadBenchmarkFixture(b, "author-profile-response.html")
+	parser := NewAuthorParser()
+
+	b.SetBytes(int64(len(htmlContent)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlContent))
+		if err != nil {
+			b.Fatalf("解析HTML失败: %v", err)
+		}
+		if _, err := parser.Parse(doc); err != nil {
+			b.Fatalf("解析失败: %v", err)
+		}
+	}
+}
+
+func TestCountryName(t *testing.T) {
+	tests := []struct {
+		code   string
+		locale language.Tag
+		iso    string
+		name   string
+	}{
+		{"us", language.English, "US", "United States"},
+		{"UK", language.English, "GB", "United Kingdom"},
+		{"pl", language.English, "PL", "Poland"},
+		{"pl", language.Chinese, "PL", "波兰"},
+		{"de", language.German, "DE", "Deutschland"},
+		{"xx", language.English, "", ""},
+		{"", language.English, "", ""},
+		{"EU", language.English, "", ""},
+	}
+
+	for _, tt := range tests {
+		iso, name := CountryName(tt.code, tt.locale)
+		assert.Equal(t, tt.iso, iso, "国家代码不匹配: %s", tt.code)
+		assert.Equal(t, tt.name, name, "国家名称不匹配: %s", tt.code)
+	}
+}
+
+func TestAuthorParserCountryLocale(t *testing.T) {
+	html := `<h1>Author: tester</h1><img src='https://cxsecurity.com/images/flags/pl.png'>`
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	require.NoError(t, err)
+	profile, err := NewAuthorParser().Parse(doc)
+	require.NoError(t, err)
+	assert.Equal(t, "PL", profile.CountryCode)
+	assert.Equal(t, "Poland", profile.Country, "默认应使用英文名称")
+	assert.Empty(t, profile.CountryLocalized, "未设置语言时不应输出本地化名称")
+
+	profile, err = NewAuthorParser(WithAuthorLocale("zh")).Parse(doc)
+	require.NoError(t, err)
+	assert.Equal(t, "Poland", profile.Country)
+	assert.Equal(t, "波兰", profile.CountryLocalized)
+}
+
+func TestAuthorParserImages(t *testing.T) {
+	html := `<h1>Author: tester</h1><img src='/images/flags/pl.png'>` +
+		`<img src="//www.gravatar.com/avatar/0123456789abcdef?s=80">`
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	require.NoError(t, err)
+	profile, err := NewAuthorParser().Parse(doc)
+	require.NoError(t, err)
+	assert.Equal(t, "https://cxsecurity.com/images/flags/pl.png", profile.FlagURL)
+	assert.Equal(t, "https://www.gravatar.com/avatar/0123456789abcdef?s=80", profile.AvatarURL)
+}
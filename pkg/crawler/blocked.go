@@ -0,0 +1,66 @@
+package crawler
+
+import (
+	"errors"
+	"regexp"
+)
+
+// ErrBlocked 表示网站返回了验证码或封禁页面，而不是请求的内容
+// 这时页面上没有漏洞，但不代表没有匹配的结果，应降低请求频率、更换代理或稍后再试。
+//
+// 示例:
+//
+//	result, err := c.Search(crawler.SearchOptions{Keyword: "wordpress"}, "")
+//	if errors.Is(err, crawler.ErrBlocked) {
+//	    // 被拦截，稍后重试
+//	}
+var ErrBlocked = errors.New("请求被网站拦截")
+
+// blockPagePatterns 是验证码和封禁页面的特征，按顺序匹配，reason为错误中显示的原因
+// 正常的详情页也有评论表单使用的reCAPTCHA控件，因此验证码控件只在页面上没有漏洞链接时才算作拦截(standalone)。
+var blockPagePatterns = []struct {
+	pattern    *regexp.Regexp
+	reason     string
+	standalone bool
+}{
+	{regexp.MustCompile(`(?i)cf-chl-|challenge-platform|cf-browser-verification|<title>\s*just a moment`), "Cloudflare验证页面", false},
+	{regexp.MustCompile(`(?i)attention required!\s*\|\s*cloudflare|sorry, you have been blocked|cf-error-details`), "Cloudflare封禁页面", false},
+	{regexp.MustCompile(`(?i)verify (that )?you are (a )?human|are you a robot|enter the characters you see`), "验证码页面", false},
+	{regexp.MustCompile(`(?i)class="[^"]*\b(g-recaptcha|h-captcha|cf-turnstile)\b`), "验证码页面", true},
+	{regexp.MustCompile(`(?i)your ip( address)? (has been|is) (banned|blocked)|you (have been|are) (banned|blocked)|<title>\s*(403 forbidden|access denied)`), "封禁页面", false},
+	{regexp.MustCompile(`(?i)<title>\s*429 too many requests|rate limit exceeded`), "请求过于频繁", false},
+}
+
+// issueLinkPattern 匹配漏洞详情页的链接，正常的列表、搜索和详情页上都有
+var issueLinkPattern = regexp.MustCompile(`/issue/WLB-`)
+
+// BlockPageReason 判断页面是否为验证码或封禁页面，是时返回原因，否则返回空字符串
+// 用于自己请求页面的程序，例如检查当前的网络和代理是否被网站拦截
+//
+// 示例:
+//
+//	content, _ := client.GetPage("/exploit/")
+//	if reason := crawler.BlockPageReason(content); reason != "" {
+//	    fmt.Println("被拦截:", reason)
+//	}
+func BlockPageReason(html string) string {
+	return detectBlockPage(html)
+}
+
+// detectBlockPage 判断页面是否为验证码或封禁页面，是时返回原因，否则返回空字符串
+func detectBlockPage(html string) string {
+	hasContent := issueLinkPattern.MatchString(html)
+	// 拦截页面通常很短，只检查开头的部分，避免在很大的正常页面上匹配
+	if len(html) > 64<<10 {
+		html = html[:64<<10]
+	}
+	for _, p := range blockPagePatterns {
+		if p.standalone && hasContent {
+			continue
+		}
+		if p.pattern.MatchString(html) {
+			return p.reason
+		}
+	}
+	return ""
+}
@@ -0,0 +1,69 @@
+package crawler
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectBlockPage(t *testing.T) {
+	for html, reason := range map[string]string{
+		`<html><head><title>Just a moment...</title></head><body><div id="cf-chl-widget"></div></body></html>`:                      "Cloudflare验证页面",
+		`<title>Attention Required! | Cloudflare</title><h1>Sorry, you have been blocked</h1>`:                                      "Cloudflare封禁页面",
+		`<form method="post"><div class="g-recaptcha" data-sitekey="x"></div></form>`:                                               "验证码页面",
+		`<p>Please verify you are a human to continue.</p>`:                                                                         "验证码页面",
+		`<h1>Your IP address has been banned</h1>`:                                                                                  "封禁页面",
+		`<html><head><title>429 Too Many Requests</title></head></html>`:                                                            "请求过于频繁",
+		`<table><tr><td><h6><a href="https://cxsecurity.com/issue/WLB-2024040001">CAPTCHA Bypass in Foo</a></h6></td></tr></table>`: "",
+	} {
+		assert.Equal(t, reason, detectBlockPage(html), html)
+	}
+
+	// 正常的详情页加载了评论表单使用的reCAPTCHA脚本，不是验证码页面
+	for _, name := range []string{"vul-detail-response.html", "search-response.html", "list-response.html"} {
+		data, err := os.ReadFile(filepath.Join(benchmarkFixtureDir, name))
+		if err != nil {
+			t.Skipf("跳过测试，测试文件不存在：%s", name)
+		}
+		assert.Empty(t, detectBlockPage(string(data)), name)
+	}
+}
+
+func TestSearchBlockedAndEmpty(t *testing.T) {
+	page := ""
+	client := &mockClient{baseURL: "https://cxsecurity.com", getPageFunc: func(path string) (string, error) {
+		return page, nil
+	}}
+	c := NewCrawler(WithHTTPClient(client))
+
+	page = `<html><head><title>Just a moment...</title></head><body><script src="/cdn-cgi/challenge-platform/h/b/orchestrate/chl_page/v1"></script></body></html>`
+	result, err := c.Search(SearchOptions{Keyword: "wordpress"}, "")
+	assert.True(t, errors.Is(err, ErrBlocked), "被拦截时返回ErrBlocked而不是空结果: %v", err)
+	assert.Nil(t, result)
+
+	// 多页和多关键词搜索同样可以判断
+	_, err = c.SearchAll(SearchOptions{Keyword: "wordpress"}, 2, "")
+	assert.ErrorIs(t, err, ErrBlocked)
+	_, err = c.MultiSearch(MultiSearchOptions{Keywords: []string{"wordpress"}}, "")
+	assert.ErrorIs(t, err, ErrBlocked)
+
+	page = `<html><head><title>CXSecurity</title></head><body><table width="100%"></table><p>No results</p></body></html>`
+	result, err = c.Search(SearchOptions{Keyword: "no-such-product"}, "")
+	require.NoError(t, err)
+	assert.True(t, result.EmptyResult)
+	assert.Empty(t, result.Vulnerabilities)
+
+	data, err := os.ReadFile(filepath.Join(benchmarkFixtureDir, "search-response.html"))
+	if err != nil {
+		t.Skip("跳过测试，测试文件不存在：search-response.html")
+	}
+	page = string(data)
+	result, err = c.Search(SearchOptions{Keyword: "php", Risks: []string{"No-such-risk"}}, "")
+	require.NoError(t, err)
+	assert.Empty(t, result.Vulnerabilities)
+	assert.False(t, result.EmptyResult, "网站上有结果，只是都被过滤掉了")
+}
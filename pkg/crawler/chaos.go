@@ -0,0 +1,179 @@
+//go:build chaos
+
+package crawler
+
+import (
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ChaosFault 是注入的故障类型
+type ChaosFault string
+
+const (
+	ChaosLatency  ChaosFault = "latency"  // 在请求前增加延迟
+	ChaosError    ChaosFault = "error"    // 不发送请求，直接返回5xx响应
+	ChaosTruncate ChaosFault = "truncate" // 只返回部分响应正文，随后读取失败
+)
+
+// ChaosConfig 是故障注入的配置，各概率的取值范围为0到1，为0时不注入对应的故障
+type ChaosConfig struct {
+	LatencyRate  float64       // 增加延迟的概率
+	Latency      time.Duration // 增加的最大延迟，实际延迟在0到该值之间随机
+	ErrorRate    float64       // 开始一次5xx突发的概率
+	ErrorBurst   int           // 一次突发连续返回5xx的请求数，小于1时为1
+	ErrorStatus  int           // 返回的状态码，为0时使用503
+	TruncateRate float64       // 截断响应正文的概率
+	Seed         int64         // 随机数种子，为0时使用当前时间，固定种子可以复现同样的故障序列
+
+	// OnInject 在每次注入故障时调用，可用于核对告警或重试的次数
+	OnInject func(fault ChaosFault, req *http.Request)
+}
+
+// chaosTransport 是按ChaosConfig注入故障的http.RoundTripper
+type chaosTransport struct {
+	next   http.RoundTripper
+	config ChaosConfig
+
+	mu        sync.Mutex
+	rand      *rand.Rand
+	burstLeft int // 当前突发剩余的5xx请求数
+}
+
+// WithChaos 在HTTP传输层注入延迟、5xx突发和截断的响应正文，
+// 用于在测试中验证嵌入爬虫的程序的重试、超时和告警行为。
+// 注入的故障经过客户端的重试、限速和请求日志，与真实的网络故障表现一致。
+// 只在使用 -tags chaos 编译时可用，正式构建中不包含该选项。
+//
+// 需要放在 WithProxy 之后，WithProxy 会替换传输层。
+//
+// 参数:
+//   - config: 故障注入配置
+//
+// 返回值:
+//   - ClientOption: 返回一个配置函数
+//
+// 示例:
+//
+//	client := NewClient(
+//	    WithRetry(3, 100*time.Millisecond),
+//	    WithChaos(ChaosConfig{ErrorRate: 0.2, ErrorBurst: 3, TruncateRate: 0.1}),
+//	)
+func WithChaos(config ChaosConfig) ClientOption {
+	return func(c *Client) {
+		next := c.client.Transport
+		if next == nil {
+			next = http.DefaultTransport
+		}
+		if config.ErrorBurst < 1 {
+			config.ErrorBurst = 1
+		}
+		if config.ErrorStatus == 0 {
+			config.ErrorStatus = http.StatusServiceUnavailable
+		}
+		seed := config.Seed
+		if seed == 0 {
+			seed = time.Now().UnixNano()
+		}
+		c.client.Transport = &chaosTransport{
+			next:   next,
+			config: config,
+			rand:   rand.New(rand.NewSource(seed)),
+		}
+	}
+}
+
+// RoundTrip 按配置依次注入延迟、5xx响应和截断的正文
+func (t *chaosTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	delay, fail, truncate := t.roll()
+
+	if delay > 0 {
+		t.inject(ChaosLatency, req)
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		}
+	}
+
+	if fail {
+		t.inject(ChaosError, req)
+		return &http.Response{
+			Status:     http.StatusText(t.config.ErrorStatus),
+			StatusCode: t.config.ErrorStatus,
+			Proto:      "HTTP/1.1",
+			ProtoMajor: 1,
+			ProtoMinor: 1,
+			Header:     http.Header{"Content-Type": {"text/plain; charset=utf-8"}},
+			Body:       io.NopCloser(strings.NewReader(http.StatusText(t.config.ErrorStatus))),
+			Request:    req,
+		}, nil
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || !truncate {
+		return resp, err
+	}
+	t.inject(ChaosTruncate, req)
+	resp.Body = &truncatedBody{body: resp.Body}
+	return resp, nil
+}
+
+// roll 决定本次请求注入哪些故障
+func (t *chaosTransport) roll() (delay time.Duration, fail, truncate bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.config.Latency > 0 && t.rand.Float64() < t.config.LatencyRate {
+		delay = time.Duration(t.rand.Int63n(int64(t.config.Latency)) + 1)
+	}
+	if t.burstLeft == 0 && t.rand.Float64() < t.config.ErrorRate {
+		t.burstLeft = t.config.ErrorBurst
+	}
+	if t.burstLeft > 0 {
+		t.burstLeft--
+		return delay, true, false
+	}
+	return delay, false, t.rand.Float64() < t.config.TruncateRate
+}
+
+func (t *chaosTransport) inject(fault ChaosFault, req *http.Request) {
+	if t.config.OnInject != nil {
+		t.config.OnInject(fault, req)
+	}
+}
+
+// truncatedBody 只返回正文的前一半，随后返回io.ErrUnexpectedEOF，
+// 与连接在传输中途断开时读取正文的结果相同
+type truncatedBody struct {
+	body io.ReadCloser
+	data []byte
+	read bool
+}
+
+func (b *truncatedBody) Read(p []byte) (int, error) {
+	if !b.read {
+		b.read = true
+		data, err := io.ReadAll(b.body)
+		if err != nil {
+			return 0, err
+		}
+		b.data = data[:len(data)/2]
+	}
+	if len(b.data) == 0 {
+		return 0, io.ErrUnexpectedEOF
+	}
+	n := copy(p, b.data)
+	b.data = b.data[n:]
+	return n, nil
+}
+
+func (b *truncatedBody) Close() error {
+	return b.body.Close()
+}
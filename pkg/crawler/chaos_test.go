@@ -0,0 +1,132 @@
+//go:build chaos
+
+package crawler
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// chaosRecorder 记录注入的故障
+type chaosRecorder struct {
+	mu     sync.Mutex
+	faults []ChaosFault
+}
+
+func (r *chaosRecorder) record(fault ChaosFault, _ *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.faults = append(r.faults, fault)
+}
+
+func newChaosServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "<html><body>ok</body></html>")
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestWithChaosErrorBurst(t *testing.T) {
+	server := newChaosServer(t)
+	rec := &chaosRecorder{}
+	var attempts []int
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithRetry(2, time.Millisecond),
+		WithChaos(ChaosConfig{ErrorRate: 1, ErrorBurst: 3, Seed: 1, OnInject: rec.record}),
+		WithRequestLogger(func(l RequestLog) { attempts = append(attempts, l.StatusCode) }),
+	)
+
+	// 突发的3个请求都返回503，用完2次重试后失败
+	_, err := client.GetPage("/")
+	require.Error(t, err)
+	assert.Equal(t, []int{503, 503, 503}, attempts)
+	assert.Equal(t, []ChaosFault{ChaosError, ChaosError, ChaosError}, rec.faults)
+}
+
+func TestWithChaosRetryRecovers(t *testing.T) {
+	server := newChaosServer(t)
+	var attempts int
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithRetry(3, time.Millisecond),
+		WithChaos(ChaosConfig{ErrorRate: 0.5, ErrorBurst: 2, ErrorStatus: http.StatusBadGateway, Seed: 42}),
+		WithRequestLogger(func(RequestLog) { attempts++ }),
+	)
+
+	for i := 0; i < 20; i++ {
+		content, err := client.GetPage("/")
+		if err == nil {
+			assert.Contains(t, content, "ok")
+		}
+	}
+	assert.Greater(t, attempts, 20, "应该有请求被重试")
+}
+
+func TestWithChaosTruncate(t *testing.T) {
+	server := newChaosServer(t)
+	rec := &chaosRecorder{}
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithRetry(0, 0),
+		WithChaos(ChaosConfig{TruncateRate: 1, OnInject: rec.record}),
+	)
+
+	_, err := client.GetPage("/")
+	require.Error(t, err)
+	assert.Equal(t, []ChaosFault{ChaosTruncate}, rec.faults)
+}
+
+func TestWithChaosLatency(t *testing.T) {
+	server := newChaosServer(t)
+	rec := &chaosRecorder{}
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithTimeout(20*time.Millisecond),
+		WithRetry(0, 0),
+		WithChaos(ChaosConfig{LatencyRate: 1, Latency: time.Second, Seed: 7, OnInject: rec.record}),
+	)
+
+	// 随机延迟可能超过超时时间，但不会超过配置的上限
+	start := time.Now()
+	client.GetPage("/")
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, []ChaosFault{ChaosLatency}, rec.faults)
+}
+
+func TestWithChaosSeedReproducible(t *testing.T) {
+	server := newChaosServer(t)
+	run := func() []ChaosFault {
+		rec := &chaosRecorder{}
+		client := NewClient(
+			WithBaseURL(server.URL),
+			WithRetry(0, 0),
+			WithChaos(ChaosConfig{ErrorRate: 0.3, TruncateRate: 0.3, Seed: 99, OnInject: rec.record}),
+		)
+		for i := 0; i < 30; i++ {
+			client.GetPage("/")
+		}
+		return rec.faults
+	}
+
+	first := run()
+	assert.NotEmpty(t, first)
+	assert.Equal(t, first, run())
+}
+
+func TestWithChaosDisabled(t *testing.T) {
+	server := newChaosServer(t)
+	client := NewClient(WithBaseURL(server.URL), WithRetry(0, 0), WithChaos(ChaosConfig{}))
+
+	content, err := client.GetPage("/")
+	require.NoError(t, err)
+	assert.Contains(t, content, "ok")
+}
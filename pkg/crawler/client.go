@@ -2,9 +2,13 @@ package crawler
 
 import (
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"regexp"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -50,7 +54,7 @@ type ClientOption func(*Client)
 //
 // 主要特性：
 // 1. 超时控制：可配置请求超时时间，防止请求阻塞
-// 2. 代理支持：支持HTTP/HTTPS代理，便于绕过访问限制
+// 2. 代理支持：支持HTTP/HTTPS代理，便于绕过访问限制，也可以轮换使用多个代理
 // 3. 自动重试：遇到临时错误时自动重试，提高成功率
 // 4. 自定义请求头：支持添加自定义HTTP头，模拟浏览器行为
 //
@@ -77,8 +81,36 @@ type Client struct {
 	maxRetries    int               // 最大重试次数
 	retryDelay    time.Duration     // 重试间隔时间
 	customHeaders map[string]string // 自定义HTTP头
+	routeHeaders  []RouteHeaders    // 按路径前缀添加的HTTP头，按前缀长度升序排列
+	throttle      *AdaptiveThrottle // 自适应限速器，为nil时不限速
+	logger        func(RequestLog)  // 请求日志回调，为nil时不记录
+	proxyPool     *proxyPool        // 轮换使用的代理，由 WithProxyPool 设置
+	maxBodySize   int64             // 响应体的最大字节数，为0时不限制
+
+	rateMu       sync.Mutex    // 保护nextRequest
+	rateInterval time.Duration // 固定限速的请求间隔，为0时不限速
+	nextRequest  time.Time     // 固定限速下一次请求最早的开始时间
+
+	statsMu  sync.Mutex // 保护requests、failures、activeMirror、failovers和lastHealthCheck
+	budget   int        // 请求预算，为0时不限制
+	requests int        // 已发出的请求数，包括重试
+	failures int        // 失败的请求数
+
+	mirrors      []Mirror // 按故障转移顺序排列的镜像，不包括baseURL
+	activeMirror int      // 当前使用的站点，0为baseURL，i为mirrors[i-1]
+	failovers    int      // 切换站点的次数
+
+	healthInterval  time.Duration // 使用镜像时检查优先级更高的站点是否恢复的间隔，为0时不检查
+	healthPath      string        // 健康检查请求的路径
+	lastHealthCheck time.Time     // 上一次健康检查或切换站点的时间
 }
 
+// ErrBudgetExhausted 表示客户端的请求预算已用完，不会再发出请求
+var ErrBudgetExhausted = errors.New("请求预算已用完")
+
+// ErrResponseTooLarge 表示响应体超过了 WithMaxResponseSize 设置的大小，不会重试
+var ErrResponseTooLarge = errors.New("响应过大")
+
 // WithTimeout 设置客户端超时时间
 // 超时时间包括连接建立、请求发送和响应接收的总时间。
 // 如果请求超过设定时间，将返回超时错误。
@@ -100,7 +132,7 @@ func WithTimeout(timeout time.Duration) ClientOption {
 
 // WithProxy 设置HTTP代理
 // 支持HTTP和HTTPS代理，用于访问需要代理的网站。
-// 如果代理URL无效，将忽略该设置。
+// 如果代理URL无效，将忽略该设置。需要轮换多个代理时使用 WithProxyPool。
 //
 // 参数:
 //   - proxyURL: 代理服务器URL，例如 "http://proxy.example.com:8080"
@@ -119,18 +151,142 @@ func WithProxy(proxyURL string) ClientOption {
 				c.client.Transport = &http.Transport{
 					Proxy: http.ProxyURL(proxy),
 				}
+				c.proxyPool = nil
 			}
 		}
 	}
 }
 
+// WithBaseURL 设置网站的基础URL，用于访问镜像或回放归档页面的测试服务
+// 末尾的 "/" 会被去掉，为空时保持默认值 https://cxsecurity.com
+//
+// 示例:
+//
+//	client := NewClient(WithBaseURL("http://127.0.0.1:8081"))
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) {
+		if baseURL = strings.TrimRight(baseURL, "/"); baseURL != "" {
+			c.baseURL = baseURL
+		}
+	}
+}
+
+// Mirror 是网站的一个镜像或备用域名
+type Mirror struct {
+	BaseURL string // 镜像的基础URL，例如 https://mirror.example.org，末尾的 "/" 会被去掉
+	// Path 将cxsecurity上的路径转换为镜像上的路径，用于路径结构不同的镜像，为nil时使用相同的路径
+	Path func(path string) string
+	// Canonical 是Path的逆转换，将镜像页面中链接的路径转换回cxsecurity上的路径，为nil时保持原路径
+	Canonical func(path string) string
+
+	links *regexp.Regexp // 匹配页面中指向镜像的链接，由 WithMirrors 生成
+}
+
+// url 返回路径在镜像上的完整URL
+func (m Mirror) url(path string) string {
+	if m.Path != nil {
+		path = m.Path(path)
+	}
+	return m.BaseURL + path
+}
+
+// canonicalize 将页面中指向镜像的链接改写为基础URL上的规范地址
+// 链接可以是 http、https 或省略协议的 //host 形式，主机名不区分大小写；
+// 只匹配完整的主机名，mirror.example.org.evil 这类更长的主机名不会被改写。
+func (m Mirror) canonicalize(content, baseURL string) string {
+	if m.links == nil {
+		return content
+	}
+	matches := m.links.FindAllStringSubmatchIndex(content, -1)
+	if len(matches) == 0 {
+		return content
+	}
+	var b strings.Builder
+	last := 0
+	for _, loc := range matches {
+		// Go的正则不支持前瞻，在这里检查链接后面的字符，避免匹配更长的主机名或其他端口
+		if loc[1] < len(content) && isHostChar(content[loc[1]]) {
+			continue
+		}
+		path := ""
+		if loc[2] >= 0 {
+			path = content[loc[2]:loc[3]]
+		}
+		if m.Canonical != nil {
+			path = m.Canonical(path)
+		}
+		b.WriteString(content[last:loc[0]])
+		b.WriteString(baseURL)
+		b.WriteString(path)
+		last = loc[1]
+	}
+	b.WriteString(content[last:])
+	return b.String()
+}
+
+// isHostChar 判断字符是否可以出现在主机名或端口中
+func isHostChar(ch byte) bool {
+	return ch == '.' || ch == '-' || ch == ':' || ch == '_' ||
+		'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || '0' <= ch && ch <= '9'
+}
+
+// mirrorLinks 返回匹配指向镜像基础URL的链接的正则，第一个分组为链接的路径部分
+func mirrorLinks(baseURL string) *regexp.Regexp {
+	host := baseURL
+	if i := strings.Index(host, "://"); i >= 0 {
+		host = host[i+3:]
+	}
+	return regexp.MustCompile(`(?i)(?:https?:)?//` + regexp.QuoteMeta(host) + `([/?#][^\s"'<>]*)?`)
+}
+
+// WithMirrors 设置基础URL不可用时依次尝试的镜像
+// 请求在当前站点重试 WithRetry 指定的次数后仍然失败(网络错误、5xx等)时，按顺序换到下一个站点重新请求，
+// 成功后之后的请求继续使用该站点；所有站点都失败时返回最后一个错误。
+// 镜像返回的页面中指向镜像的链接会被改写为 WithBaseURL 设置的地址，路径由 Mirror.Canonical 转换，
+// GetBaseURL 也总是返回该地址，结果中的链接不会因为切换镜像而改变。
+// 需要知道页面由哪个镜像返回时使用 GetPageMirror；配合 WithMirrorHealthCheck 可以在基础URL恢复后切换回来。
+//
+// 示例:
+//
+//	client := NewClient(WithMirrors(
+//	    Mirror{BaseURL: "https://mirror.example.org"},
+//	    Mirror{BaseURL: "https://archive.example.net", Path: func(p string) string { return "/cxsecurity" + p }},
+//	))
+func WithMirrors(mirrors ...Mirror) ClientOption {
+	return func(c *Client) {
+		for _, m := range mirrors {
+			if m.BaseURL = strings.TrimRight(m.BaseURL, "/"); m.BaseURL != "" {
+				m.links = mirrorLinks(m.BaseURL)
+				c.mirrors = append(c.mirrors, m)
+			}
+		}
+	}
+}
+
+// WithMirrorHealthCheck 设置使用镜像时定期检查优先级更高的站点是否恢复
+// 当前站点不是基础URL且距上一次检查或切换超过interval时，下一次请求前依次向更优先的站点请求path，
+// 返回非错误状态码的第一个站点成为当前站点。检查请求计入请求预算和请求日志。
+// interval为0时不检查，切换到镜像后一直使用该镜像，直到镜像失败；path为空时使用 "/"。
+//
+// 示例:
+//
+//	client := NewClient(WithMirrors(Mirror{BaseURL: "https://mirror.example.org"}), WithMirrorHealthCheck(5*time.Minute, "/"))
+func WithMirrorHealthCheck(interval time.Duration, path string) ClientOption {
+	return func(c *Client) {
+		if path == "" {
+			path = "/"
+		}
+		c.healthInterval, c.healthPath = interval, path
+	}
+}
+
 // WithRetry 设置重试参数
 // 当请求失败时（如服务器错误、网络超时等），将自动重试。
 // 每次重试之间会等待指定的延迟时间。
 //
 // 参数:
-//   - maxRetries: 最大重试次数，建议设置为3-5
-//   - retryDelay: 重试间隔时间，建议设置为500ms-1s
+//   - maxRetries: 最大重试次数，建议设置为3-5；为0时禁用重试，小于0时保持默认值
+//   - retryDelay: 重试间隔时间，建议设置为500ms-1s；小于等于0时保持默认值
 //
 // 返回值:
 //   - ClientOption: 返回一个配置函数
@@ -140,7 +296,7 @@ func WithProxy(proxyURL string) ClientOption {
 //	client := NewClient(WithRetry(3, 500 * time.Millisecond))
 func WithRetry(maxRetries int, retryDelay time.Duration) ClientOption {
 	return func(c *Client) {
-		if maxRetries > 0 {
+		if maxRetries >= 0 {
 			c.maxRetries = maxRetries
 		}
 		if retryDelay > 0 {
@@ -175,6 +331,138 @@ func WithHeader(key, value string) ClientOption {
 	}
 }
 
+// WithAdaptiveThrottle 启用自适应限速
+// 客户端会根据429/503响应和响应时间突增自动放慢请求速率，
+// 持续异常时暂停一段时间，服务器恢复正常后再逐步提速。
+// 启用后429响应也会被视为错误并触发重试。
+//
+// 参数:
+//   - minDelay: 请求间隔下限，例如 200 * time.Millisecond
+//   - maxDelay: 请求间隔上限，例如 10 * time.Second
+//   - pause: 间隔达到上限后仍持续异常时的暂停时长，例如 time.Minute
+//
+// 返回值:
+//   - ClientOption: 返回一个配置函数
+//
+// 示例:
+//
+//	client := NewClient(WithAdaptiveThrottle(200*time.Millisecond, 10*time.Second, time.Minute))
+func WithAdaptiveThrottle(minDelay, maxDelay, pause time.Duration) ClientOption {
+	return func(c *Client) {
+		c.throttle = NewAdaptiveThrottle(minDelay, maxDelay, pause)
+	}
+}
+
+// WithRateLimit 限制请求速率
+// 与 WithAdaptiveThrottle 不同，速率固定，不随服务器的响应调整；
+// 两者同时启用时请求需要同时满足两者的间隔。重试的请求同样受限制。
+//
+// 参数:
+//   - requestsPerSecond: 每秒最多的请求数，例如 0.5 表示每2秒一个请求；小于等于0时不限速
+//
+// 返回值:
+//   - ClientOption: 返回一个配置函数
+//
+// 示例:
+//
+//	client := NewClient(WithRateLimit(2))
+func WithRateLimit(requestsPerSecond float64) ClientOption {
+	return func(c *Client) {
+		c.rateInterval = 0
+		if requestsPerSecond > 0 {
+			c.rateInterval = time.Duration(float64(time.Second) / requestsPerSecond)
+		}
+	}
+}
+
+// WithRequestBudget 限制客户端最多发出的请求数
+// 重试的请求同样计入预算，预算用完后 GetPage 直接返回 ErrBudgetExhausted。
+// 用于限制一次爬取对网站造成的负载。
+//
+// 参数:
+//   - maxRequests: 最多的请求数；小于等于0时不限制
+//
+// 返回值:
+//   - ClientOption: 返回一个配置函数
+//
+// 示例:
+//
+//	client := NewClient(WithRequestBudget(500))
+func WithRequestBudget(maxRequests int) ClientOption {
+	return func(c *Client) {
+		c.budget = 0
+		if maxRequests > 0 {
+			c.budget = maxRequests
+		}
+	}
+}
+
+// WithMaxResponseSize 限制响应体的最大字节数
+// Content-Length 超过限制时不读取响应体，否则最多读取 maxBytes+1 个字节，
+// 超过限制时 GetPage 返回 ErrResponseTooLarge，内存和流量都不会超过限制。
+//
+// 参数:
+//   - maxBytes: 响应体的最大字节数；小于等于0时不限制
+//
+// 返回值:
+//   - ClientOption: 返回一个配置函数
+//
+// 示例:
+//
+//	client := NewClient(WithMaxResponseSize(1 << 20))
+func WithMaxResponseSize(maxBytes int64) ClientOption {
+	return func(c *Client) {
+		c.maxBodySize = 0
+		if maxBytes > 0 {
+			c.maxBodySize = maxBytes
+		}
+	}
+}
+
+// RequestLog 是一次HTTP请求的记录，通过 WithRequestLogger 回调
+type RequestLog struct {
+	URL        string        // 请求的完整URL
+	Attempt    int           // 第几次尝试，从1开始，大于1表示重试
+	StatusCode int           // 响应状态码，请求失败时为0
+	Size       int           // 响应正文的字节数
+	Duration   time.Duration // 请求耗时
+	CacheHit   bool          // 响应头表明由CDN缓存返回，没有到达网站的服务器
+	Err        error         // 本次尝试的错误，成功时为nil
+}
+
+// cacheStatusHeaders 是CDN和反向代理表示缓存状态的响应头
+var cacheStatusHeaders = []string{"CF-Cache-Status", "X-Cache", "X-Cache-Status"}
+
+// isCacheHit 根据响应头判断响应是否由缓存返回，例如 CF-Cache-Status: HIT、X-Cache: HIT from proxy
+func isCacheHit(header http.Header) bool {
+	for _, name := range cacheStatusHeaders {
+		if strings.HasPrefix(strings.ToUpper(header.Get(name)), "HIT") {
+			return true
+		}
+	}
+	return false
+}
+
+// WithRequestLogger 设置请求日志回调
+// 每次尝试(包括重试)完成后调用一次，可用于输出详细日志或统计请求耗时。
+//
+// 参数:
+//   - logger: 日志回调函数
+//
+// 返回值:
+//   - ClientOption: 返回一个配置函数
+//
+// 示例:
+//
+//	client := NewClient(WithRequestLogger(func(l RequestLog) {
+//	    log.Printf("GET %s -> %d (%v)", l.URL, l.StatusCode, l.Duration)
+//	}))
+func WithRequestLogger(logger func(RequestLog)) ClientOption {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
 // NewClient 创建一个新的Client实例
 // 默认配置:
 //   - 超时时间: 30秒
@@ -220,7 +508,7 @@ func NewClient(options ...ClientOption) *Client {
 }
 
 // GetBaseURL 返回客户端配置的基础URL
-// 这个URL用于构建完整的请求URL。
+// 这个URL用于构建结果中的链接，切换到镜像后仍然返回该地址，参见 WithMirrors。
 //
 // 返回值:
 //   - string: 网站的基础URL，例如 "https://cxsecurity.com"
@@ -228,6 +516,92 @@ func (c *Client) GetBaseURL() string {
 	return c.baseURL
 }
 
+// site 返回第i个站点，0为基础URL，之后为镜像
+func (c *Client) site(i int) Mirror {
+	if i == 0 {
+		return Mirror{BaseURL: c.baseURL}
+	}
+	return c.mirrors[i-1]
+}
+
+// ThrottleStats 返回自适应限速器的当前状态
+// 未启用自适应限速时返回零值
+func (c *Client) ThrottleStats() ThrottleStats {
+	if c.throttle == nil {
+		return ThrottleStats{}
+	}
+	return c.throttle.Stats()
+}
+
+// ClientStats 是客户端请求和限速状态的快照，用于了解爬取变慢的原因
+type ClientStats struct {
+	Requests     int           // 已发出的请求数，包括重试
+	Failures     int           // 失败的请求数
+	Budget       int           // 请求预算，为0时不限制
+	Remaining    int           // 剩余的请求数，不限制时为-1
+	RateLimit    float64       // 固定限速(请求/秒)，为0时不限速
+	BackoffUntil time.Time     // 限速或暂停导致下一个请求需要等到的时间，可以立即发送时为零值
+	Adaptive     bool          // 是否启用了自适应限速
+	Throttle     ThrottleStats // 自适应限速器的状态，未启用时为零值
+	BaseURL      string        // 当前请求使用的站点，切换到镜像后为镜像的基础URL
+	Failovers    int           // 切换站点的次数
+	Coalesced    int           // 与相同的并发请求合并、没有单独请求网站的次数，只由 Crawler.Stats 填写
+}
+
+// Stats 返回客户端当前的请求数、剩余预算和限速状态
+//
+// 示例:
+//
+//	stats := client.Stats()
+//	if !stats.BackoffUntil.IsZero() {
+//	    fmt.Println("限速中，下一个请求:", stats.BackoffUntil)
+//	}
+func (c *Client) Stats() ClientStats {
+	c.statsMu.Lock()
+	stats := ClientStats{
+		Requests:  c.requests,
+		Failures:  c.failures,
+		Budget:    c.budget,
+		Remaining: -1,
+		BaseURL:   c.site(c.activeMirror).BaseURL,
+		Failovers: c.failovers,
+	}
+	c.statsMu.Unlock()
+	if stats.Budget > 0 {
+		stats.Remaining = max(stats.Budget-stats.Requests, 0)
+	}
+
+	c.rateMu.Lock()
+	if c.rateInterval > 0 {
+		stats.RateLimit = float64(time.Second) / float64(c.rateInterval)
+		stats.BackoffUntil = c.nextRequest
+	}
+	c.rateMu.Unlock()
+
+	if c.throttle != nil {
+		stats.Adaptive = true
+		stats.Throttle = c.throttle.Stats()
+		if stats.Throttle.NextRequest.After(stats.BackoffUntil) {
+			stats.BackoffUntil = stats.Throttle.NextRequest
+		}
+	}
+	if !stats.BackoffUntil.After(time.Now()) {
+		stats.BackoffUntil = time.Time{}
+	}
+	return stats
+}
+
+// takeRequest 在预算中记录一次请求，预算已用完时返回false
+func (c *Client) takeRequest() bool {
+	c.statsMu.Lock()
+	defer c.statsMu.Unlock()
+	if c.budget > 0 && c.requests >= c.budget {
+		return false
+	}
+	c.requests++
+	return true
+}
+
 // GetPage 获取指定URL的页面内容
 // 这个方法会自动处理重试、超时和错误。
 //
@@ -247,6 +621,7 @@ func (c *Client) GetBaseURL() string {
 //   - 超时错误
 //   - 服务器错误（5xx）
 //   - URL错误
+//   - ErrBudgetExhausted: 请求预算已用完
 //
 // 示例:
 //
@@ -256,23 +631,157 @@ func (c *Client) GetBaseURL() string {
 //	}
 //	fmt.Println(content)
 func (c *Client) GetPage(path string) (string, error) {
+	content, _, err := c.GetPageMirror(path)
+	return content, err
+}
+
+// GetPageMirror 与 GetPage 相同，同时返回实际返回页面的镜像
+// 页面由基础URL返回时mirror为空，否则为镜像的基础URL；页面中的链接已经改写为规范地址。
+//
+// 示例:
+//
+//	content, mirror, err := client.GetPageMirror("/issue/WLB-2024040015")
+//	if err == nil && mirror != "" {
+//	    fmt.Println("页面来自镜像:", mirror)
+//	}
+func (c *Client) GetPageMirror(path string) (content string, mirror string, err error) {
 	// 检查baseURL是否为空
 	if c.baseURL == "" {
-		return "", errors.New("baseURL未设置")
+		return "", "", errors.New("baseURL未设置")
 	}
 
-	// 添加重试机制
+	c.checkMirrorHealth()
+	c.statsMu.Lock()
+	start := c.activeMirror
+	c.statsMu.Unlock()
+
+	// 从当前站点开始，失败时依次尝试其他站点
+	var lastErr error
+	for i := 0; i <= len(c.mirrors); i++ {
+		index := (start + i) % (len(c.mirrors) + 1)
+		content, err := c.getPageFrom(c.site(index), path)
+		if err == nil {
+			if index != start {
+				c.statsMu.Lock()
+				c.activeMirror = index
+				c.failovers++
+				c.lastHealthCheck = time.Now()
+				c.statsMu.Unlock()
+			}
+			if index == 0 {
+				return content, "", nil
+			}
+			site := c.site(index)
+			return site.canonicalize(content, c.baseURL), site.BaseURL, nil
+		}
+		if errors.Is(err, ErrBudgetExhausted) || errors.Is(err, ErrResponseTooLarge) {
+			return "", "", err
+		}
+		lastErr = err
+	}
+	return "", "", lastErr
+}
+
+// checkMirrorHealth 在使用镜像且到了检查时间时，切换回第一个恢复的更优先站点
+func (c *Client) checkMirrorHealth() {
+	if c.healthInterval <= 0 {
+		return
+	}
+	c.statsMu.Lock()
+	active := c.activeMirror
+	due := active > 0 && time.Since(c.lastHealthCheck) >= c.healthInterval
+	if due {
+		c.lastHealthCheck = time.Now()
+	}
+	c.statsMu.Unlock()
+	if !due {
+		return
+	}
+
+	for i := 0; i < active; i++ {
+		if !c.probe(c.site(i)) {
+			continue
+		}
+		c.statsMu.Lock()
+		// 检查期间其他请求可能已经切换了站点，这时保留它们的结果
+		if c.activeMirror == active {
+			c.activeMirror = i
+			c.failovers++
+		}
+		c.statsMu.Unlock()
+		return
+	}
+}
+
+// probe 向站点发送一次健康检查请求，不重试，返回站点是否可用
+func (c *Client) probe(site Mirror) bool {
+	if !c.takeRequest() {
+		return false
+	}
+	url := site.url(c.healthPath)
+	start := time.Now()
+	content, status, cacheHit, err := c.doRequest(url, c.headersFor(site, c.healthPath))
+	if err != nil {
+		c.statsMu.Lock()
+		c.failures++
+		c.statsMu.Unlock()
+	}
+	if c.logger != nil {
+		c.logger(RequestLog{
+			URL:        url,
+			Attempt:    1,
+			StatusCode: status,
+			Size:       len(content),
+			Duration:   time.Since(start),
+			CacheHit:   cacheHit,
+			Err:        err,
+		})
+	}
+	return err == nil && status < http.StatusBadRequest
+}
+
+// getPageFrom 从指定站点获取页面，失败时按 WithRetry 的设置重试
+func (c *Client) getPageFrom(site Mirror, path string) (string, error) {
+	url := site.url(path)
+	headers := c.headersFor(site, path)
 	var lastErr error
 	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+		if !c.takeRequest() {
+			if lastErr != nil {
+				return "", fmt.Errorf("%w(上一次错误: %v)", ErrBudgetExhausted, lastErr)
+			}
+			return "", ErrBudgetExhausted
+		}
+
 		if attempt > 0 {
 			// 如果不是第一次尝试，则等待一段时间
 			time.Sleep(c.retryDelay)
 		}
 
-		content, err := c.doRequest(path)
+		start := time.Now()
+		content, status, cacheHit, err := c.doRequest(url, headers)
+		if err != nil {
+			c.statsMu.Lock()
+			c.failures++
+			c.statsMu.Unlock()
+		}
+		if c.logger != nil {
+			c.logger(RequestLog{
+				URL:        url,
+				Attempt:    attempt + 1,
+				StatusCode: status,
+				Size:       len(content),
+				Duration:   time.Since(start),
+				CacheHit:   cacheHit,
+				Err:        err,
+			})
+		}
 		if err == nil {
 			return content, nil
 		}
+		if errors.Is(err, ErrResponseTooLarge) {
+			return "", err
+		}
 		lastErr = err
 	}
 
@@ -288,7 +797,7 @@ func (c *Client) GetPage(path string) (string, error) {
 //   - Accept: 支持的内容类型
 //   - Accept-Language: 语言偏好
 //
-// 2. 添加自定义请求头
+// 2. 添加自定义请求头和按路径添加的请求头
 // 3. 处理响应状态码
 //   - 2xx: 成功
 //   - 3xx: 重定向（自动处理）
@@ -296,22 +805,23 @@ func (c *Client) GetPage(path string) (string, error) {
 //   - 5xx: 服务器错误（需要重试）
 //
 // 参数:
-//   - path: 相对于baseURL的路径
+//   - url: 完整的请求URL
+//   - headers: 按路径前缀添加的请求头，覆盖自定义请求头
 //
 // 返回值:
 //   - string: 页面的HTML内容
+//   - int: 响应状态码，请求失败时为0
+//   - bool: 响应是否由CDN缓存返回
 //   - error: 请求过程中的错误
 //
 // 注意事项：
 // 1. 5xx错误会触发重试机制
 // 2. 4xx错误会返回错误页面内容
 // 3. 重定向会自动处理
-func (c *Client) doRequest(path string) (string, error) {
-	url := c.baseURL + path
-
+func (c *Client) doRequest(url string, headers map[string]string) (string, int, bool, error) {
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		return "", err
+		return "", 0, false, err
 	}
 
 	// 设置基本的请求头，模拟浏览器行为
@@ -323,23 +833,77 @@ func (c *Client) doRequest(path string) (string, error) {
 	for key, value := range c.customHeaders {
 		req.Header.Set(key, value)
 	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	c.waitRateLimit()
+	if c.throttle != nil {
+		c.throttle.Wait()
+	}
 
+	start := time.Now()
 	resp, err := c.client.Do(req)
 	if err != nil {
-		return "", err
+		if c.throttle != nil {
+			c.throttle.Observe(0, time.Since(start))
+		}
+		return "", 0, false, err
 	}
 	defer resp.Body.Close()
+	cacheHit := isCacheHit(resp.Header)
 
-	// 读取响应内容
-	bodyBytes, err := io.ReadAll(resp.Body)
+	// 设置了最大响应大小时，Content-Length 已经超过限制的响应不读取响应体
+	if c.maxBodySize > 0 && resp.ContentLength > c.maxBodySize {
+		if c.throttle != nil {
+			c.throttle.Observe(resp.StatusCode, time.Since(start))
+		}
+		return "", resp.StatusCode, cacheHit, fmt.Errorf("%w: %d 字节", ErrResponseTooLarge, resp.ContentLength)
+	}
+
+	// 读取响应内容，没有Content-Length的响应最多多读一个字节用于判断是否超过限制
+	body := io.Reader(resp.Body)
+	if c.maxBodySize > 0 {
+		body = io.LimitReader(resp.Body, c.maxBodySize+1)
+	}
+	bodyBytes, err := io.ReadAll(body)
+	if c.throttle != nil {
+		c.throttle.Observe(resp.StatusCode, time.Since(start))
+	}
 	if err != nil {
-		return "", err
+		return "", resp.StatusCode, cacheHit, err
+	}
+	if c.maxBodySize > 0 && int64(len(bodyBytes)) > c.maxBodySize {
+		return "", resp.StatusCode, cacheHit, fmt.Errorf("%w: 超过 %d 字节", ErrResponseTooLarge, c.maxBodySize)
+	}
+
+	// 启用自适应限速时，429表示请求过于频繁，需要放慢速度后重试
+	if c.throttle != nil && resp.StatusCode == http.StatusTooManyRequests {
+		return "", resp.StatusCode, cacheHit, errors.New("请求过于频繁: " + resp.Status)
 	}
 
 	// 检查状态码，某些状态码需要重试
 	if resp.StatusCode >= 500 && resp.StatusCode < 600 {
-		return "", errors.New("服务器错误: " + resp.Status)
+		return "", resp.StatusCode, cacheHit, errors.New("服务器错误: " + resp.Status)
+	}
+
+	return string(bodyBytes), resp.StatusCode, cacheHit, nil
+}
+
+// waitRateLimit 等待到固定限速允许的下一次请求时间
+// 先预留时间再等待，并发请求按预留的顺序依次发出
+func (c *Client) waitRateLimit() {
+	if c.rateInterval <= 0 {
+		return
+	}
+	c.rateMu.Lock()
+	now := time.Now()
+	start := c.nextRequest
+	if start.Before(now) {
+		start = now
 	}
+	c.nextRequest = start.Add(c.rateInterval)
+	c.rateMu.Unlock()
 
-	return string(bodyBytes), nil
+	time.Sleep(time.Until(start))
 }
@@ -2,6 +2,7 @@ package crawler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -550,3 +551,315 @@ func TestRetryMechanism(t *testing.T) {
 		t.Errorf("请求次数不匹配: 期望 3, 实际 %d", requestCount)
 	}
 }
+
+func TestRetryDisabled(t *testing.T) {
+	requestCount := 0
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer testServer.Close()
+
+	client := NewClient(WithRetry(0, time.Millisecond))
+	client.baseURL = testServer.URL
+	if _, err := client.GetPage("/"); err == nil {
+		t.Fatal("服务器错误时GetPage()应该返回错误")
+	}
+	if requestCount != 1 {
+		t.Errorf("禁用重试时请求次数不匹配: 期望 1, 实际 %d", requestCount)
+	}
+
+	// 小于0时保持默认的重试次数
+	if client := NewClient(WithRetry(-1, 0)); client.maxRetries != 3 {
+		t.Errorf("最大重试次数不匹配: 期望 3, 实际 %d", client.maxRetries)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	var times []time.Time
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		times = append(times, time.Now())
+		w.Write([]byte("ok"))
+	}))
+	defer testServer.Close()
+
+	client := NewClient(WithRateLimit(20))
+	client.baseURL = testServer.URL
+	for i := 0; i < 3; i++ {
+		if _, err := client.GetPage("/"); err != nil {
+			t.Fatalf("GetPage()返回错误: %v", err)
+		}
+	}
+	if elapsed := times[2].Sub(times[0]); elapsed < 90*time.Millisecond {
+		t.Errorf("每秒20个请求时3个请求的间隔应至少为100ms, 实际 %v", elapsed)
+	}
+
+	if client := NewClient(WithRateLimit(0)); client.rateInterval != 0 {
+		t.Errorf("速率为0时不应限速, 实际间隔 %v", client.rateInterval)
+	}
+}
+
+func TestRequestLogger(t *testing.T) {
+	requestCount := 0
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+		if requestCount == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte("正常"))
+	}))
+	defer testServer.Close()
+
+	var logs []RequestLog
+	client := NewClient(WithRetry(1, time.Millisecond), WithRequestLogger(func(l RequestLog) { logs = append(logs, l) }))
+	client.baseURL = testServer.URL
+	if _, err := client.GetPage("/page"); err != nil {
+		t.Fatalf("GetPage()返回错误: %v", err)
+	}
+
+	if len(logs) != 2 {
+		t.Fatalf("日志条数不匹配: 期望 2, 实际 %d", len(logs))
+	}
+	if logs[0].Attempt != 1 || logs[0].StatusCode != http.StatusBadGateway || logs[0].Err == nil {
+		t.Errorf("第一次请求的日志不正确: %+v", logs[0])
+	}
+	if logs[1].Attempt != 2 || logs[1].StatusCode != http.StatusOK || logs[1].Err != nil || logs[1].Size != len("正常") {
+		t.Errorf("重试请求的日志不正确: %+v", logs[1])
+	}
+	if logs[1].URL != testServer.URL+"/page" {
+		t.Errorf("URL不匹配: 期望 %s, 实际 %s", testServer.URL+"/page", logs[1].URL)
+	}
+}
+
+func TestClientStats(t *testing.T) {
+	requestCount := 0
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+		if requestCount == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte("正常"))
+	}))
+	defer testServer.Close()
+
+	client := NewClient(WithRetry(1, time.Millisecond))
+	client.baseURL = testServer.URL
+	if _, err := client.GetPage("/page"); err != nil {
+		t.Fatalf("GetPage()返回错误: %v", err)
+	}
+
+	stats := client.Stats()
+	if stats.Requests != 2 || stats.Failures != 1 {
+		t.Errorf("请求计数不正确: %+v", stats)
+	}
+	if stats.Budget != 0 || stats.Remaining != -1 {
+		t.Errorf("未设置预算时应不限制: %+v", stats)
+	}
+	if !stats.BackoffUntil.IsZero() || stats.Adaptive {
+		t.Errorf("未限速时不需要等待: %+v", stats)
+	}
+
+	limited := NewClient(WithRateLimit(0.5))
+	limited.baseURL = testServer.URL
+	if _, err := limited.GetPage("/page"); err != nil {
+		t.Fatalf("GetPage()返回错误: %v", err)
+	}
+	if stats := limited.Stats(); stats.RateLimit != 0.5 || !stats.BackoffUntil.After(time.Now()) {
+		t.Errorf("固定限速下应返回下一个请求的时间: %+v", stats)
+	}
+
+	archived := NewCrawler(WithHTTPClient(client), WithHTMLArchive(t.TempDir()))
+	if archived.Stats().Requests != 2 {
+		t.Errorf("归档客户端应返回被包装客户端的状态: %+v", archived.Stats())
+	}
+	if stats := NewCrawler(WithHTTPClient(&mockClient{})).Stats(); stats.Requests != 0 || stats.Remaining != -1 {
+		t.Errorf("不支持状态的客户端应返回零值: %+v", stats)
+	}
+}
+
+func TestRequestBudget(t *testing.T) {
+	requestCount := 0
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer testServer.Close()
+
+	client := NewClient(WithRetry(3, time.Millisecond), WithRequestBudget(2))
+	client.baseURL = testServer.URL
+
+	_, err := client.GetPage("/page")
+	if !errors.Is(err, ErrBudgetExhausted) {
+		t.Fatalf("预算用完时应返回ErrBudgetExhausted, 实际 %v", err)
+	}
+	if !strings.Contains(err.Error(), "502") {
+		t.Errorf("错误应包含上一次请求的错误: %v", err)
+	}
+	if requestCount != 2 {
+		t.Errorf("预算为2时应只发出2个请求, 实际 %d", requestCount)
+	}
+	if _, err := client.GetPage("/page"); err != ErrBudgetExhausted {
+		t.Errorf("预算用完后应直接返回ErrBudgetExhausted, 实际 %v", err)
+	}
+
+	stats := client.Stats()
+	if stats.Budget != 2 || stats.Remaining != 0 || stats.Requests != 2 || stats.Failures != 2 {
+		t.Errorf("预算状态不正确: %+v", stats)
+	}
+}
+
+func TestMaxResponseSize(t *testing.T) {
+	requestCount := 0
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+		body := strings.Repeat("a", 100)
+		switch r.URL.Path {
+		case "/small":
+			body = "ok"
+		case "/chunked":
+			// 先刷新响应头，没有Content-Length，只能读取时判断大小
+			w.(http.Flusher).Flush()
+		}
+		w.Write([]byte(body))
+	}))
+	defer testServer.Close()
+
+	client := NewClient(WithRetry(3, time.Millisecond), WithMaxResponseSize(10))
+	client.baseURL = testServer.URL
+
+	if content, err := client.GetPage("/small"); err != nil || content != "ok" {
+		t.Fatalf("未超过限制的响应应正常返回, 实际 %q, %v", content, err)
+	}
+	for _, path := range []string{"/large", "/chunked"} {
+		requestCount = 0
+		if _, err := client.GetPage(path); !errors.Is(err, ErrResponseTooLarge) {
+			t.Errorf("%s: 超过限制时应返回ErrResponseTooLarge, 实际 %v", path, err)
+		}
+		if requestCount != 1 {
+			t.Errorf("%s: 响应过大时不应重试, 实际请求 %d 次", path, requestCount)
+		}
+	}
+}
+
+func TestClientMirrors(t *testing.T) {
+	primaryRequests := 0
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		primaryRequests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer primary.Close()
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer down.Close()
+	var mirrorPaths []string
+	var mirror *httptest.Server
+	mirror = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mirrorPaths = append(mirrorPaths, r.URL.Path)
+		w.Write([]byte(`<a href="` + mirror.URL + `/issue/WLB-2024040015">link</a>`))
+	}))
+	defer mirror.Close()
+
+	client := NewClient(WithBaseURL(primary.URL), WithRetry(1, time.Millisecond), WithMirrors(
+		Mirror{BaseURL: down.URL + "/"},
+		Mirror{BaseURL: mirror.URL, Path: func(p string) string { return "/cx" + p }},
+	))
+	content, err := client.GetPage("/exploit/1")
+	if err != nil {
+		t.Fatalf("GetPage()返回错误: %v", err)
+	}
+	if want := `<a href="` + primary.URL + `/issue/WLB-2024040015">link</a>`; content != want {
+		t.Errorf("镜像页面中的链接应替换为基础URL: %s", content)
+	}
+	if len(mirrorPaths) != 1 || mirrorPaths[0] != "/cx/exploit/1" {
+		t.Errorf("应按镜像的Path转换路径: %v", mirrorPaths)
+	}
+	if client.GetBaseURL() != primary.URL {
+		t.Errorf("切换镜像后GetBaseURL应保持不变: %s", client.GetBaseURL())
+	}
+	stats := client.Stats()
+	if stats.BaseURL != mirror.URL || stats.Failovers != 1 || stats.Requests != 5 {
+		t.Errorf("镜像状态不正确: %+v", stats)
+	}
+
+	// 之后的请求直接使用可用的镜像
+	if _, err := client.GetPage("/exploit/2"); err != nil {
+		t.Fatalf("GetPage()返回错误: %v", err)
+	}
+	if primaryRequests != 2 || len(mirrorPaths) != 2 {
+		t.Errorf("切换后不应再请求不可用的站点: 基础URL %d 次, 镜像 %d 次", primaryRequests, len(mirrorPaths))
+	}
+
+	// 所有站点都失败时返回错误
+	mirror.Close()
+	if _, err := client.GetPage("/exploit/3"); err == nil {
+		t.Error("所有站点都不可用时应返回错误")
+	}
+	if stats := client.Stats(); stats.BaseURL != mirror.URL || stats.Failovers != 1 {
+		t.Errorf("所有站点都失败时不应切换: %+v", stats)
+	}
+}
+
+func TestMirrorCanonicalize(t *testing.T) {
+	m := Mirror{
+		BaseURL:   "https://Mirror.example.org/cx",
+		Canonical: func(p string) string { return strings.TrimPrefix(p, "/cx") },
+	}
+	m.links = mirrorLinks(m.BaseURL)
+	for input, want := range map[string]string{
+		`<a href="https://mirror.example.org/cx/issue/WLB-1">`:     `<a href="https://cxsecurity.com/issue/WLB-1">`,
+		`<a href="http://MIRROR.example.org/cx/cveshow/CVE-1/">`:   `<a href="https://cxsecurity.com/cveshow/CVE-1/">`,
+		`<a href="//mirror.example.org/cx/?id=1">`:                 `<a href="https://cxsecurity.com/?id=1">`,
+		`见 https://mirror.example.org/cx。`:                         `见 https://cxsecurity.com。`,
+		`<a href="https://mirror.example.org.evil/cx/issue/1">`:    `<a href="https://mirror.example.org.evil/cx/issue/1">`,
+		`<a href="https://mirror.example.org:8443/cx/issue/1">`:    `<a href="https://mirror.example.org:8443/cx/issue/1">`,
+		`<a href="https://mirror.example.org/cxsecurity/issue/1">`: `<a href="https://mirror.example.org/cxsecurity/issue/1">`,
+	} {
+		if got := m.canonicalize(input, "https://cxsecurity.com"); got != want {
+			t.Errorf("canonicalize(%q) = %q, 期望 %q", input, got, want)
+		}
+	}
+}
+
+func TestMirrorHealthCheck(t *testing.T) {
+	broken := true
+	var primaryPaths []string
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		primaryPaths = append(primaryPaths, r.URL.Path)
+		if broken {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer primary.Close()
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer mirror.Close()
+
+	client := NewClient(WithBaseURL(primary.URL), WithRetry(0, 0), WithMirrors(Mirror{BaseURL: mirror.URL}), WithMirrorHealthCheck(time.Hour, "/health"))
+	_, served, err := client.GetPageMirror("/exploit/1")
+	if err != nil {
+		t.Fatalf("GetPageMirror()返回错误: %v", err)
+	}
+	if served != mirror.URL {
+		t.Errorf("应返回实际使用的镜像: %q", served)
+	}
+
+	// 未到检查时间时继续使用镜像
+	broken = false
+	if _, served, _ = client.GetPageMirror("/exploit/2"); served != mirror.URL || len(primaryPaths) != 1 {
+		t.Errorf("未到检查时间不应请求基础URL: %q, %v", served, primaryPaths)
+	}
+
+	// 到了检查时间后请求健康检查路径，基础URL恢复时切换回来
+	client.lastHealthCheck = time.Now().Add(-2 * time.Hour)
+	if _, served, _ = client.GetPageMirror("/exploit/3"); served != "" {
+		t.Errorf("基础URL恢复后应切换回来: %q", served)
+	}
+	if got := strings.Join(primaryPaths, ","); got != "/exploit/1,/health,/exploit/3" {
+		t.Errorf("请求路径不正确: %s", got)
+	}
+	if stats := client.Stats(); stats.BaseURL != primary.URL || stats.Failovers != 2 || stats.Requests != 5 {
+		t.Errorf("健康检查后的状态不正确: %+v", stats)
+	}
+}
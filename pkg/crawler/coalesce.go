@@ -0,0 +1,61 @@
+package crawler
+
+import (
+	"errors"
+	"sync"
+)
+
+// errFlightPanicked 是执行中panic的调用返回给等待者的错误
+var errFlightPanicked = errors.New("合并的请求执行失败")
+
+// flightGroup 合并相同键的并发调用，与 golang.org/x/sync/singleflight 相同，
+// 同一时间只有第一个调用真正执行，其他调用等待并共享它的结果。零值可以直接使用。
+type flightGroup struct {
+	mu     sync.Mutex
+	calls  map[string]*flightCall
+	shared int // 等待并共享了其他调用结果的次数
+}
+
+// flightCall 是一个正在执行的调用
+type flightCall struct {
+	done      chan struct{}
+	val       any
+	freshness Freshness
+	err       error
+}
+
+// do 执行fn并返回结果，相同key的调用正在执行时等待它完成并返回同一个结果
+// shared表示结果来自其他调用；fn返回的值会被多个调用者共享，调用者修改前需要复制。
+func (g *flightGroup) do(key string, fn func() (any, Freshness, error)) (val any, freshness Freshness, shared bool, err error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall)
+	}
+	if call, ok := g.calls[key]; ok {
+		g.shared++
+		g.mu.Unlock()
+		<-call.done
+		return call.val, call.freshness, true, call.err
+	}
+	call := &flightCall{done: make(chan struct{})}
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	// fn panic时同样需要唤醒等待的调用，否则它们会一直阻塞
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(call.done)
+	}()
+	call.err = errFlightPanicked
+	call.val, call.freshness, call.err = fn()
+	return call.val, call.freshness, false, call.err
+}
+
+// sharedCount 返回共享其他调用结果的次数
+func (g *flightGroup) sharedCount() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.shared
+}
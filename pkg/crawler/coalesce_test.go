@@ -0,0 +1,138 @@
+package crawler
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+// waitShared 等待flightGroup中有n个调用在共享结果
+func waitShared(t *testing.T, g *flightGroup, n int) {
+	require.Eventually(t, func() bool { return g.sharedCount() >= n }, 5*time.Second, time.Millisecond)
+}
+
+func TestFlightGroup(t *testing.T) {
+	var g flightGroup
+	var calls atomic.Int32
+	release := make(chan struct{})
+
+	var wg sync.WaitGroup
+	results := make([]any, 5)
+	shared := make([]bool, 5)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			val, _, s, err := g.do("a", func() (any, Freshness, error) {
+				calls.Add(1)
+				<-release
+				return "result", Freshness{Source: SourceLive}, nil
+			})
+			assert.NoError(t, err)
+			results[i], shared[i] = val, s
+		}(i)
+	}
+	waitShared(t, &g, 4)
+	close(release)
+	wg.Wait()
+
+	assert.EqualValues(t, 1, calls.Load())
+	for _, val := range results {
+		assert.Equal(t, "result", val)
+	}
+	assert.ElementsMatch(t, []bool{false, true, true, true, true}, shared)
+
+	// 完成后的调用重新执行，不同的键互不影响
+	_, _, s, err := g.do("a", func() (any, Freshness, error) { return nil, Freshness{}, errors.New("失败") })
+	assert.Error(t, err)
+	assert.False(t, s)
+	assert.Equal(t, 4, g.sharedCount())
+}
+
+func TestFlightGroupPanic(t *testing.T) {
+	var g flightGroup
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	go func() {
+		defer func() { recover() }()
+		g.do("a", func() (any, Freshness, error) {
+			close(started)
+			<-release
+			panic("解析失败")
+		})
+	}()
+	<-started
+
+	done := make(chan error)
+	go func() {
+		_, _, _, err := g.do("a", func() (any, Freshness, error) { return nil, Freshness{}, nil })
+		done <- err
+	}()
+	waitShared(t, &g, 1)
+	close(release)
+
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, errFlightPanicked)
+	case <-time.After(5 * time.Second):
+		t.Fatal("panic后等待的调用没有被唤醒")
+	}
+}
+
+func TestFetchExploitCoalesced(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join(benchmarkFixtureDir, "vul-detail-response.html"))
+	if err != nil {
+		t.Skip("跳过测试，测试文件不存在：vul-detail-response.html")
+	}
+
+	var requests atomic.Int32
+	release := make(chan struct{})
+	c := NewCrawler(WithHTTPClient(&mockClient{baseURL: "https://cxsecurity.com", getPageFunc: func(path string) (string, error) {
+		requests.Add(1)
+		<-release
+		return string(data), nil
+	}}))
+
+	var wg sync.WaitGroup
+	results := make([]*model.Vulnerability, 10)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// 不带前缀的ID同样被合并
+			id := "WLB-2007030137"
+			if i%2 == 1 {
+				id = "2007030137"
+			}
+			result, _, err := c.FetchExploit(id)
+			assert.NoError(t, err)
+			results[i] = result
+		}(i)
+	}
+	waitShared(t, &c.flights, 9)
+	close(release)
+	wg.Wait()
+
+	assert.EqualValues(t, 1, requests.Load(), "相同的并发请求只应请求一次网站")
+	assert.Equal(t, 9, c.Stats().Coalesced)
+	for _, result := range results[1:] {
+		require.NotNil(t, result)
+		assert.Equal(t, results[0].Title, result.Title)
+		assert.NotSame(t, results[0], result, "每个调用者得到各自的副本")
+	}
+
+	// 修改一个调用者的结果不影响其他调用者
+	require.NotEmpty(t, results[1].AffectedVersions)
+	results[0].AffectedVersions[0] = "changed"
+	assert.NotEqual(t, "changed", results[1].AffectedVersions[0])
+}
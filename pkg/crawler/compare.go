@@ -0,0 +1,177 @@
+package crawler
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+// SnapshotDiff 是两次保存的结果之间的差异，用于审计两次归档之间发生了什么变化
+type SnapshotDiff struct {
+	Added     []model.Vulnerability `json:"added"`     // 只在新快照中出现的漏洞
+	Removed   []model.Vulnerability `json:"removed"`   // 只在旧快照中出现的漏洞
+	Changed   []VulnerabilityChange `json:"changed"`   // 两个快照中都有但字段不同的漏洞
+	Unchanged int                   `json:"unchanged"` // 两个快照中相同的漏洞数
+}
+
+// VulnerabilityChange 是同一漏洞在两个快照中的差异
+type VulnerabilityChange struct {
+	ID     string        `json:"id,omitempty"`
+	URL    string        `json:"url,omitempty"`
+	Title  string        `json:"title,omitempty"` // 新快照中的标题
+	Fields []FieldChange `json:"fields"`          // 按字段名排序
+}
+
+// FieldChange 是一个字段在两个快照中的值，值为该字段的JSON，快照中没有该字段时为空
+type FieldChange struct {
+	Field string          `json:"field"`
+	Old   json.RawMessage `json:"old,omitempty"`
+	New   json.RawMessage `json:"new,omitempty"`
+}
+
+// snapshotRecord 是快照中的一条漏洞及其JSON字段
+type snapshotRecord struct {
+	item   model.Vulnerability
+	fields map[string]json.RawMessage
+}
+
+// CompareSnapshots 比较两个快照中的漏洞，返回新增、删除和变化的漏洞
+// 漏洞按ID匹配，没有ID时按URL匹配，两者都没有的漏洞被忽略。
+// 同一快照中多次出现的漏洞按出现顺序合并字段，后出现的非空字段覆盖之前的值，
+// 这样列表和详情分别保存的同一漏洞不会被当作变化。
+//
+// 参数:
+//   - old: 旧快照中的漏洞
+//   - cur: 新快照中的漏洞
+//   - ignore: 不参与比较的字段，使用JSON字段名，例如 views、comments
+//
+// 返回值:
+//   - *SnapshotDiff: 差异，新增和删除的漏洞按日期从新到旧排列，变化的漏洞按ID排列
+func CompareSnapshots(old, cur []model.Vulnerability, ignore ...string) *SnapshotDiff {
+	skip := make(map[string]bool, len(ignore))
+	for _, field := range ignore {
+		skip[field] = true
+	}
+	before, _ := indexSnapshot(old, skip)
+	after, keys := indexSnapshot(cur, skip)
+
+	diff := &SnapshotDiff{
+		Added:   []model.Vulnerability{},
+		Removed: []model.Vulnerability{},
+		Changed: []VulnerabilityChange{},
+	}
+	for _, key := range keys {
+		a := after[key]
+		b, ok := before[key]
+		if !ok {
+			diff.Added = append(diff.Added, a.item)
+			continue
+		}
+		if fields := changedSnapshotFields(b.fields, a.fields); len(fields) > 0 {
+			diff.Changed = append(diff.Changed, VulnerabilityChange{
+				ID:     a.item.ID,
+				URL:    a.item.URL,
+				Title:  a.item.Title,
+				Fields: fields,
+			})
+		} else {
+			diff.Unchanged++
+		}
+	}
+	for key, b := range before {
+		if _, ok := after[key]; !ok {
+			diff.Removed = append(diff.Removed, b.item)
+		}
+	}
+
+	model.SortVulnerabilities(diff.Added)
+	model.SortVulnerabilities(diff.Removed)
+	sort.Slice(diff.Changed, func(i, j int) bool {
+		if diff.Changed[i].ID != diff.Changed[j].ID {
+			return diff.Changed[i].ID < diff.Changed[j].ID
+		}
+		return diff.Changed[i].URL < diff.Changed[j].URL
+	})
+	return diff
+}
+
+// indexSnapshot 按键合并快照中的漏洞，返回合并后的记录和键的出现顺序
+func indexSnapshot(items []model.Vulnerability, skip map[string]bool) (map[string]*snapshotRecord, []string) {
+	records := make(map[string]*snapshotRecord, len(items))
+	var keys []string
+	for _, item := range items {
+		key := snapshotKey(item)
+		if key == "" {
+			continue
+		}
+		fields := vulnerabilityFields(item, skip)
+		r, ok := records[key]
+		if !ok {
+			records[key] = &snapshotRecord{item: item, fields: fields}
+			keys = append(keys, key)
+			continue
+		}
+		for field, value := range fields {
+			r.fields[field] = value
+		}
+		// 保留合并后的字段，使新增和删除的漏洞包含全部已知信息
+		merged, err := json.Marshal(r.fields)
+		if err == nil {
+			var v model.Vulnerability
+			if json.Unmarshal(merged, &v) == nil {
+				r.item = v
+			}
+		}
+	}
+	return records, keys
+}
+
+// snapshotKey 返回匹配快照中漏洞使用的键，与KnownResults一样未知的ID不作为键
+func snapshotKey(v model.Vulnerability) string {
+	if v.ID != "" && v.ID != "未知" {
+		return "id:" + v.ID
+	}
+	if v.URL != "" {
+		return "url:" + v.URL
+	}
+	return ""
+}
+
+// vulnerabilityFields 返回漏洞的JSON字段，空字段已被omitempty省略
+func vulnerabilityFields(v model.Vulnerability, skip map[string]bool) map[string]json.RawMessage {
+	fields := make(map[string]json.RawMessage)
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fields
+	}
+	var raw map[string]json.RawMessage
+	if json.Unmarshal(data, &raw) != nil {
+		return fields
+	}
+	for field, value := range raw {
+		if skip[field] || string(value) == "null" {
+			continue
+		}
+		fields[field] = value
+	}
+	return fields
+}
+
+// changedSnapshotFields 返回两组字段中值不同的字段，按字段名排序
+func changedSnapshotFields(old, cur map[string]json.RawMessage) []FieldChange {
+	var changes []FieldChange
+	for field, value := range cur {
+		if prev, ok := old[field]; !ok || !bytes.Equal(prev, value) {
+			changes = append(changes, FieldChange{Field: field, Old: old[field], New: value})
+		}
+	}
+	for field, prev := range old {
+		if _, ok := cur[field]; !ok {
+			changes = append(changes, FieldChange{Field: field, Old: prev})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes
+}
@@ -0,0 +1,57 @@
+package crawler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+func TestCompareSnapshots(t *testing.T) {
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	old := []model.Vulnerability{
+		{ID: "WLB-2024050001", Title: "Foo SQL Injection", RiskLevel: "Med.", Date: day, Views: 10},
+		{ID: "WLB-2024050002", Title: "Bar XSS", Date: day},
+		{ID: "WLB-2024050003", Title: "Baz RCE", CVE: "CVE-2024-0001", Date: day},
+		// 没有ID和URL的记录无法匹配，被忽略
+		{Title: "没有键"},
+	}
+	cur := []model.Vulnerability{
+		{ID: "WLB-2024050001", Title: "Foo SQL Injection", RiskLevel: "High", Date: day, Views: 25},
+		{ID: "WLB-2024050003", Title: "Baz RCE", Date: day},
+		// 列表和详情分别保存的同一漏洞合并后比较
+		{ID: "WLB-2024050003", Content: "PoC"},
+		{URL: "https://cxsecurity.com/issue/WLB-2024050004", Title: "Qux LFI", Date: day.AddDate(0, 0, 1)},
+	}
+
+	diff := CompareSnapshots(old, cur, "views")
+	require.Len(t, diff.Added, 1)
+	assert.Equal(t, "Qux LFI", diff.Added[0].Title)
+	require.Len(t, diff.Removed, 1)
+	assert.Equal(t, "WLB-2024050002", diff.Removed[0].ID)
+	assert.Equal(t, 0, diff.Unchanged)
+
+	require.Len(t, diff.Changed, 2)
+	assert.Equal(t, "WLB-2024050001", diff.Changed[0].ID)
+	require.Len(t, diff.Changed[0].Fields, 1, "忽略的字段不参与比较")
+	assert.Equal(t, FieldChange{Field: "risk_level", Old: []byte(`"Med."`), New: []byte(`"High"`)}, diff.Changed[0].Fields[0])
+
+	assert.Equal(t, "WLB-2024050003", diff.Changed[1].ID)
+	require.Len(t, diff.Changed[1].Fields, 2)
+	assert.Equal(t, "content", diff.Changed[1].Fields[0].Field)
+	assert.Nil(t, diff.Changed[1].Fields[0].Old)
+	assert.Equal(t, "cve", diff.Changed[1].Fields[1].Field)
+	assert.Nil(t, diff.Changed[1].Fields[1].New, "新快照中没有的字段")
+}
+
+func TestCompareSnapshotsIdentical(t *testing.T) {
+	items := []model.Vulnerability{{ID: "WLB-2024050001", Title: "Foo"}, {ID: "WLB-2024050002", Title: "Bar"}}
+	diff := CompareSnapshots(items, items)
+	assert.Empty(t, diff.Added)
+	assert.Empty(t, diff.Removed)
+	assert.Empty(t, diff.Changed)
+	assert.Equal(t, 2, diff.Unchanged)
+}
@@ -0,0 +1,47 @@
+package crawler
+
+import (
+	"strings"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/language/display"
+)
+
+// CountryName 返回ISO 3166-1国家代码对应的国家名称
+// 国家数据来自 golang.org/x/text 内置的CLDR数据，覆盖全部ISO 3166国家和地区。
+// cxsecurity使用的非标准代码(例如 "UK")会被转换为标准代码("GB")后再查询。
+//
+// 参数:
+//   - code: 两位国家代码，不区分大小写，例如 "us"、"PL"
+//   - locale: 名称使用的语言，例如 language.English、language.Chinese
+//
+// 返回值:
+//   - string: 标准化后的国家代码，无法识别时为空
+//   - string: 国家名称，无法识别时为空
+//
+// 示例:
+//
+//	CountryName("uk", language.English)  // "GB", "United Kingdom"
+//	CountryName("pl", language.Chinese)  // "PL", "波兰"
+//	CountryName("xx", language.English)  // "", ""
+func CountryName(code string, locale language.Tag) (string, string) {
+	region, err := language.ParseRegion(strings.TrimSpace(code))
+	if err != nil {
+		return "", ""
+	}
+	region = region.Canonicalize()
+	if !region.IsCountry() {
+		return "", ""
+	}
+
+	namer := display.Regions(locale)
+	if namer == nil {
+		namer = display.English.Regions()
+	}
+	name := namer.Name(region)
+	if name == "" {
+		// 部分语言缺少个别地区的名称，回退到英文
+		name = display.English.Regions().Name(region)
+	}
+	return region.String(), name
+}
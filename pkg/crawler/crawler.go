@@ -1,11 +1,10 @@
 package crawler
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
-	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
 
@@ -16,8 +15,23 @@ import (
 // 它负责管理HTTP客户端和HTML解析器，提供高级的爬取功能
 // 支持爬取漏洞列表、漏洞详情、CVE详情和作者信息等
 type Crawler struct {
-	client HTTPClient // HTTP客户端，用于发送请求和获取页面内容
-	parser HTMLParser // HTML解析器，用于解析页面内容并提取数据
+	client          HTTPClient           // HTTP客户端，用于发送请求和获取页面内容
+	parser          HTMLParser           // HTML解析器，用于解析页面内容并提取数据
+	fileMode        os.FileMode          // 输出文件权限
+	dirMode         os.FileMode          // 输出目录权限
+	syncOutput      bool                 // 保存结果时是否调用fsync
+	locale          string               // 本地化名称使用的语言，例如 "zh"
+	classifier      TechniqueClassifier  // ATT&CK技术分类器，为nil时不分类
+	archiveDir      string               // 原始HTML的归档目录，为空时不归档
+	archiveCompress bool                 // 是否压缩归档的HTML
+	relatedPages    int                  // CVE页面相关漏洞列表最多额外请求的分页数
+	provenance      bool                 // 保存结果时是否写入字段来源的旁路文件
+	extras          *ExtraFieldExtractor // 用户声明的额外字段，为nil时不提取
+	fetchPolicy     FetchPolicy          // 获取漏洞详情和CVE详情时是否使用本地存储
+	fetchTTL        time.Duration        // 本地记录的有效期，0表示永不过期
+	local           LocalStore           // 读穿透模式使用的本地存储，为nil时总是从网络获取
+	labels          *LabelMapper         // 风险级别和标签的映射表，为nil时使用内置映射表
+	flights         flightGroup          // 合并 FetchExploit、FetchCveDetail 的相同并发请求
 }
 
 // CrawlerOption 是设置Crawler选项的函数类型
@@ -37,8 +51,35 @@ func WithClientOptions(options ...ClientOption) CrawlerOption {
 	}
 }
 
+// WithHTTPClient 设置自定义HTTP客户端
+// 允许用户提供自己的HTTPClient实现，例如从本地归档文件读取页面的客户端
+// 参数:
+//   - client: 自定义的HTTP客户端实现
+//
+// 返回值:
+//   - CrawlerOption: 返回一个配置函数
+func WithHTTPClient(client HTTPClient) CrawlerOption {
+	return func(c *Crawler) {
+		c.client = client
+	}
+}
+
+// WithLocale 设置本地化名称使用的语言
+// 目前用于作者信息中的国家名称：默认只输出英文名称，
+// 设置后会在CountryLocalized中额外输出该语言的名称。
+// 参数:
+//   - locale: BCP 47语言标记，例如 "zh"、"de"
+//
+// 返回值:
+//   - CrawlerOption: 返回一个配置函数
+func WithLocale(locale string) CrawlerOption {
+	return func(c *Crawler) {
+		c.locale = locale
+	}
+}
+
 // WithCustomParser 设置自定义解析器
-// 允许用户提供自己的HTML解析器实现
+// 允许用户提供自己的HTML解析器实现，WithLabelMapper 不影响自定义解析器
 // 参数:
 //   - parser: 自定义的HTML解析器实现
 //
@@ -60,8 +101,10 @@ func WithCustomParser(parser HTMLParser) CrawlerOption {
 func NewCrawler(options ...CrawlerOption) *Crawler {
 	// 创建默认配置的爬虫
 	crawler := &Crawler{
-		client: NewClient(),
-		parser: NewParser(),
+		client:       NewClient(),
+		fileMode:     DefaultFileMode,
+		dirMode:      DefaultDirMode,
+		relatedPages: DefaultRelatedPages,
 	}
 
 	// 应用选项
@@ -69,9 +112,55 @@ func NewCrawler(options ...CrawlerOption) *Crawler {
 		option(crawler)
 	}
 
+	if crawler.parser == nil {
+		crawler.parser = NewParser(WithParserLabels(crawler.labels))
+	}
+
+	if crawler.archiveDir != "" {
+		archive := NewHTMLArchiveClient(crawler.client, crawler.archiveDir)
+		archive.fileMode, archive.dirMode = crawler.fileMode, crawler.dirMode
+		archive.compress = crawler.archiveCompress
+		crawler.client = archive
+	}
+
 	return crawler
 }
 
+// StatsClient 是可以报告请求和限速状态的HTTP客户端，Client 和 HTMLArchiveClient 都实现了这个接口
+type StatsClient interface {
+	Stats() ClientStats
+}
+
+// MirrorClient 是可以报告页面由哪个镜像返回的HTTP客户端，Client 和 HTMLArchiveClient 都实现了这个接口
+type MirrorClient interface {
+	GetPageMirror(path string) (content string, mirror string, err error)
+}
+
+// getPage 获取页面内容和返回页面的镜像，客户端没有实现 MirrorClient 时镜像为空
+func (c *Crawler) getPage(path string) (string, string, error) {
+	if client, ok := c.client.(MirrorClient); ok {
+		return client.GetPageMirror(path)
+	}
+	content, err := c.client.GetPage(path)
+	return content, "", err
+}
+
+// Stats 返回HTTP客户端的请求数、剩余预算和限速状态，以及合并的并发请求数，用于了解爬取变慢的原因
+// 自定义的HTTP客户端没有实现 StatsClient 时请求和限速状态为零值，Remaining为-1
+//
+// 示例:
+//
+//	stats := c.Stats()
+//	fmt.Printf("已请求 %d 次，剩余 %d 次\n", stats.Requests, stats.Remaining)
+func (c *Crawler) Stats() ClientStats {
+	stats := ClientStats{Remaining: -1}
+	if client, ok := c.client.(StatsClient); ok {
+		stats = client.Stats()
+	}
+	stats.Coalesced = c.flights.sharedCount()
+	return stats
+}
+
 // CrawlPage 爬取指定页面并保存结果
 // 用于爬取漏洞列表页面，支持将结果保存到文件
 // 参数:
@@ -97,6 +186,7 @@ func (c *Crawler) CrawlPage(path string, outputPath string) (*model.Vulnerabilit
 	if err != nil {
 		return nil, fmt.Errorf("解析页面内容失败: %w", err)
 	}
+	c.classify(result.Items)
 
 	// 保存结果
 	if outputPath != "" {
@@ -128,16 +218,24 @@ func (c *Crawler) CrawlVulnerabilityDetail(path string, outputPath string) (*mod
 	}
 
 	// 获取页面内容
-	htmlContent, err := c.client.GetPage(path)
+	htmlContent, mirror, err := c.getPage(path)
 	if err != nil {
 		return nil, fmt.Errorf("获取漏洞详情页面内容失败: %w", err)
 	}
 
-	// 解析页面内容
-	result, err := c.parser.ParseVulnerabilityDetailPage(htmlContent)
+	// 解析页面内容，启用字段来源时同时记录每个字段的来源
+	var result *model.Vulnerability
+	var fields Provenance
+	if pp, ok := c.parser.(ProvenanceParser); ok && c.provenance && outputPath != "" {
+		result, fields, err = pp.ParseVulnerabilityDetailPageWithProvenance(htmlContent)
+	} else {
+		result, err = c.parser.ParseVulnerabilityDetailPage(htmlContent)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("解析漏洞详情页面内容失败: %w", err)
 	}
+	result.Extras = c.extractExtras(htmlContent, PageVulnerability, fields)
+	result.Mirror = mirror
 
 	// 设置URL (由于HTML内容中不含完整URL)
 	// 修复URL重复问题，避免前缀重复
@@ -149,12 +247,18 @@ func (c *Crawler) CrawlVulnerabilityDetail(path string, outputPath string) (*mod
 		}
 		result.URL = c.client.GetBaseURL() + cleanPath
 	}
+	c.classifyOne(result)
 
 	// 保存结果
 	if outputPath != "" {
 		if err := c.saveVulnerabilityDetailResult(result, outputPath); err != nil {
 			return nil, fmt.Errorf("保存漏洞详情结果失败: %w", err)
 		}
+		if fields != nil {
+			if err := c.saveProvenance(result.URL, PageVulnerability, fields, outputPath); err != nil {
+				return nil, fmt.Errorf("保存字段来源失败: %w", err)
+			}
+		}
 	}
 
 	return result, nil
@@ -174,26 +278,102 @@ func (c *Crawler) CrawlVulnerabilityDetail(path string, outputPath string) (*mod
 //
 //	result, err := crawler.CrawlCveDetail("CVE-2024-21413", "cve.json")
 func (c *Crawler) CrawlCveDetail(cveID string, outputPath string) (*model.CveDetail, error) {
-	// 构建URL路径
-	path := fmt.Sprintf("/cveshow/%s/", cveID)
+	result, _, err := c.cveDetail(cveID, outputPath)
+	return result, err
+}
+
+// FetchCveDetail 获取CVE详情，同时返回结果的获取时间和来源
+// 与 CrawlCveDetail 相同，使用 WithReadThrough 时结果可能来自本地存储。
+// 同一CVE的并发调用只请求一次网站，其他调用等待并得到同一结果的深拷贝，
+// 各调用者可以独立修改得到的结果。
+//
+// 示例:
+//
+//	result, freshness, err := crawler.FetchCveDetail("CVE-2024-21413")
+func (c *Crawler) FetchCveDetail(cveID string) (*model.CveDetail, Freshness, error) {
+	id, err := model.ParseCVEID(cveID)
+	if err != nil {
+		return nil, Freshness{}, err
+	}
+	val, freshness, _, err := c.flights.do("cve/"+id.String(), func() (any, Freshness, error) {
+		return c.cveDetail(id.String(), "")
+	})
+	if err != nil {
+		return nil, Freshness{}, err
+	}
+	return val.(*model.CveDetail).Clone(), freshness, nil
+}
+
+// cveDetail 按读穿透策略获取CVE详情并保存结果
+func (c *Crawler) cveDetail(cveID string, outputPath string) (*model.CveDetail, Freshness, error) {
+	// 规范化CVE编号并构建URL路径
+	id, err := model.ParseCVEID(cveID)
+	if err != nil {
+		return nil, Freshness{}, err
+	}
+	if !c.readThrough() {
+		result, err := c.crawlCveDetail(id, outputPath)
+		return result, LiveFreshness(time.Now()), err
+	}
+
+	// 读穿透模式下只有从网络获取成功时结果已经保存，使用本地记录时另外保存
+	saved := false
+	result, freshness, err := c.localCveDetail(id.String(), func() (*model.CveDetail, error) {
+		result, err := c.crawlCveDetail(id, outputPath)
+		saved = err == nil
+		return result, err
+	})
+	if err != nil {
+		return nil, Freshness{}, err
+	}
+	if outputPath != "" && !saved {
+		if err := c.saveCveDetailResult(result, outputPath); err != nil {
+			return nil, Freshness{}, fmt.Errorf("保存CVE详情结果失败: %w", err)
+		}
+	}
+	return result, freshness, nil
+}
+
+// crawlCveDetail 从网络获取CVE详情页面并解析
+func (c *Crawler) crawlCveDetail(id model.CVEID, outputPath string) (*model.CveDetail, error) {
+	cveID := id.String()
 
 	// 获取页面内容
-	htmlContent, err := c.client.GetPage(path)
+	htmlContent, mirror, err := c.getPage(id.Path())
 	if err != nil {
 		return nil, fmt.Errorf("获取CVE详情页面内容失败: %w", err)
 	}
 
-	// 解析页面内容
-	result, err := c.parser.ParseCveDetailPage(htmlContent)
+	// 解析页面内容，启用字段来源时同时记录每个字段的来源
+	var result *model.CveDetail
+	var fields Provenance
+	if pp, ok := c.parser.(ProvenanceParser); ok && c.provenance && outputPath != "" {
+		result, fields, err = pp.ParseCveDetailPageWithProvenance(htmlContent)
+	} else {
+		result, err = c.parser.ParseCveDetailPage(htmlContent)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("解析CVE详情页面内容失败: %w", err)
 	}
+	result.Extras = c.extractExtras(htmlContent, PageCve, fields)
+	result.Mirror = mirror
+	if c.relatedPages > 0 {
+		if err := c.crawlRelatedPages(result, htmlContent, cveID); err != nil {
+			return nil, err
+		}
+	}
+	c.classify(result.RelatedVulnerabilities)
 
 	// 保存结果
 	if outputPath != "" {
 		if err := c.saveCveDetailResult(result, outputPath); err != nil {
 			return nil, fmt.Errorf("保存CVE详情结果失败: %w", err)
 		}
+		if fields != nil {
+			if err := c.saveProvenance(c.client.GetBaseURL()+id.Path(), PageCve, fields, outputPath); err != nil {
+				return nil, fmt.Errorf("保存字段来源失败: %w", err)
+			}
+		}
 	}
 
 	return result, nil
@@ -246,59 +426,103 @@ func (c *Crawler) CrawlCveDetail(cveID string, outputPath string) (*model.CveDet
 // 2. 保存文件时会自动创建必要的目录
 // 3. 返回的接口类型需要根据实际情况转换为具体类型
 func (c *Crawler) CrawlExploit(id string, outputPath string, fields string) (interface{}, error) {
-	// 确定路径
-	var path string
 	if id == "" {
 		// 默认爬取漏洞列表页面
-		path = "/exploit/1"
-	} else {
-		// 检查ID是否已包含WLB-前缀
-		if strings.HasPrefix(id, "WLB-") {
-			path = "/issue/" + id
-		} else {
-			path = "/issue/WLB-" + id
-		}
+		return c.CrawlExploitList(1, outputPath)
 	}
 
-	// 根据路径判断是爬取详情页还是列表页
-	if strings.Contains(path, "/issue/WLB-") {
-		// 如果是详情页面，调用详情页面爬取
-		result, err := c.CrawlVulnerabilityDetail(path, outputPath)
-		if err != nil {
-			return nil, err
-		}
+	result, _, err := c.exploitDetail(id, outputPath)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
 
-		// 提取漏洞ID，并添加到结果中
-		if result.URL != "" && strings.Contains(result.URL, "WLB-") {
-			idx := strings.Index(result.URL, "WLB-")
-			result.ID = result.URL[idx:]
-		}
+// FetchExploit 获取漏洞详情，同时返回结果的获取时间和来源
+// 与 CrawlExploit 相同，使用 WithReadThrough 时结果可能来自本地存储。
+// 同一漏洞的并发调用只请求一次网站，其他调用等待并得到同一结果的深拷贝，
+// 各调用者可以独立修改得到的结果。
+//
+// 示例:
+//
+//	result, freshness, err := crawler.FetchExploit("WLB-2024040015")
+func (c *Crawler) FetchExploit(id string) (*model.Vulnerability, Freshness, error) {
+	wlbID, err := model.ParseWLBID(id)
+	if err != nil {
+		return nil, Freshness{}, err
+	}
+	val, freshness, _, err := c.flights.do("exploit/"+wlbID.String(), func() (any, Freshness, error) {
+		return c.exploitDetail(wlbID.String(), "")
+	})
+	if err != nil {
+		return nil, Freshness{}, err
+	}
+	return val.(*model.Vulnerability).Clone(), freshness, nil
+}
 
-		return result, nil
-	} else {
-		// 如果是列表页面，调用列表页面爬取
-		result, err := c.CrawlPage(path, outputPath)
-		if err != nil {
-			return nil, err
-		}
+// exploitDetail 按读穿透策略获取漏洞详情并保存结果
+func (c *Crawler) exploitDetail(id string, outputPath string) (*model.Vulnerability, Freshness, error) {
+	// 规范化ID，去掉或补全WLB-前缀
+	wlbID, err := model.ParseWLBID(id)
+	if err != nil {
+		return nil, Freshness{}, err
+	}
+	if !c.readThrough() {
+		result, err := c.crawlExploitDetail(wlbID, outputPath)
+		return result, LiveFreshness(time.Now()), err
+	}
 
-		// 处理每个漏洞项目，确保ID字段有值
-		for i := range result.Items {
-			if result.Items[i].URL != "" {
-				if idx := strings.Index(result.Items[i].URL, "WLB-"); idx != -1 {
-					// 提取URL中的ID
-					urlPart := result.Items[i].URL[idx:]
-					endIdx := len(urlPart)
-					if slashIdx := strings.IndexByte(urlPart, '/'); slashIdx != -1 {
-						endIdx = slashIdx
-					}
-					result.Items[i].ID = urlPart[:endIdx]
-				}
-			}
+	// 读穿透模式下只有从网络获取成功时结果已经保存，使用本地记录时另外保存
+	saved := false
+	result, freshness, err := c.localVulnerability(wlbID.String(), func() (*model.Vulnerability, error) {
+		result, err := c.crawlExploitDetail(wlbID, outputPath)
+		saved = err == nil
+		return result, err
+	})
+	if err != nil {
+		return nil, Freshness{}, err
+	}
+	if outputPath != "" && !saved {
+		if err := c.saveVulnerabilityDetailResult(result, outputPath); err != nil {
+			return nil, Freshness{}, fmt.Errorf("保存漏洞详情结果失败: %w", err)
 		}
+	}
+	return result, freshness, nil
+}
 
-		return result, nil
+// crawlExploitDetail 从网络获取漏洞详情页面，并根据URL或ID设置漏洞ID
+func (c *Crawler) crawlExploitDetail(wlbID model.WLBID, outputPath string) (*model.Vulnerability, error) {
+	result, err := c.CrawlVulnerabilityDetail(wlbID.Path(), outputPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// 提取漏洞ID，并添加到结果中
+	if urlID := model.WLBIDFromURL(result.URL); urlID != "" {
+		result.ID = urlID.String()
+	} else {
+		result.ID = wlbID.String()
 	}
+
+	return result, nil
+}
+
+// CrawlExploitList 爬取最新漏洞利用列表的指定页并保存结果
+// 等同于 CrawlSection(SectionExploit, page, outputPath)
+//
+// 参数:
+//   - page: 页码，从1开始，小于1时按1处理
+//   - outputPath: 结果保存路径，为空则不保存
+//
+// 返回值:
+//   - *model.VulnerabilityList: 解析后的漏洞列表
+//   - error: 如果发生错误则返回错误信息
+//
+// 示例:
+//
+//	list, err := crawler.CrawlExploitList(2, "")
+func (c *Crawler) CrawlExploitList(page int, outputPath string) (*model.VulnerabilityList, error) {
+	return c.CrawlSection(SectionExploit, page, outputPath)
 }
 
 // CrawlAuthor 爬取作者信息页面并解析作者的详细资料
@@ -350,7 +574,7 @@ func (c *Crawler) CrawlAuthor(authorID string, outputPath string) (*model.Author
 	}
 
 	// 解析页面内容
-	authorParser := NewAuthorParser()
+	authorParser := NewAuthorParser(WithAuthorLocale(c.locale), WithAuthorLabels(c.labels))
 	result, err := authorParser.Parse(doc)
 	if err != nil {
 		return nil, fmt.Errorf("解析作者页面内容失败: %w", err)
@@ -360,6 +584,7 @@ func (c *Crawler) CrawlAuthor(authorID string, outputPath string) (*model.Author
 	if result.ID == "" {
 		result.ID = authorID
 	}
+	c.classify(result.Vulnerabilities)
 
 	// 保存结果
 	if outputPath != "" {
@@ -396,26 +621,7 @@ func (c *Crawler) CrawlAuthor(authorID string, outputPath string) (*model.Author
 //	    log.Fatal(err)
 //	}
 func (c *Crawler) saveResult(result *model.VulnerabilityList, outputPath string) error {
-	// 创建目录
-	dir := filepath.Dir(outputPath)
-	if dir != "" && dir != "." {
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			return err
-		}
-	}
-
-	// 将结果序列化为JSON
-	data, err := json.MarshalIndent(result, "", "  ")
-	if err != nil {
-		return err
-	}
-
-	// 写入文件
-	if err := os.WriteFile(outputPath, data, 0644); err != nil {
-		return err
-	}
-
-	return nil
+	return c.writeJSON(result, outputPath)
 }
 
 // saveVulnerabilityDetailResult 将漏洞详情保存到JSON文件中
@@ -443,26 +649,7 @@ func (c *Crawler) saveResult(result *model.VulnerabilityList, outputPath string)
 //	    log.Fatal(err)
 //	}
 func (c *Crawler) saveVulnerabilityDetailResult(result *model.Vulnerability, outputPath string) error {
-	// 创建目录
-	dir := filepath.Dir(outputPath)
-	if dir != "" && dir != "." {
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			return err
-		}
-	}
-
-	// 将结果序列化为JSON
-	data, err := json.MarshalIndent(result, "", "  ")
-	if err != nil {
-		return err
-	}
-
-	// 写入文件
-	if err := os.WriteFile(outputPath, data, 0644); err != nil {
-		return err
-	}
-
-	return nil
+	return c.writeJSON(result, outputPath)
 }
 
 // saveCveDetailResult 将CVE详情保存到JSON文件中
@@ -490,26 +677,7 @@ func (c *Crawler) saveVulnerabilityDetailResult(result *model.Vulnerability, out
 //	    log.Fatal(err)
 //	}
 func (c *Crawler) saveCveDetailResult(result *model.CveDetail, outputPath string) error {
-	// 创建目录
-	dir := filepath.Dir(outputPath)
-	if dir != "" && dir != "." {
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			return err
-		}
-	}
-
-	// 将结果序列化为JSON
-	data, err := json.MarshalIndent(result, "", "  ")
-	if err != nil {
-		return err
-	}
-
-	// 写入文件
-	if err := os.WriteFile(outputPath, data, 0644); err != nil {
-		return err
-	}
-
-	return nil
+	return c.writeJSON(result, outputPath)
 }
 
 // saveAuthorResult 将作者信息保存到JSON文件中
@@ -537,24 +705,5 @@ func (c *Crawler) saveCveDetailResult(result *model.CveDetail, outputPath string
 //	    log.Fatal(err)
 //	}
 func (c *Crawler) saveAuthorResult(result *model.AuthorProfile, outputPath string) error {
-	// 创建目录
-	dir := filepath.Dir(outputPath)
-	if dir != "" && dir != "." {
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			return err
-		}
-	}
-
-	// 将结果序列化为JSON
-	data, err := json.MarshalIndent(result, "", "  ")
-	if err != nil {
-		return err
-	}
-
-	// 写入文件
-	if err := os.WriteFile(outputPath, data, 0644); err != nil {
-		return err
-	}
-
-	return nil
+	return c.writeJSON(result, outputPath)
 }
@@ -380,7 +380,7 @@ func TestCrawlExploit(t *testing.T) {
 	}
 
 	// 测试空ID (应该爬取列表)
-	err = crawler.CrawlExploit("", outputPath, "")
+	_, err = crawler.CrawlExploit("", outputPath, "")
 	if err != nil {
 		t.Fatalf("CrawlExploit()返回错误: %v", err)
 	}
@@ -395,7 +395,7 @@ func TestCrawlExploit(t *testing.T) {
 	}
 
 	// 测试带ID (应该爬取详情)
-	err = crawler.CrawlExploit("12345", outputPath, "")
+	_, err = crawler.CrawlExploit("12345", outputPath, "")
 	if err != nil {
 		t.Fatalf("CrawlExploit()带ID返回错误: %v", err)
 	}
@@ -455,3 +455,52 @@ func TestNewCrawlerWithOptions(t *testing.T) {
 		t.Errorf("重试次数不匹配: 期望 %d, 实际 %d", expectedRequests, requestCount)
 	}
 }
+
+func TestCrawlExploitList(t *testing.T) {
+	requestedPath := ""
+	crawler := &Crawler{
+		client: &mockClient{
+			getPageFunc: func(path string) (string, error) {
+				requestedPath = path
+				return "<html>mock html</html>", nil
+			},
+			baseURL: "https://example.com",
+		},
+		parser: &mockParser{
+			parseListPageFunc: func(htmlContent string) (*model.VulnerabilityList, error) {
+				return &model.VulnerabilityList{
+					Items: []model.Vulnerability{
+						{Title: "测试漏洞1", URL: "https://cxsecurity.com/issue/WLB-2024040035"},
+						{Title: "测试漏洞2", URL: "https://cxsecurity.com/issue/WLB-2024040036/"},
+						{Title: "测试漏洞3", URL: "https://example.com/other"},
+					},
+					CurrentPage: 3,
+					TotalPages:  5,
+				}, nil
+			},
+		},
+	}
+
+	result, err := crawler.CrawlExploitList(3, "")
+	if err != nil {
+		t.Fatalf("CrawlExploitList()返回错误: %v", err)
+	}
+	if requestedPath != "/exploit/3" {
+		t.Errorf("请求路径不正确: 期望 '/exploit/3', 实际 '%s'", requestedPath)
+	}
+
+	expected := []string{"WLB-2024040035", "WLB-2024040036", ""}
+	for i, item := range result.Items {
+		if item.ID != expected[i] {
+			t.Errorf("第%d条记录的ID不正确: 期望 '%s', 实际 '%s'", i+1, expected[i], item.ID)
+		}
+	}
+
+	// 页码小于1时爬取第一页
+	if _, err := crawler.CrawlExploitList(0, ""); err != nil {
+		t.Fatalf("CrawlExploitList()返回错误: %v", err)
+	}
+	if requestedPath != "/exploit/1" {
+		t.Errorf("请求路径不正确: 期望 '/exploit/1', 实际 '%s'", requestedPath)
+	}
+}
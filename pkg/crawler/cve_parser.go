@@ -24,6 +24,7 @@ import (
 //   - 漏洞描述
 //   - 漏洞类型 (CWE)
 //   - CVSS评分 (基础分、影响分、利用分)
+//   - CVSS v3评分 (页面中有CVSS3表格时)
 //   - 漏洞属性 (攻击范围、复杂度、认证要求等)
 //   - 受影响的软件列表
 //   - 参考链接
@@ -70,10 +71,25 @@ import (
 //
 // 注意事项:
 //  1. 日期解析支持多种格式: "2006-01-02", "02.01.2006", "2.1.2006"
-//  2. CVSS评分从标签文本中提取数值，格式为 "X.Y/10"
+//  2. CVSS评分从标签文本中提取数值，格式为 "X.Y/10"；CVSS v2和v3表格按表格前的CVSS2/CVSS3标题区分
 //  3. 相关漏洞的风险等级会被转换为标准格式 (High/Medium/Low)
 //  4. 参考链接从onclick属性中提取，确保是有效的HTTP(S)链接
 func (p *Parser) ParseCveDetailPage(htmlContent string) (*model.CveDetail, error) {
+	return p.parseCveDetail(htmlContent, nil)
+}
+
+// ParseCveDetailPageWithProvenance 与 ParseCveDetailPage 相同，同时返回每个字段的来源
+func (p *Parser) ParseCveDetailPageWithProvenance(htmlContent string) (*model.CveDetail, Provenance, error) {
+	prov := Provenance{}
+	result, err := p.parseCveDetail(htmlContent, prov)
+	if err != nil {
+		return nil, nil, err
+	}
+	return result, prov, nil
+}
+
+// parseCveDetail 解析CVE详情页面，prov不为nil时记录每个字段的来源
+func (p *Parser) parseCveDetail(htmlContent string, prov Provenance) (*model.CveDetail, error) {
 	if strings.TrimSpace(htmlContent) == "" {
 		return nil, fmt.Errorf("HTML content is empty")
 	}
@@ -88,6 +104,9 @@ func (p *Parser) ParseCveDetailPage(htmlContent string) (*model.CveDetail, error
 	// 提取CVE编号
 	// 从页面的h1标签中提取CVE编号，格式如 "CVE-2024-21413"
 	cveDetail.CveID = strings.TrimSpace(doc.Find("h1 strong").First().Text())
+	if cveDetail.CveID != "" {
+		prov.set("cve_id", "h1 strong")
+	}
 
 	// 提取发布日期和修改日期
 	// 在center标签中查找Published和Modified日期
@@ -101,6 +120,7 @@ func (p *Parser) ParseCveDetailPage(htmlContent string) (*model.CveDetail, error
 			if len(matches) > 1 {
 				if published, err := time.Parse("2006-01-02", matches[1]); err == nil {
 					cveDetail.Published = published
+					prov.set("published", "center > b:contains('Published:') + 正则 "+re.String())
 				}
 			}
 		} else if strings.Contains(text, "Modified:") {
@@ -109,6 +129,7 @@ func (p *Parser) ParseCveDetailPage(htmlContent string) (*model.CveDetail, error
 			if len(matches) > 1 {
 				if modified, err := time.Parse("2006-01-02", matches[1]); err == nil {
 					cveDetail.Modified = modified
+					prov.set("modified", "center > b:contains('Modified:') + 正则 "+re.String())
 				}
 			}
 		}
@@ -118,11 +139,27 @@ func (p *Parser) ParseCveDetailPage(htmlContent string) (*model.CveDetail, error
 	// 在Description标签后的h6标签中提取完整的漏洞描述文本
 	descriptionCell := doc.Find("td:contains('Description:')").Closest("tr").Next().Find("td h6")
 	cveDetail.Description = strings.TrimSpace(descriptionCell.Text())
+	if cveDetail.Description != "" {
+		prov.set("description", "td:contains('Description:') 所在行的下一行 td h6")
+	}
 
 	// 提取漏洞类型 (CWE)
-	// 在Type字段后查找指向CWE的链接，提取CWE类型名称
-	typeLink := doc.Find("b:contains('Type:')").Parent().Find("a[href*='/cwe/']")
+	// 在Type字段后查找指向CWE的链接，提取CWE类型名称、数字编号和链接
+	typeLink := doc.Find("b:contains('Type:')").Parent().Find("a[href*='/cwe/']").First()
 	cveDetail.Type = strings.TrimSpace(typeLink.Text())
+	if href, ok := typeLink.Attr("href"); ok {
+		cveDetail.CweURL = cweURL(href)
+	}
+	cveDetail.CweID = parseCweID(cveDetail.Type, cveDetail.CweURL)
+	if cveDetail.Type != "" {
+		prov.set("type", "b:contains('Type:') 的父元素中的 a[href*='/cwe/']")
+	}
+	if cveDetail.CweURL != "" {
+		prov.set("cwe_url", "b:contains('Type:') 的父元素中的 a[href*='/cwe/'] @href")
+	}
+	if cveDetail.CweID != 0 {
+		prov.set("cwe_id", "type 或 cwe_url + 正则 "+cweNumberPattern.String())
+	}
 
 	// --- 提取CVSS评分 ---
 	// 从CVSS评分表格中提取三个评分：
@@ -130,24 +167,21 @@ func (p *Parser) ParseCveDetailPage(htmlContent string) (*model.CveDetail, error
 	// 2. 影响评分 (Impact Score)
 	// 3. 利用评分 (Exploit Score)
 	// 评分格式为 "X.Y/10"，使用正则表达式提取数值部分
-	cvssTable := doc.Find("b:contains('CVSS Base Score')").Closest("table")
-	cvssDataRow := cvssTable.Find("tr").Eq(1) // 数据在第二行 (索引1)
-	if cvssDataRow.Length() > 0 {
-		cells := cvssDataRow.Find("td")
-		if cells.Length() >= 3 {
-			scoreText1 := cells.Eq(0).Find("span.label").Text()
-			scoreText2 := cells.Eq(1).Find("span.label").Text()
-			scoreText3 := cells.Eq(2).Find("span.label").Text()
-
-			if matches := regexp.MustCompile(`([\d.]+)/10`).FindStringSubmatch(scoreText1); len(matches) >= 2 {
-				cveDetail.CvssBaseScore, _ = strconv.ParseFloat(matches[1], 64)
-			}
-			if matches := regexp.MustCompile(`([\d.]+)/10`).FindStringSubmatch(scoreText2); len(matches) >= 2 {
-				cveDetail.CvssImpactScore, _ = strconv.ParseFloat(matches[1], 64)
-			}
-			if matches := regexp.MustCompile(`([\d.]+)/10`).FindStringSubmatch(scoreText3); len(matches) >= 2 {
-				cveDetail.CvssExploitScore, _ = strconv.ParseFloat(matches[1], 64)
-			}
+	// 有CVSS v3评分的页面中有两个评分表格，v3表格不参与v2评分的解析
+	cvss3, cvss3Table := parseCvss3(doc)
+	cveDetail.Cvss3 = cvss3
+	cvssTable := doc.Find("b:contains('CVSS Base Score')").Closest("table").NotSelection(cvss3Table).First()
+	cveDetail.CvssBaseScore, cveDetail.CvssImpactScore, cveDetail.CvssExploitScore = parseCvssScores(cvssTable)
+	if cvss3 != nil {
+		prov.set("cvss3", "h4:has(b:contains('CVSS3')) + 正则 "+cvss3VectorPattern.String()+"，之后的评分表格")
+	}
+	for field, score := range map[string]float64{
+		"cvss_base_score":    cveDetail.CvssBaseScore,
+		"cvss_impact_score":  cveDetail.CvssImpactScore,
+		"cvss_exploit_score": cveDetail.CvssExploitScore,
+	} {
+		if score != 0 {
+			prov.set(field, "table:has(b:contains('CVSS Base Score')) 第二行 span.label (不含CVSS3表格)")
 		}
 	}
 
@@ -195,6 +229,18 @@ func (p *Parser) ParseCveDetailPage(htmlContent string) (*model.CveDetail, error
 	cveDetail.ConfidentialityImpact = attrValues["Confidentiality impact"]
 	cveDetail.IntegrityImpact = attrValues["Integrity impact"]
 	cveDetail.AvailabilityImpact = attrValues["Availability impact"]
+	for field, header := range map[string]string{
+		"exploit_range":          "Exploit range",
+		"attack_complexity":      "Attack complexity",
+		"authentication":         "Authentication",
+		"confidentiality_impact": "Confidentiality impact",
+		"integrity_impact":       "Integrity impact",
+		"availability_impact":    "Availability impact",
+	} {
+		if attrValues[header] != "" {
+			prov.set(field, "table:has(b:contains('Exploit range')) 中标题为 "+header+" 的列")
+		}
+	}
 
 	// 提取受影响的软件
 	// 从表格中提取每个受影响的软件条目：
@@ -207,9 +253,9 @@ func (p *Parser) ParseCveDetailPage(htmlContent string) (*model.CveDetail, error
 			vendorA := links.Eq(0)
 			productA := links.Eq(1)
 			vendorName := strings.TrimSpace(vendorA.Text())
-			vendorURL, _ := vendorA.Attr("href")
+			vendorURL := sanitizedHref(vendorA)
 			productName := strings.TrimSpace(productA.Text())
-			productURL, _ := productA.Attr("href")
+			productURL := sanitizedHref(productA)
 			if vendorName != "" && productName != "" {
 				cveDetail.AffectedSoftware = append(cveDetail.AffectedSoftware, model.AffectedSoftware{
 					VendorName:  vendorName,
@@ -221,6 +267,10 @@ func (p *Parser) ParseCveDetailPage(htmlContent string) (*model.CveDetail, error
 		}
 	})
 
+	if len(cveDetail.AffectedSoftware) > 0 {
+		prov.set("affected_software", "table.table-striped:has(th:contains('Affected software')) tbody tr td a")
+	}
+
 	// 提取参考链接
 	// 从onclick属性中提取参考链接URL
 	// 只保留以http开头的有效链接
@@ -231,13 +281,17 @@ func (p *Parser) ParseCveDetailPage(htmlContent string) (*model.CveDetail, error
 			matches := regexp.MustCompile(`window\.open\('([^']*)'`).FindStringSubmatch(onclickAttr)
 			if len(matches) > 1 {
 				link := strings.TrimSpace(matches[1])
-				if link != "" && strings.HasPrefix(link, "http") {
+				if link != "" && strings.HasPrefix(link, "http") && SanitizeURL(link) != "" {
 					cveDetail.References = append(cveDetail.References, link)
 				}
 			}
 		}
 	})
 
+	if len(cveDetail.References) > 0 {
+		prov.set("references", "td:contains('References:') 所在行的下一行 div[onclick] 中的 window.open 地址")
+	}
+
 	// 提取相关漏洞
 	// 从WLB2数据库表格中提取相关漏洞信息：
 	// - 风险等级
@@ -250,10 +304,10 @@ func (p *Parser) ParseCveDetailPage(htmlContent string) (*model.CveDetail, error
 		relatedRows.Slice(1, goquery.ToEnd).Each(func(j int, tr *goquery.Selection) {
 			cells := tr.Find("td")
 			if cells.Length() >= 4 {
-				riskLevel := strings.TrimSpace(cells.Eq(0).Find("span.label").Text())
+				riskLevel := p.labels.orDefault().Risk(cells.Eq(0).Find("span.label").Text())
 				titleA := cells.Eq(1).Find("a")
 				title := strings.TrimSpace(titleA.Text())
-				url, _ := titleA.Attr("href")
+				url := sanitizedHref(titleA)
 				author := strings.TrimSpace(cells.Eq(2).Text())
 				dateStr := strings.TrimSpace(cells.Eq(3).Text())
 				var date time.Time
@@ -277,6 +331,121 @@ func (p *Parser) ParseCveDetailPage(htmlContent string) (*model.CveDetail, error
 			}
 		})
 	}
+	if len(cveDetail.RelatedVulnerabilities) > 0 {
+		prov.set("related_vulnerabilities", "center:contains('See advisories in our WLB2 database') 所在单元格中的表格")
+	}
+
+	// 保留网站新增、模型中还没有的字段
+	cveDetail.UnknownFields = parseUnknownCveFields(doc, prov)
 
 	return cveDetail, nil
 }
+
+var (
+	// cvssScorePattern 匹配评分表格中 "X.Y/10" 格式的评分
+	cvssScorePattern = regexp.MustCompile(`([\d.]+)/10`)
+
+	// cvss3VectorPattern 匹配CVSS v3向量，CVSS:3.x前缀可以省略
+	cvss3VectorPattern = regexp.MustCompile(`(?:CVSS:(3\.[01])/)?(AV:[NALP]/AC:[LH]/PR:[NLH]/UI:[NR]/S:[UC]/C:[NLH]/I:[NLH]/A:[NLH])`)
+)
+
+// parseCvssScores 从评分表格的第二行中提取基础评分、影响评分和可利用性评分
+func parseCvssScores(table *goquery.Selection) (base, impact, exploit float64) {
+	cells := table.Find("tr").Eq(1).Find("td") // 数据在第二行 (索引1)
+	if cells.Length() < 3 {
+		return 0, 0, 0
+	}
+	scores := make([]float64, 3)
+	for i := range scores {
+		if matches := cvssScorePattern.FindStringSubmatch(cells.Eq(i).Find("span.label").Text()); len(matches) >= 2 {
+			scores[i], _ = strconv.ParseFloat(matches[1], 64)
+		}
+	}
+	return scores[0], scores[1], scores[2]
+}
+
+// parseCvss3 解析CVSS v3评分，页面中没有CVSS3标题时返回nil
+// CVSS3标题的格式与CVSS2相同：<h4><a ...><b>CVSS3</b></a> => (CVSS:3.1/AV:N/...)</h4>，后面是评分表格。
+// 第二个返回值是v3的评分表格，没有时为空的选择集。
+func parseCvss3(doc *goquery.Document) (*model.Cvss3, *goquery.Selection) {
+	header := doc.Find("h4").FilterFunction(func(_ int, s *goquery.Selection) bool {
+		return strings.Contains(s.Find("b").Text(), "CVSS3")
+	}).First()
+	if header.Length() == 0 {
+		return nil, header
+	}
+
+	cvss := &model.Cvss3{}
+	source := header.Text()
+	if href, ok := header.Find("a").Attr("href"); ok {
+		source += " " + href
+	}
+	if m := cvss3VectorPattern.FindStringSubmatch(source); m != nil {
+		cvss.Version = m[1]
+		if cvss.Version == "" {
+			cvss.Version = "3.1"
+		}
+		cvss.Vector = "CVSS:" + cvss.Version + "/" + m[2]
+	}
+
+	// 评分表格是标题之后第一个包含CVSS Base Score的表格，中间出现其他标题时说明没有表格
+	table := header.NextAllFiltered("table, h4").First().Filter("table").FilterFunction(func(_ int, s *goquery.Selection) bool {
+		return strings.Contains(s.Text(), "CVSS Base Score")
+	})
+	cvss.BaseScore, cvss.ImpactScore, cvss.ExploitScore = parseCvssScores(table)
+
+	if cvss.Vector == "" && cvss.BaseScore == 0 {
+		return nil, table
+	}
+	if cvss.BaseScore > 0 || table.Length() > 0 {
+		cvss.Severity = Cvss3Severity(cvss.BaseScore)
+	}
+	return cvss, table
+}
+
+// Cvss3Severity 按CVSS v3.x规范返回评分对应的严重程度
+func Cvss3Severity(score float64) string {
+	switch {
+	case score >= 9:
+		return "Critical"
+	case score >= 7:
+		return "High"
+	case score >= 4:
+		return "Medium"
+	case score > 0:
+		return "Low"
+	}
+	return "None"
+}
+
+// cweNumberPattern 用于从CWE标签或链接中提取数字编号
+var cweNumberPattern = regexp.MustCompile(`(?i)CWE-(\d+)`)
+
+// cweURL 把CWE链接补全为绝对地址
+func cweURL(href string) string {
+	href = strings.TrimSpace(href)
+	switch {
+	case href == "":
+		return ""
+	case strings.HasPrefix(href, "http://"), strings.HasPrefix(href, "https://"):
+		return href
+	case strings.HasPrefix(href, "/"):
+		return "https://cxsecurity.com" + href
+	default:
+		return "https://cxsecurity.com/" + href
+	}
+}
+
+// parseCweID 从CWE标签或链接中提取数字编号，优先使用标签
+//
+// CWE-Other、NVD-CWE-noinfo 等没有数字编号的类型返回0
+func parseCweID(label, link string) int {
+	for _, s := range []string{label, link} {
+		if m := cweNumberPattern.FindStringSubmatch(s); m != nil {
+			if id, err := strconv.Atoi(m[1]); err == nil && id > 0 {
+				return id
+			}
+		}
+	}
+	return 0
+}
@@ -2,11 +2,15 @@ package crawler
 
 import (
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
 )
 
 func TestParseCveDetailPage(t *testing.T) {
@@ -44,6 +48,8 @@ func TestParseCveDetailPage(t *testing.T) {
 
 	// 漏洞类型
 	assert.Equal(t, "CWE-Other", result.Type, "漏洞类型(CWE)不匹配")
+	assert.Zero(t, result.CweID, "CWE-Other没有数字编号")
+	assert.Equal(t, "https://cxsecurity.com/cwe/CWE-Other/", result.CweURL, "CWE链接不匹配")
 
 	// 漏洞属性
 	assert.Equal(t, "Remote", result.ExploitRange, "利用范围不匹配")
@@ -81,3 +87,83 @@ func TestParseCveDetailPage(t *testing.T) {
 		assert.Equal(t, expectedVulnDate.Format("2006-01-02"), vuln.Date.Format("2006-01-02"), "相关漏洞日期不匹配") // 比较格式化后的日期字符串
 	}
 }
+
+func BenchmarkParseCveDetailPage(b *testing.B) {
+	htmlContent := loadBenchmarkFixture(b, "cve-show-detail-response.html")
+	parser := NewParser()
+
+	b.SetBytes(int64(len(htmlContent)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := parser.ParseCveDetailPage(htmlContent); err != nil {
+			b.Fatalf("解析失败: %v", err)
+		}
+	}
+}
+
+func TestParseCveDetailPageCvss3(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join(benchmarkFixtureDir, "cve-show-detail-response.html"))
+	if err != nil {
+		t.Skip("跳过测试，样本文件不存在：cve-show-detail-response.html")
+	}
+	page := string(data)
+
+	// 样本页面只有CVSS v2评分
+	result, err := NewParser().ParseCveDetailPage(page)
+	require.NoError(t, err)
+	assert.Nil(t, result.Cvss3)
+
+	// 在CVSS2之前插入CVSS3标题和评分表格，v2评分保持不变
+	cvss3 := `<h4><A HREF="https://nvd.nist.gov/vuln-metrics/cvss/v3-calculator?vector=AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H&version=3.1" TITLE="NVD CVSS3"><B>CVSS3</B></A> => (CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H)</h4>
+<TABLE><TR><TD><CENTER><B>CVSS Base Score</B></CENTER></TD><TD><CENTER><B>Impact Subscore</B></CENTER></TD><TD><CENTER><B>Exploitability Subscore</B></CENTER></TD></TR><TR>
+<TD><CENTER><h6><span class="label label-danger">9.8/10</span></h6></CENTER></TD>
+<TD><CENTER><h6><span class="label label-danger">5.9/10</span></h6></CENTER></TD>
+<TD><CENTER><h6><span class="label label-danger">3.9/10</span></h6></CENTER></TD></TR></TABLE>
+`
+	marker := `<h4><A HREF="http://nvd.nist.gov/cvss.cfm?version=2`
+	require.Contains(t, page, marker)
+	result, err = NewParser().ParseCveDetailPage(strings.Replace(page, marker, cvss3+marker, 1))
+	require.NoError(t, err)
+	require.NotNil(t, result.Cvss3)
+	assert.Equal(t, model.Cvss3{
+		Version:      "3.1",
+		Vector:       "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+		BaseScore:    9.8,
+		ImpactScore:  5.9,
+		ExploitScore: 3.9,
+		Severity:     "Critical",
+	}, *result.Cvss3)
+	assert.Equal(t, 6.8, result.CvssBaseScore)
+	assert.Equal(t, 8.6, result.CvssExploitScore)
+
+	// 只有向量没有评分表格
+	result, err = NewParser().ParseCveDetailPage(strings.Replace(page, marker, `<h4><B>CVSS3</B> => (CVSS:3.0/AV:L/AC:H/PR:L/UI:R/S:C/C:L/I:N/A:N)</h4>`+marker, 1))
+	require.NoError(t, err)
+	require.NotNil(t, result.Cvss3)
+	assert.Equal(t, "CVSS:3.0/AV:L/AC:H/PR:L/UI:R/S:C/C:L/I:N/A:N", result.Cvss3.Vector)
+	assert.Zero(t, result.Cvss3.BaseScore)
+	assert.Empty(t, result.Cvss3.Severity)
+	assert.Equal(t, 6.8, result.CvssBaseScore)
+}
+
+func TestCvss3Severity(t *testing.T) {
+	assert.Equal(t, "None", Cvss3Severity(0))
+	assert.Equal(t, "Low", Cvss3Severity(3.9))
+	assert.Equal(t, "Medium", Cvss3Severity(4))
+	assert.Equal(t, "High", Cvss3Severity(8.9))
+	assert.Equal(t, "Critical", Cvss3Severity(9))
+}
+
+func TestParseCweID(t *testing.T) {
+	assert.Equal(t, 79, parseCweID("CWE-79", "https://cxsecurity.com/cwe/CWE-79/"))
+	assert.Equal(t, 22, parseCweID("", "/cwe/CWE-22/"))
+	assert.Equal(t, 119, parseCweID("cwe-119", ""))
+	assert.Zero(t, parseCweID("CWE-Other", "https://cxsecurity.com/cwe/CWE-Other/"))
+	assert.Zero(t, parseCweID("NVD-CWE-noinfo", ""))
+
+	assert.Equal(t, "https://cxsecurity.com/cwe/CWE-79/", cweURL("/cwe/CWE-79/"))
+	assert.Equal(t, "https://cxsecurity.com/cwe/CWE-79/", cweURL("cwe/CWE-79/"))
+	assert.Equal(t, "https://cxsecurity.com/cwe/CWE-79/", cweURL(" https://cxsecurity.com/cwe/CWE-79/ "))
+	assert.Empty(t, cweURL(""))
+}
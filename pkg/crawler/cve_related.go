@@ -0,0 +1,131 @@
+package crawler
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+// DefaultRelatedPages 是CVE页面相关漏洞列表默认最多额外请求的分页数
+const DefaultRelatedPages = 10
+
+// WithRelatedPages 设置CVE页面相关漏洞列表最多额外请求的分页数
+// 相关漏洞较多时页面只显示第一页，爬取CVE详情时会跟随分页链接收集完整列表
+// 参数:
+//   - maxPages: 最多额外请求的页数，小于等于0时不跟随分页
+//
+// 返回值:
+//   - CrawlerOption: 返回一个配置函数
+func WithRelatedPages(maxPages int) CrawlerOption {
+	return func(c *Crawler) {
+		c.relatedPages = maxPages
+	}
+}
+
+// relatedPageNumberPattern 匹配分页链接中的页码，例如 /cveshow/CVE-2007-1411/2/ 或 ?page=2
+var relatedPageNumberPattern = regexp.MustCompile(`(?:/cveshow/CVE-\d+-\d+/(\d+)/?$|[?&]page=(\d+))`)
+
+// relatedPagePaths 从CVE页面的相关漏洞区域中提取其他分页的路径
+//
+// 只接受指向同一CVE的链接，结果按页码排序，第1页不包含在内
+func relatedPagePaths(htmlContent, cveID string) []string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlContent))
+	if err != nil {
+		return nil
+	}
+	section := doc.Find("td > center:contains('See advisories in our WLB2 database')").Closest("td")
+	if section.Length() == 0 {
+		return nil
+	}
+
+	pages := make(map[int]string)
+	section.Find("a[href]").Each(func(_ int, a *goquery.Selection) {
+		href, _ := a.Attr("href")
+		u, err := url.Parse(strings.TrimSpace(href))
+		if err != nil || !strings.Contains(strings.ToUpper(u.Path), "/CVESHOW/"+strings.ToUpper(cveID)) {
+			return
+		}
+		m := relatedPageNumberPattern.FindStringSubmatch(u.RequestURI())
+		if m == nil {
+			return
+		}
+		n, _ := strconv.Atoi(m[1] + m[2])
+		if n > 1 {
+			pages[n] = u.RequestURI()
+		}
+	})
+
+	numbers := make([]int, 0, len(pages))
+	for n := range pages {
+		numbers = append(numbers, n)
+	}
+	sort.Ints(numbers)
+	paths := make([]string, 0, len(numbers))
+	for _, n := range numbers {
+		paths = append(paths, pages[n])
+	}
+	return paths
+}
+
+// crawlRelatedPages 跟随相关漏洞列表的分页，把其他页的相关漏洞合并到result中
+//
+// 新发现的分页链接也会继续跟随，最多请求 c.relatedPages 个页面；
+// 还有分页没有请求时 result.RelatedTruncated 为true
+func (c *Crawler) crawlRelatedPages(result *model.CveDetail, htmlContent, cveID string) error {
+	queue := relatedPagePaths(htmlContent, cveID)
+	if len(queue) == 0 {
+		return nil
+	}
+
+	seenPages := make(map[string]bool)
+	seenVulns := make(map[string]bool)
+	for _, v := range result.RelatedVulnerabilities {
+		seenVulns[relatedKey(v)] = true
+	}
+
+	requested := 0
+	for len(queue) > 0 {
+		path := queue[0]
+		queue = queue[1:]
+		if seenPages[path] {
+			continue
+		}
+		if requested >= c.relatedPages {
+			result.RelatedTruncated = true
+			break
+		}
+		seenPages[path] = true
+		requested++
+
+		content, err := c.client.GetPage(path)
+		if err != nil {
+			return fmt.Errorf("获取相关漏洞分页 %s 失败: %w", path, err)
+		}
+		page, err := c.parser.ParseCveDetailPage(content)
+		if err != nil {
+			return fmt.Errorf("解析相关漏洞分页 %s 失败: %w", path, err)
+		}
+		for _, v := range page.RelatedVulnerabilities {
+			if key := relatedKey(v); !seenVulns[key] {
+				seenVulns[key] = true
+				result.RelatedVulnerabilities = append(result.RelatedVulnerabilities, v)
+			}
+		}
+		queue = append(queue, relatedPagePaths(content, cveID)...)
+	}
+	return nil
+}
+
+// relatedKey 返回相关漏洞的去重键，优先使用链接
+func relatedKey(v model.Vulnerability) string {
+	if v.URL != "" {
+		return v.URL
+	}
+	return v.Title
+}
@@ -0,0 +1,90 @@
+package crawler
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// relatedFixturePage 在样本CVE页面的相关漏洞表格中替换为指定的漏洞，并在表格后加上分页链接
+func relatedFixturePage(t *testing.T, wlb string, pages ...int) string {
+	data, err := os.ReadFile(filepath.Join(benchmarkFixtureDir, "cve-show-detail-response.html"))
+	if err != nil {
+		t.Skip("跳过测试，样本文件不存在：cve-show-detail-response.html")
+	}
+	page := strings.ReplaceAll(string(data), "WLB-2007030105", wlb)
+
+	var links strings.Builder
+	links.WriteString(`<ul class="pagination">`)
+	for _, n := range pages {
+		fmt.Fprintf(&links, `<li><a href="https://cxsecurity.com/cveshow/CVE-2007-1411/%d/">%d</a></li>`, n, n)
+	}
+	links.WriteString(`<li><a href="https://cxsecurity.com/cveshow/CVE-2007-9999/2/">other</a></li></ul>`)
+
+	marker := `</TR></TABLE><P><CENTER><B>Type:</B>`
+	require.Contains(t, page, marker)
+	return strings.Replace(page, marker, `</TR></TABLE>`+links.String()+`<P><CENTER><B>Type:</B>`, 1)
+}
+
+func TestRelatedPagePaths(t *testing.T) {
+	page := relatedFixturePage(t, "WLB-2007030105", 3, 1, 2, 3)
+	assert.Equal(t, []string{"/cveshow/CVE-2007-1411/2/", "/cveshow/CVE-2007-1411/3/"}, relatedPagePaths(page, "CVE-2007-1411"))
+
+	// 没有分页链接
+	page = relatedFixturePage(t, "WLB-2007030105")
+	assert.Empty(t, relatedPagePaths(page, "CVE-2007-1411"))
+}
+
+func TestCrawlCveDetailRelatedPages(t *testing.T) {
+	pages := map[string]string{
+		"/cveshow/CVE-2007-1411/":   relatedFixturePage(t, "WLB-2007030101", 2),
+		"/cveshow/CVE-2007-1411/2/": relatedFixturePage(t, "WLB-2007030102", 1, 3),
+		"/cveshow/CVE-2007-1411/3/": relatedFixturePage(t, "WLB-2007030101", 1, 2),
+	}
+	var requested []string
+	client := &mockClient{getPageFunc: func(path string) (string, error) {
+		requested = append(requested, path)
+		if page, ok := pages[path]; ok {
+			return page, nil
+		}
+		return "", fmt.Errorf("unexpected path %s", path)
+	}}
+
+	// 跟随全部分页，重复的相关漏洞只保留一次
+	c := NewCrawler(WithHTTPClient(client))
+	result, err := c.CrawlCveDetail("CVE-2007-1411", "")
+	require.NoError(t, err)
+	require.Len(t, result.RelatedVulnerabilities, 2)
+	assert.Equal(t, "https://cxsecurity.com/issue/WLB-2007030101", result.RelatedVulnerabilities[0].URL)
+	assert.Equal(t, "https://cxsecurity.com/issue/WLB-2007030102", result.RelatedVulnerabilities[1].URL)
+	assert.False(t, result.RelatedTruncated)
+	assert.Equal(t, []string{"/cveshow/CVE-2007-1411/", "/cveshow/CVE-2007-1411/2/", "/cveshow/CVE-2007-1411/3/"}, requested)
+
+	// 限制请求数时标记为未获取完整
+	requested = nil
+	c = NewCrawler(WithHTTPClient(client), WithRelatedPages(1))
+	result, err = c.CrawlCveDetail("CVE-2007-1411", "")
+	require.NoError(t, err)
+	assert.Len(t, result.RelatedVulnerabilities, 2)
+	assert.True(t, result.RelatedTruncated)
+	assert.Len(t, requested, 2)
+
+	// 不跟随分页
+	requested = nil
+	c = NewCrawler(WithHTTPClient(client), WithRelatedPages(0))
+	result, err = c.CrawlCveDetail("CVE-2007-1411", "")
+	require.NoError(t, err)
+	assert.Len(t, result.RelatedVulnerabilities, 1)
+	assert.False(t, result.RelatedTruncated)
+	assert.Len(t, requested, 1)
+
+	// 分页请求失败时返回错误
+	delete(pages, "/cveshow/CVE-2007-1411/3/")
+	_, err = NewCrawler(WithHTTPClient(client)).CrawlCveDetail("CVE-2007-1411", "")
+	assert.ErrorContains(t, err, "/cveshow/CVE-2007-1411/3/")
+}
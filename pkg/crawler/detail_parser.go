@@ -22,6 +22,9 @@ import (
 // - 发布日期：支持多种日期格式（YYYY.MM.DD、YYYY-MM-DD等）
 // - 作者信息：包括作者名称和个人主页URL
 // - 其他标签：漏洞类型、平台等信息
+// - 正文：公告内容和PoC代码等原始文本
+// - 描述、受影响版本和平台：从正文的常见写法中提取，见 parseAdvisoryFields
+// - 模型中还没有的标签和值：例如CVSS评分，保存在UnknownFields中，见 parseUnknownDetailFields
 //
 // 参数:
 //   - htmlContent: 详情页面的HTML内容
@@ -64,6 +67,21 @@ import (
 // 3. 标签会自动去重，避免重复
 // 4. 作者URL会根据需要处理为完整路径
 func (p *Parser) ParseVulnerabilityDetailPage(htmlContent string) (*model.Vulnerability, error) {
+	return p.parseVulnerabilityDetail(htmlContent, nil)
+}
+
+// ParseVulnerabilityDetailPageWithProvenance 与 ParseVulnerabilityDetailPage 相同，同时返回每个字段的来源
+func (p *Parser) ParseVulnerabilityDetailPageWithProvenance(htmlContent string) (*model.Vulnerability, Provenance, error) {
+	prov := Provenance{}
+	result, err := p.parseVulnerabilityDetail(htmlContent, prov)
+	if err != nil {
+		return nil, nil, err
+	}
+	return result, prov, nil
+}
+
+// parseVulnerabilityDetail 解析漏洞详情页面，prov不为nil时记录每个字段的来源
+func (p *Parser) parseVulnerabilityDetail(htmlContent string, prov Provenance) (*model.Vulnerability, error) {
 	if strings.TrimSpace(htmlContent) == "" {
 		return nil, fmt.Errorf("HTML content is empty")
 	}
@@ -79,15 +97,23 @@ func (p *Parser) ParseVulnerabilityDetailPage(htmlContent string) (*model.Vulner
 
 	// 提取标题 - 更精确的选择器
 	vulnerability.Title = strings.TrimSpace(doc.Find("h4 > B").First().Text())
-	if vulnerability.Title == "" {
+	if vulnerability.Title != "" {
+		prov.set("title", "h4 > B")
+	} else {
 		// 增加更多备选方案或日志记录
 		// log.Println("Could not find title with primary selector, trying alternatives...")
 		vulnerability.Title = strings.TrimSpace(doc.Find(".panel-body h4 b").First().Text()) // 尝试另一个常见的结构
+		if vulnerability.Title != "" {
+			prov.set("title", ".panel-body h4 b (备选)")
+		}
 	}
 
 	// 提取风险级别 - 定位包含 "Risk:" 的 well 内部的 label
 	riskLevelLabel := doc.Find(".well-sm:contains('Risk:')").Find("span.label")
-	vulnerability.RiskLevel = strings.TrimSpace(riskLevelLabel.Text())
+	vulnerability.RiskLevel = p.labels.orDefault().Risk(riskLevelLabel.Text())
+	if vulnerability.RiskLevel != "" {
+		prov.set("risk_level", ".well-sm:contains('Risk:') span.label")
+	}
 
 	// 正则表达式用于提取CVE和CWE编号
 	cvePattern := regexp.MustCompile(`CVE-\d{4}-\d+`)
@@ -100,8 +126,10 @@ func (p *Parser) ParseVulnerabilityDetailPage(htmlContent string) (*model.Vulner
 		// 使用正则表达式匹配CVE编号
 		if matches := cvePattern.FindStringSubmatch(cveText); len(matches) > 0 {
 			vulnerability.CVE = matches[0]
+			prov.set("cve", ".well-sm:contains('CVE:') a[href*='cveshow'] + 正则 "+cvePattern.String())
 		} else {
 			vulnerability.CVE = cveText // 如果无法匹配模式，保留原始文本
+			prov.set("cve", ".well-sm:contains('CVE:') a[href*='cveshow'] (原始文本)")
 		}
 	}
 
@@ -112,13 +140,16 @@ func (p *Parser) ParseVulnerabilityDetailPage(htmlContent string) (*model.Vulner
 		// 使用正则表达式匹配CWE编号
 		if matches := cwePattern.FindStringSubmatch(cweText); len(matches) > 0 {
 			vulnerability.CWE = matches[0]
+			prov.set("cwe", ".well-sm:contains('CWE:') a[href*='cwe'] + 正则 "+cwePattern.String())
 		} else {
 			vulnerability.CWE = cweText // 如果无法匹配模式，保留原始文本
+			prov.set("cwe", ".well-sm:contains('CWE:') a[href*='cwe'] (原始文本)")
 		}
 	}
 
 	// 提取Local状态 - 设置bool字段
 	doc.Find(".well-sm:contains('Local:')").Each(func(_ int, s *goquery.Selection) {
+		prov.set("is_local", ".well-sm:contains('Local:') b")
 		s.Find("b, B").Each(func(_ int, b *goquery.Selection) {
 			if strings.TrimSpace(b.Text()) == "Yes" {
 				vulnerability.IsLocal = true
@@ -128,6 +159,7 @@ func (p *Parser) ParseVulnerabilityDetailPage(htmlContent string) (*model.Vulner
 
 	// 提取Remote状态 - 设置bool字段
 	doc.Find(".well-sm:contains('Remote:')").Each(func(_ int, s *goquery.Selection) {
+		prov.set("is_remote", ".well-sm:contains('Remote:') b")
 		s.Find("b, B").Each(func(_ int, b *goquery.Selection) {
 			if strings.TrimSpace(b.Text()) == "Yes" {
 				vulnerability.IsRemote = true
@@ -153,6 +185,7 @@ func (p *Parser) ParseVulnerabilityDetailPage(htmlContent string) (*model.Vulner
 		for _, format := range formats {
 			if t, err := time.Parse(format, dateText); err == nil {
 				vulnerability.Date = t
+				prov.set("date", ".panel-body .row .col-xs-12.col-md-3 .well-sm b + 格式 "+format)
 				break
 			}
 		}
@@ -162,7 +195,13 @@ func (p *Parser) ParseVulnerabilityDetailPage(htmlContent string) (*model.Vulner
 	authorSelection := doc.Find(".well-sm:contains('Credit:')").Find("a[href*='author']")
 	if authorSelection.Length() > 0 {
 		vulnerability.Author = strings.TrimSpace(authorSelection.Text())
-		vulnerability.AuthorURL, _ = authorSelection.Attr("href")
+		vulnerability.AuthorURL = sanitizedHref(authorSelection)
+		if vulnerability.Author != "" {
+			prov.set("author", ".well-sm:contains('Credit:') a[href*='author']")
+		}
+		if vulnerability.AuthorURL != "" {
+			prov.set("author_url", ".well-sm:contains('Credit:') a[href*='author'] @href")
+		}
 		// 确保 AuthorURL 是相对路径或绝对路径
 		if vulnerability.AuthorURL != "" && !strings.HasPrefix(vulnerability.AuthorURL, "/") && !strings.HasPrefix(vulnerability.AuthorURL, "http") {
 			// 如果需要，添加基础 URL 或 "/"
@@ -186,23 +225,35 @@ func (p *Parser) ParseVulnerabilityDetailPage(htmlContent string) (*model.Vulner
 		// 寻找可能的标签值
 		labelText := strings.TrimSpace(s.Find("label, span.label").Text())
 		if labelText != "" && labelText != "N/A" && !strings.Contains(labelText, ":") {
-			vulnerability.Tags = append(vulnerability.Tags, labelText)
+			vulnerability.Tags = append(vulnerability.Tags, p.labels.orDefault().Tag(labelText))
 		}
 	})
 
-	// --- 去重 Tags ---
+	// 去重并排序标签
+	vulnerability.Tags = model.SortTags(vulnerability.Tags)
 	if len(vulnerability.Tags) > 0 {
-		uniqueTags := make(map[string]struct{})
-		var result []string
-		for _, tag := range vulnerability.Tags {
-			if _, exists := uniqueTags[tag]; !exists {
-				uniqueTags[tag] = struct{}{}
-				result = append(result, tag)
-			}
+		prov.set("tags", ".well-sm label, span.label (不含 CVE/CWE/Local/Remote/Risk/Credit)")
+	}
+
+	// 提取正文 - 正文位于premex中，保留原有的换行和缩进
+	premex := doc.Find("div.premex").First()
+	content := strings.ReplaceAll(premex.Text(), "\r\n", "\n")
+	vulnerability.Content = strings.Trim(content, "\n\t ")
+	if vulnerability.Content != "" {
+		prov.set("content", "div.premex")
+		// 正文中的HTML在保存和通过API提供之前清理，防止在浏览器中显示时执行PoC中的脚本
+		if fragment, err := premex.Html(); err == nil {
+			vulnerability.ContentHTML = strings.Trim(SanitizeHTML(strings.ReplaceAll(fragment, "\r\n", "\n")), "\n\t ")
+			prov.set("content_html", "div.premex 的HTML，经过 SanitizeHTML 清理")
 		}
-		vulnerability.Tags = result
 	}
-	// --- End 去重 Tags ---
+
+	fields := parseAdvisoryFieldsProvenance(vulnerability.Content, vulnerability.Title, prov)
+	vulnerability.Description = fields.Description
+	vulnerability.AffectedVersions = fields.AffectedVersions
+	vulnerability.Platform = fields.Platform
+	vulnerability.Source = parseSourceAttribution(doc, vulnerability.Content, prov)
+	vulnerability.UnknownFields = parseUnknownDetailFields(doc, prov)
 
 	return vulnerability, nil
 }
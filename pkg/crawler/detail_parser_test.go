@@ -2,6 +2,9 @@ package crawler
 
 import (
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
@@ -43,6 +46,42 @@ func TestParseVulnerabilityDetailPage(t *testing.T) {
 	assert.Contains(t, result.Tags, "CVE-2007-1475", "标签应包含CVE")
 	assert.Contains(t, result.Tags, "CWE-119", "标签应包含CWE")
 	assert.Contains(t, result.Tags, "Local", "标签应包含Local")
+	assert.True(t, sort.StringsAreSorted(result.Tags), "标签应按字母顺序排列")
 	// Remote 标签在此HTML中不存在，所以不检查
 	// assert.Contains(t, result.Tags, "Remote", "标签应包含Remote")
 }
+
+func BenchmarkParseVulnerabilityDetailPage(b *testing.B) {
+	htmlContent := loadBenchmarkFixture(b, "vul-detail-response.html")
+	parser := NewParser()
+
+	b.SetBytes(int64(len(htmlContent)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := parser.ParseVulnerabilityDetailPage(htmlContent); err != nil {
+			b.Fatalf("解析失败: %v", err)
+		}
+	}
+}
+
+func TestParseVulnerabilityDetailPageContent(t *testing.T) {
+	htmlContent, err := os.ReadFile(filepath.Join(benchmarkFixtureDir, "vul-detail-response.html"))
+	if err != nil {
+		t.Skip("跳过测试，样本文件不存在：vul-detail-response.html")
+	}
+
+	result, err := NewParser().ParseVulnerabilityDetailPage(string(htmlContent))
+	assert.NoError(t, err)
+
+	// 正文中的HTML实体已解码，换行保留
+	assert.True(t, strings.HasPrefix(result.Content, "<?php"), "正文应以PoC代码开头")
+	assert.Contains(t, result.Content, "\n// by rgod\n")
+	assert.Contains(t, result.Content, `die("only works with interbase extension ");`)
+	assert.True(t, strings.HasSuffix(result.Content, "original url: http://retrogod.altervista.org/php_446_ibase_connect_bof.html"))
+
+	// 正文只有PoC代码，没有描述；版本来自标题，平台来自注释
+	assert.Empty(t, result.Description)
+	assert.Equal(t, []string{"<= 4.4.6"}, result.AffectedVersions)
+	assert.Equal(t, "Windows", result.Platform)
+}
@@ -0,0 +1,212 @@
+package crawler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/cascadia"
+)
+
+// ExtraField 是用户在规则文件中声明的额外字段
+// 网站上有但模型中没有的数据，可以不修改代码，通过CSS选择器提取到结果的 extras 中
+type ExtraField struct {
+	Name     string `json:"name"`            // 字段名，即 extras 中的键
+	Page     string `json:"page"`            // 页面类型，PageVulnerability 或 PageCve
+	Selector string `json:"selector"`        // CSS选择器，取第一个匹配的元素
+	Attr     string `json:"attr,omitempty"`  // 取元素的属性，为空时取元素的文本
+	Regex    string `json:"regex,omitempty"` // 对取到的值匹配正则，有分组时取第一个分组，没有匹配时不输出该字段
+}
+
+// ExtraFieldExtractor 按ExtraField规则从页面中提取额外字段
+type ExtraFieldExtractor struct {
+	fields   []ExtraField
+	selector map[string]goquery.Matcher // 页面类型/字段名 -> 编译后的选择器
+	patterns map[string]*regexp.Regexp  // 页面类型/字段名 -> 编译后的正则
+}
+
+// Rules 是规则文件的内容
+type Rules struct {
+	Fields []ExtraField   `json:"fields,omitempty"` // 额外字段规则
+	Labels []LabelMapping `json:"labels,omitempty"` // 标签映射，在 DefaultLabelMappings 之后应用
+}
+
+// LoadRules 从JSON文件读取规则
+// 文件内容可以是只包含额外字段的ExtraField数组，也可以是包含 fields 和 labels 的对象。
+//
+// 示例文件:
+//
+//	{
+//	  "fields": [{"name": "dork", "page": "vulnerability", "selector": ".well-sm:contains('Dork:') b"}],
+//	  "labels": [{"kind": "risk", "canonical": "High", "aliases": ["kritisch"]}]
+//	}
+func LoadRules(path string) (*Rules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取规则文件失败: %w", err)
+	}
+	var rules Rules
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		err = json.Unmarshal(data, &rules.Fields)
+	} else {
+		err = json.Unmarshal(data, &rules)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("解析规则文件失败: %w", err)
+	}
+	return &rules, nil
+}
+
+// LoadExtraFields 从JSON文件读取额外字段规则，文件格式见 LoadRules
+//
+// 示例文件:
+//
+//	[{"name": "dork", "page": "vulnerability", "selector": ".well-sm:contains('Dork:') b"}]
+func LoadExtraFields(path string) ([]ExtraField, error) {
+	rules, err := LoadRules(path)
+	if err != nil {
+		return nil, err
+	}
+	return rules.Fields, nil
+}
+
+// NewExtraFieldExtractor 校验并编译额外字段规则
+// 字段名为空或重复、页面类型未知、选择器或正则无效时返回错误
+//
+// 示例:
+//
+//	fields, err := LoadExtraFields("extract_rules.json")
+//	extractor, err := NewExtraFieldExtractor(fields)
+//	c := NewCrawler(WithExtraFields(extractor))
+func NewExtraFieldExtractor(fields []ExtraField) (*ExtraFieldExtractor, error) {
+	e := &ExtraFieldExtractor{
+		selector: make(map[string]goquery.Matcher, len(fields)),
+		patterns: make(map[string]*regexp.Regexp),
+	}
+	seen := make(map[string]bool, len(fields))
+	for i, f := range fields {
+		f.Name = strings.TrimSpace(f.Name)
+		if f.Name == "" {
+			return nil, fmt.Errorf("第%d条额外字段规则缺少name", i+1)
+		}
+		key := f.Page + "/" + f.Name
+		if seen[key] {
+			return nil, fmt.Errorf("额外字段 %q 重复", f.Name)
+		}
+		seen[key] = true
+		if f.Page != PageVulnerability && f.Page != PageCve {
+			return nil, fmt.Errorf("额外字段 %q 的页面类型 %q 无效，应为 %s 或 %s", f.Name, f.Page, PageVulnerability, PageCve)
+		}
+		matcher, err := compileSelector(f.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("额外字段 %q 的选择器无效: %w", f.Name, err)
+		}
+		e.selector[key] = matcher
+		if f.Regex != "" {
+			pattern, err := regexp.Compile(f.Regex)
+			if err != nil {
+				return nil, fmt.Errorf("额外字段 %q 的正则无效: %w", f.Name, err)
+			}
+			e.patterns[key] = pattern
+		}
+		e.fields = append(e.fields, f)
+	}
+	return e, nil
+}
+
+// compileSelector 编译CSS选择器，只匹配第一个元素
+// goquery对无效的选择器不会报错，而是什么都不匹配，这里先用cascadia检查
+func compileSelector(selector string) (goquery.Matcher, error) {
+	if strings.TrimSpace(selector) == "" {
+		return nil, errors.New("选择器为空")
+	}
+	if _, err := cascadia.Compile(selector); err != nil {
+		return nil, err
+	}
+	return goquery.Single(selector), nil
+}
+
+// Extract 从页面中提取指定类型页面的额外字段，没有规则或都没有匹配时返回nil
+//
+// 参数:
+//   - doc: 已解析的页面
+//   - page: 页面类型，PageVulnerability 或 PageCve
+//   - prov: 字段来源，不为nil时记录为 extras.<字段名>
+func (e *ExtraFieldExtractor) Extract(doc *goquery.Document, page string, prov Provenance) map[string]string {
+	var extras map[string]string
+	for _, f := range e.fields {
+		if f.Page != page {
+			continue
+		}
+		key := f.Page + "/" + f.Name
+		sel := doc.FindMatcher(e.selector[key])
+		if sel.Length() == 0 {
+			continue
+		}
+		value := strings.TrimSpace(sel.Text())
+		if f.Attr != "" {
+			value = strings.TrimSpace(sel.AttrOr(f.Attr, ""))
+		}
+		if pattern := e.patterns[key]; pattern != nil {
+			m := pattern.FindStringSubmatch(value)
+			if m == nil {
+				continue
+			}
+			value = m[0]
+			if len(m) > 1 {
+				value = m[1]
+			}
+		}
+		if value == "" {
+			continue
+		}
+		if extras == nil {
+			extras = make(map[string]string)
+		}
+		extras[f.Name] = value
+		prov.set("extras."+f.Name, f.rule())
+	}
+	return extras
+}
+
+// rule 返回记录在字段来源中的规则描述
+func (f ExtraField) rule() string {
+	rule := f.Selector
+	if f.Attr != "" {
+		rule += " @" + f.Attr
+	}
+	if f.Regex != "" {
+		rule += " + 正则 " + f.Regex
+	}
+	return rule
+}
+
+// WithExtraFields 设置额外字段规则，爬取漏洞详情和CVE详情时提取到结果的 Extras 中
+//
+// 参数:
+//   - extractor: 由 NewExtraFieldExtractor 创建的提取器，为nil时不提取
+//
+// 返回值:
+//   - CrawlerOption: 返回一个配置函数
+func WithExtraFields(extractor *ExtraFieldExtractor) CrawlerOption {
+	return func(c *Crawler) {
+		c.extras = extractor
+	}
+}
+
+// extractExtras 按配置的规则从页面中提取额外字段，没有配置规则或页面无法解析时返回nil
+func (c *Crawler) extractExtras(htmlContent, page string, prov Provenance) map[string]string {
+	if c.extras == nil {
+		return nil
+	}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlContent))
+	if err != nil {
+		return nil
+	}
+	return c.extras.Extract(doc, page, prov)
+}
@@ -0,0 +1,100 @@
+package crawler
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtraFieldExtractor(t *testing.T) {
+	page, err := os.ReadFile(filepath.Join(benchmarkFixtureDir, "vul-detail-response.html"))
+	require.NoError(t, err)
+	client := &mockClient{
+		getPageFunc: func(string) (string, error) { return string(page), nil },
+		baseURL:     "https://cxsecurity.com",
+	}
+	extractor, err := NewExtraFieldExtractor([]ExtraField{
+		{Name: "wlb_number", Page: PageVulnerability, Selector: "input[name='wlb']", Attr: "value"},
+		{Name: "keywords", Page: PageVulnerability, Selector: "meta[name='keywords']", Attr: "content", Regex: `^([^,]+)`},
+		{Name: "page_title", Page: PageVulnerability, Selector: "title", Regex: `(?i)cxsecurity\.com`},
+		{Name: "missing", Page: PageVulnerability, Selector: ".no-such-element"},
+		{Name: "no_match", Page: PageVulnerability, Selector: "title", Regex: `^never$`},
+		{Name: "cve_title", Page: PageCve, Selector: "title"},
+	})
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	output := filepath.Join(dir, "detail.json")
+	c := NewCrawler(WithHTTPClient(client), WithExtraFields(extractor), WithProvenance())
+	v, err := c.CrawlVulnerabilityDetail("/issue/WLB-2007030137", output)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"wlb_number": "2007030137",
+		"keywords":   "PHP",
+		"page_title": "CXSecurity.com",
+	}, v.Extras)
+
+	data, err := os.ReadFile(ProvenancePath(output))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"extras.wlb_number": "input[name='wlb'] @value"`)
+
+	// 没有配置规则时不提取
+	v, err = NewCrawler(WithHTTPClient(client)).CrawlVulnerabilityDetail("/issue/WLB-2007030137", "")
+	require.NoError(t, err)
+	assert.Nil(t, v.Extras)
+}
+
+func TestNewExtraFieldExtractorErrors(t *testing.T) {
+	for name, field := range map[string]ExtraField{
+		"缺少name": {Page: PageVulnerability, Selector: "title"},
+		"页面类型无效": {Name: "a", Page: "list", Selector: "title"},
+		"选择器为空":  {Name: "a", Page: PageCve},
+		"选择器无效":  {Name: "a", Page: PageCve, Selector: "div[["},
+		"正则无效":   {Name: "a", Page: PageCve, Selector: "title", Regex: "("},
+	} {
+		_, err := NewExtraFieldExtractor([]ExtraField{field})
+		assert.Error(t, err, name)
+	}
+
+	_, err := NewExtraFieldExtractor([]ExtraField{
+		{Name: "a", Page: PageCve, Selector: "title"},
+		{Name: "a", Page: PageCve, Selector: "h1"},
+	})
+	assert.ErrorContains(t, err, "重复")
+
+	// 不同页面类型可以使用相同的字段名
+	_, err = NewExtraFieldExtractor([]ExtraField{
+		{Name: "a", Page: PageCve, Selector: "title"},
+		{Name: "a", Page: PageVulnerability, Selector: "title"},
+	})
+	assert.NoError(t, err)
+}
+
+func TestLoadExtraFields(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[{"name": "title", "page": "cve", "selector": "title", "regex": "CVE-\\d+-\\d+"}]`), 0644))
+	fields, err := LoadExtraFields(path)
+	require.NoError(t, err)
+	require.Len(t, fields, 1)
+	assert.Equal(t, `CVE-\d+-\d+`, fields[0].Regex)
+
+	page, err := os.ReadFile(filepath.Join(benchmarkFixtureDir, "cve-show-detail-response.html"))
+	require.NoError(t, err)
+	extractor, err := NewExtraFieldExtractor(fields)
+	require.NoError(t, err)
+	client := &mockClient{
+		getPageFunc: func(string) (string, error) { return string(page), nil },
+		baseURL:     "https://cxsecurity.com",
+	}
+	d, err := NewCrawler(WithHTTPClient(client), WithExtraFields(extractor), WithRelatedPages(0)).CrawlCveDetail("CVE-2007-1411", "")
+	require.NoError(t, err)
+	assert.Equal(t, "CVE-2007-1411", d.Extras["title"])
+
+	require.NoError(t, os.WriteFile(path, []byte(strings.Repeat("[", 3)), 0644))
+	_, err = LoadExtraFields(path)
+	assert.Error(t, err)
+}
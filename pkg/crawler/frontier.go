@@ -0,0 +1,237 @@
+package crawler
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FrontierItem 表示爬取队列中的一个待爬取页面
+type FrontierItem struct {
+	Path     string    // 相对于baseURL的路径，例如 "/issue/WLB-2024040015"
+	Category string    // 页面分类，用于礼貌延迟，例如 "list"、"detail"、"cve"、"author"
+	Priority int       // 优先级，数值越大越先爬取
+	Date     time.Time // 页面关联的发布日期，可为零值
+}
+
+// frontierEntry 是堆中的元素，seq用于同优先级时保持先进先出
+type frontierEntry struct {
+	item FrontierItem
+	seq  uint64
+}
+
+// frontierHeap 按优先级从高到低排序的最大堆
+type frontierHeap []frontierEntry
+
+func (h frontierHeap) Len() int { return len(h) }
+func (h frontierHeap) Less(i, j int) bool {
+	if h[i].item.Priority != h[j].item.Priority {
+		return h[i].item.Priority > h[j].item.Priority
+	}
+	return h[i].seq < h[j].seq
+}
+func (h frontierHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *frontierHeap) Push(x interface{}) { *h = append(*h, x.(frontierEntry)) }
+func (h *frontierHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	entry := old[n-1]
+	*h = old[:n-1]
+	return entry
+}
+
+// Frontier 是带优先级和分类礼貌延迟的爬取队列
+//
+// 与按页码顺序逐页爬取不同，Frontier总是先返回优先级最高的页面，
+// 例如新发布的High风险漏洞会排在多年前的Low风险回填页面之前。
+// 同时每个分类有独立的礼貌延迟：同一分类的两次出队之间至少间隔指定时间，
+// 而不同分类之间互不影响。
+//
+// 使用示例：
+//
+//	frontier := NewFrontier(time.Second)
+//	frontier.SetCategoryDelay("detail", 3*time.Second)
+//	frontier.Push(FrontierItem{Path: "/issue/WLB-2024040015", Category: "detail",
+//	    Priority: RiskPriority("High", time.Now())})
+//
+//	for frontier.Len() > 0 {
+//	    item, err := frontier.Next(ctx)
+//	    if err != nil {
+//	        break
+//	    }
+//	    content, err := client.GetPage(item.Path)
+//	    // ...
+//	}
+type Frontier struct {
+	mu           sync.Mutex
+	queues       map[string]*frontierHeap // 每个分类一个优先队列
+	delays       map[string]time.Duration // 每个分类的礼貌延迟
+	defaultDelay time.Duration            // 未单独设置的分类使用的延迟
+	nextAllowed  map[string]time.Time     // 每个分类下一次允许出队的时间
+	seq          uint64                   // 入队序号
+	size         int                      // 队列中的元素总数
+	now          func() time.Time         // 当前时间，便于测试替换
+}
+
+// NewFrontier 创建一个新的爬取队列
+// 参数:
+//   - defaultDelay: 未单独设置延迟的分类使用的礼貌延迟
+func NewFrontier(defaultDelay time.Duration) *Frontier {
+	return &Frontier{
+		queues:       make(map[string]*frontierHeap),
+		delays:       make(map[string]time.Duration),
+		defaultDelay: defaultDelay,
+		nextAllowed:  make(map[string]time.Time),
+		now:          time.Now,
+	}
+}
+
+// SetCategoryDelay 设置指定分类的礼貌延迟
+func (f *Frontier) SetCategoryDelay(category string, delay time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.delays[category] = delay
+}
+
+// Push 将页面加入队列
+func (f *Frontier) Push(item FrontierItem) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	q, ok := f.queues[item.Category]
+	if !ok {
+		q = &frontierHeap{}
+		f.queues[item.Category] = q
+	}
+	f.seq++
+	heap.Push(q, frontierEntry{item: item, seq: f.seq})
+	f.size++
+}
+
+// Len 返回队列中剩余的页面数量
+func (f *Frontier) Len() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.size
+}
+
+// TryPop 尝试取出一个可以立即爬取的页面
+// 在所有礼貌延迟已到期的分类中，返回优先级最高的页面。
+//
+// 返回值:
+//   - FrontierItem: 取出的页面
+//   - time.Duration: 没有可立即爬取的页面时，需要等待的最短时间
+//   - bool: 是否成功取出页面；队列为空时返回false且等待时间为0
+func (f *Frontier) TryPop() (FrontierItem, time.Duration, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := f.now()
+	var (
+		bestCategory string
+		bestEntry    frontierEntry
+		found        bool
+		minWait      time.Duration = -1
+	)
+
+	for category, q := range f.queues {
+		if q.Len() == 0 {
+			continue
+		}
+		if allowed := f.nextAllowed[category]; now.Before(allowed) {
+			if wait := allowed.Sub(now); minWait < 0 || wait < minWait {
+				minWait = wait
+			}
+			continue
+		}
+		top := (*q)[0]
+		if !found || (frontierHeap{top, bestEntry}).Less(0, 1) {
+			bestCategory, bestEntry, found = category, top, true
+		}
+	}
+
+	if !found {
+		if minWait < 0 {
+			minWait = 0
+		}
+		return FrontierItem{}, minWait, false
+	}
+
+	heap.Pop(f.queues[bestCategory])
+	f.size--
+	f.nextAllowed[bestCategory] = now.Add(f.delayFor(bestCategory))
+	return bestEntry.item, 0, true
+}
+
+// Next 阻塞直到有页面可以爬取，并返回该页面
+// 队列为空时返回ErrFrontierEmpty，ctx被取消时返回ctx的错误
+func (f *Frontier) Next(ctx context.Context) (FrontierItem, error) {
+	for {
+		item, wait, ok := f.TryPop()
+		if ok {
+			return item, nil
+		}
+		if wait == 0 {
+			return FrontierItem{}, ErrFrontierEmpty
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return FrontierItem{}, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// delayFor 返回分类的礼貌延迟
+func (f *Frontier) delayFor(category string) time.Duration {
+	if delay, ok := f.delays[category]; ok {
+		return delay
+	}
+	return f.defaultDelay
+}
+
+// ErrFrontierEmpty 表示爬取队列中已没有待爬取的页面
+var ErrFrontierEmpty = errors.New("爬取队列为空")
+
+// RiskPriority 根据风险级别和发布日期计算页面优先级
+// 新近程度优先：7天内 > 30天内 > 一年内 > 更早；同一时间段内按风险级别 High > Med. > Low 排序。
+// 日期为零值的页面视为最旧，风险级别无法识别时排在Low之后。
+//
+// 示例:
+//
+//	RiskPriority("High", time.Now())                 // 33
+//	RiskPriority("Low", time.Now().AddDate(-3, 0, 0)) // 1
+func RiskPriority(riskLevel string, date time.Time) int {
+	recency := 0
+	if !date.IsZero() {
+		age := time.Since(date)
+		switch {
+		case age <= 7*24*time.Hour:
+			recency = 3
+		case age <= 30*24*time.Hour:
+			recency = 2
+		case age <= 365*24*time.Hour:
+			recency = 1
+		}
+	}
+
+	risk := 0
+	switch strings.ToLower(strings.TrimSpace(riskLevel)) {
+	case "high":
+		risk = 3
+	case "med.", "medium":
+		risk = 2
+	case "low":
+		risk = 1
+	}
+
+	return recency*10 + risk
+}
@@ -0,0 +1,96 @@
+package crawler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrontierPriorityOrder(t *testing.T) {
+	frontier := NewFrontier(0)
+
+	now := time.Now()
+	frontier.Push(FrontierItem{Path: "/issue/old-low", Category: "detail", Priority: RiskPriority("Low", now.AddDate(-5, 0, 0))})
+	frontier.Push(FrontierItem{Path: "/issue/new-high", Category: "detail", Priority: RiskPriority("High", now)})
+	frontier.Push(FrontierItem{Path: "/issue/new-low", Category: "detail", Priority: RiskPriority("Low", now)})
+	frontier.Push(FrontierItem{Path: "/exploit/1", Category: "list", Priority: RiskPriority("Med.", now)})
+
+	var order []string
+	for frontier.Len() > 0 {
+		item, err := frontier.Next(context.Background())
+		require.NoError(t, err)
+		order = append(order, item.Path)
+	}
+
+	assert.Equal(t, []string{"/issue/new-high", "/exploit/1", "/issue/new-low", "/issue/old-low"}, order, "出队顺序不符合优先级")
+
+	_, err := frontier.Next(context.Background())
+	assert.ErrorIs(t, err, ErrFrontierEmpty, "空队列应返回ErrFrontierEmpty")
+}
+
+func TestFrontierSamePriorityIsFIFO(t *testing.T) {
+	frontier := NewFrontier(0)
+	frontier.Push(FrontierItem{Path: "/a", Category: "detail", Priority: 1})
+	frontier.Push(FrontierItem{Path: "/b", Category: "list", Priority: 1})
+	frontier.Push(FrontierItem{Path: "/c", Category: "detail", Priority: 1})
+
+	for _, expected := range []string{"/a", "/b", "/c"} {
+		item, _, ok := frontier.TryPop()
+		require.True(t, ok)
+		assert.Equal(t, expected, item.Path, "同优先级应保持先进先出")
+	}
+}
+
+func TestFrontierCategoryDelay(t *testing.T) {
+	frontier := NewFrontier(0)
+	now := time.Date(2024, 4, 9, 0, 0, 0, 0, time.UTC)
+	frontier.now = func() time.Time { return now }
+	frontier.SetCategoryDelay("detail", 3*time.Second)
+
+	frontier.Push(FrontierItem{Path: "/issue/1", Category: "detail", Priority: 10})
+	frontier.Push(FrontierItem{Path: "/issue/2", Category: "detail", Priority: 10})
+	frontier.Push(FrontierItem{Path: "/exploit/1", Category: "list", Priority: 1})
+
+	item, _, ok := frontier.TryPop()
+	require.True(t, ok)
+	assert.Equal(t, "/issue/1", item.Path)
+
+	// detail分类处于礼貌延迟中，应先返回其他分类的低优先级页面
+	item, _, ok = frontier.TryPop()
+	require.True(t, ok)
+	assert.Equal(t, "/exploit/1", item.Path, "延迟中的分类不应阻塞其他分类")
+
+	_, wait, ok := frontier.TryPop()
+	assert.False(t, ok, "所有分类都在延迟中时不应出队")
+	assert.Equal(t, 3*time.Second, wait, "等待时间不匹配")
+
+	now = now.Add(3 * time.Second)
+	item, _, ok = frontier.TryPop()
+	require.True(t, ok)
+	assert.Equal(t, "/issue/2", item.Path)
+}
+
+func TestFrontierNextHonorsContext(t *testing.T) {
+	frontier := NewFrontier(time.Hour)
+	frontier.Push(FrontierItem{Path: "/a", Category: "detail"})
+	frontier.Push(FrontierItem{Path: "/b", Category: "detail"})
+
+	_, err := frontier.Next(context.Background())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = frontier.Next(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "ctx超时后应返回错误")
+}
+
+func TestRiskPriority(t *testing.T) {
+	now := time.Now()
+	assert.Greater(t, RiskPriority("High", now), RiskPriority("Low", now))
+	assert.Greater(t, RiskPriority("Low", now), RiskPriority("High", now.AddDate(-2, 0, 0)), "新近程度应优先于风险级别")
+	assert.Equal(t, RiskPriority("Med.", now), RiskPriority("Medium", now))
+	assert.Equal(t, 0, RiskPriority("", time.Time{}))
+}
@@ -0,0 +1,49 @@
+package crawler
+
+import (
+	"errors"
+	"os"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+// KnownResults 是之前的结果中已有漏洞的集合，用于只保留新出现的漏洞
+// 漏洞ID和URL都作为键，任意一个相同即认为是同一个漏洞
+type KnownResults map[string]bool
+
+// NewKnownResults 根据之前的结果创建集合
+func NewKnownResults(items []model.Vulnerability) KnownResults {
+	known := make(KnownResults, len(items)*2)
+	for _, v := range items {
+		known.add(v.ID, v.URL)
+	}
+	return known
+}
+
+// LoadKnownResults 从之前保存的结果文件创建集合，支持的格式见LoadResultFile
+// 文件不存在时返回空集合，这样脚本第一次运行时所有结果都是新的
+func LoadKnownResults(path string) (KnownResults, error) {
+	items, err := LoadResultFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return KnownResults{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return NewKnownResults(items), nil
+}
+
+// add 将漏洞ID和URL加入集合，未知的ID不加入
+func (k KnownResults) add(id, url string) {
+	if id != "" && id != "未知" {
+		k["id:"+id] = true
+	}
+	if url != "" {
+		k["url:"+url] = true
+	}
+}
+
+// Contains 返回ID或URL相同的漏洞是否在集合中，nil集合不包含任何漏洞
+func (k KnownResults) Contains(id, url string) bool {
+	return (id != "" && id != "未知" && k["id:"+id]) || (url != "" && k["url:"+url])
+}
@@ -0,0 +1,52 @@
+package crawler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+func TestKnownResults(t *testing.T) {
+	known := NewKnownResults([]model.Vulnerability{
+		{ID: "WLB-2024040001", URL: "https://cxsecurity.com/issue/WLB-2024040001"},
+		{URL: "https://cxsecurity.com/issue/WLB-2024040002"},
+		{ID: "未知"},
+	})
+	assert.True(t, known.Contains("WLB-2024040001", ""))
+	assert.True(t, known.Contains("未知", "https://cxsecurity.com/issue/WLB-2024040002"))
+	assert.False(t, known.Contains("WLB-2024040003", "https://cxsecurity.com/issue/WLB-2024040003"))
+	assert.False(t, known.Contains("未知", ""))
+
+	var empty KnownResults
+	assert.False(t, empty.Contains("WLB-2024040001", ""))
+}
+
+func TestLoadKnownResults(t *testing.T) {
+	dir := t.TempDir()
+
+	// 文件不存在时所有结果都是新的
+	known, err := LoadKnownResults(filepath.Join(dir, "missing.json"))
+	require.NoError(t, err)
+	assert.Empty(t, known)
+
+	path := filepath.Join(dir, "previous.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"keyword":"xss","vulnerabilities":[{"id":"WLB-1","date":"2024-04-09"}]}`), 0o644))
+	known, err = LoadKnownResults(path)
+	require.NoError(t, err)
+	assert.True(t, known.Contains("WLB-1", ""))
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"foo":1}`), 0o644))
+	_, err = LoadKnownResults(path)
+	assert.Error(t, err)
+}
+
+func TestSearchOptionsExclude(t *testing.T) {
+	opts := SearchOptions{Exclude: NewKnownResults([]model.Vulnerability{{ID: "WLB-1"}})}
+	assert.False(t, opts.match(SearchVulnerability{ID: "WLB-1"}))
+	assert.True(t, opts.match(SearchVulnerability{ID: "WLB-2"}))
+}
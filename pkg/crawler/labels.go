@@ -0,0 +1,156 @@
+package crawler
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+)
+
+// 标签映射的类型
+const (
+	LabelRisk = "risk" // 风险级别，规范值为 "High"、"Med."、"Low"
+	LabelTag  = "tag"  // 漏洞标签，包括 Remote/Local 标记
+)
+
+// LabelMapping 是一条标签映射，将网站上同一标签的不同写法映射为规范值
+// 网站在不同页面、不同时期和本地化版本中会用 "Med."、"Medium"、"中危" 等不同写法表示同一标签，
+// 解析时统一映射为规范值，过滤、统计和合并时不需要再考虑这些写法。
+type LabelMapping struct {
+	Kind      string   `json:"kind"`      // 映射类型，LabelRisk 或 LabelTag
+	Canonical string   `json:"canonical"` // 规范值
+	Aliases   []string `json:"aliases"`   // 其他写法，不区分大小写，忽略首尾空白和末尾的 "."
+}
+
+// DefaultLabelMappings 是内置的标签映射表
+// 规则文件中的映射在这些映射之后应用，可以覆盖内置的写法。
+var DefaultLabelMappings = []LabelMapping{
+	{Kind: LabelRisk, Canonical: "High", Aliases: []string{"h", "hi", "高", "高危", "高风险", "hoch", "élevé", "eleve", "alto", "alta", "wysoki", "wysokie", "высокий"}},
+	{Kind: LabelRisk, Canonical: "Med.", Aliases: []string{"m", "med", "medium", "mid", "moderate", "中", "中危", "中风险", "mittel", "moyen", "medio", "media", "średni", "średnie", "средний"}},
+	{Kind: LabelRisk, Canonical: "Low", Aliases: []string{"l", "lo", "低", "低危", "低风险", "niedrig", "faible", "bajo", "baja", "niski", "niskie", "низкий"}},
+	{Kind: LabelTag, Canonical: "Remote", Aliases: []string{"远程", "fern", "distant", "remoto", "zdalny", "удаленный"}},
+	{Kind: LabelTag, Canonical: "Local", Aliases: []string{"本地", "lokal", "lokalny", "локальный"}},
+}
+
+// LabelMapper 按映射表将标签的不同写法转换为规范值
+type LabelMapper struct {
+	risk map[string]string // 规范化的写法 -> 规范值
+	tag  map[string]string
+}
+
+// defaultLabels 是只使用内置映射表的LabelMapper，解析器没有设置映射时使用
+var defaultLabels = func() *LabelMapper {
+	m, err := NewLabelMapper(nil)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}()
+
+// NewLabelMapper 创建使用内置映射表和mappings的LabelMapper
+// 类型未知、规范值为空、风险级别的规范值不是 High/Med./Low 的写法之一，
+// 或者同一写法在mappings中映射到不同的规范值时返回错误。
+//
+// 示例:
+//
+//	m, err := NewLabelMapper([]LabelMapping{{Kind: LabelRisk, Canonical: "High", Aliases: []string{"kritisch"}}})
+//	c := NewCrawler(WithLabelMapper(m))
+func NewLabelMapper(mappings []LabelMapping) (*LabelMapper, error) {
+	m := &LabelMapper{risk: make(map[string]string), tag: make(map[string]string)}
+	for _, mapping := range DefaultLabelMappings {
+		table := m.table(mapping.Kind)
+		table[labelKey(mapping.Canonical)] = mapping.Canonical
+		for _, alias := range mapping.Aliases {
+			table[labelKey(alias)] = mapping.Canonical
+		}
+	}
+
+	builtinRisk := maps.Clone(m.risk)
+	seen := make(map[string]string)
+	for i, mapping := range mappings {
+		table := m.table(mapping.Kind)
+		if table == nil {
+			return nil, fmt.Errorf("第%d条标签映射的类型 %q 无效，应为 %s 或 %s", i+1, mapping.Kind, LabelRisk, LabelTag)
+		}
+		canonical := strings.TrimSpace(mapping.Canonical)
+		if canonical == "" {
+			return nil, fmt.Errorf("第%d条标签映射缺少canonical", i+1)
+		}
+		if mapping.Kind == LabelRisk {
+			// 风险级别只能映射到网站使用的三个级别，否则过滤和统计无法识别
+			if canonical = builtinRisk[labelKey(canonical)]; canonical == "" {
+				return nil, fmt.Errorf("第%d条标签映射的风险级别 %q 无效，应为 High、Med. 或 Low", i+1, mapping.Canonical)
+			}
+		}
+		for _, alias := range append([]string{canonical}, mapping.Aliases...) {
+			key := labelKey(alias)
+			if key == "" {
+				continue
+			}
+			if prev, ok := seen[mapping.Kind+"/"+key]; ok && prev != canonical {
+				return nil, fmt.Errorf("标签 %q 同时映射到 %q 和 %q", strings.TrimSpace(alias), prev, canonical)
+			}
+			seen[mapping.Kind+"/"+key] = canonical
+			table[key] = canonical
+		}
+	}
+	return m, nil
+}
+
+// table 返回映射类型对应的映射表，类型未知时返回nil
+func (m *LabelMapper) table(kind string) map[string]string {
+	switch kind {
+	case LabelRisk:
+		return m.risk
+	case LabelTag:
+		return m.tag
+	}
+	return nil
+}
+
+// lookup 返回标签的规范值，没有映射时返回空字符串
+func (m *LabelMapper) lookup(kind, label string) string {
+	return m.table(kind)[labelKey(label)]
+}
+
+// Risk 返回风险级别的规范值，没有映射的写法原样返回(去掉首尾空白)
+func (m *LabelMapper) Risk(label string) string {
+	if canonical := m.lookup(LabelRisk, label); canonical != "" {
+		return canonical
+	}
+	return strings.TrimSpace(label)
+}
+
+// Tag 返回标签的规范值，没有映射的标签原样返回(去掉首尾空白)
+func (m *LabelMapper) Tag(label string) string {
+	if canonical := m.lookup(LabelTag, label); canonical != "" {
+		return canonical
+	}
+	return strings.TrimSpace(label)
+}
+
+// labelKey 返回用于查找映射的键：小写，去掉首尾空白和末尾的 "."
+func labelKey(label string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(label)), ".")
+}
+
+// orDefault 在m为nil时返回只使用内置映射表的LabelMapper
+func (m *LabelMapper) orDefault() *LabelMapper {
+	if m == nil {
+		return defaultLabels
+	}
+	return m
+}
+
+// WithLabelMapper 设置解析风险级别和标签时使用的映射表，为nil时使用内置映射表
+// 映射表应用于默认的解析器和作者页面解析器，通过 WithParser 设置的自定义解析器不受影响。
+//
+// 示例:
+//
+//	rules, err := LoadRules("rules.json")
+//	mapper, err := NewLabelMapper(rules.Labels)
+//	c := NewCrawler(WithLabelMapper(mapper))
+func WithLabelMapper(m *LabelMapper) CrawlerOption {
+	return func(c *Crawler) {
+		c.labels = m
+	}
+}
@@ -0,0 +1,103 @@
+package crawler
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLabelMapper(t *testing.T) {
+	m, err := NewLabelMapper(nil)
+	require.NoError(t, err)
+	for label, want := range map[string]string{"High": "High", " medium ": "Med.", "MED.": "Med.", "中危": "Med.", "Niedrig": "Low", "Élevé": "High", "critical": "critical"} {
+		assert.Equal(t, want, m.Risk(label), label)
+	}
+	assert.Equal(t, "Remote", m.Tag("远程"))
+	assert.Equal(t, "Local", m.Tag(" lokal "))
+	assert.Equal(t, "XSS", m.Tag("XSS"))
+
+	// 规则文件中的映射补充或覆盖内置的写法
+	m, err = NewLabelMapper([]LabelMapping{
+		{Kind: LabelRisk, Canonical: "high", Aliases: []string{"Kritisch", "critical"}},
+		{Kind: LabelRisk, Canonical: "Low", Aliases: []string{"m"}},
+		{Kind: LabelTag, Canonical: "XSS", Aliases: []string{"Cross-Site Scripting", "跨站脚本"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "High", m.Risk("critical"), "风险级别的规范值统一为网站的写法")
+	assert.Equal(t, "Low", m.Risk("M"))
+	assert.Equal(t, "Med.", m.Risk("Medium"))
+	assert.Equal(t, "XSS", m.Tag("cross-site scripting"))
+	assert.Equal(t, "", NormalizeRiskLevel("critical"), "规则文件的映射不影响内置映射表")
+
+	for name, mappings := range map[string][]LabelMapping{
+		"类型":   {{Kind: "severity", Canonical: "High"}},
+		"规范值":  {{Kind: LabelTag, Canonical: " "}},
+		"风险级别": {{Kind: LabelRisk, Canonical: "Critical"}},
+		"冲突":   {{Kind: LabelTag, Canonical: "Web", Aliases: []string{"webapps"}}, {Kind: LabelTag, Canonical: "WebApp", Aliases: []string{"WebApps"}}},
+	} {
+		_, err := NewLabelMapper(mappings)
+		assert.Error(t, err, "应拒绝无效的%s", name)
+	}
+}
+
+func TestParsersApplyLabelMapping(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join(benchmarkFixtureDir, "list-response.html"))
+	require.NoError(t, err)
+	original, err := NewParser().ParseListPage(string(data))
+	require.NoError(t, err)
+
+	// 列表页的本地化写法映射为与原页面相同的规范值
+	localized := strings.NewReplacer(`>High<`, `>高危<`, `>Med.<`, `>Medium<`, `>Remote<`, `>远程<`, `>Low<`, `>Gering<`).Replace(string(data))
+	mapper, err := NewLabelMapper([]LabelMapping{{Kind: LabelRisk, Canonical: "Low", Aliases: []string{"gering"}}})
+	require.NoError(t, err)
+	list, err := NewParser(WithParserLabels(mapper)).ParseListPage(localized)
+	require.NoError(t, err)
+	require.Equal(t, len(original.Items), len(list.Items))
+	for i, v := range list.Items {
+		assert.Equal(t, original.Items[i].RiskLevel, v.RiskLevel, v.Title)
+		assert.Equal(t, original.Items[i].IsRemote, v.IsRemote, v.Title)
+		assert.Equal(t, original.Items[i].Tags, v.Tags, v.Title)
+	}
+
+	// 详情页同样使用映射表，通过 WithLabelMapper 传给爬虫的默认解析器
+	detail, err := os.ReadFile(filepath.Join(benchmarkFixtureDir, "vul-detail-response.html"))
+	require.NoError(t, err)
+	client := &mockClient{
+		getPageFunc: func(string) (string, error) {
+			return strings.Replace(string(detail), `>High</span>`, `>Kritisch</span>`, 1), nil
+		},
+		baseURL: "https://cxsecurity.com",
+	}
+	mapper, err = NewLabelMapper([]LabelMapping{{Kind: LabelRisk, Canonical: "High", Aliases: []string{"kritisch"}}})
+	require.NoError(t, err)
+	v, err := NewCrawler(WithHTTPClient(client), WithLabelMapper(mapper)).CrawlVulnerabilityDetail("/issue/WLB-2007030137", "")
+	require.NoError(t, err)
+	assert.Equal(t, "High", v.RiskLevel)
+	v, err = NewCrawler(WithHTTPClient(client)).CrawlVulnerabilityDetail("/issue/WLB-2007030137", "")
+	require.NoError(t, err)
+	assert.Equal(t, "Kritisch", v.RiskLevel, "没有映射的写法原样保留")
+}
+
+func TestLoadRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+  "fields": [{"name": "title", "page": "cve", "selector": "title"}],
+  "labels": [{"kind": "tag", "canonical": "XSS", "aliases": ["Cross-Site Scripting"]}]
+}`), 0644))
+	rules, err := LoadRules(path)
+	require.NoError(t, err)
+	assert.Len(t, rules.Fields, 1)
+	require.Len(t, rules.Labels, 1)
+	assert.Equal(t, []string{"Cross-Site Scripting"}, rules.Labels[0].Aliases)
+
+	// 只有额外字段的数组格式仍然可以使用
+	require.NoError(t, os.WriteFile(path, []byte(` [{"name": "title", "page": "cve", "selector": "title"}]`), 0644))
+	rules, err = LoadRules(path)
+	require.NoError(t, err)
+	assert.Len(t, rules.Fields, 1)
+	assert.Empty(t, rules.Labels)
+}
@@ -0,0 +1,109 @@
+package crawler
+
+import (
+	"fmt"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+// Latest的默认值
+const (
+	DefaultLatestLimit    = 20 // 默认返回的记录数
+	DefaultLatestMaxPages = 10 // 查找since时默认最多扫描的列表页数
+)
+
+// LatestOptions 是Latest的选项
+type LatestOptions struct {
+	Section  Section // 列表栏目，默认为 SectionExploit
+	Since    string  // 上次调用返回的cursor，只返回比它新的漏洞；为空时返回最新的Limit条
+	Limit    int     // 最多返回的记录数，默认为 DefaultLatestLimit
+	MaxPages int     // 查找Since时最多扫描的列表页数，默认为 DefaultLatestMaxPages
+}
+
+// LatestResult 是增量获取的结果
+type LatestResult struct {
+	Since           string                `json:"since,omitempty"` // 规范化后的since
+	Cursor          string                `json:"cursor"`          // 下次调用时作为since传入，没有新漏洞时与since相同
+	HasMore         bool                  `json:"has_more"`        // 还有更新的漏洞没有返回，应立即用cursor再次调用
+	Gap             bool                  `json:"gap"`             // 扫描的页中没有找到since，两次调用之间可能漏掉了漏洞
+	PagesScanned    int                   `json:"pages_scanned"`   // 实际请求的列表页数
+	Vulnerabilities []model.Vulnerability `json:"vulnerabilities"` // 按发布顺序从新到旧排列
+}
+
+// Latest 返回列表栏目中比opts.Since新的漏洞，用于只关心新条目的轮询程序
+//
+// 从第1页开始依次扫描列表页，直到遇到Since或扫描了MaxPages页。
+// 新漏洞超过Limit条时返回其中最旧的Limit条，并设置HasMore，
+// 轮询程序用返回的Cursor继续调用即可按顺序取完，不会跳过中间的漏洞。
+// 在扫描的页中没有找到Since时(例如两次调用间隔太久)设置Gap，结果为扫描到的漏洞。
+//
+// 示例:
+//
+//	result, err := c.Latest(crawler.LatestOptions{Since: "WLB-2024040015", Limit: 50})
+//	// 处理 result.Vulnerabilities 后保存 result.Cursor，下次作为Since传入
+func (c *Crawler) Latest(opts LatestOptions) (*LatestResult, error) {
+	if opts.Section == "" {
+		opts.Section = SectionExploit
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = DefaultLatestLimit
+	}
+	if opts.MaxPages <= 0 {
+		opts.MaxPages = DefaultLatestMaxPages
+	}
+	result := &LatestResult{Vulnerabilities: []model.Vulnerability{}}
+	var since model.WLBID
+	if opts.Since != "" {
+		id, err := model.ParseWLBID(opts.Since)
+		if err != nil {
+			return nil, err
+		}
+		since = id
+		result.Since = id.String()
+	}
+
+	// 没有since时只需要最新的Limit条
+	var items []model.Vulnerability
+	seen := make(map[string]bool)
+	found := false
+	for page := 1; page <= opts.MaxPages && !found; page++ {
+		if since == "" && len(items) >= opts.Limit {
+			break
+		}
+		list, err := c.CrawlSection(opts.Section, page, "")
+		if err != nil {
+			return nil, fmt.Errorf("获取第 %d 页失败: %w", page, err)
+		}
+		result.PagesScanned++
+		for _, item := range list.Items {
+			id, err := model.ParseWLBID(item.ID)
+			if err == nil && id == since {
+				found = true
+				break
+			}
+			if seen[item.ID+item.URL] {
+				continue
+			}
+			seen[item.ID+item.URL] = true
+			items = append(items, item)
+		}
+		if page >= list.TotalPages {
+			break
+		}
+	}
+	result.Gap = since != "" && !found
+
+	switch {
+	case since == "":
+		items = items[:min(len(items), opts.Limit)]
+	case len(items) > opts.Limit:
+		items = items[len(items)-opts.Limit:]
+		result.HasMore = true
+	}
+	result.Vulnerabilities = append(result.Vulnerabilities, items...)
+	result.Cursor = result.Since
+	if len(items) > 0 {
+		result.Cursor = items[0].ID
+	}
+	return result, nil
+}
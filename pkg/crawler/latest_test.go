@@ -0,0 +1,78 @@
+package crawler
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+func TestLatest(t *testing.T) {
+	var paths []string
+	client := &mockClient{baseURL: "https://cxsecurity.com", getPageFunc: func(path string) (string, error) {
+		paths = append(paths, path)
+		return path, nil
+	}}
+	// 共3页，每页3条，ID从新到旧为 WLB-2024040009 ... WLB-2024040001
+	parser := &mockParser{parseListPageFunc: func(html string) (*model.VulnerabilityList, error) {
+		var page int
+		fmt.Sscanf(html[strings.LastIndex(html, "/")+1:], "%d", &page)
+		list := &model.VulnerabilityList{CurrentPage: page, TotalPages: 3}
+		for i := 0; i < 3; i++ {
+			list.Items = append(list.Items, model.Vulnerability{ID: fmt.Sprintf("WLB-202404%04d", 10-(page-1)*3-i-1)})
+		}
+		return list, nil
+	}}
+	c := NewCrawler(WithHTTPClient(client), WithCustomParser(parser))
+	ids := func(r *LatestResult) []string {
+		var ids []string
+		for _, v := range r.Vulnerabilities {
+			ids = append(ids, v.ID)
+		}
+		return ids
+	}
+
+	result, err := c.Latest(LatestOptions{Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"WLB-2024040009", "WLB-2024040008"}, ids(result))
+	assert.Equal(t, "WLB-2024040009", result.Cursor)
+	assert.Equal(t, 1, result.PagesScanned, "没有since时只获取需要的页")
+
+	result, err = c.Latest(LatestOptions{Since: "2024040005"})
+	require.NoError(t, err)
+	assert.Equal(t, "WLB-2024040005", result.Since)
+	assert.Equal(t, []string{"WLB-2024040009", "WLB-2024040008", "WLB-2024040007", "WLB-2024040006"}, ids(result))
+	assert.False(t, result.HasMore)
+	assert.False(t, result.Gap)
+	assert.Equal(t, 2, result.PagesScanned)
+
+	// 超过limit时返回最旧的几条，用cursor继续取
+	result, err = c.Latest(LatestOptions{Since: "WLB-2024040005", Limit: 3})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"WLB-2024040008", "WLB-2024040007", "WLB-2024040006"}, ids(result))
+	assert.True(t, result.HasMore)
+	result, err = c.Latest(LatestOptions{Since: result.Cursor, Limit: 3})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"WLB-2024040009"}, ids(result))
+	assert.False(t, result.HasMore)
+
+	result, err = c.Latest(LatestOptions{Since: "WLB-2024040009"})
+	require.NoError(t, err)
+	assert.Empty(t, result.Vulnerabilities)
+	assert.NotNil(t, result.Vulnerabilities)
+	assert.Equal(t, "WLB-2024040009", result.Cursor, "没有新漏洞时cursor不变")
+
+	result, err = c.Latest(LatestOptions{Since: "WLB-2020010001", MaxPages: 2})
+	require.NoError(t, err)
+	assert.True(t, result.Gap)
+	assert.Len(t, result.Vulnerabilities, 6)
+
+	paths = nil
+	_, err = c.Latest(LatestOptions{Since: "CVE-2024-0001"})
+	assert.Error(t, err)
+	assert.Empty(t, paths)
+}
@@ -96,8 +96,8 @@ func (p *Parser) ParseListPage(htmlContent string) (*model.VulnerabilityList, er
 		return result, nil
 	}
 
-	// 检查是否是搜索结果页面
-	isSearchPage := doc.Find("div[ng-controller='PagIt']").Length() > 0
+	// 检查是否是搜索结果页面，列表页同样有分页控件，但使用 table-striped 表格
+	isSearchPage := !table.HasClass("table-striped") && doc.Find("div[ng-controller='PagIt']").Length() > 0
 
 	// 根据页面类型决定如何解析
 	if isSearchPage {
@@ -119,12 +119,12 @@ func (p *Parser) ParseListPage(htmlContent string) (*model.VulnerabilityList, er
 
 			// 风险级别 (第一列)
 			riskLevelCell := cells.Eq(0).Find("span.label")
-			riskLevel := strings.TrimSpace(riskLevelCell.Text())
+			riskLevel := p.labels.orDefault().Risk(riskLevelCell.Text())
 
 			// 标题和URL (第二列)
 			titleCell := cells.Eq(1).Find("h6 a")
 			title := strings.TrimSpace(titleCell.Text())
-			url, _ := titleCell.Attr("href")
+			url := sanitizedHref(titleCell)
 
 			// 修正URL，确保是完整的
 			if url != "" && !strings.HasPrefix(url, "http") {
@@ -157,7 +157,7 @@ func (p *Parser) ParseListPage(htmlContent string) (*model.VulnerabilityList, er
 			// 作者 (第四列)
 			authorCell := cells.Eq(3).Find("a")
 			author := strings.TrimSpace(authorCell.Text())
-			authorURL, _ := authorCell.Attr("href")
+			authorURL := sanitizedHref(authorCell)
 
 			// 修正作者URL
 			if authorURL != "" && !strings.HasPrefix(authorURL, "http") {
@@ -177,6 +177,9 @@ func (p *Parser) ParseListPage(htmlContent string) (*model.VulnerabilityList, er
 				cwe = cweMatches[0]
 			}
 
+			// 评论数和浏览数，页面上没有时为0
+			pop := parseRowPopularity(row, titleCell)
+
 			// 初始化漏洞对象
 			vulnerability := model.Vulnerability{
 				Date:      date,
@@ -188,10 +191,13 @@ func (p *Parser) ParseListPage(htmlContent string) (*model.VulnerabilityList, er
 				Author:    author,
 				AuthorURL: authorURL,
 				Tags:      []string{}, // 搜索页面中可能没有标签
+				Comments:  pop.comments,
+				Views:     pop.views,
 			}
 
 			// 只有标题不为空才添加该漏洞
 			if vulnerability.Title != "" {
+				vulnerability.Tags = model.SortTags(vulnerability.Tags)
 				result.Items = append(result.Items, vulnerability)
 			}
 		})
@@ -222,12 +228,12 @@ func (p *Parser) ParseListPage(htmlContent string) (*model.VulnerabilityList, er
 
 			// 风险级别 (第一列)
 			riskLevelCell := cells.Eq(0).Find("span.label")
-			riskLevel := strings.TrimSpace(riskLevelCell.Text())
+			riskLevel := p.labels.orDefault().Risk(riskLevelCell.Text())
 
 			// 标题和URL (第二列)
 			titleCell := cells.Eq(1).Find("div.row div.col-md-7 a")
 			title := strings.TrimSpace(titleCell.Text())
-			url, _ := titleCell.Attr("href")
+			url := sanitizedHref(titleCell)
 			// 修正URL，确保是完整的
 			if url != "" && !strings.HasPrefix(url, "http") {
 				if strings.HasPrefix(url, "/") {
@@ -253,9 +259,10 @@ func (p *Parser) ParseListPage(htmlContent string) (*model.VulnerabilityList, er
 				// 跳过作者标签
 				if tagSelection.Find("a[href*='/author/']").Length() == 0 {
 					tag := strings.TrimSpace(tagSelection.Text())
-					if tag == "" {
+					if tag == "" || isPopularityLabel(tag) {
 						return
 					}
+					tag = p.labels.orDefault().Tag(tag)
 
 					// 检查是否是CVE编号
 					if cveMatches := cvePattern.FindStringSubmatch(tag); len(cveMatches) > 0 {
@@ -287,7 +294,7 @@ func (p *Parser) ParseListPage(htmlContent string) (*model.VulnerabilityList, er
 			// 作者信息 (第二列，右侧的作者链接)
 			authorSelection := cells.Eq(1).Find("div.row div.col-md-5 a[href*='/author/']")
 			vulnerability.Author = strings.TrimSpace(authorSelection.Text())
-			vulnerability.AuthorURL, _ = authorSelection.Attr("href")
+			vulnerability.AuthorURL = sanitizedHref(authorSelection)
 			// 修正作者URL
 			if vulnerability.AuthorURL != "" && !strings.HasPrefix(vulnerability.AuthorURL, "http") {
 				if strings.HasPrefix(vulnerability.AuthorURL, "/") {
@@ -297,8 +304,13 @@ func (p *Parser) ParseListPage(htmlContent string) (*model.VulnerabilityList, er
 				}
 			}
 
+			// 评论数和浏览数，页面上没有时为0
+			pop := parseRowPopularity(element, titleCell)
+			vulnerability.Comments, vulnerability.Views = pop.comments, pop.views
+
 			// 只有标题不为空才添加该漏洞
 			if vulnerability.Title != "" {
+				vulnerability.Tags = model.SortTags(vulnerability.Tags)
 				result.Items = append(result.Items, vulnerability)
 			}
 		})
@@ -12,9 +12,9 @@ func TestParseListPage(t *testing.T) {
 	parser := NewParser()
 
 	// 加载测试HTML文件
-	htmlContent, err := os.ReadFile("../../docs/list-response.html")
+	htmlContent, err := os.ReadFile("../../docs/response-examples/list-response.html")
 	if err != nil {
-		t.Skip("跳过测试，测试文件不存在：../../docs/list-response.html")
+		t.Skip("跳过测试，测试文件不存在：../../docs/response-examples/list-response.html")
 		return
 	}
 
@@ -23,7 +23,7 @@ func TestParseListPage(t *testing.T) {
 	assert.NoError(t, err, "解析失败")
 	assert.NotNil(t, result, "解析结果不应为nil")
 
-	// --- 验证字段值是否与 docs/list-response.html 完全对应 ---
+	// --- 验证字段值是否与 docs/response-examples/list-response.html 完全对应 ---
 
 	// 验证列表非空
 	assert.NotEmpty(t, result.Items, "解析结果中应包含漏洞条目")
@@ -51,6 +51,34 @@ func TestParseListPage(t *testing.T) {
 		// 预期标签
 		assert.Contains(t, item.Tags, "CVE", "第一条记录的标签应包含CVE")
 		assert.Contains(t, item.Tags, "CWE", "第一条记录的标签应包含CWE")
-		assert.Contains(t, item.Tags, "Local", "第一条记录的标签应包含Local")
+		assert.True(t, item.IsLocal, "第一条记录应为本地漏洞")
+	}
+}
+
+func BenchmarkParseListPage(b *testing.B) {
+	htmlContent := loadBenchmarkFixture(b, "list-response.html")
+	parser := NewParser()
+
+	b.SetBytes(int64(len(htmlContent)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := parser.ParseListPage(htmlContent); err != nil {
+			b.Fatalf("解析失败: %v", err)
+		}
+	}
+}
+
+func BenchmarkParseSearchPage(b *testing.B) {
+	htmlContent := loadBenchmarkFixture(b, "search-response.html")
+	parser := NewParser()
+
+	b.SetBytes(int64(len(htmlContent)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := parser.ParseListPage(htmlContent); err != nil {
+			b.Fatalf("解析失败: %v", err)
+		}
 	}
 }
@@ -0,0 +1,123 @@
+package crawler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+// ToVulnerability 将搜索结果项转换为model.Vulnerability
+// 日期无法解析时Date保持零值
+func (v SearchVulnerability) ToVulnerability() model.Vulnerability {
+	date, _ := time.Parse("2006-01-02", v.Date)
+	return model.Vulnerability{
+		ID:           v.ID,
+		Date:         date,
+		Title:        v.Title,
+		URL:          v.URL,
+		RiskLevel:    v.RiskLevel,
+		DerivedRisk:  v.DerivedRisk,
+		RiskInferred: v.RiskInferred,
+		IsRemote:     v.Remote,
+		IsLocal:      v.Local,
+		Author:       v.Author,
+		AuthorURL:    v.AuthorURL,
+		ClusterID:    v.ClusterID,
+	}
+}
+
+// LoadResultFile 读取之前保存的JSON结果文件，返回其中的漏洞
+// 支持exploit/list保存的漏洞列表、漏洞详情、search保存的搜索结果(包括多关键词搜索)、
+// 作者信息(返回作者发布的漏洞)、CVE详情(返回相关漏洞)以及漏洞数组。
+//
+// 参数:
+//   - path: JSON文件路径
+//
+// 返回值:
+//   - []model.Vulnerability: 文件中的漏洞，按文件中的顺序排列
+//   - error: 读取失败、JSON格式错误或无法识别文件内容时返回错误
+//
+// 示例:
+//
+//	items, err := crawler.LoadResultFile("search_result.json")
+func LoadResultFile(path string) ([]model.Vulnerability, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取结果文件失败: %w", err)
+	}
+	items, err := ParseResultJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("解析结果文件 %s 失败: %w", path, err)
+	}
+	return items, nil
+}
+
+// ParseResultJSON 从JSON数据中解析漏洞，支持的格式见LoadResultFile
+func ParseResultJSON(data []byte) ([]model.Vulnerability, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		var items []model.Vulnerability
+		if err := json.Unmarshal(data, &items); err != nil {
+			return nil, err
+		}
+		return items, nil
+	}
+
+	var probe struct {
+		Keyword                *string         `json:"keyword"`
+		Keywords               []KeywordHits   `json:"keywords"`
+		Items                  json.RawMessage `json:"items"`
+		Vulnerabilities        json.RawMessage `json:"vulnerabilities"`
+		RelatedVulnerabilities json.RawMessage `json:"related_vulnerabilities"`
+		CveID                  string          `json:"cve_id"`
+		ID                     string          `json:"id"`
+		Title                  string          `json:"title"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case probe.Items != nil:
+		var items []model.Vulnerability
+		err := json.Unmarshal(probe.Items, &items)
+		return items, err
+	case probe.Keyword != nil || probe.Keywords != nil:
+		// 搜索结果的日期为 YYYY-MM-DD 字符串，需要逐项转换
+		var found []SearchVulnerability
+		if probe.Vulnerabilities != nil {
+			if err := json.Unmarshal(probe.Vulnerabilities, &found); err != nil {
+				return nil, err
+			}
+		}
+		items := make([]model.Vulnerability, 0, len(found))
+		for _, v := range found {
+			items = append(items, v.ToVulnerability())
+		}
+		return items, nil
+	case probe.Vulnerabilities != nil:
+		var items []model.Vulnerability
+		err := json.Unmarshal(probe.Vulnerabilities, &items)
+		return items, err
+	case probe.CveID != "":
+		var items []model.Vulnerability
+		if probe.RelatedVulnerabilities != nil {
+			if err := json.Unmarshal(probe.RelatedVulnerabilities, &items); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	case probe.ID != "" || probe.Title != "":
+		var item model.Vulnerability
+		if err := json.Unmarshal(data, &item); err != nil {
+			return nil, err
+		}
+		return []model.Vulnerability{item}, nil
+	}
+	return nil, errors.New("无法识别的结果格式")
+}
@@ -0,0 +1,68 @@
+package crawler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseResultJSON(t *testing.T) {
+	// 列表结果
+	items, err := ParseResultJSON([]byte(`{"items":[{"id":"WLB-1","date":"2024-04-09T00:00:00Z","title":"A"}],"current_page":1}`))
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, time.Date(2024, 4, 9, 0, 0, 0, 0, time.UTC), items[0].Date)
+
+	// 搜索结果的日期和利用方式字段与列表不同
+	items, err = ParseResultJSON([]byte(`{"keyword":"xss","vulnerabilities":[{"id":"WLB-2","date":"2024-04-09","title":"B","remote":true}]}`))
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "WLB-2", items[0].ID)
+	assert.Equal(t, time.Date(2024, 4, 9, 0, 0, 0, 0, time.UTC), items[0].Date)
+	assert.True(t, items[0].IsRemote)
+
+	// 多关键词搜索结果
+	items, err = ParseResultJSON([]byte(`{"keywords":[{"keyword":"xss","hits":1}],"total":1,"vulnerabilities":[{"id":"WLB-7","date":"2024-04-10","keywords":["xss"]}]}`))
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC), items[0].Date)
+
+	// 作者信息
+	items, err = ParseResultJSON([]byte(`{"id":"researcher","vulnerabilities":[{"title":"C"},{"title":"D"}]}`))
+	require.NoError(t, err)
+	assert.Len(t, items, 2)
+
+	// CVE详情的相关漏洞
+	items, err = ParseResultJSON([]byte(`{"cve_id":"CVE-2024-1","related_vulnerabilities":[{"id":"WLB-3"}]}`))
+	require.NoError(t, err)
+	assert.Equal(t, "WLB-3", items[0].ID)
+
+	// 单条漏洞详情和漏洞数组
+	items, err = ParseResultJSON([]byte(`{"id":"WLB-4","title":"E"}`))
+	require.NoError(t, err)
+	assert.Equal(t, "WLB-4", items[0].ID)
+	items, err = ParseResultJSON([]byte(` [{"id":"WLB-5"},{"id":"WLB-6"}]`))
+	require.NoError(t, err)
+	assert.Len(t, items, 2)
+
+	_, err = ParseResultJSON([]byte(`{"foo":1}`))
+	assert.Error(t, err)
+	_, err = ParseResultJSON([]byte(`not json`))
+	assert.Error(t, err)
+}
+
+func TestLoadResultFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "result.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"items":[{"id":"WLB-1"}]}`), 0644))
+
+	items, err := LoadResultFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "WLB-1", items[0].ID)
+
+	_, err = LoadResultFile(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,146 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+// 镜像爬取时页面在Frontier中的分类，每个分类有独立的礼貌延迟
+const (
+	MirrorCategoryList   = "list"   // 列表页
+	MirrorCategoryDetail = "detail" // 漏洞详情页
+)
+
+// MirrorOptions 是Mirror的选项
+type MirrorOptions struct {
+	Section     Section       // 列表栏目，默认为 SectionExploit
+	MaxPages    int           // 最多爬取的列表页数，0表示爬取到最后一页
+	ListDelay   time.Duration // 两次请求列表页之间的礼貌延迟
+	DetailDelay time.Duration // 两次请求详情页之间的礼貌延迟
+	Seen        *SeenSet      // 已镜像的详情页，为nil时每次都爬取全部详情页
+}
+
+// MirrorStats 是镜像爬取的统计
+type MirrorStats struct {
+	ListPages int `json:"list_pages"` // 爬取的列表页数
+	Details   int `json:"details"`    // 成功爬取的详情页数
+	Failed    int `json:"failed"`     // 爬取失败的详情页数
+	Skipped   int `json:"skipped"`    // 之前已经镜像过而跳过的详情页数
+}
+
+// Mirror 爬取列表栏目的所有列表页和其中每个漏洞的详情页，每得到一个漏洞详情调用一次fn
+//
+// 列表页和详情页放入同一个Frontier调度，而不是逐页顺序爬取：
+// 详情页按 RiskPriority 排序，新发布的High风险漏洞先于旧的Low风险漏洞爬取；
+// 下一个列表页的优先级由当前页最旧条目的日期决定，越往后回填越旧的页面，优先级越低。
+// 列表页和详情页各自遵守礼貌延迟，互不影响。
+//
+// 指定opts.Seen时，详情页成功交给fn后记录在集合中，之后再运行时跳过已经镜像过的详情页，
+// 因此中断后重新运行或定期运行只会爬取新的漏洞。
+//
+// 单个详情页失败只计入统计，列表页失败、fn返回错误或ctx被取消时停止并返回错误，
+// 此时返回的统计是停止前的结果。
+//
+// 示例:
+//
+//	stats, err := c.Mirror(ctx, crawler.MirrorOptions{ListDelay: time.Second, DetailDelay: 2 * time.Second},
+//	    func(v *model.Vulnerability) error {
+//	        s.Put(*v)
+//	        return nil
+//	    })
+func (c *Crawler) Mirror(ctx context.Context, opts MirrorOptions, fn func(v *model.Vulnerability) error) (*MirrorStats, error) {
+	if opts.Section == "" {
+		opts.Section = SectionExploit
+	}
+	firstPath, err := SectionPath(opts.Section, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	frontier := NewFrontier(opts.DetailDelay)
+	frontier.SetCategoryDelay(MirrorCategoryList, opts.ListDelay)
+	frontier.Push(FrontierItem{Path: firstPath, Category: MirrorCategoryList, Priority: RiskPriority("", time.Now())})
+
+	stats := &MirrorStats{}
+	queued := make(map[model.WLBID]bool)
+	// 同一时间队列中最多只有一个列表页，page是它的页码
+	page := 1
+	for {
+		item, err := frontier.Next(ctx)
+		if errors.Is(err, ErrFrontierEmpty) {
+			return stats, nil
+		}
+		if err != nil {
+			return stats, err
+		}
+
+		if item.Category == MirrorCategoryDetail {
+			result, _, err := c.exploitDetail(model.WLBIDFromURL(item.Path).String(), "")
+			if err != nil {
+				stats.Failed++
+				continue
+			}
+			stats.Details++
+			if err := fn(result); err != nil {
+				return stats, err
+			}
+			if opts.Seen != nil {
+				if _, err := opts.Seen.Add(mirrorSeenURL(model.WLBIDFromURL(item.Path))); err != nil {
+					return stats, err
+				}
+			}
+			continue
+		}
+
+		list, err := c.CrawlSection(opts.Section, page, "")
+		if err != nil {
+			return stats, fmt.Errorf("获取第 %d 页失败: %w", page, err)
+		}
+		stats.ListPages++
+
+		var oldest time.Time
+		for _, v := range list.Items {
+			if !v.Date.IsZero() && (oldest.IsZero() || v.Date.Before(oldest)) {
+				oldest = v.Date
+			}
+			id, err := model.ParseWLBID(v.ID)
+			if err != nil || queued[id] {
+				continue
+			}
+			queued[id] = true
+			if opts.Seen != nil {
+				seen, err := opts.Seen.Contains(mirrorSeenURL(id))
+				if err != nil {
+					return stats, err
+				}
+				if seen {
+					stats.Skipped++
+					continue
+				}
+			}
+			frontier.Push(FrontierItem{
+				Path:     id.Path(),
+				Category: MirrorCategoryDetail,
+				Priority: RiskPriority(v.RiskLevel, v.Date),
+				Date:     v.Date,
+			})
+		}
+
+		if page >= list.TotalPages || (opts.MaxPages > 0 && page >= opts.MaxPages) {
+			continue
+		}
+		page++
+		path, _ := SectionPath(opts.Section, page)
+		frontier.Push(FrontierItem{Path: path, Category: MirrorCategoryList, Priority: RiskPriority("", oldest), Date: oldest})
+	}
+}
+
+// mirrorSeenURL 返回详情页在已镜像集合中的键
+// 使用规范地址而不是当前的基础URL，切换镜像站点后已镜像的记录仍然有效
+func mirrorSeenURL(id model.WLBID) string {
+	return model.SiteURL + id.Path()
+}
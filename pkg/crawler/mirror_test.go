@@ -0,0 +1,115 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+// newMirrorTestCrawler 返回一个有2页列表的爬虫，以及记录请求路径的切片
+// 第1页: WLB-2024040003(三年前，Low)、WLB-2024040002(今天，High)
+// 第2页: WLB-2024040002(翻页时重复出现)、WLB-2024040001(三年前，Med.)
+func newMirrorTestCrawler(failDetail string) (*Crawler, *[]string) {
+	var paths []string
+	client := &mockClient{baseURL: "https://cxsecurity.com", getPageFunc: func(path string) (string, error) {
+		paths = append(paths, path)
+		if failDetail != "" && strings.HasSuffix(path, failDetail) {
+			return "", errors.New("connection reset")
+		}
+		return path, nil
+	}}
+	old := time.Now().AddDate(-3, 0, 0)
+	parser := &mockParser{
+		parseListPageFunc: func(html string) (*model.VulnerabilityList, error) {
+			if strings.HasSuffix(html, "/2") {
+				return &model.VulnerabilityList{CurrentPage: 2, TotalPages: 2, Items: []model.Vulnerability{
+					{ID: "WLB-2024040002", RiskLevel: "High", Date: time.Now()},
+					{ID: "WLB-2024040001", RiskLevel: "Med.", Date: old},
+				}}, nil
+			}
+			return &model.VulnerabilityList{CurrentPage: 1, TotalPages: 2, Items: []model.Vulnerability{
+				{ID: "WLB-2024040003", RiskLevel: "Low", Date: old},
+				{ID: "WLB-2024040002", RiskLevel: "High", Date: time.Now()},
+			}}, nil
+		},
+		parseVulnerabilityDetailPageFunc: func(html string) (*model.Vulnerability, error) {
+			return &model.Vulnerability{URL: "https://cxsecurity.com" + html, Title: html}, nil
+		},
+	}
+	return NewCrawler(WithHTTPClient(client), WithCustomParser(parser)), &paths
+}
+
+func TestMirror(t *testing.T) {
+	c, paths := newMirrorTestCrawler("")
+
+	var ids []string
+	stats, err := c.Mirror(context.Background(), MirrorOptions{}, func(v *model.Vulnerability) error {
+		ids = append(ids, v.ID)
+		return nil
+	})
+	require.NoError(t, err)
+
+	// 新的High风险漏洞先于旧的漏洞和回填的列表页爬取，重复出现的漏洞只爬取一次
+	assert.Equal(t, []string{
+		"/exploit/1",
+		"/issue/WLB-2024040002",
+		"/issue/WLB-2024040003",
+		"/exploit/2",
+		"/issue/WLB-2024040001",
+	}, *paths)
+	assert.Equal(t, []string{"WLB-2024040002", "WLB-2024040003", "WLB-2024040001"}, ids)
+	assert.Equal(t, &MirrorStats{ListPages: 2, Details: 3}, stats)
+}
+
+func TestMirrorMaxPagesAndFailures(t *testing.T) {
+	c, paths := newMirrorTestCrawler("WLB-2024040003")
+
+	stats, err := c.Mirror(context.Background(), MirrorOptions{Section: SectionBulletin, MaxPages: 1}, func(v *model.Vulnerability) error {
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/wlb/1", "/issue/WLB-2024040002", "/issue/WLB-2024040003"}, *paths)
+	assert.Equal(t, &MirrorStats{ListPages: 1, Details: 1, Failed: 1}, stats, "单个详情页失败不中断镜像")
+}
+
+func TestMirrorStopsOnCallbackError(t *testing.T) {
+	c, _ := newMirrorTestCrawler("")
+
+	stop := errors.New("disk full")
+	stats, err := c.Mirror(context.Background(), MirrorOptions{}, func(v *model.Vulnerability) error {
+		return stop
+	})
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, 1, stats.Details)
+
+	_, err = c.Mirror(context.Background(), MirrorOptions{Section: "unknown"}, nil)
+	assert.Error(t, err)
+}
+
+func TestMirrorSkipsSeenDetails(t *testing.T) {
+	seen, err := OpenSeenSet(t.TempDir(), 1000, 0.001)
+	require.NoError(t, err)
+	defer seen.Close()
+	save := func(v *model.Vulnerability) error { return nil }
+
+	c, _ := newMirrorTestCrawler("WLB-2024040003")
+	stats, err := c.Mirror(context.Background(), MirrorOptions{Seen: seen}, save)
+	require.NoError(t, err)
+	assert.Equal(t, &MirrorStats{ListPages: 2, Details: 2, Failed: 1}, stats)
+	assert.Equal(t, 2, seen.Len(), "失败的详情页不记录")
+
+	// 再次运行只爬取列表页和上次失败的详情页
+	c, paths := newMirrorTestCrawler("")
+	stats, err = c.Mirror(context.Background(), MirrorOptions{Seen: seen}, save)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/exploit/1", "/issue/WLB-2024040003", "/exploit/2"}, *paths)
+	assert.Equal(t, &MirrorStats{ListPages: 2, Details: 1, Skipped: 2}, stats)
+	assert.Equal(t, 3, seen.Len())
+}
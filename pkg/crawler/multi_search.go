@@ -0,0 +1,220 @@
+package crawler
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultSearchConcurrency 是多关键词搜索默认同时进行的搜索数
+const DefaultSearchConcurrency = 4
+
+// MultiSearchOptions 是MultiSearch的选项
+type MultiSearchOptions struct {
+	Keywords    []string      // 搜索关键词，每个关键词单独搜索，重复的关键词(不区分大小写)只搜索一次
+	Search      SearchOptions // 每个关键词使用的搜索条件，其中的Keyword会被忽略
+	Pages       int           // 每个关键词最多获取的页数，从Search.Page开始，默认1页
+	Concurrency int           // 同时进行的搜索数，默认为 DefaultSearchConcurrency
+}
+
+// KeywordHits 是一个关键词的搜索统计
+type KeywordHits struct {
+	Keyword    string `json:"keyword"`
+	Hits       int    `json:"hits"`                 // 该关键词搜索到的漏洞数，同一漏洞只统计一次
+	Pages      int    `json:"pages"`                // 实际获取的页数
+	TotalPages int    `json:"total_pages"`          // 网站上的总页数
+	Duplicates int    `json:"duplicates,omitempty"` // 在该关键词的多页结果中重复出现而去掉的记录数
+	Error      string `json:"error,omitempty"`      // 搜索失败的原因，失败前已获取的结果仍然保留
+}
+
+// MultiSearchResult 是多关键词搜索合并去重后的结果
+type MultiSearchResult struct {
+	Keywords        []KeywordHits         `json:"keywords"`        // 每个关键词的统计，顺序与输入相同
+	SortOrder       string                `json:"sort_order"`      // 排序顺序(ASC、DESC或RELEVANCE)
+	Total           int                   `json:"total"`           // 去重后的漏洞数
+	Vulnerabilities []SearchVulnerability `json:"vulnerabilities"` // 去重后的漏洞，Keywords为匹配的关键词
+}
+
+// ReadKeywords 读取关键词列表，每行一个关键词
+// 忽略空行和以 # 开头的注释行，重复的关键词(不区分大小写)只保留第一次出现的
+func ReadKeywords(r io.Reader) ([]string, error) {
+	var keywords []string
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if key := strings.ToLower(line); !seen[key] {
+			seen[key] = true
+			keywords = append(keywords, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取关键词列表失败: %w", err)
+	}
+	return keywords, nil
+}
+
+// ReadKeywordsFile 从文件读取关键词列表，格式见 ReadKeywords
+func ReadKeywordsFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("打开关键词列表失败: %w", err)
+	}
+	defer f.Close()
+	return ReadKeywords(f)
+}
+
+// keywordSearch 是一个关键词的搜索结果
+type keywordSearch struct {
+	hits  KeywordHits
+	items []SearchVulnerability
+	err   error // 搜索失败的原因，与 hits.Error 相同，用于 errors.Is 判断(例如ErrBlocked)
+}
+
+// MultiSearch 并发搜索多个关键词，合并结果并按漏洞ID(没有ID时按URL，参见 DedupKey)去重
+//
+// 最多同时进行 Concurrency 个搜索，请求间隔由客户端的限速控制(参见 WithAdaptiveThrottle)。
+// 某个关键词搜索失败时记录在该关键词的 Error 中，不影响其他关键词；所有关键词都失败时返回错误。
+// 合并后的漏洞按日期排序(SortRelevance时按相关度排序)，Keywords 字段为搜索到该漏洞的关键词。
+//
+// 示例:
+//
+//	keywords, _ := crawler.ReadKeywordsFile("products.txt")
+//	result, err := c.MultiSearch(crawler.MultiSearchOptions{
+//	    Keywords:    keywords,
+//	    Search:      crawler.SearchOptions{Risks: []string{"High"}},
+//	    Concurrency: 4,
+//	}, "products.json")
+func (c *Crawler) MultiSearch(opts MultiSearchOptions, outputPath string) (*MultiSearchResult, error) {
+	var keywords []string
+	seen := make(map[string]bool)
+	for _, kw := range opts.Keywords {
+		kw = strings.TrimSpace(kw)
+		if key := strings.ToLower(kw); kw != "" && !seen[key] {
+			seen[key] = true
+			keywords = append(keywords, kw)
+		}
+	}
+	if len(keywords) == 0 {
+		return nil, errors.New("没有指定搜索关键词")
+	}
+	if opts.Pages < 1 {
+		opts.Pages = 1
+	}
+	if opts.Concurrency < 1 {
+		opts.Concurrency = DefaultSearchConcurrency
+	}
+	search := opts.Search.normalize()
+
+	searches := make([]keywordSearch, len(keywords))
+	sem := make(chan struct{}, opts.Concurrency)
+	var wg sync.WaitGroup
+	for i, kw := range keywords {
+		wg.Add(1)
+		go func(i int, kw string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			searches[i] = c.searchKeyword(kw, search, opts.Pages)
+		}(i, kw)
+	}
+	wg.Wait()
+
+	result := &MultiSearchResult{SortOrder: search.SortOrder, Keywords: make([]KeywordHits, 0, len(searches))}
+	index := make(map[string]int)
+	failed := 0
+	for _, s := range searches {
+		result.Keywords = append(result.Keywords, s.hits)
+		if s.hits.Error != "" {
+			failed++
+		}
+		for _, item := range s.items {
+			key := item.DedupKey()
+			if i, ok := index[key]; ok {
+				merged := &result.Vulnerabilities[i]
+				merged.Keywords = append(merged.Keywords, s.hits.Keyword)
+				if item.Score > merged.Score {
+					merged.Score = item.Score
+				}
+				continue
+			}
+			index[key] = len(result.Vulnerabilities)
+			item.Keywords = []string{s.hits.Keyword}
+			result.Vulnerabilities = append(result.Vulnerabilities, item)
+		}
+	}
+	if failed == len(searches) {
+		return nil, fmt.Errorf("所有关键词都搜索失败: %w", searches[0].err)
+	}
+	sortMultiSearch(result.Vulnerabilities, search.SortOrder)
+	result.Total = len(result.Vulnerabilities)
+
+	if outputPath != "" {
+		if err := c.writeJSON(result, outputPath); err != nil {
+			return nil, fmt.Errorf("保存搜索结果失败: %w", err)
+		}
+	}
+	return result, nil
+}
+
+// searchKeyword 搜索一个关键词的前pages页，同一关键词中重复的漏洞只保留一次
+func (c *Crawler) searchKeyword(keyword string, opts SearchOptions, pages int) keywordSearch {
+	s := keywordSearch{hits: KeywordHits{Keyword: keyword}}
+	seen := make(map[string]bool)
+	opts.Keyword = keyword
+	for page := opts.Page; page < opts.Page+pages; page++ {
+		pageOpts := opts
+		pageOpts.Page = page
+		result, err := c.Search(pageOpts, "")
+		if err != nil {
+			s.hits.Error = err.Error()
+			s.err = err
+			break
+		}
+		s.hits.Pages++
+		s.hits.TotalPages = result.TotalPages
+		for _, item := range result.Vulnerabilities {
+			key := item.DedupKey()
+			if seen[key] {
+				s.hits.Duplicates++
+				continue
+			}
+			seen[key] = true
+			s.items = append(s.items, item)
+		}
+		if page >= result.TotalPages {
+			break
+		}
+	}
+	s.hits.Hits = len(s.items)
+	return s
+}
+
+// sortMultiSearch 按排序顺序排列合并后的漏洞，日期未知的排在最后
+func sortMultiSearch(items []SearchVulnerability, sortOrder string) {
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if sortOrder == SortRelevance && a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Date != b.Date {
+			knownA, knownB := a.Date != "未知", b.Date != "未知"
+			if knownA != knownB {
+				return knownA
+			}
+			if sortOrder == "ASC" {
+				return a.Date < b.Date
+			}
+			return a.Date > b.Date
+		}
+		return a.ID < b.ID
+	})
+}
@@ -0,0 +1,120 @@
+package crawler
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+func TestReadKeywords(t *testing.T) {
+	keywords, err := ReadKeywords(strings.NewReader("# 产品列表\nWordPress\n\n  nginx  \nwordpress\nApache Struts\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"WordPress", "nginx", "Apache Struts"}, keywords)
+}
+
+// multiSearchCrawler 返回一个按关键词和页码返回固定结果的爬虫
+// 客户端把请求路径原样作为页面内容，解析器根据路径中的关键词返回漏洞
+func multiSearchCrawler(pages map[string][][]model.Vulnerability, inFlight, maxInFlight *int32) *Crawler {
+	var mu sync.Mutex
+	client := &mockClient{baseURL: "https://cxsecurity.com", getPageFunc: func(path string) (string, error) {
+		if inFlight != nil {
+			n := atomic.AddInt32(inFlight, 1)
+			mu.Lock()
+			if n > *maxInFlight {
+				*maxInFlight = n
+			}
+			mu.Unlock()
+			time.Sleep(10 * time.Millisecond)
+			atomic.AddInt32(inFlight, -1)
+		}
+		return path, nil
+	}}
+	parser := &mockParser{parseListPageFunc: func(path string) (*model.VulnerabilityList, error) {
+		// 路径格式为 /search/wlb/DESC/AND/日期/页码/每页数量/关键词/
+		parts := strings.Split(strings.Trim(path, "/"), "/")
+		keyword := parts[len(parts)-1]
+		page, _ := strconv.Atoi(parts[len(parts)-3])
+		list, ok := pages[keyword]
+		if !ok {
+			return nil, errors.New("search failed")
+		}
+		return &model.VulnerabilityList{Items: list[page-1], CurrentPage: page, TotalPages: len(list)}, nil
+	}}
+	return NewCrawler(WithHTTPClient(client), WithCustomParser(parser))
+}
+
+func TestMultiSearch(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 4, d, 0, 0, 0, 0, time.UTC) }
+	pages := map[string][][]model.Vulnerability{
+		"wordpress": {
+			{{ID: "WLB-2024040001", Title: "WordPress Plugin XSS", Date: day(1)}, {ID: "WLB-2024040003", Title: "WordPress nginx RCE", Date: day(3)}},
+			// 网站翻页时上一页的漏洞可能再次出现
+			{{ID: "WLB-2024040002", Title: "WordPress SQL Injection", Date: day(2)}, {ID: "WLB-2024040003", Title: "WordPress nginx RCE", Date: day(3)}},
+		},
+		"nginx": {
+			{{ID: "WLB-2024040003", Title: "WordPress nginx RCE", Date: day(3)}, {ID: "WLB-2024040004", Title: "nginx DoS"}},
+		},
+	}
+	c := multiSearchCrawler(pages, nil, nil)
+
+	result, err := c.MultiSearch(MultiSearchOptions{Keywords: []string{"wordpress", "nginx", "WordPress", "broken"}, Pages: 3}, "")
+	require.NoError(t, err)
+
+	require.Len(t, result.Keywords, 3)
+	assert.Equal(t, KeywordHits{Keyword: "wordpress", Hits: 3, Pages: 2, TotalPages: 2, Duplicates: 1}, result.Keywords[0])
+	assert.Equal(t, KeywordHits{Keyword: "nginx", Hits: 2, Pages: 1, TotalPages: 1}, result.Keywords[1])
+	assert.Equal(t, "broken", result.Keywords[2].Keyword)
+	assert.Contains(t, result.Keywords[2].Error, "search failed")
+
+	// 按日期降序排列，日期未知的在最后，两个关键词都搜索到的漏洞只出现一次
+	assert.Equal(t, 4, result.Total)
+	var ids []string
+	for _, v := range result.Vulnerabilities {
+		ids = append(ids, v.ID)
+	}
+	assert.Equal(t, []string{"WLB-2024040003", "WLB-2024040002", "WLB-2024040001", "WLB-2024040004"}, ids)
+	assert.Equal(t, []string{"wordpress", "nginx"}, result.Vulnerabilities[0].Keywords)
+	assert.Equal(t, []string{"nginx"}, result.Vulnerabilities[3].Keywords)
+
+	// 只获取第一页
+	result, err = c.MultiSearch(MultiSearchOptions{Keywords: []string{"wordpress"}, Search: SearchOptions{SortOrder: "ASC"}}, "")
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Keywords[0].Pages)
+	assert.Equal(t, "WLB-2024040001", result.Vulnerabilities[0].ID)
+}
+
+func TestMultiSearchErrors(t *testing.T) {
+	c := multiSearchCrawler(nil, nil, nil)
+
+	_, err := c.MultiSearch(MultiSearchOptions{Keywords: []string{" ", ""}}, "")
+	assert.ErrorContains(t, err, "没有指定搜索关键词")
+
+	_, err = c.MultiSearch(MultiSearchOptions{Keywords: []string{"a", "b"}}, "")
+	assert.ErrorContains(t, err, "所有关键词都搜索失败")
+}
+
+func TestMultiSearchConcurrency(t *testing.T) {
+	pages := make(map[string][][]model.Vulnerability)
+	var keywords []string
+	for _, kw := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		pages[kw] = [][]model.Vulnerability{{{ID: "WLB-" + kw}}}
+		keywords = append(keywords, kw)
+	}
+	var inFlight, maxInFlight int32
+	c := multiSearchCrawler(pages, &inFlight, &maxInFlight)
+
+	result, err := c.MultiSearch(MultiSearchOptions{Keywords: keywords, Concurrency: 3}, "")
+	require.NoError(t, err)
+	assert.Equal(t, 8, result.Total)
+	assert.LessOrEqual(t, maxInFlight, int32(3))
+	assert.Greater(t, maxInFlight, int32(1))
+}
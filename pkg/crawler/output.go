@@ -0,0 +1,159 @@
+package crawler
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// DefaultFileMode 输出文件的默认权限
+	DefaultFileMode os.FileMode = 0644
+	// DefaultDirMode 输出目录的默认权限
+	DefaultDirMode os.FileMode = 0755
+	// SecureFileMode 安全输出模式下的文件权限，只有当前用户可以读写
+	SecureFileMode os.FileMode = 0600
+	// SecureDirMode 安全输出模式下的目录权限，只有当前用户可以访问
+	SecureDirMode os.FileMode = 0700
+)
+
+// WithOutputPermissions 设置输出文件和目录的权限
+// 爬取到的漏洞利用数据可能比较敏感，在多人共用的主机上可以收紧权限。
+// 参数为0时保持默认值。
+//
+// 参数:
+//   - fileMode: 输出文件权限，例如 0640
+//   - dirMode: 新建输出目录的权限，例如 0750
+//
+// 返回值:
+//   - CrawlerOption: 返回一个配置函数
+//
+// 示例:
+//
+//	c := NewCrawler(WithOutputPermissions(0640, 0750))
+func WithOutputPermissions(fileMode, dirMode os.FileMode) CrawlerOption {
+	return func(c *Crawler) {
+		if fileMode != 0 {
+			c.fileMode = fileMode.Perm()
+		}
+		if dirMode != 0 {
+			c.dirMode = dirMode.Perm()
+		}
+	}
+}
+
+// WithSecureOutput 使用只有当前用户可以访问的权限(文件0600、目录0700)保存结果
+func WithSecureOutput() CrawlerOption {
+	return WithOutputPermissions(SecureFileMode, SecureDirMode)
+}
+
+// WithOutputSync 设置保存结果时是否调用fsync
+// 开启后在重命名前将文件内容刷到磁盘，并同步所在目录，
+// 即使系统崩溃或断电也不会留下内容不完整的结果文件，但写入速度会变慢。
+//
+// 示例:
+//
+//	c := NewCrawler(WithOutputSync(true))
+func WithOutputSync(sync bool) CrawlerOption {
+	return func(c *Crawler) {
+		c.syncOutput = sync
+	}
+}
+
+// SaveJSON 使用爬虫配置的文件权限和同步方式，将任意结果保存为JSON文件
+// 适用于对爬取结果做了过滤等处理后再保存的场景
+func (c *Crawler) SaveJSON(v interface{}, outputPath string) error {
+	return c.writeJSON(v, outputPath)
+}
+
+// SaveFile 使用爬虫配置的文件权限和同步方式保存任意数据，用于导出CSV、Cypher等非JSON格式
+// 自动创建不存在的目录，写入是原子的
+func (c *Crawler) SaveFile(data []byte, outputPath string) error {
+	return c.writeFile(data, outputPath)
+}
+
+// writeJSON 将结果格式化为JSON并保存到文件中
+// 自动创建不存在的目录，并通过WriteFileAtomic原子地写入，
+// 中断的运行不会留下被截断的结果文件。
+func (c *Crawler) writeJSON(v interface{}, outputPath string) error {
+	// 将结果序列化为JSON
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("编码JSON失败: %w", err)
+	}
+	return c.writeFile(data, outputPath)
+}
+
+// writeFile 创建不存在的目录后原子地写入文件
+func (c *Crawler) writeFile(data []byte, outputPath string) error {
+	// 创建目录
+	dir := filepath.Dir(outputPath)
+	if dir != "" && dir != "." {
+		if err := os.MkdirAll(dir, c.dirMode); err != nil {
+			return fmt.Errorf("创建输出目录失败: %w", err)
+		}
+	}
+
+	// 写入文件
+	if err := WriteFileAtomic(outputPath, data, c.fileMode, c.syncOutput); err != nil {
+		return fmt.Errorf("写入文件失败: %w", err)
+	}
+
+	return nil
+}
+
+// WriteFileAtomic 原子地将数据写入文件
+// 先写入同一目录下的临时文件，再重命名为目标文件。读取方要么看到旧文件，
+// 要么看到完整的新文件，不会看到写了一半的内容；写入失败时临时文件会被删除。
+// 写入后会显式设置文件权限，因此权限不受umask影响，覆盖已有文件时也会更新权限。
+//
+// 参数:
+//   - path: 目标文件路径，所在目录必须已存在
+//   - data: 要写入的数据
+//   - perm: 文件权限
+//   - sync: 是否在重命名前调用fsync，并在重命名后同步所在目录
+//
+// 返回值:
+//   - error: 写入、同步或重命名失败时返回错误
+func WriteFileAtomic(path string, data []byte, perm os.FileMode, sync bool) (err error) {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmpPath)
+		}
+	}()
+
+	if _, err = tmp.Write(data); err != nil {
+		return err
+	}
+	if sync {
+		if err = tmp.Sync(); err != nil {
+			return err
+		}
+	}
+	if err = tmp.Chmod(perm); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(tmpPath, path); err != nil {
+		return err
+	}
+
+	if sync {
+		// 同步目录，确保重命名本身已持久化；部分平台不支持对目录fsync，忽略该错误
+		if d, openErr := os.Open(dir); openErr == nil {
+			d.Sync()
+			d.Close()
+		}
+	}
+	return nil
+}
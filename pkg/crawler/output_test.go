@@ -0,0 +1,43 @@
+package crawler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	outputPath := filepath.Join(dir, "result.json")
+	require.NoError(t, os.WriteFile(outputPath, []byte(`{"old":true}`), 0644))
+
+	for _, sync := range []bool{false, true} {
+		require.NoError(t, WriteFileAtomic(outputPath, []byte(`{"new":true}`), 0644, sync))
+
+		data, err := os.ReadFile(outputPath)
+		require.NoError(t, err)
+		assert.Equal(t, `{"new":true}`, string(data))
+	}
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "不应残留临时文件")
+}
+
+func TestWriteFileAtomicKeepsOldFileOnFailure(t *testing.T) {
+	dir := t.TempDir()
+	// 目标路径是一个非空目录，重命名会失败
+	outputPath := filepath.Join(dir, "result.json")
+	require.NoError(t, os.MkdirAll(filepath.Join(outputPath, "child"), 0755))
+
+	err := WriteFileAtomic(outputPath, []byte("{}"), 0644, false)
+	assert.Error(t, err)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "失败时应删除临时文件")
+	assert.True(t, entries[0].IsDir(), "原有内容不应被破坏")
+}
@@ -0,0 +1,62 @@
+//go:build !windows
+
+package crawler
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Windows没有Unix权限位和umask，权限相关的测试只在其他系统上运行
+
+func TestWriteJSONPermissions(t *testing.T) {
+	// 使用宽松的umask，确认权限由配置决定
+	oldMask := syscall.Umask(0)
+	defer syscall.Umask(oldMask)
+
+	tests := []struct {
+		name     string
+		options  []CrawlerOption
+		fileMode os.FileMode
+		dirMode  os.FileMode
+	}{
+		{"默认权限", nil, DefaultFileMode, DefaultDirMode},
+		{"安全输出", []CrawlerOption{WithSecureOutput()}, SecureFileMode, SecureDirMode},
+		{"自定义权限", []CrawlerOption{WithOutputPermissions(0640, 0750)}, 0640, 0750},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCrawler(tt.options...)
+			dir := filepath.Join(t.TempDir(), "out")
+			outputPath := filepath.Join(dir, "result.json")
+
+			require.NoError(t, c.writeJSON(map[string]string{"id": "WLB-2024040015"}, outputPath))
+
+			info, err := os.Stat(outputPath)
+			require.NoError(t, err)
+			assert.Equal(t, tt.fileMode, info.Mode().Perm(), "文件权限不匹配")
+
+			info, err = os.Stat(dir)
+			require.NoError(t, err)
+			assert.Equal(t, tt.dirMode, info.Mode().Perm(), "目录权限不匹配")
+		})
+	}
+}
+
+func TestWriteJSONTightensExistingFile(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "result.json")
+	require.NoError(t, os.WriteFile(outputPath, []byte("{}"), 0644))
+
+	c := NewCrawler(WithSecureOutput())
+	require.NoError(t, c.writeJSON([]int{1}, outputPath))
+
+	info, err := os.Stat(outputPath)
+	require.NoError(t, err)
+	assert.Equal(t, SecureFileMode, info.Mode().Perm(), "覆盖已有文件时应更新权限")
+}
@@ -4,6 +4,10 @@ import (
 	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
 )
 
+// ParserRulesVersion 是内置解析规则(选择器、正则和标签映射)的版本
+// 修改解析规则时更新，同一版本解析同一页面的结果相同，用于排查不同版本的解析结果为什么不一致
+const ParserRulesVersion = "2026.10.16"
+
 // HTMLParser 定义HTML解析器接口，用于解析不同类型的页面内容。
 // 这个接口的设计目的是将页面解析逻辑与爬虫逻辑分离，便于测试和扩展。
 //
@@ -52,9 +56,29 @@ type HTMLParser interface {
 //	    log.Fatal(err)
 //	}
 //	fmt.Printf("Found %d vulnerabilities\n", len(list.Items))
-type Parser struct{}
+type Parser struct {
+	labels *LabelMapper // 标签映射表，为nil时使用内置映射表
+}
+
+// ParserOption 是设置Parser选项的函数类型
+type ParserOption func(*Parser)
+
+// WithParserLabels 设置解析风险级别和标签时使用的映射表，为nil时使用内置映射表
+//
+// 示例:
+//
+//	parser := NewParser(WithParserLabels(mapper))
+func WithParserLabels(m *LabelMapper) ParserOption {
+	return func(p *Parser) {
+		p.labels = m
+	}
+}
 
 // NewParser 创建一个新的Parser实例
-func NewParser() *Parser {
-	return &Parser{}
+func NewParser(options ...ParserOption) *Parser {
+	p := &Parser{}
+	for _, option := range options {
+		option(p)
+	}
+	return p
 }
@@ -1,6 +1,13 @@
 package crawler
 
-import "testing"
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// benchmarkFixtureDir 存放归档页面样本的目录，基准测试使用这些页面作为输入
+const benchmarkFixtureDir = "../../docs/response-examples"
 
 func TestNewParser(t *testing.T) {
 	parser := NewParser()
@@ -13,3 +20,14 @@ func TestNewParser(t *testing.T) {
 	// 由于Parser结构体是空的，我们只能验证它不是nil
 	// 实际功能在其他专门的解析器测试文件中测试
 }
+
+// loadBenchmarkFixture 读取归档的页面样本，文件不存在时跳过基准测试
+func loadBenchmarkFixture(b *testing.B, name string) string {
+	b.Helper()
+
+	content, err := os.ReadFile(filepath.Join(benchmarkFixtureDir, name))
+	if err != nil {
+		b.Skipf("跳过基准测试，样本文件不存在：%s", name)
+	}
+	return string(content)
+}
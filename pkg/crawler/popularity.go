@@ -0,0 +1,88 @@
+package crawler
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+var (
+	// commentsPatterns 匹配 "Comments: 3"、"3 comments" 这样的评论数，按顺序尝试
+	// 名称在前的形式优先，"Comments: 3 Views: 10" 中的 "3 Views" 不会被当作浏览数
+	commentsPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)\bcomments?\s*:?\s*(\d[\d,]*)`),
+		regexp.MustCompile(`(?i)(\d[\d,]*)\s*comments?\b`),
+	}
+	// viewsPatterns 匹配 "Views: 1,024"、"120 hits" 这样的浏览数
+	viewsPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)\b(?:views?|hits?)\s*:?\s*(\d[\d,]*)`),
+		regexp.MustCompile(`(?i)(\d[\d,]*)\s*(?:views?|hits?)\b`),
+	}
+	// countPattern 匹配图标旁边的数字
+	countPattern = regexp.MustCompile(`\d[\d,]*`)
+)
+
+// popularity 是列表行中的热度计数，页面上没有时为0
+type popularity struct {
+	comments int
+	views    int
+}
+
+// parseCount 解析带千位分隔符的数字
+func parseCount(s string) int {
+	n, _ := strconv.Atoi(strings.ReplaceAll(s, ",", ""))
+	return n
+}
+
+// matchCount 返回文本中第一个匹配的计数，没有匹配时返回0
+func matchCount(patterns []*regexp.Regexp, text string) int {
+	for _, pattern := range patterns {
+		if m := pattern.FindStringSubmatch(text); m != nil {
+			return parseCount(m[1])
+		}
+	}
+	return 0
+}
+
+// isPopularityLabel 判断列表行中的标签是否为计数，计数由 parseRowPopularity 提取，不作为漏洞标签
+func isPopularityLabel(text string) bool {
+	for _, pattern := range append(commentsPatterns, viewsPatterns...) {
+		if pattern.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseRowPopularity 从列表行中提取评论数和浏览数
+// 网站只在部分列表中显示计数，形式为文本（"Comments: 3"）或图标加数字
+// （glyphicon-comment、glyphicon-eye-open）；标题可能包含同样的单词，不参与匹配。
+//
+// 参数:
+//   - row: 列表中的一行
+//   - title: 标题所在的元素，匹配时排除
+func parseRowPopularity(row, title *goquery.Selection) popularity {
+	var p popularity
+	row.Find("[class*='glyphicon-comment'], [class*='fa-comment']").EachWithBreak(func(_ int, icon *goquery.Selection) bool {
+		p.comments = parseCount(countPattern.FindString(icon.Parent().Text()))
+		return p.comments == 0
+	})
+	row.Find("[class*='glyphicon-eye'], [class*='fa-eye']").EachWithBreak(func(_ int, icon *goquery.Selection) bool {
+		p.views = parseCount(countPattern.FindString(icon.Parent().Text()))
+		return p.views == 0
+	})
+
+	text := row.Text()
+	if t := title.Text(); t != "" {
+		text = strings.Replace(text, t, " ", 1)
+	}
+	if p.comments == 0 {
+		p.comments = matchCount(commentsPatterns, text)
+	}
+	if p.views == 0 {
+		p.views = matchCount(viewsPatterns, text)
+	}
+	return p
+}
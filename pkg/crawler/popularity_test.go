@@ -0,0 +1,55 @@
+package crawler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseListPagePopularity(t *testing.T) {
+	html := `<table class="table table-striped"><thead><tr><th><font>2024-04-15</font></th></tr></thead><tbody>
+<tr><td><span class="label">High</span></td><td><div class="row">
+  <div class="col-md-7"><h6><a href="/issue/WLB-2024040001">Comments 2 Plugin XSS</a></h6></div>
+  <div class="col-md-5"><h6><span class="label">Comments: 3</span> <span class="label">Views: 1,024</span> <span class="label">PHP</span>
+  <span class="label"><a href="/author/alice/1/">alice</a></span></h6></div>
+</div></td></tr>
+<tr><td><span class="label">Low</span></td><td><div class="row">
+  <div class="col-md-7"><h6><a href="/issue/WLB-2024040002">Banner</a></h6></div>
+  <div class="col-md-5"><h6><span><i class="glyphicon glyphicon-comment"></i> 7</span> <span><i class="glyphicon glyphicon-eye-open"></i> 88</span></h6></div>
+</div></td></tr>
+<tr><td><span class="label">Low</span></td><td><div class="row">
+  <div class="col-md-7"><h6><a href="/issue/WLB-2024040003">Comments 5 views 9</a></h6></div>
+  <div class="col-md-5"><h6><span class="label">Remote</span></h6></div>
+</div></td></tr>
+</tbody></table>`
+
+	list, err := NewParser().ParseListPage(html)
+	require.NoError(t, err)
+	require.Len(t, list.Items, 3)
+
+	assert.Equal(t, 3, list.Items[0].Comments)
+	assert.Equal(t, 1024, list.Items[0].Views)
+	assert.Equal(t, []string{"PHP"}, list.Items[0].Tags, "计数不应作为标签")
+
+	assert.Equal(t, 7, list.Items[1].Comments)
+	assert.Equal(t, 88, list.Items[1].Views)
+
+	// 标题中的单词不是计数
+	assert.Zero(t, list.Items[2].Comments)
+	assert.Zero(t, list.Items[2].Views)
+}
+
+func TestParseListPageWithoutPopularity(t *testing.T) {
+	page, err := os.ReadFile(filepath.Join(benchmarkFixtureDir, "list-response.html"))
+	require.NoError(t, err)
+	list, err := NewParser().ParseListPage(string(page))
+	require.NoError(t, err)
+	require.NotEmpty(t, list.Items)
+	for _, item := range list.Items {
+		assert.Zero(t, item.Comments, item.ID)
+		assert.Zero(t, item.Views, item.ID)
+	}
+}
@@ -0,0 +1,110 @@
+package crawler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+// Provenance 记录每个字段由哪个选择器或规则提取，键为结果中的JSON字段名
+// 只记录提取到值的字段，排查大批量爬取中的提取质量问题时，可以看出值来自主选择器还是备选规则
+type Provenance map[string]string
+
+// set 记录字段的来源，p为nil时不记录，解析器不需要判断是否启用了来源记录
+func (p Provenance) set(field, rule string) {
+	if p != nil {
+		p[field] = rule
+	}
+}
+
+// ProvenanceReport 是一个页面的字段来源，由 WithProvenance 写入与结果文件同名的旁路文件
+type ProvenanceReport struct {
+	URL     string     `json:"url"`               // 页面地址
+	Kind    string     `json:"kind"`              // 页面类型，vulnerability 或 cve
+	Fields  Provenance `json:"fields"`            // 字段 -> 选择器或规则
+	Missing []string   `json:"missing,omitempty"` // 解析器会尝试提取但页面上没有找到的字段
+}
+
+// ProvenanceParser 是可以同时返回字段来源的解析器，默认的Parser实现了该接口
+// WithCustomParser 设置的解析器没有实现该接口时不写入旁路文件
+type ProvenanceParser interface {
+	// ParseVulnerabilityDetailPageWithProvenance 与 ParseVulnerabilityDetailPage 相同，同时返回字段来源
+	ParseVulnerabilityDetailPageWithProvenance(htmlContent string) (*model.Vulnerability, Provenance, error)
+
+	// ParseCveDetailPageWithProvenance 与 ParseCveDetailPage 相同，同时返回字段来源
+	ParseCveDetailPageWithProvenance(htmlContent string) (*model.CveDetail, Provenance, error)
+}
+
+// 页面类型，用于字段来源和额外字段
+const (
+	PageVulnerability = "vulnerability"
+	PageCve           = "cve"
+)
+
+// provenanceFields 是各类页面中解析器会尝试提取的字段，用于列出缺失的字段
+var provenanceFields = map[string][]string{
+	PageVulnerability: {
+		"title", "risk_level", "cve", "cwe", "is_local", "is_remote", "date",
+		"author", "author_url", "tags", "content", "description", "affected_versions", "platform", "source",
+	},
+	PageCve: {
+		"cve_id", "published", "modified", "description", "type", "cwe_url", "cwe_id",
+		"cvss_base_score", "cvss_impact_score", "cvss_exploit_score", "cvss3",
+		"exploit_range", "attack_complexity", "authentication",
+		"confidentiality_impact", "integrity_impact", "availability_impact",
+		"affected_software", "references", "related_vulnerabilities",
+	},
+}
+
+// WithProvenance 保存结果时额外写入字段来源的旁路文件，见 ProvenancePath
+// 只对漏洞详情和CVE详情生效，解析器需要实现 ProvenanceParser
+//
+// 返回值:
+//   - CrawlerOption: 返回一个配置函数
+func WithProvenance() CrawlerOption {
+	return func(c *Crawler) {
+		c.provenance = true
+	}
+}
+
+// ProvenancePath 返回结果文件对应的旁路文件路径，例如 cve.json -> cve.provenance.json
+func ProvenancePath(outputPath string) string {
+	return strings.TrimSuffix(outputPath, ".json") + ".provenance.json"
+}
+
+// NewProvenanceReport 根据解析得到的字段来源生成旁路文件的内容
+//
+// 参数:
+//   - url: 页面地址
+//   - kind: 页面类型，PageVulnerability 或 PageCve
+//   - fields: 解析器返回的字段来源
+func NewProvenanceReport(url, kind string, fields Provenance) ProvenanceReport {
+	report := ProvenanceReport{URL: url, Kind: kind, Fields: fields}
+	if report.Fields == nil {
+		report.Fields = Provenance{}
+	}
+	for _, field := range provenanceFields[kind] {
+		if _, ok := report.Fields[field]; !ok {
+			report.Missing = append(report.Missing, field)
+		}
+	}
+	sort.Strings(report.Missing)
+	return report
+}
+
+// saveProvenance 将字段来源写入结果文件的旁路文件
+// 选择器中常见 > 等字符，不做HTML转义，便于直接阅读
+func (c *Crawler) saveProvenance(url, kind string, fields Provenance, outputPath string) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(NewProvenanceReport(url, kind, fields)); err != nil {
+		return fmt.Errorf("编码JSON失败: %w", err)
+	}
+	return c.writeFile(buf.Bytes(), ProvenancePath(outputPath))
+}
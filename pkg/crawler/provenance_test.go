@@ -0,0 +1,85 @@
+package crawler
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWithProvenance(t *testing.T) {
+	parser := NewParser()
+
+	detail, err := os.ReadFile(filepath.Join(benchmarkFixtureDir, "vul-detail-response.html"))
+	require.NoError(t, err)
+	vuln, prov, err := parser.ParseVulnerabilityDetailPageWithProvenance(string(detail))
+	require.NoError(t, err)
+	assert.Equal(t, "h4 > B", prov["title"])
+	assert.Contains(t, prov["cve"], "a[href*='cveshow']")
+	assert.Contains(t, prov["date"], "2006.01.02")
+	assert.Equal(t, "div.premex", prov["content"])
+	assert.NotContains(t, prov, "description", "没有提取到的字段不应记录来源")
+
+	// 与不记录来源时的结果相同
+	plain, err := parser.ParseVulnerabilityDetailPage(string(detail))
+	require.NoError(t, err)
+	assert.Equal(t, plain, vuln)
+
+	cvePage, err := os.ReadFile(filepath.Join(benchmarkFixtureDir, "cve-show-detail-response.html"))
+	require.NoError(t, err)
+	_, prov, err = parser.ParseCveDetailPageWithProvenance(string(cvePage))
+	require.NoError(t, err)
+	assert.Equal(t, "h1 strong", prov["cve_id"])
+	assert.Contains(t, prov["exploit_range"], "Exploit range")
+	assert.Contains(t, prov, "references")
+}
+
+func TestNewProvenanceReport(t *testing.T) {
+	report := NewProvenanceReport("https://cxsecurity.com/issue/WLB-1", PageVulnerability, Provenance{
+		"title":   "h4 > B",
+		"content": "div.premex",
+	})
+	assert.Len(t, report.Fields, 2)
+	assert.Contains(t, report.Missing, "cve")
+	assert.NotContains(t, report.Missing, "title")
+	assert.IsNonDecreasing(t, report.Missing)
+
+	report = NewProvenanceReport("", PageCve, nil)
+	assert.NotNil(t, report.Fields)
+	assert.Contains(t, report.Missing, "cve_id")
+}
+
+func TestProvenancePath(t *testing.T) {
+	assert.Equal(t, "out/cve.provenance.json", ProvenancePath("out/cve.json"))
+	assert.Equal(t, "result.provenance.json", ProvenancePath("result"))
+}
+
+func TestCrawlerWithProvenance(t *testing.T) {
+	page, err := os.ReadFile(filepath.Join(benchmarkFixtureDir, "vul-detail-response.html"))
+	require.NoError(t, err)
+	client := &mockClient{
+		getPageFunc: func(string) (string, error) { return string(page), nil },
+		baseURL:     "https://cxsecurity.com",
+	}
+	dir := t.TempDir()
+	output := filepath.Join(dir, "detail.json")
+
+	// 未启用时不写入旁路文件
+	_, err = NewCrawler(WithHTTPClient(client)).CrawlVulnerabilityDetail("/issue/WLB-2007030137", output)
+	require.NoError(t, err)
+	assert.NoFileExists(t, ProvenancePath(output))
+
+	_, err = NewCrawler(WithHTTPClient(client), WithProvenance()).CrawlVulnerabilityDetail("/issue/WLB-2007030137", output)
+	require.NoError(t, err)
+	data, err := os.ReadFile(ProvenancePath(output))
+	require.NoError(t, err)
+	var report ProvenanceReport
+	require.NoError(t, json.Unmarshal(data, &report))
+	assert.Equal(t, "https://cxsecurity.com/issue/WLB-2007030137", report.URL)
+	assert.Equal(t, PageVulnerability, report.Kind)
+	assert.Contains(t, string(data), `"h4 > B"`, "选择器不应被HTML转义")
+	assert.Equal(t, "h4 > B", report.Fields["title"])
+}
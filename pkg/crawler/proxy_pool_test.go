@@ -0,0 +1,141 @@
+package crawler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// proxyRecorder 启动一个记录请求数的HTTP代理，status不为200时返回该状态码
+type proxyRecorder struct {
+	*httptest.Server
+	mu    sync.Mutex
+	hosts []string
+}
+
+func newProxyRecorder(status int) *proxyRecorder {
+	p := &proxyRecorder{}
+	p.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p.mu.Lock()
+		p.hosts = append(p.hosts, r.URL.Host)
+		p.mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	return p
+}
+
+func (p *proxyRecorder) count() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.hosts)
+}
+
+func TestProxyPoolRoundRobin(t *testing.T) {
+	a, b := newProxyRecorder(http.StatusOK), newProxyRecorder(http.StatusOK)
+	defer a.Close()
+	defer b.Close()
+
+	client := NewClient(WithBaseURL("http://site.test"), WithRetry(0, 0), WithProxyPool([]string{a.URL, "::bad", b.URL}, RotateRoundRobin))
+	for i := 0; i < 4; i++ {
+		_, err := client.GetPage("/exploit/")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, a.count())
+	assert.Equal(t, 2, b.count())
+	assert.Equal(t, []string{"site.test"}, a.hosts[:1], "请求经过代理发往网站")
+
+	stats := client.ProxyStats()
+	require.Len(t, stats, 2, "无效的代理地址被忽略")
+	assert.Equal(t, 2, stats[0].Requests)
+	assert.Nil(t, NewClient().ProxyStats())
+}
+
+func TestProxyPoolEjection(t *testing.T) {
+	good, bad := newProxyRecorder(http.StatusOK), newProxyRecorder(http.StatusBadGateway)
+	defer good.Close()
+	defer bad.Close()
+
+	client := NewClient(WithBaseURL("http://site.test"), WithRetry(0, 0), WithProxyPool([]string{good.URL, bad.URL}, RotateRoundRobin))
+	now := time.Now()
+	client.proxyPool.now = func() time.Time { return now }
+	for i := 0; i < 10; i++ {
+		client.GetPage("/exploit/")
+	}
+	assert.Equal(t, proxyMaxFailures, bad.count(), "连续失败后不再使用")
+	assert.Equal(t, 10-proxyMaxFailures, good.count())
+	stats := client.ProxyStats()
+	assert.Equal(t, 1, stats[1].Ejections)
+	assert.Equal(t, now.Add(proxyCooldown), stats[1].EjectedUntil)
+
+	// 冷却结束后重新加入轮换，再失败一次就被剔除
+	now = now.Add(proxyCooldown)
+	for i := 0; i < 4; i++ {
+		client.GetPage("/exploit/")
+	}
+	assert.Equal(t, proxyMaxFailures+1, bad.count())
+	assert.Equal(t, 2, client.ProxyStats()[1].Ejections)
+	assert.Equal(t, 0, client.ProxyStats()[0].Failures)
+}
+
+func TestProxyPoolExhausted(t *testing.T) {
+	bad := newProxyRecorder(http.StatusTooManyRequests)
+	defer bad.Close()
+
+	client := NewClient(WithBaseURL("http://site.test"), WithRetry(proxyMaxFailures, time.Millisecond), WithAdaptiveThrottle(time.Millisecond, time.Millisecond, time.Millisecond),
+		WithProxyPool([]string{bad.URL}, RotateRandom))
+	_, err := client.GetPage("/exploit/")
+	assert.True(t, errors.Is(err, ErrNoProxyAvailable), "%v", err)
+	assert.Equal(t, proxyMaxFailures, bad.count())
+}
+
+func TestProxyPoolSticky(t *testing.T) {
+	var proxies []*poolProxy
+	for _, raw := range []string{"http://10.0.0.1:8080", "http://10.0.0.2:8080", "socks5://10.0.0.3:1080"} {
+		u, err := url.Parse(raw)
+		require.NoError(t, err)
+		proxies = append(proxies, &poolProxy{url: u})
+	}
+	pool := &proxyPool{strategy: RotateSticky, maxFailures: 1, cooldown: time.Minute, now: time.Now, proxies: proxies, sticky: map[string]int{}}
+
+	first, err := pool.pick("cxsecurity.com")
+	require.NoError(t, err)
+	mirror, err := pool.pick("mirror.example.org")
+	require.NoError(t, err)
+	assert.NotSame(t, first, mirror, "不同的主机分配不同的代理")
+	for i := 0; i < 3; i++ {
+		p, err := pool.pick("cxsecurity.com")
+		require.NoError(t, err)
+		assert.Same(t, first, p, "同一主机固定使用同一个代理")
+	}
+
+	// 代理被剔除后重新分配，之后固定使用新的代理
+	pool.report(first, false)
+	next, err := pool.pick("cxsecurity.com")
+	require.NoError(t, err)
+	assert.NotSame(t, first, next)
+	again, err := pool.pick("cxsecurity.com")
+	require.NoError(t, err)
+	assert.Same(t, next, again)
+}
+
+func TestParseRotationStrategy(t *testing.T) {
+	for input, want := range map[string]RotationStrategy{
+		"":            RotateRoundRobin,
+		"round-robin": RotateRoundRobin,
+		"Random":      RotateRandom,
+		" sticky ":    RotateSticky,
+	} {
+		got, err := ParseRotationStrategy(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, got, input)
+	}
+	_, err := ParseRotationStrategy("least-used")
+	assert.Error(t, err)
+}
@@ -0,0 +1,134 @@
+package crawler
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetentionPolicy 是数据目录的保留策略，0表示永久保留
+// 原始HTML(.html/.htm，以及压缩的.html.zst和旧版本的.html.gz)可以随时重新爬取，体积也大，通常只保留一段时间；
+// 解析后的记录(.json/.jsonl)体积小，通常永久保留。其他文件不受影响。
+type RetentionPolicy struct {
+	RawHTML time.Duration `json:"raw_html"` // 原始HTML的保留时间
+	Records time.Duration `json:"records"`  // 解析后记录的保留时间
+}
+
+// DefaultRetentionPolicy 是默认的保留策略：原始HTML保留90天，解析后的记录永久保留
+var DefaultRetentionPolicy = RetentionPolicy{RawHTML: 90 * 24 * time.Hour}
+
+// ParseRetention 解析保留时间
+// 支持 "30d"(天)、"2w"(周)、Go的时长格式(例如 "36h")，"0"、"forever" 和空字符串表示永久保留
+func ParseRetention(s string) (time.Duration, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	switch s {
+	case "", "0", "forever", "never":
+		return 0, nil
+	}
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			v, err := strconv.Atoi(n)
+			if err != nil || v < 0 {
+				return 0, fmt.Errorf("无效的保留时间: %s", s)
+			}
+			return time.Duration(v) * unit, nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("无效的保留时间: %s", s)
+	}
+	return d, nil
+}
+
+// FormatRetention 将保留时间格式化为ParseRetention可以解析的字符串，整天数使用 "d"
+func FormatRetention(d time.Duration) string {
+	if d <= 0 {
+		return "forever"
+	}
+	if d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	}
+	return d.String()
+}
+
+// PruneReport 是一次清理的结果
+type PruneReport struct {
+	Removed      []string `json:"removed"`       // 删除(或dry-run时将要删除)的文件
+	RemovedBytes int64    `json:"removed_bytes"` // 删除的文件总大小
+	Kept         int      `json:"kept"`          // 保留的原始HTML和记录文件数量
+}
+
+// Prune 按保留策略清理目录中过期的文件
+// 按文件的修改时间判断是否过期，删除文件后会同时删除变空的子目录(不包括dir本身)。
+//
+// 参数:
+//   - dir: 数据目录，会递归处理其中的文件
+//   - policy: 保留策略
+//   - now: 当前时间
+//   - dryRun: 为true时只返回将要删除的文件，不实际删除
+//
+// 示例:
+//
+//	report, err := crawler.Prune("data", crawler.DefaultRetentionPolicy, time.Now(), false)
+func Prune(dir string, policy RetentionPolicy, now time.Time, dryRun bool) (*PruneReport, error) {
+	report := &PruneReport{}
+	var dirs []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir {
+				dirs = append(dirs, path)
+			}
+			return nil
+		}
+
+		var maxAge time.Duration
+		switch strings.ToLower(filepath.Ext(strings.TrimSuffix(strings.TrimSuffix(path, ".gz"), ".zst"))) {
+		case ".html", ".htm":
+			maxAge = policy.RawHTML
+		case ".json", ".jsonl":
+			maxAge = policy.Records
+		default:
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if maxAge <= 0 || now.Sub(info.ModTime()) <= maxAge {
+			report.Kept++
+			return nil
+		}
+		if !dryRun {
+			if err := os.Remove(path); err != nil {
+				return fmt.Errorf("删除文件失败: %w", err)
+			}
+		}
+		report.Removed = append(report.Removed, path)
+		report.RemovedBytes += info.Size()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 从最深的目录开始删除空目录
+	if !dryRun {
+		sort.Slice(dirs, func(i, j int) bool { return len(dirs[i]) > len(dirs[j]) })
+		for _, d := range dirs {
+			if entries, err := os.ReadDir(d); err == nil && len(entries) == 0 {
+				_ = os.Remove(d)
+			}
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,78 @@
+package crawler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRetention(t *testing.T) {
+	tests := map[string]time.Duration{
+		"":        0,
+		"forever": 0,
+		"90d":     90 * 24 * time.Hour,
+		"2w":      14 * 24 * time.Hour,
+		"36h":     36 * time.Hour,
+	}
+	for in, want := range tests {
+		got, err := ParseRetention(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+		if want > 0 {
+			back, err := ParseRetention(FormatRetention(want))
+			require.NoError(t, err)
+			assert.Equal(t, want, back)
+		}
+	}
+	for _, in := range []string{"abc", "-1d", "xd"} {
+		_, err := ParseRetention(in)
+		assert.Error(t, err, in)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	write := func(name string, age time.Duration) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte("data"), 0644))
+		mtime := now.Add(-age)
+		require.NoError(t, os.Chtimes(path, mtime, mtime))
+		return path
+	}
+	day := 24 * time.Hour
+	oldHTML := write("html/2024-01-01/issue_WLB-1.html", 150*day)
+	newHTML := write("html/2024-05-30/issue_WLB-2.html", 2*day)
+	oldGzip := write("html/2024-01-02/issue_WLB-3.html.gz", 150*day)
+	oldZstd := write("html/2024-01-02/issue_WLB-4.html.zst", 150*day)
+	oldJSON := write("results/old.json", 400*day)
+	other := write("notes.txt", 400*day)
+
+	// dry-run不删除文件
+	report, err := Prune(dir, DefaultRetentionPolicy, now, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{oldHTML, oldGzip, oldZstd}, report.Removed)
+	assert.FileExists(t, oldHTML)
+
+	report, err = Prune(dir, DefaultRetentionPolicy, now, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{oldHTML, oldGzip, oldZstd}, report.Removed)
+	assert.Equal(t, int64(12), report.RemovedBytes)
+	assert.Equal(t, 2, report.Kept)
+	assert.NoFileExists(t, oldHTML)
+	assert.NoDirExists(t, filepath.Dir(oldHTML))
+	assert.FileExists(t, newHTML)
+	assert.FileExists(t, oldJSON)
+	assert.FileExists(t, other)
+
+	// 记录也设置了保留时间时会被清理
+	report, err = Prune(dir, RetentionPolicy{RawHTML: 90 * day, Records: 365 * day}, now, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{oldJSON}, report.Removed)
+	assert.FileExists(t, other)
+}
@@ -0,0 +1,160 @@
+package crawler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+// FetchPolicy 决定获取漏洞详情和CVE详情时是否使用本地存储
+type FetchPolicy string
+
+const (
+	FetchNetwork    FetchPolicy = "network"     // 总是从网络获取，不读写本地存储，默认
+	FetchLocal      FetchPolicy = "local"       // 只读本地存储，不发送请求，没有记录时返回 ErrNotInLocalStore
+	FetchLocalFirst FetchPolicy = "local-first" // 本地记录未过期时直接返回，否则从网络获取并写入本地存储
+)
+
+// ErrNotInLocalStore 表示使用 FetchLocal 时本地存储中没有要获取的记录
+var ErrNotInLocalStore = errors.New("本地存储中没有该记录")
+
+// ParseFetchPolicy 解析获取策略的名称，空字符串为 FetchNetwork
+func ParseFetchPolicy(s string) (FetchPolicy, error) {
+	switch p := FetchPolicy(strings.ToLower(strings.TrimSpace(s))); p {
+	case "":
+		return FetchNetwork, nil
+	case FetchNetwork, FetchLocal, FetchLocalFirst:
+		return p, nil
+	}
+	return "", fmt.Errorf("不支持的获取策略 %q，可选值：network、local、local-first", s)
+}
+
+// FetchSource 表示结果的来源
+type FetchSource string
+
+const (
+	SourceLive  FetchSource = "live"  // 本次从网站获取
+	SourceCache FetchSource = "cache" // 来自内存中的缓存，例如API服务的作者订阅
+	SourceStore FetchSource = "store" // 来自本地存储(读穿透模式)
+)
+
+// Freshness 描述结果的获取时间和来源，API响应中用于告知客户端数据有多旧
+type Freshness struct {
+	FetchedAt time.Time   `json:"fetched_at"` // 从网站获取的时间，本地存储中没有获取时间的记录为零值
+	CacheHit  bool        `json:"cache_hit"`  // 是否没有请求网站，直接使用了缓存或本地存储
+	Source    FetchSource `json:"source"`
+}
+
+// LiveFreshness 返回在now从网站获取的结果的Freshness
+func LiveFreshness(now time.Time) Freshness {
+	return Freshness{FetchedAt: now, Source: SourceLive}
+}
+
+// MarshalJSON 获取时间统一输出为UTC，未知时省略
+func (f Freshness) MarshalJSON() ([]byte, error) {
+	var fetchedAt *time.Time
+	if !f.FetchedAt.IsZero() {
+		utc := f.FetchedAt.UTC()
+		fetchedAt = &utc
+	}
+	return json.Marshal(struct {
+		FetchedAt *time.Time  `json:"fetched_at,omitempty"`
+		CacheHit  bool        `json:"cache_hit"`
+		Source    FetchSource `json:"source"`
+	}{fetchedAt, f.CacheHit, f.Source})
+}
+
+// LocalStore 是读穿透模式使用的本地存储，store.ReadThrough 实现了这个接口
+// 实现需要可以并发使用，API服务会同时处理多个请求。
+type LocalStore interface {
+	// Vulnerability 按漏洞ID返回保存的漏洞详情，没有或只有列表页中的字段时第二个返回值为false
+	Vulnerability(id string) (model.Vulnerability, bool)
+	// SaveVulnerability 保存从网络获取到的漏洞详情，并记录获取时间
+	SaveVulnerability(v model.Vulnerability) error
+	// CveDetail 按CVE编号返回保存的CVE详情
+	CveDetail(id string) (model.CveDetail, bool)
+	// SaveCveDetail 保存从网络获取到的CVE详情，并记录获取时间
+	SaveCveDetail(d model.CveDetail) error
+}
+
+// WithReadThrough 设置获取漏洞详情(CrawlExploit)和CVE详情(CrawlCveDetail)时使用的本地存储
+// 使用 FetchLocalFirst 时，获取时间(LastSeen)在ttl以内的本地记录直接返回，重复查询同一漏洞不再发送请求；
+// 过期或不存在时从网络获取并写入本地存储，网络请求失败时返回过期的本地记录。
+// ttl为0时本地记录永不过期；没有获取时间的记录(例如 import 导入的)在ttl大于0时视为已过期。
+// 列表、搜索和作者页面不受影响。
+//
+// 示例:
+//
+//	c := NewCrawler(WithReadThrough(FetchLocalFirst, 24*time.Hour, store.NewReadThrough(vulns, cves)))
+func WithReadThrough(policy FetchPolicy, ttl time.Duration, local LocalStore) CrawlerOption {
+	return func(c *Crawler) {
+		c.fetchPolicy, c.fetchTTL, c.local = policy, ttl, local
+	}
+}
+
+// readThrough 返回是否需要使用本地存储
+func (c *Crawler) readThrough() bool {
+	return c.local != nil && (c.fetchPolicy == FetchLocal || c.fetchPolicy == FetchLocalFirst)
+}
+
+// fresh 判断获取时间为lastSeen的本地记录是否可以直接使用
+func (c *Crawler) fresh(lastSeen time.Time) bool {
+	if c.fetchPolicy == FetchLocal || c.fetchTTL <= 0 {
+		return true
+	}
+	return !lastSeen.IsZero() && time.Since(lastSeen) < c.fetchTTL
+}
+
+// localVulnerability 按读穿透策略获取漏洞详情，fetch从网络获取
+func (c *Crawler) localVulnerability(id string, fetch func() (*model.Vulnerability, error)) (*model.Vulnerability, Freshness, error) {
+	local, ok := c.local.Vulnerability(id)
+	stored := Freshness{FetchedAt: local.LastSeen, CacheHit: true, Source: SourceStore}
+	if ok && c.fresh(local.LastSeen) {
+		return &local, stored, nil
+	}
+	if c.fetchPolicy == FetchLocal {
+		return nil, Freshness{}, fmt.Errorf("%w: %s", ErrNotInLocalStore, id)
+	}
+
+	result, err := fetch()
+	if err != nil {
+		if ok {
+			return &local, stored, nil
+		}
+		return nil, Freshness{}, err
+	}
+	now := time.Now()
+	if err := c.local.SaveVulnerability(*result); err != nil {
+		return nil, Freshness{}, fmt.Errorf("保存到本地存储失败: %w", err)
+	}
+	return result, LiveFreshness(now), nil
+}
+
+// localCveDetail 按读穿透策略获取CVE详情，fetch从网络获取
+func (c *Crawler) localCveDetail(id string, fetch func() (*model.CveDetail, error)) (*model.CveDetail, Freshness, error) {
+	local, ok := c.local.CveDetail(id)
+	stored := Freshness{FetchedAt: local.LastSeen, CacheHit: true, Source: SourceStore}
+	if ok && c.fresh(local.LastSeen) {
+		return &local, stored, nil
+	}
+	if c.fetchPolicy == FetchLocal {
+		return nil, Freshness{}, fmt.Errorf("%w: %s", ErrNotInLocalStore, id)
+	}
+
+	result, err := fetch()
+	if err != nil {
+		if ok {
+			return &local, stored, nil
+		}
+		return nil, Freshness{}, err
+	}
+	now := time.Now()
+	if err := c.local.SaveCveDetail(*result); err != nil {
+		return nil, Freshness{}, fmt.Errorf("保存到本地存储失败: %w", err)
+	}
+	return result, LiveFreshness(now), nil
+}
@@ -0,0 +1,202 @@
+package crawler
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+// memoryStore 是测试用的本地存储
+type memoryStore struct {
+	vulns map[string]model.Vulnerability
+	cves  map[string]model.CveDetail
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{vulns: map[string]model.Vulnerability{}, cves: map[string]model.CveDetail{}}
+}
+
+func (m *memoryStore) Vulnerability(id string) (model.Vulnerability, bool) {
+	v, ok := m.vulns[id]
+	return v, ok
+}
+
+func (m *memoryStore) SaveVulnerability(v model.Vulnerability) error {
+	v.LastSeen = time.Now()
+	m.vulns[v.ID] = v
+	return nil
+}
+
+func (m *memoryStore) CveDetail(id string) (model.CveDetail, bool) {
+	d, ok := m.cves[id]
+	return d, ok
+}
+
+func (m *memoryStore) SaveCveDetail(d model.CveDetail) error {
+	d.LastSeen = time.Now()
+	m.cves[d.CveID] = d
+	return nil
+}
+
+// fixtureCountingClient 返回示例页面并记录请求次数，fail为true时所有请求失败
+func fixtureCountingClient(t *testing.T, requests *int, fail *bool) *mockClient {
+	pages := map[string]string{
+		"/issue/WLB-2007030137":   "vul-detail-response.html",
+		"/cveshow/CVE-2007-1411/": "cve-show-detail-response.html",
+	}
+	return &mockClient{baseURL: "https://cxsecurity.com", getPageFunc: func(path string) (string, error) {
+		*requests++
+		if *fail {
+			return "", errors.New("网络不可用")
+		}
+		name, ok := pages[path]
+		if !ok {
+			return "", errors.New("未知页面: " + path)
+		}
+		data, err := os.ReadFile(filepath.Join(benchmarkFixtureDir, name))
+		if err != nil {
+			t.Skipf("跳过测试，测试文件不存在：%s", name)
+		}
+		return string(data), nil
+	}}
+}
+
+func TestParseFetchPolicy(t *testing.T) {
+	for input, want := range map[string]FetchPolicy{"": FetchNetwork, "network": FetchNetwork, "Local": FetchLocal, " local-first ": FetchLocalFirst} {
+		got, err := ParseFetchPolicy(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, got, input)
+	}
+	_, err := ParseFetchPolicy("cache")
+	assert.Error(t, err)
+}
+
+func TestReadThroughExploit(t *testing.T) {
+	requests, fail := 0, false
+	local := newMemoryStore()
+	c := NewCrawler(WithHTTPClient(fixtureCountingClient(t, &requests, &fail)), WithReadThrough(FetchLocalFirst, time.Hour, local))
+
+	// 第一次从网络获取并写入本地存储，第二次直接使用本地记录
+	first, err := c.CrawlExploit("WLB-2007030137", "", "")
+	require.NoError(t, err)
+	assert.Equal(t, 1, requests)
+	require.Contains(t, local.vulns, "WLB-2007030137")
+	second, err := c.CrawlExploit("2007030137", "", "")
+	require.NoError(t, err)
+	assert.Equal(t, 1, requests, "未过期的本地记录不应再发送请求")
+	assert.Equal(t, first.(*model.Vulnerability).Title, second.(*model.Vulnerability).Title)
+
+	// 使用本地记录时同样保存结果文件
+	output := filepath.Join(t.TempDir(), "vuln.json")
+	_, err = c.CrawlExploit("WLB-2007030137", output, "")
+	require.NoError(t, err)
+	assert.FileExists(t, output)
+
+	// 过期后重新获取，网络失败时返回过期的本地记录
+	stale := local.vulns["WLB-2007030137"]
+	stale.LastSeen = time.Now().Add(-2 * time.Hour)
+	local.vulns["WLB-2007030137"] = stale
+	fail = true
+	result, err := c.CrawlExploit("WLB-2007030137", "", "")
+	require.NoError(t, err)
+	assert.Equal(t, 2, requests)
+	assert.Equal(t, stale.Title, result.(*model.Vulnerability).Title)
+}
+
+func TestReadThroughCveDetail(t *testing.T) {
+	requests, fail := 0, false
+	local := newMemoryStore()
+	c := NewCrawler(WithHTTPClient(fixtureCountingClient(t, &requests, &fail)), WithRelatedPages(0), WithReadThrough(FetchLocalFirst, 0, local))
+
+	_, err := c.CrawlCveDetail("CVE-2007-1411", "")
+	require.NoError(t, err)
+	require.Contains(t, local.cves, "CVE-2007-1411")
+	sent := requests
+
+	// ttl为0时本地记录永不过期
+	stale := local.cves["CVE-2007-1411"]
+	stale.LastSeen = time.Now().AddDate(-1, 0, 0)
+	local.cves["CVE-2007-1411"] = stale
+	_, err = c.CrawlCveDetail("cve-2007-1411", "")
+	require.NoError(t, err)
+	assert.Equal(t, sent, requests)
+}
+
+func TestReadThroughLocalOnly(t *testing.T) {
+	requests, fail := 0, false
+	local := newMemoryStore()
+	local.vulns["WLB-2007030137"] = model.Vulnerability{ID: "WLB-2007030137", Title: "本地记录"}
+	c := NewCrawler(WithHTTPClient(fixtureCountingClient(t, &requests, &fail)), WithReadThrough(FetchLocal, time.Hour, local))
+
+	// 只读本地存储时没有获取时间的记录也直接使用
+	result, err := c.CrawlExploit("WLB-2007030137", "", "")
+	require.NoError(t, err)
+	assert.Equal(t, "本地记录", result.(*model.Vulnerability).Title)
+
+	_, err = c.CrawlExploit("WLB-2007030138", "", "")
+	assert.ErrorIs(t, err, ErrNotInLocalStore)
+	_, err = c.CrawlCveDetail("CVE-2007-1411", "")
+	assert.ErrorIs(t, err, ErrNotInLocalStore)
+	assert.Zero(t, requests, "只读本地存储时不应发送请求")
+}
+
+func TestReadThroughNetwork(t *testing.T) {
+	requests, fail := 0, false
+	local := newMemoryStore()
+	c := NewCrawler(WithHTTPClient(fixtureCountingClient(t, &requests, &fail)), WithReadThrough(FetchNetwork, time.Hour, local))
+
+	for i := 0; i < 2; i++ {
+		_, err := c.CrawlExploit("WLB-2007030137", "", "")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, requests)
+	assert.Empty(t, local.vulns, "network策略不应写入本地存储")
+}
+
+func TestFetchFreshness(t *testing.T) {
+	requests, fail := 0, false
+	local := newMemoryStore()
+	c := NewCrawler(WithHTTPClient(fixtureCountingClient(t, &requests, &fail)), WithReadThrough(FetchLocalFirst, time.Hour, local))
+
+	_, f, err := c.FetchExploit("WLB-2007030137")
+	require.NoError(t, err)
+	assert.Equal(t, SourceLive, f.Source)
+	assert.False(t, f.CacheHit)
+	assert.WithinDuration(t, time.Now(), f.FetchedAt, time.Minute)
+
+	_, f, err = c.FetchExploit("WLB-2007030137")
+	require.NoError(t, err)
+	assert.Equal(t, SourceStore, f.Source)
+	assert.True(t, f.CacheHit)
+	assert.Equal(t, local.vulns["WLB-2007030137"].LastSeen, f.FetchedAt, "获取时间为记录写入本地存储的时间")
+
+	// 不使用本地存储时总是从网站获取
+	_, f, err = NewCrawler(WithHTTPClient(fixtureCountingClient(t, &requests, &fail)), WithRelatedPages(0)).FetchCveDetail("CVE-2007-1411")
+	require.NoError(t, err)
+	assert.Equal(t, SourceLive, f.Source)
+}
+
+func TestFreshnessJSON(t *testing.T) {
+	fetched := time.Date(2024, 5, 1, 16, 0, 0, 0, time.FixedZone("CST", 8*3600))
+	data, err := json.Marshal(Freshness{FetchedAt: fetched, CacheHit: true, Source: SourceStore})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"fetched_at":"2024-05-01T08:00:00Z","cache_hit":true,"source":"store"}`, string(data))
+
+	// 获取时间未知时省略
+	data, err = json.Marshal(Freshness{CacheHit: true, Source: SourceStore})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"cache_hit":true,"source":"store"}`, string(data))
+
+	var decoded Freshness
+	require.NoError(t, json.Unmarshal([]byte(`{"fetched_at":"2024-05-01T08:00:00Z","cache_hit":false,"source":"live"}`), &decoded))
+	assert.True(t, fetched.Equal(decoded.FetchedAt))
+	assert.Equal(t, SourceLive, decoded.Source)
+}
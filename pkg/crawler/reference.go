@@ -0,0 +1,239 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrReferenceBlocked 表示参考链接的目标地址被安全策略拒绝
+var ErrReferenceBlocked = errors.New("目标地址被拒绝")
+
+// ErrReferenceTooLarge 表示参考链接的响应内容超过大小限制
+var ErrReferenceTooLarge = errors.New("响应内容超过大小限制")
+
+// ReferenceFetcherOption 是设置ReferenceFetcher选项的函数类型
+type ReferenceFetcherOption func(*ReferenceFetcher)
+
+// ReferenceFetcher 用于获取漏洞公告中的参考链接和附件
+//
+// 参考链接来自第三方提交的公告内容，不可信任。为防止恶意公告让爬虫访问内部服务(SSRF)，
+// ReferenceFetcher 对每个请求都做以下限制：
+// 1. 协议白名单：默认只允许 http 和 https
+// 2. 内网地址拦截：在建立连接时检查解析后的IP，拒绝回环、私有、链路本地等地址，
+// 因此通过DNS重绑定或重定向跳转到内网地址也会被拦截
+// 3. 大小限制：响应内容超过上限时返回错误，而不是无限读取
+// 4. 重定向限制：最多跟随有限次重定向，且每次跳转都会重新检查协议
+//
+// 使用示例：
+//
+//	fetcher := NewReferenceFetcher(WithReferenceMaxSize(1 << 20))
+//	for _, ref := range cveDetail.References {
+//	    body, err := fetcher.Fetch(ref)
+//	    if errors.Is(err, ErrReferenceBlocked) {
+//	        continue
+//	    }
+//	    // ...
+//	}
+type ReferenceFetcher struct {
+	client             *http.Client
+	allowedSchemes     map[string]bool // 允许的协议
+	maxSize            int64           // 响应内容大小上限(字节)
+	maxRedirects       int             // 最多跟随的重定向次数
+	allowPrivateTarget bool            // 是否允许访问内网地址，仅用于测试或可信环境
+}
+
+// WithReferenceTimeout 设置获取参考链接的超时时间
+func WithReferenceTimeout(timeout time.Duration) ReferenceFetcherOption {
+	return func(f *ReferenceFetcher) {
+		f.client.Timeout = timeout
+	}
+}
+
+// WithReferenceSchemes 设置允许的协议白名单，例如 "https"
+func WithReferenceSchemes(schemes ...string) ReferenceFetcherOption {
+	return func(f *ReferenceFetcher) {
+		f.allowedSchemes = make(map[string]bool, len(schemes))
+		for _, scheme := range schemes {
+			f.allowedSchemes[strings.ToLower(scheme)] = true
+		}
+	}
+}
+
+// WithReferenceMaxSize 设置响应内容大小上限(字节)
+func WithReferenceMaxSize(maxSize int64) ReferenceFetcherOption {
+	return func(f *ReferenceFetcher) {
+		if maxSize > 0 {
+			f.maxSize = maxSize
+		}
+	}
+}
+
+// WithReferenceMaxRedirects 设置最多跟随的重定向次数，0表示不跟随重定向
+func WithReferenceMaxRedirects(maxRedirects int) ReferenceFetcherOption {
+	return func(f *ReferenceFetcher) {
+		if maxRedirects >= 0 {
+			f.maxRedirects = maxRedirects
+		}
+	}
+}
+
+// WithReferencePrivateTargets 设置是否允许访问回环、私有网段等内网地址
+// 默认不允许，只应在测试或完全可信的环境中开启
+func WithReferencePrivateTargets(allow bool) ReferenceFetcherOption {
+	return func(f *ReferenceFetcher) {
+		f.allowPrivateTarget = allow
+	}
+}
+
+// NewReferenceFetcher 创建一个新的参考链接获取器
+// 默认配置:
+//   - 超时时间: 15秒
+//   - 允许的协议: http, https
+//   - 响应大小上限: 2MB
+//   - 最多重定向次数: 3
+//   - 拒绝访问内网地址
+func NewReferenceFetcher(options ...ReferenceFetcherOption) *ReferenceFetcher {
+	f := &ReferenceFetcher{
+		allowedSchemes: map[string]bool{"http": true, "https": true},
+		maxSize:        2 << 20,
+		maxRedirects:   3,
+	}
+
+	dialer := &net.Dialer{
+		Timeout: 10 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			if f.allowPrivateTarget {
+				return nil
+			}
+			return checkReferenceAddress(address)
+		},
+	}
+	f.client = &http.Client{
+		Timeout: 15 * time.Second,
+		Transport: &http.Transport{
+			// 不使用环境变量中的代理，否则连接检查的对象会变成代理服务器
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 10 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) > f.maxRedirects {
+				return fmt.Errorf("%w: 重定向次数超过 %d", ErrReferenceBlocked, f.maxRedirects)
+			}
+			return f.checkURL(req.URL)
+		},
+	}
+
+	for _, option := range options {
+		option(f)
+	}
+
+	return f
+}
+
+// Fetch 获取参考链接的内容
+// 返回值:
+//   - []byte: 响应内容
+//   - error: 目标被拒绝时返回包装了ErrReferenceBlocked的错误，
+//     内容超过大小限制时返回包装了ErrReferenceTooLarge的错误
+func (f *ReferenceFetcher) Fetch(rawURL string) ([]byte, error) {
+	return f.FetchContext(context.Background(), rawURL)
+}
+
+// FetchContext 与Fetch相同，但支持通过ctx取消请求
+func (f *ReferenceFetcher) FetchContext(ctx context.Context, rawURL string) ([]byte, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return nil, fmt.Errorf("解析URL失败: %w", err)
+	}
+	if err := f.checkURL(u); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.ContentLength > f.maxSize {
+		return nil, fmt.Errorf("%w: %d 字节", ErrReferenceTooLarge, resp.ContentLength)
+	}
+
+	// 多读一个字节，用于判断内容是否超过上限
+	body, err := io.ReadAll(io.LimitReader(resp.Body, f.maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > f.maxSize {
+		return nil, fmt.Errorf("%w: 超过 %d 字节", ErrReferenceTooLarge, f.maxSize)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, errors.New("请求失败: " + resp.Status)
+	}
+
+	return body, nil
+}
+
+// checkURL 检查URL的协议和主机
+// 主机为IP字面量时直接检查，域名则在建立连接时检查解析结果
+func (f *ReferenceFetcher) checkURL(u *url.URL) error {
+	if !f.allowedSchemes[strings.ToLower(u.Scheme)] {
+		return fmt.Errorf("%w: 不允许的协议 %q", ErrReferenceBlocked, u.Scheme)
+	}
+	host := u.Hostname()
+	if host == "" {
+		return fmt.Errorf("%w: 缺少主机名", ErrReferenceBlocked)
+	}
+	if f.allowPrivateTarget {
+		return nil
+	}
+	if strings.EqualFold(host, "localhost") || strings.HasSuffix(strings.ToLower(host), ".localhost") {
+		return fmt.Errorf("%w: %s", ErrReferenceBlocked, host)
+	}
+	if ip := net.ParseIP(host); ip != nil && isPrivateReferenceIP(ip) {
+		return fmt.Errorf("%w: %s", ErrReferenceBlocked, host)
+	}
+	return nil
+}
+
+// checkReferenceAddress 在建立连接前检查目标IP
+func checkReferenceAddress(address string) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrReferenceBlocked, err)
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || isPrivateReferenceIP(ip) {
+		return fmt.Errorf("%w: %s", ErrReferenceBlocked, host)
+	}
+	return nil
+}
+
+// isPrivateReferenceIP 判断IP是否属于不应从公告链接访问的地址
+func isPrivateReferenceIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return true
+	}
+	// 100.64.0.0/10 运营商级NAT地址，部分云环境的内部服务使用该网段
+	if ip4 := ip.To4(); ip4 != nil && ip4[0] == 100 && ip4[1]&0xc0 == 64 {
+		return true
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package crawler
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReferenceFetcherBlocksUnsafeTargets(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("内部服务"))
+	}))
+	defer testServer.Close()
+
+	fetcher := NewReferenceFetcher()
+	for _, rawURL := range []string{
+		"file:///etc/passwd",
+		"gopher://example.com/",
+		"ftp://example.com/advisory.txt",
+		"http://localhost/",
+		"http://127.0.0.1/",
+		"http://[::1]/",
+		"http://169.254.169.254/latest/meta-data/",
+		"http://10.0.0.1/",
+		"http://192.168.1.1/",
+		"http://100.64.0.1/",
+		"http:///no-host",
+		testServer.URL,
+	} {
+		_, err := fetcher.Fetch(rawURL)
+		assert.ErrorIs(t, err, ErrReferenceBlocked, "应拒绝访问 %s", rawURL)
+	}
+}
+
+func TestReferenceFetcherBlocksRedirectToPrivate(t *testing.T) {
+	fetcher := NewReferenceFetcher()
+	err := fetcher.client.CheckRedirect(
+		httptest.NewRequest(http.MethodGet, "http://127.0.0.1/admin", nil),
+		[]*http.Request{httptest.NewRequest(http.MethodGet, "http://example.com/", nil)},
+	)
+	assert.ErrorIs(t, err, ErrReferenceBlocked, "重定向到内网地址应被拒绝")
+
+	assert.NoError(t, checkReferenceAddress("93.184.216.34:80"))
+	assert.ErrorIs(t, checkReferenceAddress("10.1.2.3:443"), ErrReferenceBlocked, "连接内网IP应被拒绝")
+	assert.True(t, isPrivateReferenceIP(net.ParseIP("fd00::1")))
+}
+
+func TestReferenceFetcherSizeLimit(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/large":
+			// 使用分块传输，不设置Content-Length
+			w.(http.Flusher).Flush()
+			w.Write([]byte(strings.Repeat("a", 2048)))
+		case "/redirect":
+			http.Redirect(w, r, "/small", http.StatusFound)
+		default:
+			w.Write([]byte("small"))
+		}
+	}))
+	defer testServer.Close()
+
+	fetcher := NewReferenceFetcher(
+		WithReferencePrivateTargets(true),
+		WithReferenceMaxSize(1024),
+	)
+
+	body, err := fetcher.Fetch(testServer.URL + "/redirect")
+	require.NoError(t, err)
+	assert.Equal(t, "small", string(body))
+
+	_, err = fetcher.Fetch(testServer.URL + "/large")
+	assert.ErrorIs(t, err, ErrReferenceTooLarge, "超过大小限制应返回错误")
+
+	noRedirect := NewReferenceFetcher(WithReferencePrivateTargets(true), WithReferenceMaxRedirects(0))
+	_, err = noRedirect.Fetch(testServer.URL + "/redirect")
+	assert.ErrorIs(t, err, ErrReferenceBlocked, "禁止重定向时应返回错误")
+
+	httpsOnly := NewReferenceFetcher(WithReferencePrivateTargets(true), WithReferenceSchemes("https"))
+	_, err = httpsOnly.Fetch(testServer.URL)
+	assert.ErrorIs(t, err, ErrReferenceBlocked, "不在白名单中的协议应被拒绝")
+}
@@ -0,0 +1,102 @@
+package crawler
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SortRelevance 是按相关度排序的排序方式
+// 网站只支持按日期排序，使用该值时按日期降序获取结果，再在客户端计算相关度并排序
+const SortRelevance = "RELEVANCE"
+
+// 相关度各部分的权重，合计为100
+const (
+	relevanceKeywordWeight = 50.0
+	relevanceRecencyWeight = 30.0
+	relevanceRiskWeight    = 20.0
+
+	// relevanceHalfLife 是时效性得分减半的天数
+	relevanceHalfLife = 365.0
+)
+
+// RelevanceScore 计算搜索结果的相关度，范围为0到100，越大越相关
+// 由三部分组成：
+//   - 关键词匹配(50分)：关键词中每个词在标题中出现得越靠前得分越高，只在作者中出现时得一半
+//   - 时效性(30分)：发布一年后减半，日期未知时为0
+//   - 风险级别(20分)：High满分，Med.为60%，Low为30%
+//
+// 参数:
+//   - item: 搜索结果项
+//   - keyword: 搜索关键词，支持多个关键词，用空格分隔
+//   - now: 计算时效性使用的当前时间
+func RelevanceScore(item SearchVulnerability, keyword string, now time.Time) float64 {
+	score := relevanceKeywordWeight*keywordMatchScore(item, keyword) +
+		relevanceRecencyWeight*recencyScore(item.Date, now) +
+		relevanceRiskWeight*riskScore(item.RiskLevel)
+	return math.Round(score*100) / 100
+}
+
+// keywordMatchScore 返回关键词匹配得分，范围为0到1
+func keywordMatchScore(item SearchVulnerability, keyword string) float64 {
+	terms := strings.Fields(strings.ToLower(keyword))
+	if len(terms) == 0 {
+		return 0
+	}
+
+	title := strings.ToLower(item.Title)
+	author := strings.ToLower(item.Author)
+	total := 0.0
+	for _, term := range terms {
+		if pos := strings.Index(title, term); pos >= 0 {
+			// 出现在标题中得0.5分，位置越靠前额外加分，最多0.5分
+			total += 0.5 + 0.5*(1-float64(pos)/float64(len(title)))
+		} else if strings.Contains(author, term) {
+			total += 0.5
+		}
+	}
+	return total / float64(len(terms))
+}
+
+// recencyScore 返回时效性得分，范围为0到1，日期格式为 YYYY-MM-DD
+func recencyScore(date string, now time.Time) float64 {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return 0
+	}
+	days := now.Sub(t).Hours() / 24
+	if days < 0 {
+		days = 0
+	}
+	return math.Pow(0.5, days/relevanceHalfLife)
+}
+
+// riskScore 返回风险级别得分，范围为0到1
+func riskScore(risk string) float64 {
+	switch NormalizeRiskLevel(risk) {
+	case "High":
+		return 1
+	case "Med.":
+		return 0.6
+	case "Low":
+		return 0.3
+	}
+	return 0
+}
+
+// ScoreRelevance 为搜索结果中的每一项计算相关度并按相关度从高到低排序
+// 相关度相同时保持原有顺序。只对当前页的结果排序，不会跨页。
+//
+// 示例:
+//
+//	result, _ := c.SearchVulnerabilities("wordpress", 1, "")
+//	crawler.ScoreRelevance(result, time.Now())
+func ScoreRelevance(result *SearchResult, now time.Time) {
+	for i := range result.Vulnerabilities {
+		result.Vulnerabilities[i].Score = RelevanceScore(result.Vulnerabilities[i], result.Keyword, now)
+	}
+	sort.SliceStable(result.Vulnerabilities, func(i, j int) bool {
+		return result.Vulnerabilities[i].Score > result.Vulnerabilities[j].Score
+	})
+}
@@ -0,0 +1,56 @@
+package crawler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRelevanceScore(t *testing.T) {
+	now := time.Date(2024, 4, 9, 0, 0, 0, 0, time.UTC)
+
+	// 标题开头匹配、当天发布、高风险时接近满分
+	item := SearchVulnerability{Title: "WordPress Plugin SQL Injection", Date: "2024-04-09", RiskLevel: "High"}
+	assert.Equal(t, 100.0, RelevanceScore(item, "wordpress", now))
+
+	// 一年前发布时时效性减半
+	item.Date = "2023-04-10"
+	assert.Equal(t, 85.0, RelevanceScore(item, "wordpress", now))
+
+	// 未匹配关键词、日期未知时只有风险级别得分
+	item = SearchVulnerability{Title: "Joomla XSS", Date: "unknown", RiskLevel: "Med."}
+	assert.Equal(t, 12.0, RelevanceScore(item, "wordpress", now))
+
+	// 只在作者中出现时得一半关键词分
+	item = SearchVulnerability{Title: "Joomla XSS", Author: "wordpress-team", Date: "unknown"}
+	assert.Equal(t, 25.0, RelevanceScore(item, "WordPress", now))
+}
+
+func TestScoreRelevance(t *testing.T) {
+	now := time.Date(2024, 4, 9, 0, 0, 0, 0, time.UTC)
+	result := &SearchResult{
+		Keyword: "sql injection",
+		Vulnerabilities: []SearchVulnerability{
+			{ID: "WLB-1", Title: "Joomla XSS", Date: "2024-04-09", RiskLevel: "Low"},
+			{ID: "WLB-2", Title: "Shop SQL Injection", Date: "2020-01-01", RiskLevel: "High"},
+			{ID: "WLB-3", Title: "SQL Injection in Shop", Date: "2024-04-01", RiskLevel: "High"},
+		},
+	}
+
+	ScoreRelevance(result, now)
+	var ids []string
+	for _, v := range result.Vulnerabilities {
+		ids = append(ids, v.ID)
+		assert.Greater(t, v.Score, 0.0)
+	}
+	assert.Equal(t, []string{"WLB-3", "WLB-2", "WLB-1"}, ids)
+}
+
+func TestSearchOptionsRelevance(t *testing.T) {
+	now := time.Date(2024, 4, 9, 0, 0, 0, 0, time.UTC)
+	opts := SearchOptions{Keyword: "xss", SortOrder: SortRelevance}.normalize()
+	assert.Equal(t, SortRelevance, opts.SortOrder)
+	// 网站不支持按相关度排序，请求时使用DESC
+	assert.Equal(t, "/search/wlb/DESC/AND/2024.4.9.1999.1.1/1/10/xss/", opts.path(now))
+}
@@ -0,0 +1,98 @@
+package crawler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+)
+
+// RouteHeaders 是按请求路径前缀添加的HTTP头
+// 用于模拟正常浏览时的请求，例如访问漏洞详情时带上来自列表页的Referer，
+// 一些栏目直接访问时更容易被拦截。
+type RouteHeaders struct {
+	Prefix string `json:"prefix"` // 路径前缀，例如 "/issue/"，"/" 匹配所有请求
+	// Headers 是要添加的HTTP头，值中的 {base} 替换为实际请求的站点地址，
+	// 使用镜像时为镜像的地址，Referer 与请求的主机保持一致
+	Headers map[string]string `json:"headers"`
+}
+
+// LoadRouteHeaders 从JSON文件读取按路径前缀添加的HTTP头，并校验前缀和HTTP头名称
+//
+// 示例文件:
+//
+//	[
+//	  {"prefix": "/issue/", "headers": {"Referer": "{base}/wlb/"}},
+//	  {"prefix": "/cveshow/", "headers": {"Referer": "{base}/cvelist/", "Sec-Fetch-Site": "same-origin"}}
+//	]
+func LoadRouteHeaders(path string) ([]RouteHeaders, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取路径请求头文件失败: %w", err)
+	}
+	var routes []RouteHeaders
+	if err := json.Unmarshal(data, &routes); err != nil {
+		return nil, fmt.Errorf("解析路径请求头文件失败: %w", err)
+	}
+	for _, route := range routes {
+		if err := route.validate(); err != nil {
+			return nil, err
+		}
+	}
+	return routes, nil
+}
+
+// validate 检查路径前缀和HTTP头是否有效
+func (r RouteHeaders) validate() error {
+	if !strings.HasPrefix(r.Prefix, "/") {
+		return fmt.Errorf("无效的路径前缀 %q，应以 / 开头", r.Prefix)
+	}
+	for name, value := range r.Headers {
+		if name == "" || strings.ContainsAny(name, " \t\r\n:") {
+			return fmt.Errorf("路径 %s 的请求头名称 %q 无效", r.Prefix, name)
+		}
+		if strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("路径 %s 的请求头 %s 的值不能包含换行", r.Prefix, name)
+		}
+	}
+	return nil
+}
+
+// WithRouteHeaders 按请求路径前缀添加HTTP头
+// 路径匹配多个前缀时都会添加，前缀更长的优先，同样长度时后添加的优先；
+// 这些头在 WithHeader 之后设置，会覆盖同名的头。前缀匹配的是cxsecurity上的路径，
+// 与镜像的 Mirror.Path 无关，健康检查请求同样按路径匹配。
+//
+// 示例:
+//
+//	client := NewClient(WithRouteHeaders(
+//	    RouteHeaders{Prefix: "/issue/", Headers: map[string]string{"Referer": "{base}/wlb/"}},
+//	))
+func WithRouteHeaders(routes ...RouteHeaders) ClientOption {
+	return func(c *Client) {
+		c.routeHeaders = append(c.routeHeaders, routes...)
+		// 稳定排序，按顺序设置时更长的前缀和后添加的规则覆盖前面的
+		sort.SliceStable(c.routeHeaders, func(i, j int) bool {
+			return len(c.routeHeaders[i].Prefix) < len(c.routeHeaders[j].Prefix)
+		})
+	}
+}
+
+// headersFor 返回请求站点上的路径需要添加的HTTP头，没有匹配的前缀时返回nil
+func (c *Client) headersFor(site Mirror, path string) map[string]string {
+	var headers map[string]string
+	for _, route := range c.routeHeaders {
+		if !strings.HasPrefix(path, route.Prefix) {
+			continue
+		}
+		if headers == nil {
+			headers = make(map[string]string)
+		}
+		for name, value := range route.Headers {
+			headers[http.CanonicalHeaderKey(name)] = strings.ReplaceAll(value, "{base}", site.BaseURL)
+		}
+	}
+	return headers
+}
@@ -0,0 +1,68 @@
+package crawler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouteHeaders(t *testing.T) {
+	received := map[string]http.Header{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received[r.URL.Path] = r.Header.Clone()
+	}))
+	defer server.Close()
+
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithHeader("Referer", "https://example.org/"),
+		WithHeader("X-Global", "1"),
+		WithRouteHeaders(
+			RouteHeaders{Prefix: "/issue/", Headers: map[string]string{"referer": "{base}/wlb/", "X-Section": "issue"}},
+			RouteHeaders{Prefix: "/", Headers: map[string]string{"X-Section": "all"}},
+		),
+	)
+	for _, path := range []string{"/issue/WLB-2024040015", "/cveshow/CVE-2024-1/"} {
+		_, err := client.GetPage(path)
+		require.NoError(t, err)
+	}
+
+	issue := received["/issue/WLB-2024040015"]
+	assert.Equal(t, server.URL+"/wlb/", issue.Get("Referer"), "按路径添加的头覆盖全局的头，{base}替换为站点地址")
+	assert.Equal(t, "issue", issue.Get("X-Section"), "更长的前缀优先")
+	assert.Equal(t, "1", issue.Get("X-Global"))
+
+	cve := received["/cveshow/CVE-2024-1/"]
+	assert.Equal(t, "https://example.org/", cve.Get("Referer"))
+	assert.Equal(t, "all", cve.Get("X-Section"))
+}
+
+func TestLoadRouteHeaders(t *testing.T) {
+	dir := t.TempDir()
+	write := func(content string) string {
+		path := filepath.Join(dir, "headers.json")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+
+	routes, err := LoadRouteHeaders(write(`[{"prefix": "/issue/", "headers": {"Referer": "{base}/wlb/"}}]`))
+	require.NoError(t, err)
+	assert.Equal(t, []RouteHeaders{{Prefix: "/issue/", Headers: map[string]string{"Referer": "{base}/wlb/"}}}, routes)
+
+	for _, content := range []string{
+		`[{"prefix": "issue/", "headers": {"Referer": "x"}}]`,
+		`[{"prefix": "/issue/", "headers": {"Bad Name": "x"}}]`,
+		`[{"prefix": "/issue/", "headers": {"Referer": "a\r\nX-Injected: 1"}}]`,
+		`{"prefix": "/"}`,
+	} {
+		_, err := LoadRouteHeaders(write(content))
+		assert.Error(t, err, content)
+	}
+	_, err = LoadRouteHeaders(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,142 @@
+package crawler
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// sanitizeAllowedTags 是SanitizeHTML保留的标签白名单
+// 只包含排版相关的标签，不包含任何可以执行脚本或加载外部资源的标签
+var sanitizeAllowedTags = map[string]bool{
+	"p": true, "br": true, "pre": true, "code": true, "blockquote": true,
+	"b": true, "strong": true, "i": true, "em": true, "u": true,
+	"ul": true, "ol": true, "li": true, "a": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"table": true, "thead": true, "tbody": true, "tr": true, "th": true, "td": true,
+}
+
+// sanitizeDroppedTags 是连同内容一起丢弃的标签
+var sanitizeDroppedTags = map[string]bool{
+	"script": true, "style": true, "iframe": true, "object": true, "embed": true,
+	"noscript": true, "template": true, "svg": true, "math": true, "frame": true, "frameset": true,
+}
+
+// SanitizeHTML 清理从页面中提取的HTML片段，防止存储型XSS
+// 在存储或通过API提供漏洞描述、PoC等HTML内容之前调用。
+//
+// 处理规则：
+// 1. 只保留白名单中的排版标签，其他标签被移除但保留其文本内容
+// 2. script、style、iframe等标签连同内容一起丢弃
+// 3. 删除所有属性，只有a标签保留经过SanitizeURL检查的href，并添加rel="nofollow noopener"
+// 4. 文本内容重新转义，PoC中的 <script> 等代码会以文本形式保留
+//
+// 示例:
+//
+//	SanitizeHTML(`<p onclick="x()">PoC: <script>alert(1)</script></p>`)
+//	// 返回 `<p>PoC: </p>`
+func SanitizeHTML(fragment string) string {
+	var sb strings.Builder
+	tokenizer := html.NewTokenizer(strings.NewReader(fragment))
+	dropDepth := 0
+	var dropTag string
+
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == html.ErrorToken {
+			// io.EOF表示片段已处理完，其他错误也返回已清理的部分
+			return sb.String()
+		}
+
+		token := tokenizer.Token()
+		tag := strings.ToLower(token.Data)
+
+		// 正在丢弃某个危险标签的内容
+		if dropDepth > 0 {
+			switch {
+			case tokenType == html.StartTagToken && tag == dropTag:
+				dropDepth++
+			case tokenType == html.EndTagToken && tag == dropTag:
+				dropDepth--
+			}
+			continue
+		}
+
+		switch tokenType {
+		case html.TextToken:
+			sb.WriteString(html.EscapeString(token.Data))
+		case html.StartTagToken, html.SelfClosingTagToken:
+			if sanitizeDroppedTags[tag] {
+				if tokenType == html.StartTagToken {
+					dropTag = tag
+					dropDepth = 1
+				}
+				continue
+			}
+			if !sanitizeAllowedTags[tag] {
+				continue
+			}
+			sb.WriteString("<" + tag)
+			if tag == "a" {
+				for _, attr := range token.Attr {
+					if strings.ToLower(attr.Key) != "href" {
+						continue
+					}
+					if href := SanitizeURL(attr.Val); href != "" {
+						sb.WriteString(` href="` + html.EscapeString(href) + `" rel="nofollow noopener"`)
+					}
+				}
+			}
+			if tokenType == html.SelfClosingTagToken {
+				sb.WriteString(" /")
+			}
+			sb.WriteString(">")
+		case html.EndTagToken:
+			if sanitizeAllowedTags[tag] {
+				sb.WriteString("</" + tag + ">")
+			}
+		}
+		// 注释和DOCTYPE直接丢弃
+	}
+}
+
+// SanitizeURL 检查从页面中提取的链接，只允许http、https和相对路径
+// 对于javascript:、data:、vbscript:等可以执行脚本的链接返回空字符串。
+// 首尾的空白会被去掉，链接中间的空格等字符保持不变；
+// 含有控制字符的链接直接拒绝，浏览器会忽略协议中的控制字符，例如 "java\tscript:"。
+//
+// 示例:
+//
+//	SanitizeURL("/author/rgod/1/")        // "/author/rgod/1/"
+//	SanitizeURL("javascript:alert(1)")    // ""
+func SanitizeURL(rawURL string) string {
+	cleaned := strings.TrimSpace(rawURL)
+	if cleaned == "" || strings.ContainsFunc(cleaned, func(r rune) bool { return r < ' ' || r == 0x7f }) {
+		return ""
+	}
+
+	u, err := url.Parse(cleaned)
+	if err != nil {
+		return ""
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "":
+		// 相对路径，不允许以冒号开头的伪协议
+		if strings.Contains(strings.SplitN(cleaned, "/", 2)[0], ":") {
+			return ""
+		}
+		return cleaned
+	case "http", "https":
+		return cleaned
+	default:
+		return ""
+	}
+}
+
+// sanitizedHref 读取元素的href属性并经过SanitizeURL检查
+func sanitizedHref(s *goquery.Selection) string {
+	href, _ := s.Attr("href")
+	return SanitizeURL(href)
+}
@@ -0,0 +1,75 @@
+package crawler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSanitizeHTML(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"保留排版标签", `<p>SQL <b>injection</b><br/>in <code>id</code></p>`, `<p>SQL <b>injection</b><br />in <code>id</code></p>`},
+		{"丢弃script内容", `<p>PoC<script>alert(1)</script></p>`, `<p>PoC</p>`},
+		{"丢弃嵌套的危险标签", `<svg><svg onload=alert(1)></svg></svg>ok`, `ok`},
+		{"删除事件属性", `<p onclick="alert(1)" style="x">text</p>`, `<p>text</p>`},
+		{"移除未知标签保留文本", `<img src=x onerror=alert(1)><div>text</div>`, `text`},
+		{"转义文本", `payload: &lt;script&gt;alert(1)&lt;/script&gt;`, `payload: &lt;script&gt;alert(1)&lt;/script&gt;`},
+		{"安全链接", `<a href="https://example.com/a?b=1&c=2" target="_blank">ref</a>`, `<a href="https://example.com/a?b=1&amp;c=2" rel="nofollow noopener">ref</a>`},
+		{"危险链接", `<a href="javascript:alert(1)">x</a>`, `<a>x</a>`},
+		{"丢弃注释", `a<!-- <script>alert(1)</script> -->b`, `ab`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, SanitizeHTML(tt.input))
+		})
+	}
+}
+
+func TestSanitizeURL(t *testing.T) {
+	for _, safe := range []string{
+		"https://cxsecurity.com/issue/WLB-2024040015",
+		"http://example.com",
+		"/author/rgod/1/",
+		"author/rgod/1/",
+		"https://example.com/advisories/my report.txt?q=sql injection",
+	} {
+		assert.Equal(t, safe, SanitizeURL(safe), "安全链接应保持不变")
+	}
+	assert.Equal(t, "https://example.com/a b", SanitizeURL(" https://example.com/a b\n"), "只去掉首尾的空白")
+
+	for _, unsafe := range []string{
+		"javascript:alert(1)",
+		"JaVaScRiPt:alert(1)",
+		"java\tscript:alert(1)",
+		" javascript:alert(1)",
+		"data:text/html;base64,PHNjcmlwdD4=",
+		"vbscript:msgbox(1)",
+		"https://example.com/\x00",
+		"/author/\x7f",
+		"",
+	} {
+		assert.Empty(t, SanitizeURL(unsafe), "应拒绝链接 %q", unsafe)
+	}
+}
+
+func TestParserSanitizesLinks(t *testing.T) {
+	vuln, err := NewParser().ParseVulnerabilityDetailPage(
+		`<div class="well well-sm">Credit: <a href="javascript:alert(document.cookie)//author/">evil</a></div>`)
+	require.NoError(t, err)
+	assert.Equal(t, "evil", vuln.Author)
+	assert.Empty(t, vuln.AuthorURL, "javascript:链接不应被保存")
+}
+
+func TestParserSanitizesContentHTML(t *testing.T) {
+	vuln, err := NewParser().ParseVulnerabilityDetailPage(
+		`<div class="well well-sm premex">PoC:<br><b onmouseover="alert(1)">id</b>=1&lt;script&gt;<script>alert(document.cookie)</script><iframe src="//evil"></iframe></div>`)
+	require.NoError(t, err)
+	assert.Equal(t, "PoC:id=1<script>alert(document.cookie)", vuln.Content, "纯文本的正文保持原样")
+	assert.Equal(t, "PoC:<br /><b>id</b>=1&lt;script&gt;", vuln.ContentHTML)
+}
@@ -1,24 +1,25 @@
 package crawler
 
 import (
-	"encoding/json"
 	"fmt"
 	"net/url"
-	"os"
-	"path/filepath"
-	"strings"
 	"time"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
 )
 
 // SearchResult 表示搜索结果
 // 包含搜索的元数据（关键词、分页信息等）和漏洞列表
 type SearchResult struct {
-	Keyword         string                `json:"keyword"`         // 搜索关键词
-	CurrentPage     int                   `json:"current_page"`    // 当前页码
-	TotalPages      int                   `json:"total_pages"`     // 总页数
-	SortOrder       string                `json:"sort_order"`      // 排序顺序(ASC或DESC)
-	PerPage         int                   `json:"per_page"`        // 每页记录数
-	Vulnerabilities []SearchVulnerability `json:"vulnerabilities"` // 漏洞列表
+	Keyword         string                `json:"keyword"`                 // 搜索关键词
+	CurrentPage     int                   `json:"current_page"`            // 当前页码
+	TotalPages      int                   `json:"total_pages"`             // 总页数
+	SortOrder       string                `json:"sort_order"`              // 排序顺序(ASC、DESC或RELEVANCE)
+	PerPage         int                   `json:"per_page"`                // 每页记录数
+	PagesFetched    int                   `json:"pages_fetched,omitempty"` // 合并的页数，仅SearchAll输出
+	Duplicates      int                   `json:"duplicates,omitempty"`    // 在多页中重复出现而去掉的记录数，仅SearchAll输出
+	EmptyResult     bool                  `json:"empty_result,omitempty"`  // 网站上没有匹配的漏洞；被拦截时返回ErrBlocked，过滤后为空时为false
+	Vulnerabilities []SearchVulnerability `json:"vulnerabilities"`         // 漏洞列表
 }
 
 // SearchVulnerability 表示搜索结果中的单个漏洞项
@@ -29,8 +30,17 @@ type SearchVulnerability struct {
 	URL       string `json:"url"`        // 漏洞详情页URL
 	Date      string `json:"date"`       // 发布日期
 	RiskLevel string `json:"risk_level"` // 风险级别（High/Medium/Low）
-	Author    string `json:"author"`     // 作者名称
-	AuthorURL string `json:"author_url"` // 作者主页URL
+	// DerivedRisk 是规范化的风险级别，没有标签时为推断的级别，见 DeriveRisk
+	DerivedRisk  string  `json:"derived_risk,omitempty"`
+	RiskInferred bool    `json:"risk_inferred,omitempty"` // DerivedRisk是推断的
+	Author       string  `json:"author"`                  // 作者名称
+	AuthorURL    string  `json:"author_url"`              // 作者主页URL
+	Remote       bool    `json:"remote,omitempty"`        // 是否可远程利用
+	Local        bool    `json:"local,omitempty"`         // 是否为本地利用
+	Score        float64 `json:"score,omitempty"`         // 相关度，仅按相关度排序时计算，见RelevanceScore
+	ClusterID    string  `json:"cluster_id,omitempty"`    // 近似重复公告的分组ID，见AnnotateSearchClusters
+	// Keywords 是搜索到该漏洞的关键词，仅多关键词搜索时输出，见MultiSearch
+	Keywords []string `json:"keywords,omitempty"`
 }
 
 // SearchVulnerabilities 根据关键词搜索漏洞
@@ -103,24 +113,142 @@ func (c *Crawler) SearchVulnerabilities(keyword string, page int, outputPath str
 // 2. sortOrder只支持"ASC"或"DESC"，其他值会被设为默认值"DESC"
 // 3. 页码小于1会被设为1
 // 4. 搜索结果会被缓存，相同的搜索参数会返回相同的结果
+// 5. 需要按风险级别或利用方式过滤时请使用Search
 func (c *Crawler) SearchVulnerabilitiesAdvanced(keyword string, page int, perPage int, sortOrder string, outputPath string) (*SearchResult, error) {
-	// 构建搜索URL，格式为: /search/wlb/DESC/AND/结束日期.开始日期/页码/每页数量/关键词/
-	// 结束日期使用当前日期，开始日期使用一个固定的早期日期
-	currentTime := time.Now()
-	endDate := fmt.Sprintf("%d.%d.%d", currentTime.Year(), currentTime.Month(), currentTime.Day())
-	startDate := "1999.1.1" // 一个固定的早期日期
+	return c.Search(SearchOptions{
+		Keyword:   keyword,
+		Page:      page,
+		PerPage:   perPage,
+		SortOrder: sortOrder,
+	}, outputPath)
+}
+
+// SearchOptions 是Search的搜索条件
+//
+// cxsecurity的搜索URL只支持排序、日期范围、每页数量和关键词，日期范围通过After和Before指定；没有风险级别和
+// 远程/本地利用的参数，因此Risks、Remote和Local在获取每页结果后过滤。
+// 指定了过滤条件而没有指定PerPage时，每页数量使用网站支持的最大值30，减少需要请求的页数。
+type SearchOptions struct {
+	Keyword   string    // 搜索关键词，支持多个关键词，用空格分隔
+	Page      int       // 页码，从1开始，小于1时按1处理
+	PerPage   int       // 每页记录数，支持10或30
+	SortOrder string    // 排序顺序，"ASC"、"DESC"或SortRelevance
+	Risks     []string  // 只保留这些风险级别的漏洞，例如 "High"、"Med."，为空时不过滤
+	Remote    bool      // 只保留可远程利用的漏洞
+	Local     bool      // 只保留本地利用的漏洞，与Remote同时指定时两者都保留
+	After     time.Time // 只搜索该日期及之后发布的漏洞，为零值时从1999年1月1日开始
+	Before    time.Time // 只搜索该日期及之前发布的漏洞，为零值时到当前日期为止
+	// Exclude 中的漏洞不会出现在结果中，用于只输出与之前的结果相比新出现的漏洞，见LoadKnownResults
+	Exclude KnownResults
+}
+
+// hasFilters 返回是否指定了需要在客户端过滤的条件
+func (o SearchOptions) hasFilters() bool {
+	return len(o.Risks) > 0 || o.Remote || o.Local
+}
 
-	// 默认值和验证
-	if perPage != 10 && perPage != 30 {
-		perPage = 10 // 默认每页10条，仅支持10或30
+// normalize 填充默认值并校验参数
+func (o SearchOptions) normalize() SearchOptions {
+	if o.Page < 1 {
+		o.Page = 1
 	}
+	if o.PerPage != 10 && o.PerPage != 30 {
+		o.PerPage = 10 // 默认每页10条，仅支持10或30
+		if o.hasFilters() {
+			o.PerPage = 30
+		}
+	}
+	if o.SortOrder != "ASC" && o.SortOrder != "DESC" && o.SortOrder != SortRelevance {
+		o.SortOrder = "DESC" // 默认为DESC，仅支持ASC、DESC或RELEVANCE
+	}
+	return o
+}
+
+// searchStartDate 是未指定After时使用的开始日期，早于网站收录的第一条漏洞
+var searchStartDate = time.Date(1999, 1, 1, 0, 0, 0, 0, time.UTC)
 
-	if sortOrder != "ASC" && sortOrder != "DESC" {
-		sortOrder = "DESC" // 默认为DESC，仅支持ASC或DESC
+// path 构建搜索URL，格式为: /search/wlb/DESC/AND/结束日期.开始日期/页码/每页数量/关键词/
+// 日期格式为 年.月.日，月和日不补零；未指定Before时结束日期使用当前日期
+func (o SearchOptions) path(now time.Time) string {
+	end, start := now, searchStartDate
+	if !o.Before.IsZero() {
+		end = o.Before
+	}
+	if !o.After.IsZero() {
+		start = o.After
 	}
+	endDate := fmt.Sprintf("%d.%d.%d", end.Year(), end.Month(), end.Day())
+	startDate := fmt.Sprintf("%d.%d.%d", start.Year(), start.Month(), start.Day())
 
-	path := fmt.Sprintf("/search/wlb/%s/AND/%s.%s/%d/%d/%s/",
-		sortOrder, endDate, startDate, page, perPage, url.QueryEscape(keyword))
+	// 网站不支持按相关度排序，先获取最新的结果再在客户端排序
+	sortOrder := o.SortOrder
+	if sortOrder == SortRelevance {
+		sortOrder = "DESC"
+	}
+	return fmt.Sprintf("/search/wlb/%s/AND/%s.%s/%d/%d/%s/",
+		sortOrder, endDate, startDate, o.Page, o.PerPage, url.QueryEscape(o.Keyword))
+}
+
+// match 判断漏洞是否满足风险级别和利用方式的过滤条件，并且不在Exclude中
+func (o SearchOptions) match(item SearchVulnerability) bool {
+	if len(o.Risks) > 0 {
+		matched := false
+		for _, r := range o.Risks {
+			if item.Risk() == NormalizeRiskLevel(r) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if (o.Remote || o.Local) && !((o.Remote && item.Remote) || (o.Local && item.Local)) {
+		return false
+	}
+	return !o.Exclude.Contains(item.ID, item.URL)
+}
+
+// Risk 返回规范化的风险级别，没有标签时使用推断的级别，都没有时返回空字符串
+func (v SearchVulnerability) Risk() string {
+	if risk := NormalizeRiskLevel(v.RiskLevel); risk != "" {
+		return risk
+	}
+	return v.DerivedRisk
+}
+
+// NormalizeRiskLevel 将风险级别规范为cxsecurity使用的 "High"、"Med."、"Low"
+// 不区分大小写，支持 "medium"、"med"、"中危" 等 DefaultLabelMappings 中的写法，无法识别时返回空字符串
+func NormalizeRiskLevel(risk string) string {
+	return defaultLabels.lookup(LabelRisk, risk)
+}
+
+// Search 按搜索条件搜索漏洞
+// 风险级别和远程/本地利用的过滤在获取当前页后进行，返回的分页信息是网站上未过滤的分页。
+// 网站返回验证码或封禁页面时返回包装了ErrBlocked的错误，没有匹配的漏洞时返回 EmptyResult 为true的结果。
+//
+// 参数:
+//   - opts: 搜索条件
+//   - outputPath: 结果保存路径，为空则不保存
+//
+// 返回值:
+//   - *SearchResult: 过滤后的搜索结果
+//   - error: 搜索过程中的错误
+//
+// 示例:
+//
+//	// 搜索可远程利用的高危SQL注入漏洞
+//	result, err := crawler.Search(SearchOptions{
+//	    Keyword: "sql injection",
+//	    Risks:   []string{"High"},
+//	    Remote:  true,
+//	}, "")
+func (c *Crawler) Search(opts SearchOptions, outputPath string) (*SearchResult, error) {
+	if !opts.After.IsZero() && !opts.Before.IsZero() && opts.After.After(opts.Before) {
+		return nil, fmt.Errorf("开始日期 %s 晚于结束日期 %s", opts.After.Format("2006-01-02"), opts.Before.Format("2006-01-02"))
+	}
+	opts = opts.normalize()
+	path := opts.path(time.Now())
 
 	// 获取页面内容
 	htmlContent, err := c.client.GetPage(path)
@@ -128,55 +256,43 @@ func (c *Crawler) SearchVulnerabilitiesAdvanced(keyword string, page int, perPag
 		return nil, fmt.Errorf("获取搜索结果页面内容失败: %w", err)
 	}
 
-	// 解析搜索结果页面
+	// 解析搜索结果页面，没有漏洞时区分没有匹配的结果和被网站拦截
 	vulnList, err := c.parser.ParseListPage(htmlContent)
+	if err != nil || len(vulnList.Items) == 0 {
+		if reason := detectBlockPage(htmlContent); reason != "" {
+			return nil, fmt.Errorf("%w(%s): %s", ErrBlocked, reason, path)
+		}
+	}
 	if err != nil {
 		return nil, fmt.Errorf("解析搜索结果页面内容失败: %w", err)
 	}
 
 	// 转换为SearchResult格式
 	result := &SearchResult{
-		Keyword:         keyword,
+		Keyword:         opts.Keyword,
 		CurrentPage:     vulnList.CurrentPage,
 		TotalPages:      vulnList.TotalPages,
-		SortOrder:       sortOrder,
-		PerPage:         perPage,
+		SortOrder:       opts.SortOrder,
+		PerPage:         opts.PerPage,
+		EmptyResult:     len(vulnList.Items) == 0,
 		Vulnerabilities: make([]SearchVulnerability, 0, len(vulnList.Items)),
 	}
 
 	// 提取搜索结果项
 	for _, item := range vulnList.Items {
-		// 提取ID
-		id := "未知"
-		if item.ID != "" {
-			id = item.ID
-		} else if item.URL != "" && strings.Contains(item.URL, "WLB-") {
-			idx := strings.Index(item.URL, "WLB-")
-			id = item.URL[idx:]
-		}
-
-		// 格式化日期
-		date := "未知"
-		if !item.Date.IsZero() {
-			date = item.Date.Format("2006-01-02")
-		}
-
-		searchVuln := SearchVulnerability{
-			ID:        id,
-			Title:     item.Title,
-			URL:       item.URL,
-			Date:      date,
-			RiskLevel: item.RiskLevel,
-			Author:    item.Author,
-			AuthorURL: item.AuthorURL,
+		searchVuln := toSearchVulnerability(item)
+		if opts.match(searchVuln) {
+			result.Vulnerabilities = append(result.Vulnerabilities, searchVuln)
 		}
+	}
 
-		result.Vulnerabilities = append(result.Vulnerabilities, searchVuln)
+	if opts.SortOrder == SortRelevance {
+		ScoreRelevance(result, time.Now())
 	}
 
 	// 保存结果
 	if outputPath != "" {
-		if err := saveSearchResult(result, outputPath); err != nil {
+		if err := c.saveSearchResult(result, outputPath); err != nil {
 			return nil, fmt.Errorf("保存搜索结果失败: %w", err)
 		}
 	}
@@ -184,26 +300,39 @@ func (c *Crawler) SearchVulnerabilitiesAdvanced(keyword string, page int, perPag
 	return result, nil
 }
 
-// saveSearchResult 保存搜索结果
-func saveSearchResult(result *SearchResult, outputPath string) error {
-	// 创建目录
-	dir := filepath.Dir(outputPath)
-	if dir != "." && dir != "" {
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			return fmt.Errorf("创建输出目录失败: %w", err)
-		}
+// toSearchVulnerability 将列表页中的漏洞转换为搜索结果项，没有ID和日期时为 "未知"
+func toSearchVulnerability(item model.Vulnerability) SearchVulnerability {
+	// 提取ID
+	id := "未知"
+	if item.ID != "" {
+		id = item.ID
+	} else if urlID := model.WLBIDFromURL(item.URL); urlID != "" {
+		id = urlID.String()
 	}
 
-	// 将结果编码为JSON
-	jsonData, err := json.MarshalIndent(result, "", "  ")
-	if err != nil {
-		return fmt.Errorf("编码JSON失败: %w", err)
+	// 格式化日期
+	date := "未知"
+	if !item.Date.IsZero() {
+		date = item.Date.Format("2006-01-02")
 	}
 
-	// 写入文件
-	if err := os.WriteFile(outputPath, jsonData, 0644); err != nil {
-		return fmt.Errorf("写入文件失败: %w", err)
+	DeriveRisk(&item)
+	return SearchVulnerability{
+		ID:           id,
+		Title:        item.Title,
+		URL:          item.URL,
+		Date:         date,
+		RiskLevel:    item.RiskLevel,
+		DerivedRisk:  item.DerivedRisk,
+		RiskInferred: item.RiskInferred,
+		Author:       item.Author,
+		AuthorURL:    item.AuthorURL,
+		Remote:       item.IsRemote,
+		Local:        item.IsLocal,
 	}
+}
 
-	return nil
+// saveSearchResult 保存搜索结果
+func (c *Crawler) saveSearchResult(result *SearchResult, outputPath string) error {
+	return c.writeJSON(result, outputPath)
 }
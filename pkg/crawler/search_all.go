@@ -0,0 +1,97 @@
+package crawler
+
+import (
+	"fmt"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+// DefaultSearchMaxPages 是 SearchAll 默认最多获取的页数
+const DefaultSearchMaxPages = 20
+
+// SearchAll 从opts.Page开始依次获取搜索结果的每一页，合并为一个结果
+//
+// 获取到最后一页或达到maxPages页时停止，请求间隔由客户端的限速控制(参见 WithRateLimit)。
+// 网站翻页时同一漏洞可能出现在多页中，按漏洞ID(参见 DedupKey)只保留第一次出现的记录，
+// 去掉的记录数保存在 Duplicates 中；按相关度排序时合并后的结果整体按相关度重新排序。
+// 某一页失败时返回已获取的结果和错误，第一页失败时结果为nil。
+//
+// 参数:
+//   - opts: 搜索条件
+//   - maxPages: 最多获取的页数，小于等于0时使用 DefaultSearchMaxPages
+//   - outputPath: 合并后结果的保存路径，为空则不保存；某一页失败时不保存
+//
+// 返回值:
+//   - *SearchResult: 合并后的结果，CurrentPage为起始页码，PagesFetched为实际获取的页数
+//   - error: 搜索或保存失败时返回错误
+//
+// 示例:
+//
+//	result, err := c.SearchAll(SearchOptions{Keyword: "wordpress"}, 10, "wordpress.json")
+func (c *Crawler) SearchAll(opts SearchOptions, maxPages int, outputPath string) (*SearchResult, error) {
+	if maxPages <= 0 {
+		maxPages = DefaultSearchMaxPages
+	}
+	opts = opts.normalize()
+
+	var result *SearchResult
+	seen := make(map[string]bool)
+	for page := opts.Page; page < opts.Page+maxPages; page++ {
+		pageOpts := opts
+		pageOpts.Page = page
+		pageResult, err := c.Search(pageOpts, "")
+		if err != nil {
+			if result == nil {
+				return nil, err
+			}
+			return result, fmt.Errorf("第 %d 页搜索失败: %w", page, err)
+		}
+		if result == nil {
+			result = &SearchResult{
+				Keyword:         pageResult.Keyword,
+				CurrentPage:     opts.Page,
+				SortOrder:       pageResult.SortOrder,
+				PerPage:         pageResult.PerPage,
+				EmptyResult:     pageResult.EmptyResult,
+				Vulnerabilities: []SearchVulnerability{},
+			}
+		}
+		result.TotalPages = pageResult.TotalPages
+		result.PagesFetched++
+		for _, item := range pageResult.Vulnerabilities {
+			key := item.DedupKey()
+			if seen[key] {
+				result.Duplicates++
+				continue
+			}
+			seen[key] = true
+			result.Vulnerabilities = append(result.Vulnerabilities, item)
+		}
+		if page >= pageResult.TotalPages {
+			break
+		}
+	}
+	if result.SortOrder == SortRelevance {
+		sortMultiSearch(result.Vulnerabilities, SortRelevance)
+	}
+
+	if outputPath != "" {
+		if err := c.saveSearchResult(result, outputPath); err != nil {
+			return nil, fmt.Errorf("保存搜索结果失败: %w", err)
+		}
+	}
+	return result, nil
+}
+
+// DedupKey 返回合并多页结果时使用的去重键
+// 优先使用规范化的WLB漏洞ID，ID无法识别时从链接中提取，都没有时使用原始的ID和链接；
+// 同一漏洞在不同页中的链接写法不同(例如带或不带 www.)时也能识别为重复。
+func (v SearchVulnerability) DedupKey() string {
+	if id, err := model.ParseWLBID(v.ID); err == nil {
+		return id.String()
+	}
+	if id := model.WLBIDFromURL(v.URL); id != "" {
+		return id.String()
+	}
+	return v.ID + "|" + v.URL
+}
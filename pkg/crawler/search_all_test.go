@@ -0,0 +1,80 @@
+package crawler
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+func TestSearchAll(t *testing.T) {
+	var paths []string
+	client := &mockClient{baseURL: "https://cxsecurity.com", getPageFunc: func(path string) (string, error) {
+		paths = append(paths, path)
+		return path, nil
+	}}
+	// 路径格式为 /search/wlb/DESC/AND/日期/页码/每页数量/关键词/，共4页，每页2条，第2、3页有一条重复
+	parser := &mockParser{parseListPageFunc: func(html string) (*model.VulnerabilityList, error) {
+		var page int
+		fmt.Sscanf(strings.Split(html, "/")[6], "%d", &page)
+		items := []model.Vulnerability{
+			{ID: fmt.Sprintf("WLB-%d", page*2-1), Date: time.Date(2024, 4, 30-page, 0, 0, 0, 0, time.UTC)},
+			{ID: fmt.Sprintf("WLB-%d", page*2), Date: time.Date(2024, 4, 30-page, 0, 0, 0, 0, time.UTC)},
+		}
+		if page == 3 {
+			items[1].ID = "WLB-4"
+		}
+		return &model.VulnerabilityList{Items: items, CurrentPage: page, TotalPages: 4}, nil
+	}}
+	c := NewCrawler(WithHTTPClient(client), WithCustomParser(parser))
+
+	result, err := c.SearchAll(SearchOptions{Keyword: "php", Page: 2}, 0, "")
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.CurrentPage)
+	assert.Equal(t, 4, result.TotalPages)
+	assert.Equal(t, 3, result.PagesFetched, "到最后一页时停止")
+	var ids []string
+	for _, v := range result.Vulnerabilities {
+		ids = append(ids, v.ID)
+	}
+	assert.Equal(t, []string{"WLB-3", "WLB-4", "WLB-5", "WLB-7", "WLB-8"}, ids)
+	assert.Equal(t, 1, result.Duplicates)
+
+	paths = nil
+	result, err = c.SearchAll(SearchOptions{Keyword: "php"}, 2, "")
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.PagesFetched, "最多获取maxPages页")
+	assert.Len(t, paths, 2)
+
+	// 中途失败时返回已获取的结果
+	client.getPageFunc = func(path string) (string, error) {
+		if strings.Contains(path, "/2/10/") {
+			return "", errors.New("offline")
+		}
+		return path, nil
+	}
+	result, err = c.SearchAll(SearchOptions{Keyword: "php"}, 0, "")
+	assert.ErrorContains(t, err, "第 2 页搜索失败")
+	require.NotNil(t, result)
+	assert.Equal(t, 1, result.PagesFetched)
+
+	result, err = c.SearchAll(SearchOptions{Keyword: "php", Page: 2}, 0, "")
+	assert.Error(t, err)
+	assert.Nil(t, result)
+}
+
+func TestSearchVulnerabilityDedupKey(t *testing.T) {
+	a := SearchVulnerability{ID: "WLB-2024040015", URL: "https://cxsecurity.com/issue/WLB-2024040015"}
+	b := SearchVulnerability{ID: "wlb-2024040015", URL: "https://www.cxsecurity.com/issue/WLB-2024040015/"}
+	c := SearchVulnerability{ID: "未知", URL: "https://cxsecurity.com/issue/WLB-2024040015"}
+	assert.Equal(t, "WLB-2024040015", a.DedupKey())
+	assert.Equal(t, a.DedupKey(), b.DedupKey(), "ID大小写和链接写法不同时也是同一漏洞")
+	assert.Equal(t, a.DedupKey(), c.DedupKey(), "ID未知时从链接中提取")
+	assert.NotEqual(t, SearchVulnerability{ID: "未知", URL: "/a"}.DedupKey(), SearchVulnerability{ID: "未知", URL: "/b"}.DedupKey())
+}
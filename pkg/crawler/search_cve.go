@@ -0,0 +1,87 @@
+package crawler
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+// SearchByCVE 返回某个CVE对应的所有WLB公告
+//
+// 先校验CVE编号的格式，然后从CVE详情页的相关公告列表(CVE与WLB的对照)中获取公告，
+// 相关公告较多时按 WithRelatedPages 跟随分页。CVE页面获取失败或没有列出公告时，
+// 改为在网站上以CVE编号为关键词搜索第一页。opts中的关键词被忽略，风险级别、利用方式、
+// 日期和Exclude的过滤对两种来源都有效；Page、PerPage和SortOrder只用于站内搜索。
+//
+// 参数:
+//   - cveID: CVE编号，例如 CVE-2024-1234，不区分大小写
+//   - opts: 过滤条件
+//   - outputPath: 结果保存路径，为空则不保存
+//
+// 返回值:
+//   - *SearchResult: 按发布日期从新到旧排列的公告，Keyword为规范化的CVE编号
+//   - error: CVE编号无效或两种来源都失败时返回错误
+//
+// 示例:
+//
+//	result, err := crawler.SearchByCVE("CVE-2007-1411", SearchOptions{Risks: []string{"High"}}, "")
+func (c *Crawler) SearchByCVE(cveID string, opts SearchOptions, outputPath string) (*SearchResult, error) {
+	id, err := model.ParseCVEID(cveID)
+	if err != nil {
+		return nil, err
+	}
+	if !opts.After.IsZero() && !opts.Before.IsZero() && opts.After.After(opts.Before) {
+		return nil, fmt.Errorf("开始日期 %s 晚于结束日期 %s", opts.After.Format("2006-01-02"), opts.Before.Format("2006-01-02"))
+	}
+	opts.Keyword = id.String()
+
+	detail, err := c.CrawlCveDetail(id.String(), "")
+	if err != nil || len(detail.RelatedVulnerabilities) == 0 {
+		// CVE页面获取失败或没有列出公告时使用站内搜索
+		result, searchErr := c.Search(opts, outputPath)
+		if searchErr != nil && err != nil {
+			return nil, fmt.Errorf("%v；站内搜索也失败: %w", err, searchErr)
+		}
+		return result, searchErr
+	}
+
+	result := &SearchResult{
+		Keyword:         id.String(),
+		CurrentPage:     1,
+		TotalPages:      1,
+		SortOrder:       "DESC",
+		Vulnerabilities: make([]SearchVulnerability, 0, len(detail.RelatedVulnerabilities)),
+	}
+	seen := make(map[string]bool)
+	for _, item := range detail.RelatedVulnerabilities {
+		if (!opts.After.IsZero() && item.Date.Before(opts.After)) || (!opts.Before.IsZero() && item.Date.After(opts.Before)) {
+			continue
+		}
+		v := toSearchVulnerability(item)
+		if seen[v.ID+v.URL] || !opts.match(v) {
+			continue
+		}
+		seen[v.ID+v.URL] = true
+		result.Vulnerabilities = append(result.Vulnerabilities, v)
+	}
+	// 日期为 YYYY-MM-DD 格式，可以直接按字符串比较，日期未知的排在最后，日期相同时按ID排列
+	sort.SliceStable(result.Vulnerabilities, func(i, j int) bool {
+		a, b := result.Vulnerabilities[i], result.Vulnerabilities[j]
+		if a.Date != b.Date {
+			if a.Date == "未知" || b.Date == "未知" {
+				return b.Date == "未知"
+			}
+			return a.Date > b.Date
+		}
+		return a.ID < b.ID
+	})
+	result.PerPage = len(result.Vulnerabilities)
+
+	if outputPath != "" {
+		if err := c.saveSearchResult(result, outputPath); err != nil {
+			return nil, fmt.Errorf("保存搜索结果失败: %w", err)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,74 @@
+package crawler
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+func TestSearchByCVE(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 4, d, 0, 0, 0, 0, time.UTC) }
+	var paths []string
+	client := &mockClient{baseURL: "https://cxsecurity.com", getPageFunc: func(path string) (string, error) {
+		paths = append(paths, path)
+		return path, nil
+	}}
+	related := []model.Vulnerability{
+		{ID: "WLB-2024040001", Title: "PHP RCE", Date: day(1), RiskLevel: "High"},
+		{ID: "WLB-2024040003", Title: "PHP RCE exploit", Date: day(3), RiskLevel: "High"},
+		{ID: "WLB-2024040001", Title: "PHP RCE", Date: day(1), RiskLevel: "High"},
+		{ID: "WLB-2024040002", Title: "PHP RCE PoC", Date: day(2), RiskLevel: "Low"},
+		{ID: "WLB-2024040009", Title: "undated", RiskLevel: "High"},
+	}
+	parser := &mockParser{
+		parseCveDetailPageFunc: func(html string) (*model.CveDetail, error) {
+			if strings.Contains(html, "CVE-2024-0002") {
+				return &model.CveDetail{CveID: "CVE-2024-0002"}, nil
+			}
+			return &model.CveDetail{CveID: "CVE-2024-0001", RelatedVulnerabilities: related}, nil
+		},
+		parseListPageFunc: func(html string) (*model.VulnerabilityList, error) {
+			return &model.VulnerabilityList{Items: []model.Vulnerability{{ID: "WLB-2024040005", Title: "found by search", Date: day(5)}}, CurrentPage: 1, TotalPages: 1}, nil
+		},
+	}
+	c := NewCrawler(WithHTTPClient(client), WithCustomParser(parser), WithRelatedPages(0))
+
+	_, err := c.SearchByCVE("CVE-24-1", SearchOptions{}, "")
+	assert.ErrorContains(t, err, "无效的CVE编号")
+	assert.Empty(t, paths, "格式无效时不发送请求")
+
+	result, err := c.SearchByCVE(" cve-2024-0001 ", SearchOptions{Keyword: "ignored", Risks: []string{"high"}}, "")
+	require.NoError(t, err)
+	assert.Equal(t, "CVE-2024-0001", result.Keyword)
+	var ids []string
+	for _, v := range result.Vulnerabilities {
+		ids = append(ids, v.ID)
+	}
+	assert.Equal(t, []string{"WLB-2024040003", "WLB-2024040001", "WLB-2024040009"}, ids)
+	assert.Equal(t, 3, result.PerPage)
+
+	result, err = c.SearchByCVE("CVE-2024-0001", SearchOptions{After: day(2)}, "")
+	require.NoError(t, err)
+	require.Len(t, result.Vulnerabilities, 2)
+	assert.Equal(t, "WLB-2024040003", result.Vulnerabilities[0].ID)
+
+	// CVE页面没有列出公告时使用站内搜索
+	paths = nil
+	result, err = c.SearchByCVE("CVE-2024-0002", SearchOptions{}, "")
+	require.NoError(t, err)
+	assert.Equal(t, "CVE-2024-0002", result.Keyword)
+	require.Len(t, result.Vulnerabilities, 1)
+	assert.Equal(t, "WLB-2024040005", result.Vulnerabilities[0].ID)
+	require.Len(t, paths, 2)
+	assert.True(t, strings.HasSuffix(paths[1], "/CVE-2024-0002/"), paths[1])
+
+	client.getPageFunc = func(path string) (string, error) { return "", errors.New("offline") }
+	_, err = c.SearchByCVE("CVE-2024-0001", SearchOptions{}, "")
+	assert.ErrorContains(t, err, "站内搜索也失败")
+}
@@ -0,0 +1,73 @@
+package crawler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeRiskLevel(t *testing.T) {
+	assert.Equal(t, "High", NormalizeRiskLevel("high"))
+	assert.Equal(t, "High", NormalizeRiskLevel(" H "))
+	assert.Equal(t, "Med.", NormalizeRiskLevel("Med."))
+	assert.Equal(t, "Med.", NormalizeRiskLevel("medium"))
+	assert.Equal(t, "Med.", NormalizeRiskLevel("MED"))
+	assert.Equal(t, "Low", NormalizeRiskLevel("low"))
+	assert.Equal(t, "Med.", NormalizeRiskLevel("中危"))
+	assert.Equal(t, "", NormalizeRiskLevel("critical"))
+	assert.Equal(t, "", NormalizeRiskLevel(""))
+}
+
+func TestSearchOptionsNormalize(t *testing.T) {
+	opts := SearchOptions{Keyword: "xss"}.normalize()
+	assert.Equal(t, 1, opts.Page)
+	assert.Equal(t, 10, opts.PerPage)
+	assert.Equal(t, "DESC", opts.SortOrder)
+
+	// 有过滤条件时默认每页30条，显式指定的值保留
+	assert.Equal(t, 30, SearchOptions{Remote: true}.normalize().PerPage)
+	assert.Equal(t, 10, SearchOptions{Risks: []string{"High"}, PerPage: 10}.normalize().PerPage)
+	assert.Equal(t, "ASC", SearchOptions{SortOrder: "ASC"}.normalize().SortOrder)
+}
+
+func TestSearchOptionsPath(t *testing.T) {
+	now := time.Date(2024, 4, 9, 0, 0, 0, 0, time.UTC)
+	opts := SearchOptions{Keyword: "sql injection", Page: 2, Risks: []string{"High"}}.normalize()
+	assert.Equal(t, "/search/wlb/DESC/AND/2024.4.9.1999.1.1/2/30/sql+injection/", opts.path(now))
+
+	// 指定日期范围时替换URL中的 结束日期.开始日期
+	opts = SearchOptions{
+		Keyword: "xss",
+		After:   time.Date(2020, 1, 5, 0, 0, 0, 0, time.UTC),
+		Before:  time.Date(2021, 12, 31, 0, 0, 0, 0, time.UTC),
+	}.normalize()
+	assert.Equal(t, "/search/wlb/DESC/AND/2021.12.31.2020.1.5/1/10/xss/", opts.path(now))
+
+	opts = SearchOptions{Keyword: "xss", After: time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)}.normalize()
+	assert.Equal(t, "/search/wlb/DESC/AND/2024.4.9.2023.6.1/1/10/xss/", opts.path(now))
+}
+
+func TestSearchInvalidDateRange(t *testing.T) {
+	_, err := NewCrawler().Search(SearchOptions{
+		Keyword: "xss",
+		After:   time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+		Before:  time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+	}, "")
+	assert.Error(t, err)
+}
+
+func TestSearchOptionsMatch(t *testing.T) {
+	high := SearchVulnerability{RiskLevel: "High", Remote: true}
+	med := SearchVulnerability{RiskLevel: "Med.", Local: true}
+
+	assert.True(t, SearchOptions{}.match(high))
+	assert.True(t, SearchOptions{Risks: []string{"high"}}.match(high))
+	assert.False(t, SearchOptions{Risks: []string{"high"}}.match(med))
+	assert.True(t, SearchOptions{Risks: []string{"low", "medium"}}.match(med))
+
+	assert.True(t, SearchOptions{Remote: true}.match(high))
+	assert.False(t, SearchOptions{Remote: true}.match(med))
+	assert.True(t, SearchOptions{Remote: true, Local: true}.match(med))
+	assert.False(t, SearchOptions{Risks: []string{"High"}, Local: true}.match(high))
+}
@@ -203,3 +203,24 @@ func TestSearchVulnerabilities(t *testing.T) {
 		}
 	})
 }
+
+func BenchmarkSearchVulnerabilitiesAdvanced(b *testing.B) {
+	htmlContent := loadBenchmarkFixture(b, "search-response.html")
+
+	// 使用归档的搜索结果页面代替真实请求，只衡量搜索流水线本身的开销
+	crawler := NewCrawler(WithHTTPClient(&mockClient{
+		getPageFunc: func(path string) (string, error) {
+			return htmlContent, nil
+		},
+		baseURL: "https://cxsecurity.com",
+	}))
+
+	b.SetBytes(int64(len(htmlContent)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := crawler.SearchVulnerabilitiesAdvanced("XSS", 1, 10, "DESC", ""); err != nil {
+			b.Fatalf("搜索失败: %v", err)
+		}
+	}
+}
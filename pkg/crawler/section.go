@@ -0,0 +1,102 @@
+package crawler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+// Section 表示cxsecurity网站的列表栏目
+type Section string
+
+const (
+	// SectionExploit 漏洞利用(Exploit)列表，对应 /exploit/
+	SectionExploit Section = "exploit"
+	// SectionBulletin 全部安全公告(WLB)列表，包括漏洞利用和普通漏洞公告，对应 /wlb/
+	SectionBulletin Section = "wlb"
+)
+
+// sectionPaths 是已知栏目对应的列表页路径前缀
+var sectionPaths = map[Section]string{
+	SectionExploit:  "/exploit/",
+	SectionBulletin: "/wlb/",
+}
+
+// Sections 返回所有已知栏目的名称，按字母顺序排列
+func Sections() []Section {
+	sections := make([]Section, 0, len(sectionPaths))
+	for s := range sectionPaths {
+		sections = append(sections, s)
+	}
+	sort.Slice(sections, func(i, j int) bool { return sections[i] < sections[j] })
+	return sections
+}
+
+// SectionPath 返回栏目指定页的路径
+// section可以是已知栏目名称，也可以是以 "/" 开头的列表页路径前缀(例如 "/exploit/")，
+// 用于访问使用相同列表页结构的其他栏目。
+//
+// 示例:
+//
+//	path, _ := SectionPath(SectionBulletin, 2) // "/wlb/2"
+func SectionPath(section Section, page int) (string, error) {
+	if page < 1 {
+		page = 1
+	}
+
+	prefix, ok := sectionPaths[Section(strings.ToLower(string(section)))]
+	if !ok {
+		if !strings.HasPrefix(string(section), "/") {
+			return "", fmt.Errorf("未知的栏目: %s", section)
+		}
+		prefix = string(section)
+	}
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return fmt.Sprintf("%s%d", prefix, page), nil
+}
+
+// CrawlSection 爬取指定栏目的列表页并保存结果
+// 与CrawlPage不同，会从每条记录的URL中提取漏洞ID
+//
+// 参数:
+//   - section: 栏目，例如 SectionExploit、SectionBulletin，或以 "/" 开头的路径前缀
+//   - page: 页码，从1开始，小于1时按1处理
+//   - outputPath: 结果保存路径，为空则不保存
+//
+// 返回值:
+//   - *model.VulnerabilityList: 解析后的漏洞列表
+//   - error: 栏目未知或爬取失败时返回错误
+//
+// 示例:
+//
+//	list, err := crawler.CrawlSection(SectionBulletin, 1, "wlb.json")
+func (c *Crawler) CrawlSection(section Section, page int, outputPath string) (*model.VulnerabilityList, error) {
+	path, err := SectionPath(section, page)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := c.CrawlPage(path, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// 处理每个漏洞项目，确保ID字段有值
+	for i := range result.Items {
+		if result.Items[i].ID == "" {
+			result.Items[i].ID = model.WLBIDFromURL(result.Items[i].URL).String()
+		}
+	}
+
+	if outputPath != "" {
+		if err := c.saveResult(result, outputPath); err != nil {
+			return nil, fmt.Errorf("保存结果失败: %w", err)
+		}
+	}
+
+	return result, nil
+}
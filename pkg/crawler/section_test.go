@@ -0,0 +1,63 @@
+package crawler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+func TestSectionPath(t *testing.T) {
+	path, err := SectionPath(SectionExploit, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "/exploit/1", path)
+
+	path, err = SectionPath(SectionBulletin, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, "/wlb/3", path)
+
+	// 栏目名称不区分大小写，页码小于1时按1处理
+	path, err = SectionPath("WLB", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "/wlb/1", path)
+
+	// 自定义路径前缀
+	path, err = SectionPath("/bugs", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, "/bugs/2", path)
+
+	_, err = SectionPath("unknown", 1)
+	assert.Error(t, err)
+
+	assert.Equal(t, []Section{SectionExploit, SectionBulletin}, Sections())
+}
+
+func TestCrawlSection(t *testing.T) {
+	requestedPath := ""
+	c := &Crawler{
+		client: &mockClient{
+			getPageFunc: func(path string) (string, error) {
+				requestedPath = path
+				return "<html>mock html</html>", nil
+			},
+		},
+		parser: &mockParser{
+			parseListPageFunc: func(htmlContent string) (*model.VulnerabilityList, error) {
+				return &model.VulnerabilityList{
+					Items: []model.Vulnerability{{URL: "https://cxsecurity.com/issue/WLB-2024040035"}},
+				}, nil
+			},
+		},
+	}
+
+	result, err := c.CrawlSection(SectionBulletin, 2, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "/wlb/2", requestedPath)
+	assert.Equal(t, "WLB-2024040035", result.Items[0].ID)
+
+	requestedPath = ""
+	_, err = c.CrawlSection("unknown", 1, "")
+	assert.Error(t, err)
+	assert.Empty(t, requestedPath, "未知栏目不应发送请求")
+}
@@ -0,0 +1,285 @@
+package crawler
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// seenShardCount 精确集合在磁盘上的分片数量
+// URL按哈希值的第一个字节分配到不同分片，核对时只需读取一个分片
+const seenShardCount = 256
+
+// bloomFilter 是一个定长的布隆过滤器
+// 内存占用只与预期元素数量和误判率有关，不会随着添加的URL增多而增长
+type bloomFilter struct {
+	bits []uint64 // 位数组
+	m    uint64   // 位数
+	k    uint64   // 哈希函数个数
+}
+
+// newBloomFilter 根据预期元素数量和误判率创建布隆过滤器
+// 使用标准公式 m = -n*ln(p)/(ln2)^2, k = m/n*ln2 计算位数和哈希函数个数
+func newBloomFilter(expectedItems int, falsePositiveRate float64) *bloomFilter {
+	if expectedItems < 1 {
+		expectedItems = 1
+	}
+	if falsePositiveRate <= 0 || falsePositiveRate >= 1 {
+		falsePositiveRate = 0.001
+	}
+
+	n := float64(expectedItems)
+	m := uint64(math.Ceil(-n * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2)))
+	if m < 64 {
+		m = 64
+	}
+	k := uint64(math.Round(float64(m) / n * math.Ln2))
+	if k < 1 {
+		k = 1
+	}
+
+	return &bloomFilter{
+		bits: make([]uint64, (m+63)/64),
+		m:    m,
+		k:    k,
+	}
+}
+
+// hashes 使用双重哈希从两个FNV哈希值派生出k个位置
+// 哈希函数是确定性的，重启后根据磁盘数据重建的过滤器与之前完全一致
+func (b *bloomFilter) hashes(key string) (uint64, uint64) {
+	h1 := fnv.New64a()
+	h1.Write([]byte(key))
+	h2 := fnv.New64()
+	h2.Write([]byte(key))
+	return h1.Sum64(), h2.Sum64() | 1
+}
+
+// add 将key加入过滤器
+func (b *bloomFilter) add(key string) {
+	h1, h2 := b.hashes(key)
+	for i := uint64(0); i < b.k; i++ {
+		pos := (h1 + i*h2) % b.m
+		b.bits[pos/64] |= 1 << (pos % 64)
+	}
+}
+
+// test 检查key是否可能存在，返回false表示一定不存在
+func (b *bloomFilter) test(key string) bool {
+	h1, h2 := b.hashes(key)
+	for i := uint64(0); i < b.k; i++ {
+		pos := (h1 + i*h2) % b.m
+		if b.bits[pos/64]&(1<<(pos%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// SeenSet 记录已经访问过的URL，用于全站镜像等需要跟踪海量URL的场景
+//
+// 它由两部分组成：
+// 1. 内存中的布隆过滤器：大小固定，快速判断URL"一定没见过"
+// 2. 磁盘上的精确集合：按哈希分片的追加写文件，用于持久化和核对布隆过滤器的误判
+//
+// 只有布隆过滤器判断"可能见过"时才会读取对应的磁盘分片进行精确核对，
+// 因此内存占用不会随URL数量无限增长，同时结果没有误判。
+//
+// 使用示例：
+//
+//	seen, err := OpenSeenSet("mirror/seen", 1000000, 0.001)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer seen.Close()
+//
+//	added, err := seen.Add("https://cxsecurity.com/issue/WLB-2024040015")
+//	if added {
+//	    // 第一次见到这个URL，加入爬取队列
+//	}
+type SeenSet struct {
+	mu     sync.Mutex
+	dir    string                   // 精确集合所在目录
+	bloom  *bloomFilter             // 布隆过滤器
+	shards [seenShardCount]*os.File // 已打开的分片文件，按需打开
+	count  int                      // 精确集合中的URL数量
+}
+
+// OpenSeenSet 打开(或创建)位于dir目录下的已访问URL集合
+// 如果目录中已有之前保存的数据，会流式读取并重建布隆过滤器
+//
+// 参数:
+//   - dir: 精确集合的存储目录
+//   - expectedItems: 预期的URL数量，用于计算布隆过滤器大小
+//   - falsePositiveRate: 布隆过滤器的误判率，例如0.001
+//
+// 返回值:
+//   - *SeenSet: 已访问URL集合
+//   - error: 创建目录或读取已有数据失败时返回错误
+func OpenSeenSet(dir string, expectedItems int, falsePositiveRate float64) (*SeenSet, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("创建目录失败: %w", err)
+	}
+
+	s := &SeenSet{
+		dir:   dir,
+		bloom: newBloomFilter(expectedItems, falsePositiveRate),
+	}
+
+	// 根据磁盘上的精确集合重建布隆过滤器
+	for shard := 0; shard < seenShardCount; shard++ {
+		err := s.scanShard(shard, func(u string) bool {
+			s.bloom.add(u)
+			s.count++
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// Add 将URL加入集合
+// 返回值:
+//   - bool: URL是第一次出现时返回true，已经见过时返回false
+//   - error: 读写磁盘失败时返回错误
+func (s *SeenSet) Add(rawURL string) (bool, error) {
+	key := normalizeSeenURL(rawURL)
+	if key == "" {
+		return false, errors.New("URL不能为空")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	exists, err := s.contains(key)
+	if err != nil || exists {
+		return false, err
+	}
+
+	shard := seenShard(key)
+	if s.shards[shard] == nil {
+		f, err := os.OpenFile(s.shardPath(shard), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return false, fmt.Errorf("打开分片文件失败: %w", err)
+		}
+		s.shards[shard] = f
+	}
+	if _, err := s.shards[shard].WriteString(key + "\n"); err != nil {
+		return false, fmt.Errorf("写入分片文件失败: %w", err)
+	}
+
+	s.bloom.add(key)
+	s.count++
+	return true, nil
+}
+
+// Contains 检查URL是否已经在集合中，结果是精确的
+func (s *SeenSet) Contains(rawURL string) (bool, error) {
+	key := normalizeSeenURL(rawURL)
+	if key == "" {
+		return false, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.contains(key)
+}
+
+// Len 返回集合中的URL数量
+func (s *SeenSet) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.count
+}
+
+// Close 关闭所有已打开的分片文件
+func (s *SeenSet) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var firstErr error
+	for i, f := range s.shards {
+		if f == nil {
+			continue
+		}
+		if err := f.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		s.shards[i] = nil
+	}
+	return firstErr
+}
+
+// contains 先查询布隆过滤器，只有可能存在时才读取磁盘分片核对
+func (s *SeenSet) contains(key string) (bool, error) {
+	if !s.bloom.test(key) {
+		return false, nil
+	}
+
+	found := false
+	err := s.scanShard(seenShard(key), func(u string) bool {
+		if u == key {
+			found = true
+			return false
+		}
+		return true
+	})
+	return found, err
+}
+
+// scanShard 逐行读取分片文件，fn返回false时停止读取
+func (s *SeenSet) scanShard(shard int, fn func(u string) bool) error {
+	f, err := os.Open(s.shardPath(shard))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("打开分片文件失败: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" && !fn(line) {
+			return nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("读取分片文件失败: %w", err)
+	}
+	return nil
+}
+
+// shardPath 返回分片文件路径
+func (s *SeenSet) shardPath(shard int) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%02x.txt", shard))
+}
+
+// seenShard 根据URL的SHA1哈希计算所属分片
+func seenShard(key string) int {
+	sum := sha1.Sum([]byte(key))
+	return int(sum[0])
+}
+
+// normalizeSeenURL 规范化URL：去除首尾空白和片段(#之后的部分)
+// 同一页面的不同锚点视为同一个URL
+func normalizeSeenURL(rawURL string) string {
+	u := strings.TrimSpace(rawURL)
+	if idx := strings.IndexByte(u, '#'); idx != -1 {
+		u = u[:idx]
+	}
+	return u
+}
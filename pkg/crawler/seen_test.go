@@ -0,0 +1,78 @@
+package crawler
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeenSet(t *testing.T) {
+	dir := t.TempDir()
+
+	seen, err := OpenSeenSet(dir, 1000, 0.01)
+	require.NoError(t, err, "打开集合失败")
+
+	// 第一次添加应返回true，重复添加应返回false
+	added, err := seen.Add("https://cxsecurity.com/issue/WLB-2024040015")
+	require.NoError(t, err)
+	assert.True(t, added, "第一次添加应返回true")
+
+	added, err = seen.Add("https://cxsecurity.com/issue/WLB-2024040015#comments")
+	require.NoError(t, err)
+	assert.False(t, added, "只有锚点不同的URL应视为已见过")
+
+	exists, err := seen.Contains("https://cxsecurity.com/issue/WLB-2024040016")
+	require.NoError(t, err)
+	assert.False(t, exists, "未添加的URL不应存在")
+
+	for i := 0; i < 500; i++ {
+		_, err := seen.Add(fmt.Sprintf("https://cxsecurity.com/issue/WLB-2024%06d", i))
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 501, seen.Len(), "集合大小不匹配")
+	require.NoError(t, seen.Close())
+
+	// 重新打开后数据应该仍然存在
+	reopened, err := OpenSeenSet(dir, 1000, 0.01)
+	require.NoError(t, err, "重新打开集合失败")
+	defer reopened.Close()
+
+	assert.Equal(t, 501, reopened.Len(), "重新打开后集合大小不匹配")
+	exists, err = reopened.Contains("https://cxsecurity.com/issue/WLB-2024000123")
+	require.NoError(t, err)
+	assert.True(t, exists, "重新打开后应能找到已添加的URL")
+
+	// 精确集合保证没有误判
+	for i := 500; i < 1500; i++ {
+		exists, err := reopened.Contains(fmt.Sprintf("https://cxsecurity.com/issue/WLB-2024%06d", i))
+		require.NoError(t, err)
+		assert.False(t, exists, "未添加的URL不应被判断为存在")
+	}
+}
+
+func TestSeenSetRejectsEmptyURL(t *testing.T) {
+	seen, err := OpenSeenSet(t.TempDir(), 10, 0.01)
+	require.NoError(t, err)
+	defer seen.Close()
+
+	_, err = seen.Add("   ")
+	assert.Error(t, err, "空URL应返回错误")
+}
+
+func BenchmarkSeenSetAdd(b *testing.B) {
+	seen, err := OpenSeenSet(b.TempDir(), b.N+1, 0.001)
+	if err != nil {
+		b.Fatalf("打开集合失败: %v", err)
+	}
+	defer seen.Close()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := seen.Add(fmt.Sprintf("https://cxsecurity.com/issue/WLB-%d", i)); err != nil {
+			b.Fatalf("添加失败: %v", err)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package crawler
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+// riskKeywords 是推断风险级别使用的关键词，按级别从高到低检查，第一个匹配的级别即为结果
+// 关键词不区分大小写，按完整单词匹配，标点会被视为空格，因此 "priv-esc" 写作 "priv esc"
+var riskKeywords = []struct {
+	Risk     string
+	Keywords []string
+}{
+	{Risk: "High", Keywords: []string{
+		"remote code execution", "rce", "code execution", "command injection", "command execution", "os command",
+		"sql injection", "sqli", "privilege escalation", "local privilege", "elevation of privilege", "privesc", "priv esc",
+		"authentication bypass", "auth bypass", "arbitrary file upload", "unrestricted file upload", "shell upload",
+		"deserialization", "buffer overflow", "heap overflow", "stack overflow", "use after free", "memory corruption",
+		"template injection", "ssti", "backdoor",
+	}},
+	{Risk: "Med.", Keywords: []string{
+		"xss", "cross site scripting", "csrf", "cross site request forgery", "path traversal", "directory traversal",
+		"file inclusion", "lfi", "rfi", "ssrf", "server side request forgery", "xxe", "arbitrary file read",
+		"arbitrary file download", "insecure direct object reference", "idor", "denial of service", "dos",
+	}},
+	{Risk: "Low", Keywords: []string{
+		"information disclosure", "open redirect", "clickjacking", "user enumeration", "username enumeration",
+		"path disclosure", "full path disclosure", "missing security headers",
+	}},
+}
+
+// cvssMentionPattern 匹配正文中的CVSS分数，例如 "CVSS v3.1 Base Score: 9.8"、"CVSS: 7.5"
+// 分数后面不能是 "/"，避免把 "CVSS:3.1/AV:N/..." 这样的向量中的版本号当作分数
+var cvssMentionPattern = regexp.MustCompile(`(?i)\bcvss(?:\s*v?[234](?:\.[01])?)?\s*(?:base\s*)?(?:score)?\s*[:=]?\s*(\d{1,2}(?:\.\d)?)(?:[^\d./]|$)`)
+
+// riskFromCVSS 按CVSS v3的严重程度划分将分数转换为风险级别，无效的分数返回空字符串
+// 9.0以上的Critical也归为High，与cxsecurity的三个级别对应
+func riskFromCVSS(score float64) string {
+	switch {
+	case score > 10 || score <= 0:
+		return ""
+	case score >= 7:
+		return "High"
+	case score >= 4:
+		return "Med."
+	}
+	return "Low"
+}
+
+// InferRisk 在漏洞没有风险级别标签时推断风险级别
+//
+// 依次使用以下依据：
+//  1. 正文或描述中的CVSS分数
+//  2. 标题、标签、描述和正文中的漏洞类型关键词，例如RCE、SQL注入、提权为High，XSS、CSRF为Med.
+//  3. 可远程利用的漏洞为Med.，其他为Low
+//
+// 返回值总是 "High"、"Med." 或 "Low" 之一，结果只是估计，不应代替网站上的标签。
+func InferRisk(v *model.Vulnerability) string {
+	for _, text := range []string{v.Description, v.Content} {
+		for _, m := range cvssMentionPattern.FindAllStringSubmatch(text, -1) {
+			if score, err := strconv.ParseFloat(m[1], 64); err == nil {
+				if risk := riskFromCVSS(score); risk != "" {
+					return risk
+				}
+			}
+		}
+	}
+
+	text := " " + normalizeTitle(strings.Join(append([]string{v.Title, v.Description, v.Content}, v.Tags...), " ")) + " "
+	for _, level := range riskKeywords {
+		for _, kw := range level.Keywords {
+			if strings.Contains(text, " "+kw+" ") {
+				return level.Risk
+			}
+		}
+	}
+
+	if v.IsRemote {
+		return "Med."
+	}
+	return "Low"
+}
+
+// DeriveRisk 填充漏洞的 DerivedRisk 字段
+// 有风险级别标签时为规范化的标签；没有标签或无法识别时使用 InferRisk 推断，并将 RiskInferred 设为true。
+func DeriveRisk(v *model.Vulnerability) {
+	if risk := NormalizeRiskLevel(v.RiskLevel); risk != "" {
+		v.DerivedRisk, v.RiskInferred = risk, false
+		return
+	}
+	v.DerivedRisk, v.RiskInferred = InferRisk(v), true
+}
+
+// EffectiveRisk 返回用于按风险级别过滤的级别：优先使用标签，其次是 DerivedRisk，都没有时现场推断
+// 这样没有标签的漏洞不会在按风险级别过滤时被悄悄丢掉。
+func EffectiveRisk(v *model.Vulnerability) string {
+	if risk := NormalizeRiskLevel(v.RiskLevel); risk != "" {
+		return risk
+	}
+	if risk := NormalizeRiskLevel(v.DerivedRisk); risk != "" {
+		return risk
+	}
+	return InferRisk(v)
+}
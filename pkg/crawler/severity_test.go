@@ -0,0 +1,55 @@
+package crawler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+func TestInferRisk(t *testing.T) {
+	cases := []struct {
+		name string
+		v    model.Vulnerability
+		want string
+	}{
+		{"RCE", model.Vulnerability{Title: "Foo CMS 1.2 Remote Code Execution"}, "High"},
+		{"缩写", model.Vulnerability{Title: "Bar 2.0 SQLi"}, "High"},
+		{"标点", model.Vulnerability{Title: "Baz Priv-Esc"}, "High"},
+		{"标签", model.Vulnerability{Title: "Qux 3.1", Tags: []string{"XSS"}}, "Med."},
+		{"DoS不匹配Windows", model.Vulnerability{Title: "Windows Information Disclosure"}, "Low"},
+		{"CVSS优先于关键词", model.Vulnerability{Title: "Foo XSS", Description: "CVSS v3.1 Base Score: 9.6"}, "High"},
+		{"CVSS向量不是分数", model.Vulnerability{Title: "Foo", Content: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"}, "Low"},
+		{"中等CVSS", model.Vulnerability{Content: "cvss: 5.3 (medium)"}, "Med."},
+		{"远程", model.Vulnerability{Title: "Foo 1.0", IsRemote: true}, "Med."},
+		{"无依据", model.Vulnerability{Title: "Foo 1.0"}, "Low"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, InferRisk(&tc.v))
+		})
+	}
+}
+
+func TestDeriveRisk(t *testing.T) {
+	labeled := model.Vulnerability{Title: "Foo RCE", RiskLevel: "low"}
+	DeriveRisk(&labeled)
+	assert.Equal(t, "Low", labeled.DerivedRisk)
+	assert.False(t, labeled.RiskInferred)
+
+	unlabeled := model.Vulnerability{Title: "Foo RCE"}
+	DeriveRisk(&unlabeled)
+	assert.Equal(t, "High", unlabeled.DerivedRisk)
+	assert.True(t, unlabeled.RiskInferred)
+
+	// 没有DerivedRisk的旧记录现场推断
+	assert.Equal(t, "High", EffectiveRisk(&model.Vulnerability{Title: "Foo RCE"}))
+	assert.Equal(t, "Med.", EffectiveRisk(&model.Vulnerability{Title: "Foo RCE", DerivedRisk: "Med."}))
+	assert.Equal(t, "Low", EffectiveRisk(&model.Vulnerability{Title: "Foo RCE", RiskLevel: "Low"}))
+
+	// 搜索结果按推断的级别过滤
+	item := SearchVulnerability{DerivedRisk: "High", RiskInferred: true}
+	assert.True(t, SearchOptions{Risks: []string{"high"}}.match(item))
+	assert.False(t, SearchOptions{Risks: []string{"low"}}.match(item))
+}
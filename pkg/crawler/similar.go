@@ -0,0 +1,305 @@
+package crawler
+
+import (
+	"hash/fnv"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+// DefaultSimilarityThreshold 是判定两条公告为近似重复的默认相似度
+// 同一个漏洞利用被多次发布时，标题通常只有版本号、标点或个别单词不同
+const DefaultSimilarityThreshold = 0.7
+
+const (
+	// minHashSize 是MinHash签名的长度，估计误差约为 1/sqrt(minHashSize)
+	minHashSize = 128
+	// minHashBands 是LSH分桶的段数，每段 minHashSize/minHashBands 个值
+	// 签名在任意一段上完全相同的两条记录才会进一步比较相似度
+	minHashBands = 32
+	// shingleSize 是标题切片的字符数
+	shingleSize = 4
+)
+
+// minHashSeeds 是每个哈希函数使用的种子，由固定的伪随机序列生成，保证结果可复现
+var minHashSeeds = func() [minHashSize]uint64 {
+	var seeds [minHashSize]uint64
+	x := uint64(0x9E3779B97F4A7C15)
+	for i := range seeds {
+		x = splitMix64(x)
+		seeds[i] = x
+	}
+	return seeds
+}()
+
+// splitMix64 是SplitMix64混合函数，用于将一个哈希值派生为多个独立的哈希值
+func splitMix64(x uint64) uint64 {
+	x += 0x9E3779B97F4A7C15
+	x = (x ^ (x >> 30)) * 0xBF58476D1CE4E5B9
+	x = (x ^ (x >> 27)) * 0x94D049BB133111EB
+	return x ^ (x >> 31)
+}
+
+// normalizeTitle 将标题转为小写，并将连续的非字母数字字符合并为一个空格
+func normalizeTitle(title string) string {
+	var b strings.Builder
+	space := true
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			space = false
+		} else if !space {
+			b.WriteByte(' ')
+			space = true
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// titleShingles 返回标准化后标题的字符切片集合
+func titleShingles(title string) map[string]struct{} {
+	runes := []rune(normalizeTitle(title))
+	shingles := make(map[string]struct{})
+	if len(runes) == 0 {
+		return shingles
+	}
+	if len(runes) <= shingleSize {
+		shingles[string(runes)] = struct{}{}
+		return shingles
+	}
+	for i := 0; i+shingleSize <= len(runes); i++ {
+		shingles[string(runes[i:i+shingleSize])] = struct{}{}
+	}
+	return shingles
+}
+
+// minHash 计算标题的MinHash签名，标题为空时返回nil
+func minHash(title string) []uint64 {
+	shingles := titleShingles(title)
+	if len(shingles) == 0 {
+		return nil
+	}
+
+	sig := make([]uint64, minHashSize)
+	for i := range sig {
+		sig[i] = math.MaxUint64
+	}
+	for s := range shingles {
+		h := fnv.New64a()
+		h.Write([]byte(s))
+		base := h.Sum64()
+		for i, seed := range minHashSeeds {
+			if v := splitMix64(base ^ seed); v < sig[i] {
+				sig[i] = v
+			}
+		}
+	}
+	return sig
+}
+
+// signatureSimilarity 用两个签名中相同位置取值相同的比例估计Jaccard相似度
+func signatureSimilarity(a, b []uint64) float64 {
+	if a == nil || b == nil {
+		return 0
+	}
+	same := 0
+	for i := range a {
+		if a[i] == b[i] {
+			same++
+		}
+	}
+	return float64(same) / float64(len(a))
+}
+
+// TitleSimilarity 返回两个标题的相似度估计值，范围为0到1
+// 标题先转为小写并去掉标点，再按字符切片后用MinHash估计Jaccard相似度
+func TitleSimilarity(a, b string) float64 {
+	return signatureSimilarity(minHash(a), minHash(b))
+}
+
+// ClusterTitles 将近似重复的标题分组
+// 使用MinHash签名和LSH分桶找出候选对，只比较落在同一个桶中的标题，
+// 相似度不低于threshold的标题归入同一组，分组具有传递性。
+//
+// 参数:
+//   - titles: 标题列表
+//   - threshold: 相似度阈值，范围为0到1，不大于0时使用DefaultSimilarityThreshold
+//
+// 返回值:
+//   - []int: 每个标题所属的分组编号，与titles一一对应；编号为组内第一个标题的下标，
+//     没有近似重复的标题编号为其自身的下标
+func ClusterTitles(titles []string, threshold float64) []int {
+	if threshold <= 0 {
+		threshold = DefaultSimilarityThreshold
+	}
+
+	sigs := make([][]uint64, len(titles))
+	for i, t := range titles {
+		sigs[i] = minHash(t)
+	}
+
+	// 并查集，根节点始终是组内最小的下标
+	parent := make([]int, len(titles))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	union := func(i, j int) {
+		ri, rj := find(i), find(j)
+		if ri == rj {
+			return
+		}
+		if ri < rj {
+			parent[rj] = ri
+		} else {
+			parent[ri] = rj
+		}
+	}
+
+	rows := minHashSize / minHashBands
+	compared := make(map[[2]int]bool)
+	for band := 0; band < minHashBands; band++ {
+		buckets := make(map[uint64][]int)
+		for i, sig := range sigs {
+			if sig == nil {
+				continue
+			}
+			h := fnv.New64a()
+			for _, v := range sig[band*rows : (band+1)*rows] {
+				var buf [8]byte
+				for k := range buf {
+					buf[k] = byte(v >> (8 * k))
+				}
+				h.Write(buf[:])
+			}
+			key := h.Sum64()
+			buckets[key] = append(buckets[key], i)
+		}
+
+		for _, members := range buckets {
+			for a := 0; a < len(members); a++ {
+				for b := a + 1; b < len(members); b++ {
+					pair := [2]int{members[a], members[b]}
+					if compared[pair] {
+						continue
+					}
+					compared[pair] = true
+					if signatureSimilarity(sigs[pair[0]], sigs[pair[1]]) >= threshold {
+						union(pair[0], pair[1])
+					}
+				}
+			}
+		}
+	}
+
+	clusters := make([]int, len(titles))
+	for i := range clusters {
+		clusters[i] = find(i)
+	}
+	return clusters
+}
+
+// clusterIDs 根据分组结果为有近似重复的记录生成分组ID
+// 分组ID取组内最小的漏洞ID，同一漏洞被多次发布时通常就是最早发布的那一条；
+// 组内都没有ID时使用组内第一条记录的标题。单独成组的记录返回空字符串。
+func clusterIDs(ids, titles []string, threshold float64) []string {
+	clusters := ClusterTitles(titles, threshold)
+
+	members := make(map[int][]int)
+	for i, c := range clusters {
+		members[c] = append(members[c], i)
+	}
+
+	result := make([]string, len(titles))
+	for root, idx := range members {
+		if len(idx) < 2 {
+			continue
+		}
+		var candidates []string
+		for _, i := range idx {
+			if ids[i] != "" {
+				candidates = append(candidates, ids[i])
+			}
+		}
+		clusterID := titles[root]
+		if len(candidates) > 0 {
+			sort.Strings(candidates)
+			clusterID = candidates[0]
+		}
+		for _, i := range idx {
+			result[i] = clusterID
+		}
+	}
+	return result
+}
+
+// AnnotateClusters 为近似重复的漏洞设置ClusterID
+// 同一组的漏洞ClusterID相同，没有近似重复的漏洞ClusterID为空。
+//
+// 参数:
+//   - items: 漏洞列表，会被原地修改
+//   - threshold: 相似度阈值，不大于0时使用DefaultSimilarityThreshold
+//
+// 返回值:
+//   - int: 包含两条及以上记录的分组数量
+//
+// 示例:
+//
+//	list, _ := c.CrawlExploitList(1, "")
+//	n := crawler.AnnotateClusters(list.Items, 0)
+func AnnotateClusters(items []model.Vulnerability, threshold float64) int {
+	ids := make([]string, len(items))
+	titles := make([]string, len(items))
+	for i, item := range items {
+		ids[i], titles[i] = item.ID, item.Title
+	}
+	return applyClusterIDs(clusterIDs(ids, titles, threshold), func(i int, id string) { items[i].ClusterID = id })
+}
+
+// AnnotateSearchClusters 为搜索结果中近似重复的漏洞设置ClusterID，用法同AnnotateClusters
+func AnnotateSearchClusters(items []SearchVulnerability, threshold float64) int {
+	ids := make([]string, len(items))
+	titles := make([]string, len(items))
+	for i, item := range items {
+		ids[i], titles[i] = item.ID, item.Title
+	}
+	return applyClusterIDs(clusterIDs(ids, titles, threshold), func(i int, id string) { items[i].ClusterID = id })
+}
+
+// applyClusterIDs 调用set写入分组ID，并返回非空分组ID的数量
+func applyClusterIDs(ids []string, set func(i int, id string)) int {
+	clusters := make(map[string]struct{})
+	for i, id := range ids {
+		set(i, id)
+		if id != "" {
+			clusters[id] = struct{}{}
+		}
+	}
+	return len(clusters)
+}
+
+// CollapseClusters 每个分组只保留第一条记录，用于在告警和报告中减少重复
+// 没有ClusterID的记录全部保留，返回的切片保持原有顺序
+func CollapseClusters(items []model.Vulnerability) []model.Vulnerability {
+	seen := make(map[string]bool)
+	result := make([]model.Vulnerability, 0, len(items))
+	for _, item := range items {
+		if item.ClusterID != "" {
+			if seen[item.ClusterID] {
+				continue
+			}
+			seen[item.ClusterID] = true
+		}
+		result = append(result, item)
+	}
+	return result
+}
@@ -0,0 +1,54 @@
+package crawler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+func TestTitleSimilarity(t *testing.T) {
+	assert.Equal(t, 1.0, TitleSimilarity("WordPress Plugin SQL Injection", "wordpress plugin - sql injection"))
+	assert.Greater(t, TitleSimilarity("WordPress Contact Form 7 5.3.1 SQL Injection", "WordPress Contact Form 7 5.3.2 SQL Injection"), DefaultSimilarityThreshold)
+	assert.Less(t, TitleSimilarity("WordPress Contact Form 7 SQL Injection", "Linux Kernel Privilege Escalation"), 0.2)
+	assert.Equal(t, 0.0, TitleSimilarity("", "Linux Kernel"))
+}
+
+func TestClusterTitles(t *testing.T) {
+	titles := []string{
+		"Apache Struts 2.5.30 Remote Code Execution",
+		"Linux Kernel 6.1 Privilege Escalation",
+		"Apache Struts 2.5.31 Remote Code Execution",
+		"",
+		"Apache Struts 2.5.30 - Remote Code Execution",
+	}
+	assert.Equal(t, []int{0, 1, 0, 3, 0}, ClusterTitles(titles, 0))
+}
+
+func TestAnnotateClusters(t *testing.T) {
+	items := []model.Vulnerability{
+		{ID: "WLB-2024040020", Title: "Apache Struts 2.5.31 Remote Code Execution"},
+		{ID: "WLB-2024040011", Title: "Linux Kernel 6.1 Privilege Escalation"},
+		{ID: "WLB-2024040010", Title: "Apache Struts 2.5.30 Remote Code Execution"},
+	}
+
+	assert.Equal(t, 1, AnnotateClusters(items, 0))
+	// 分组ID取组内最小的漏洞ID
+	assert.Equal(t, "WLB-2024040010", items[0].ClusterID)
+	assert.Equal(t, "", items[1].ClusterID)
+	assert.Equal(t, "WLB-2024040010", items[2].ClusterID)
+
+	collapsed := CollapseClusters(items)
+	assert.Len(t, collapsed, 2)
+	assert.Equal(t, "WLB-2024040020", collapsed[0].ID)
+	assert.Equal(t, "WLB-2024040011", collapsed[1].ID)
+
+	search := []SearchVulnerability{
+		{ID: "WLB-2", Title: "Joomla 4.2 Information Disclosure"},
+		{ID: "WLB-1", Title: "Joomla 4.2.7 Information Disclosure"},
+	}
+	assert.Equal(t, 1, AnnotateSearchClusters(search, 0))
+	assert.Equal(t, "WLB-1", search[0].ClusterID)
+	assert.Equal(t, "WLB-1", search[1].ClusterID)
+}
@@ -0,0 +1,114 @@
+package crawler
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+var (
+	// sourceLinePattern 匹配正文中注明出处的行，例如网站转载时追加的 "original url: http://..."，
+	// 以及作者写的 "Source: https://seclists.org/..."、"# Original Advisory: ..."
+	sourceLinePattern = regexp.MustCompile(`(?i)^[\s#*\[\]+\-|>/]*(original\s+url|original\s+source|original\s+advisory|advisory\s+url|source|reference)\s*[:\]]\s*(.+)$`)
+
+	// sourceURLPattern 匹配行中的链接
+	sourceURLPattern = regexp.MustCompile(`https?://[^\s<>"')\]]+`)
+)
+
+// sourcePriority 是出处字段的优先级，网站追加的 original url 最可靠
+var sourcePriority = map[string]int{
+	"original url":      4,
+	"original source":   3,
+	"original advisory": 3,
+	"advisory url":      2,
+	"source":            2,
+	"reference":         1,
+}
+
+// knownSources 是常见出处的名称，按链接前缀匹配
+var knownSources = []struct {
+	prefix string
+	name   string
+}{
+	{"seclists.org/fulldisclosure", "Full Disclosure"},
+	{"seclists.org/bugtraq", "Bugtraq"},
+	{"seclists.org/oss-sec", "oss-security"},
+	{"openwall.com/lists/oss-security", "oss-security"},
+	{"packetstormsecurity.com", "Packet Storm"},
+	{"exploit-db.com", "Exploit-DB"},
+	{"securityfocus.com", "SecurityFocus"},
+	{"github.com", "GitHub"},
+	{"zerodayinitiative.com", "Zero Day Initiative"},
+}
+
+// parseSourceAttribution 从漏洞详情页中提取公告的原始出处
+// 依次查找：正文中的 "original url:"、"Source:" 等行，正文后 #refer 中的链接。
+// 行中链接前面的文字作为出处名称，没有时使用常见出处的名称或链接的主机名。
+//
+// 参数:
+//   - doc: 漏洞详情页
+//   - content: 已提取的正文
+//   - prov: 字段来源，不为nil时记录使用的规则
+func parseSourceAttribution(doc *goquery.Document, content string, prov Provenance) *model.SourceAttribution {
+	var best *model.SourceAttribution
+	bestPriority := 0
+	for _, line := range strings.Split(content, "\n") {
+		m := sourceLinePattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		label := strings.ToLower(strings.Join(strings.Fields(m[1]), " "))
+		// 同一优先级取最后一行，网站追加的出处在正文末尾
+		if sourcePriority[label] < bestPriority {
+			continue
+		}
+		if source := sourceFromText(m[2]); source != nil {
+			best, bestPriority = source, sourcePriority[label]
+			prov.set("source", "div.premex 中的 "+m[1]+": 行")
+		}
+	}
+	if best != nil {
+		return best
+	}
+
+	link := doc.Find("#refer a[href^='http']").First()
+	if href := sanitizedHref(link); href != "" {
+		prov.set("source", "#refer a[href^='http']")
+		return newSourceAttribution(strings.TrimSpace(link.Text()), href)
+	}
+	return nil
+}
+
+// sourceFromText 从 "Full Disclosure - https://..." 这样的文字中提取出处，没有链接时返回nil
+func sourceFromText(text string) *model.SourceAttribution {
+	loc := sourceURLPattern.FindStringIndex(text)
+	if loc == nil {
+		return nil
+	}
+	link := strings.TrimRight(text[loc[0]:loc[1]], ".,;:")
+	name := strings.Trim(strings.TrimSpace(text[:loc[0]]), "-–:|()[] ")
+	return newSourceAttribution(name, link)
+}
+
+// newSourceAttribution 创建出处，name为空或与链接相同时根据链接推断名称
+func newSourceAttribution(name, link string) *model.SourceAttribution {
+	u, err := url.Parse(link)
+	if err != nil || u.Host == "" {
+		return nil
+	}
+	if name == "" || name == link {
+		host := strings.TrimPrefix(strings.ToLower(u.Host), "www.")
+		name = host
+		for _, known := range knownSources {
+			if strings.HasPrefix(host+u.Path, known.prefix) {
+				name = known.name
+				break
+			}
+		}
+	}
+	return &model.SourceAttribution{Name: name, URL: link}
+}
@@ -0,0 +1,58 @@
+package crawler
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+func TestParseSourceAttribution(t *testing.T) {
+	empty, err := goquery.NewDocumentFromReader(strings.NewReader(""))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		content string
+		want    *model.SourceAttribution
+	}{
+		{"网站追加的原始链接", "PoC\n\noriginal url: http://example.org/advisory.html",
+			&model.SourceAttribution{Name: "example.org", URL: "http://example.org/advisory.html"}},
+		{"带名称的出处", "# Source: Full Disclosure - https://seclists.org/fulldisclosure/2024/Apr/1",
+			&model.SourceAttribution{Name: "Full Disclosure", URL: "https://seclists.org/fulldisclosure/2024/Apr/1"}},
+		{"常见出处的名称", "Source: https://www.exploit-db.com/exploits/50000.",
+			&model.SourceAttribution{Name: "Exploit-DB", URL: "https://www.exploit-db.com/exploits/50000"}},
+		{"原始链接优先", "Source: https://github.com/a/b\n[+] Reference: https://blog.example.com/x\n\noriginal url: https://packetstormsecurity.com/files/1",
+			&model.SourceAttribution{Name: "Packet Storm", URL: "https://packetstormsecurity.com/files/1"}},
+		{"没有链接", "Source: internal research\nReference: CVE-2024-0001", nil},
+		{"没有出处", "// site: http://retrogod.altervista.org", nil},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, parseSourceAttribution(empty, tt.content, nil), tt.name)
+	}
+
+	// 正文中没有时使用 #refer 中的链接
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(`<p id="refer"><a href="https://seclists.org/bugtraq/2024/1">https://seclists.org/bugtraq/2024/1</a></p>`))
+	require.NoError(t, err)
+	prov := Provenance{}
+	assert.Equal(t, &model.SourceAttribution{Name: "Bugtraq", URL: "https://seclists.org/bugtraq/2024/1"}, parseSourceAttribution(doc, "", prov))
+	assert.Equal(t, "#refer a[href^='http']", prov["source"])
+}
+
+func TestParseVulnerabilityDetailSource(t *testing.T) {
+	page, err := os.ReadFile(filepath.Join(benchmarkFixtureDir, "vul-detail-response.html"))
+	require.NoError(t, err)
+	v, prov, err := NewParser().ParseVulnerabilityDetailPageWithProvenance(string(page))
+	require.NoError(t, err)
+	assert.Equal(t, &model.SourceAttribution{
+		Name: "retrogod.altervista.org",
+		URL:  "http://retrogod.altervista.org/php_446_ibase_connect_bof.html",
+	}, v.Source)
+	assert.Contains(t, prov["source"], "original url")
+}
@@ -0,0 +1,200 @@
+package crawler
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// throttleLatencySpikeFactor 响应时间超过平均值的多少倍视为延迟突增
+	throttleLatencySpikeFactor = 3
+	// throttleLatencyWarmup 计算平均响应时间所需的最少正常响应数，之前不判断延迟突增
+	throttleLatencyWarmup = 5
+	// throttlePauseAfter 请求间隔已达上限后，连续多少次异常信号触发暂停
+	throttlePauseAfter = 3
+)
+
+// ThrottleStats 是自适应限速器的当前状态快照
+type ThrottleStats struct {
+	Delay       time.Duration // 当前请求间隔
+	Rate        float64       // 当前请求速率(请求/秒)，暂停期间为0
+	Paused      bool          // 是否处于暂停状态
+	PausedUntil time.Time     // 暂停结束时间
+	NextRequest time.Time     // 下一个请求最早的发送时间，包括暂停
+	Throttled   int           // 收到的429/503响应数量
+	Spikes      int           // 检测到的响应时间突增次数
+	Errors      int           // 网络错误次数，例如连接被重置或拒绝
+	AvgLatency  time.Duration // 正常响应的平均响应时间
+}
+
+// AdaptiveThrottle 根据服务器的响应信号自动调整请求速率
+//
+// 调整策略(请求间隔按比例增减)：
+// 1. 收到429/503响应、网络错误或响应时间突增时，请求间隔翻倍，直到达到上限
+// 2. 间隔已达上限后仍连续收到异常信号，暂停一段时间后再继续
+// 3. 收到正常响应时，请求间隔缩短四分之一，直到回到下限
+//
+// 放慢时翻倍而提速时每次只缩短四分之一，因此一次异常需要连续约三次正常响应才能恢复，
+// 速率在服务器状态波动时偏向保守。
+//
+// 使用示例：
+//
+//	client := NewClient(
+//	    WithAdaptiveThrottle(200*time.Millisecond, 10*time.Second, time.Minute),
+//	)
+//	// ...
+//	stats := client.ThrottleStats()
+//	fmt.Printf("当前速率: %.2f 请求/秒\n", stats.Rate)
+type AdaptiveThrottle struct {
+	mu          sync.Mutex
+	minDelay    time.Duration // 请求间隔下限
+	maxDelay    time.Duration // 请求间隔上限
+	pause       time.Duration // 触发暂停时的暂停时长
+	delay       time.Duration // 当前请求间隔
+	lastRequest time.Time     // 上一次请求的开始时间
+	pausedUntil time.Time     // 暂停结束时间
+	badStreak   int           // 间隔达到上限后连续的异常信号次数
+	avgLatency  time.Duration // 正常响应时间的指数移动平均
+	samples     int           // 参与平均的正常响应数
+	throttled   int           // 收到的429/503响应数量
+	spikes      int           // 响应时间突增次数
+	errors      int           // 网络错误次数
+
+	now   func() time.Time    // 当前时间，便于测试替换
+	sleep func(time.Duration) // 等待函数，便于测试替换
+}
+
+// NewAdaptiveThrottle 创建一个自适应限速器
+// 参数:
+//   - minDelay: 请求间隔下限，即服务器状态良好时的最快速率，小于等于0时使用200毫秒
+//   - maxDelay: 请求间隔上限，小于minDelay时使用minDelay的50倍
+//   - pause: 间隔达到上限后仍持续收到异常信号时的暂停时长，小于等于0时使用maxDelay的6倍
+func NewAdaptiveThrottle(minDelay, maxDelay, pause time.Duration) *AdaptiveThrottle {
+	if minDelay <= 0 {
+		minDelay = 200 * time.Millisecond
+	}
+	if maxDelay < minDelay {
+		maxDelay = minDelay * 50
+	}
+	if pause <= 0 {
+		pause = maxDelay * 6
+	}
+
+	return &AdaptiveThrottle{
+		minDelay: minDelay,
+		maxDelay: maxDelay,
+		pause:    pause,
+		delay:    minDelay,
+		now:      time.Now,
+		sleep:    time.Sleep,
+	}
+}
+
+// Wait 阻塞直到允许发送下一个请求
+func (t *AdaptiveThrottle) Wait() {
+	t.mu.Lock()
+	now := t.now()
+	next := t.lastRequest.Add(t.delay)
+	if t.pausedUntil.After(next) {
+		next = t.pausedUntil
+	}
+	if next.Before(now) {
+		next = now
+	}
+	// 先占用时间槽再释放锁，保证并发请求之间也保持间隔
+	t.lastRequest = next
+	t.mu.Unlock()
+
+	if wait := next.Sub(now); wait > 0 {
+		t.sleep(wait)
+	}
+}
+
+// Observe 记录一次请求的结果，并据此调整请求速率
+// 参数:
+//   - statusCode: HTTP状态码，网络错误时传0
+//   - latency: 请求耗时
+func (t *AdaptiveThrottle) Observe(statusCode int, latency time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	bad := false
+	switch {
+	case statusCode == http.StatusTooManyRequests || statusCode == http.StatusServiceUnavailable:
+		t.throttled++
+		bad = true
+	case statusCode == 0:
+		// 服务器过载时常直接重置或拒绝连接，这类错误往往很快返回，同样视为需要放慢的信号
+		t.errors++
+		bad = true
+	case t.samples >= throttleLatencyWarmup && latency > t.avgLatency*throttleLatencySpikeFactor:
+		t.spikes++
+		bad = true
+	}
+
+	if bad {
+		t.slowDown()
+		return
+	}
+
+	if t.samples == 0 {
+		t.avgLatency = latency
+	} else {
+		t.avgLatency = (t.avgLatency*7 + latency) / 8
+	}
+	t.samples++
+	t.speedUp()
+}
+
+// Stats 返回限速器当前状态的快照
+func (t *AdaptiveThrottle) Stats() ThrottleStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stats := ThrottleStats{
+		Delay:       t.delay,
+		PausedUntil: t.pausedUntil,
+		Paused:      t.now().Before(t.pausedUntil),
+		Throttled:   t.throttled,
+		Spikes:      t.spikes,
+		Errors:      t.errors,
+		AvgLatency:  t.avgLatency,
+	}
+	if !t.lastRequest.IsZero() {
+		stats.NextRequest = t.lastRequest.Add(t.delay)
+	}
+	if t.pausedUntil.After(stats.NextRequest) {
+		stats.NextRequest = t.pausedUntil
+	}
+	if !stats.Paused {
+		stats.Rate = float64(time.Second) / float64(t.delay)
+	}
+	return stats
+}
+
+// slowDown 将请求间隔翻倍；已达上限时累计异常次数，超过阈值则暂停
+func (t *AdaptiveThrottle) slowDown() {
+	if t.delay >= t.maxDelay {
+		t.badStreak++
+		if t.badStreak >= throttlePauseAfter {
+			t.pausedUntil = t.now().Add(t.pause)
+			t.badStreak = 0
+		}
+		return
+	}
+
+	t.delay *= 2
+	if t.delay > t.maxDelay {
+		t.delay = t.maxDelay
+	}
+}
+
+// speedUp 将请求间隔缩短四分之一，直到回到下限
+func (t *AdaptiveThrottle) speedUp() {
+	t.badStreak = 0
+	t.delay -= t.delay / 4
+	if t.delay < t.minDelay {
+		t.delay = t.minDelay
+	}
+}
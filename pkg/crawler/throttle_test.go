@@ -0,0 +1,121 @@
+package crawler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestThrottle 创建一个使用假时钟的限速器，sleep只推进时钟而不真正等待
+func newTestThrottle(minDelay, maxDelay, pause time.Duration) (*AdaptiveThrottle, *time.Time) {
+	now := time.Date(2024, 4, 9, 0, 0, 0, 0, time.UTC)
+	throttle := NewAdaptiveThrottle(minDelay, maxDelay, pause)
+	throttle.now = func() time.Time { return now }
+	throttle.sleep = func(d time.Duration) { now = now.Add(d) }
+	return throttle, &now
+}
+
+func TestAdaptiveThrottleSlowDownAndRecover(t *testing.T) {
+	throttle, _ := newTestThrottle(100*time.Millisecond, time.Second, time.Minute)
+	assert.Equal(t, 10.0, throttle.Stats().Rate, "初始速率应为1/minDelay")
+
+	throttle.Observe(http.StatusTooManyRequests, 50*time.Millisecond)
+	assert.Equal(t, 200*time.Millisecond, throttle.Stats().Delay, "429后请求间隔应翻倍")
+	throttle.Observe(http.StatusServiceUnavailable, 50*time.Millisecond)
+	assert.Equal(t, 400*time.Millisecond, throttle.Stats().Delay, "503后请求间隔应翻倍")
+	assert.Equal(t, 2, throttle.Stats().Throttled)
+
+	for i := 0; i < 20; i++ {
+		throttle.Observe(http.StatusOK, 50*time.Millisecond)
+	}
+	assert.Equal(t, 100*time.Millisecond, throttle.Stats().Delay, "持续正常后应回到下限")
+}
+
+func TestAdaptiveThrottleLatencySpike(t *testing.T) {
+	throttle, _ := newTestThrottle(100*time.Millisecond, time.Second, time.Minute)
+	for i := 0; i < throttleLatencyWarmup; i++ {
+		throttle.Observe(http.StatusOK, 100*time.Millisecond)
+	}
+
+	throttle.Observe(http.StatusOK, 2*time.Second)
+	stats := throttle.Stats()
+	assert.Equal(t, 1, stats.Spikes, "应检测到响应时间突增")
+	assert.Equal(t, 200*time.Millisecond, stats.Delay)
+}
+
+func TestAdaptiveThrottleNetworkError(t *testing.T) {
+	throttle, _ := newTestThrottle(100*time.Millisecond, time.Second, time.Minute)
+
+	// 很快返回的连接重置或拒绝不应让限速器提速
+	delay := throttle.Stats().Delay
+	for i := 0; i < 10; i++ {
+		throttle.Observe(0, time.Millisecond)
+		stats := throttle.Stats()
+		assert.GreaterOrEqual(t, stats.Delay, delay, "网络错误后请求间隔不应缩短")
+		delay = stats.Delay
+	}
+	stats := throttle.Stats()
+	assert.Equal(t, time.Second, stats.Delay, "持续网络错误时请求间隔应达到上限")
+	assert.Equal(t, 10, stats.Errors)
+	assert.Zero(t, stats.AvgLatency, "网络错误不参与平均响应时间的计算")
+}
+
+func TestAdaptiveThrottlePause(t *testing.T) {
+	throttle, now := newTestThrottle(100*time.Millisecond, 400*time.Millisecond, time.Minute)
+
+	// 两次翻倍达到上限，之后连续异常触发暂停
+	for i := 0; i < 2+throttlePauseAfter; i++ {
+		throttle.Observe(http.StatusServiceUnavailable, 0)
+	}
+	stats := throttle.Stats()
+	require.True(t, stats.Paused, "持续异常后应暂停")
+	assert.Equal(t, 0.0, stats.Rate, "暂停期间速率应为0")
+
+	start := *now
+	throttle.Wait()
+	assert.Equal(t, time.Minute, now.Sub(start), "Wait应等待到暂停结束")
+	assert.False(t, throttle.Stats().Paused)
+}
+
+func TestAdaptiveThrottleWaitSpacing(t *testing.T) {
+	throttle, now := newTestThrottle(100*time.Millisecond, time.Second, time.Minute)
+
+	start := *now
+	throttle.Wait()
+	throttle.Wait()
+	throttle.Wait()
+	assert.Equal(t, 200*time.Millisecond, now.Sub(start), "连续请求之间应间隔minDelay")
+}
+
+func TestClientAdaptiveThrottle(t *testing.T) {
+	requestCount := 0
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+		if requestCount == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer testServer.Close()
+
+	client := NewClient(
+		WithRetry(1, time.Millisecond),
+		WithAdaptiveThrottle(time.Millisecond, 10*time.Millisecond, 10*time.Millisecond),
+	)
+	client.baseURL = testServer.URL
+
+	content, err := client.GetPage("/")
+	require.NoError(t, err, "429后应重试成功")
+	assert.Equal(t, "ok", content)
+	assert.Equal(t, 2, requestCount)
+	assert.Equal(t, 1, client.ThrottleStats().Throttled)
+	assert.False(t, client.ThrottleStats().NextRequest.IsZero(), "发出请求后应有下一个请求的时间")
+	assert.True(t, client.Stats().Adaptive)
+
+	assert.Equal(t, ThrottleStats{}, NewClient().ThrottleStats(), "未启用限速时应返回零值")
+}
@@ -0,0 +1,99 @@
+package crawler
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// knownDetailLabels 是漏洞详情页上已经解析到模型字段的标签，键为 fieldKey 规范化后的名称
+// 为新的标签添加模型字段后需要加入这里，避免同时出现在 UnknownFields 中
+var knownDetailLabels = map[string]bool{
+	"risk": true, "local": true, "remote": true, "cve": true, "cwe": true, "credit": true,
+}
+
+// knownCveLabels 是CVE详情页上已经解析到模型字段的标签
+var knownCveLabels = map[string]bool{
+	"published": true, "modified": true, "type": true, "description": true, "references": true,
+}
+
+// fieldKey 将页面上的标签转换为 unknown_fields 中的键
+// 例如 "CVSS Base Score:" 转换为 "cvss_base_score"，没有字母和数字时返回空字符串
+func fieldKey(label string) string {
+	var b strings.Builder
+	underscore := false
+	for _, r := range strings.ToLower(strings.TrimSpace(label)) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if underscore && b.Len() > 0 {
+				b.WriteByte('_')
+			}
+			b.WriteRune(r)
+			underscore = false
+		} else {
+			underscore = true
+		}
+	}
+	return b.String()
+}
+
+// addUnknownField 记录一个模型中没有的标签和值，已知的标签、空值和重复的标签会被忽略
+func addUnknownField(fields map[string]string, known map[string]bool, label, value string, prov Provenance, source string) map[string]string {
+	key := fieldKey(label)
+	value = strings.Join(strings.Fields(value), " ")
+	if key == "" || value == "" || known[key] {
+		return fields
+	}
+	if _, ok := fields[key]; ok {
+		return fields
+	}
+	if fields == nil {
+		fields = make(map[string]string)
+	}
+	fields[key] = value
+	prov.set("unknown_fields."+key, source)
+	return fields
+}
+
+// parseUnknownDetailFields 提取漏洞详情页上模型中还没有的标签和值
+// 网站新增的字段在支持之前会保留在结果中，不会在导出时丢失。提取的内容有：
+//   - 摘要区域中 "<u>标签:</u> 值" 形式的字段，不含风险级别、CVE等已经解析的字段
+//   - CVSS区域中 "标签: <b>值</b>" 形式的评分和攻击条件
+func parseUnknownDetailFields(doc *goquery.Document, prov Provenance) map[string]string {
+	var fields map[string]string
+	doc.Find(".well-sm > u").Each(func(_ int, s *goquery.Selection) {
+		label := strings.TrimSpace(s.Text())
+		if !strings.HasSuffix(label, ":") {
+			return
+		}
+		value := strings.TrimPrefix(strings.TrimSpace(s.Parent().Text()), label)
+		fields = addUnknownField(fields, knownDetailLabels, label, value, prov, ".well-sm > u:contains('"+label+"') 所在元素的文本")
+	})
+	doc.Find(".well-sm .nopadding").Each(func(_ int, s *goquery.Selection) {
+		label, value, ok := strings.Cut(s.Text(), ":")
+		if !ok {
+			return
+		}
+		fields = addUnknownField(fields, knownDetailLabels, label, value, prov, ".well-sm .nopadding:contains('"+strings.TrimSpace(label)+":')")
+	})
+	return fields
+}
+
+// parseUnknownCveFields 提取CVE详情页上 "<b>标签:</b> 值" 形式、模型中还没有的字段
+// 值为标签之后到下一个元素之前的文本，例如发布日期之后的字段
+func parseUnknownCveFields(doc *goquery.Document, prov Provenance) map[string]string {
+	var fields map[string]string
+	doc.Find("center > b").Each(func(_ int, s *goquery.Selection) {
+		label := strings.TrimSpace(s.Text())
+		if !strings.HasSuffix(label, ":") {
+			return
+		}
+		var value strings.Builder
+		for n := s.Nodes[0].NextSibling; n != nil && n.Type == html.TextNode; n = n.NextSibling {
+			value.WriteString(n.Data)
+		}
+		fields = addUnknownField(fields, knownCveLabels, label, value.String(), prov, "center > b:contains('"+label+"') 之后的文本")
+	})
+	return fields
+}
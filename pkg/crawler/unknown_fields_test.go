@@ -0,0 +1,63 @@
+package crawler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldKey(t *testing.T) {
+	for label, want := range map[string]string{
+		"CVSS Base Score:":  "cvss_base_score",
+		" Exploit range: ":  "exploit_range",
+		"Dork:":             "dork",
+		"Fixed in (vendor)": "fixed_in_vendor",
+		"::":                "",
+	} {
+		assert.Equal(t, want, fieldKey(label), label)
+	}
+}
+
+func TestParseUnknownDetailFields(t *testing.T) {
+	page, err := os.ReadFile(filepath.Join(benchmarkFixtureDir, "vul-detail-response.html"))
+	require.NoError(t, err)
+
+	v, prov, err := NewParser().ParseVulnerabilityDetailPageWithProvenance(string(page))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"cvss_base_score":         "5.4/10",
+		"impact_subscore":         "6.4/10",
+		"exploitability_subscore": "5.5/10",
+		"exploit_range":           "Adjacent network",
+		"attack_complexity":       "Medium",
+		"authentication":          "No required",
+		"confidentiality_impact":  "Partial",
+		"integrity_impact":        "Partial",
+		"availability_impact":     "Partial",
+	}, v.UnknownFields, "已解析的风险级别、CVE等字段不应重复出现")
+	assert.Contains(t, prov, "unknown_fields.exploit_range")
+
+	// 网站新增的摘要字段
+	v, err = NewParser().ParseVulnerabilityDetailPage(`<div class="panel-body"><h4><B>Title</B></h4>
+		<div class="well well-sm"><U>Risk:</U> <b><span class="label">High</span></b></div>
+		<div class="well well-sm"><U>Dork:</U> <b>inurl:/wp-content/plugins/foo</b></div>
+		<div class="well well-sm"><U>Vendor fix:</U> <b></b></div></div>`)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"dork": "inurl:/wp-content/plugins/foo"}, v.UnknownFields)
+}
+
+func TestParseUnknownCveFields(t *testing.T) {
+	page, err := os.ReadFile(filepath.Join(benchmarkFixtureDir, "cve-show-detail-response.html"))
+	require.NoError(t, err)
+	d, err := NewParser().ParseCveDetailPage(string(page))
+	require.NoError(t, err)
+	assert.Nil(t, d.UnknownFields, "示例页面上的字段都已解析")
+
+	d, err = NewParser().ParseCveDetailPage(`<center><h1><strong>CVE-2024-1</strong></h1>
+		<BR><B>Published:</B> 2024-01-10&nbsp;&nbsp;<B>Modified:</B> 2024-02-01&nbsp;&nbsp;<B>Assigner:</B> mitre<BR></center>`)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"assigner": "mitre"}, d.UnknownFields)
+}
@@ -0,0 +1,163 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+// BundleFile 是证据包中的一个附加文件，例如代码片段或截图
+type BundleFile struct {
+	Name   string // 文件名，不含目录
+	Source string // 来源，截图为原始链接，代码片段为识别出的语言
+	Data   []byte
+}
+
+// BundleMissing 是没能放进证据包的附件，例如下载失败的截图
+type BundleMissing struct {
+	Source string // 原始链接
+	Reason string // 失败原因
+}
+
+// AdvisoryBundle 是一个漏洞公告的证据包，可以整体作为附件提交到工单或报告中
+// 压缩包内的文件都位于以漏洞ID命名的目录中：
+//
+//	WLB-2024040015/
+//	  README.md       Markdown摘要
+//	  advisory.json   解析后的漏洞
+//	  advisory.html   原始详情页
+//	  snippets/       从正文中提取的代码片段
+//	  screenshots/    公告中引用的图片
+type AdvisoryBundle struct {
+	Vulnerability *model.Vulnerability
+	HTML          string          // 原始详情页HTML
+	Snippets      []BundleFile    // 代码片段，Source为识别出的语言
+	Screenshots   []BundleFile    // 截图，Source为原始链接
+	Missing       []BundleMissing // 没能下载的截图
+	Created       time.Time       // 生成时间
+	Dates         model.DateFormat
+}
+
+// Dir 返回压缩包内的顶层目录名
+func (b *AdvisoryBundle) Dir() string {
+	if b.Vulnerability.ID != "" {
+		return b.Vulnerability.ID
+	}
+	return "advisory"
+}
+
+// WriteZip 将证据包写为ZIP文件
+func (b *AdvisoryBundle) WriteZip(w io.Writer) error {
+	zw := zip.NewWriter(w)
+	add := func(name string, data []byte) error {
+		f, err := zw.CreateHeader(&zip.FileHeader{Name: b.Dir() + "/" + name, Method: zip.Deflate, Modified: b.Created})
+		if err != nil {
+			return err
+		}
+		_, err = f.Write(data)
+		return err
+	}
+
+	var summary strings.Builder
+	if err := b.WriteMarkdown(&summary); err != nil {
+		return err
+	}
+	if err := add("README.md", []byte(summary.String())); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(b.Vulnerability, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := add("advisory.json", data); err != nil {
+		return err
+	}
+	if b.HTML != "" {
+		if err := add("advisory.html", []byte(b.HTML)); err != nil {
+			return err
+		}
+	}
+	for _, f := range b.Snippets {
+		if err := add("snippets/"+f.Name, f.Data); err != nil {
+			return err
+		}
+	}
+	for _, f := range b.Screenshots {
+		if err := add("screenshots/"+f.Name, f.Data); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// WriteMarkdown 输出证据包的Markdown摘要：漏洞的元数据、描述和包内文件列表
+func (b *AdvisoryBundle) WriteMarkdown(w io.Writer) error {
+	v := b.Vulnerability
+	bw := bufio.NewWriter(w)
+	title := strings.TrimSpace(v.Title)
+	if title == "" {
+		title = b.Dir()
+	}
+	fmt.Fprintf(bw, "# %s\n\n", title)
+
+	fmt.Fprint(bw, "| Field | Value |\n|---|---|\n")
+	row := func(name, value string) {
+		if strings.TrimSpace(value) != "" {
+			fmt.Fprintf(bw, "| %s | %s |\n", name, markdownCell(value))
+		}
+	}
+	row("ID", v.ID)
+	if v.URL != "" {
+		fmt.Fprintf(bw, "| URL | <%s> |\n", v.URL)
+	}
+	risk := crawler.EffectiveRisk(v)
+	if v.RiskLevel == "" && risk != "" {
+		risk += " (inferred)"
+	}
+	row("Risk", risk)
+	row("Date", b.Dates.Format(v.Date))
+	row("CVE", v.CVE)
+	row("CWE", v.CWE)
+	row("Author", v.Author)
+	row("Platform", v.Platform)
+	row("Affected versions", strings.Join(v.AffectedVersions, ", "))
+	if v.Source != nil {
+		row("Original source", v.Source.Name+" "+v.Source.URL)
+	}
+	row("Tags", strings.Join(v.Tags, ", "))
+
+	if v.Description != "" {
+		fmt.Fprintf(bw, "\n## Description\n\n%s\n", strings.TrimSpace(v.Description))
+	}
+	if v.AISummary != nil {
+		fmt.Fprintf(bw, "\n## AI summary\n\n_Generated by %s, not reviewed._\n\n%s\n", v.AISummary.Model, strings.TrimSpace(v.AISummary.Summary))
+	}
+
+	fmt.Fprint(bw, "\n## Contents\n\n- `advisory.json`: parsed advisory\n")
+	if b.HTML != "" {
+		fmt.Fprint(bw, "- `advisory.html`: raw advisory page\n")
+	}
+	for _, f := range b.Snippets {
+		fmt.Fprintf(bw, "- `snippets/%s`: code snippet (%s)\n", f.Name, f.Source)
+	}
+	for _, f := range b.Screenshots {
+		fmt.Fprintf(bw, "- `screenshots/%s`: <%s>\n", f.Name, f.Source)
+	}
+	if len(b.Missing) > 0 {
+		fmt.Fprint(bw, "\n## Missing screenshots\n\n")
+		for _, m := range b.Missing {
+			fmt.Fprintf(bw, "- <%s>: %s\n", m.Source, markdownCell(m.Reason))
+		}
+	}
+	if !b.Created.IsZero() {
+		fmt.Fprintf(bw, "\n_Bundle created %s._\n", b.Created.UTC().Format(time.RFC3339))
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,73 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+func TestAdvisoryBundleWriteZip(t *testing.T) {
+	b := &AdvisoryBundle{
+		Vulnerability: &model.Vulnerability{
+			ID: "WLB-2024040015", Title: "Struts | RCE", URL: "https://cxsecurity.com/issue/WLB-2024040015",
+			RiskLevel: "High", CVE: "CVE-2024-1111", Author: "hyp3rlinx", Description: "远程代码执行",
+			Date: time.Date(2024, 4, 5, 0, 0, 0, 0, time.UTC),
+		},
+		HTML:        "<html></html>",
+		Snippets:    []BundleFile{{Name: "snippet-1.py", Source: "python", Data: []byte("print(1)\n")}},
+		Screenshots: []BundleFile{{Name: "screenshot-1.png", Source: "https://example.com/poc.png", Data: []byte("png")}},
+		Missing:     []BundleMissing{{Source: "https://example.com/gone.png", Reason: "请求失败: 404 Not Found"}},
+		Created:     time.Date(2024, 4, 6, 8, 0, 0, 0, time.UTC),
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, b.WriteZip(&buf))
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	files := make(map[string]string)
+	for _, f := range r.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		data, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		rc.Close()
+		files[f.Name] = string(data)
+	}
+	assert.Len(t, files, 5)
+	assert.Equal(t, "<html></html>", files["WLB-2024040015/advisory.html"])
+	assert.Equal(t, "print(1)\n", files["WLB-2024040015/snippets/snippet-1.py"])
+	assert.Equal(t, "png", files["WLB-2024040015/screenshots/screenshot-1.png"])
+
+	var v model.Vulnerability
+	require.NoError(t, json.Unmarshal([]byte(files["WLB-2024040015/advisory.json"]), &v))
+	assert.Equal(t, "CVE-2024-1111", v.CVE)
+
+	summary := files["WLB-2024040015/README.md"]
+	assert.True(t, strings.HasPrefix(summary, "# Struts | RCE\n"))
+	assert.Contains(t, summary, "| Risk | High |")
+	assert.Contains(t, summary, "| Date | 2024-04-05 |")
+	assert.Contains(t, summary, "- `snippets/snippet-1.py`: code snippet (python)")
+	assert.Contains(t, summary, "- <https://example.com/gone.png>: 请求失败: 404 Not Found")
+	assert.NotContains(t, summary, "| Platform |", "空字段不输出")
+}
+
+func TestAdvisoryBundleWithoutHTML(t *testing.T) {
+	b := &AdvisoryBundle{Vulnerability: &model.Vulnerability{Title: "没有ID"}}
+	var buf bytes.Buffer
+	require.NoError(t, b.WriteZip(&buf))
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	require.Len(t, r.File, 2)
+	assert.Equal(t, "advisory/README.md", r.File[0].Name)
+	assert.Equal(t, "advisory/advisory.json", r.File[1].Name)
+}
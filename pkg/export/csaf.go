@@ -0,0 +1,325 @@
+package export
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+// CSAFVersion 是生成的文档使用的CSAF版本
+const CSAFVersion = "2.0"
+
+// CSAFDocument 是CSAF 2.0文档，结构见 https://docs.oasis-open.org/csaf/csaf/v2.0/
+type CSAFDocument struct {
+	Document        CSAFDocumentMeta    `json:"document"`
+	ProductTree     *CSAFProductTree    `json:"product_tree,omitempty"`
+	Vulnerabilities []CSAFVulnerability `json:"vulnerabilities,omitempty"`
+}
+
+// CSAFDocumentMeta 是文档级别的元数据
+type CSAFDocumentMeta struct {
+	Category    string          `json:"category"`
+	CSAFVersion string          `json:"csaf_version"`
+	Lang        string          `json:"lang,omitempty"`
+	Notes       []CSAFNote      `json:"notes,omitempty"`
+	Publisher   CSAFPublisher   `json:"publisher"`
+	References  []CSAFReference `json:"references,omitempty"`
+	Title       string          `json:"title"`
+	Tracking    CSAFTracking    `json:"tracking"`
+}
+
+// CSAFNote 是说明文字
+type CSAFNote struct {
+	Category string `json:"category"`
+	Text     string `json:"text"`
+	Title    string `json:"title,omitempty"`
+}
+
+// CSAFPublisher 是文档的发布方
+type CSAFPublisher struct {
+	Category  string `json:"category"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+// CSAFReference 是参考链接
+type CSAFReference struct {
+	Category string `json:"category,omitempty"`
+	Summary  string `json:"summary"`
+	URL      string `json:"url"`
+}
+
+// CSAFTracking 是文档的版本跟踪信息
+type CSAFTracking struct {
+	CurrentReleaseDate string         `json:"current_release_date"`
+	Generator          *CSAFGenerator `json:"generator,omitempty"`
+	ID                 string         `json:"id"`
+	InitialReleaseDate string         `json:"initial_release_date"`
+	RevisionHistory    []CSAFRevision `json:"revision_history"`
+	Status             string         `json:"status"`
+	Version            string         `json:"version"`
+}
+
+// CSAFGenerator 记录生成文档的工具
+type CSAFGenerator struct {
+	Engine CSAFEngine `json:"engine"`
+}
+
+// CSAFEngine 是生成文档的工具名称
+type CSAFEngine struct {
+	Name string `json:"name"`
+}
+
+// CSAFRevision 是修订记录
+type CSAFRevision struct {
+	Date    string `json:"date"`
+	Number  string `json:"number"`
+	Summary string `json:"summary"`
+}
+
+// CSAFProductTree 是产品树，按 厂商 -> 产品 两级组织
+type CSAFProductTree struct {
+	Branches []CSAFBranch `json:"branches"`
+}
+
+// CSAFBranch 是产品树的分支
+type CSAFBranch struct {
+	Category string       `json:"category"`
+	Name     string       `json:"name"`
+	Branches []CSAFBranch `json:"branches,omitempty"`
+	Product  *CSAFProduct `json:"product,omitempty"`
+}
+
+// CSAFProduct 是产品树的叶子节点
+type CSAFProduct struct {
+	Name      string `json:"name"`
+	ProductID string `json:"product_id"`
+}
+
+// CSAFVulnerability 是漏洞信息
+type CSAFVulnerability struct {
+	CVE           string             `json:"cve"`
+	CWE           *CSAFCWE           `json:"cwe,omitempty"`
+	Notes         []CSAFNote         `json:"notes,omitempty"`
+	ProductStatus *CSAFProductStatus `json:"product_status,omitempty"`
+	References    []CSAFReference    `json:"references,omitempty"`
+	ReleaseDate   string             `json:"release_date,omitempty"`
+	Scores        []CSAFScore        `json:"scores,omitempty"`
+	Threats       []CSAFThreat       `json:"threats,omitempty"`
+}
+
+// CSAFCWE 是漏洞类型
+type CSAFCWE struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// CSAFProductStatus 是产品受影响的状态
+type CSAFProductStatus struct {
+	KnownAffected []string `json:"known_affected,omitempty"`
+}
+
+// CSAFScore 是产品对应的评分
+type CSAFScore struct {
+	CvssV3   *CVECvssV3 `json:"cvss_v3,omitempty"`
+	CvssV2   *CVECvssV2 `json:"cvss_v2,omitempty"`
+	Products []string   `json:"products"`
+}
+
+// CSAFThreat 是威胁信息，这里用于说明cxsecurity上公开的漏洞利用
+type CSAFThreat struct {
+	Category   string   `json:"category"`
+	Date       string   `json:"date,omitempty"`
+	Details    string   `json:"details"`
+	ProductIDs []string `json:"product_ids,omitempty"`
+}
+
+// CSAFOptions 是生成CSAF文档的选项
+type CSAFOptions struct {
+	PublisherName      string    // 发布方名称，为空时为 "cxsecurity"
+	PublisherNamespace string    // 发布方的命名空间URL，为空时为 https://cxsecurity.com
+	Now                time.Time // CVE没有日期时使用的时间，零值时使用当前时间
+}
+
+// ToCSAF 将CVE详情和相关的WLB漏洞转换为CSAF 2.0文档
+// 有受影响软件时生成 csaf_security_advisory 类型的文档，受影响软件列为known_affected；
+// 没有受影响软件时生成 csaf_base 类型的文档。相关的WLB漏洞作为参考链接，
+// 并在threats中说明已有公开的漏洞利用。
+//
+// 示例:
+//
+//	detail, _ := c.CrawlCveDetail("CVE-2007-1411", "")
+//	doc, err := export.ToCSAF(detail, export.CSAFOptions{})
+func ToCSAF(d *model.CveDetail, opts CSAFOptions) (*CSAFDocument, error) {
+	if d == nil || d.CveID == "" {
+		return nil, errors.New("CVE编号不能为空")
+	}
+	if opts.PublisherName == "" {
+		opts.PublisherName = CVERecordProvider
+	}
+	if opts.PublisherNamespace == "" {
+		opts.PublisherNamespace = "https://cxsecurity.com"
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+
+	initial, current := d.Published, d.Modified
+	if initial.IsZero() {
+		initial = opts.Now
+	}
+	if current.IsZero() || current.Before(initial) {
+		current = initial
+	}
+
+	source := CVESourceURL + d.CveID + "/"
+	doc := &CSAFDocument{
+		Document: CSAFDocumentMeta{
+			Category:    "csaf_base",
+			CSAFVersion: CSAFVersion,
+			Lang:        "en",
+			Publisher: CSAFPublisher{
+				Category:  "other",
+				Name:      opts.PublisherName,
+				Namespace: opts.PublisherNamespace,
+			},
+			References: []CSAFReference{{Category: "external", Summary: "cxsecurity " + d.CveID, URL: source}},
+			Title:      csafTitle(d),
+			Tracking: CSAFTracking{
+				CurrentReleaseDate: csafDate(current),
+				Generator:          &CSAFGenerator{Engine: CSAFEngine{Name: "cxsecurity-crawler"}},
+				ID:                 d.CveID,
+				InitialReleaseDate: csafDate(initial),
+				RevisionHistory:    []CSAFRevision{{Date: csafDate(initial), Number: "1", Summary: "Initial version."}},
+				Status:             "final",
+				Version:            "1",
+			},
+		},
+	}
+
+	vuln := CSAFVulnerability{
+		CVE:        d.CveID,
+		References: []CSAFReference{{Category: "external", Summary: "cxsecurity " + d.CveID, URL: source}},
+	}
+	if desc := strings.TrimSpace(d.Description); desc != "" {
+		doc.Document.Notes = []CSAFNote{{Category: "summary", Title: "Summary", Text: desc}}
+		vuln.Notes = []CSAFNote{{Category: "description", Title: "Vulnerability description", Text: desc}}
+	}
+	if !d.Published.IsZero() {
+		vuln.ReleaseDate = csafDate(d.Published)
+	}
+	if cwe := d.CWE(); cwe != "" {
+		// 页面上没有CWE名称，使用编号作为名称
+		vuln.CWE = &CSAFCWE{ID: cwe, Name: cwe}
+	} else if t := strings.TrimSpace(d.Type); cweIDPattern.MatchString(t) {
+		vuln.CWE = &CSAFCWE{ID: t, Name: t}
+	}
+	seenRefs := map[string]bool{source: true}
+	for _, ref := range d.References {
+		if ref = strings.TrimSpace(ref); ref != "" && !seenRefs[ref] {
+			seenRefs[ref] = true
+			vuln.References = append(vuln.References, CSAFReference{Category: "external", Summary: ref, URL: ref})
+		}
+	}
+
+	productIDs := csafProducts(doc, d.AffectedSoftware)
+	if len(productIDs) > 0 {
+		doc.Document.Category = "csaf_security_advisory"
+		vuln.ProductStatus = &CSAFProductStatus{KnownAffected: productIDs}
+		score := CSAFScore{CvssV3: cvssV3(d), Products: productIDs}
+		if vector := cvssV2Vector(d); vector != "" && d.CvssBaseScore > 0 {
+			score.CvssV2 = &CVECvssV2{Version: "2.0", VectorString: vector, BaseScore: d.CvssBaseScore}
+		}
+		if score.CvssV3 != nil || score.CvssV2 != nil {
+			vuln.Scores = []CSAFScore{score}
+		}
+	}
+
+	for _, related := range d.RelatedVulnerabilities {
+		if related.URL == "" {
+			continue
+		}
+		summary := related.Title
+		if summary == "" {
+			summary = related.URL
+		}
+		vuln.References = append(vuln.References, CSAFReference{Category: "external", Summary: summary, URL: related.URL})
+
+		threat := CSAFThreat{
+			Category:   "exploit_status",
+			Details:    fmt.Sprintf("Public exploit published on cxsecurity: %s", related.URL),
+			ProductIDs: productIDs,
+		}
+		if !related.Date.IsZero() {
+			threat.Date = csafDate(related.Date)
+		}
+		vuln.Threats = append(vuln.Threats, threat)
+	}
+
+	doc.Vulnerabilities = []CSAFVulnerability{vuln}
+	return doc, nil
+}
+
+// csafProducts 为受影响软件构建产品树，返回所有产品ID
+func csafProducts(doc *CSAFDocument, software []model.AffectedSoftware) []string {
+	var ids []string
+	vendors := make(map[string]int)
+	seen := make(map[string]bool)
+	tree := &CSAFProductTree{}
+
+	for _, sw := range software {
+		vendor, product := strings.TrimSpace(sw.VendorName), strings.TrimSpace(sw.ProductName)
+		if product == "" || seen[vendor+"\x00"+product] {
+			continue
+		}
+		seen[vendor+"\x00"+product] = true
+		if vendor == "" {
+			vendor = "unknown"
+		}
+
+		i, ok := vendors[vendor]
+		if !ok {
+			i = len(tree.Branches)
+			vendors[vendor] = i
+			tree.Branches = append(tree.Branches, CSAFBranch{Category: "vendor", Name: vendor})
+		}
+
+		id := fmt.Sprintf("CSAFPID-%04d", len(ids)+1)
+		ids = append(ids, id)
+		tree.Branches[i].Branches = append(tree.Branches[i].Branches, CSAFBranch{
+			Category: "product_name",
+			Name:     product,
+			Product:  &CSAFProduct{Name: productName(sw), ProductID: id},
+		})
+	}
+
+	if len(ids) > 0 {
+		doc.ProductTree = tree
+	}
+	return ids
+}
+
+// csafTitle 返回文档标题：CVE编号加上第一条相关漏洞的标题，没有时使用描述的开头
+func csafTitle(d *model.CveDetail) string {
+	for _, v := range d.RelatedVulnerabilities {
+		if v.Title != "" {
+			return d.CveID + ": " + v.Title
+		}
+	}
+	desc := []rune(strings.TrimSpace(d.Description))
+	if len(desc) == 0 {
+		return d.CveID
+	}
+	if len(desc) > 80 {
+		desc = append(desc[:77], []rune("...")...)
+	}
+	return d.CveID + ": " + string(desc)
+}
+
+// csafDate 将时间格式化为CSAF使用的UTC时间戳
+func csafDate(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,85 @@
+package export
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+func TestToCSAF(t *testing.T) {
+	detail := &model.CveDetail{
+		CveID:                 "CVE-2007-1411",
+		Published:             time.Date(2007, 3, 10, 0, 0, 0, 0, time.UTC),
+		Modified:              time.Date(2018, 10, 16, 0, 0, 0, 0, time.UTC),
+		Description:           "Buffer overflow in PHP 4.4.6 and earlier",
+		Type:                  "CWE-119",
+		CvssBaseScore:         6.8,
+		ExploitRange:          "Remote",
+		AttackComplexity:      "Medium",
+		Authentication:        "No required",
+		ConfidentialityImpact: "Partial",
+		IntegrityImpact:       "Partial",
+		AvailabilityImpact:    "Partial",
+		AffectedSoftware: []model.AffectedSoftware{
+			{VendorName: "PHP", ProductName: "PHP"},
+			{VendorName: "PHP", ProductName: "PHP"},
+			{VendorName: "PHP", ProductName: "PHP-FPM"},
+		},
+		References: []string{"http://example.com/advisory", "http://example.com/advisory"},
+		RelatedVulnerabilities: []model.Vulnerability{
+			{Title: "PHP mssql_connect() overflow", URL: "https://cxsecurity.com/issue/WLB-2007030105", Date: time.Date(2007, 3, 12, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+
+	doc, err := ToCSAF(detail, CSAFOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "csaf_security_advisory", doc.Document.Category)
+	assert.Equal(t, "2.0", doc.Document.CSAFVersion)
+	assert.Equal(t, "CVE-2007-1411: PHP mssql_connect() overflow", doc.Document.Title)
+	assert.Equal(t, "cxsecurity", doc.Document.Publisher.Name)
+	assert.Equal(t, "CVE-2007-1411", doc.Document.Tracking.ID)
+	assert.Equal(t, "2007-03-10T00:00:00Z", doc.Document.Tracking.InitialReleaseDate)
+	assert.Equal(t, "2018-10-16T00:00:00Z", doc.Document.Tracking.CurrentReleaseDate)
+
+	require.NotNil(t, doc.ProductTree)
+	require.Len(t, doc.ProductTree.Branches, 1)
+	assert.Equal(t, "PHP", doc.ProductTree.Branches[0].Name)
+	require.Len(t, doc.ProductTree.Branches[0].Branches, 2)
+	assert.Equal(t, "CSAFPID-0002", doc.ProductTree.Branches[0].Branches[1].Product.ProductID)
+
+	require.Len(t, doc.Vulnerabilities, 1)
+	vuln := doc.Vulnerabilities[0]
+	assert.Equal(t, "CWE-119", vuln.CWE.ID)
+	assert.Equal(t, []string{"CSAFPID-0001", "CSAFPID-0002"}, vuln.ProductStatus.KnownAffected)
+	require.Len(t, vuln.References, 3)
+	assert.Equal(t, "https://cxsecurity.com/issue/WLB-2007030105", vuln.References[2].URL)
+	require.Len(t, vuln.Scores, 1)
+	assert.Equal(t, "AV:N/AC:M/Au:N/C:P/I:P/A:P", vuln.Scores[0].CvssV2.VectorString)
+	require.Len(t, vuln.Threats, 1)
+	assert.Equal(t, "exploit_status", vuln.Threats[0].Category)
+	assert.Equal(t, "2007-03-12T00:00:00Z", vuln.Threats[0].Date)
+
+	data, err := json.Marshal(doc)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"csaf_version":"2.0"`)
+}
+
+func TestToCSAFWithoutProducts(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	doc, err := ToCSAF(&model.CveDetail{CveID: "CVE-2024-0001"}, CSAFOptions{PublisherName: "ACME PSIRT", PublisherNamespace: "https://acme.example", Now: now})
+	require.NoError(t, err)
+	assert.Equal(t, "csaf_base", doc.Document.Category)
+	assert.Equal(t, "CVE-2024-0001", doc.Document.Title)
+	assert.Equal(t, "ACME PSIRT", doc.Document.Publisher.Name)
+	assert.Equal(t, "2024-01-02T03:04:05Z", doc.Document.Tracking.InitialReleaseDate)
+	assert.Nil(t, doc.ProductTree)
+	assert.Nil(t, doc.Vulnerabilities[0].ProductStatus)
+
+	_, err = ToCSAF(nil, CSAFOptions{})
+	assert.Error(t, err)
+}
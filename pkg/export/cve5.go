@@ -0,0 +1,296 @@
+package export
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+// CVE JSON 5.0记录中使用的常量
+const (
+	// CVERecordDataVersion 是生成的记录使用的CVE JSON版本
+	CVERecordDataVersion = "5.0"
+	// CVERecordProvider 是providerMetadata中的shortName，标明数据来源
+	CVERecordProvider = "cxsecurity"
+	// CVERecordOrgID 是providerMetadata和assignerOrgId使用的组织ID
+	// cxsecurity不是CNA，没有CVE Program分配的UUID，这里使用全零UUID占位
+	CVERecordOrgID = "00000000-0000-0000-0000-000000000000"
+	// CVESourceURL 是cxsecurity的CVE详情页URL前缀
+	CVESourceURL = "https://cxsecurity.com/cveshow/"
+)
+
+// cweIDPattern 用于从漏洞类型中提取CWE编号
+var cweIDPattern = regexp.MustCompile(`^CWE-\d+$`)
+
+// CVERecord 是CVE JSON 5.0 (CVE Record Format) 记录
+// 只包含能从cxsecurity页面得到的字段，结构见 https://github.com/CVEProject/cve-schema
+type CVERecord struct {
+	DataType    string        `json:"dataType"`
+	DataVersion string        `json:"dataVersion"`
+	CveMetadata CVEMetadata   `json:"cveMetadata"`
+	Containers  CVEContainers `json:"containers"`
+}
+
+// CVEMetadata 是记录的元数据
+type CVEMetadata struct {
+	CveID         string `json:"cveId"`
+	AssignerOrgID string `json:"assignerOrgId"`
+	State         string `json:"state"`
+	DatePublished string `json:"datePublished,omitempty"`
+	DateUpdated   string `json:"dateUpdated,omitempty"`
+}
+
+// CVEContainers 是记录的数据容器，只生成cna容器
+type CVEContainers struct {
+	CNA CVECNAContainer `json:"cna"`
+}
+
+// CVECNAContainer 是cna容器
+type CVECNAContainer struct {
+	ProviderMetadata CVEProviderMetadata `json:"providerMetadata"`
+	Descriptions     []CVEDescription    `json:"descriptions"`
+	Affected         []CVEAffected       `json:"affected"`
+	ProblemTypes     []CVEProblemType    `json:"problemTypes,omitempty"`
+	References       []CVEReference      `json:"references"`
+	Metrics          []CVEMetric         `json:"metrics,omitempty"`
+	XGenerator       *CVEGenerator       `json:"x_generator,omitempty"`
+}
+
+// CVEProviderMetadata 标明容器数据的提供方
+type CVEProviderMetadata struct {
+	OrgID       string `json:"orgId"`
+	ShortName   string `json:"shortName,omitempty"`
+	DateUpdated string `json:"dateUpdated,omitempty"`
+}
+
+// CVEDescription 是漏洞描述
+type CVEDescription struct {
+	Lang  string `json:"lang"`
+	Value string `json:"value"`
+}
+
+// CVEAffected 是受影响的产品
+type CVEAffected struct {
+	Vendor   string       `json:"vendor"`
+	Product  string       `json:"product"`
+	Versions []CVEVersion `json:"versions"`
+}
+
+// CVEVersion 是受影响的版本
+type CVEVersion struct {
+	Version string `json:"version"`
+	Status  string `json:"status"`
+}
+
+// CVEProblemType 是漏洞类型
+type CVEProblemType struct {
+	Descriptions []CVEProblemTypeDescription `json:"descriptions"`
+}
+
+// CVEProblemTypeDescription 是漏洞类型的描述，CWE类型带有cweId
+type CVEProblemTypeDescription struct {
+	Lang        string `json:"lang"`
+	Description string `json:"description"`
+	Type        string `json:"type,omitempty"`
+	CweID       string `json:"cweId,omitempty"`
+}
+
+// CVEReference 是参考链接
+type CVEReference struct {
+	URL  string   `json:"url"`
+	Name string   `json:"name,omitempty"`
+	Tags []string `json:"tags,omitempty"`
+}
+
+// CVEMetric 是评分，cxsecurity页面提供CVSS v2评分，部分页面还提供CVSS v3评分
+type CVEMetric struct {
+	CvssV31 *CVECvssV3 `json:"cvssV3_1,omitempty"`
+	CvssV30 *CVECvssV3 `json:"cvssV3_0,omitempty"`
+	CvssV2  *CVECvssV2 `json:"cvssV2_0,omitempty"`
+}
+
+// CVECvssV3 是CVSS v3.x评分
+type CVECvssV3 struct {
+	Version      string  `json:"version"`
+	VectorString string  `json:"vectorString"`
+	BaseScore    float64 `json:"baseScore"`
+	BaseSeverity string  `json:"baseSeverity"`
+}
+
+// CVECvssV2 是CVSS v2评分
+type CVECvssV2 struct {
+	Version      string  `json:"version"`
+	VectorString string  `json:"vectorString"`
+	BaseScore    float64 `json:"baseScore"`
+}
+
+// CVEGenerator 记录生成该记录的工具
+type CVEGenerator struct {
+	Engine string `json:"engine"`
+}
+
+// ToCVERecord 将CVE详情转换为CVE JSON 5.0记录
+// cxsecurity的CVE页面作为第一个参考链接，相关的WLB漏洞作为带有 "exploit" 标签的参考链接输出；
+// 受影响软件没有版本信息，统一输出版本 "n/a"。CVSS v2的各项指标都能识别时才输出metrics。
+//
+// 参数:
+//   - d: CVE详情
+//
+// 返回值:
+//   - *CVERecord: CVE JSON 5.0记录，可以直接用json.Marshal序列化
+//   - error: CVE编号为空时返回错误
+//
+// 示例:
+//
+//	detail, _ := c.CrawlCveDetail("CVE-2007-1411", "")
+//	record, err := export.ToCVERecord(detail)
+func ToCVERecord(d *model.CveDetail) (*CVERecord, error) {
+	if d == nil || d.CveID == "" {
+		return nil, errors.New("CVE编号不能为空")
+	}
+
+	updated := d.Modified
+	if updated.IsZero() {
+		updated = d.Published
+	}
+
+	cna := CVECNAContainer{
+		ProviderMetadata: CVEProviderMetadata{
+			OrgID:       CVERecordOrgID,
+			ShortName:   CVERecordProvider,
+			DateUpdated: cveTimestamp(updated),
+		},
+		Descriptions: []CVEDescription{{Lang: "en", Value: strings.TrimSpace(d.Description)}},
+		Affected:     []CVEAffected{},
+		References:   []CVEReference{},
+		XGenerator:   &CVEGenerator{Engine: "cxsecurity-crawler"},
+	}
+	if cna.Descriptions[0].Value == "" {
+		cna.Descriptions[0].Value = "n/a"
+	}
+
+	seenProducts := make(map[string]bool)
+	for _, sw := range d.AffectedSoftware {
+		vendor, product := strings.TrimSpace(sw.VendorName), strings.TrimSpace(sw.ProductName)
+		if product == "" || seenProducts[vendor+"\x00"+product] {
+			continue
+		}
+		seenProducts[vendor+"\x00"+product] = true
+		if vendor == "" {
+			vendor = "n/a"
+		}
+		cna.Affected = append(cna.Affected, CVEAffected{
+			Vendor:   vendor,
+			Product:  product,
+			Versions: []CVEVersion{{Version: "n/a", Status: "affected"}},
+		})
+	}
+
+	if len(cna.Affected) == 0 {
+		// schema要求affected至少有一项，与MITRE的旧记录一样使用 n/a 占位
+		cna.Affected = append(cna.Affected, CVEAffected{
+			Vendor:   "n/a",
+			Product:  "n/a",
+			Versions: []CVEVersion{{Version: "n/a", Status: "affected"}},
+		})
+	}
+
+	if t := strings.TrimSpace(d.Type); t != "" {
+		desc := CVEProblemTypeDescription{Lang: "en", Description: t, Type: "text"}
+		if cwe := d.CWE(); cwe != "" {
+			desc.Type, desc.CweID = "CWE", cwe
+		} else if cweIDPattern.MatchString(t) {
+			desc.Type, desc.CweID = "CWE", t
+		}
+		cna.ProblemTypes = []CVEProblemType{{Descriptions: []CVEProblemTypeDescription{desc}}}
+	}
+
+	// cxsecurity的CVE页面作为第一个参考链接，保证references不为空
+	source := CVESourceURL + d.CveID + "/"
+	cna.References = append(cna.References, CVEReference{URL: source, Name: CVERecordProvider})
+	seenRefs := map[string]bool{source: true}
+	for _, ref := range d.References {
+		if ref = strings.TrimSpace(ref); ref != "" && !seenRefs[ref] {
+			seenRefs[ref] = true
+			cna.References = append(cna.References, CVEReference{URL: ref})
+		}
+	}
+	for _, v := range d.RelatedVulnerabilities {
+		if v.URL == "" || seenRefs[v.URL] {
+			continue
+		}
+		seenRefs[v.URL] = true
+		cna.References = append(cna.References, CVEReference{URL: v.URL, Name: v.Title, Tags: []string{"exploit"}})
+	}
+
+	if v3 := cvssV3(d); v3 != nil {
+		if v3.Version == "3.0" {
+			cna.Metrics = append(cna.Metrics, CVEMetric{CvssV30: v3})
+		} else {
+			cna.Metrics = append(cna.Metrics, CVEMetric{CvssV31: v3})
+		}
+	}
+	if vector := cvssV2Vector(d); vector != "" && d.CvssBaseScore > 0 {
+		cna.Metrics = append(cna.Metrics, CVEMetric{CvssV2: &CVECvssV2{Version: "2.0", VectorString: vector, BaseScore: d.CvssBaseScore}})
+	}
+
+	return &CVERecord{
+		DataType:    "CVE_RECORD",
+		DataVersion: CVERecordDataVersion,
+		CveMetadata: CVEMetadata{
+			CveID:         d.CveID,
+			AssignerOrgID: CVERecordOrgID,
+			State:         "PUBLISHED",
+			DatePublished: cveTimestamp(d.Published),
+			DateUpdated:   cveTimestamp(updated),
+		},
+		Containers: CVEContainers{CNA: cna},
+	}, nil
+}
+
+// cveTimestamp 将时间格式化为CVE记录使用的UTC时间戳，零值返回空字符串
+func cveTimestamp(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format("2006-01-02T15:04:05.000Z")
+}
+
+// cvssV3 返回页面上的CVSS v3评分，没有向量或基础评分时返回nil
+func cvssV3(d *model.CveDetail) *CVECvssV3 {
+	if d.Cvss3 == nil || d.Cvss3.Vector == "" || d.Cvss3.BaseScore <= 0 {
+		return nil
+	}
+	return &CVECvssV3{
+		Version:      d.Cvss3.Version,
+		VectorString: d.Cvss3.Vector,
+		BaseScore:    d.Cvss3.BaseScore,
+		BaseSeverity: strings.ToUpper(d.Cvss3.Severity),
+	}
+}
+
+// cvssV2Vector 根据页面上的CVSS v2指标生成向量字符串，有无法识别的指标时返回空字符串
+func cvssV2Vector(d *model.CveDetail) string {
+	lookup := func(value string, table map[string]string) string {
+		return table[strings.ToLower(strings.TrimSpace(value))]
+	}
+	impact := map[string]string{"none": "N", "partial": "P", "complete": "C"}
+
+	parts := []string{
+		"AV:" + lookup(d.ExploitRange, map[string]string{"remote": "N", "network": "N", "local": "L", "local network": "A", "adjacent network": "A"}),
+		"AC:" + lookup(d.AttackComplexity, map[string]string{"low": "L", "medium": "M", "high": "H"}),
+		"Au:" + lookup(d.Authentication, map[string]string{"no required": "N", "none": "N", "single": "S", "single system": "S", "multiple": "M", "multiple systems": "M"}),
+		"C:" + lookup(d.ConfidentialityImpact, impact),
+		"I:" + lookup(d.IntegrityImpact, impact),
+		"A:" + lookup(d.AvailabilityImpact, impact),
+	}
+	for _, p := range parts {
+		if strings.HasSuffix(p, ":") {
+			return ""
+		}
+	}
+	return strings.Join(parts, "/")
+}
@@ -0,0 +1,114 @@
+package export
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+func TestToCVERecord(t *testing.T) {
+	detail := &model.CveDetail{
+		CveID:                 "CVE-2007-1411",
+		Published:             time.Date(2007, 3, 10, 0, 0, 0, 0, time.UTC),
+		Modified:              time.Date(2018, 10, 16, 0, 0, 0, 0, time.UTC),
+		Description:           "Buffer overflow in PHP 4.4.6 and earlier",
+		Type:                  "CWE-119",
+		CvssBaseScore:         6.8,
+		ExploitRange:          "Remote",
+		AttackComplexity:      "Medium",
+		Authentication:        "No required",
+		ConfidentialityImpact: "Partial",
+		IntegrityImpact:       "Partial",
+		AvailabilityImpact:    "Partial",
+		AffectedSoftware: []model.AffectedSoftware{
+			{VendorName: "PHP", ProductName: "PHP"},
+			{VendorName: "PHP", ProductName: "PHP"},
+		},
+		References: []string{"http://example.com/advisory", "http://example.com/advisory"},
+		RelatedVulnerabilities: []model.Vulnerability{
+			{Title: "PHP mssql_connect() overflow", URL: "https://cxsecurity.com/issue/WLB-2007030105"},
+		},
+	}
+
+	record, err := ToCVERecord(detail)
+	require.NoError(t, err)
+	assert.Equal(t, "CVE_RECORD", record.DataType)
+	assert.Equal(t, "5.0", record.DataVersion)
+	assert.Equal(t, "CVE-2007-1411", record.CveMetadata.CveID)
+	assert.Equal(t, "2007-03-10T00:00:00.000Z", record.CveMetadata.DatePublished)
+	assert.Equal(t, "2018-10-16T00:00:00.000Z", record.CveMetadata.DateUpdated)
+
+	cna := record.Containers.CNA
+	assert.Equal(t, []CVEAffected{{Vendor: "PHP", Product: "PHP", Versions: []CVEVersion{{Version: "n/a", Status: "affected"}}}}, cna.Affected)
+	assert.Equal(t, "CWE-119", cna.ProblemTypes[0].Descriptions[0].CweID)
+	require.Len(t, cna.References, 3)
+	assert.Equal(t, "https://cxsecurity.com/cveshow/CVE-2007-1411/", cna.References[0].URL)
+	assert.Equal(t, []string{"exploit"}, cna.References[2].Tags)
+	require.Len(t, cna.Metrics, 1)
+	assert.Equal(t, "AV:N/AC:M/Au:N/C:P/I:P/A:P", cna.Metrics[0].CvssV2.VectorString)
+
+	data, err := json.Marshal(record)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"cvssV2_0":{"version":"2.0","vectorString":"AV:N/AC:M/Au:N/C:P/I:P/A:P","baseScore":6.8}`)
+}
+
+func TestToCVERecordMinimal(t *testing.T) {
+	record, err := ToCVERecord(&model.CveDetail{CveID: "CVE-2024-1", Type: "CWE-Other", ExploitRange: "Remote"})
+	require.NoError(t, err)
+
+	cna := record.Containers.CNA
+	assert.Equal(t, "n/a", cna.Descriptions[0].Value)
+	// 非CWE编号的类型作为文本输出
+	assert.Equal(t, "text", cna.ProblemTypes[0].Descriptions[0].Type)
+	assert.Empty(t, cna.ProblemTypes[0].Descriptions[0].CweID)
+	// 指标不完整时不输出metrics
+	assert.Nil(t, cna.Metrics)
+
+	// schema要求affected和references至少有一项
+	assert.Equal(t, "n/a", cna.Affected[0].Product)
+	assert.Len(t, cna.References, 1)
+
+	_, err = ToCVERecord(&model.CveDetail{})
+	assert.Error(t, err)
+}
+
+func TestToCVERecordCvss3(t *testing.T) {
+	detail := &model.CveDetail{
+		CveID: "CVE-2024-2",
+		Cvss3: &model.Cvss3{Version: "3.1", Vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", BaseScore: 9.8, Severity: "Critical"},
+	}
+	record, err := ToCVERecord(detail)
+	require.NoError(t, err)
+	metrics := record.Containers.CNA.Metrics
+	require.Len(t, metrics, 1)
+	assert.Equal(t, &CVECvssV3{Version: "3.1", VectorString: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", BaseScore: 9.8, BaseSeverity: "CRITICAL"}, metrics[0].CvssV31)
+
+	// VEX中v3评分排在v2之前
+	detail.CvssBaseScore = 7.5
+	ratings := vexVulnerability(detail, "in_triage").Ratings
+	require.Len(t, ratings, 2)
+	assert.Equal(t, "CVSSv31", ratings[0].Method)
+	assert.Equal(t, "critical", ratings[0].Severity)
+	assert.Equal(t, "CVSSv2", ratings[1].Method)
+}
+
+func TestToCVERecordCweID(t *testing.T) {
+	// 数字编号优先于类型标签
+	detail := &model.CveDetail{CveID: "CVE-2024-2", Type: "Cross Site Scripting", CweID: 79, CweURL: "https://cxsecurity.com/cwe/CWE-79/"}
+	assert.Equal(t, "CWE-79", detail.CWE())
+
+	record, err := ToCVERecord(detail)
+	require.NoError(t, err)
+	desc := record.Containers.CNA.ProblemTypes[0].Descriptions[0]
+	assert.Equal(t, "CWE", desc.Type)
+	assert.Equal(t, "CWE-79", desc.CweID)
+	assert.Equal(t, "Cross Site Scripting", desc.Description)
+	assert.Equal(t, []int{79}, vexVulnerability(detail, "in_triage").CWEs)
+
+	assert.Empty(t, (&model.CveDetail{Type: "CWE-Other"}).CWE())
+}
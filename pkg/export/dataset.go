@@ -0,0 +1,353 @@
+package export
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/schema"
+)
+
+// 数据集归档中的文件
+const (
+	DatasetManifestFile      = "manifest.json"         // 清单，列出其他文件的大小、记录数和SHA-256
+	DatasetVulnerabilityFile = "vulnerabilities.jsonl" // 漏洞，每行一条，按ID排列
+	DatasetCVEFile           = "cve_details.jsonl"     // CVE详情，每行一条，按CVE编号排列
+)
+
+// datasetNamePattern 限制数据集名称和版本中的字符，它们会出现在文件名中
+var datasetNamePattern = regexp.MustCompile(`^[0-9A-Za-z][0-9A-Za-z._-]*$`)
+
+// Dataset 是要发布的数据集的一个版本
+// 归档为 tar.gz，所有文件位于 <Name>-<Version>/ 目录中：
+//
+//	cxsecurity-dataset-2024.05.01/
+//	  manifest.json
+//	  vulnerabilities.jsonl
+//	  cve_details.jsonl
+//	  schema/vulnerability.json
+//	  schema/cve-detail.json
+//
+// 相同的数据、版本和创建时间生成的归档完全相同，便于复现和校验。
+type Dataset struct {
+	Name            string // 数据集名称，例如 cxsecurity-dataset
+	Version         string // 版本，例如 2024.05.01
+	Created         time.Time
+	Vulnerabilities []model.Vulnerability
+	CVEs            []model.CveDetail
+}
+
+// DatasetManifest 是数据集归档中的 manifest.json
+type DatasetManifest struct {
+	Name            string        `json:"name"`
+	Version         string        `json:"version"`
+	Created         time.Time     `json:"created"`
+	Vulnerabilities int           `json:"vulnerabilities"`           // 漏洞数量
+	CVEs            int           `json:"cve_details"`               // CVE详情数量
+	FirstPublished  *time.Time    `json:"first_published,omitempty"` // 最早的漏洞发布日期
+	LastPublished   *time.Time    `json:"last_published,omitempty"`  // 最新的漏洞发布日期
+	Files           []DatasetFile `json:"files"`                     // 除清单外的所有文件
+}
+
+// DatasetFile 是清单中的一个文件
+type DatasetFile struct {
+	Path    string `json:"path"` // 相对于数据集目录的路径
+	Size    int64  `json:"size"`
+	SHA256  string `json:"sha256"`
+	Records int    `json:"records,omitempty"` // JSONL文件的记录数
+	Schema  string `json:"schema,omitempty"`  // JSONL文件中每条记录对应的Schema文件
+}
+
+// Dir 返回归档中的顶层目录名，也是归档文件名的前缀
+func (d *Dataset) Dir() string {
+	return d.Name + "-" + d.Version
+}
+
+// WriteArchive 校验所有记录符合Schema后，将数据集写为 tar.gz 归档
+//
+// 返回值:
+//   - *DatasetManifest: 写入归档的清单
+//   - error: 名称或版本无效、记录不符合Schema或写入失败时返回错误
+func (d *Dataset) WriteArchive(w io.Writer) (*DatasetManifest, error) {
+	if !datasetNamePattern.MatchString(d.Name) {
+		return nil, fmt.Errorf("无效的数据集名称 %q，只能包含字母、数字、点、下划线和连字符", d.Name)
+	}
+	if !datasetNamePattern.MatchString(d.Version) {
+		return nil, fmt.Errorf("无效的数据集版本 %q，只能包含字母、数字、点、下划线和连字符", d.Version)
+	}
+
+	vulns := make([]model.Vulnerability, len(d.Vulnerabilities))
+	copy(vulns, d.Vulnerabilities)
+	sort.SliceStable(vulns, func(i, j int) bool {
+		if vulns[i].ID != vulns[j].ID {
+			return vulns[i].ID < vulns[j].ID
+		}
+		return vulns[i].URL < vulns[j].URL
+	})
+	cves := make([]model.CveDetail, len(d.CVEs))
+	copy(cves, d.CVEs)
+	sort.SliceStable(cves, func(i, j int) bool { return cves[i].CveID < cves[j].CveID })
+
+	manifest := &DatasetManifest{Name: d.Name, Version: d.Version, Created: d.Created.UTC(), Vulnerabilities: len(vulns), CVEs: len(cves)}
+	var contents [][]byte
+	add := func(name, schemaFile string, records int, data []byte) {
+		sum := sha256.Sum256(data)
+		manifest.Files = append(manifest.Files, DatasetFile{Path: name, Size: int64(len(data)), SHA256: hex.EncodeToString(sum[:]), Records: records, Schema: schemaFile})
+		contents = append(contents, data)
+	}
+
+	var buf bytes.Buffer
+	for i := range vulns {
+		v := &vulns[i]
+		if err := schema.ValidateJSON(schema.Vulnerability, v); err != nil {
+			return nil, fmt.Errorf("漏洞 %s: %w", v.ID, err)
+		}
+		if err := writeJSONLine(&buf, v); err != nil {
+			return nil, err
+		}
+		if !v.Date.IsZero() {
+			date := v.Date
+			if manifest.FirstPublished == nil || date.Before(*manifest.FirstPublished) {
+				manifest.FirstPublished = &date
+			}
+			if manifest.LastPublished == nil || date.After(*manifest.LastPublished) {
+				manifest.LastPublished = &date
+			}
+		}
+	}
+	add(DatasetVulnerabilityFile, "schema/"+schema.Vulnerability+".json", len(vulns), bytes.Clone(buf.Bytes()))
+
+	buf.Reset()
+	for i := range cves {
+		if err := schema.ValidateJSON(schema.CveDetail, &cves[i]); err != nil {
+			return nil, fmt.Errorf("CVE详情 %s: %w", cves[i].CveID, err)
+		}
+		if err := writeJSONLine(&buf, &cves[i]); err != nil {
+			return nil, err
+		}
+	}
+	add(DatasetCVEFile, "schema/"+schema.CveDetail+".json", len(cves), bytes.Clone(buf.Bytes()))
+
+	for _, name := range []string{schema.Vulnerability, schema.CveDetail} {
+		data, err := schema.Get(name)
+		if err != nil {
+			return nil, err
+		}
+		add("schema/"+name+".json", "", 0, data)
+	}
+
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	gz, err := gzip.NewWriterLevel(w, gzip.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	gz.Name = d.Dir() + ".tar"
+	gz.ModTime = manifest.Created
+	tw := tar.NewWriter(gz)
+	write := func(name string, data []byte) error {
+		hdr := &tar.Header{
+			Name:     path.Join(d.Dir(), name),
+			Mode:     0644,
+			Size:     int64(len(data)),
+			ModTime:  manifest.Created,
+			Typeflag: tar.TypeReg,
+			Format:   tar.FormatPAX,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+	// 清单放在最前面，流式读取时可以先拿到文件列表
+	if err := write(DatasetManifestFile, append(manifestData, '\n')); err != nil {
+		return nil, err
+	}
+	for i, f := range manifest.Files {
+		if err := write(f.Path, contents[i]); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// writeJSONLine 将v编码为一行JSON
+func writeJSONLine(w io.Writer, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// VerifyDatasetArchive 读取数据集归档，检查每个文件的大小和SHA-256与清单一致，且没有缺少或多余的文件
+func VerifyDatasetArchive(r io.Reader) (*DatasetManifest, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("读取数据集归档失败: %w", err)
+	}
+	defer gz.Close()
+
+	var manifest *DatasetManifest
+	expected := make(map[string]DatasetFile)
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("读取数据集归档失败: %w", err)
+		}
+		dir, name, ok := strings.Cut(hdr.Name, "/")
+		if !ok {
+			return nil, fmt.Errorf("数据集归档中有不在数据集目录中的文件 %s", hdr.Name)
+		}
+
+		if manifest == nil {
+			if name != DatasetManifestFile {
+				return nil, fmt.Errorf("数据集归档的第一个文件应为 %s，实际为 %s", DatasetManifestFile, hdr.Name)
+			}
+			manifest = &DatasetManifest{}
+			if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+				return nil, fmt.Errorf("解析数据集清单失败: %w", err)
+			}
+			if dir != manifest.Name+"-"+manifest.Version {
+				return nil, fmt.Errorf("数据集目录 %s 与清单中的名称和版本不一致", dir)
+			}
+			for _, f := range manifest.Files {
+				expected[f.Path] = f
+			}
+			continue
+		}
+
+		f, ok := expected[name]
+		if !ok {
+			return nil, fmt.Errorf("数据集归档中有清单中没有的文件 %s", hdr.Name)
+		}
+		delete(expected, name)
+		h := sha256.New()
+		size, err := io.Copy(h, tr)
+		if err != nil {
+			return nil, fmt.Errorf("读取数据集归档失败: %w", err)
+		}
+		if size != f.Size || hex.EncodeToString(h.Sum(nil)) != f.SHA256 {
+			return nil, fmt.Errorf("文件 %s 的大小或SHA-256与清单不一致", hdr.Name)
+		}
+	}
+	if manifest == nil {
+		return nil, errors.New("数据集归档中没有清单")
+	}
+	for name := range expected {
+		return nil, fmt.Errorf("数据集归档中缺少文件 %s", name)
+	}
+	return manifest, nil
+}
+
+// WriteChecksums 以 sha256sum 的格式输出文件的SHA-256，可以用 sha256sum -c 校验
+// sums 的键为文件名，值为十六进制的SHA-256，按文件名排列
+func WriteChecksums(w io.Writer, sums map[string]string) error {
+	names := make([]string, 0, len(sums))
+	for name := range sums {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, err := fmt.Fprintf(w, "%s  %s\n", sums[name], name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParseChecksums 解析 sha256sum 格式的校验和文件，返回文件名到SHA-256的映射
+func ParseChecksums(data []byte) (map[string]string, error) {
+	sums := make(map[string]string)
+	for i, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		sum, name, ok := strings.Cut(strings.TrimSpace(line), " ")
+		name = strings.TrimPrefix(strings.TrimSpace(name), "*")
+		if !ok || len(sum) != sha256.Size*2 || name == "" {
+			return nil, fmt.Errorf("校验和文件第 %d 行格式无效", i+1)
+		}
+		sums[name] = strings.ToLower(sum)
+	}
+	return sums, nil
+}
+
+// ParseSigningKey 解析PEM格式(PKCS#8)的Ed25519私钥，例如 openssl genpkey -algorithm ed25519 生成的密钥
+func ParseSigningKey(data []byte) (ed25519.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("签名密钥不是PEM格式")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("解析签名密钥失败: %w", err)
+	}
+	private, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, errors.New("签名密钥不是Ed25519私钥")
+	}
+	return private, nil
+}
+
+// ParseVerifyKey 解析PEM格式(PKIX)的Ed25519公钥，例如 openssl pkey -pubout 导出的公钥
+func ParseVerifyKey(data []byte) (ed25519.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("公钥不是PEM格式")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("解析公钥失败: %w", err)
+	}
+	public, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, errors.New("公钥不是Ed25519公钥")
+	}
+	return public, nil
+}
+
+// SignChecksums 使用Ed25519私钥对校验和文件签名，返回Base64编码的签名(一行)
+func SignChecksums(key ed25519.PrivateKey, checksums []byte) []byte {
+	return []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(key, checksums)) + "\n")
+}
+
+// VerifyChecksumsSignature 使用Ed25519公钥校验 SignChecksums 生成的签名
+func VerifyChecksumsSignature(key ed25519.PublicKey, checksums, signature []byte) error {
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil {
+		return fmt.Errorf("解析签名失败: %w", err)
+	}
+	if !ed25519.Verify(key, checksums, sig) {
+		return errors.New("签名无效")
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package export
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+func datasetTestData() *Dataset {
+	day := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	return &Dataset{
+		Name:    "cxsecurity-dataset",
+		Version: "2024.05.01",
+		Created: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Vulnerabilities: []model.Vulnerability{
+			{ID: "WLB-2024040002", Title: "B", Date: day.AddDate(0, 0, 3), CVE: "CVE-2024-1111"},
+			{ID: "WLB-2024040001", Title: "A", Date: day},
+		},
+		CVEs: []model.CveDetail{{CveID: "CVE-2024-1111", Description: "test"}},
+	}
+}
+
+// readDatasetArchive 返回归档中每个文件的内容，键为归档中的路径
+func readDatasetArchive(t *testing.T, data []byte) (names []string, files map[string]string) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	files = make(map[string]string)
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		content, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		names = append(names, hdr.Name)
+		files[hdr.Name] = string(content)
+	}
+	return names, files
+}
+
+func TestDatasetWriteArchive(t *testing.T) {
+	d := datasetTestData()
+	var buf bytes.Buffer
+	manifest, err := d.WriteArchive(&buf)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, manifest.Vulnerabilities)
+	assert.Equal(t, 1, manifest.CVEs)
+	assert.Equal(t, time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), *manifest.FirstPublished)
+	assert.Equal(t, time.Date(2024, 4, 4, 0, 0, 0, 0, time.UTC), *manifest.LastPublished)
+	require.Len(t, manifest.Files, 4)
+	assert.Equal(t, DatasetFile{Path: DatasetVulnerabilityFile, Size: manifest.Files[0].Size, SHA256: manifest.Files[0].SHA256, Records: 2, Schema: "schema/vulnerability.json"}, manifest.Files[0])
+
+	names, files := readDatasetArchive(t, buf.Bytes())
+	assert.Equal(t, []string{
+		"cxsecurity-dataset-2024.05.01/manifest.json",
+		"cxsecurity-dataset-2024.05.01/vulnerabilities.jsonl",
+		"cxsecurity-dataset-2024.05.01/cve_details.jsonl",
+		"cxsecurity-dataset-2024.05.01/schema/vulnerability.json",
+		"cxsecurity-dataset-2024.05.01/schema/cve-detail.json",
+	}, names)
+	lines := strings.Split(strings.TrimSpace(files["cxsecurity-dataset-2024.05.01/vulnerabilities.jsonl"]), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], `"id":"WLB-2024040001"`, "按ID排列")
+
+	// 相同的输入生成完全相同的归档
+	var again bytes.Buffer
+	_, err = d.WriteArchive(&again)
+	require.NoError(t, err)
+	assert.Equal(t, buf.Bytes(), again.Bytes())
+
+	verified, err := VerifyDatasetArchive(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, manifest.Files, verified.Files)
+}
+
+func TestDatasetWriteArchiveInvalid(t *testing.T) {
+	d := datasetTestData()
+	d.Version = "../2024"
+	_, err := d.WriteArchive(io.Discard)
+	assert.Error(t, err)
+
+	d = datasetTestData()
+	d.Vulnerabilities[0].DerivedRisk = "Critical"
+	_, err = d.WriteArchive(io.Discard)
+	assert.ErrorContains(t, err, "WLB-2024040002")
+}
+
+func TestVerifyDatasetArchiveTampered(t *testing.T) {
+	var buf bytes.Buffer
+	_, err := datasetTestData().WriteArchive(&buf)
+	require.NoError(t, err)
+	names, files := readDatasetArchive(t, buf.Bytes())
+
+	// 修改一条记录后重新打包，清单不变
+	files[names[1]] = strings.Replace(files[names[1]], `"title":"A"`, `"title":"X"`, 1)
+	var tampered bytes.Buffer
+	gz := gzip.NewWriter(&tampered)
+	tw := tar.NewWriter(gz)
+	for _, name := range names {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(files[name]))}))
+		_, err := tw.Write([]byte(files[name]))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+
+	_, err = VerifyDatasetArchive(&tampered)
+	assert.ErrorContains(t, err, "vulnerabilities.jsonl")
+}
+
+func TestDatasetChecksumsAndSignature(t *testing.T) {
+	sums := map[string]string{
+		"b.tar.gz": strings.Repeat("b", 64),
+		"a.json":   strings.Repeat("a", 64),
+	}
+	var buf bytes.Buffer
+	require.NoError(t, WriteChecksums(&buf, sums))
+	assert.Equal(t, strings.Repeat("a", 64)+"  a.json\n"+strings.Repeat("b", 64)+"  b.tar.gz\n", buf.String())
+	parsed, err := ParseChecksums(buf.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, sums, parsed)
+	_, err = ParseChecksums([]byte("abc  file"))
+	assert.Error(t, err)
+
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	require.NoError(t, err)
+	publicDER, err := x509.MarshalPKIXPublicKey(public)
+	require.NoError(t, err)
+
+	key, err := ParseSigningKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}))
+	require.NoError(t, err)
+	verifyKey, err := ParseVerifyKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}))
+	require.NoError(t, err)
+	_, err = ParseSigningKey([]byte("not a key"))
+	assert.Error(t, err)
+
+	sig := SignChecksums(key, buf.Bytes())
+	assert.NoError(t, VerifyChecksumsSignature(verifyKey, buf.Bytes(), sig))
+	assert.Error(t, VerifyChecksumsSignature(verifyKey, append(buf.Bytes(), 'x'), sig))
+}
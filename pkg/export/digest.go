@@ -0,0 +1,253 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+// DigestOptions 是生成摘要的选项
+type DigestOptions struct {
+	Since time.Time // 统计的起始时间(包含)，零值时不限制
+	Now   time.Time // 统计的截止时间，零值时使用当前时间
+	Top   int       // 每个排行榜输出的条数，默认10条
+
+	Dates model.DateFormat // 报告中日期的显示方式，零值时为UTC的 2006-01-02
+}
+
+// DigestCount 是排行榜中的一项
+type DigestCount struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"` // 引用该CVE或由该作者发布的公告数
+}
+
+// Digest 是一段时间内最值得关注的公告的摘要，用于周报等定期报告
+type Digest struct {
+	Since              time.Time             `json:"since,omitempty"`
+	Until              time.Time             `json:"until"`
+	Total              int                   `json:"total"`               // 统计范围内的公告数(按ID去重)
+	ByRisk             map[string]int        `json:"by_risk"`             // 风险级别 -> 公告数
+	TopVulnerabilities []model.Vulnerability `json:"top_vulnerabilities"` // 风险最高的公告，同级别时较新的在前
+	TopCVEs            []DigestCount         `json:"top_cves"`            // 被最多公告引用的CVE
+	TopAuthors         []DigestCount         `json:"top_authors"`         // 发布公告最多的作者
+
+	dates model.DateFormat
+}
+
+// riskRank 返回风险级别的排序权重，越高越严重
+func riskRank(risk string) int {
+	switch riskLabel(risk) {
+	case "High":
+		return 3
+	case "Med.":
+		return 2
+	case "Low":
+		return 1
+	}
+	return 0
+}
+
+// BuildDigest 统计发布日期在 [Since, Now] 范围内的公告，相同ID的公告只统计一次
+// 没有发布日期的公告无法判断是否在范围内，不会被统计。
+//
+// 示例:
+//
+//	items, _ := crawler.LoadResultFile("exploits.json")
+//	d := export.BuildDigest(items, export.DigestOptions{Since: time.Now().AddDate(0, 0, -7)})
+//	d.WriteMarkdown(os.Stdout)
+func BuildDigest(items []model.Vulnerability, opts DigestOptions) *Digest {
+	if opts.Top <= 0 {
+		opts.Top = 10
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+
+	d := &Digest{Since: opts.Since, Until: opts.Now, ByRisk: make(map[string]int), dates: opts.Dates}
+	cves := make(map[string]int)
+	authors := make(map[string]int)
+	var selected []model.Vulnerability
+	seen := make(map[string]bool)
+	for _, v := range items {
+		if v.Date.IsZero() || v.Date.After(opts.Now) || (!opts.Since.IsZero() && v.Date.Before(opts.Since)) {
+			continue
+		}
+		key := v.ID
+		if key == "" {
+			key = v.URL
+		}
+		if key != "" {
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+		}
+
+		d.Total++
+		d.ByRisk[riskLabel(v.RiskLevel)]++
+		selected = append(selected, v)
+		referenced := make(map[string]bool)
+		for _, id := range cveIDPattern.FindAllString(strings.ToUpper(v.CVE), -1) {
+			if !referenced[id] {
+				referenced[id] = true
+				cves[id]++
+			}
+		}
+		if author := strings.TrimSpace(v.Author); author != "" {
+			authors[author]++
+		}
+	}
+
+	sort.SliceStable(selected, func(i, j int) bool {
+		a, b := selected[i], selected[j]
+		if ra, rb := riskRank(a.RiskLevel), riskRank(b.RiskLevel); ra != rb {
+			return ra > rb
+		}
+		if !a.Date.Equal(b.Date) {
+			return a.Date.After(b.Date)
+		}
+		return a.ID < b.ID
+	})
+	if len(selected) > opts.Top {
+		selected = selected[:opts.Top]
+	}
+	d.TopVulnerabilities = selected
+	d.TopCVEs = topCounts(cves, opts.Top)
+	d.TopAuthors = topCounts(authors, opts.Top)
+	return d
+}
+
+// topCounts 返回数量最多的n项，数量相同时按名称排列
+func topCounts(counts map[string]int, n int) []DigestCount {
+	list := make([]DigestCount, 0, len(counts))
+	for name, count := range counts {
+		list = append(list, DigestCount{Name: name, Count: count})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Count != list[j].Count {
+			return list[i].Count > list[j].Count
+		}
+		return list[i].Name < list[j].Name
+	})
+	if len(list) > n {
+		list = list[:n]
+	}
+	return list
+}
+
+// Period 返回统计范围的文字描述，例如 "2024-01-03 – 2024-01-10"
+func (d *Digest) Period() string {
+	if d.Since.IsZero() {
+		return "until " + d.FormatDate(d.Until)
+	}
+	return d.FormatDate(d.Since) + " – " + d.FormatDate(d.Until)
+}
+
+// FormatDate 按 DigestOptions.Dates 输出日期，零值输出空字符串
+func (d *Digest) FormatDate(t time.Time) string {
+	return d.dates.Format(t)
+}
+
+// digestRisks 是摘要中风险级别的输出顺序
+var digestRisks = []string{"High", "Med.", "Low", "unknown"}
+
+// WriteMarkdown 以Markdown格式输出摘要，可以直接贴到Wiki、Issue或邮件中
+func (d *Digest) WriteMarkdown(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "# CXSecurity digest %s\n\n", d.Period())
+	fmt.Fprintf(bw, "%d advisories published.", d.Total)
+	for _, risk := range digestRisks {
+		if n := d.ByRisk[risk]; n > 0 {
+			fmt.Fprintf(bw, " %s: %d.", risk, n)
+		}
+	}
+	fmt.Fprint(bw, "\n\n## Highest risk\n\n")
+	if len(d.TopVulnerabilities) == 0 {
+		fmt.Fprint(bw, "_None._\n")
+	} else {
+		fmt.Fprint(bw, "| Risk | Date | ID | Title | CVE | Author |\n|---|---|---|---|---|---|\n")
+		for _, v := range d.TopVulnerabilities {
+			id := markdownCell(v.ID)
+			if v.URL != "" {
+				id = "[" + id + "](" + v.URL + ")"
+			}
+			fmt.Fprintf(bw, "| %s | %s | %s | %s | %s | %s |\n", riskLabel(v.RiskLevel), d.FormatDate(v.Date),
+				id, markdownCell(v.Title), markdownCell(v.CVE), markdownCell(v.Author))
+		}
+	}
+	writeMarkdownCounts(bw, "Most referenced CVEs", "CVE", "Advisories", d.TopCVEs)
+	writeMarkdownCounts(bw, "Most active authors", "Author", "Advisories", d.TopAuthors)
+	return bw.Flush()
+}
+
+// writeMarkdownCounts 以两列表格输出一个排行榜
+func writeMarkdownCounts(w io.Writer, title, name, count string, list []DigestCount) {
+	fmt.Fprintf(w, "\n## %s\n\n", title)
+	if len(list) == 0 {
+		fmt.Fprint(w, "_None._\n")
+		return
+	}
+	fmt.Fprintf(w, "| %s | %s |\n|---|---|\n", name, count)
+	for _, c := range list {
+		fmt.Fprintf(w, "| %s | %d |\n", markdownCell(c.Name), c.Count)
+	}
+}
+
+// markdownCell 转义表格单元格中的竖线并去掉换行
+func markdownCell(s string) string {
+	return strings.NewReplacer("|", `\|`, "\r", "", "\n", " ").Replace(strings.TrimSpace(s))
+}
+
+// digestHTML 是HTML摘要的模板，样式内联以便作为邮件正文发送
+var digestHTML = template.Must(template.New("digest").Funcs(template.FuncMap{
+	"risk": riskLabel,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>CXSecurity digest {{.Period}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 1.5em; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+th { background: #f4f4f4; }
+.High { color: #c62828; font-weight: bold; }
+.Med\. { color: #ef6c00; }
+.Low { color: #2e7d32; }
+</style>
+</head>
+<body>
+<h1>CXSecurity digest {{.Period}}</h1>
+<p>{{.Total}} advisories published.{{range $risk := .Risks}}{{with index $.ByRisk $risk}} {{$risk}}: {{.}}.{{end}}{{end}}</p>
+<h2>Highest risk</h2>
+{{if .TopVulnerabilities}}<table>
+<tr><th>Risk</th><th>Date</th><th>ID</th><th>Title</th><th>CVE</th><th>Author</th></tr>
+{{range .TopVulnerabilities}}<tr><td class="{{risk .RiskLevel}}">{{risk .RiskLevel}}</td><td>{{$.FormatDate .Date}}</td><td>{{if .URL}}<a href="{{.URL}}">{{.ID}}</a>{{else}}{{.ID}}{{end}}</td><td>{{.Title}}</td><td>{{.CVE}}</td><td>{{.Author}}</td></tr>
+{{end}}</table>{{else}}<p><em>None.</em></p>{{end}}
+<h2>Most referenced CVEs</h2>
+{{if .TopCVEs}}<table>
+<tr><th>CVE</th><th>Advisories</th></tr>
+{{range .TopCVEs}}<tr><td>{{.Name}}</td><td>{{.Count}}</td></tr>
+{{end}}</table>{{else}}<p><em>None.</em></p>{{end}}
+<h2>Most active authors</h2>
+{{if .TopAuthors}}<table>
+<tr><th>Author</th><th>Advisories</th></tr>
+{{range .TopAuthors}}<tr><td>{{.Name}}</td><td>{{.Count}}</td></tr>
+{{end}}</table>{{else}}<p><em>None.</em></p>{{end}}
+</body>
+</html>
+`))
+
+// WriteHTML 以独立的HTML页面输出摘要，内容经过转义，可以作为邮件正文发送
+func (d *Digest) WriteHTML(w io.Writer) error {
+	return digestHTML.Execute(w, struct {
+		*Digest
+		Risks []string
+	}{d, digestRisks})
+}
@@ -0,0 +1,87 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+func digestItems() []model.Vulnerability {
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	return []model.Vulnerability{
+		{ID: "WLB-1", RiskLevel: "Low", Date: day(9), CVE: "CVE-2024-0001", Author: "alice"},
+		{ID: "WLB-2", RiskLevel: "High", Date: day(5), CVE: "CVE-2024-0001, cve-2024-0002", Author: "bob", Title: "Foo | Bar <script>", URL: "https://cxsecurity.com/issue/WLB-2"},
+		{ID: "WLB-2", RiskLevel: "High", Date: day(5), CVE: "CVE-2024-0001"},
+		{ID: "WLB-3", RiskLevel: "High", Date: day(8), CVE: "CVE-2024-0002 CVE-2024-0002", Author: "alice"},
+		{ID: "WLB-4", RiskLevel: "medium", Date: day(7), Author: "alice"},
+		{ID: "WLB-5", RiskLevel: "High", Date: day(1), CVE: "CVE-2024-0009", Author: "old"},
+		{ID: "WLB-6", RiskLevel: "High"},
+	}
+}
+
+func TestBuildDigest(t *testing.T) {
+	now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	d := BuildDigest(digestItems(), DigestOptions{Since: now.AddDate(0, 0, -7), Now: now, Top: 3})
+
+	assert.Equal(t, 4, d.Total)
+	assert.Equal(t, map[string]int{"High": 2, "Med.": 1, "Low": 1}, d.ByRisk)
+	var ids []string
+	for _, v := range d.TopVulnerabilities {
+		ids = append(ids, v.ID)
+	}
+	assert.Equal(t, []string{"WLB-3", "WLB-2", "WLB-4"}, ids, "按风险级别排列，同级别时较新的在前")
+	assert.Equal(t, []DigestCount{{"CVE-2024-0001", 2}, {"CVE-2024-0002", 2}}, d.TopCVEs)
+	assert.Equal(t, []DigestCount{{"alice", 3}, {"bob", 1}}, d.TopAuthors)
+
+	// 不限制起始时间时统计所有有日期的公告
+	all := BuildDigest(digestItems(), DigestOptions{Now: now})
+	assert.Equal(t, 5, all.Total)
+	assert.Equal(t, "until 2024-01-10", all.Period())
+}
+
+func TestDigestWriteMarkdown(t *testing.T) {
+	now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	d := BuildDigest(digestItems(), DigestOptions{Since: now.AddDate(0, 0, -7), Now: now})
+
+	var buf bytes.Buffer
+	require.NoError(t, d.WriteMarkdown(&buf))
+	out := buf.String()
+	assert.Contains(t, out, "# CXSecurity digest 2024-01-03 – 2024-01-10")
+	assert.Contains(t, out, "4 advisories published. High: 2. Med.: 1. Low: 1.")
+	assert.Contains(t, out, "| High | 2024-01-05 | [WLB-2](https://cxsecurity.com/issue/WLB-2) | Foo \\| Bar <script> |")
+	assert.Contains(t, out, "| CVE-2024-0001 | 2 |")
+	assert.Contains(t, out, "| alice | 3 |")
+
+	buf.Reset()
+	require.NoError(t, BuildDigest(nil, DigestOptions{Now: now}).WriteMarkdown(&buf))
+	assert.Contains(t, buf.String(), "0 advisories published.")
+	assert.Contains(t, buf.String(), "_None._")
+}
+
+func TestDigestWriteHTML(t *testing.T) {
+	now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	d := BuildDigest(digestItems(), DigestOptions{Since: now.AddDate(0, 0, -7), Now: now})
+
+	var buf bytes.Buffer
+	require.NoError(t, d.WriteHTML(&buf))
+	out := buf.String()
+	assert.Contains(t, out, "<title>CXSecurity digest 2024-01-03 – 2024-01-10</title>")
+	assert.Contains(t, out, "4 advisories published. High: 2. Med.: 1. Low: 1.")
+	assert.Contains(t, out, `<a href="https://cxsecurity.com/issue/WLB-2">WLB-2</a>`)
+	assert.Contains(t, out, "Foo | Bar &lt;script&gt;")
+	assert.NotContains(t, out, "<script>")
+	assert.Contains(t, out, "<td>alice</td><td>3</td>")
+
+	// 按指定的时区和格式显示日期
+	dates, err := model.ParseDateFormat("America/New_York", "eu")
+	require.NoError(t, err)
+	buf.Reset()
+	require.NoError(t, BuildDigest(digestItems(), DigestOptions{Since: now.AddDate(0, 0, -7), Now: now, Dates: dates}).WriteHTML(&buf))
+	assert.Contains(t, buf.String(), "<title>CXSecurity digest 03.01.2024 – 10.01.2024</title>")
+	assert.Contains(t, buf.String(), "<td>04.01.2024</td>", "UTC零点在纽约是前一天")
+}
@@ -0,0 +1,115 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+// githubLevels 是风险级别对应的GitHub Actions注释级别
+var githubLevels = map[string]string{
+	"High": "error",
+	"Med.": "warning",
+	"Low":  "notice",
+}
+
+// githubData 转义工作流命令的消息部分
+func githubData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// githubProperty 转义工作流命令的属性值，除消息部分的字符外还需要转义 ":" 和 ","
+func githubProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
+
+// WriteGitHubAnnotations 为每个漏洞输出一条GitHub Actions工作流命令，在运行页面上显示为注释
+// High为 ::error，Med.为 ::warning，Low和无法识别的级别为 ::notice；没有标签时使用推断的级别。
+//
+// 示例:
+//
+//	// ::error title=WLB-2024040015 (High)::WordPress Plugin SQL Injection - https://cxsecurity.com/issue/WLB-2024040015
+//	export.WriteGitHubAnnotations(os.Stdout, items)
+func WriteGitHubAnnotations(w io.Writer, items []model.Vulnerability) error {
+	bw := bufio.NewWriter(w)
+	for i := range items {
+		v := &items[i]
+		risk := crawler.EffectiveRisk(v)
+		level, ok := githubLevels[risk]
+		if !ok {
+			level = "notice"
+		}
+		title := v.ID
+		if title == "" {
+			title = v.URL
+		}
+		title += " (" + risk + ")"
+
+		message := strings.TrimSpace(v.Title)
+		if v.CVE != "" {
+			message += " [" + strings.TrimSpace(v.CVE) + "]"
+		}
+		if v.URL != "" {
+			message += " - " + v.URL
+		}
+		fmt.Fprintf(bw, "::%s title=%s::%s\n", level, githubProperty(title), githubData(message))
+	}
+	return bw.Flush()
+}
+
+// WriteGitHubSummary 以Markdown输出漏洞列表，写入 $GITHUB_STEP_SUMMARY 后显示在运行的摘要页面上
+// 风险高的在前，同级别时较新的在前；title为摘要的标题，dates为日期的显示方式
+func WriteGitHubSummary(w io.Writer, title string, items []model.Vulnerability, dates model.DateFormat) error {
+	sorted := make([]model.Vulnerability, len(items))
+	copy(sorted, items)
+	for i := range sorted {
+		crawler.DeriveRisk(&sorted[i])
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if ra, rb := riskRank(a.DerivedRisk), riskRank(b.DerivedRisk); ra != rb {
+			return ra > rb
+		}
+		if !a.Date.Equal(b.Date) {
+			return a.Date.After(b.Date)
+		}
+		return a.ID < b.ID
+	})
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "## %s\n\n", markdownCell(title))
+	if len(sorted) == 0 {
+		fmt.Fprint(bw, "No new findings.\n\n")
+		return bw.Flush()
+	}
+	counts := make(map[string]int)
+	for _, v := range sorted {
+		counts[riskLabel(v.DerivedRisk)]++
+	}
+	fmt.Fprintf(bw, "%d new findings.", len(sorted))
+	for _, risk := range digestRisks {
+		if n := counts[risk]; n > 0 {
+			fmt.Fprintf(bw, " %s: %d.", risk, n)
+		}
+	}
+	fmt.Fprint(bw, "\n\n| Risk | Date | ID | Title | CVE |\n|---|---|---|---|---|\n")
+	for _, v := range sorted {
+		risk := v.DerivedRisk
+		if v.RiskInferred {
+			risk = "~" + risk
+		}
+		date := dates.Format(v.Date)
+		id := markdownCell(v.ID)
+		if v.URL != "" {
+			id = "[" + id + "](" + v.URL + ")"
+		}
+		fmt.Fprintf(bw, "| %s | %s | %s | %s | %s |\n", risk, date, id, markdownCell(v.Title), markdownCell(v.CVE))
+	}
+	fmt.Fprintln(bw)
+	return bw.Flush()
+}
@@ -0,0 +1,46 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+func TestWriteGitHubAnnotations(t *testing.T) {
+	items := []model.Vulnerability{
+		{ID: "WLB-1", Title: "Plugin SQL Injection", RiskLevel: "High", CVE: "CVE-2024-1", URL: "https://cxsecurity.com/issue/WLB-1"},
+		{ID: "WLB-2", Title: "100% XSS\nsecond line", RiskLevel: "med"},
+		{ID: "WLB-3", Title: "Banner"},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, WriteGitHubAnnotations(&buf, items))
+	assert.Equal(t, "::error title=WLB-1 (High)::Plugin SQL Injection [CVE-2024-1] - https://cxsecurity.com/issue/WLB-1\n"+
+		"::warning title=WLB-2 (Med.)::100%25 XSS%0Asecond line\n"+
+		"::notice title=WLB-3 (Low)::Banner\n", buf.String())
+
+	assert.Equal(t, "a%3Ab%2Cc%25", githubProperty("a:b,c%"))
+}
+
+func TestWriteGitHubSummary(t *testing.T) {
+	items := []model.Vulnerability{
+		{ID: "WLB-1", Title: "Banner", RiskLevel: "Low", Date: time.Date(2024, 4, 2, 0, 0, 0, 0, time.UTC)},
+		{ID: "WLB-2", Title: "Remote Code Execution | Plugin", URL: "https://cxsecurity.com/issue/WLB-2"},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, WriteGitHubSummary(&buf, "CXSecurity: wordpress", items, model.DateFormat{}))
+	out := buf.String()
+	assert.Contains(t, out, "## CXSecurity: wordpress\n\n2 new findings. High: 1. Low: 1.\n")
+	assert.Contains(t, out, "| ~High |  | [WLB-2](https://cxsecurity.com/issue/WLB-2) | Remote Code Execution \\| Plugin |  |\n"+
+		"| Low | 2024-04-02 | WLB-1 | Banner |  |\n")
+	// 不修改传入的切片
+	assert.Empty(t, items[1].DerivedRisk)
+
+	buf.Reset()
+	require.NoError(t, WriteGitHubSummary(&buf, "CXSecurity", nil, model.DateFormat{}))
+	assert.Equal(t, "## CXSecurity\n\nNo new findings.\n\n", buf.String())
+}
@@ -0,0 +1,296 @@
+// Package export 将爬取到的漏洞数据转换为其他工具使用的格式
+package export
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+// 知识图谱中的节点类型
+const (
+	LabelAuthor        = "Author"
+	LabelVulnerability = "Vulnerability"
+	LabelCVE           = "CVE"
+	LabelProduct       = "Product"
+)
+
+// 知识图谱中的关系类型
+const (
+	RelPublished  = "PUBLISHED"  // Author -> Vulnerability
+	RelReferences = "REFERENCES" // Vulnerability -> CVE
+	RelAffects    = "AFFECTS"    // CVE -> Product
+)
+
+// cveIDPattern 用于从漏洞的CVE字段中提取CVE编号，一个字段中可能有多个编号
+var cveIDPattern = regexp.MustCompile(`CVE-\d{4}-\d+`)
+
+// GraphNode 是知识图谱中的节点
+type GraphNode struct {
+	ID         string            // 节点ID，在同类型节点中唯一，例如漏洞ID、CVE编号
+	Label      string            // 节点类型，例如 LabelVulnerability
+	Properties map[string]string // 节点属性，空值不输出
+}
+
+// GraphEdge 是知识图谱中的有向边
+type GraphEdge struct {
+	From string // 起点的节点键，见Graph.key
+	To   string // 终点的节点键
+	Type string // 关系类型，例如 RelPublished
+}
+
+// Graph 是由漏洞、作者、CVE和受影响产品组成的知识图谱
+// 结构为 Author-PUBLISHED->Vulnerability-REFERENCES->CVE-AFFECTS->Product，
+// 可以导出为Cypher语句导入Neo4j，或导出为GraphML在Gephi等工具中查看。
+// 重复添加的节点会合并属性，重复的边只保留一条，输出顺序与添加顺序一致。
+type Graph struct {
+	nodes     []*GraphNode
+	nodeIndex map[string]*GraphNode
+	edges     []GraphEdge
+	edgeIndex map[GraphEdge]bool
+}
+
+// NewGraph 创建一个空的知识图谱
+func NewGraph() *Graph {
+	return &Graph{
+		nodeIndex: make(map[string]*GraphNode),
+		edgeIndex: make(map[GraphEdge]bool),
+	}
+}
+
+// key 返回节点在图中的唯一键，不同类型的节点ID可以相同
+func (g *Graph) key(label, id string) string {
+	return label + ":" + id
+}
+
+// Nodes 返回图中的所有节点，按添加顺序排列
+func (g *Graph) Nodes() []*GraphNode {
+	return g.nodes
+}
+
+// Edges 返回图中的所有边，按添加顺序排列
+func (g *Graph) Edges() []GraphEdge {
+	return g.edges
+}
+
+// addNode 添加节点并返回节点键，节点已存在时只补充原来为空的属性
+func (g *Graph) addNode(label, id string, props map[string]string) string {
+	k := g.key(label, id)
+	node, ok := g.nodeIndex[k]
+	if !ok {
+		node = &GraphNode{ID: id, Label: label, Properties: make(map[string]string)}
+		g.nodeIndex[k] = node
+		g.nodes = append(g.nodes, node)
+	}
+	for name, value := range props {
+		if value != "" && node.Properties[name] == "" {
+			node.Properties[name] = value
+		}
+	}
+	return k
+}
+
+// addEdge 添加一条边，重复的边会被忽略
+func (g *Graph) addEdge(from, to, rel string) {
+	e := GraphEdge{From: from, To: to, Type: rel}
+	if g.edgeIndex[e] {
+		return
+	}
+	g.edgeIndex[e] = true
+	g.edges = append(g.edges, e)
+}
+
+// AddVulnerability 添加一条漏洞，以及它的作者和引用的CVE
+// 没有ID和URL的漏洞无法唯一标识，会被忽略。
+func (g *Graph) AddVulnerability(v model.Vulnerability) {
+	id := v.ID
+	if id == "" {
+		id = v.URL
+	}
+	if id == "" {
+		return
+	}
+
+	props := map[string]string{
+		"title":      v.Title,
+		"url":        v.URL,
+		"risk_level": v.RiskLevel,
+		"cwe":        v.CWE,
+	}
+	if !v.Date.IsZero() {
+		props["date"] = v.Date.Format("2006-01-02")
+	}
+	if v.IsRemote {
+		props["remote"] = "true"
+	}
+	if v.IsLocal {
+		props["local"] = "true"
+	}
+	vuln := g.addNode(LabelVulnerability, id, props)
+
+	if author := strings.TrimSpace(v.Author); author != "" {
+		a := g.addNode(LabelAuthor, author, map[string]string{"name": author, "url": v.AuthorURL})
+		g.addEdge(a, vuln, RelPublished)
+	}
+	for _, cveID := range cveIDPattern.FindAllString(v.CVE, -1) {
+		c := g.addNode(LabelCVE, cveID, nil)
+		g.addEdge(vuln, c, RelReferences)
+	}
+}
+
+// AddCveDetail 添加一条CVE详情，以及受影响的产品和相关漏洞
+// 相关漏洞即使CVE字段为空，也会与该CVE建立REFERENCES关系。
+func (g *Graph) AddCveDetail(d *model.CveDetail) {
+	if d == nil || d.CveID == "" {
+		return
+	}
+
+	props := map[string]string{
+		"description": d.Description,
+		"type":        d.Type,
+	}
+	if !d.Published.IsZero() {
+		props["published"] = d.Published.Format("2006-01-02")
+	}
+	if d.CvssBaseScore > 0 {
+		props["cvss_base_score"] = fmt.Sprintf("%.1f", d.CvssBaseScore)
+	}
+	cve := g.addNode(LabelCVE, d.CveID, props)
+
+	for _, sw := range d.AffectedSoftware {
+		name := productName(sw)
+		if name == "" {
+			continue
+		}
+		p := g.addNode(LabelProduct, name, map[string]string{
+			"vendor":  sw.VendorName,
+			"product": sw.ProductName,
+			"url":     sw.ProductURL,
+		})
+		g.addEdge(cve, p, RelAffects)
+	}
+
+	for _, v := range d.RelatedVulnerabilities {
+		if v.ID == "" && v.URL == "" {
+			continue
+		}
+		g.AddVulnerability(v)
+		id := v.ID
+		if id == "" {
+			id = v.URL
+		}
+		g.addEdge(g.key(LabelVulnerability, id), cve, RelReferences)
+	}
+}
+
+// productName 返回产品节点的ID，格式为 "厂商 产品"
+func productName(sw model.AffectedSoftware) string {
+	vendor := strings.TrimSpace(sw.VendorName)
+	product := strings.TrimSpace(sw.ProductName)
+	switch {
+	case vendor == "":
+		return product
+	case product == "":
+		return vendor
+	case strings.HasPrefix(strings.ToLower(product), strings.ToLower(vendor)):
+		// 产品名称已经包含厂商名称，例如 "Apache HTTP Server"
+		return product
+	}
+	return vendor + " " + product
+}
+
+// sortedKeys 返回按字母顺序排列的属性名
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// cypherString 将字符串转换为Cypher字符串字面量
+func cypherString(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return `"` + r.Replace(s) + `"`
+}
+
+// WriteCypher 将图导出为Cypher语句，每条语句一行，以分号结尾
+// 使用MERGE而不是CREATE，重复导入同一份数据或多次导入有重叠的数据时不会产生重复的节点和边。
+// 可以通过 cypher-shell < graph.cypher 导入Neo4j。
+func (g *Graph) WriteCypher(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for _, label := range []string{LabelAuthor, LabelVulnerability, LabelCVE, LabelProduct} {
+		fmt.Fprintf(bw, "CREATE CONSTRAINT IF NOT EXISTS FOR (n:%s) REQUIRE n.id IS UNIQUE;\n", label)
+	}
+
+	for _, n := range g.nodes {
+		fmt.Fprintf(bw, "MERGE (n:%s {id: %s})", n.Label, cypherString(n.ID))
+		keys := sortedKeys(n.Properties)
+		for i, k := range keys {
+			sep := ", "
+			if i == 0 {
+				sep = " SET "
+			}
+			fmt.Fprintf(bw, "%sn.%s = %s", sep, k, cypherString(n.Properties[k]))
+		}
+		bw.WriteString(";\n")
+	}
+
+	for _, e := range g.edges {
+		from, to := g.nodeIndex[e.From], g.nodeIndex[e.To]
+		fmt.Fprintf(bw, "MATCH (a:%s {id: %s}), (b:%s {id: %s}) MERGE (a)-[:%s]->(b);\n",
+			from.Label, cypherString(from.ID), to.Label, cypherString(to.ID), e.Type)
+	}
+	return bw.Flush()
+}
+
+// WriteGraphML 将图导出为GraphML文档
+// 节点类型保存在label属性中，关系类型保存在边的label属性中，其余属性各自对应一个<key>。
+func (g *Graph) WriteGraphML(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	escape := func(s string) string {
+		var b strings.Builder
+		xml.EscapeText(&b, []byte(s))
+		return b.String()
+	}
+
+	// 收集所有节点属性名，每个属性对应一个<key>
+	propNames := map[string]string{}
+	for _, n := range g.nodes {
+		for k := range n.Properties {
+			propNames[k] = ""
+		}
+	}
+
+	bw.WriteString(xml.Header)
+	bw.WriteString(`<graphml xmlns="http://graphml.graphdrawing.org/xmlns">` + "\n")
+	bw.WriteString(`  <key id="label" for="all" attr.name="label" attr.type="string"/>` + "\n")
+	for _, k := range sortedKeys(propNames) {
+		fmt.Fprintf(bw, "  <key id=\"%s\" for=\"node\" attr.name=\"%s\" attr.type=\"string\"/>\n", escape(k), escape(k))
+	}
+	bw.WriteString(`  <graph id="cxsecurity" edgedefault="directed">` + "\n")
+
+	for _, n := range g.nodes {
+		fmt.Fprintf(bw, "    <node id=\"%s\">\n", escape(g.key(n.Label, n.ID)))
+		fmt.Fprintf(bw, "      <data key=\"label\">%s</data>\n", escape(n.Label))
+		for _, k := range sortedKeys(n.Properties) {
+			fmt.Fprintf(bw, "      <data key=\"%s\">%s</data>\n", escape(k), escape(n.Properties[k]))
+		}
+		bw.WriteString("    </node>\n")
+	}
+	for i, e := range g.edges {
+		fmt.Fprintf(bw, "    <edge id=\"e%d\" source=\"%s\" target=\"%s\">\n", i, escape(e.From), escape(e.To))
+		fmt.Fprintf(bw, "      <data key=\"label\">%s</data>\n", escape(e.Type))
+		bw.WriteString("    </edge>\n")
+	}
+
+	bw.WriteString("  </graph>\n</graphml>\n")
+	return bw.Flush()
+}
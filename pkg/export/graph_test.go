@@ -0,0 +1,102 @@
+package export
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+func testGraph() *Graph {
+	g := NewGraph()
+	g.AddVulnerability(model.Vulnerability{
+		ID:        "WLB-2024040015",
+		Title:     `Apache "Struts" RCE`,
+		Date:      time.Date(2024, 4, 9, 0, 0, 0, 0, time.UTC),
+		RiskLevel: "High",
+		CVE:       "CVE-2024-1111, CVE-2024-2222",
+		Author:    "researcher",
+		IsRemote:  true,
+	})
+	g.AddCveDetail(&model.CveDetail{
+		CveID:         "CVE-2024-1111",
+		CvssBaseScore: 9.8,
+		AffectedSoftware: []model.AffectedSoftware{
+			{VendorName: "Apache", ProductName: "Struts"},
+			{VendorName: "Apache", ProductName: "Apache Tomcat"},
+		},
+		RelatedVulnerabilities: []model.Vulnerability{
+			{ID: "WLB-2024040015"},
+			{ID: "WLB-2024040099", Author: "researcher"},
+		},
+	})
+	return g
+}
+
+func TestGraph(t *testing.T) {
+	g := testGraph()
+
+	var labels []string
+	for _, n := range g.Nodes() {
+		labels = append(labels, n.Label+":"+n.ID)
+	}
+	assert.Equal(t, []string{
+		"Vulnerability:WLB-2024040015",
+		"Author:researcher",
+		"CVE:CVE-2024-1111",
+		"CVE:CVE-2024-2222",
+		"Product:Apache Struts",
+		"Product:Apache Tomcat",
+		"Vulnerability:WLB-2024040099",
+	}, labels)
+
+	// 重复的边只保留一条
+	assert.Len(t, g.Edges(), 7)
+	assert.Equal(t, "9.8", g.nodeIndex["CVE:CVE-2024-1111"].Properties["cvss_base_score"])
+
+	// 没有ID和URL的漏洞被忽略
+	g.AddVulnerability(model.Vulnerability{Title: "no id"})
+	assert.Len(t, g.Nodes(), 7)
+}
+
+func TestWriteCypher(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, testGraph().WriteCypher(&buf))
+	out := buf.String()
+
+	assert.Contains(t, out, "CREATE CONSTRAINT IF NOT EXISTS FOR (n:Vulnerability) REQUIRE n.id IS UNIQUE;\n")
+	assert.Contains(t, out, `MERGE (n:Vulnerability {id: "WLB-2024040015"}) SET n.date = "2024-04-09", n.remote = "true", n.risk_level = "High", n.title = "Apache \"Struts\" RCE";`)
+	assert.Contains(t, out, `MATCH (a:Author {id: "researcher"}), (b:Vulnerability {id: "WLB-2024040015"}) MERGE (a)-[:PUBLISHED]->(b);`)
+	assert.Contains(t, out, `MATCH (a:CVE {id: "CVE-2024-1111"}), (b:Product {id: "Apache Tomcat"}) MERGE (a)-[:AFFECTS]->(b);`)
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		assert.True(t, strings.HasSuffix(line, ";"), line)
+	}
+}
+
+func TestWriteGraphML(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, testGraph().WriteGraphML(&buf))
+
+	var doc struct {
+		Graph struct {
+			Nodes []struct {
+				ID string `xml:"id,attr"`
+			} `xml:"node"`
+			Edges []struct {
+				Source string `xml:"source,attr"`
+				Target string `xml:"target,attr"`
+			} `xml:"edge"`
+		} `xml:"graph"`
+	}
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+	assert.Len(t, doc.Graph.Nodes, 7)
+	assert.Len(t, doc.Graph.Edges, 7)
+	assert.Equal(t, "Author:researcher", doc.Graph.Edges[0].Source)
+	assert.Contains(t, buf.String(), "Apache &#34;Struts&#34; RCE")
+}
@@ -0,0 +1,186 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+// MetricsOptions 是生成Prometheus指标的选项
+type MetricsOptions struct {
+	Days    int       // 按天统计的天数(包含当天)，默认30天
+	TopTags int       // 按标签统计时只输出数量最多的标签，默认50个，用于控制标签基数
+	Now     time.Time // 统计截止的时间，零值时使用当前时间
+}
+
+// AdvisoryStats 是公告的聚合统计
+type AdvisoryStats struct {
+	Total         int                       // 公告总数(按ID去重)
+	ByRisk        map[string]int            // 风险级别 -> 公告数
+	ByDay         map[string]map[string]int // 日期(2006-01-02) -> 风险级别 -> 公告数，只包含统计范围内的日期
+	ByTag         map[string]int            // 标签 -> 公告数
+	LastPublished time.Time                 // 最新公告的发布日期
+}
+
+// riskLabel 返回用作指标标签的风险级别，无法识别时为 "unknown"
+func riskLabel(risk string) string {
+	if r := crawler.NormalizeRiskLevel(risk); r != "" {
+		return r
+	}
+	return "unknown"
+}
+
+// advisoryTags 返回公告的标签：页面上的其他标签以及 remote/local，全部转换为小写
+func advisoryTags(v model.Vulnerability) []string {
+	var tags []string
+	for _, tag := range v.Tags {
+		if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	if v.IsRemote {
+		tags = append(tags, "remote")
+	}
+	if v.IsLocal {
+		tags = append(tags, "local")
+	}
+	return tags
+}
+
+// ComputeAdvisoryStats 统计公告数量，相同ID的公告只统计一次
+func ComputeAdvisoryStats(items []model.Vulnerability, opts MetricsOptions) *AdvisoryStats {
+	if opts.Days <= 0 {
+		opts.Days = 30
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	today := time.Date(opts.Now.Year(), opts.Now.Month(), opts.Now.Day(), 0, 0, 0, 0, time.UTC)
+	since := today.AddDate(0, 0, -(opts.Days - 1))
+
+	stats := &AdvisoryStats{
+		ByRisk: make(map[string]int),
+		ByDay:  make(map[string]map[string]int),
+		ByTag:  make(map[string]int),
+	}
+	seen := make(map[string]bool)
+	for _, v := range items {
+		key := v.ID
+		if key == "" {
+			key = v.URL
+		}
+		if key != "" {
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+		}
+
+		stats.Total++
+		risk := riskLabel(v.RiskLevel)
+		stats.ByRisk[risk]++
+		for _, tag := range advisoryTags(v) {
+			stats.ByTag[tag]++
+		}
+		if v.Date.IsZero() {
+			continue
+		}
+		if v.Date.After(stats.LastPublished) {
+			stats.LastPublished = v.Date
+		}
+		day := time.Date(v.Date.Year(), v.Date.Month(), v.Date.Day(), 0, 0, 0, 0, time.UTC)
+		if day.Before(since) || day.After(today) {
+			continue
+		}
+		date := day.Format("2006-01-02")
+		if stats.ByDay[date] == nil {
+			stats.ByDay[date] = make(map[string]int)
+		}
+		stats.ByDay[date][risk]++
+	}
+	return stats
+}
+
+// WritePrometheus 以Prometheus文本格式输出公告统计，可以直接作为 /metrics 的响应
+// 输出的指标:
+//   - cxsecurity_advisories{risk}: 各风险级别的公告数
+//   - cxsecurity_advisories_daily{date,risk}: 最近若干天每天发布的公告数，没有公告的日期输出0
+//   - cxsecurity_advisories_by_tag{tag}: 各标签的公告数，只输出数量最多的标签
+//   - cxsecurity_advisory_last_published_timestamp_seconds: 最新公告的发布时间
+//
+// 示例:
+//
+//	items, _ := crawler.LoadResultFile("exploits.json")
+//	export.WritePrometheus(w, items, export.MetricsOptions{Days: 7})
+func WritePrometheus(w io.Writer, items []model.Vulnerability, opts MetricsOptions) error {
+	if opts.Days <= 0 {
+		opts.Days = 30
+	}
+	if opts.TopTags <= 0 {
+		opts.TopTags = 50
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	stats := ComputeAdvisoryStats(items, opts)
+
+	bw := bufio.NewWriter(w)
+	risks := []string{"High", "Med.", "Low", "unknown"}
+
+	writeHeader(bw, "cxsecurity_advisories", "Number of advisories by risk level.")
+	for _, risk := range risks {
+		fmt.Fprintf(bw, "cxsecurity_advisories{risk=%s} %d\n", promLabel(risk), stats.ByRisk[risk])
+	}
+
+	writeHeader(bw, "cxsecurity_advisories_daily", "Number of advisories published per day by risk level.")
+	today := time.Date(opts.Now.Year(), opts.Now.Month(), opts.Now.Day(), 0, 0, 0, 0, time.UTC)
+	for i := opts.Days - 1; i >= 0; i-- {
+		date := today.AddDate(0, 0, -i).Format("2006-01-02")
+		for _, risk := range risks {
+			fmt.Fprintf(bw, "cxsecurity_advisories_daily{date=%s,risk=%s} %d\n", promLabel(date), promLabel(risk), stats.ByDay[date][risk])
+		}
+	}
+
+	writeHeader(bw, "cxsecurity_advisories_by_tag", "Number of advisories by tag.")
+	tags := make([]string, 0, len(stats.ByTag))
+	for tag := range stats.ByTag {
+		tags = append(tags, tag)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if stats.ByTag[tags[i]] != stats.ByTag[tags[j]] {
+			return stats.ByTag[tags[i]] > stats.ByTag[tags[j]]
+		}
+		return tags[i] < tags[j]
+	})
+	if len(tags) > opts.TopTags {
+		tags = tags[:opts.TopTags]
+	}
+	for _, tag := range tags {
+		fmt.Fprintf(bw, "cxsecurity_advisories_by_tag{tag=%s} %d\n", promLabel(tag), stats.ByTag[tag])
+	}
+
+	writeHeader(bw, "cxsecurity_advisory_last_published_timestamp_seconds", "Publication time of the newest advisory.")
+	var last int64
+	if !stats.LastPublished.IsZero() {
+		last = stats.LastPublished.Unix()
+	}
+	fmt.Fprintf(bw, "cxsecurity_advisory_last_published_timestamp_seconds %d\n", last)
+
+	return bw.Flush()
+}
+
+// writeHeader 输出指标的HELP和TYPE行，所有指标都是gauge
+func writeHeader(w io.Writer, name, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+}
+
+// promLabel 按Prometheus文本格式转义标签值并加上引号
+func promLabel(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value) + `"`
+}
@@ -0,0 +1,57 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+func TestComputeAdvisoryStats(t *testing.T) {
+	now := time.Date(2024, 1, 10, 15, 0, 0, 0, time.UTC)
+	items := []model.Vulnerability{
+		{ID: "WLB-1", RiskLevel: "High", Date: time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), IsRemote: true, Tags: []string{"WordPress"}},
+		{ID: "WLB-1", RiskLevel: "High", Date: time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)},
+		{ID: "WLB-2", RiskLevel: "medium", Date: time.Date(2024, 1, 9, 0, 0, 0, 0, time.UTC), IsLocal: true},
+		{ID: "WLB-3", Date: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), IsRemote: true},
+	}
+
+	stats := ComputeAdvisoryStats(items, MetricsOptions{Days: 7, Now: now})
+	assert.Equal(t, 3, stats.Total)
+	assert.Equal(t, map[string]int{"High": 1, "Med.": 1, "unknown": 1}, stats.ByRisk)
+	assert.Equal(t, map[string]int{"remote": 2, "local": 1, "wordpress": 1}, stats.ByTag)
+	assert.Equal(t, 1, stats.ByDay["2024-01-10"]["High"])
+	assert.Equal(t, 1, stats.ByDay["2024-01-09"]["Med."])
+	assert.NotContains(t, stats.ByDay, "2023-01-01", "超出统计范围的日期不应计入")
+	assert.Equal(t, time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), stats.LastPublished)
+}
+
+func TestWritePrometheus(t *testing.T) {
+	now := time.Date(2024, 1, 10, 15, 0, 0, 0, time.UTC)
+	items := []model.Vulnerability{
+		{ID: "WLB-1", RiskLevel: "High", Date: time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), Tags: []string{`a"b`, "x"}},
+		{ID: "WLB-2", RiskLevel: "Low", Date: time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC), Tags: []string{"x"}},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WritePrometheus(&buf, items, MetricsOptions{Days: 3, TopTags: 1, Now: now}))
+	out := buf.String()
+
+	assert.Contains(t, out, "# TYPE cxsecurity_advisories gauge\n")
+	assert.Contains(t, out, `cxsecurity_advisories{risk="High"} 1`)
+	assert.Contains(t, out, `cxsecurity_advisories{risk="Med."} 0`)
+	assert.Contains(t, out, `cxsecurity_advisories_daily{date="2024-01-09",risk="High"} 0`)
+	assert.Contains(t, out, `cxsecurity_advisories_daily{date="2024-01-08",risk="Low"} 1`)
+	assert.NotContains(t, out, `date="2024-01-07"`)
+	assert.Contains(t, out, `cxsecurity_advisories_by_tag{tag="x"} 2`)
+	assert.NotContains(t, out, `a\"b`, "只输出数量最多的标签")
+	assert.Contains(t, out, "cxsecurity_advisory_last_published_timestamp_seconds 1704844800\n")
+}
+
+func TestPromLabel(t *testing.T) {
+	assert.Equal(t, `"a\"b\\c\nd"`, promLabel("a\"b\\c\nd"))
+}
@@ -0,0 +1,138 @@
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+// RSSChannel 是RSS频道的基本信息
+type RSSChannel struct {
+	Title       string    // 频道标题
+	Link        string    // 频道对应的网页，例如作者主页
+	Description string    // 频道描述
+	FeedURL     string    // 订阅地址本身，为空时不输出 atom:link
+	Updated     time.Time // 上次爬取的时间，零值时不输出
+}
+
+type rssDocument struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Atom    string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	Self          *rssLink  `xml:"atom:link,omitempty"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link,omitempty"`
+	Description string   `xml:"description,omitempty"`
+	Category    []string `xml:"category,omitempty"`
+	GUID        rssGUID  `xml:"guid"`
+	PubDate     string   `xml:"pubDate,omitempty"`
+}
+
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+// WriteRSS 将漏洞输出为RSS 2.0订阅，可以在阅读器中关注作者或搜索的新发布
+// 条目按发布日期从新到旧排列，GUID使用漏洞ID，同一漏洞重新爬取后不会在阅读器中重复出现。
+//
+// 示例:
+//
+//	export.WriteRSS(w, export.RSSChannel{
+//		Title: "hyp3rlinx",
+//		Link:  "https://cxsecurity.com/author/hyp3rlinx/1/",
+//	}, profile.Vulnerabilities)
+func WriteRSS(w io.Writer, channel RSSChannel, items []model.Vulnerability) error {
+	sorted := make([]model.Vulnerability, len(items))
+	copy(sorted, items)
+	model.SortVulnerabilities(sorted)
+
+	doc := rssDocument{
+		Version: "2.0",
+		Atom:    "http://www.w3.org/2005/Atom",
+		Channel: rssChannel{
+			Title:       channel.Title,
+			Link:        channel.Link,
+			Description: channel.Description,
+			Items:       make([]rssItem, 0, len(sorted)),
+		},
+	}
+	if channel.FeedURL != "" {
+		doc.Channel.Self = &rssLink{Href: channel.FeedURL, Rel: "self", Type: "application/rss+xml"}
+	}
+	if !channel.Updated.IsZero() {
+		doc.Channel.LastBuildDate = channel.Updated.UTC().Format(time.RFC1123Z)
+	}
+	for i := range sorted {
+		doc.Channel.Items = append(doc.Channel.Items, rssItemOf(&sorted[i]))
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("编码RSS失败: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// rssItemOf 将漏洞转换为RSS条目，描述中列出风险级别、CVE和利用方式
+func rssItemOf(v *model.Vulnerability) rssItem {
+	item := rssItem{
+		Title:    strings.TrimSpace(v.Title),
+		Link:     v.URL,
+		Category: v.Tags,
+		GUID:     rssGUID{Value: v.ID},
+	}
+	if item.GUID.Value == "" {
+		item.GUID = rssGUID{Value: v.URL, IsPermaLink: true}
+	}
+	if item.Title == "" {
+		item.Title = item.GUID.Value
+	}
+	if !v.Date.IsZero() {
+		item.PubDate = v.Date.UTC().Format(time.RFC1123Z)
+	}
+
+	var parts []string
+	if risk := crawler.EffectiveRisk(v); risk != "" {
+		parts = append(parts, "Risk: "+risk)
+	}
+	if v.CVE != "" {
+		parts = append(parts, "CVE: "+strings.TrimSpace(v.CVE))
+	}
+	switch {
+	case v.IsRemote:
+		parts = append(parts, "Remote")
+	case v.IsLocal:
+		parts = append(parts, "Local")
+	}
+	item.Description = strings.Join(parts, " | ")
+	return item
+}
@@ -0,0 +1,57 @@
+package export
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+func TestWriteRSS(t *testing.T) {
+	items := []model.Vulnerability{
+		{ID: "WLB-1", Title: "Old & Banner", RiskLevel: "Low", URL: "https://cxsecurity.com/issue/WLB-1", Date: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{ID: "WLB-2", Title: "SQL Injection", RiskLevel: "High", CVE: "CVE-2024-1", IsRemote: true, Tags: []string{"PHP"},
+			URL: "https://cxsecurity.com/issue/WLB-2", Date: time.Date(2024, 4, 2, 0, 0, 0, 0, time.UTC)},
+		{URL: "https://cxsecurity.com/issue/x"},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, WriteRSS(&buf, RSSChannel{
+		Title:   "hyp3rlinx",
+		Link:    "https://cxsecurity.com/author/hyp3rlinx/1/",
+		FeedURL: "http://localhost:8080/api/v1/author/hyp3rlinx/feed",
+		Updated: time.Date(2024, 4, 3, 8, 0, 0, 0, time.UTC),
+	}, items))
+	out := buf.String()
+	assert.Contains(t, out, `<atom:link href="http://localhost:8080/api/v1/author/hyp3rlinx/feed" rel="self" type="application/rss+xml"></atom:link>`)
+	assert.Contains(t, out, "<lastBuildDate>Wed, 03 Apr 2024 08:00:00 +0000</lastBuildDate>")
+	assert.Contains(t, out, "<title>Old &amp; Banner</title>")
+	assert.Contains(t, out, `<guid isPermaLink="false">WLB-2</guid>`)
+	assert.Contains(t, out, `<guid isPermaLink="true">https://cxsecurity.com/issue/x</guid>`)
+
+	var doc rssDocument
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+	require.Len(t, doc.Channel.Items, 3)
+	// 按发布日期从新到旧排列，不修改传入的切片
+	first := doc.Channel.Items[0]
+	assert.Equal(t, "SQL Injection", first.Title)
+	assert.Equal(t, "Risk: High | CVE: CVE-2024-1 | Remote", first.Description)
+	assert.Equal(t, []string{"PHP"}, first.Category)
+	assert.Equal(t, "Tue, 02 Apr 2024 00:00:00 +0000", first.PubDate)
+	assert.Equal(t, "WLB-1", items[0].ID)
+	// 没有标题时使用ID或URL，没有日期时不输出pubDate
+	assert.Equal(t, "https://cxsecurity.com/issue/x", doc.Channel.Items[2].Title)
+	assert.Empty(t, doc.Channel.Items[2].PubDate)
+
+	// 没有条目时仍然是有效的订阅
+	buf.Reset()
+	require.NoError(t, WriteRSS(&buf, RSSChannel{Title: "empty"}, nil))
+	var empty rssDocument
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &empty))
+	assert.Empty(t, empty.Channel.Items)
+	assert.NotContains(t, buf.String(), "atom:link href")
+}
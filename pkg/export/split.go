@@ -0,0 +1,137 @@
+package export
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+// 拆分导出的分组方式
+const (
+	SplitByAuthor  = "author"  // 每个作者一组
+	SplitByProduct = "product" // 每个受影响的厂商和产品一组，根据引用的CVE详情确定
+)
+
+// SplitGroup 是拆分导出中的一组漏洞，通常保存为一个文件交给对应的负责人
+type SplitGroup struct {
+	Name    string                // 作者名称或产品名称，没有作者或产品信息的组为空
+	Vendor  string                // 厂商名称，仅按产品分组时有值
+	Product string                // 产品名称，仅按产品分组时有值
+	Items   []model.Vulnerability // 组内的漏洞，按发布日期从新到旧排列
+}
+
+// CVELookup 按CVE编号返回CVE详情，没有该CVE时第二个返回值为false
+type CVELookup func(cveID string) (model.CveDetail, bool)
+
+// SplitVulnerabilities 将漏洞按作者或受影响的产品分组
+// 同一漏洞(按ID，没有ID时按URL)出现多次时使用最后一次出现的记录。
+// 按产品分组时，漏洞引用的CVE影响多个产品时会出现在每个产品的组中；
+// 没有CVE、CVE详情不存在或CVE详情中没有受影响软件的漏洞放在Name为空的组中。
+//
+// 参数:
+//   - items: 要分组的漏洞
+//   - by: SplitByAuthor 或 SplitByProduct
+//   - cves: 查找CVE详情的函数，按产品分组时必须提供
+//
+// 返回值:
+//   - []SplitGroup: 分组，漏洞多的组在前，同样数量时按名称排列，Name为空的组在最后
+//   - error: 分组方式无效时返回错误
+func SplitVulnerabilities(items []model.Vulnerability, by string, cves CVELookup) ([]SplitGroup, error) {
+	if by != SplitByAuthor && by != SplitByProduct {
+		return nil, fmt.Errorf("不支持的分组方式 %q，可选值：author、product", by)
+	}
+	if by == SplitByProduct && cves == nil {
+		return nil, fmt.Errorf("按产品分组需要CVE详情")
+	}
+
+	groups := make(map[string]*SplitGroup)
+	add := func(key string, group SplitGroup, item model.Vulnerability) {
+		g, ok := groups[key]
+		if !ok {
+			g = &group
+			groups[key] = g
+		}
+		g.Items = append(g.Items, item)
+	}
+
+	for _, item := range latestRecords(items) {
+		if by == SplitByAuthor {
+			name := strings.TrimSpace(item.Author)
+			add(strings.ToLower(name), SplitGroup{Name: name}, item)
+			continue
+		}
+
+		affected := affectedProducts(item, cves)
+		if len(affected) == 0 {
+			add("", SplitGroup{}, item)
+			continue
+		}
+		for _, sw := range affected {
+			vendor, product := strings.TrimSpace(sw.VendorName), strings.TrimSpace(sw.ProductName)
+			add(strings.ToLower(vendor+"\x00"+product), SplitGroup{Name: productName(sw), Vendor: vendor, Product: product}, item)
+		}
+	}
+
+	result := make([]SplitGroup, 0, len(groups))
+	for _, g := range groups {
+		model.SortVulnerabilities(g.Items)
+		result = append(result, *g)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if (a.Name == "") != (b.Name == "") {
+			return b.Name == ""
+		}
+		if len(a.Items) != len(b.Items) {
+			return len(a.Items) > len(b.Items)
+		}
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	})
+	return result, nil
+}
+
+// latestRecords 按ID(没有ID时按URL)去重，保留每个漏洞最后一次出现的记录，顺序为第一次出现的顺序
+func latestRecords(items []model.Vulnerability) []model.Vulnerability {
+	index := make(map[string]int, len(items))
+	result := make([]model.Vulnerability, 0, len(items))
+	for _, item := range items {
+		key := item.ID
+		if key == "" {
+			key = item.URL
+		}
+		if i, ok := index[key]; ok && key != "" {
+			result[i] = item
+			continue
+		}
+		index[key] = len(result)
+		result = append(result, item)
+	}
+	return result
+}
+
+// affectedProducts 返回漏洞引用的所有CVE影响的软件，去掉没有产品名称和重复的条目
+func affectedProducts(item model.Vulnerability, cves CVELookup) []model.AffectedSoftware {
+	var affected []model.AffectedSoftware
+	seen := make(map[string]bool)
+	for _, ref := range cveIDPattern.FindAllString(item.CVE, -1) {
+		id, err := model.ParseCVEID(ref)
+		if err != nil {
+			continue
+		}
+		detail, ok := cves(id.String())
+		if !ok {
+			continue
+		}
+		for _, sw := range detail.AffectedSoftware {
+			key := strings.ToLower(strings.TrimSpace(sw.VendorName) + "\x00" + strings.TrimSpace(sw.ProductName))
+			if strings.TrimSpace(sw.ProductName) == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			affected = append(affected, sw)
+		}
+	}
+	return affected
+}
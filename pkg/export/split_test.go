@@ -0,0 +1,73 @@
+package export
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+func splitTestItems() []model.Vulnerability {
+	day := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	return []model.Vulnerability{
+		{ID: "WLB-1", Title: "Struts RCE", Author: "researcher", CVE: "CVE-2024-1111, CVE-2024-2222", Date: day},
+		{ID: "WLB-2", Title: "httpd DoS", Author: "Researcher", CVE: "CVE-2024-2222", Date: day.AddDate(0, 0, 1)},
+		{ID: "WLB-3", Title: "没有CVE", Author: "hyp3rlinx", Date: day},
+		{ID: "WLB-4", Title: "没有作者", CVE: "CVE-2024-9999", Date: day},
+		// 同一漏洞出现多次时使用最后一次的记录
+		{ID: "WLB-1", Title: "Apache Struts RCE", Author: "researcher", CVE: "CVE-2024-1111", Date: day},
+	}
+}
+
+func splitTestCVEs(id string) (model.CveDetail, bool) {
+	details := map[string]model.CveDetail{
+		"CVE-2024-1111": {CveID: "CVE-2024-1111", AffectedSoftware: []model.AffectedSoftware{
+			{VendorName: "Apache", ProductName: "Struts"},
+			{VendorName: "Apache", ProductName: ""},
+		}},
+		"CVE-2024-2222": {CveID: "CVE-2024-2222", AffectedSoftware: []model.AffectedSoftware{
+			{VendorName: "Apache", ProductName: "Apache HTTP Server"},
+			{VendorName: "apache", ProductName: "struts"},
+		}},
+	}
+	d, ok := details[id]
+	return d, ok
+}
+
+func TestSplitVulnerabilitiesByAuthor(t *testing.T) {
+	groups, err := SplitVulnerabilities(splitTestItems(), SplitByAuthor, nil)
+	require.NoError(t, err)
+	require.Len(t, groups, 3)
+
+	assert.Equal(t, "researcher", groups[0].Name, "作者名称不区分大小写")
+	require.Len(t, groups[0].Items, 2)
+	assert.Equal(t, "WLB-2", groups[0].Items[0].ID, "按发布日期从新到旧排列")
+	assert.Equal(t, "Apache Struts RCE", groups[0].Items[1].Title)
+	assert.Equal(t, "hyp3rlinx", groups[1].Name)
+	assert.Equal(t, "", groups[2].Name, "没有作者的组在最后")
+}
+
+func TestSplitVulnerabilitiesByProduct(t *testing.T) {
+	groups, err := SplitVulnerabilities(splitTestItems(), SplitByProduct, splitTestCVEs)
+	require.NoError(t, err)
+	require.Len(t, groups, 3)
+
+	assert.Equal(t, SplitGroup{Name: "Apache Struts", Vendor: "Apache", Product: "Struts"}, SplitGroup{Name: groups[0].Name, Vendor: groups[0].Vendor, Product: groups[0].Product})
+	require.Len(t, groups[0].Items, 2, "厂商和产品名称不区分大小写")
+	assert.Equal(t, "WLB-2", groups[0].Items[0].ID)
+
+	assert.Equal(t, "Apache HTTP Server", groups[1].Name)
+	require.Len(t, groups[1].Items, 1)
+	assert.Equal(t, "WLB-2", groups[1].Items[0].ID, "WLB-1最后一次出现时只引用了CVE-2024-1111")
+
+	assert.Equal(t, "", groups[2].Name)
+	assert.Len(t, groups[2].Items, 2, "没有CVE或CVE详情的漏洞")
+
+	_, err = SplitVulnerabilities(nil, SplitByProduct, nil)
+	assert.Error(t, err)
+	_, err = SplitVulnerabilities(nil, "cwe", nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,274 @@
+package export
+
+import (
+	"crypto/rand"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+// CycloneDXSpecVersion 是生成的VEX文档使用的CycloneDX规范版本
+const CycloneDXSpecVersion = "1.5"
+
+// VEX分析状态，见CycloneDX规范中的 vulnerability.analysis.state
+const (
+	VEXStateInTriage      = "in_triage"
+	VEXStateExploitable   = "exploitable"
+	VEXStateNotAffected   = "not_affected"
+	VEXStateResolved      = "resolved"
+	VEXStateFalsePositive = "false_positive"
+)
+
+// VEXComponent 是VEX文档中受影响的组件，通常来自关注列表
+type VEXComponent struct {
+	Ref     string // bom-ref，在文档中唯一
+	Name    string // 产品名称
+	Vendor  string // 厂商名称
+	Version string // 正在使用的版本
+	CPE     string // CPE 2.3名称
+}
+
+// VEXMatch 是一对匹配的组件和CVE
+type VEXMatch struct {
+	Component VEXComponent
+	Detail    *model.CveDetail
+}
+
+// CycloneDXBOM 是CycloneDX BOM文档，这里只用于承载VEX信息
+type CycloneDXBOM struct {
+	BOMFormat       string               `json:"bomFormat"`
+	SpecVersion     string               `json:"specVersion"`
+	SerialNumber    string               `json:"serialNumber"`
+	Version         int                  `json:"version"`
+	Metadata        CycloneDXMetadata    `json:"metadata"`
+	Components      []CycloneDXComponent `json:"components"`
+	Vulnerabilities []CycloneDXVuln      `json:"vulnerabilities"`
+}
+
+// CycloneDXMetadata 是文档的元数据
+type CycloneDXMetadata struct {
+	Timestamp string         `json:"timestamp"`
+	Tools     CycloneDXTools `json:"tools"`
+}
+
+// CycloneDXTools 是生成文档的工具
+type CycloneDXTools struct {
+	Components []CycloneDXComponent `json:"components"`
+}
+
+// CycloneDXComponent 是组件
+type CycloneDXComponent struct {
+	Type      string `json:"type"`
+	BOMRef    string `json:"bom-ref,omitempty"`
+	Publisher string `json:"publisher,omitempty"`
+	Name      string `json:"name"`
+	Version   string `json:"version,omitempty"`
+	CPE       string `json:"cpe,omitempty"`
+}
+
+// CycloneDXVuln 是漏洞及其对组件的影响
+type CycloneDXVuln struct {
+	BOMRef      string               `json:"bom-ref"`
+	ID          string               `json:"id"`
+	Source      CycloneDXSource      `json:"source"`
+	References  []CycloneDXReference `json:"references,omitempty"`
+	Ratings     []CycloneDXRating    `json:"ratings,omitempty"`
+	CWEs        []int                `json:"cwes,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Published   string               `json:"published,omitempty"`
+	Updated     string               `json:"updated,omitempty"`
+	Analysis    CycloneDXAnalysis    `json:"analysis"`
+	Affects     []CycloneDXAffect    `json:"affects"`
+}
+
+// CycloneDXSource 是漏洞信息的来源
+type CycloneDXSource struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+// CycloneDXReference 是同一漏洞在其他来源中的编号，这里是相关的WLB漏洞
+type CycloneDXReference struct {
+	ID     string          `json:"id"`
+	Source CycloneDXSource `json:"source"`
+}
+
+// CycloneDXRating 是漏洞评分
+type CycloneDXRating struct {
+	Source   *CycloneDXSource `json:"source,omitempty"`
+	Score    float64          `json:"score"`
+	Severity string           `json:"severity,omitempty"`
+	Method   string           `json:"method"`
+	Vector   string           `json:"vector,omitempty"`
+}
+
+// CycloneDXAnalysis 是VEX分析结果
+type CycloneDXAnalysis struct {
+	State string `json:"state"`
+}
+
+// CycloneDXAffect 指向受影响的组件
+type CycloneDXAffect struct {
+	Ref string `json:"ref"`
+}
+
+// VEXOptions 是生成VEX文档的选项
+type VEXOptions struct {
+	State        string    // 分析状态，为空时使用VEXStateInTriage
+	Timestamp    time.Time // 文档时间，零值时使用当前时间
+	SerialNumber string    // 文档序列号(urn:uuid:...)，为空时随机生成
+}
+
+// ToVEX 根据匹配的组件和CVE生成CycloneDX VEX文档
+// 同一个CVE匹配多个组件时只输出一条漏洞，affects中列出所有组件。
+// cxsecurity只给出受影响的产品，没有版本范围，默认分析状态为in_triage，需要人工确认。
+//
+// 示例:
+//
+//	bom := export.ToVEX(matches, export.VEXOptions{})
+//	data, _ := json.MarshalIndent(bom, "", "  ")
+func ToVEX(matches []VEXMatch, opts VEXOptions) *CycloneDXBOM {
+	if opts.State == "" {
+		opts.State = VEXStateInTriage
+	}
+	if opts.Timestamp.IsZero() {
+		opts.Timestamp = time.Now()
+	}
+	if opts.SerialNumber == "" {
+		opts.SerialNumber = newSerialNumber()
+	}
+
+	bom := &CycloneDXBOM{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  CycloneDXSpecVersion,
+		SerialNumber: opts.SerialNumber,
+		Version:      1,
+		Metadata: CycloneDXMetadata{
+			Timestamp: opts.Timestamp.UTC().Format(time.RFC3339),
+			Tools: CycloneDXTools{Components: []CycloneDXComponent{
+				{Type: "application", Name: "cxsecurity-crawler"},
+			}},
+		},
+		Components:      []CycloneDXComponent{},
+		Vulnerabilities: []CycloneDXVuln{},
+	}
+
+	components := make(map[string]bool)
+	vulns := make(map[string]int)
+	for _, m := range matches {
+		if m.Detail == nil || m.Detail.CveID == "" || m.Component.Ref == "" {
+			continue
+		}
+
+		if !components[m.Component.Ref] {
+			components[m.Component.Ref] = true
+			bom.Components = append(bom.Components, CycloneDXComponent{
+				Type:      "application",
+				BOMRef:    m.Component.Ref,
+				Publisher: m.Component.Vendor,
+				Name:      m.Component.Name,
+				Version:   m.Component.Version,
+				CPE:       m.Component.CPE,
+			})
+		}
+
+		i, ok := vulns[m.Detail.CveID]
+		if !ok {
+			i = len(bom.Vulnerabilities)
+			vulns[m.Detail.CveID] = i
+			bom.Vulnerabilities = append(bom.Vulnerabilities, vexVulnerability(m.Detail, opts.State))
+		}
+		affects := &bom.Vulnerabilities[i].Affects
+		duplicate := false
+		for _, a := range *affects {
+			duplicate = duplicate || a.Ref == m.Component.Ref
+		}
+		if !duplicate {
+			*affects = append(*affects, CycloneDXAffect{Ref: m.Component.Ref})
+		}
+	}
+	return bom
+}
+
+// vexVulnerability 将CVE详情转换为VEX中的漏洞，affects由调用方填充
+func vexVulnerability(d *model.CveDetail, state string) CycloneDXVuln {
+	source := CycloneDXSource{Name: CVERecordProvider, URL: CVESourceURL + d.CveID + "/"}
+	v := CycloneDXVuln{
+		BOMRef:      d.CveID,
+		ID:          d.CveID,
+		Source:      source,
+		Description: strings.TrimSpace(d.Description),
+		Analysis:    CycloneDXAnalysis{State: state},
+		Affects:     []CycloneDXAffect{},
+	}
+	if !d.Published.IsZero() {
+		v.Published = d.Published.UTC().Format(time.RFC3339)
+	}
+	if !d.Modified.IsZero() {
+		v.Updated = d.Modified.UTC().Format(time.RFC3339)
+	}
+	if v3 := cvssV3(d); v3 != nil {
+		method := "CVSSv31"
+		if v3.Version == "3.0" {
+			method = "CVSSv3"
+		}
+		v.Ratings = append(v.Ratings, CycloneDXRating{
+			Source:   &source,
+			Score:    v3.BaseScore,
+			Severity: strings.ToLower(v3.BaseSeverity),
+			Method:   method,
+			Vector:   v3.VectorString,
+		})
+	}
+	if d.CvssBaseScore > 0 {
+		v.Ratings = append(v.Ratings, CycloneDXRating{
+			Source:   &source,
+			Score:    d.CvssBaseScore,
+			Severity: cvssV2Severity(d.CvssBaseScore),
+			Method:   "CVSSv2",
+			Vector:   cvssV2Vector(d),
+		})
+	}
+	if d.CweID > 0 {
+		v.CWEs = []int{d.CweID}
+	} else if id := strings.TrimPrefix(strings.TrimSpace(d.Type), "CWE-"); id != d.Type {
+		if n, err := strconv.Atoi(id); err == nil {
+			v.CWEs = []int{n}
+		}
+	}
+	for _, related := range d.RelatedVulnerabilities {
+		id := related.ID
+		if id == "" {
+			id = model.WLBIDFromURL(related.URL).String()
+		}
+		if id != "" {
+			v.References = append(v.References, CycloneDXReference{ID: id, Source: CycloneDXSource{Name: CVERecordProvider, URL: related.URL}})
+		}
+	}
+	return v
+}
+
+// cvssV2Severity 按NVD的划分返回CVSS v2评分对应的严重程度
+func cvssV2Severity(score float64) string {
+	switch {
+	case score >= 7:
+		return "high"
+	case score >= 4:
+		return "medium"
+	case score > 0:
+		return "low"
+	}
+	return "none"
+}
+
+// newSerialNumber 生成随机的 urn:uuid 序列号(UUID v4)
+func newSerialNumber() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,59 @@
+package export
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+func TestToVEX(t *testing.T) {
+	detail := &model.CveDetail{
+		CveID:         "CVE-2007-1411",
+		Type:          "CWE-119",
+		CvssBaseScore: 6.8,
+		RelatedVulnerabilities: []model.Vulnerability{
+			{URL: "https://cxsecurity.com/issue/WLB-2007030105"},
+		},
+	}
+	php := VEXComponent{Ref: "php", Name: "php", Vendor: "php", Version: "4.4.6"}
+	legacy := VEXComponent{Ref: "legacy-php", Name: "php"}
+
+	bom := ToVEX([]VEXMatch{
+		{Component: php, Detail: detail},
+		{Component: legacy, Detail: detail},
+		{Component: php, Detail: detail},
+		{Component: php, Detail: nil},
+	}, VEXOptions{
+		Timestamp:    time.Date(2024, 4, 9, 0, 0, 0, 0, time.UTC),
+		SerialNumber: "urn:uuid:3e671687-395b-41f5-a30f-a58921a69b79",
+	})
+
+	assert.Equal(t, "CycloneDX", bom.BOMFormat)
+	assert.Equal(t, "1.5", bom.SpecVersion)
+	assert.Equal(t, "2024-04-09T00:00:00Z", bom.Metadata.Timestamp)
+	require.Len(t, bom.Components, 2)
+	assert.Equal(t, "4.4.6", bom.Components[0].Version)
+
+	require.Len(t, bom.Vulnerabilities, 1)
+	v := bom.Vulnerabilities[0]
+	assert.Equal(t, "CVE-2007-1411", v.ID)
+	assert.Equal(t, VEXStateInTriage, v.Analysis.State)
+	assert.Equal(t, []CycloneDXAffect{{Ref: "php"}, {Ref: "legacy-php"}}, v.Affects)
+	assert.Equal(t, []int{119}, v.CWEs)
+	assert.Equal(t, "medium", v.Ratings[0].Severity)
+	assert.Equal(t, "WLB-2007030105", v.References[0].ID)
+
+	data, err := json.Marshal(bom)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"bom-ref":"php"`)
+}
+
+func TestNewSerialNumber(t *testing.T) {
+	assert.Regexp(t, `^urn:uuid:[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, newSerialNumber())
+	assert.NotEqual(t, newSerialNumber(), newSerialNumber())
+}
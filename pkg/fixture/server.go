@@ -0,0 +1,90 @@
+// Package fixture 提供回放归档页面的HTTP服务，用于在不访问cxsecurity的情况下进行端到端测试
+//
+// 服务按请求路径返回 docs/response-examples 中对应类型的页面，例如所有 /issue/ 下的
+// 请求都返回同一个漏洞详情页。将爬虫的基础URL指向服务的地址即可，参见 crawler.WithBaseURL。
+//
+// 使用示例：
+//
+//	server, err := fixture.NewServer("docs/response-examples")
+//	defer server.Close()
+//	c := crawler.NewCrawler(crawler.WithClientOptions(crawler.WithBaseURL(server.URL)))
+package fixture
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Route 是路径前缀和回放页面的对应关系
+type Route struct {
+	Prefix string // 请求路径前缀，例如 /issue/
+	File   string // 页面文件名，相对于页面目录
+}
+
+// Routes 是默认的路由表，与 docs/response-examples 中的文件对应
+var Routes = []Route{
+	{"/exploit/", "list-response.html"},
+	{"/wlb/", "list-response.html"},
+	{"/issue/", "vul-detail-response.html"},
+	{"/cveshow/", "cve-show-detail-response.html"},
+	{"/author/", "author-profile-response.html"},
+	{"/search/", "search-response.html"},
+}
+
+// Server 是回放归档页面的HTTP服务，嵌入的 httptest.Server 提供 URL 和 Close
+type Server struct {
+	*httptest.Server
+	pages map[string]string // 路径前缀 -> 页面内容
+
+	mu       sync.Mutex
+	requests []string
+}
+
+// NewServer 读取dir中Routes对应的页面并启动服务，任一页面不存在时返回错误
+//
+// 参数:
+//   - dir: 页面目录，例如 docs/response-examples
+//
+// 返回值:
+//   - *Server: 已经启动的服务，使用完后需要调用Close
+//   - error: 读取页面失败时返回错误
+func NewServer(dir string) (*Server, error) {
+	s := &Server{pages: make(map[string]string, len(Routes))}
+	for _, route := range Routes {
+		content, err := os.ReadFile(filepath.Join(dir, route.File))
+		if err != nil {
+			return nil, fmt.Errorf("读取回放页面失败: %w", err)
+		}
+		s.pages[route.Prefix] = string(content)
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s, nil
+}
+
+// serveHTTP 返回请求路径对应的页面，未知路径返回404
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests = append(s.requests, r.URL.Path)
+	s.mu.Unlock()
+
+	for _, route := range Routes {
+		if strings.HasPrefix(r.URL.Path, route.Prefix) {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			fmt.Fprint(w, s.pages[route.Prefix])
+			return
+		}
+	}
+	http.NotFound(w, r)
+}
+
+// Requests 返回服务收到的所有请求路径，按请求顺序排列
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
@@ -0,0 +1,49 @@
+package fixture
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+)
+
+const pagesDir = "../../docs/response-examples"
+
+func TestServer(t *testing.T) {
+	server, err := NewServer(pagesDir)
+	require.NoError(t, err)
+	defer server.Close()
+	c := crawler.NewCrawler(crawler.WithClientOptions(crawler.WithBaseURL(server.URL), crawler.WithRetry(0, 0)))
+
+	list, err := c.CrawlSection(crawler.SectionExploit, 2, "")
+	require.NoError(t, err)
+	require.NotEmpty(t, list.Items)
+	assert.Equal(t, "WLB-2007030137", list.Items[0].ID)
+
+	detail, err := c.CrawlVulnerabilityDetail("/issue/WLB-2007030137", "")
+	require.NoError(t, err)
+	assert.NotEmpty(t, detail.Title)
+
+	cve, err := c.CrawlCveDetail("CVE-2007-1411", "")
+	require.NoError(t, err)
+	assert.Equal(t, "CVE-2007-1411", cve.CveID)
+
+	author, err := c.CrawlAuthor("rgod", "")
+	require.NoError(t, err)
+	assert.NotEmpty(t, author.Name)
+
+	resp, err := http.Get(server.URL + "/unknown/1")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	assert.Equal(t, "/exploit/2", server.Requests()[0])
+}
+
+func TestNewServerMissingPage(t *testing.T) {
+	_, err := NewServer(t.TempDir())
+	assert.Error(t, err)
+}
@@ -0,0 +1,81 @@
+package llm
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+// chatServer 返回一个模拟的 /chat/completions 接口，回复content并记录收到的请求
+func chatServer(t *testing.T, content string, got *chatRequest, auth *string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+		if auth != nil {
+			*auth = r.Header.Get("Authorization")
+		}
+		if got != nil {
+			require.NoError(t, json.NewDecoder(r.Body).Decode(got))
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"choices": []map[string]interface{}{{"message": map[string]string{"role": "assistant", "content": content}}},
+		})
+	}))
+}
+
+func TestSummarize(t *testing.T) {
+	var got chatRequest
+	var auth string
+	server := chatServer(t, "```json\n{\"summary\": \"WordPress plugin\\n  SQL injection.\", \"affected_versions\": [\"<= 4.4.6\", \" <= 4.4.6\", \"\"]}\n```", &got, &auth)
+	defer server.Close()
+
+	s := NewSummarizer(server.URL+"/v1/", WithAPIKey("sk-test"), WithModel("gpt-4o-mini"), WithMaxInput(10))
+	summary, err := s.Summarize(model.Vulnerability{Title: "WP Plugin SQLi", CVE: "CVE-2024-1234", Content: "0123456789abcdef"})
+	require.NoError(t, err)
+
+	assert.True(t, summary.AIGenerated)
+	assert.Equal(t, "WordPress plugin SQL injection.", summary.Summary)
+	assert.Equal(t, []string{"<= 4.4.6"}, summary.AffectedVersions)
+	assert.Equal(t, "gpt-4o-mini", summary.Model)
+	assert.WithinDuration(t, time.Now(), summary.GeneratedAt, time.Minute)
+
+	assert.Equal(t, "Bearer sk-test", auth)
+	assert.Equal(t, "gpt-4o-mini", got.Model)
+	assert.Equal(t, "json_object", got.ResponseFormat["type"])
+	require.Len(t, got.Messages, 2)
+	assert.Contains(t, got.Messages[1].Content, "CVE: CVE-2024-1234")
+	assert.Contains(t, got.Messages[1].Content, "0123456789\n[truncated]")
+	assert.NotContains(t, got.Messages[1].Content, "abcdef")
+}
+
+func TestSummarizeErrors(t *testing.T) {
+	s := NewSummarizer("")
+	assert.Equal(t, DefaultModel, s.Model())
+	_, err := s.Summarize(model.Vulnerability{Title: "only title"})
+	assert.ErrorIs(t, err, ErrNoContent)
+
+	server := chatServer(t, "not json", nil, nil)
+	defer server.Close()
+	_, err = NewSummarizer(server.URL + "/v1").Summarize(model.Vulnerability{Description: "x"})
+	assert.ErrorContains(t, err, "没有返回有效的JSON")
+
+	empty := chatServer(t, `{"summary": "  "}`, nil, nil)
+	defer empty.Close()
+	_, err = NewSummarizer(empty.URL + "/v1").Summarize(model.Vulnerability{Description: "x"})
+	assert.ErrorContains(t, err, "摘要为空")
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "model not found", http.StatusNotFound)
+	}))
+	defer failing.Close()
+	_, err = NewSummarizer(failing.URL + "/v1").Summarize(model.Vulnerability{Description: "x"})
+	assert.ErrorContains(t, err, "404")
+	assert.True(t, strings.Contains(err.Error(), "model not found"))
+}
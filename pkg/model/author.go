@@ -7,11 +7,14 @@ import (
 // AuthorProfile 表示作者的个人资料信息
 type AuthorProfile struct {
 	// 基本信息
-	ID            string `json:"id,omitempty"`             // 作者ID
-	Name          string `json:"name,omitempty"`           // 作者名称
-	Country       string `json:"country,omitempty"`        // 国家
-	CountryCode   string `json:"country_code,omitempty"`   // 国家代码
-	ReportedCount int    `json:"reported_count,omitempty"` // 报告数量
+	ID               string `json:"id,omitempty"`                // 作者ID
+	Name             string `json:"name,omitempty"`              // 作者名称
+	Country          string `json:"country,omitempty"`           // 国家英文名称
+	CountryCode      string `json:"country_code,omitempty"`      // ISO 3166-1国家代码
+	CountryLocalized string `json:"country_localized,omitempty"` // 指定语言的国家名称
+	ReportedCount    int    `json:"reported_count,omitempty"`    // 报告数量
+	AvatarURL        string `json:"avatar_url,omitempty"`        // 头像图片地址
+	FlagURL          string `json:"flag_url,omitempty"`          // 国旗图片地址
 
 	// 联系信息
 	Twitter     string `json:"twitter,omitempty"`     // Twitter链接
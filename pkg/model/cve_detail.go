@@ -1,6 +1,9 @@
 package model
 
 import (
+	"maps"
+	"slices"
+	"strconv"
 	"time"
 )
 
@@ -13,13 +16,18 @@ type CveDetail struct {
 	Description string    `json:"description,omitempty"` // 漏洞描述
 
 	// 类型信息
-	Type string `json:"type,omitempty"` // 漏洞类型
+	Type   string `json:"type,omitempty"`    // 漏洞类型，即页面上的CWE标签，例如 CWE-79 或 CWE-Other
+	CweID  int    `json:"cwe_id,omitempty"`  // CWE数字编号，CWE-Other等没有编号的类型为0
+	CweURL string `json:"cwe_url,omitempty"` // cxsecurity上的CWE页面链接
 
 	// CVSS评分
 	CvssBaseScore    float64 `json:"cvss_base_score,omitempty"`    // CVSS基础评分
 	CvssImpactScore  float64 `json:"cvss_impact_score,omitempty"`  // CVSS影响评分
 	CvssExploitScore float64 `json:"cvss_exploit_score,omitempty"` // CVSS可利用性评分
 
+	// CVSS v3评分，只有部分页面提供，没有时为nil
+	Cvss3 *Cvss3 `json:"cvss3,omitempty"`
+
 	// 漏洞属性
 	ExploitRange          string `json:"exploit_range,omitempty"`          // 利用范围
 	AttackComplexity      string `json:"attack_complexity,omitempty"`      // 攻击复杂度
@@ -36,6 +44,52 @@ type CveDetail struct {
 
 	// 相关漏洞
 	RelatedVulnerabilities []Vulnerability `json:"related_vulnerabilities,omitempty"` // 相关漏洞列表
+	RelatedTruncated       bool            `json:"related_truncated,omitempty"`       // 相关漏洞还有分页没有获取
+
+	// 用户声明的额外字段
+	Extras map[string]string `json:"extras,omitempty"` // 按规则文件中的选择器从CVE页面提取的字段，键为规则中的字段名
+
+	// 页面上有但模型中还没有的字段
+	UnknownFields map[string]string `json:"unknown_fields,omitempty"` // CVE页面上未建模的标签和值，键为规范化的标签
+
+	// 同步记录，由CVE详情存储在每次获取时维护
+	LastSeen time.Time  `json:"last_seen,omitempty"` // 最后一次获取的时间
+	Change   *CveChange `json:"change,omitempty"`    // 最近一次发现的修改，从未发现修改时为nil
+	Mirror   string     `json:"mirror,omitempty"`    // 返回详情页的镜像地址，从 --base-url 获取时为空；链接已改写为规范地址
+}
+
+// Score 返回CVE的评分，有CVSS v3评分时优先使用v3的基础评分
+func (d *CveDetail) Score() float64 {
+	if d.Cvss3 != nil && d.Cvss3.BaseScore > 0 {
+		return d.Cvss3.BaseScore
+	}
+	return d.CvssBaseScore
+}
+
+// CveChange 记录重新获取CVE详情时发现的修改，保存修改前的修改日期和评分
+type CveChange struct {
+	DetectedAt       time.Time `json:"detected_at"`                 // 发现修改的时间
+	Fields           []string  `json:"fields"`                      // 发生变化的字段：modified、cvss、description、type、references、affected_software
+	PreviousModified time.Time `json:"previous_modified,omitempty"` // 修改前页面上的修改日期
+	PreviousScore    float64   `json:"previous_score,omitempty"`    // 修改前的评分
+}
+
+// CWE 返回 CWE-<编号> 形式的CWE编号，没有数字编号时返回空字符串
+func (d *CveDetail) CWE() string {
+	if d.CweID <= 0 {
+		return ""
+	}
+	return "CWE-" + strconv.Itoa(d.CweID)
+}
+
+// Cvss3 表示CVSS v3评分
+type Cvss3 struct {
+	Version      string  `json:"version,omitempty"`       // 版本，3.0 或 3.1
+	Vector       string  `json:"vector,omitempty"`        // 向量字符串，例如 CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H
+	BaseScore    float64 `json:"base_score,omitempty"`    // 基础评分
+	ImpactScore  float64 `json:"impact_score,omitempty"`  // 影响评分
+	ExploitScore float64 `json:"exploit_score,omitempty"` // 可利用性评分
+	Severity     string  `json:"severity,omitempty"`      // 严重程度：None、Low、Medium、High、Critical
 }
 
 // AffectedSoftware 表示受影响的软件
@@ -45,3 +99,28 @@ type AffectedSoftware struct {
 	ProductName string `json:"product_name,omitempty"` // 产品名称
 	ProductURL  string `json:"product_url,omitempty"`  // 产品URL
 }
+
+// Clone 返回CVE详情的深拷贝，修改副本的切片、映射和指针字段不会影响原值
+func (d *CveDetail) Clone() *CveDetail {
+	c := *d
+	if d.Cvss3 != nil {
+		cvss3 := *d.Cvss3
+		c.Cvss3 = &cvss3
+	}
+	c.AffectedSoftware = slices.Clone(d.AffectedSoftware)
+	c.References = slices.Clone(d.References)
+	if d.RelatedVulnerabilities != nil {
+		c.RelatedVulnerabilities = make([]Vulnerability, len(d.RelatedVulnerabilities))
+		for i := range d.RelatedVulnerabilities {
+			c.RelatedVulnerabilities[i] = *d.RelatedVulnerabilities[i].Clone()
+		}
+	}
+	c.Extras = maps.Clone(d.Extras)
+	c.UnknownFields = maps.Clone(d.UnknownFields)
+	if d.Change != nil {
+		change := *d.Change
+		change.Fields = slices.Clone(d.Change.Fields)
+		c.Change = &change
+	}
+	return &c
+}
@@ -145,3 +145,31 @@ func TestAffectedSoftwareJSON(t *testing.T) {
 		t.Errorf("产品名称不匹配: 期望 测试产品, 实际 %s", decodedSoftware.ProductName)
 	}
 }
+
+func TestCveDetailClone(t *testing.T) {
+	d := &CveDetail{
+		CveID:                  "CVE-2024-21413",
+		Cvss3:                  &Cvss3{BaseScore: 9.8},
+		AffectedSoftware:       []AffectedSoftware{{VendorName: "Microsoft"}},
+		References:             []string{"https://example.com"},
+		RelatedVulnerabilities: []Vulnerability{{ID: "WLB-2024040015", Tags: []string{"RCE"}}},
+		Extras:                 map[string]string{"vendor": "acme"},
+		Change:                 &CveChange{Fields: []string{"cvss"}},
+	}
+	c := d.Clone()
+	if c.CveID != d.CveID || c.Cvss3.BaseScore != d.Cvss3.BaseScore {
+		t.Fatalf("副本与原值不同: %+v", c)
+	}
+
+	c.Cvss3.BaseScore = 0
+	c.AffectedSoftware[0].VendorName = "changed"
+	c.References[0] = "changed"
+	c.RelatedVulnerabilities[0].Tags[0] = "changed"
+	c.Extras["vendor"] = "changed"
+	c.Change.Fields[0] = "changed"
+
+	if d.Cvss3.BaseScore != 9.8 || d.AffectedSoftware[0].VendorName == "changed" || d.References[0] == "changed" ||
+		d.RelatedVulnerabilities[0].Tags[0] == "changed" || d.Extras["vendor"] == "changed" || d.Change.Fields[0] == "changed" {
+		t.Errorf("修改副本影响了原值: %+v", d)
+	}
+}
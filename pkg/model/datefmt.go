@@ -0,0 +1,76 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultDateLayout 是表格和报告中日期的默认格式
+const DefaultDateLayout = "2006-01-02"
+
+// dateLayoutPresets 是常用日期格式的名称
+var dateLayoutPresets = map[string]string{
+	"iso":     DefaultDateLayout,
+	"rfc3339": time.RFC3339,
+	"us":      "01/02/2006",
+	"eu":      "02.01.2006",
+	"long":    "January 2, 2006",
+	"cn":      "2006年01月02日",
+}
+
+// DateFormat 控制日期在表格和报告中的显示方式
+// 从网站解析的日期以UTC保存，结果文件中的日期始终是UTC的RFC3339格式，不受显示方式影响。
+type DateFormat struct {
+	Location *time.Location // 显示使用的时区，nil时为UTC
+	Layout   string         // Go时间格式，空时为 DefaultDateLayout
+}
+
+// ParseDateFormat 根据时区名称和格式创建显示方式
+//
+// 参数:
+//   - tz: IANA时区名称，例如 Asia/Shanghai；Local 为系统时区；空字符串为UTC
+//   - layout: 格式名称(iso、rfc3339、us、eu、long、cn)或Go时间格式，例如 "02 Jan 2006"
+//
+// 返回值:
+//   - DateFormat: 显示方式
+//   - error: 时区不存在或格式中不包含任何日期和时间元素时返回错误
+func ParseDateFormat(tz, layout string) (DateFormat, error) {
+	var f DateFormat
+	if tz != "" {
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			return f, fmt.Errorf("无效的时区 %q: %w", tz, err)
+		}
+		f.Location = loc
+	}
+	if preset, ok := dateLayoutPresets[strings.ToLower(layout)]; ok {
+		layout = preset
+	}
+	// 不包含任何格式元素的字符串原样输出，通常是拼错了格式名称
+	if layout != "" && time.Date(2001, 2, 3, 4, 5, 6, 0, time.UTC).Format(layout) == layout {
+		return f, fmt.Errorf("无效的日期格式 %q，应为 iso、rfc3339、us、eu、long、cn 或Go时间格式，例如 2006-01-02", layout)
+	}
+	f.Layout = layout
+	return f, nil
+}
+
+// In 返回转换到显示时区的时间
+func (f DateFormat) In(t time.Time) time.Time {
+	if f.Location == nil {
+		return t.UTC()
+	}
+	return t.In(f.Location)
+}
+
+// Format 按显示方式输出日期，零值输出空字符串
+func (f DateFormat) Format(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	layout := f.Layout
+	if layout == "" {
+		layout = DefaultDateLayout
+	}
+	return f.In(t).Format(layout)
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDateFormat(t *testing.T) {
+	ts := time.Date(2024, 3, 24, 22, 30, 0, 0, time.UTC)
+
+	f, err := ParseDateFormat("", "")
+	require.NoError(t, err)
+	assert.Equal(t, "2024-03-24", f.Format(ts))
+	assert.Empty(t, f.Format(time.Time{}))
+
+	f, err = ParseDateFormat("Asia/Shanghai", "rfc3339")
+	require.NoError(t, err)
+	assert.Equal(t, "2024-03-25T06:30:00+08:00", f.Format(ts), "转换到显示时区后可能是第二天")
+
+	f, err = ParseDateFormat("", "EU")
+	require.NoError(t, err)
+	assert.Equal(t, "24.03.2024", f.Format(ts))
+
+	f, err = ParseDateFormat("", "02 Jan 2006")
+	require.NoError(t, err)
+	assert.Equal(t, "24 Mar 2024", f.Format(ts))
+
+	_, err = ParseDateFormat("Mars/Olympus", "")
+	assert.Error(t, err)
+	_, err = ParseDateFormat("", "isoo")
+	assert.Error(t, err)
+}
+
+func TestVulnerabilityJSONDateUTC(t *testing.T) {
+	loc := time.FixedZone("UTC+8", 8*3600)
+	data, err := json.Marshal(Vulnerability{ID: "WLB-2024030001", Date: time.Date(2024, 3, 25, 6, 30, 0, 0, loc)})
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"date":"2024-03-24T22:30:00Z"`, "结果文件中的日期始终为UTC")
+}
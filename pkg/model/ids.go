@@ -0,0 +1,118 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// SiteURL 是cxsecurity网站的地址，漏洞和CVE页面的链接都基于它
+const SiteURL = "https://cxsecurity.com"
+
+// WLBPrefix 是cxsecurity漏洞ID的前缀
+const WLBPrefix = "WLB-"
+
+var (
+	// wlbIDPattern 匹配去掉前缀后的漏洞编号，例如 2024040015 或 2024-0001
+	wlbIDPattern = regexp.MustCompile(`^\d[\d-]*$`)
+
+	// wlbInTextPattern 用于从URL等文本中找出漏洞ID
+	wlbInTextPattern = regexp.MustCompile(`(?i)WLB-(?:WLB-)*(\d[\d-]*)`)
+
+	// cveIDPattern 匹配规范的CVE编号，序号至少4位
+	cveIDPattern = regexp.MustCompile(`^CVE-\d{4}-\d{4,}$`)
+
+	// cveInTextPattern 用于从URL等文本中找出CVE编号
+	cveInTextPattern = regexp.MustCompile(`(?i)CVE-\d{4}-\d{4,}`)
+)
+
+// WLBID 是规范化后的cxsecurity漏洞ID，总是带有 WLB- 前缀，例如 WLB-2024040015
+type WLBID string
+
+// ParseWLBID 解析并规范化漏洞ID
+//
+// 接受带或不带 WLB- 前缀（不区分大小写）的编号，也接受漏洞详情页的链接，
+// 重复的前缀（WLB-WLB-）会被去掉
+//
+// 示例:
+//
+//	id, err := model.ParseWLBID("2024040015")                                  // WLB-2024040015
+//	id, err := model.ParseWLBID("https://cxsecurity.com/issue/WLB-2024040015") // WLB-2024040015
+func ParseWLBID(s string) (WLBID, error) {
+	raw := strings.TrimSpace(s)
+	if strings.Contains(raw, "/") {
+		if id := WLBIDFromURL(raw); id != "" {
+			return id, nil
+		}
+		return "", fmt.Errorf("无效的漏洞ID: %q", s)
+	}
+	number := raw
+	for len(number) >= len(WLBPrefix) && strings.EqualFold(number[:len(WLBPrefix)], WLBPrefix) {
+		number = number[len(WLBPrefix):]
+	}
+	if !wlbIDPattern.MatchString(number) {
+		return "", fmt.Errorf("无效的漏洞ID: %q", s)
+	}
+	return WLBID(WLBPrefix + number), nil
+}
+
+// WLBIDFromURL 从漏洞详情页链接中提取漏洞ID，没有时返回空字符串
+func WLBIDFromURL(url string) WLBID {
+	m := wlbInTextPattern.FindStringSubmatch(url)
+	if m == nil {
+		return ""
+	}
+	return WLBID(WLBPrefix + strings.TrimRight(m[1], "-"))
+}
+
+// String 返回带前缀的漏洞ID
+func (id WLBID) String() string {
+	return string(id)
+}
+
+// Path 返回漏洞详情页相对于网站地址的路径，例如 /issue/WLB-2024040015
+func (id WLBID) Path() string {
+	return "/issue/" + string(id)
+}
+
+// URL 返回漏洞详情页的完整链接
+func (id WLBID) URL() string {
+	return SiteURL + id.Path()
+}
+
+// CVEID 是规范化后的CVE编号，总是大写，例如 CVE-2024-21413
+type CVEID string
+
+// ParseCVEID 解析并规范化CVE编号
+//
+// 编号不区分大小写，也接受CVE详情页等包含CVE编号的链接
+//
+// 示例:
+//
+//	id, err := model.ParseCVEID(" cve-2024-21413 ") // CVE-2024-21413
+func ParseCVEID(s string) (CVEID, error) {
+	raw := strings.TrimSpace(s)
+	if strings.Contains(raw, "/") {
+		raw = cveInTextPattern.FindString(raw)
+	}
+	id := strings.ToUpper(raw)
+	if !cveIDPattern.MatchString(id) {
+		return "", fmt.Errorf("无效的CVE编号: %q", s)
+	}
+	return CVEID(id), nil
+}
+
+// String 返回CVE编号
+func (id CVEID) String() string {
+	return string(id)
+}
+
+// Path 返回CVE详情页相对于网站地址的路径，例如 /cveshow/CVE-2024-21413/
+func (id CVEID) Path() string {
+	return "/cveshow/" + string(id) + "/"
+}
+
+// URL 返回CVE详情页的完整链接
+func (id CVEID) URL() string {
+	return SiteURL + id.Path()
+}
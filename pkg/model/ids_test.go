@@ -0,0 +1,64 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWLBID(t *testing.T) {
+	for _, input := range []string{
+		"WLB-2024040015",
+		"2024040015",
+		" wlb-2024040015 ",
+		"WLB-WLB-2024040015",
+		"https://cxsecurity.com/issue/WLB-2024040015",
+		"/issue/WLB-2024040015/",
+	} {
+		id, err := ParseWLBID(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, WLBID("WLB-2024040015"), id, input)
+	}
+
+	id, err := ParseWLBID("2024-0001")
+	require.NoError(t, err)
+	assert.Equal(t, "WLB-2024-0001", id.String())
+	assert.Equal(t, "/issue/WLB-2024-0001", id.Path())
+	assert.Equal(t, "https://cxsecurity.com/issue/WLB-2024-0001", id.URL())
+
+	for _, input := range []string{"", "WLB-", "abc", "WLB-12a", "../../etc/passwd", "https://cxsecurity.com/exploit/1"} {
+		_, err := ParseWLBID(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestWLBIDFromURL(t *testing.T) {
+	assert.Equal(t, WLBID("WLB-2024040015"), WLBIDFromURL("https://cxsecurity.com/issue/WLB-2024040015"))
+	assert.Equal(t, WLBID("WLB-2024040015"), WLBIDFromURL("https://cxsecurity.com/issue/WLB-2024040015/"))
+	assert.Equal(t, WLBID("WLB-2024040015"), WLBIDFromURL("/issue/WLB-WLB-2024040015"))
+	assert.Empty(t, WLBIDFromURL("https://cxsecurity.com/exploit/1"))
+	assert.Empty(t, WLBIDFromURL(""))
+}
+
+func TestParseCVEID(t *testing.T) {
+	for _, input := range []string{
+		"CVE-2024-21413",
+		" cve-2024-21413 ",
+		"https://cxsecurity.com/cveshow/CVE-2024-21413/",
+	} {
+		id, err := ParseCVEID(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, CVEID("CVE-2024-21413"), id, input)
+	}
+
+	id, err := ParseCVEID("CVE-2007-1411")
+	require.NoError(t, err)
+	assert.Equal(t, "/cveshow/CVE-2007-1411/", id.Path())
+	assert.Equal(t, "https://cxsecurity.com/cveshow/CVE-2007-1411/", id.URL())
+
+	for _, input := range []string{"", "CVE-2024", "CVE-2024-1", "2024-21413", "CVE-2024-21413x", "https://cxsecurity.com/exploit/1"} {
+		_, err := ParseCVEID(input)
+		assert.Error(t, err, input)
+	}
+}
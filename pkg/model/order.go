@@ -0,0 +1,40 @@
+package model
+
+import "sort"
+
+// SortTags 去掉重复的标签并按字母顺序排列，保证两次运行的输出只在标签真正变化时不同
+// 输入为空时原样返回，不改变nil和空切片在JSON中的区别
+func SortTags(tags []string) []string {
+	if len(tags) == 0 {
+		return tags
+	}
+	seen := make(map[string]bool, len(tags))
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if !seen[tag] {
+			seen[tag] = true
+			result = append(result, tag)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// SortVulnerabilities 按发布日期从新到旧排列漏洞，日期相同时按ID(没有ID时为URL)排列
+// 存储和导出使用同样的顺序，对比两次的结果时只会看到真正的变化
+func SortVulnerabilities(items []Vulnerability) {
+	sort.SliceStable(items, func(i, j int) bool {
+		if !items[i].Date.Equal(items[j].Date) {
+			return items[i].Date.After(items[j].Date)
+		}
+		return items[i].sortKey() < items[j].sortKey()
+	})
+}
+
+// sortKey 返回同一日期内排序使用的键
+func (v Vulnerability) sortKey() string {
+	if v.ID != "" {
+		return v.ID
+	}
+	return v.URL
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortTags(t *testing.T) {
+	assert.Equal(t, []string{"CVE", "Local", "Web"}, SortTags([]string{"Web", "CVE", "Local", "Web"}))
+	assert.Nil(t, SortTags(nil))
+	assert.Equal(t, []string{}, SortTags([]string{}))
+
+	// 不修改输入的切片
+	tags := []string{"b", "a"}
+	SortTags(tags)
+	assert.Equal(t, []string{"b", "a"}, tags)
+}
+
+func TestSortVulnerabilities(t *testing.T) {
+	day1 := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	day2 := time.Date(2024, 4, 2, 0, 0, 0, 0, time.UTC)
+	items := []Vulnerability{
+		{ID: "WLB-2024040003", Date: day1},
+		{URL: "https://example.com/b", Date: day2},
+		{ID: "WLB-2024040001", Date: day1},
+		{ID: "WLB-2024040002", Date: day2},
+		{ID: "WLB-2024040009"},
+	}
+	SortVulnerabilities(items)
+
+	var keys []string
+	for _, v := range items {
+		keys = append(keys, v.sortKey())
+	}
+	assert.Equal(t, []string{
+		"WLB-2024040002",
+		"https://example.com/b",
+		"WLB-2024040001",
+		"WLB-2024040003",
+		"WLB-2024040009",
+	}, keys)
+}
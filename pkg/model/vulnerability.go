@@ -2,6 +2,8 @@ package model
 
 import (
 	"encoding/json"
+	"maps"
+	"slices"
 	"time"
 )
 
@@ -14,6 +16,10 @@ type Vulnerability struct {
 	URL       string    `json:"url,omitempty"`        // 漏洞详情页URL
 	RiskLevel string    `json:"risk_level,omitempty"` // 风险级别(High, Med., Low)
 
+	// 规范化的风险级别，没有风险级别标签时根据CVSS分数和漏洞类型推断
+	DerivedRisk  string `json:"derived_risk,omitempty"`  // High、Med. 或 Low
+	RiskInferred bool   `json:"risk_inferred,omitempty"` // DerivedRisk是推断的，而不是页面上的标签
+
 	// CVE和CWE信息
 	CVE string `json:"cve,omitempty"` // CVE编号(如CVE-2024-32113)
 	CWE string `json:"cwe,omitempty"` // CWE编号(如CWE-22)
@@ -28,13 +34,77 @@ type Vulnerability struct {
 	// 作者信息
 	Author    string `json:"author,omitempty"`     // 作者名称
 	AuthorURL string `json:"author_url,omitempty"` // 作者页面URL
+
+	// 热度，列表页显示时才有，可用于排序
+	Comments int `json:"comments,omitempty"` // 评论数
+	Views    int `json:"views,omitempty"`    // 浏览数
+
+	// 正文
+	Content          string   `json:"content,omitempty"`           // 漏洞详情页的正文(公告、PoC代码等原始文本)
+	ContentHTML      string   `json:"content_html,omitempty"`      // 正文的HTML，经过 crawler.SanitizeHTML 清理，可以直接在网页中显示
+	Description      string   `json:"description,omitempty"`       // 从正文中提取的漏洞描述
+	AffectedVersions []string `json:"affected_versions,omitempty"` // 受影响的版本，例如 "<= 4.4.6"
+	Platform         string   `json:"platform,omitempty"`          // 平台，例如 Windows
+
+	// 原始出处
+	Source *SourceAttribution `json:"source,omitempty"` // 公告最初发布的地方，引用时应注明
+
+	// ATT&CK技术
+	Techniques []string `json:"techniques,omitempty"` // MITRE ATT&CK技术编号(如T1190)，仅在启用分类时填充
+
+	// 近似重复分组
+	ClusterID string `json:"cluster_id,omitempty"` // 近似重复公告的分组ID，同一漏洞多次发布时相同
+
+	// AI生成的摘要
+	AISummary *AISummary `json:"ai_summary,omitempty"` // 由大语言模型生成的摘要，仅在运行 summarize 后填充
+
+	// 引用的CVE详情的修改
+	CVEUpdate *CVEUpdate `json:"cve_update,omitempty"` // 重新获取引用的CVE详情时发现的最近一次修改，仅在运行 enrich-cve --refresh 后填充
+
+	// 用户声明的额外字段
+	Extras map[string]string `json:"extras,omitempty"` // 按规则文件中的选择器从详情页提取的字段，键为规则中的字段名
+
+	// 页面上有但模型中还没有的字段
+	UnknownFields map[string]string `json:"unknown_fields,omitempty"` // 详情页上未建模的标签和值，键为规范化的标签，例如 cvss_base_score
+
+	// 本地存储
+	LastSeen time.Time `json:"last_seen,omitempty"` // 最后一次从详情页获取的时间，由读穿透模式的本地存储维护
+	Mirror   string    `json:"mirror,omitempty"`    // 返回详情页的镜像地址，从 --base-url 获取时为空；链接已改写为规范地址
 }
 
-// MarshalJSON 自定义JSON序列化方法，确保零值日期被正确省略
+// SourceAttribution 是公告的原始出处，例如邮件列表或研究人员的博客
+// cxsecurity上的很多公告是转载的，在报告中引用时应同时注明原始出处
+type SourceAttribution struct {
+	Name string `json:"name"` // 出处名称，例如 Full Disclosure；正文中没有写明时为常见出处的名称或链接的主机名
+	URL  string `json:"url"`  // 原始公告的链接
+}
+
+// CVEUpdate 记录漏洞引用的CVE在上次获取之后被修改，便于分析人员发现旧CVE有了新信息
+type CVEUpdate struct {
+	CveID         string    `json:"cve_id"`                   // 被修改的CVE编号
+	Modified      time.Time `json:"modified,omitempty"`       // CVE页面上新的修改日期
+	DetectedAt    time.Time `json:"detected_at"`              // 发现修改的时间
+	Fields        []string  `json:"fields"`                   // 发生变化的字段，与 CveChange.Fields 相同
+	PreviousScore float64   `json:"previous_score,omitempty"` // 修改前的评分
+	Score         float64   `json:"score,omitempty"`          // 修改后的评分
+}
+
+// AISummary 是由大语言模型根据公告正文生成的摘要
+// 内容未经人工核对，可能不准确，显示和导出时应标明是AI生成的
+type AISummary struct {
+	AIGenerated      bool      `json:"ai_generated"`                // 固定为true，标明内容由AI生成
+	Summary          string    `json:"summary"`                     // 一段话的摘要
+	AffectedVersions []string  `json:"affected_versions,omitempty"` // 从正文中提取的受影响版本，与解析出的 affected_versions 分开保存
+	Model            string    `json:"model"`                       // 生成摘要使用的模型
+	GeneratedAt      time.Time `json:"generated_at"`                // 生成时间
+}
+
+// MarshalJSON 自定义JSON序列化方法，确保零值日期和获取时间被正确省略，统一输出为UTC
 func (v Vulnerability) MarshalJSON() ([]byte, error) {
 	type Alias Vulnerability
 	aux := &struct {
-		Date *time.Time `json:"date,omitempty"`
+		Date     *time.Time `json:"date,omitempty"`
+		LastSeen *time.Time `json:"last_seen,omitempty"`
 		*Alias
 	}{
 		Alias: (*Alias)(&v),
@@ -42,7 +112,12 @@ func (v Vulnerability) MarshalJSON() ([]byte, error) {
 
 	// 只有当Date不是零值时才设置
 	if !v.Date.IsZero() {
-		aux.Date = &v.Date
+		date := v.Date.UTC()
+		aux.Date = &date
+	}
+	if !v.LastSeen.IsZero() {
+		lastSeen := v.LastSeen.UTC()
+		aux.LastSeen = &lastSeen
 	}
 
 	return json.Marshal(aux)
@@ -54,3 +129,28 @@ type VulnerabilityList struct {
 	CurrentPage int             `json:"current_page"` // 当前页码
 	TotalPages  int             `json:"total_pages"`  // 总页数
 }
+
+// Clone 返回漏洞的深拷贝，修改副本的切片、映射和指针字段不会影响原值
+func (v *Vulnerability) Clone() *Vulnerability {
+	c := *v
+	c.Tags = slices.Clone(v.Tags)
+	c.AffectedVersions = slices.Clone(v.AffectedVersions)
+	c.Techniques = slices.Clone(v.Techniques)
+	c.Extras = maps.Clone(v.Extras)
+	c.UnknownFields = maps.Clone(v.UnknownFields)
+	if v.Source != nil {
+		source := *v.Source
+		c.Source = &source
+	}
+	if v.AISummary != nil {
+		summary := *v.AISummary
+		summary.AffectedVersions = slices.Clone(v.AISummary.AffectedVersions)
+		c.AISummary = &summary
+	}
+	if v.CVEUpdate != nil {
+		update := *v.CVEUpdate
+		update.Fields = slices.Clone(v.CVEUpdate.Fields)
+		c.CVEUpdate = &update
+	}
+	return &c
+}
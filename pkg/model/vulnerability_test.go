@@ -145,3 +145,36 @@ func TestVulnerabilityListJSON(t *testing.T) {
 		t.Errorf("期望第二个漏洞风险级别: Medium, 实际: %s", decodedList.Items[1].RiskLevel)
 	}
 }
+
+func TestVulnerabilityClone(t *testing.T) {
+	v := &Vulnerability{
+		ID:               "WLB-2024040015",
+		Tags:             []string{"XSS"},
+		AffectedVersions: []string{"<= 4.4.6"},
+		Techniques:       []string{"T1190"},
+		Source:           &SourceAttribution{Name: "Full Disclosure"},
+		AISummary:        &AISummary{Summary: "摘要", AffectedVersions: []string{"4.4"}},
+		CVEUpdate:        &CVEUpdate{CveID: "CVE-2024-1", Fields: []string{"cvss"}},
+		Extras:           map[string]string{"vendor": "acme"},
+		UnknownFields:    map[string]string{"cvss_base_score": "5.0"},
+	}
+	c := v.Clone()
+	if c.ID != v.ID || c.Source.Name != v.Source.Name {
+		t.Fatalf("副本与原值不同: %+v", c)
+	}
+
+	c.Tags[0] = "changed"
+	c.AffectedVersions[0] = "changed"
+	c.Techniques[0] = "changed"
+	c.Source.Name = "changed"
+	c.AISummary.AffectedVersions[0] = "changed"
+	c.CVEUpdate.Fields[0] = "changed"
+	c.Extras["vendor"] = "changed"
+	c.UnknownFields["cvss_base_score"] = "changed"
+
+	if v.Tags[0] == "changed" || v.AffectedVersions[0] == "changed" || v.Techniques[0] == "changed" ||
+		v.Source.Name == "changed" || v.AISummary.AffectedVersions[0] == "changed" || v.CVEUpdate.Fields[0] == "changed" ||
+		v.Extras["vendor"] == "changed" || v.UnknownFields["cvss_base_score"] == "changed" {
+		t.Errorf("修改副本影响了原值: %+v", v)
+	}
+}
@@ -0,0 +1,326 @@
+// Package query 对保存的漏洞数据执行只读的SQL查询
+//
+// 查询由嵌入的SQLite(modernc.org/sqlite，纯Go实现，不需要cgo)执行：每次查询将数据加载到
+// 一个内存数据库的 vulnerabilities 表中，加载后通过 PRAGMA query_only 将连接设为只读，
+// 然后执行SELECT语句。支持SQLite的全部查询语法和内置函数，语义与sqlite3命令行相同。
+package query
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+// Table 是查询的表名
+const Table = "vulnerabilities"
+
+// ErrNotSelect 表示语句不是SELECT语句，查询是只读的
+var ErrNotSelect = errors.New("只支持只读的SELECT语句")
+
+// Column 是表中的一列
+type Column struct {
+	Name        string
+	Type        string // SQLite中的列类型，BOOLEAN列在结果中为bool
+	Description string
+}
+
+// Columns 是vulnerabilities表的所有列，SELECT * 按此顺序输出
+// 空字符串和零值日期为NULL；tags和techniques是逗号分隔的字符串。
+var Columns = []Column{
+	{"id", "TEXT", "漏洞ID (WLB-XXXXXXXXX)"},
+	{"date", "TEXT", "发布日期 (YYYY-MM-DD)"},
+	{"title", "TEXT", "标题"},
+	{"url", "TEXT", "详情页URL"},
+	{"risk_level", "TEXT", "风险级别 (High、Med.、Low)"},
+	{"derived_risk", "TEXT", "规范化的风险级别，没有标签时为推断的级别"},
+	{"risk_inferred", "BOOLEAN", "derived_risk是否为推断的"},
+	{"cve", "TEXT", "CVE编号"},
+	{"cwe", "TEXT", "CWE编号"},
+	{"is_remote", "BOOLEAN", "是否为远程漏洞"},
+	{"is_local", "BOOLEAN", "是否为本地漏洞"},
+	{"tags", "TEXT", "其他标签"},
+	{"author", "TEXT", "作者名称"},
+	{"author_url", "TEXT", "作者页面URL"},
+	{"comments", "INTEGER", "评论数，列表页没有显示时为NULL"},
+	{"views", "INTEGER", "浏览数，列表页没有显示时为NULL"},
+	{"techniques", "TEXT", "ATT&CK技术编号"},
+	{"cluster_id", "TEXT", "近似重复分组ID"},
+	{"description", "TEXT", "从正文中提取的漏洞描述"},
+	{"affected_versions", "TEXT", "受影响的版本，逗号分隔"},
+	{"platform", "TEXT", "平台"},
+	{"ai_summary", "TEXT", "AI生成的摘要，未经人工核对，见 summarize 命令"},
+	{"cve_updated_at", "TEXT", "上次获取后发现引用的CVE被修改的日期 (YYYY-MM-DD)，见 enrich-cve --refresh"},
+	{"cve_modified", "TEXT", "被修改的CVE页面上新的修改日期 (YYYY-MM-DD)"},
+	{"cve_changes", "TEXT", "被修改的CVE发生变化的字段，逗号分隔"},
+}
+
+// nullable 将空字符串转换为NULL
+func nullable(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// nullableCount 将0转换为NULL
+func nullableCount(n int) interface{} {
+	if n == 0 {
+		return nil
+	}
+	return int64(n)
+}
+
+// columnValue 返回漏洞在指定列上的值
+func columnValue(v *model.Vulnerability, name string) interface{} {
+	switch name {
+	case "id":
+		return nullable(v.ID)
+	case "date":
+		if v.Date.IsZero() {
+			return nil
+		}
+		return v.Date.Format("2006-01-02")
+	case "title":
+		return nullable(v.Title)
+	case "url":
+		return nullable(v.URL)
+	case "risk_level":
+		return nullable(v.RiskLevel)
+	case "derived_risk":
+		return nullable(v.DerivedRisk)
+	case "risk_inferred":
+		return v.RiskInferred
+	case "cve":
+		return nullable(v.CVE)
+	case "cwe":
+		return nullable(v.CWE)
+	case "is_remote":
+		return v.IsRemote
+	case "is_local":
+		return v.IsLocal
+	case "tags":
+		return nullable(strings.Join(v.Tags, ","))
+	case "author":
+		return nullable(v.Author)
+	case "author_url":
+		return nullable(v.AuthorURL)
+	case "comments":
+		return nullableCount(v.Comments)
+	case "views":
+		return nullableCount(v.Views)
+	case "techniques":
+		return nullable(strings.Join(v.Techniques, ","))
+	case "cluster_id":
+		return nullable(v.ClusterID)
+	case "description":
+		return nullable(v.Description)
+	case "affected_versions":
+		return nullable(strings.Join(v.AffectedVersions, ","))
+	case "platform":
+		return nullable(v.Platform)
+	case "ai_summary":
+		if v.AISummary == nil {
+			return nil
+		}
+		return nullable(v.AISummary.Summary)
+	case "cve_updated_at":
+		if v.CVEUpdate == nil {
+			return nil
+		}
+		return v.CVEUpdate.DetectedAt.Format("2006-01-02")
+	case "cve_modified":
+		if v.CVEUpdate == nil || v.CVEUpdate.Modified.IsZero() {
+			return nil
+		}
+		return v.CVEUpdate.Modified.Format("2006-01-02")
+	case "cve_changes":
+		if v.CVEUpdate == nil {
+			return nil
+		}
+		return nullable(strings.Join(v.CVEUpdate.Fields, ","))
+	}
+	return nil
+}
+
+// Result 是查询结果
+// 值的类型为 nil、string、bool(BOOLEAN列)、int64(整数)或float64
+type Result struct {
+	Columns []string        `json:"columns"`
+	Rows    [][]interface{} `json:"rows"`
+}
+
+// Records 将结果转换为以列名为键的记录，便于输出为JSON对象数组
+func (r *Result) Records() []map[string]interface{} {
+	records := make([]map[string]interface{}, len(r.Rows))
+	for i, row := range r.Rows {
+		record := make(map[string]interface{}, len(r.Columns))
+		for j, col := range r.Columns {
+			record[col] = row[j]
+		}
+		records[i] = record
+	}
+	return records
+}
+
+// Run 对漏洞执行SQL查询
+//
+// 参数:
+//   - query: SELECT语句，表名为vulnerabilities
+//   - items: 查询的数据，通常来自 crawler.LoadResultFile
+//
+// 返回值:
+//   - *Result: 查询结果
+//   - error: 语法错误、不是SELECT语句或引用了不存在的列时返回错误
+//
+// 示例:
+//
+//	items, _ := crawler.LoadResultFile("exploits.json")
+//	result, err := query.Run("SELECT risk_level, COUNT(*) AS n FROM vulnerabilities GROUP BY risk_level ORDER BY n DESC", items)
+func Run(query string, items []model.Vulnerability) (*Result, error) {
+	if !isSelect(query) {
+		return nil, ErrNotSelect
+	}
+
+	ctx := context.Background()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	// 内存数据库只属于创建它的连接，加载和查询必须使用同一个连接
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := load(ctx, conn, items); err != nil {
+		return nil, fmt.Errorf("加载数据失败: %w", err)
+	}
+	if _, err := conn.ExecContext(ctx, "PRAGMA query_only = ON"); err != nil {
+		return nil, err
+	}
+
+	rows, err := conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, queryError(err)
+	}
+	defer rows.Close()
+
+	result := &Result{Rows: [][]interface{}{}}
+	if result.Columns, err = rows.Columns(); err != nil {
+		return nil, err
+	}
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		values := make([]interface{}, len(result.Columns))
+		dest := make([]interface{}, len(values))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		for i, v := range values {
+			values[i] = normalizeValue(v, types[i].DatabaseTypeName())
+		}
+		result.Rows = append(result.Rows, values)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, queryError(err)
+	}
+	return result, nil
+}
+
+// load 创建vulnerabilities表并在一个事务中插入所有漏洞
+func load(ctx context.Context, conn *sql.Conn, items []model.Vulnerability) error {
+	defs := make([]string, len(Columns))
+	names := make([]string, len(Columns))
+	for i, col := range Columns {
+		defs[i] = col.Name + " " + col.Type
+		names[i] = col.Name
+	}
+	if _, err := conn.ExecContext(ctx, "CREATE TABLE "+Table+" ("+strings.Join(defs, ", ")+")"); err != nil {
+		return err
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	stmt, err := tx.PrepareContext(ctx, "INSERT INTO "+Table+" ("+strings.Join(names, ", ")+") VALUES (?"+strings.Repeat(", ?", len(Columns)-1)+")")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	args := make([]interface{}, len(Columns))
+	for i := range items {
+		for j, col := range Columns {
+			args[j] = columnValue(&items[i], col.Name)
+		}
+		if _, err := stmt.ExecContext(ctx, args...); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// isSelect 判断语句是否以SELECT或WITH开头，跳过开头的空白、注释和括号
+// 以WITH开头的修改语句会被 query_only 拒绝
+func isSelect(query string) bool {
+	s := query
+	for {
+		s = strings.TrimLeft(s, " \t\r\n(")
+		switch {
+		case strings.HasPrefix(s, "--"):
+			i := strings.IndexByte(s, '\n')
+			if i < 0 {
+				return false
+			}
+			s = s[i+1:]
+		case strings.HasPrefix(s, "/*"):
+			i := strings.Index(s, "*/")
+			if i < 0 {
+				return false
+			}
+			s = s[i+2:]
+		default:
+			word, _, _ := strings.Cut(strings.ToUpper(s), " ")
+			word = strings.TrimRight(word, "\t\r\n(")
+			return word == "SELECT" || word == "WITH"
+		}
+	}
+}
+
+// queryError 将SQLite拒绝写入的错误转换为 ErrNotSelect，其他错误原样返回
+func queryError(err error) error {
+	var e *sqlite.Error
+	if errors.As(err, &e) && e.Code()&0xff == sqlite3.SQLITE_READONLY {
+		return fmt.Errorf("%w: %v", ErrNotSelect, err)
+	}
+	return err
+}
+
+// normalizeValue 将BOOLEAN列的值转换为bool，BLOB转换为字符串
+func normalizeValue(v interface{}, typ string) interface{} {
+	switch v := v.(type) {
+	case int64:
+		if typ == "BOOLEAN" {
+			return v != 0
+		}
+	case []byte:
+		return string(v)
+	}
+	return v
+}
@@ -0,0 +1,256 @@
+package query
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+var testItems = []model.Vulnerability{
+	{ID: "WLB-1", Title: "WordPress Plugin SQL Injection", RiskLevel: "High", Date: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), IsRemote: true, Author: "alice", CVE: "CVE-2024-0001"},
+	{ID: "WLB-2", Title: "Linux Kernel Privilege Escalation", RiskLevel: "Med.", Date: time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC), IsLocal: true, Author: "bob"},
+	{ID: "WLB-3", Title: "WordPress Theme XSS", RiskLevel: "Low", Date: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), IsRemote: true, Author: "alice", Tags: []string{"xss", "wordpress"}},
+	{ID: "WLB-4", Title: "Apache RCE", RiskLevel: "High", Date: time.Date(2024, 2, 5, 0, 0, 0, 0, time.UTC), IsRemote: true, Author: "carol"},
+}
+
+func TestRunSelectWhere(t *testing.T) {
+	result, err := Run("select id, title from vulnerabilities where title like '%wordpress%' and is_remote order by date desc", testItems)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"id", "title"}, result.Columns)
+	assert.Equal(t, [][]interface{}{
+		{"WLB-3", "WordPress Theme XSS"},
+		{"WLB-1", "WordPress Plugin SQL Injection"},
+	}, result.Rows)
+
+	result, err = Run("SELECT id FROM vulnerabilities WHERE cve IS NULL AND risk_level IN ('High', 'Low') AND date BETWEEN '2024-02-01' AND '2024-02-28' LIMIT 1 OFFSET 1", testItems)
+	require.NoError(t, err)
+	assert.Equal(t, [][]interface{}{{"WLB-4"}}, result.Rows)
+}
+
+func TestRunAggregate(t *testing.T) {
+	result, err := Run(`SELECT substr(date, 1, 7) AS month, risk_level, COUNT(*) AS n
+		FROM vulnerabilities GROUP BY month, risk_level ORDER BY month, n DESC, risk_level`, testItems)
+	require.NoError(t, err)
+	assert.Equal(t, [][]interface{}{
+		{"2024-01", "High", int64(1)},
+		{"2024-01", "Med.", int64(1)},
+		{"2024-02", "High", int64(1)},
+		{"2024-02", "Low", int64(1)},
+	}, result.Rows)
+
+	result, err = Run(`SELECT substr(date, 1, 7) AS month, COUNT(*) AS n, COUNT(DISTINCT author) AS authors
+		FROM vulnerabilities GROUP BY 1 HAVING n >= 2 ORDER BY 1 DESC`, testItems)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"month", "n", "authors"}, result.Columns)
+	assert.Equal(t, [][]interface{}{
+		{"2024-02", int64(2), int64(2)},
+		{"2024-01", int64(2), int64(2)},
+	}, result.Rows)
+
+	result, err = Run("SELECT COUNT(*), MIN(date), MAX(date), GROUP_CONCAT(DISTINCT author) FROM vulnerabilities WHERE is_remote", testItems)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"COUNT(*)", "MIN(date)", "MAX(date)", "GROUP_CONCAT(DISTINCT author)"}, result.Columns)
+	assert.Equal(t, [][]interface{}{{int64(3), "2024-01-02", "2024-02-05", "alice,carol"}}, result.Rows)
+
+	result, err = Run("SELECT COUNT(*) FROM vulnerabilities WHERE id = 'none'", testItems)
+	require.NoError(t, err)
+	assert.Equal(t, [][]interface{}{{int64(0)}}, result.Rows, "没有行时聚合结果仍有一行")
+}
+
+func TestRunDistinctAndExpressions(t *testing.T) {
+	result, err := Run("SELECT DISTINCT author FROM vulnerabilities ORDER BY author", testItems)
+	require.NoError(t, err)
+	assert.Equal(t, [][]interface{}{{"alice"}, {"bob"}, {"carol"}}, result.Rows)
+
+	result, err = Run(`SELECT upper(author) || ':' || id AS label, length(title) * 2 - 1 AS x, coalesce(cve, 'n/a') cve FROM vulnerabilities WHERE id = 'WLB-2'`, testItems)
+	require.NoError(t, err)
+	assert.Equal(t, [][]interface{}{{"BOB:WLB-2", int64(65), "n/a"}}, result.Rows)
+
+	result, err = Run(`SELECT "id" FROM vulnerabilities WHERE tags LIKE '%xss%' AND NOT is_local;`, testItems)
+	require.NoError(t, err)
+	assert.Equal(t, [][]interface{}{{"WLB-3"}}, result.Rows)
+}
+
+func TestRunSelectStar(t *testing.T) {
+	result, err := Run("SELECT * FROM vulnerabilities LIMIT 1", testItems)
+	require.NoError(t, err)
+	require.Len(t, result.Columns, len(Columns))
+	assert.Equal(t, "WLB-1", result.Rows[0][0])
+	assert.Equal(t, "WLB-1", result.Records()[0]["id"])
+}
+
+func TestRunCVEUpdate(t *testing.T) {
+	items := []model.Vulnerability{
+		{ID: "WLB-1", CVEUpdate: &model.CVEUpdate{CveID: "CVE-2024-0001", Modified: time.Date(2024, 5, 8, 0, 0, 0, 0, time.UTC), DetectedAt: time.Date(2024, 5, 10, 3, 0, 0, 0, time.UTC), Fields: []string{"modified", "cvss"}}},
+		{ID: "WLB-2"},
+	}
+	result, err := Run("SELECT id, cve_updated_at, cve_modified, cve_changes FROM vulnerabilities WHERE cve_updated_at >= '2024-05-01' OR cve_changes IS NULL", items)
+	require.NoError(t, err)
+	assert.Equal(t, [][]interface{}{
+		{"WLB-1", "2024-05-10", "2024-05-08", "modified,cvss"},
+		{"WLB-2", nil, nil, nil},
+	}, result.Rows)
+}
+
+func TestRunPopularity(t *testing.T) {
+	items := []model.Vulnerability{
+		{ID: "WLB-1", Views: 120, Comments: 2},
+		{ID: "WLB-2", Views: 1024},
+		{ID: "WLB-3"},
+	}
+	result, err := Run("SELECT id, views, comments FROM vulnerabilities WHERE views IS NOT NULL ORDER BY views DESC", items)
+	require.NoError(t, err)
+	assert.Equal(t, [][]interface{}{
+		{"WLB-2", int64(1024), nil},
+		{"WLB-1", int64(120), int64(2)},
+	}, result.Rows)
+}
+
+func TestRunErrors(t *testing.T) {
+	for _, sql := range []string{
+		"DELETE FROM vulnerabilities",
+		"DROP TABLE vulnerabilities",
+		"PRAGMA query_only = OFF",
+		"ATTACH DATABASE 'other.db' AS other",
+		// 以WITH开头的修改语句由 query_only 拒绝
+		"WITH old AS (SELECT id FROM vulnerabilities) DELETE FROM vulnerabilities WHERE id IN old",
+	} {
+		_, err := Run(sql, testItems)
+		assert.True(t, errors.Is(err, ErrNotSelect), "%s: %v", sql, err)
+	}
+
+	for _, sql := range []string{
+		"SELECT id FROM users",
+		"SELECT nope FROM vulnerabilities",
+		"SELECT id FROM vulnerabilities WHERE COUNT(*) > 1",
+		"SELECT id FROM vulnerabilities WHERE title = 'unterminated",
+		"SELECT id FROM vulnerabilities; DELETE FROM vulnerabilities",
+		"SELECT FROM vulnerabilities",
+		"SELECT foo(id) FROM vulnerabilities",
+	} {
+		_, err := Run(sql, testItems)
+		assert.Error(t, err, sql)
+	}
+}
+
+func TestRunSQLiteErrors(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		sql  string
+		want string
+	}{
+		{"GROUP BY 列序号为0", "SELECT risk_level, COUNT(*) FROM vulnerabilities GROUP BY 0", "GROUP BY term out of range - should be between 1 and 2"},
+		{"ORDER BY 列序号超出列数", "SELECT id, title FROM vulnerabilities ORDER BY 1, 5", "ORDER BY term out of range - should be between 1 and 2"},
+		{"coalesce只有一个参数", "SELECT coalesce(cve) FROM vulnerabilities", "wrong number of arguments to function coalesce()"},
+		{"未知的函数", "SELECT foo(id) FROM vulnerabilities", "no such function: foo"},
+		{"未知的列", "SELECT nope FROM vulnerabilities", "no such column: nope"},
+		{"未闭合的括号", "SELECT lower(id FROM vulnerabilities", "syntax error"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// 没有行时也要报告错误，而不是等到计算时才发现
+			for _, items := range [][]model.Vulnerability{testItems, nil} {
+				_, err := Run(tt.sql, items)
+				require.Error(t, err, tt.sql)
+				assert.Contains(t, err.Error(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRunNullSemantics(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		sql  string
+		want [][]interface{}
+	}{
+		{"与NULL比较不为真", "SELECT id FROM vulnerabilities WHERE cve = NULL", nil},
+		{"不等于不匹配NULL", "SELECT id FROM vulnerabilities WHERE cve != 'CVE-2024-0002'", [][]interface{}{{"WLB-1"}}},
+		{"NOT NULL仍为NULL", "SELECT id FROM vulnerabilities WHERE NOT (cve = 'CVE-2024-0002')", [][]interface{}{{"WLB-1"}}},
+		{"IS NULL", "SELECT id FROM vulnerabilities WHERE cve IS NULL AND risk_level = 'High'", [][]interface{}{{"WLB-4"}}},
+		{"IN列表中的NULL不匹配", "SELECT id FROM vulnerabilities WHERE cve IN ('CVE-2024-0001', NULL)", [][]interface{}{{"WLB-1"}}},
+		{"NOT IN列表中有NULL时不为真", "SELECT id FROM vulnerabilities WHERE id NOT IN ('WLB-1', NULL)", nil},
+		{"NOT IN", "SELECT id FROM vulnerabilities WHERE id NOT IN ('WLB-1', 'WLB-2')", [][]interface{}{{"WLB-3"}, {"WLB-4"}}},
+		{"OR的三值逻辑", "SELECT id FROM vulnerabilities WHERE cve = 'x' OR id = 'WLB-2'", [][]interface{}{{"WLB-2"}}},
+		{"BETWEEN的边界为NULL", "SELECT id FROM vulnerabilities WHERE date BETWEEN cve AND '2025-01-01'", nil},
+		{"NULL参与运算", "SELECT cve || '!', views + 1, -cve FROM vulnerabilities WHERE id = 'WLB-2'", [][]interface{}{{nil, nil, nil}}},
+		{"除以0为NULL", "SELECT 1 / 0, 5 % 0 FROM vulnerabilities LIMIT 1", [][]interface{}{{nil, nil}}},
+		{"coalesce返回第一个非NULL值", "SELECT coalesce(cve, NULL, author), ifnull(cve, 'none') FROM vulnerabilities WHERE id = 'WLB-2'", [][]interface{}{{"bob", "none"}}},
+		{"函数的参数为NULL", "SELECT upper(cve), length(cve), substr(cve, 1, 3) FROM vulnerabilities WHERE id = 'WLB-2'", [][]interface{}{{nil, nil, nil}}},
+		{"升序时NULL在前", "SELECT id FROM vulnerabilities ORDER BY cve, id", [][]interface{}{{"WLB-2"}, {"WLB-3"}, {"WLB-4"}, {"WLB-1"}}},
+		{"降序时NULL在后", "SELECT id FROM vulnerabilities ORDER BY cve DESC, id LIMIT 2", [][]interface{}{{"WLB-1"}, {"WLB-2"}}},
+		{"DISTINCT将NULL视为相同", "SELECT DISTINCT cve FROM vulnerabilities ORDER BY 1", [][]interface{}{{nil}, {"CVE-2024-0001"}}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Run(tt.sql, testItems)
+			require.NoError(t, err, tt.sql)
+			if tt.want == nil {
+				tt.want = [][]interface{}{}
+			}
+			assert.Equal(t, tt.want, result.Rows, tt.sql)
+		})
+	}
+}
+
+func TestRunGroupAndOrder(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		sql  string
+		want [][]interface{}
+	}{
+		{"按列序号降序", "SELECT id, author FROM vulnerabilities ORDER BY 2 DESC, 1", [][]interface{}{{"WLB-4", "carol"}, {"WLB-2", "bob"}, {"WLB-1", "alice"}, {"WLB-3", "alice"}}},
+		{"按列序号分组", "SELECT author, COUNT(*) FROM vulnerabilities GROUP BY 1 ORDER BY 2 DESC, 1", [][]interface{}{{"alice", int64(2)}, {"bob", int64(1)}, {"carol", int64(1)}}},
+		{"按别名分组和排序", "SELECT is_remote AS remote, COUNT(*) AS n FROM vulnerabilities GROUP BY remote ORDER BY n", [][]interface{}{{false, int64(1)}, {true, int64(3)}}},
+		{"按表达式分组", "SELECT substr(date, 1, 7), MAX(id) FROM vulnerabilities GROUP BY substr(date, 1, 7) ORDER BY 1", [][]interface{}{{"2024-01", "WLB-2"}, {"2024-02", "WLB-4"}}},
+		{"非整数常量不是列序号", "SELECT id FROM vulnerabilities ORDER BY 1.5, id DESC LIMIT 2", [][]interface{}{{"WLB-4"}, {"WLB-3"}}},
+		{"按不在输出中的列排序", "SELECT id FROM vulnerabilities ORDER BY date DESC LIMIT 1", [][]interface{}{{"WLB-4"}}},
+		{"HAVING使用聚合函数", "SELECT author FROM vulnerabilities GROUP BY author HAVING COUNT(*) > 1", [][]interface{}{{"alice"}}},
+		{"分页", "SELECT id FROM vulnerabilities ORDER BY id LIMIT 2 OFFSET 3", [][]interface{}{{"WLB-4"}}},
+		{"OFFSET超出行数", "SELECT id FROM vulnerabilities LIMIT 2 OFFSET 10", nil},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Run(tt.sql, testItems)
+			require.NoError(t, err, tt.sql)
+			if tt.want == nil {
+				tt.want = [][]interface{}{}
+			}
+			assert.Equal(t, tt.want, result.Rows, tt.sql)
+		})
+	}
+}
+
+func TestRunAggregateFunctions(t *testing.T) {
+	items := []model.Vulnerability{
+		{ID: "WLB-1", Author: "alice", Views: 10, CVE: "CVE-2024-0002"},
+		{ID: "WLB-2", Author: "alice", Views: 25},
+		{ID: "WLB-3", Author: "bob", CVE: "CVE-2024-0001"},
+		{ID: "WLB-4", Author: "bob", CVE: "CVE-2024-0001"},
+	}
+	for _, tt := range []struct {
+		name string
+		sql  string
+		want [][]interface{}
+	}{
+		{"COUNT忽略NULL", "SELECT COUNT(*), COUNT(cve), COUNT(DISTINCT cve) FROM vulnerabilities", [][]interface{}{{int64(4), int64(3), int64(2)}}},
+		{"SUM和AVG忽略NULL", "SELECT SUM(views), AVG(views) FROM vulnerabilities", [][]interface{}{{int64(35), 17.5}}},
+		{"全为NULL时SUM、AVG、MIN、MAX为NULL", "SELECT SUM(views), AVG(views), MIN(views), MAX(views), GROUP_CONCAT(views) FROM vulnerabilities WHERE author = 'bob'", [][]interface{}{{nil, nil, nil, nil, nil}}},
+		{"MIN和MAX", "SELECT MIN(cve), MAX(cve) FROM vulnerabilities", [][]interface{}{{"CVE-2024-0001", "CVE-2024-0002"}}},
+		{"GROUP_CONCAT的分隔符", "SELECT author, GROUP_CONCAT(cve, '|') FROM vulnerabilities GROUP BY author ORDER BY author", [][]interface{}{{"alice", "CVE-2024-0002"}, {"bob", "CVE-2024-0001|CVE-2024-0001"}}},
+		{"没有行时COUNT为0", "SELECT COUNT(*), SUM(views) FROM vulnerabilities WHERE id = 'none'", [][]interface{}{{int64(0), nil}}},
+		{"没有行时分组结果为空", "SELECT author, COUNT(*) FROM vulnerabilities WHERE id = 'none' GROUP BY author", nil},
+		{"聚合函数参与运算", "SELECT author, MAX(views) - MIN(views) FROM vulnerabilities GROUP BY 1 ORDER BY 1", [][]interface{}{{"alice", int64(15)}, {"bob", nil}}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Run(tt.sql, items)
+			require.NoError(t, err, tt.sql)
+			if tt.want == nil {
+				tt.want = [][]interface{}{}
+			}
+			assert.Equal(t, tt.want, result.Rows, tt.sql)
+		})
+	}
+}
@@ -0,0 +1,123 @@
+// Package schema 提供各种输出结果的JSON Schema文档并校验JSON数据
+//
+// Schema文档嵌入在程序中，使用 draft 2020-12 的常用子集编写，
+// 下游程序可以用任意JSON Schema实现校验爬虫的输出：
+//
+//	data, _ := schema.Get(schema.CveDetail)
+//	err := schema.Validate(schema.CveDetail, output)
+package schema
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// 各种输出结果的Schema名称
+const (
+	Vulnerability     = "vulnerability"
+	VulnerabilityList = "vulnerability-list"
+	CveDetail         = "cve-detail"
+	AuthorProfile     = "author-profile"
+	SearchResult      = "search-result"
+	MultiSearchResult = "multi-search-result"
+)
+
+//go:embed schemas/*.json
+var files embed.FS
+
+var (
+	loadOnce sync.Once
+	loaded   map[string]map[string]any
+	loadErr  error
+)
+
+// Names 返回所有Schema的名称，按字母排序
+func Names() []string {
+	entries, _ := files.ReadDir("schemas")
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, strings.TrimSuffix(e.Name(), ".json"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Get 返回指定名称的Schema文档原文
+func Get(name string) ([]byte, error) {
+	data, err := files.ReadFile("schemas/" + name + ".json")
+	if err != nil {
+		return nil, fmt.Errorf("未知的Schema: %s（可选值：%s）", name, strings.Join(Names(), ", "))
+	}
+	return data, nil
+}
+
+// load 解析所有嵌入的Schema文档
+func load() (map[string]map[string]any, error) {
+	loadOnce.Do(func() {
+		loaded = make(map[string]map[string]any)
+		for _, name := range Names() {
+			data, _ := Get(name)
+			var doc map[string]any
+			if err := json.Unmarshal(data, &doc); err != nil {
+				loadErr = fmt.Errorf("解析Schema %s 失败: %w", name, err)
+				return
+			}
+			loaded[name] = doc
+		}
+	})
+	return loaded, loadErr
+}
+
+// Validate 使用指定名称的Schema校验JSON数据
+//
+// 校验失败时返回 *ValidationError，包含所有不符合的位置
+func Validate(name string, data []byte) error {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("解析JSON失败: %w", err)
+	}
+	return ValidateValue(name, v)
+}
+
+// ValidateValue 使用指定名称的Schema校验 encoding/json 解码得到的值
+func ValidateValue(name string, v any) error {
+	docs, err := load()
+	if err != nil {
+		return err
+	}
+	doc, ok := docs[name]
+	if !ok {
+		_, err := Get(name)
+		return err
+	}
+	val := &validator{docs: docs}
+	val.validate(doc, doc, v, "")
+	if len(val.problems) > 0 {
+		return &ValidationError{Schema: name, Problems: val.problems}
+	}
+	return nil
+}
+
+// ValidateJSON 把v编码为JSON后使用指定名称的Schema校验，用于校验程序中的结果对象
+func ValidateJSON(name string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("编码JSON失败: %w", err)
+	}
+	return Validate(name, data)
+}
+
+// ValidationError 是Schema校验失败的错误
+type ValidationError struct {
+	Schema   string   // Schema名称
+	Problems []string // 每个不符合的位置和原因，位置使用JSON Pointer表示
+}
+
+// Error 实现error接口
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("不符合Schema %s:\n  %s", e.Schema, strings.Join(e.Problems, "\n  "))
+}
@@ -0,0 +1,184 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+// fixtureDir 是保存的cxsecurity页面样本目录
+const fixtureDir = "../../docs/response-examples"
+
+// fixtureClient 按路径前缀返回样本页面，用于离线运行完整的爬取流程
+type fixtureClient struct{}
+
+func (fixtureClient) GetPage(path string) (string, error) {
+	files := map[string]string{
+		"/issue/":   "vul-detail-response.html",
+		"/cveshow/": "cve-show-detail-response.html",
+		"/author/":  "author-profile-response.html",
+		"/search/":  "search-response.html",
+		// 搜索结果页与列表页结构相同，并且包含完整的标签
+		"/exploit/": "search-response.html",
+	}
+	for prefix, file := range files {
+		if strings.HasPrefix(path, prefix) {
+			data, err := os.ReadFile(filepath.Join(fixtureDir, file))
+			return string(data), err
+		}
+	}
+	return "", fmt.Errorf("没有路径 %s 的样本", path)
+}
+
+func (fixtureClient) GetBaseURL() string {
+	return "https://cxsecurity.com"
+}
+
+func TestNamesAndGet(t *testing.T) {
+	assert.Equal(t, []string{AuthorProfile, CveDetail, MultiSearchResult, SearchResult, Vulnerability, VulnerabilityList}, Names())
+	for _, name := range Names() {
+		data, err := Get(name)
+		require.NoError(t, err)
+		var doc map[string]any
+		require.NoError(t, json.Unmarshal(data, &doc), name)
+		assert.Equal(t, "https://github.com/scagogogo/cxsecurity-crawler/schema/"+name+".json", doc["$id"])
+	}
+	_, err := Get("nope")
+	assert.ErrorContains(t, err, "未知的Schema")
+}
+
+// TestSchemasMatchModels 确保Schema的属性与Go结构体的JSON字段一一对应
+func TestSchemasMatchModels(t *testing.T) {
+	cases := []struct {
+		name    string
+		pointer string
+		typ     reflect.Type
+	}{
+		{Vulnerability, "", reflect.TypeOf(model.Vulnerability{})},
+		{Vulnerability, "$defs/aiSummary", reflect.TypeOf(model.AISummary{})},
+		{Vulnerability, "$defs/cveUpdate", reflect.TypeOf(model.CVEUpdate{})},
+		{VulnerabilityList, "", reflect.TypeOf(model.VulnerabilityList{})},
+		{CveDetail, "", reflect.TypeOf(model.CveDetail{})},
+		{CveDetail, "$defs/cvss3", reflect.TypeOf(model.Cvss3{})},
+		{CveDetail, "$defs/affectedSoftware", reflect.TypeOf(model.AffectedSoftware{})},
+		{CveDetail, "$defs/cveChange", reflect.TypeOf(model.CveChange{})},
+		{AuthorProfile, "", reflect.TypeOf(model.AuthorProfile{})},
+		{SearchResult, "", reflect.TypeOf(crawler.SearchResult{})},
+		{SearchResult, "$defs/searchVulnerability", reflect.TypeOf(crawler.SearchVulnerability{})},
+		{MultiSearchResult, "", reflect.TypeOf(crawler.MultiSearchResult{})},
+		{MultiSearchResult, "$defs/keywordHits", reflect.TypeOf(crawler.KeywordHits{})},
+		{MultiSearchResult, "$defs/searchVulnerability", reflect.TypeOf(crawler.SearchVulnerability{})},
+	}
+	docs, err := load()
+	require.NoError(t, err)
+	for _, tc := range cases {
+		schema := docs[tc.name]
+		for _, part := range strings.Split(tc.pointer, "/") {
+			if part != "" {
+				schema = schema[part].(map[string]any)
+			}
+		}
+		var properties []string
+		for name := range schema["properties"].(map[string]any) {
+			properties = append(properties, name)
+		}
+		sort.Strings(properties)
+		assert.Equal(t, jsonFields(tc.typ), properties, "%s %s", tc.name, tc.typ)
+	}
+}
+
+// jsonFields 返回结构体的JSON字段名，按字母排序
+func jsonFields(typ reflect.Type) []string {
+	var fields []string
+	for i := 0; i < typ.NumField(); i++ {
+		name, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields = append(fields, name)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// TestValidateCrawlerOutputs 用样本页面运行爬取流程，校验各种输出都符合Schema
+func TestValidateCrawlerOutputs(t *testing.T) {
+	if _, err := os.Stat(fixtureDir); err != nil {
+		t.Skip("跳过测试，样本目录不存在：" + fixtureDir)
+	}
+	c := crawler.NewCrawler(crawler.WithHTTPClient(fixtureClient{}), crawler.WithTechniqueClassifier(crawler.NewKeywordClassifier(nil)))
+
+	list, err := c.CrawlExploitList(1, "")
+	require.NoError(t, err)
+	require.NotEmpty(t, list.Items)
+	assert.NoError(t, ValidateJSON(VulnerabilityList, list))
+
+	vuln, err := c.CrawlVulnerabilityDetail("/issue/WLB-2024040015", "")
+	require.NoError(t, err)
+	assert.NoError(t, ValidateJSON(Vulnerability, vuln))
+
+	cve, err := c.CrawlCveDetail("CVE-2007-1411", "")
+	require.NoError(t, err)
+	assert.NoError(t, ValidateJSON(CveDetail, cve))
+
+	author, err := c.CrawlAuthor("m4xth0r", "")
+	require.NoError(t, err)
+	assert.NoError(t, ValidateJSON(AuthorProfile, author))
+
+	result, err := c.SearchVulnerabilities("XSS", 1, "")
+	require.NoError(t, err)
+	require.NotEmpty(t, result.Vulnerabilities)
+	assert.NoError(t, ValidateJSON(SearchResult, result))
+
+	result, err = c.Search(crawler.SearchOptions{Keyword: "XSS", SortOrder: crawler.SortRelevance}, "")
+	require.NoError(t, err)
+	assert.NoError(t, ValidateJSON(SearchResult, result))
+
+	multi, err := c.MultiSearch(crawler.MultiSearchOptions{Keywords: []string{"XSS", "SQL"}}, "")
+	require.NoError(t, err)
+	require.NotEmpty(t, multi.Vulnerabilities)
+	assert.NoError(t, ValidateJSON(MultiSearchResult, multi))
+}
+
+func TestValidateErrors(t *testing.T) {
+	err := Validate(CveDetail, []byte(`{
+		"cve_id": "cve-2024-1",
+		"published": "yesterday",
+		"cvss_base_score": 11,
+		"cwe_id": 1.5,
+		"cvss3": {"severity": "Severe"},
+		"related_vulnerabilities": [{"id": "WLB-1", "unknown": true}],
+		"extra": 1
+	}`))
+	var verr *ValidationError
+	require.ErrorAs(t, err, &verr)
+	assert.Equal(t, CveDetail, verr.Schema)
+	assert.ElementsMatch(t, []string{
+		`/cve_id: "cve-2024-1" 不匹配 ^CVE-[0-9]{4}-[0-9]{4,}$`,
+		`/published: "yesterday" 不是RFC 3339时间`,
+		`/cvss_base_score: 11 大于最大值 10`,
+		`/cwe_id: 类型应为 integer，实际为 number`,
+		`/cvss3/severity: 值 Severe 不在可选值 [None Low Medium High Critical] 中`,
+		`/related_vulnerabilities/0/unknown: 不允许的属性`,
+		`/extra: 不允许的属性`,
+	}, verr.Problems)
+
+	err = Validate(SearchResult, []byte(`{"keyword": "x", "vulnerabilities": null}`))
+	require.ErrorAs(t, err, &verr)
+	assert.Contains(t, verr.Problems, `/: 缺少必需的属性 "sort_order"`)
+
+	assert.ErrorContains(t, Validate(Vulnerability, []byte(`[]`)), "类型应为 object")
+	assert.ErrorContains(t, Validate(Vulnerability, []byte(`{`)), "解析JSON失败")
+	assert.ErrorContains(t, Validate("nope", []byte(`{}`)), "未知的Schema")
+	assert.NoError(t, Validate(VulnerabilityList, []byte(`{"items": null, "current_page": 1, "total_pages": 1}`)))
+}
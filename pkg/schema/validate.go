@@ -0,0 +1,196 @@
+package schema
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// validator 实现本项目Schema用到的JSON Schema关键字：
+// type、enum、properties、required、additionalProperties、items、
+// minimum、maximum、pattern、format(date-time) 和 $ref（文档内的 #/$defs/... 或其他Schema文件名）
+type validator struct {
+	docs     map[string]map[string]any
+	problems []string
+}
+
+// validate 校验v是否符合schema，root是schema所在的文档，用于解析文档内的 $ref
+func (val *validator) validate(root, schema map[string]any, v any, path string) {
+	if ref, ok := schema["$ref"].(string); ok {
+		refRoot, target, err := val.resolve(root, ref)
+		if err != nil {
+			val.fail(path, err.Error())
+			return
+		}
+		val.validate(refRoot, target, v, path)
+		return
+	}
+
+	if types, ok := schema["type"]; ok && !matchesType(types, v) {
+		val.fail(path, fmt.Sprintf("类型应为 %v，实际为 %s", types, typeName(v)))
+		return
+	}
+	if enum, ok := schema["enum"].([]any); ok && !inEnum(enum, v) {
+		val.fail(path, fmt.Sprintf("值 %v 不在可选值 %v 中", v, enum))
+	}
+
+	switch x := v.(type) {
+	case map[string]any:
+		val.validateObject(root, schema, x, path)
+	case []any:
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range x {
+				val.validate(root, items, item, fmt.Sprintf("%s/%d", path, i))
+			}
+		}
+	case string:
+		if pattern, ok := schema["pattern"].(string); ok {
+			if re, err := regexp.Compile(pattern); err != nil {
+				val.fail(path, fmt.Sprintf("无效的pattern %q", pattern))
+			} else if !re.MatchString(x) {
+				val.fail(path, fmt.Sprintf("%q 不匹配 %s", x, pattern))
+			}
+		}
+		if schema["format"] == "date-time" {
+			if _, err := time.Parse(time.RFC3339Nano, x); err != nil {
+				val.fail(path, fmt.Sprintf("%q 不是RFC 3339时间", x))
+			}
+		}
+	case float64:
+		if min, ok := schema["minimum"].(float64); ok && x < min {
+			val.fail(path, fmt.Sprintf("%v 小于最小值 %v", x, min))
+		}
+		if max, ok := schema["maximum"].(float64); ok && x > max {
+			val.fail(path, fmt.Sprintf("%v 大于最大值 %v", x, max))
+		}
+	}
+}
+
+// validateObject 校验对象的属性
+func (val *validator) validateObject(root, schema map[string]any, obj map[string]any, path string) {
+	if required, ok := schema["required"].([]any); ok {
+		for _, r := range required {
+			if name, _ := r.(string); name != "" {
+				if _, ok := obj[name]; !ok {
+					val.fail(path, fmt.Sprintf("缺少必需的属性 %q", name))
+				}
+			}
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]any)
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		childPath := path + "/" + escapePointer(k)
+		if prop, ok := properties[k].(map[string]any); ok {
+			val.validate(root, prop, obj[k], childPath)
+		} else if additional, ok := schema["additionalProperties"].(bool); ok && !additional {
+			val.fail(childPath, "不允许的属性")
+		}
+	}
+}
+
+// resolve 解析 $ref，返回目标Schema和它所在的文档
+func (val *validator) resolve(root map[string]any, ref string) (map[string]any, map[string]any, error) {
+	file, fragment, _ := strings.Cut(ref, "#")
+	if file != "" {
+		doc, ok := val.docs[strings.TrimSuffix(file, ".json")]
+		if !ok {
+			return nil, nil, fmt.Errorf("无法解析的$ref %q", ref)
+		}
+		root = doc
+	}
+	target := root
+	for _, part := range strings.Split(strings.Trim(fragment, "/"), "/") {
+		if part == "" {
+			continue
+		}
+		next, ok := target[part].(map[string]any)
+		if !ok {
+			return nil, nil, fmt.Errorf("无法解析的$ref %q", ref)
+		}
+		target = next
+	}
+	return root, target, nil
+}
+
+func (val *validator) fail(path, msg string) {
+	if path == "" {
+		path = "/"
+	}
+	val.problems = append(val.problems, path+": "+msg)
+}
+
+// matchesType 判断v是否符合type关键字，type可以是单个类型名或类型名数组
+func matchesType(types any, v any) bool {
+	switch t := types.(type) {
+	case string:
+		return isType(t, v)
+	case []any:
+		for _, name := range t {
+			if s, _ := name.(string); isType(s, v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isType(name string, v any) bool {
+	switch name {
+	case "integer":
+		f, ok := v.(float64)
+		return ok && f == math.Trunc(f)
+	case "number":
+		_, ok := v.(float64)
+		return ok
+	default:
+		return typeName(v) == name
+	}
+}
+
+// typeName 返回 encoding/json 解码得到的值对应的JSON类型名
+func typeName(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+func inEnum(enum []any, v any) bool {
+	switch v.(type) {
+	case map[string]any, []any:
+		// 本项目的枚举值都是标量，对象和数组不能用 == 比较
+		return false
+	}
+	for _, e := range enum {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
+
+// escapePointer 按JSON Pointer规则转义属性名
+func escapePointer(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
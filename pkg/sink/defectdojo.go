@@ -0,0 +1,288 @@
+// Package sink 将爬取到的漏洞推送到外部的漏洞管理系统
+package sink
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+// DefectDojoScanType 是推送时使用的扫描类型，DefectDojo用它解析上传的文件
+const DefectDojoScanType = "Generic Findings Import"
+
+// DefectDojoOption 是设置DefectDojo选项的函数类型
+type DefectDojoOption func(*DefectDojo)
+
+// DefectDojo 通过DefectDojo v2 API导入漏洞
+// 指定engagement时调用 import-scan 在该engagement下创建新的test；
+// 指定test时调用 reimport-scan 更新已有的test，DefectDojo会按 unique_id_from_tool 去重，
+// 适合定期推送同一批数据。两者都指定时优先使用test。
+//
+// 使用示例：
+//
+//	dd := sink.NewDefectDojo("https://defectdojo.example.com", token, sink.WithDefectDojoEngagement(3))
+//	result, err := dd.Push(items)
+type DefectDojo struct {
+	baseURL    string
+	token      string
+	engagement int
+	test       int
+	testTitle  string
+	client     *http.Client
+}
+
+// WithDefectDojoEngagement 设置导入的engagement ID
+func WithDefectDojoEngagement(id int) DefectDojoOption {
+	return func(d *DefectDojo) {
+		d.engagement = id
+	}
+}
+
+// WithDefectDojoTest 设置重新导入的test ID
+func WithDefectDojoTest(id int) DefectDojoOption {
+	return func(d *DefectDojo) {
+		d.test = id
+	}
+}
+
+// WithDefectDojoTestTitle 设置新建test的标题，默认为 "cxsecurity"
+func WithDefectDojoTestTitle(title string) DefectDojoOption {
+	return func(d *DefectDojo) {
+		if title != "" {
+			d.testTitle = title
+		}
+	}
+}
+
+// WithDefectDojoHTTPClient 设置使用的HTTP客户端，默认超时时间为60秒
+func WithDefectDojoHTTPClient(client *http.Client) DefectDojoOption {
+	return func(d *DefectDojo) {
+		if client != nil {
+			d.client = client
+		}
+	}
+}
+
+// NewDefectDojo 创建DefectDojo推送器
+//
+// 参数:
+//   - baseURL: DefectDojo的地址，例如 https://defectdojo.example.com
+//   - token: API v2的Token
+//   - options: 可选配置，至少需要指定engagement或test
+func NewDefectDojo(baseURL, token string, options ...DefectDojoOption) *DefectDojo {
+	d := &DefectDojo{
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		token:     token,
+		testTitle: "cxsecurity",
+		client:    &http.Client{Timeout: 60 * time.Second},
+	}
+	for _, option := range options {
+		option(d)
+	}
+	return d
+}
+
+// DefectDojoFinding 是Generic Findings Import格式中的一条发现
+type DefectDojoFinding struct {
+	Title            string   `json:"title"`
+	Description      string   `json:"description"`
+	Severity         string   `json:"severity"`
+	Date             string   `json:"date,omitempty"`
+	CVE              string   `json:"cve,omitempty"`
+	CWE              int      `json:"cwe,omitempty"`
+	References       string   `json:"references,omitempty"`
+	UniqueIDFromTool string   `json:"unique_id_from_tool"`
+	VulnIDFromTool   string   `json:"vuln_id_from_tool,omitempty"`
+	Tags             []string `json:"tags,omitempty"`
+	Active           bool     `json:"active"`
+	Verified         bool     `json:"verified"`
+}
+
+// DefectDojoReport 是Generic Findings Import格式的文件内容
+type DefectDojoReport struct {
+	Findings []DefectDojoFinding `json:"findings"`
+}
+
+// DefectDojoResult 是导入接口返回的结果
+type DefectDojoResult struct {
+	Test       int `json:"test_id"`
+	Engagement int `json:"engagement_id"`
+	Product    int `json:"product_id"`
+	Findings   int `json:"findings"` // 推送的发现数量
+}
+
+// ToDefectDojoFindings 将漏洞转换为DefectDojo的发现
+// 风险级别 High/Med./Low 对应 High/Medium/Low，未知时为 Info；
+// 漏洞ID作为 unique_id_from_tool，重新导入时用于去重。
+func ToDefectDojoFindings(items []model.Vulnerability) []DefectDojoFinding {
+	findings := make([]DefectDojoFinding, 0, len(items))
+	for _, v := range items {
+		title := v.Title
+		if title == "" {
+			title = v.ID
+		}
+		f := DefectDojoFinding{
+			Title:            title,
+			Description:      defectDojoDescription(v),
+			Severity:         defectDojoSeverity(v.RiskLevel),
+			CVE:              v.CVE,
+			CWE:              cweNumber(v.CWE),
+			References:       v.URL,
+			UniqueIDFromTool: v.ID,
+			VulnIDFromTool:   v.ID,
+			Tags:             append([]string{"cxsecurity"}, v.Techniques...),
+			Active:           true,
+		}
+		if f.UniqueIDFromTool == "" {
+			f.UniqueIDFromTool = v.URL
+		}
+		if !v.Date.IsZero() {
+			f.Date = v.Date.Format("2006-01-02")
+		}
+		findings = append(findings, f)
+	}
+	return findings
+}
+
+// Push 将漏洞推送到DefectDojo
+func (d *DefectDojo) Push(items []model.Vulnerability) (*DefectDojoResult, error) {
+	if d.baseURL == "" || d.token == "" {
+		return nil, errors.New("DefectDojo地址和Token不能为空")
+	}
+	if d.engagement <= 0 && d.test <= 0 {
+		return nil, errors.New("需要指定engagement或test")
+	}
+
+	report, err := json.Marshal(DefectDojoReport{Findings: ToDefectDojoFindings(items)})
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := "/api/v2/import-scan/"
+	fields := map[string]string{
+		"scan_type":          DefectDojoScanType,
+		"active":             "true",
+		"verified":           "false",
+		"minimum_severity":   "Info",
+		"close_old_findings": "false",
+		"scan_date":          time.Now().Format("2006-01-02"),
+	}
+	if d.test > 0 {
+		endpoint = "/api/v2/reimport-scan/"
+		fields["test"] = strconv.Itoa(d.test)
+	} else {
+		fields["engagement"] = strconv.Itoa(d.engagement)
+		fields["test_title"] = d.testTitle
+	}
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	for k, v := range fields {
+		if err := w.WriteField(k, v); err != nil {
+			return nil, err
+		}
+	}
+	part, err := w.CreateFormFile("file", "cxsecurity.json")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := part.Write(report); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, d.baseURL+endpoint, &body)
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("Authorization", "Token "+d.token)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("请求DefectDojo失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("读取响应失败: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("DefectDojo返回错误 %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+
+	result := &DefectDojoResult{}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, result); err != nil {
+			return nil, fmt.Errorf("解析响应失败: %w", err)
+		}
+	}
+	result.Findings = len(items)
+	if result.Test == 0 {
+		result.Test = d.test
+	}
+	return result, nil
+}
+
+// defectDojoSeverity 将cxsecurity的风险级别转换为DefectDojo的严重程度
+func defectDojoSeverity(risk string) string {
+	switch strings.ToLower(strings.TrimSuffix(strings.TrimSpace(risk), ".")) {
+	case "high":
+		return "High"
+	case "med", "medium":
+		return "Medium"
+	case "low":
+		return "Low"
+	default:
+		return "Info"
+	}
+}
+
+// defectDojoDescription 生成发现的描述，包含作者、远程/本地等信息和公告正文
+func defectDojoDescription(v model.Vulnerability) string {
+	var b strings.Builder
+	if v.URL != "" {
+		fmt.Fprintf(&b, "Source: %s\n", v.URL)
+	}
+	if v.Author != "" {
+		fmt.Fprintf(&b, "Author: %s\n", v.Author)
+	}
+	if v.IsRemote {
+		b.WriteString("Remote: yes\n")
+	}
+	if v.IsLocal {
+		b.WriteString("Local: yes\n")
+	}
+	if len(v.Techniques) > 0 {
+		fmt.Fprintf(&b, "ATT&CK: %s\n", strings.Join(v.Techniques, ", "))
+	}
+	if content := strings.TrimSpace(v.Content); content != "" {
+		b.WriteString("\n" + content + "\n")
+	}
+	if b.Len() == 0 {
+		return v.Title
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// cweNumber 从 CWE-22 形式的编号中取出数字，无法解析时返回0
+func cweNumber(cwe string) int {
+	n, err := strconv.Atoi(strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(cwe)), "CWE-"))
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
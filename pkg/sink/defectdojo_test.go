@@ -0,0 +1,84 @@
+package sink
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+func TestToDefectDojoFindings(t *testing.T) {
+	findings := ToDefectDojoFindings([]model.Vulnerability{
+		{ID: "WLB-2024010001", Title: "Foo SQL Injection", URL: "https://cxsecurity.com/issue/WLB-2024010001",
+			RiskLevel: "Med.", CVE: "CVE-2024-0001", CWE: "CWE-89", Date: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+			Author: "alice", IsRemote: true},
+		{ID: "WLB-2024010002", RiskLevel: "unknown"},
+	})
+	require.Len(t, findings, 2)
+	assert.Equal(t, "Medium", findings[0].Severity)
+	assert.Equal(t, 89, findings[0].CWE)
+	assert.Equal(t, "2024-01-02", findings[0].Date)
+	assert.Equal(t, "WLB-2024010001", findings[0].UniqueIDFromTool)
+	assert.Contains(t, findings[0].Description, "Author: alice")
+	assert.Equal(t, "Info", findings[1].Severity)
+	assert.Equal(t, "WLB-2024010002", findings[1].Title)
+}
+
+func TestDefectDojoPush(t *testing.T) {
+	var path string
+	var fields map[string][]string
+	var report DefectDojoReport
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		assert.Equal(t, "Token secret", r.Header.Get("Authorization"))
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		fields = r.MultipartForm.Value
+		file, _, err := r.FormFile("file")
+		require.NoError(t, err)
+		data, _ := io.ReadAll(file)
+		require.NoError(t, json.Unmarshal(data, &report))
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"test_id": 42, "engagement_id": 3, "product_id": 1}`))
+	}))
+	defer server.Close()
+
+	items := []model.Vulnerability{{ID: "WLB-2024010001", Title: "Foo", RiskLevel: "High"}}
+
+	result, err := NewDefectDojo(server.URL+"/", "secret", WithDefectDojoEngagement(3)).Push(items)
+	require.NoError(t, err)
+	assert.Equal(t, "/api/v2/import-scan/", path)
+	assert.Equal(t, []string{"3"}, fields["engagement"])
+	assert.Equal(t, []string{DefectDojoScanType}, fields["scan_type"])
+	assert.Equal(t, 42, result.Test)
+	assert.Equal(t, 1, result.Findings)
+	require.Len(t, report.Findings, 1)
+	assert.Equal(t, "High", report.Findings[0].Severity)
+
+	_, err = NewDefectDojo(server.URL, "secret", WithDefectDojoEngagement(3), WithDefectDojoTest(42)).Push(items)
+	require.NoError(t, err)
+	assert.Equal(t, "/api/v2/reimport-scan/", path)
+	assert.Equal(t, []string{"42"}, fields["test"])
+	assert.Nil(t, fields["engagement"])
+}
+
+func TestDefectDojoPushErrors(t *testing.T) {
+	_, err := NewDefectDojo("", "secret", WithDefectDojoEngagement(1)).Push(nil)
+	assert.Error(t, err)
+	_, err = NewDefectDojo("http://127.0.0.1", "secret").Push(nil)
+	assert.Error(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"detail":"Invalid token."}`, http.StatusUnauthorized)
+	}))
+	defer server.Close()
+	_, err = NewDefectDojo(server.URL, "bad", WithDefectDojoTest(1)).Push(nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "401")
+}
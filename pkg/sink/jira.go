@@ -0,0 +1,220 @@
+package sink
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+// JiraOption 是设置Jira选项的函数类型
+type JiraOption func(*Jira)
+
+// Jira 通过JIRA REST API v2创建工单
+// 指定用户名时使用Basic认证(JIRA Cloud的邮箱 + API Token)，否则使用Bearer认证(JIRA Server/Data Center的个人访问令牌)。
+//
+// 使用示例：
+//
+//	jira := sink.NewJira("https://example.atlassian.net", "me@example.com", token,
+//	    sink.WithJiraProject("SEC"), sink.WithJiraLabels("cxsecurity", "watchlist"))
+//	issue, err := jira.CreateIssue(vuln, "php")
+type Jira struct {
+	baseURL   string
+	user      string
+	token     string
+	project   string
+	issueType string
+	labels    []string
+	client    *http.Client
+}
+
+// WithJiraProject 设置创建工单的项目Key
+func WithJiraProject(project string) JiraOption {
+	return func(j *Jira) {
+		j.project = project
+	}
+}
+
+// WithJiraIssueType 设置工单类型，默认为 "Bug"
+func WithJiraIssueType(issueType string) JiraOption {
+	return func(j *Jira) {
+		if issueType != "" {
+			j.issueType = issueType
+		}
+	}
+}
+
+// WithJiraLabels 设置工单的标签，JIRA的标签不能包含空格，空格会被替换为连字符
+func WithJiraLabels(labels ...string) JiraOption {
+	return func(j *Jira) {
+		j.labels = nil
+		for _, label := range labels {
+			if label = strings.Join(strings.Fields(label), "-"); label != "" {
+				j.labels = append(j.labels, label)
+			}
+		}
+	}
+}
+
+// WithJiraHTTPClient 设置使用的HTTP客户端，默认超时时间为30秒
+func WithJiraHTTPClient(client *http.Client) JiraOption {
+	return func(j *Jira) {
+		if client != nil {
+			j.client = client
+		}
+	}
+}
+
+// NewJira 创建JIRA工单创建器
+//
+// 参数:
+//   - baseURL: JIRA的地址，例如 https://example.atlassian.net
+//   - user: 用户名或邮箱，为空时使用Bearer认证
+//   - token: API Token或个人访问令牌
+//   - options: 可选配置，至少需要指定项目
+func NewJira(baseURL, user, token string, options ...JiraOption) *Jira {
+	j := &Jira{
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		user:      user,
+		token:     token,
+		issueType: "Bug",
+		labels:    []string{"cxsecurity"},
+		client:    &http.Client{Timeout: 30 * time.Second},
+	}
+	for _, option := range options {
+		option(j)
+	}
+	return j
+}
+
+// JiraIssue 是创建成功的工单
+type JiraIssue struct {
+	ID  string `json:"id"`
+	Key string `json:"key"`
+	URL string `json:"url"` // 工单的浏览地址
+}
+
+// jiraIssueRequest 是创建工单接口的请求体
+type jiraIssueRequest struct {
+	Fields jiraIssueFields `json:"fields"`
+}
+
+type jiraIssueFields struct {
+	Project     jiraKey  `json:"project"`
+	IssueType   jiraName `json:"issuetype"`
+	Summary     string   `json:"summary"`
+	Description string   `json:"description"`
+	Labels      []string `json:"labels,omitempty"`
+}
+
+type jiraKey struct {
+	Key string `json:"key"`
+}
+
+type jiraName struct {
+	Name string `json:"name"`
+}
+
+// CreateIssue 为匹配到关注产品的漏洞创建工单
+// 工单标题为 "[cxsecurity] 漏洞ID 标题"，描述中包含关注的产品、公告链接、风险级别、CVE等信息，
+// 并额外添加一个以漏洞ID命名的标签，便于在JIRA中查找。
+//
+// 参数:
+//   - v: 漏洞
+//   - product: 匹配到的关注产品名称
+func (j *Jira) CreateIssue(v model.Vulnerability, product string) (*JiraIssue, error) {
+	if j.baseURL == "" || j.token == "" {
+		return nil, errors.New("JIRA地址和Token不能为空")
+	}
+	if j.project == "" {
+		return nil, errors.New("需要指定JIRA项目")
+	}
+
+	labels := append([]string{}, j.labels...)
+	if v.ID != "" {
+		labels = append(labels, v.ID)
+	}
+	headers := map[string]string{"Authorization": "Bearer " + j.token}
+	if j.user != "" {
+		headers["Authorization"] = "Basic " + base64.StdEncoding.EncodeToString([]byte(j.user+":"+j.token))
+	}
+	data, err := postJSON(j.client, "JIRA", j.baseURL+"/rest/api/2/issue", headers, jiraIssueRequest{Fields: jiraIssueFields{
+		Project:     jiraKey{Key: j.project},
+		IssueType:   jiraName{Name: j.issueType},
+		Summary:     jiraSummary(v),
+		Description: jiraDescription(v, product),
+		Labels:      labels,
+	}})
+	if err != nil {
+		return nil, err
+	}
+
+	issue := &JiraIssue{}
+	if err := json.Unmarshal(data, issue); err != nil {
+		return nil, fmt.Errorf("解析响应失败: %w", err)
+	}
+	if issue.Key == "" {
+		return nil, errors.New("JIRA响应中没有工单编号")
+	}
+	issue.URL = j.baseURL + "/browse/" + issue.Key
+	return issue, nil
+}
+
+// jiraSummary 返回工单标题，JIRA限制标题最多255个字符
+func jiraSummary(v model.Vulnerability) string {
+	summary := strings.TrimSpace("[cxsecurity] " + v.ID + " " + v.Title)
+	if r := []rune(summary); len(r) > 255 {
+		summary = string(r[:252]) + "..."
+	}
+	return summary
+}
+
+// jiraDescription 使用JIRA wiki标记生成工单描述
+func jiraDescription(v model.Vulnerability, product string) string {
+	var b strings.Builder
+	line := func(label, value string) {
+		if value != "" {
+			fmt.Fprintf(&b, "*%s:* %s\n", label, value)
+		}
+	}
+	line("Watched product", product)
+	if v.URL != "" {
+		title := v.Title
+		if title == "" {
+			title = v.ID
+		}
+		line("Advisory", fmt.Sprintf("[%s|%s]", strings.NewReplacer("[", "(", "]", ")", "|", "/").Replace(title), v.URL))
+	}
+	line("ID", v.ID)
+	line("Risk", v.RiskLevel)
+	if v.CVE != "" {
+		line("CVE", fmt.Sprintf("[%s|https://cxsecurity.com/cveshow/%s/]", v.CVE, v.CVE))
+	}
+	line("CWE", v.CWE)
+	if !v.Date.IsZero() {
+		line("Published", v.Date.Format("2006-01-02"))
+	}
+	if v.Author != "" {
+		author := v.Author
+		if v.AuthorURL != "" {
+			author = fmt.Sprintf("[%s|%s]", v.Author, v.AuthorURL)
+		}
+		line("Author", author)
+	}
+	switch {
+	case v.IsRemote && v.IsLocal:
+		line("Access", "Remote, Local")
+	case v.IsRemote:
+		line("Access", "Remote")
+	case v.IsLocal:
+		line("Access", "Local")
+	}
+	line("ATT&CK", strings.Join(v.Techniques, ", "))
+	line("Tags", strings.Join(v.Tags, ", "))
+	return strings.TrimSpace(b.String())
+}
@@ -0,0 +1,67 @@
+package sink
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+func TestJiraCreateIssue(t *testing.T) {
+	var body jiraIssueRequest
+	var user, pass string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/rest/api/2/issue", r.URL.Path)
+		user, pass, _ = r.BasicAuth()
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"10001","key":"SEC-42","self":"x"}`))
+	}))
+	defer server.Close()
+
+	jira := NewJira(server.URL+"/", "me@example.com", "secret",
+		WithJiraProject("SEC"), WithJiraIssueType("Task"), WithJiraLabels("cxsecurity", "php watch"))
+	issue, err := jira.CreateIssue(model.Vulnerability{
+		ID: "WLB-2024010001", Title: "PHP 8.1 [RCE]", URL: "https://cxsecurity.com/issue/WLB-2024010001",
+		RiskLevel: "High", CVE: "CVE-2024-0001", IsRemote: true,
+	}, "php")
+	require.NoError(t, err)
+	assert.Equal(t, "SEC-42", issue.Key)
+	assert.Equal(t, server.URL+"/browse/SEC-42", issue.URL)
+	assert.Equal(t, []string{"me@example.com", "secret"}, []string{user, pass})
+
+	assert.Equal(t, "SEC", body.Fields.Project.Key)
+	assert.Equal(t, "Task", body.Fields.IssueType.Name)
+	assert.Equal(t, "[cxsecurity] WLB-2024010001 PHP 8.1 [RCE]", body.Fields.Summary)
+	assert.Equal(t, []string{"cxsecurity", "php-watch", "WLB-2024010001"}, body.Fields.Labels)
+	assert.Contains(t, body.Fields.Description, "*Watched product:* php")
+	assert.Contains(t, body.Fields.Description, "[PHP 8.1 (RCE)|https://cxsecurity.com/issue/WLB-2024010001]")
+	assert.Contains(t, body.Fields.Description, "*Access:* Remote")
+}
+
+func TestJiraCreateIssueErrors(t *testing.T) {
+	_, err := NewJira("https://jira.example.com", "", "token").CreateIssue(model.Vulnerability{ID: "WLB-1"}, "php")
+	assert.Error(t, err, "没有项目时应返回错误")
+
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		http.Error(w, `{"errorMessages":["project is required"]}`, http.StatusBadRequest)
+	}))
+	defer server.Close()
+	_, err = NewJira(server.URL, "", "pat", WithJiraProject("SEC")).CreateIssue(model.Vulnerability{ID: "WLB-1"}, "php")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "400")
+	assert.Equal(t, "Bearer pat", auth)
+}
+
+func TestJiraSummaryTruncated(t *testing.T) {
+	summary := jiraSummary(model.Vulnerability{ID: "WLB-1", Title: strings.Repeat("a", 300)})
+	assert.Len(t, []rune(summary), 255)
+}
@@ -0,0 +1,270 @@
+package sink
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+// Pager 是值班告警系统，用于在关注的产品出现高风险的公开漏洞利用时呼叫值班人员
+type Pager interface {
+	// Name 返回告警系统的名称，例如 "pagerduty"
+	Name() string
+	// Page 为匹配到关注产品的漏洞触发告警，返回告警的去重键
+	Page(v model.Vulnerability, product string) (string, error)
+}
+
+// IsPageable 判断风险级别是否需要呼叫值班人员，只有 Critical 和 High 需要
+func IsPageable(risk string) bool {
+	switch strings.ToLower(strings.TrimSpace(risk)) {
+	case "critical", "high":
+		return true
+	}
+	return false
+}
+
+// DedupKey 返回漏洞告警的去重键，同一个漏洞重复触发时告警系统只保留一个告警
+func DedupKey(v model.Vulnerability) string {
+	return "cxsecurity-" + v.ID
+}
+
+// pageSummary 返回告警的标题
+func pageSummary(v model.Vulnerability, product string) string {
+	return fmt.Sprintf("[cxsecurity] %s: public exploit %s %s", product, v.ID, v.Title)
+}
+
+// pageDetails 返回告警的附加信息
+func pageDetails(v model.Vulnerability, product string) map[string]string {
+	details := map[string]string{"watched_product": product, "id": v.ID}
+	add := func(k, value string) {
+		if value != "" {
+			details[k] = value
+		}
+	}
+	add("url", v.URL)
+	add("risk", v.RiskLevel)
+	add("cve", v.CVE)
+	add("cwe", v.CWE)
+	add("author", v.Author)
+	add("techniques", strings.Join(v.Techniques, ", "))
+	if !v.Date.IsZero() {
+		details["published"] = v.Date.Format("2006-01-02")
+	}
+	return details
+}
+
+// truncateRunes 将字符串截断为最多n个字符
+func truncateRunes(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n-3]) + "..."
+	}
+	return s
+}
+
+// PagerDutyEventsURL 是PagerDuty Events API v2的地址
+const PagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// PagerDutyOption 是设置PagerDuty选项的函数类型
+type PagerDutyOption func(*PagerDuty)
+
+// PagerDuty 通过Events API v2触发PagerDuty告警
+// 使用漏洞ID作为dedup_key，同一个漏洞在告警解决之前不会产生新的事件。
+type PagerDuty struct {
+	routingKey string
+	url        string
+	client     *http.Client
+}
+
+// WithPagerDutyURL 设置Events API的地址，用于测试或代理
+func WithPagerDutyURL(url string) PagerDutyOption {
+	return func(p *PagerDuty) {
+		if url != "" {
+			p.url = url
+		}
+	}
+}
+
+// WithPagerDutyHTTPClient 设置使用的HTTP客户端，默认超时时间为30秒
+func WithPagerDutyHTTPClient(client *http.Client) PagerDutyOption {
+	return func(p *PagerDuty) {
+		if client != nil {
+			p.client = client
+		}
+	}
+}
+
+// NewPagerDuty 创建PagerDuty告警器
+//
+// 参数:
+//   - routingKey: 服务的Events API v2集成密钥(Integration Key)
+func NewPagerDuty(routingKey string, options ...PagerDutyOption) *PagerDuty {
+	p := &PagerDuty{
+		routingKey: routingKey,
+		url:        PagerDutyEventsURL,
+		client:     &http.Client{Timeout: 30 * time.Second},
+	}
+	for _, option := range options {
+		option(p)
+	}
+	return p
+}
+
+// Name 返回 "pagerduty"
+func (p *PagerDuty) Name() string {
+	return "pagerduty"
+}
+
+type pagerDutyEvent struct {
+	RoutingKey  string           `json:"routing_key"`
+	EventAction string           `json:"event_action"`
+	DedupKey    string           `json:"dedup_key"`
+	Payload     pagerDutyPayload `json:"payload"`
+	Links       []pagerDutyLink  `json:"links,omitempty"`
+}
+
+type pagerDutyPayload struct {
+	Summary       string            `json:"summary"`
+	Source        string            `json:"source"`
+	Severity      string            `json:"severity"`
+	Timestamp     string            `json:"timestamp,omitempty"`
+	Component     string            `json:"component,omitempty"`
+	Class         string            `json:"class,omitempty"`
+	CustomDetails map[string]string `json:"custom_details,omitempty"`
+}
+
+type pagerDutyLink struct {
+	Href string `json:"href"`
+	Text string `json:"text"`
+}
+
+// Page 触发PagerDuty告警，风险级别为Critical时告警级别为critical，否则为error
+func (p *PagerDuty) Page(v model.Vulnerability, product string) (string, error) {
+	if p.routingKey == "" {
+		return "", errors.New("PagerDuty集成密钥不能为空")
+	}
+
+	severity := "error"
+	if strings.EqualFold(v.RiskLevel, "critical") {
+		severity = "critical"
+	}
+	event := pagerDutyEvent{
+		RoutingKey:  p.routingKey,
+		EventAction: "trigger",
+		DedupKey:    DedupKey(v),
+		Payload: pagerDutyPayload{
+			Summary:       truncateRunes(pageSummary(v, product), 1024),
+			Source:        "cxsecurity.com",
+			Severity:      severity,
+			Component:     product,
+			Class:         "public exploit",
+			CustomDetails: pageDetails(v, product),
+		},
+	}
+	if !v.Date.IsZero() {
+		event.Payload.Timestamp = v.Date.UTC().Format(time.RFC3339)
+	}
+	if v.URL != "" {
+		event.Links = []pagerDutyLink{{Href: v.URL, Text: v.ID}}
+	}
+
+	if _, err := postJSON(p.client, "PagerDuty", p.url, nil, event); err != nil {
+		return "", err
+	}
+	return event.DedupKey, nil
+}
+
+// OpsgenieAPIURL 是Opsgenie API的地址，欧洲区域为 https://api.eu.opsgenie.com
+const OpsgenieAPIURL = "https://api.opsgenie.com"
+
+// OpsgenieOption 是设置Opsgenie选项的函数类型
+type OpsgenieOption func(*Opsgenie)
+
+// Opsgenie 通过Alert API创建Opsgenie告警
+// 使用漏洞ID作为alias，同一个漏洞在告警关闭之前不会产生新的告警。
+type Opsgenie struct {
+	apiKey string
+	url    string
+	client *http.Client
+}
+
+// WithOpsgenieURL 设置API地址，例如欧洲区域的 https://api.eu.opsgenie.com
+func WithOpsgenieURL(url string) OpsgenieOption {
+	return func(o *Opsgenie) {
+		if url != "" {
+			o.url = strings.TrimRight(url, "/")
+		}
+	}
+}
+
+// WithOpsgenieHTTPClient 设置使用的HTTP客户端，默认超时时间为30秒
+func WithOpsgenieHTTPClient(client *http.Client) OpsgenieOption {
+	return func(o *Opsgenie) {
+		if client != nil {
+			o.client = client
+		}
+	}
+}
+
+// NewOpsgenie 创建Opsgenie告警器
+//
+// 参数:
+//   - apiKey: API集成的密钥
+func NewOpsgenie(apiKey string, options ...OpsgenieOption) *Opsgenie {
+	o := &Opsgenie{
+		apiKey: apiKey,
+		url:    OpsgenieAPIURL,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+	for _, option := range options {
+		option(o)
+	}
+	return o
+}
+
+// Name 返回 "opsgenie"
+func (o *Opsgenie) Name() string {
+	return "opsgenie"
+}
+
+type opsgenieAlert struct {
+	Message     string            `json:"message"`
+	Alias       string            `json:"alias"`
+	Description string            `json:"description,omitempty"`
+	Tags        []string          `json:"tags,omitempty"`
+	Details     map[string]string `json:"details,omitempty"`
+	Entity      string            `json:"entity,omitempty"`
+	Source      string            `json:"source"`
+	Priority    string            `json:"priority"`
+}
+
+// Page 创建Opsgenie告警，风险级别为Critical时优先级为P1，否则为P2
+func (o *Opsgenie) Page(v model.Vulnerability, product string) (string, error) {
+	if o.apiKey == "" {
+		return "", errors.New("Opsgenie API密钥不能为空")
+	}
+
+	priority := "P2"
+	if strings.EqualFold(v.RiskLevel, "critical") {
+		priority = "P1"
+	}
+	alert := opsgenieAlert{
+		Message:     truncateRunes(pageSummary(v, product), 130),
+		Alias:       DedupKey(v),
+		Description: truncateRunes(strings.TrimSpace(v.Title+"\n"+v.URL), 15000),
+		Tags:        append([]string{"cxsecurity"}, v.Techniques...),
+		Details:     pageDetails(v, product),
+		Entity:      product,
+		Source:      "cxsecurity.com",
+		Priority:    priority,
+	}
+
+	headers := map[string]string{"Authorization": "GenieKey " + o.apiKey}
+	if _, err := postJSON(o.client, "Opsgenie", o.url+"/v2/alerts", headers, alert); err != nil {
+		return "", err
+	}
+	return alert.Alias, nil
+}
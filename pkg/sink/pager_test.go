@@ -0,0 +1,84 @@
+package sink
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+var pageVuln = model.Vulnerability{
+	ID: "WLB-2024010001", Title: "PHP 8.1 Remote Code Execution", URL: "https://cxsecurity.com/issue/WLB-2024010001",
+	RiskLevel: "High", CVE: "CVE-2024-0001", Date: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+}
+
+func TestIsPageable(t *testing.T) {
+	assert.True(t, IsPageable("High"))
+	assert.True(t, IsPageable(" critical "))
+	assert.False(t, IsPageable("Med."))
+	assert.False(t, IsPageable(""))
+}
+
+func TestPagerDutyPage(t *testing.T) {
+	var event pagerDutyEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(`{"status":"success","dedup_key":"cxsecurity-WLB-2024010001"}`))
+	}))
+	defer server.Close()
+
+	key, err := NewPagerDuty("routing", WithPagerDutyURL(server.URL)).Page(pageVuln, "php")
+	require.NoError(t, err)
+	assert.Equal(t, "cxsecurity-WLB-2024010001", key)
+	assert.Equal(t, "routing", event.RoutingKey)
+	assert.Equal(t, "trigger", event.EventAction)
+	assert.Equal(t, "error", event.Payload.Severity)
+	assert.Equal(t, "php", event.Payload.Component)
+	assert.Equal(t, "2024-01-02T00:00:00Z", event.Payload.Timestamp)
+	assert.Equal(t, "CVE-2024-0001", event.Payload.CustomDetails["cve"])
+	assert.Equal(t, pageVuln.URL, event.Links[0].Href)
+
+	_, err = NewPagerDuty("").Page(pageVuln, "php")
+	assert.Error(t, err)
+}
+
+func TestOpsgeniePage(t *testing.T) {
+	var alert opsgenieAlert
+	var auth, path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth, path = r.Header.Get("Authorization"), r.URL.Path
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&alert))
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(`{"result":"Request will be processed","requestId":"x"}`))
+	}))
+	defer server.Close()
+
+	critical := pageVuln
+	critical.RiskLevel = "Critical"
+	key, err := NewOpsgenie("genie", WithOpsgenieURL(server.URL+"/")).Page(critical, "php")
+	require.NoError(t, err)
+	assert.Equal(t, "cxsecurity-WLB-2024010001", key)
+	assert.Equal(t, "GenieKey genie", auth)
+	assert.Equal(t, "/v2/alerts", path)
+	assert.Equal(t, "P1", alert.Priority)
+	assert.Equal(t, "cxsecurity-WLB-2024010001", alert.Alias)
+	assert.LessOrEqual(t, len([]rune(alert.Message)), 130)
+}
+
+func TestPagerErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message":"Invalid routing key"}`, http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	_, err := NewPagerDuty("bad", WithPagerDutyURL(server.URL)).Page(pageVuln, "php")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "400")
+}
@@ -0,0 +1,86 @@
+package sink
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+// AuthorPublicationsEvent 是作者发布了新漏洞时发送的事件类型
+const AuthorPublicationsEvent = "author.new_publications"
+
+// AuthorPublications 是作者发布了新漏洞的通知内容
+type AuthorPublications struct {
+	Event           string                `json:"event"` // 固定为 author.new_publications
+	AuthorID        string                `json:"author_id"`
+	AuthorName      string                `json:"author_name,omitempty"`
+	AuthorURL       string                `json:"author_url"`
+	CheckedAt       time.Time             `json:"checked_at"`
+	Vulnerabilities []model.Vulnerability `json:"vulnerabilities"`
+}
+
+// CVEUpdatedEvent 是重新获取CVE详情时发现修改后发送的事件类型
+const CVEUpdatedEvent = "cve.updated"
+
+// CVEUpdates 是CVE详情被修改的通知内容
+type CVEUpdates struct {
+	Event     string            `json:"event"` // 固定为 cve.updated
+	CheckedAt time.Time         `json:"checked_at"`
+	Updates   []CVEUpdateNotice `json:"updates"`
+}
+
+// CVEUpdateNotice 是一个被修改的CVE及引用它的漏洞
+type CVEUpdateNotice struct {
+	Detail          model.CveDetail       `json:"detail"` // 修改后的CVE详情，Change中是修改前的修改日期和评分
+	Vulnerabilities []model.Vulnerability `json:"vulnerabilities,omitempty"`
+}
+
+// WebhookOption 是设置Webhook选项的函数类型
+type WebhookOption func(*Webhook)
+
+// Webhook 将通知以JSON格式POST到指定地址，可以对接Slack工作流、n8n等接受JSON的服务
+type Webhook struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+// WithWebhookHeader 设置请求头，例如用于认证的 Authorization
+func WithWebhookHeader(key, value string) WebhookOption {
+	return func(w *Webhook) {
+		w.headers[key] = value
+	}
+}
+
+// WithWebhookHTTPClient 设置使用的HTTP客户端，默认超时时间为30秒
+func WithWebhookHTTPClient(client *http.Client) WebhookOption {
+	return func(w *Webhook) {
+		if client != nil {
+			w.client = client
+		}
+	}
+}
+
+// NewWebhook 创建发送到指定地址的Webhook
+func NewWebhook(url string, options ...WebhookOption) *Webhook {
+	w := &Webhook{
+		url:     url,
+		headers: make(map[string]string),
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+	for _, option := range options {
+		option(w)
+	}
+	return w
+}
+
+// Send 发送一条通知，payload会被编码为JSON作为请求体
+func (w *Webhook) Send(payload interface{}) error {
+	if w.url == "" {
+		return errors.New("Webhook地址不能为空")
+	}
+	_, err := postJSON(w.client, "Webhook", w.url, w.headers, payload)
+	return err
+}
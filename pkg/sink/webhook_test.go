@@ -0,0 +1,52 @@
+package sink
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+func TestWebhookSend(t *testing.T) {
+	var got AuthorPublications
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	payload := AuthorPublications{
+		Event:           AuthorPublicationsEvent,
+		AuthorID:        "alice",
+		AuthorURL:       "https://cxsecurity.com/author/alice/1/",
+		CheckedAt:       time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		Vulnerabilities: []model.Vulnerability{{ID: "WLB-2024010001", Title: "Foo XSS"}},
+	}
+	hook := NewWebhook(server.URL, WithWebhookHeader("Authorization", "Bearer secret"))
+	require.NoError(t, hook.Send(payload))
+	assert.Equal(t, AuthorPublicationsEvent, got.Event)
+	assert.Equal(t, "alice", got.AuthorID)
+	require.Len(t, got.Vulnerabilities, 1)
+	assert.Equal(t, "WLB-2024010001", got.Vulnerabilities[0].ID)
+}
+
+func TestWebhookSendError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "denied", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	err := NewWebhook(server.URL).Send(map[string]string{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "403")
+	assert.Error(t, NewWebhook("").Send(nil))
+}
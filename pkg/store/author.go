@@ -0,0 +1,47 @@
+package store
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+// AuthorID 返回记录的作者ID，即作者页面URL /author/<ID>/ 中的名称，没有作者URL时返回空字符串
+func AuthorID(v model.Vulnerability) string {
+	u, err := url.Parse(v.AuthorURL)
+	if err != nil {
+		return ""
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 2 || parts[0] != "author" {
+		return ""
+	}
+	id, err := url.PathUnescape(parts[1])
+	if err != nil {
+		return parts[1]
+	}
+	return id
+}
+
+// ByAuthor 返回存储中作者的所有记录，按发布日期从新到旧排列
+//
+// author 为作者ID，不区分大小写；没有作者URL的记录按作者名称匹配。
+// 结果包括历次爬取、导入的全部记录，不受作者页面分页数量的限制。
+func (s *Store) ByAuthor(author string) []model.Vulnerability {
+	author = strings.TrimSpace(author)
+	if author == "" {
+		return nil
+	}
+	var items []model.Vulnerability
+	for _, v := range s.All() {
+		id := AuthorID(v)
+		if id == "" {
+			id = strings.TrimSpace(v.Author)
+		}
+		if strings.EqualFold(id, author) {
+			items = append(items, v)
+		}
+	}
+	return items
+}
@@ -0,0 +1,239 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+// DefaultCVEFile 是配置目录下默认的CVE详情存储文件名
+const DefaultCVEFile = "cve_details.json"
+
+// CVEFailure 记录获取CVE详情失败的情况，下次补全时会重试
+type CVEFailure struct {
+	CveID       string    `json:"cve_id"`
+	Error       string    `json:"error"`        // 最后一次失败的原因
+	Attempts    int       `json:"attempts"`     // 失败次数
+	LastAttempt time.Time `json:"last_attempt"` // 最后一次尝试的时间
+}
+
+// cveFile 是CVE详情存储文件的格式
+type cveFile struct {
+	Details  []model.CveDetail `json:"details"`
+	Failures []CVEFailure      `json:"failures,omitempty"`
+}
+
+// CVEStore 是CVE详情的存储，按CVE编号保存，同时记录获取失败的CVE
+// 在内存中修改，调用Save后写入文件
+type CVEStore struct {
+	path     string
+	details  map[string]model.CveDetail
+	failures map[string]CVEFailure
+	fileMode os.FileMode
+	dirMode  os.FileMode
+}
+
+// OpenCVE 打开CVE详情存储文件，文件不存在时返回空的存储
+//
+// 示例:
+//
+//	cves, err := store.OpenCVE("data/cve_details.json")
+func OpenCVE(path string, opts ...Option) (*CVEStore, error) {
+	// 选项作用于Store，这里只取其中的权限设置
+	s := &Store{fileMode: crawler.DefaultFileMode, dirMode: crawler.DefaultDirMode}
+	for _, opt := range opts {
+		opt(s)
+	}
+	c := &CVEStore{
+		path:     path,
+		details:  make(map[string]model.CveDetail),
+		failures: make(map[string]CVEFailure),
+		fileMode: s.fileMode,
+		dirMode:  s.dirMode,
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取CVE详情存储文件失败: %w", err)
+	}
+	var f cveFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("解析CVE详情存储文件 %s 失败: %w", path, err)
+	}
+	for _, d := range f.Details {
+		if d.CveID != "" {
+			c.details[d.CveID] = d
+		}
+	}
+	for _, failure := range f.Failures {
+		if failure.CveID != "" {
+			c.failures[failure.CveID] = failure
+		}
+	}
+	return c, nil
+}
+
+// Path 返回存储文件的路径
+func (c *CVEStore) Path() string {
+	return c.path
+}
+
+// Len 返回CVE详情的数量
+func (c *CVEStore) Len() int {
+	return len(c.details)
+}
+
+// Get 按CVE编号返回CVE详情
+func (c *CVEStore) Get(cveID string) (model.CveDetail, bool) {
+	d, ok := c.details[cveID]
+	return d, ok
+}
+
+// All 返回所有CVE详情，按CVE编号排列
+func (c *CVEStore) All() []model.CveDetail {
+	details := make([]model.CveDetail, 0, len(c.details))
+	for _, d := range c.details {
+		details = append(details, d)
+	}
+	sort.Slice(details, func(i, j int) bool { return details[i].CveID < details[j].CveID })
+	return details
+}
+
+// Put 写入一条CVE详情，并清除该CVE的失败记录
+func (c *CVEStore) Put(d model.CveDetail) {
+	if d.CveID == "" {
+		return
+	}
+	c.details[d.CveID] = d
+	delete(c.failures, d.CveID)
+}
+
+// Sync 写入一次获取到的CVE详情并记录获取时间，与已保存的详情比较，返回本次发现的修改
+// 第一次获取或没有变化时返回nil，之前发现的修改保留在详情的Change中，直到下一次发现修改
+func (c *CVEStore) Sync(d model.CveDetail, now time.Time) *model.CveChange {
+	if d.CveID == "" {
+		return nil
+	}
+	d.LastSeen = now
+	old, ok := c.details[d.CveID]
+	d.Change = old.Change
+	var change *model.CveChange
+	if ok {
+		if fields := changedFields(old, d); len(fields) > 0 {
+			change = &model.CveChange{DetectedAt: now, Fields: fields, PreviousModified: old.Modified, PreviousScore: old.Score()}
+			d.Change = change
+		}
+	}
+	c.Put(d)
+	return change
+}
+
+// changedFields 返回两次获取之间发生变化的字段，不比较每次获取都可能不同的相关漏洞
+func changedFields(old, cur model.CveDetail) []string {
+	var fields []string
+	if !cur.Modified.IsZero() && !cur.Modified.Equal(old.Modified) {
+		fields = append(fields, "modified")
+	}
+	vector := func(d model.CveDetail) string {
+		if d.Cvss3 == nil {
+			return ""
+		}
+		return d.Cvss3.Vector
+	}
+	if old.Score() != cur.Score() || vector(old) != vector(cur) {
+		fields = append(fields, "cvss")
+	}
+	if strings.TrimSpace(old.Description) != strings.TrimSpace(cur.Description) {
+		fields = append(fields, "description")
+	}
+	if old.Type != cur.Type {
+		fields = append(fields, "type")
+	}
+	if !sameStrings(old.References, cur.References) {
+		fields = append(fields, "references")
+	}
+	software := func(d model.CveDetail) []string {
+		names := make([]string, len(d.AffectedSoftware))
+		for i, sw := range d.AffectedSoftware {
+			names[i] = sw.VendorName + "/" + sw.ProductName
+		}
+		return names
+	}
+	if !sameStrings(software(old), software(cur)) {
+		fields = append(fields, "affected_software")
+	}
+	return fields
+}
+
+// sameStrings 判断两个列表是否包含相同的元素，不考虑顺序
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	counts := make(map[string]int, len(a))
+	for _, s := range a {
+		counts[s]++
+	}
+	for _, s := range b {
+		if counts[s] == 0 {
+			return false
+		}
+		counts[s]--
+	}
+	return true
+}
+
+// Failure 返回CVE的失败记录
+func (c *CVEStore) Failure(cveID string) (CVEFailure, bool) {
+	f, ok := c.failures[cveID]
+	return f, ok
+}
+
+// Failures 返回所有失败记录，按CVE编号排列
+func (c *CVEStore) Failures() []CVEFailure {
+	failures := make([]CVEFailure, 0, len(c.failures))
+	for _, f := range c.failures {
+		failures = append(failures, f)
+	}
+	sort.Slice(failures, func(i, j int) bool { return failures[i].CveID < failures[j].CveID })
+	return failures
+}
+
+// RecordFailure 记录一次获取失败，累加失败次数
+func (c *CVEStore) RecordFailure(cveID string, err error, now time.Time) CVEFailure {
+	f := c.failures[cveID]
+	f.CveID = cveID
+	f.Error = err.Error()
+	f.Attempts++
+	f.LastAttempt = now
+	c.failures[cveID] = f
+	return f
+}
+
+// Save 将CVE详情和失败记录原子地写入存储文件
+func (c *CVEStore) Save() error {
+	f := cveFile{Details: c.All(), Failures: c.Failures()}
+
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化CVE详情失败: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), c.dirMode); err != nil {
+		return fmt.Errorf("创建存储目录失败: %w", err)
+	}
+	if err := crawler.WriteFileAtomic(c.path, data, c.fileMode, false); err != nil {
+		return fmt.Errorf("保存CVE详情存储文件失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package store
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+// cveRefPattern 用于从漏洞记录的CVE字段中找出所有CVE编号
+var cveRefPattern = regexp.MustCompile(`(?i)CVE-\d{4}-\d{4,}`)
+
+// errEmptyDetail 表示CVE页面没有解析出内容，例如cxsecurity还没有收录该CVE
+var errEmptyDetail = errors.New("CVE详情页面没有内容")
+
+// CVEFetcher 获取一个CVE的详情，通常是 Crawler.CrawlCveDetail
+type CVEFetcher func(cveID string) (*model.CveDetail, error)
+
+// EnrichOptions 是补全CVE详情的选项
+type EnrichOptions struct {
+	Limit        int              // 本次最多请求的CVE数量，0表示不限制
+	MaxAttempts  int              // 失败达到该次数后不再重试，0表示总是重试
+	DryRun       bool             // 只列出需要补全的CVE，不发送请求
+	RefreshAfter time.Duration    // 大于0时也重新获取最后一次获取早于该时长的CVE，发现修改时标记引用它的漏洞
+	Now          func() time.Time // 记录失败时间使用的时钟，为nil时使用time.Now
+}
+
+// EnrichResult 是补全一个CVE的结果
+type EnrichResult struct {
+	CveID    string `json:"cve_id"`
+	Status   string `json:"status"` // fetched、failed、pending 或 given_up
+	Error    string `json:"error,omitempty"`
+	Attempts int    `json:"attempts,omitempty"` // 累计失败次数
+
+	Change *model.CveChange `json:"change,omitempty"` // 重新获取时发现的修改，只有updated状态有
+}
+
+// 补全结果的状态
+const (
+	EnrichFetched = "fetched"  // 已获取并保存
+	EnrichFailed  = "failed"   // 本次获取失败，下次会重试
+	EnrichPending = "pending"  // 因 DryRun 或 Limit 本次没有请求
+	EnrichGivenUp = "given_up" // 失败次数达到 MaxAttempts，不再重试
+
+	EnrichUpdated   = "updated"   // 重新获取后发现修改
+	EnrichUnchanged = "unchanged" // 重新获取后没有变化
+)
+
+// MissingCVEs 返回存储中的漏洞引用了、但CVE详情存储中还没有的CVE编号
+// 从未尝试过的CVE排在前面，之前失败过的按失败次数从少到多排在后面
+func MissingCVEs(s *Store, cves *CVEStore) []string {
+	seen := make(map[string]bool)
+	var missing []string
+	for _, v := range s.items {
+		for _, ref := range cveRefPattern.FindAllString(v.CVE, -1) {
+			id, err := model.ParseCVEID(ref)
+			if err != nil || seen[id.String()] {
+				continue
+			}
+			seen[id.String()] = true
+			if _, ok := cves.Get(id.String()); !ok {
+				missing = append(missing, id.String())
+			}
+		}
+	}
+	sort.Slice(missing, func(i, j int) bool {
+		fi, _ := cves.Failure(missing[i])
+		fj, _ := cves.Failure(missing[j])
+		if fi.Attempts != fj.Attempts {
+			return fi.Attempts < fj.Attempts
+		}
+		return missing[i] < missing[j]
+	})
+	return missing
+}
+
+// StaleCVEs 返回存储中的漏洞引用了、CVE详情最后一次获取早于before的CVE编号，最久没有获取的排在前面
+func StaleCVEs(s *Store, cves *CVEStore, before time.Time) []string {
+	seen := make(map[string]bool)
+	var stale []string
+	for _, v := range s.items {
+		for _, ref := range cveRefPattern.FindAllString(v.CVE, -1) {
+			id, err := model.ParseCVEID(ref)
+			if err != nil || seen[id.String()] {
+				continue
+			}
+			seen[id.String()] = true
+			if d, ok := cves.Get(id.String()); ok && d.LastSeen.Before(before) {
+				stale = append(stale, id.String())
+			}
+		}
+	}
+	sort.Slice(stale, func(i, j int) bool {
+		di, _ := cves.Get(stale[i])
+		dj, _ := cves.Get(stale[j])
+		if !di.LastSeen.Equal(dj.LastSeen) {
+			return di.LastSeen.Before(dj.LastSeen)
+		}
+		return stale[i] < stale[j]
+	})
+	return stale
+}
+
+// markCVEUpdate 在引用了该CVE的漏洞上记录CVE详情的修改，返回标记的漏洞数量
+func markCVEUpdate(s *Store, d model.CveDetail, change *model.CveChange) int {
+	update := &model.CVEUpdate{
+		CveID:         d.CveID,
+		Modified:      d.Modified,
+		DetectedAt:    change.DetectedAt,
+		Fields:        change.Fields,
+		PreviousScore: change.PreviousScore,
+		Score:         d.Score(),
+	}
+	marked := 0
+	for key, v := range s.items {
+		for _, ref := range cveRefPattern.FindAllString(v.CVE, -1) {
+			if id, err := model.ParseCVEID(ref); err == nil && id.String() == d.CveID {
+				v.CVEUpdate = update
+				s.items[key] = v
+				marked++
+				break
+			}
+		}
+	}
+	return marked
+}
+
+// EnrichCVEs 为存储中引用了CVE但还没有CVE详情的漏洞补全CVE详情
+//
+// 请求逐个进行，限速由fetch使用的客户端负责。获取失败的CVE记录在CVE详情存储中，
+// 下次调用时重试，达到 MaxAttempts 后不再请求。补全后需要调用 cves.Save 保存。
+// 指定 RefreshAfter 时还会重新获取已有的CVE详情，发现修改日期、评分等变化时
+// 在引用它的漏洞上记录 CVEUpdate，这时还需要调用 s.Save 保存漏洞存储。
+//
+// 示例:
+//
+//	c := crawler.NewCrawler(crawler.WithRelatedPages(0))
+//	fetch := func(id string) (*model.CveDetail, error) { return c.CrawlCveDetail(id, "") }
+//	results := store.EnrichCVEs(s, cves, fetch, store.EnrichOptions{Limit: 100, MaxAttempts: 5})
+func EnrichCVEs(s *Store, cves *CVEStore, fetch CVEFetcher, opts EnrichOptions) []EnrichResult {
+	now := opts.Now
+	if now == nil {
+		now = time.Now
+	}
+
+	missing := MissingCVEs(s, cves)
+	if opts.RefreshAfter > 0 {
+		missing = append(missing, StaleCVEs(s, cves, now().Add(-opts.RefreshAfter))...)
+	}
+	results := make([]EnrichResult, 0, len(missing))
+	requested := 0
+	for _, id := range missing {
+		_, refresh := cves.Get(id)
+		failure, _ := cves.Failure(id)
+		result := EnrichResult{CveID: id, Error: failure.Error, Attempts: failure.Attempts}
+		switch {
+		case opts.MaxAttempts > 0 && failure.Attempts >= opts.MaxAttempts:
+			result.Status = EnrichGivenUp
+		case opts.DryRun || (opts.Limit > 0 && requested >= opts.Limit):
+			result.Status = EnrichPending
+		default:
+			requested++
+			detail, err := fetch(id)
+			if err == nil && (detail == nil || detail.CveID == "") {
+				err = errEmptyDetail
+			}
+			if err != nil {
+				failure = cves.RecordFailure(id, err, now())
+				result.Status, result.Error, result.Attempts = EnrichFailed, failure.Error, failure.Attempts
+			} else {
+				change := cves.Sync(*detail, now())
+				result.Status, result.Error, result.Attempts = EnrichFetched, "", 0
+				switch {
+				case change != nil:
+					markCVEUpdate(s, *detail, change)
+					result.Status, result.Change = EnrichUpdated, change
+				case refresh:
+					result.Status = EnrichUnchanged
+				}
+			}
+		}
+		results = append(results, result)
+	}
+	return results
+}
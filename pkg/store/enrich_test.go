@@ -0,0 +1,147 @@
+package store
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnrichCVEs(t *testing.T) {
+	dir := t.TempDir()
+	s, err := Open(filepath.Join(dir, DefaultFile))
+	require.NoError(t, err)
+	s.Put(model.Vulnerability{ID: "WLB-1", CVE: "CVE-2024-0003"})
+	s.Put(model.Vulnerability{ID: "WLB-2", CVE: "cve-2024-0001, CVE-2024-0002"})
+	s.Put(model.Vulnerability{ID: "WLB-3", CVE: "CVE-2024-0001"})
+	s.Put(model.Vulnerability{ID: "WLB-4"})
+
+	cvePath := filepath.Join(dir, "db", DefaultCVEFile)
+	cves, err := OpenCVE(cvePath, WithPermissions(0o600, 0o700))
+	require.NoError(t, err)
+	cves.Put(model.CveDetail{CveID: "CVE-2024-0003"})
+	assert.Equal(t, []string{"CVE-2024-0001", "CVE-2024-0002"}, MissingCVEs(s, cves))
+
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	var requested []string
+	fetch := func(id string) (*model.CveDetail, error) {
+		requested = append(requested, id)
+		if id == "CVE-2024-0002" {
+			return nil, errors.New("timeout")
+		}
+		return &model.CveDetail{CveID: id, CvssBaseScore: 5}, nil
+	}
+	opts := EnrichOptions{MaxAttempts: 2, Now: func() time.Time { return now }}
+
+	// 只列出需要补全的CVE
+	results := EnrichCVEs(s, cves, fetch, EnrichOptions{DryRun: true})
+	assert.Empty(t, requested)
+	assert.Equal(t, []EnrichResult{{CveID: "CVE-2024-0001", Status: EnrichPending}, {CveID: "CVE-2024-0002", Status: EnrichPending}}, results)
+
+	results = EnrichCVEs(s, cves, fetch, opts)
+	assert.Equal(t, []EnrichResult{
+		{CveID: "CVE-2024-0001", Status: EnrichFetched},
+		{CveID: "CVE-2024-0002", Status: EnrichFailed, Error: "timeout", Attempts: 1},
+	}, results)
+	assert.Equal(t, []CVEFailure{{CveID: "CVE-2024-0002", Error: "timeout", Attempts: 1, LastAttempt: now}}, cves.Failures())
+	require.NoError(t, cves.Save())
+
+	info, err := os.Stat(cvePath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	// 重新打开后失败的CVE会被重试，达到MaxAttempts后不再请求
+	cves, err = OpenCVE(cvePath)
+	require.NoError(t, err)
+	assert.Equal(t, 2, cves.Len())
+	requested = nil
+	results = EnrichCVEs(s, cves, fetch, opts)
+	assert.Equal(t, []string{"CVE-2024-0002"}, requested)
+	assert.Equal(t, 2, results[0].Attempts)
+
+	requested = nil
+	results = EnrichCVEs(s, cves, fetch, opts)
+	assert.Empty(t, requested)
+	assert.Equal(t, []EnrichResult{{CveID: "CVE-2024-0002", Status: EnrichGivenUp, Error: "timeout", Attempts: 2}}, results)
+
+	// 获取成功后清除失败记录
+	cves.Put(model.CveDetail{CveID: "CVE-2024-0002"})
+	assert.Empty(t, cves.Failures())
+	assert.Empty(t, MissingCVEs(s, cves))
+}
+
+func TestEnrichCVEsLimit(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), DefaultFile))
+	require.NoError(t, err)
+	s.Put(model.Vulnerability{ID: "WLB-1", CVE: "CVE-2024-0001"})
+	s.Put(model.Vulnerability{ID: "WLB-2", CVE: "CVE-2024-0002"})
+	s.Put(model.Vulnerability{ID: "WLB-3", CVE: "CVE-2024-0003"})
+	cves, err := OpenCVE(filepath.Join(t.TempDir(), DefaultCVEFile))
+	require.NoError(t, err)
+
+	// 之前失败过的CVE排在从未尝试过的后面
+	cves.RecordFailure("CVE-2024-0001", errors.New("503"), time.Now())
+	fetch := func(id string) (*model.CveDetail, error) {
+		if id == "CVE-2024-0003" {
+			return &model.CveDetail{}, nil
+		}
+		return &model.CveDetail{CveID: id}, nil
+	}
+	results := EnrichCVEs(s, cves, fetch, EnrichOptions{Limit: 2})
+	require.Len(t, results, 3)
+	assert.Equal(t, EnrichResult{CveID: "CVE-2024-0002", Status: EnrichFetched}, results[0])
+	assert.Equal(t, EnrichResult{CveID: "CVE-2024-0003", Status: EnrichFailed, Error: errEmptyDetail.Error(), Attempts: 1}, results[1])
+	assert.Equal(t, EnrichResult{CveID: "CVE-2024-0001", Status: EnrichPending, Error: "503", Attempts: 1}, results[2])
+}
+
+func TestEnrichCVEsRefresh(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), DefaultFile))
+	require.NoError(t, err)
+	s.Put(model.Vulnerability{ID: "WLB-1", CVE: "CVE-2024-0001"})
+	s.Put(model.Vulnerability{ID: "WLB-2", CVE: "CVE-2024-0001, CVE-2024-0002"})
+	s.Put(model.Vulnerability{ID: "WLB-3", CVE: "CVE-2024-0003"})
+	cves, err := OpenCVE(filepath.Join(t.TempDir(), DefaultCVEFile))
+	require.NoError(t, err)
+
+	day := func(d int) time.Time { return time.Date(2024, 5, d, 0, 0, 0, 0, time.UTC) }
+	cves.Sync(model.CveDetail{CveID: "CVE-2024-0001", Modified: day(1), CvssBaseScore: 5, References: []string{"a", "b"}}, day(1))
+	cves.Sync(model.CveDetail{CveID: "CVE-2024-0002", Modified: day(1)}, day(2))
+	cves.Sync(model.CveDetail{CveID: "CVE-2024-0003", Modified: day(1)}, day(9))
+	assert.Equal(t, []string{"CVE-2024-0001", "CVE-2024-0002"}, StaleCVEs(s, cves, day(5)))
+
+	fetch := func(id string) (*model.CveDetail, error) {
+		if id == "CVE-2024-0001" {
+			return &model.CveDetail{CveID: id, Modified: day(8), Cvss3: &model.Cvss3{BaseScore: 9.8}, References: []string{"b", "a"}}, nil
+		}
+		return &model.CveDetail{CveID: id, Modified: day(1)}, nil
+	}
+	results := EnrichCVEs(s, cves, fetch, EnrichOptions{RefreshAfter: 5 * 24 * time.Hour, Now: func() time.Time { return day(10) }})
+	change := &model.CveChange{DetectedAt: day(10), Fields: []string{"modified", "cvss"}, PreviousModified: day(1), PreviousScore: 5}
+	assert.Equal(t, []EnrichResult{
+		{CveID: "CVE-2024-0001", Status: EnrichUpdated, Change: change},
+		{CveID: "CVE-2024-0002", Status: EnrichUnchanged},
+	}, results)
+
+	d, _ := cves.Get("CVE-2024-0001")
+	assert.Equal(t, day(10), d.LastSeen)
+	assert.Equal(t, change, d.Change)
+	update := &model.CVEUpdate{CveID: "CVE-2024-0001", Modified: day(8), DetectedAt: day(10), Fields: []string{"modified", "cvss"}, PreviousScore: 5, Score: 9.8}
+	for _, id := range []string{"WLB-1", "WLB-2"} {
+		v, _ := s.Get(id)
+		assert.Equal(t, update, v.CVEUpdate, id)
+	}
+	v, _ := s.Get("WLB-3")
+	assert.Nil(t, v.CVEUpdate)
+
+	// 没有新的修改时保留之前发现的修改
+	again, _ := fetch("CVE-2024-0001")
+	assert.Nil(t, cves.Sync(*again, day(11)))
+	d, _ = cves.Get("CVE-2024-0001")
+	assert.Equal(t, change, d.Change)
+	assert.Empty(t, StaleCVEs(s, cves, day(5)))
+}
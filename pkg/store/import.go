@@ -0,0 +1,91 @@
+package store
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+)
+
+// FileReport 是导入一个结果文件的统计
+type FileReport struct {
+	File      string `json:"file"`
+	Records   int    `json:"records"`   // 文件中的记录数
+	Added     int    `json:"added"`     // 新增的记录数
+	Updated   int    `json:"updated"`   // 合并更新的记录数
+	Unchanged int    `json:"unchanged"` // 已存在且没有变化的记录数
+	Skipped   int    `json:"skipped"`   // 没有ID和URL而跳过的记录数
+	Error     string `json:"error,omitempty"`
+}
+
+// Import 将之前保存的JSON结果文件导入存储，按漏洞ID去重
+// path可以是文件或目录，目录会递归导入其中所有的 .json 文件(跳过存储文件本身)。
+// 支持 crawler.LoadResultFile 能识别的所有格式，无法识别的文件记录在FileReport.Error中，不影响其他文件。
+// 导入后需要调用Save保存。
+//
+// 示例:
+//
+//	reports, err := s.Import("results")
+func (s *Store) Import(path string) ([]FileReport, error) {
+	files, err := resultFiles(path)
+	if err != nil {
+		return nil, err
+	}
+	self, _ := filepath.Abs(s.path)
+
+	reports := make([]FileReport, 0, len(files))
+	for _, file := range files {
+		if abs, _ := filepath.Abs(file); abs == self {
+			continue
+		}
+		report := FileReport{File: file}
+		items, err := crawler.LoadResultFile(file)
+		if err != nil {
+			report.Error = err.Error()
+			reports = append(reports, report)
+			continue
+		}
+		report.Records = len(items)
+		for _, v := range items {
+			switch s.Put(v) {
+			case Added:
+				report.Added++
+			case Updated:
+				report.Updated++
+			case Unchanged:
+				report.Unchanged++
+			default:
+				report.Skipped++
+			}
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// resultFiles 返回path本身，或目录中按路径排序的所有 .json 文件
+func resultFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	var files []string
+	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && strings.EqualFold(filepath.Ext(p), ".json") {
+			files = append(files, p)
+		}
+		return nil
+	})
+	sort.Strings(files)
+	return files, err
+}
@@ -0,0 +1,64 @@
+package store
+
+import (
+	"sync"
+	"time"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+// ReadThrough 将漏洞存储和CVE详情存储适配为 crawler.LocalStore，用于读穿透模式
+// 每次写入后立即保存对应的存储文件，可以并发使用。
+// 同一个存储文件不应同时被其他进程修改，否则后保存的一方会覆盖另一方的修改。
+//
+// 示例:
+//
+//	local := store.NewReadThrough(vulns, cves)
+//	c := crawler.NewCrawler(crawler.WithReadThrough(crawler.FetchLocalFirst, 24*time.Hour, local))
+type ReadThrough struct {
+	mu    sync.Mutex
+	vulns *Store
+	cves  *CVEStore
+	now   func() time.Time
+}
+
+// NewReadThrough 创建读穿透模式使用的本地存储
+func NewReadThrough(vulns *Store, cves *CVEStore) *ReadThrough {
+	return &ReadThrough{vulns: vulns, cves: cves, now: time.Now}
+}
+
+// Vulnerability 返回保存的漏洞详情
+// 只有从详情页获取过(有获取时间或正文)的记录才返回，列表页和搜索结果中的记录缺少正文等字段
+func (r *ReadThrough) Vulnerability(id string) (model.Vulnerability, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	v, ok := r.vulns.Get(id)
+	if !ok || (v.LastSeen.IsZero() && v.Content == "") {
+		return model.Vulnerability{}, false
+	}
+	return v, true
+}
+
+// SaveVulnerability 记录获取时间后写入漏洞存储并保存文件
+func (r *ReadThrough) SaveVulnerability(v model.Vulnerability) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	v.LastSeen = r.now()
+	r.vulns.Put(v)
+	return r.vulns.Save()
+}
+
+// CveDetail 返回保存的CVE详情
+func (r *ReadThrough) CveDetail(id string) (model.CveDetail, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.cves.Get(id)
+}
+
+// SaveCveDetail 写入CVE详情存储并保存文件，与已保存的详情比较并记录修改，参见 CVEStore.Sync
+func (r *ReadThrough) SaveCveDetail(d model.CveDetail) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cves.Sync(d, r.now())
+	return r.cves.Save()
+}
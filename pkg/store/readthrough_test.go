@@ -0,0 +1,53 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+var _ crawler.LocalStore = (*ReadThrough)(nil)
+
+func TestReadThrough(t *testing.T) {
+	dir := t.TempDir()
+	vulns, err := Open(filepath.Join(dir, DefaultFile))
+	require.NoError(t, err)
+	cves, err := OpenCVE(filepath.Join(dir, DefaultCVEFile))
+	require.NoError(t, err)
+	now := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	r := NewReadThrough(vulns, cves)
+	r.now = func() time.Time { return now }
+
+	// 只有列表页字段的记录不作为漏洞详情返回
+	vulns.Put(model.Vulnerability{ID: "WLB-2024010001", Title: "Search"})
+	_, ok := r.Vulnerability("WLB-2024010001")
+	assert.False(t, ok)
+
+	require.NoError(t, r.SaveVulnerability(model.Vulnerability{ID: "WLB-2024010001", Title: "Detail", Content: "PoC"}))
+	v, ok := r.Vulnerability("WLB-2024010001")
+	require.True(t, ok)
+	assert.Equal(t, "Detail", v.Title)
+	assert.Equal(t, now, v.LastSeen)
+
+	require.NoError(t, r.SaveCveDetail(model.CveDetail{CveID: "CVE-2024-0001", Description: "desc"}))
+	d, ok := r.CveDetail("CVE-2024-0001")
+	require.True(t, ok)
+	assert.Equal(t, now, d.LastSeen)
+
+	// 写入后立即保存到文件
+	reopened, err := Open(filepath.Join(dir, DefaultFile))
+	require.NoError(t, err)
+	v, ok = reopened.Get("WLB-2024010001")
+	require.True(t, ok)
+	assert.True(t, now.Equal(v.LastSeen))
+	reopenedCVEs, err := OpenCVE(filepath.Join(dir, DefaultCVEFile))
+	require.NoError(t, err)
+	_, ok = reopenedCVEs.Get("CVE-2024-0001")
+	assert.True(t, ok)
+}
@@ -0,0 +1,288 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+)
+
+// 重试队列的默认文件名，都在配置目录下
+const (
+	DefaultRetryFile      = "retry_queue.json" // 等待重试的条目
+	DefaultDeadLetterFile = "dead_letter.json" // 重试次数用完、不再重试的条目
+)
+
+// DefaultMaxRetries 是条目进入死信文件前最多的失败次数
+const DefaultMaxRetries = 5
+
+// 重试条目的类型
+const (
+	RetryExploit = "exploit" // 漏洞详情，Key为漏洞ID
+	RetryCVE     = "cve"     // CVE详情，Key为CVE编号
+)
+
+// RetryItem 是一个获取或解析失败、等待重试的条目
+type RetryItem struct {
+	Kind        string    `json:"kind"`             // RetryExploit 或 RetryCVE
+	Key         string    `json:"key"`              // 漏洞ID或CVE编号
+	Output      string    `json:"output,omitempty"` // 原来的结果保存路径，重试成功后保存到这里
+	Error       string    `json:"error"`            // 最后一次失败的原因
+	Attempts    int       `json:"attempts"`         // 失败次数
+	FirstFailed time.Time `json:"first_failed"`     // 第一次失败的时间
+	LastAttempt time.Time `json:"last_attempt"`     // 最后一次尝试的时间
+}
+
+// retryKey 返回条目在队列中的键
+func retryKey(kind, key string) string {
+	return kind + ":" + key
+}
+
+// RetryQueue 是批量爬取中失败条目的持久化重试队列
+//
+// 失败的条目记录在队列文件中，之后可以用 Retry 重新处理；失败次数达到 MaxAttempts 后
+// 条目移到死信文件，不再自动重试，死信文件可以作为失败报告查看。
+// 在内存中修改，调用Save后写入文件。
+type RetryQueue struct {
+	// MaxAttempts 是条目移到死信文件前最多的失败次数，0表示不限制，默认为 DefaultMaxRetries
+	MaxAttempts int
+
+	path     string
+	deadPath string
+	items    map[string]RetryItem
+	dead     map[string]RetryItem
+	fileMode os.FileMode
+	dirMode  os.FileMode
+}
+
+// OpenRetryQueue 打开重试队列，文件不存在时返回空的队列
+// 死信文件与队列文件在同一目录下，文件名为 DefaultDeadLetterFile
+//
+// 示例:
+//
+//	q, err := store.OpenRetryQueue("data/retry_queue.json")
+func OpenRetryQueue(path string, opts ...Option) (*RetryQueue, error) {
+	// 选项作用于Store，这里只取其中的权限设置
+	s := &Store{fileMode: crawler.DefaultFileMode, dirMode: crawler.DefaultDirMode}
+	for _, opt := range opts {
+		opt(s)
+	}
+	q := &RetryQueue{
+		MaxAttempts: DefaultMaxRetries,
+		path:        path,
+		deadPath:    filepath.Join(filepath.Dir(path), DefaultDeadLetterFile),
+		items:       make(map[string]RetryItem),
+		dead:        make(map[string]RetryItem),
+		fileMode:    s.fileMode,
+		dirMode:     s.dirMode,
+	}
+	if err := readRetryItems(q.path, q.items); err != nil {
+		return nil, err
+	}
+	if err := readRetryItems(q.deadPath, q.dead); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// readRetryItems 读取条目数组到items中，文件不存在时不做任何处理
+func readRetryItems(path string, items map[string]RetryItem) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("读取重试队列文件失败: %w", err)
+	}
+	var list []RetryItem
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("解析重试队列文件 %s 失败: %w", path, err)
+	}
+	for _, item := range list {
+		if item.Kind != "" && item.Key != "" {
+			items[retryKey(item.Kind, item.Key)] = item
+		}
+	}
+	return nil
+}
+
+// Path 返回队列文件的路径
+func (q *RetryQueue) Path() string {
+	return q.path
+}
+
+// DeadLetterPath 返回死信文件的路径
+func (q *RetryQueue) DeadLetterPath() string {
+	return q.deadPath
+}
+
+// Record 记录一次失败，累加失败次数
+// 失败次数达到 MaxAttempts 时条目移到死信文件，第二个返回值为true。
+// 已经在死信文件中的条目再次失败时只更新错误信息。
+func (q *RetryQueue) Record(kind, key, output string, err error, now time.Time) (RetryItem, bool) {
+	k := retryKey(kind, key)
+	if item, ok := q.dead[k]; ok {
+		item.Error, item.LastAttempt = err.Error(), now
+		item.Attempts++
+		q.dead[k] = item
+		return item, true
+	}
+
+	item, ok := q.items[k]
+	if !ok {
+		item = RetryItem{Kind: kind, Key: key, FirstFailed: now}
+	}
+	if output != "" {
+		item.Output = output
+	}
+	item.Error = err.Error()
+	item.Attempts++
+	item.LastAttempt = now
+	if q.MaxAttempts > 0 && item.Attempts >= q.MaxAttempts {
+		delete(q.items, k)
+		q.dead[k] = item
+		return item, true
+	}
+	q.items[k] = item
+	return item, false
+}
+
+// Resolve 在条目处理成功后将其从队列和死信文件中删除，条目不存在时返回false
+func (q *RetryQueue) Resolve(kind, key string) bool {
+	k := retryKey(kind, key)
+	_, pending := q.items[k]
+	_, dead := q.dead[k]
+	delete(q.items, k)
+	delete(q.dead, k)
+	return pending || dead
+}
+
+// Pending 返回等待重试的条目，失败次数少的在前，同样次数时按类型和键排列
+func (q *RetryQueue) Pending() []RetryItem {
+	return sortedRetryItems(q.items)
+}
+
+// Dead 返回死信文件中的条目，按类型和键排列
+func (q *RetryQueue) Dead() []RetryItem {
+	return sortedRetryItems(q.dead)
+}
+
+// Requeue 将死信文件中的条目放回队列并清零失败次数，返回放回的数量
+func (q *RetryQueue) Requeue() int {
+	n := len(q.dead)
+	for k, item := range q.dead {
+		item.Attempts = 0
+		q.items[k] = item
+	}
+	q.dead = make(map[string]RetryItem)
+	return n
+}
+
+// sortedRetryItems 将条目按失败次数、类型和键排列
+func sortedRetryItems(items map[string]RetryItem) []RetryItem {
+	list := make([]RetryItem, 0, len(items))
+	for _, item := range items {
+		list = append(list, item)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Attempts != list[j].Attempts {
+			return list[i].Attempts < list[j].Attempts
+		}
+		return retryKey(list[i].Kind, list[i].Key) < retryKey(list[j].Kind, list[j].Key)
+	})
+	return list
+}
+
+// Save 将队列和死信文件原子地写入磁盘
+func (q *RetryQueue) Save() error {
+	if err := q.write(q.path, q.Pending()); err != nil {
+		return err
+	}
+	return q.write(q.deadPath, q.Dead())
+}
+
+// write 将条目数组写入文件
+func (q *RetryQueue) write(path string, items []RetryItem) error {
+	data, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化重试队列失败: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), q.dirMode); err != nil {
+		return fmt.Errorf("创建存储目录失败: %w", err)
+	}
+	if err := crawler.WriteFileAtomic(path, data, q.fileMode, false); err != nil {
+		return fmt.Errorf("保存重试队列文件失败: %w", err)
+	}
+	return nil
+}
+
+// RetryFunc 重新处理一个条目，返回nil表示成功
+type RetryFunc func(item RetryItem) error
+
+// RetryOptions 是重新处理队列的选项
+type RetryOptions struct {
+	Limit  int              // 本次最多处理的条目数，0表示不限制
+	DryRun bool             // 只列出等待重试的条目，不处理
+	Now    func() time.Time // 记录失败时间使用的时钟，为nil时使用time.Now
+}
+
+// RetryResult 是重新处理一个条目的结果
+type RetryResult struct {
+	Kind     string `json:"kind"`
+	Key      string `json:"key"`
+	Status   string `json:"status"` // succeeded、failed、dead 或 pending
+	Error    string `json:"error,omitempty"`
+	Attempts int    `json:"attempts,omitempty"` // 累计失败次数
+}
+
+// 重新处理的状态
+const (
+	RetrySucceeded = "succeeded" // 处理成功，已从队列中删除
+	RetryFailed    = "failed"    // 再次失败，仍在队列中
+	RetryDead      = "dead"      // 再次失败且达到 MaxAttempts，移到死信文件
+	RetryPending   = "pending"   // 因 DryRun 或 Limit 本次没有处理
+)
+
+// Retry 按 Pending 的顺序重新处理队列中的条目，处理后需要调用 Save 保存
+//
+// 示例:
+//
+//	c := crawler.NewCrawler()
+//	results := q.Retry(func(item store.RetryItem) error {
+//		_, err := c.CrawlExploit(item.Key, item.Output, "")
+//		return err
+//	}, store.RetryOptions{Limit: 50})
+func (q *RetryQueue) Retry(fn RetryFunc, opts RetryOptions) []RetryResult {
+	now := opts.Now
+	if now == nil {
+		now = time.Now
+	}
+
+	pending := q.Pending()
+	results := make([]RetryResult, 0, len(pending))
+	for i, item := range pending {
+		result := RetryResult{Kind: item.Kind, Key: item.Key, Error: item.Error, Attempts: item.Attempts}
+		if opts.DryRun || (opts.Limit > 0 && i >= opts.Limit) {
+			result.Status = RetryPending
+			results = append(results, result)
+			continue
+		}
+		if err := fn(item); err != nil {
+			updated, dead := q.Record(item.Kind, item.Key, "", err, now())
+			result.Status, result.Error, result.Attempts = RetryFailed, updated.Error, updated.Attempts
+			if dead {
+				result.Status = RetryDead
+			}
+		} else {
+			q.Resolve(item.Kind, item.Key)
+			result.Status, result.Error, result.Attempts = RetrySucceeded, "", 0
+		}
+		results = append(results, result)
+	}
+	return results
+}
@@ -0,0 +1,96 @@
+package store
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryQueueRecord(t *testing.T) {
+	dir := t.TempDir()
+	q, err := OpenRetryQueue(filepath.Join(dir, "db", DefaultRetryFile), WithPermissions(0o600, 0o700))
+	require.NoError(t, err)
+	q.MaxAttempts = 2
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	item, dead := q.Record(RetryExploit, "WLB-1", "out.json", errors.New("timeout"), now)
+	assert.False(t, dead)
+	assert.Equal(t, RetryItem{Kind: RetryExploit, Key: "WLB-1", Output: "out.json", Error: "timeout", Attempts: 1, FirstFailed: now, LastAttempt: now}, item)
+	q.Record(RetryCVE, "CVE-2024-0001", "", errors.New("503"), now)
+
+	// 达到MaxAttempts后移到死信文件
+	item, dead = q.Record(RetryExploit, "WLB-1", "", errors.New("parse"), now.Add(time.Hour))
+	assert.True(t, dead)
+	assert.Equal(t, 2, item.Attempts)
+	assert.Equal(t, "out.json", item.Output)
+	assert.Equal(t, now, item.FirstFailed)
+	require.Len(t, q.Pending(), 1)
+	require.Len(t, q.Dead(), 1)
+	require.NoError(t, q.Save())
+
+	info, err := os.Stat(q.DeadLetterPath())
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	assert.Equal(t, filepath.Join(dir, "db", DefaultDeadLetterFile), q.DeadLetterPath())
+
+	reopened, err := OpenRetryQueue(q.Path())
+	require.NoError(t, err)
+	assert.Equal(t, q.Pending(), reopened.Pending())
+	assert.Equal(t, q.Dead(), reopened.Dead())
+
+	// 成功后从队列和死信文件中删除
+	assert.True(t, reopened.Resolve(RetryExploit, "WLB-1"))
+	assert.False(t, reopened.Resolve(RetryExploit, "WLB-1"))
+	assert.Empty(t, reopened.Dead())
+}
+
+func TestRetryQueueRetry(t *testing.T) {
+	q, err := OpenRetryQueue(filepath.Join(t.TempDir(), DefaultRetryFile))
+	require.NoError(t, err)
+	q.MaxAttempts = 3
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	q.Record(RetryExploit, "WLB-1", "", errors.New("timeout"), now)
+	q.Record(RetryExploit, "WLB-2", "", errors.New("timeout"), now)
+	q.Record(RetryExploit, "WLB-2", "", errors.New("timeout"), now)
+	q.Record(RetryCVE, "CVE-2024-0001", "", errors.New("timeout"), now)
+
+	var processed []string
+	fn := func(item RetryItem) error {
+		processed = append(processed, item.Key)
+		if item.Kind == RetryCVE {
+			return nil
+		}
+		return errors.New("still down")
+	}
+	opts := RetryOptions{Now: func() time.Time { return now }}
+
+	results := q.Retry(fn, RetryOptions{DryRun: true})
+	assert.Empty(t, processed)
+	require.Len(t, results, 3)
+	assert.Equal(t, RetryPending, results[0].Status)
+
+	// 失败次数少的先处理
+	results = q.Retry(fn, RetryOptions{Limit: 2, Now: opts.Now})
+	assert.Equal(t, []string{"CVE-2024-0001", "WLB-1"}, processed)
+	assert.Equal(t, []RetryResult{
+		{Kind: RetryCVE, Key: "CVE-2024-0001", Status: RetrySucceeded},
+		{Kind: RetryExploit, Key: "WLB-1", Status: RetryFailed, Error: "still down", Attempts: 2},
+		{Kind: RetryExploit, Key: "WLB-2", Status: RetryPending, Error: "timeout", Attempts: 2},
+	}, results)
+
+	results = q.Retry(fn, opts)
+	assert.Equal(t, RetryDead, results[0].Status)
+	assert.Equal(t, RetryDead, results[1].Status)
+	assert.Empty(t, q.Pending())
+	assert.Len(t, q.Dead(), 2)
+
+	// 死信条目可以放回队列
+	assert.Equal(t, 2, q.Requeue())
+	assert.Empty(t, q.Dead())
+	assert.Equal(t, 0, q.Pending()[0].Attempts)
+}
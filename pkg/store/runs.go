@@ -0,0 +1,244 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+)
+
+// DefaultRunsFile 是配置目录下默认的运行记录文件名
+const DefaultRunsFile = "runs.json"
+
+// DefaultMaxRuns 是运行记录文件中最多保留的记录数，超过时删除最早的记录
+const DefaultMaxRuns = 1000
+
+// Run 是一次命令运行中HTTP请求的统计，用于容量规划和检查爬取是否对网站足够友好
+type Run struct {
+	Command   string         `json:"command"`          // 命令，例如 "search"、"saved run"
+	Started   time.Time      `json:"started"`          // 开始时间
+	Finished  time.Time      `json:"finished"`         // 结束时间
+	Success   bool           `json:"success"`          // 命令是否成功完成
+	Requests  int            `json:"requests"`         // 发出的请求数，包括重试
+	Retries   int            `json:"retries"`          // 其中重试的请求数
+	Failures  int            `json:"failures"`         // 失败的请求数
+	Bytes     int64          `json:"bytes"`            // 收到的响应正文字节数
+	CacheHits int            `json:"cache_hits"`       // 由CDN缓存返回的请求数
+	Errors    map[string]int `json:"errors,omitempty"` // 按原因统计的错误数，例如 timeout、network、http_503
+}
+
+// Duration 返回运行的时长
+func (r Run) Duration() time.Duration {
+	return r.Finished.Sub(r.Started)
+}
+
+// CacheHitRate 返回由缓存返回的请求比例，没有请求时为0
+func (r Run) CacheHitRate() float64 {
+	if r.Requests == 0 {
+		return 0
+	}
+	return float64(r.CacheHits) / float64(r.Requests)
+}
+
+// SumRuns 汇总多次运行的统计，Command为空，Started和Finished为最早的开始和最晚的结束时间
+func SumRuns(runs []Run) Run {
+	var total Run
+	total.Success = true
+	for i, r := range runs {
+		if i == 0 || r.Started.Before(total.Started) {
+			total.Started = r.Started
+		}
+		if r.Finished.After(total.Finished) {
+			total.Finished = r.Finished
+		}
+		total.Success = total.Success && r.Success
+		total.Requests += r.Requests
+		total.Retries += r.Retries
+		total.Failures += r.Failures
+		total.Bytes += r.Bytes
+		total.CacheHits += r.CacheHits
+		for reason, n := range r.Errors {
+			if total.Errors == nil {
+				total.Errors = make(map[string]int)
+			}
+			total.Errors[reason] += n
+		}
+	}
+	return total
+}
+
+// RunRecorder 在命令运行期间统计HTTP请求，可以并发调用
+//
+// 示例:
+//
+//	rec := store.NewRunRecorder("search", time.Now())
+//	c := crawler.NewCrawler(crawler.WithClientOptions(crawler.WithRequestLogger(rec.Observe)))
+//	// ...
+//	run := rec.Finish(time.Now(), err == nil)
+type RunRecorder struct {
+	mu  sync.Mutex
+	run Run
+}
+
+// NewRunRecorder 创建一次运行的统计
+func NewRunRecorder(command string, started time.Time) *RunRecorder {
+	return &RunRecorder{run: Run{Command: command, Started: started}}
+}
+
+// Observe 记录一次请求，可以直接作为 crawler.WithRequestLogger 的回调
+func (r *RunRecorder) Observe(l crawler.RequestLog) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.run.Requests++
+	if l.Attempt > 1 {
+		r.run.Retries++
+	}
+	if l.Err != nil {
+		r.run.Failures++
+	}
+	r.run.Bytes += int64(l.Size)
+	if l.CacheHit {
+		r.run.CacheHits++
+	}
+	if reason := requestErrorReason(l); reason != "" {
+		if r.run.Errors == nil {
+			r.run.Errors = make(map[string]int)
+		}
+		r.run.Errors[reason]++
+	}
+}
+
+// Requests 返回已记录的请求数
+func (r *RunRecorder) Requests() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.run.Requests
+}
+
+// Finish 结束统计并返回运行记录
+func (r *RunRecorder) Finish(finished time.Time, success bool) Run {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	run := r.run
+	run.Finished, run.Success = finished, success
+	if run.Errors != nil {
+		run.Errors = make(map[string]int, len(r.run.Errors))
+		for reason, n := range r.run.Errors {
+			run.Errors[reason] = n
+		}
+	}
+	return run
+}
+
+// requestErrorReason 返回请求出错的原因，请求成功且状态码小于400时返回空字符串
+// 有状态码时为 http_<状态码>，网络错误分为 timeout 和 network
+func requestErrorReason(l crawler.RequestLog) string {
+	if l.StatusCode >= 400 {
+		return "http_" + strconv.Itoa(l.StatusCode)
+	}
+	if l.Err == nil {
+		return ""
+	}
+	if l.StatusCode > 0 {
+		// 读取响应正文失败
+		return "read"
+	}
+	var netErr net.Error
+	if errors.As(l.Err, &netErr) && netErr.Timeout() {
+		return "timeout"
+	}
+	return "network"
+}
+
+// RunLog 是持久化的运行记录，在内存中修改，调用Save后写入文件
+type RunLog struct {
+	// MaxRuns 是最多保留的记录数，0表示不限制，默认为 DefaultMaxRuns
+	MaxRuns int
+
+	path     string
+	runs     []Run
+	fileMode os.FileMode
+	dirMode  os.FileMode
+}
+
+// OpenRunLog 打开运行记录文件，文件不存在时返回空的记录
+//
+// 示例:
+//
+//	l, err := store.OpenRunLog("data/runs.json")
+func OpenRunLog(path string, opts ...Option) (*RunLog, error) {
+	// 选项作用于Store，这里只取其中的权限设置
+	s := &Store{fileMode: crawler.DefaultFileMode, dirMode: crawler.DefaultDirMode}
+	for _, opt := range opts {
+		opt(s)
+	}
+	l := &RunLog{
+		MaxRuns:  DefaultMaxRuns,
+		path:     path,
+		fileMode: s.fileMode,
+		dirMode:  s.dirMode,
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取运行记录文件失败: %w", err)
+	}
+	if err := json.Unmarshal(data, &l.runs); err != nil {
+		return nil, fmt.Errorf("解析运行记录文件 %s 失败: %w", path, err)
+	}
+	return l, nil
+}
+
+// Path 返回运行记录文件的路径
+func (l *RunLog) Path() string {
+	return l.path
+}
+
+// Append 添加一次运行的记录
+func (l *RunLog) Append(run Run) {
+	l.runs = append(l.runs, run)
+}
+
+// Runs 返回所有记录，按开始时间从新到旧排列
+func (l *RunLog) Runs() []Run {
+	runs := append([]Run(nil), l.runs...)
+	sort.SliceStable(runs, func(i, j int) bool { return runs[i].Started.After(runs[j].Started) })
+	return runs
+}
+
+// Save 将记录原子地写入文件，超过 MaxRuns 的最早的记录被删除
+func (l *RunLog) Save() error {
+	runs := l.Runs()
+	if l.MaxRuns > 0 && len(runs) > l.MaxRuns {
+		runs = runs[:l.MaxRuns]
+	}
+	// 文件中按时间顺序保存，便于追加和用其他工具查看
+	for i, j := 0, len(runs)-1; i < j; i, j = i+1, j-1 {
+		runs[i], runs[j] = runs[j], runs[i]
+	}
+	l.runs = runs
+
+	data, err := json.MarshalIndent(runs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化运行记录失败: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(l.path), l.dirMode); err != nil {
+		return fmt.Errorf("创建存储目录失败: %w", err)
+	}
+	if err := crawler.WriteFileAtomic(l.path, data, l.fileMode, false); err != nil {
+		return fmt.Errorf("保存运行记录文件失败: %w", err)
+	}
+	return nil
+}
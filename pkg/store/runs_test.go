@@ -0,0 +1,95 @@
+package store
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+)
+
+// timeoutError 是一个超时的网络错误
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+var _ net.Error = timeoutError{}
+
+func TestRunRecorder(t *testing.T) {
+	start := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	rec := NewRunRecorder("search", start)
+	rec.Observe(crawler.RequestLog{Attempt: 1, StatusCode: 503, Err: errors.New("服务器错误: 503")})
+	rec.Observe(crawler.RequestLog{Attempt: 2, Err: timeoutError{}})
+	rec.Observe(crawler.RequestLog{Attempt: 3, StatusCode: 200, Size: 1000, CacheHit: true})
+	rec.Observe(crawler.RequestLog{Attempt: 1, Err: errors.New("connection refused")})
+	rec.Observe(crawler.RequestLog{Attempt: 1, StatusCode: 404, Size: 24})
+	assert.Equal(t, 5, rec.Requests())
+
+	run := rec.Finish(start.Add(time.Minute), true)
+	assert.Equal(t, Run{
+		Command:   "search",
+		Started:   start,
+		Finished:  start.Add(time.Minute),
+		Success:   true,
+		Requests:  5,
+		Retries:   2,
+		Failures:  3,
+		Bytes:     1024,
+		CacheHits: 1,
+		Errors:    map[string]int{"http_503": 1, "timeout": 1, "network": 1, "http_404": 1},
+	}, run)
+	assert.Equal(t, time.Minute, run.Duration())
+	assert.InDelta(t, 0.2, run.CacheHitRate(), 1e-9)
+	assert.Zero(t, Run{}.CacheHitRate())
+}
+
+func TestRunLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db", DefaultRunsFile)
+	l, err := OpenRunLog(path, WithPermissions(0o600, 0o700))
+	require.NoError(t, err)
+	assert.Empty(t, l.Runs())
+
+	start := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		l.Append(Run{Command: "exploit", Started: start.Add(time.Duration(i) * time.Hour), Requests: i + 1, Success: true})
+	}
+	l.MaxRuns = 2
+	require.NoError(t, l.Save())
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	l, err = OpenRunLog(path)
+	require.NoError(t, err)
+	runs := l.Runs()
+	require.Len(t, runs, 2, "超过MaxRuns时删除最早的记录")
+	assert.Equal(t, 3, runs[0].Requests, "按开始时间从新到旧排列")
+	assert.Equal(t, 2, runs[1].Requests)
+
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0o600))
+	_, err = OpenRunLog(path)
+	assert.Error(t, err)
+}
+
+func TestSumRuns(t *testing.T) {
+	start := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	total := SumRuns([]Run{
+		{Started: start.Add(time.Hour), Finished: start.Add(2 * time.Hour), Success: true, Requests: 10, Bytes: 100, CacheHits: 5, Errors: map[string]int{"timeout": 1}},
+		{Started: start, Finished: start.Add(time.Minute), Success: false, Requests: 2, Failures: 2, Errors: map[string]int{"timeout": 1, "http_503": 1}},
+	})
+	assert.Equal(t, start, total.Started)
+	assert.Equal(t, start.Add(2*time.Hour), total.Finished)
+	assert.False(t, total.Success)
+	assert.Equal(t, 12, total.Requests)
+	assert.Equal(t, int64(100), total.Bytes)
+	assert.Equal(t, map[string]int{"timeout": 2, "http_503": 1}, total.Errors)
+}
@@ -0,0 +1,238 @@
+// Package store 保存爬取到的漏洞记录，按漏洞ID去重
+//
+// 记录保存在一个JSON数组文件中，与exploit/list保存的漏洞数组格式相同，
+// 可以直接用于 sql -d、api --metrics-data 等读取结果文件的命令。
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+)
+
+// DefaultFile 是配置目录下默认的存储文件名
+const DefaultFile = "vulnerabilities.json"
+
+// Change 表示写入一条记录的结果
+type Change int
+
+const (
+	Skipped   Change = iota // 记录没有ID和URL，无法去重，未写入
+	Added                   // 新增的记录
+	Updated                 // 已有的记录补充或更新了字段
+	Unchanged               // 已有相同的记录
+)
+
+// Store 是漏洞记录的存储，在内存中修改，调用Save后写入文件
+type Store struct {
+	path     string
+	items    map[string]model.Vulnerability
+	fileMode os.FileMode
+	dirMode  os.FileMode
+}
+
+// Option 是设置Store选项的函数类型
+type Option func(*Store)
+
+// WithPermissions 设置存储文件和新建目录的权限，默认与爬虫的输出权限相同
+func WithPermissions(fileMode, dirMode os.FileMode) Option {
+	return func(s *Store) {
+		s.fileMode, s.dirMode = fileMode, dirMode
+	}
+}
+
+// Open 打开存储文件，文件不存在时返回空的存储
+//
+// 示例:
+//
+//	s, err := store.Open("data/vulnerabilities.json")
+func Open(path string, opts ...Option) (*Store, error) {
+	s := &Store{
+		path:     path,
+		items:    make(map[string]model.Vulnerability),
+		fileMode: crawler.DefaultFileMode,
+		dirMode:  crawler.DefaultDirMode,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取存储文件失败: %w", err)
+	}
+	var items []model.Vulnerability
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("解析存储文件 %s 失败: %w", path, err)
+	}
+	for _, v := range items {
+		s.Put(v)
+	}
+	return s, nil
+}
+
+// Path 返回存储文件的路径
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Len 返回记录数量
+func (s *Store) Len() int {
+	return len(s.items)
+}
+
+// Get 按漏洞ID(没有ID时为URL)返回记录
+func (s *Store) Get(key string) (model.Vulnerability, bool) {
+	v, ok := s.items[key]
+	return v, ok
+}
+
+// All 返回所有记录，按发布日期从新到旧排列，日期相同时按ID排列
+func (s *Store) All() []model.Vulnerability {
+	items := make([]model.Vulnerability, 0, len(s.items))
+	for _, v := range s.items {
+		items = append(items, v)
+	}
+	model.SortVulnerabilities(items)
+	return items
+}
+
+// Put 写入一条记录，已有相同ID的记录时合并
+// 合并时新记录中非空的字段覆盖旧值，空字段保留旧值，
+// 这样搜索结果这类字段较少的记录不会覆盖之前从详情页得到的标签、正文等信息。
+func (s *Store) Put(v model.Vulnerability) Change {
+	key := recordKey(v)
+	if key == "" {
+		return Skipped
+	}
+	old, ok := s.items[key]
+	if !ok {
+		s.items[key] = v
+		return Added
+	}
+	merged := merge(old, v)
+	if reflect.DeepEqual(old, merged) {
+		return Unchanged
+	}
+	s.items[key] = merged
+	return Updated
+}
+
+// Save 将所有记录原子地写入存储文件
+func (s *Store) Save() error {
+	data, err := json.MarshalIndent(s.All(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化记录失败: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), s.dirMode); err != nil {
+		return fmt.Errorf("创建存储目录失败: %w", err)
+	}
+	if err := crawler.WriteFileAtomic(s.path, data, s.fileMode, false); err != nil {
+		return fmt.Errorf("保存存储文件失败: %w", err)
+	}
+	return nil
+}
+
+// recordKey 返回用于去重的键：漏洞ID，没有ID时为URL
+func recordKey(v model.Vulnerability) string {
+	if v.ID != "" {
+		return v.ID
+	}
+	return v.URL
+}
+
+// merge 合并同一漏洞的两条记录，b中非空的字段覆盖a
+func merge(a, b model.Vulnerability) model.Vulnerability {
+	pick := func(old, new string) string {
+		if new != "" {
+			return new
+		}
+		return old
+	}
+	pickSlice := func(old, new []string) []string {
+		if len(new) > 0 {
+			return new
+		}
+		return old
+	}
+
+	m := a
+	if !b.Date.IsZero() {
+		m.Date = b.Date
+	}
+	m.ID = pick(a.ID, b.ID)
+	m.Title = pick(a.Title, b.Title)
+	m.URL = pick(a.URL, b.URL)
+	m.RiskLevel = pick(a.RiskLevel, b.RiskLevel)
+	// 推断的风险级别不覆盖来自标签的级别
+	if b.DerivedRisk != "" && (!b.RiskInferred || a.DerivedRisk == "" || a.RiskInferred) {
+		m.DerivedRisk, m.RiskInferred = b.DerivedRisk, b.RiskInferred
+	}
+	m.CVE = pick(a.CVE, b.CVE)
+	m.CWE = pick(a.CWE, b.CWE)
+	m.IsRemote = a.IsRemote || b.IsRemote
+	m.IsLocal = a.IsLocal || b.IsLocal
+	m.Tags = pickSlice(a.Tags, b.Tags)
+	m.Author = pick(a.Author, b.Author)
+	m.AuthorURL = pick(a.AuthorURL, b.AuthorURL)
+	// 热度来自最近一次显示计数的列表页，详情页和没有计数的列表不覆盖
+	if b.Comments > 0 {
+		m.Comments = b.Comments
+	}
+	if b.Views > 0 {
+		m.Views = b.Views
+	}
+	m.Content = pick(a.Content, b.Content)
+	m.ContentHTML = pick(a.ContentHTML, b.ContentHTML)
+	m.Description = pick(a.Description, b.Description)
+	m.AffectedVersions = pickSlice(a.AffectedVersions, b.AffectedVersions)
+	m.Platform = pick(a.Platform, b.Platform)
+	m.Techniques = pickSlice(a.Techniques, b.Techniques)
+	m.ClusterID = pick(a.ClusterID, b.ClusterID)
+	if b.Source != nil {
+		m.Source = b.Source
+	}
+	if b.AISummary != nil {
+		m.AISummary = b.AISummary
+	}
+	if b.CVEUpdate != nil {
+		m.CVEUpdate = b.CVEUpdate
+	}
+	if b.LastSeen.After(a.LastSeen) {
+		m.LastSeen = b.LastSeen
+	}
+	// 镜像跟随最近一次获取的详情页，重新从 --base-url 获取时清除
+	if b.Content != "" {
+		m.Mirror = b.Mirror
+	}
+	// 额外字段逐个合并，规则文件中新增的字段不会丢掉之前提取的字段
+	if len(b.Extras) > 0 {
+		m.Extras = make(map[string]string, len(a.Extras)+len(b.Extras))
+		for k, v := range a.Extras {
+			m.Extras[k] = v
+		}
+		for k, v := range b.Extras {
+			m.Extras[k] = v
+		}
+	}
+	// 未建模的字段同样逐个合并，页面上暂时缺少的字段保留之前的值
+	if len(b.UnknownFields) > 0 {
+		m.UnknownFields = make(map[string]string, len(a.UnknownFields)+len(b.UnknownFields))
+		for k, v := range a.UnknownFields {
+			m.UnknownFields[k] = v
+		}
+		for k, v := range b.UnknownFields {
+			m.UnknownFields[k] = v
+		}
+	}
+	return m
+}
@@ -0,0 +1,139 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/scagogogo/cxsecurity-crawler/pkg/crawler"
+	"github.com/scagogogo/cxsecurity-crawler/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorePutAndSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db", DefaultFile)
+	s, err := Open(path)
+	require.NoError(t, err)
+
+	detail := model.Vulnerability{ID: "WLB-2024010001", Title: "Detail", Tags: []string{"XSS"}, Content: "PoC"}
+	assert.Equal(t, Added, s.Put(detail))
+	assert.Equal(t, Unchanged, s.Put(detail))
+
+	// 搜索结果没有标签和正文，合并时保留旧值
+	date := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, Updated, s.Put(model.Vulnerability{ID: "WLB-2024010001", Title: "Search", Date: date}))
+	v, ok := s.Get("WLB-2024010001")
+	require.True(t, ok)
+	assert.Equal(t, "Search", v.Title)
+	assert.Equal(t, []string{"XSS"}, v.Tags)
+	assert.Equal(t, "PoC", v.Content)
+	assert.Equal(t, date, v.Date)
+
+	// 推断的风险级别不覆盖来自标签的级别
+	s.Put(model.Vulnerability{ID: "WLB-2024010001", RiskLevel: "Low", DerivedRisk: "Low"})
+	s.Put(model.Vulnerability{ID: "WLB-2024010001", DerivedRisk: "High", RiskInferred: true})
+	v, _ = s.Get("WLB-2024010001")
+	assert.Equal(t, "Low", v.DerivedRisk)
+	assert.False(t, v.RiskInferred)
+
+	// 额外字段逐个合并
+	s.Put(model.Vulnerability{ID: "WLB-2024010001", Extras: map[string]string{"a": "1", "b": "1"}})
+	s.Put(model.Vulnerability{ID: "WLB-2024010001", Extras: map[string]string{"b": "2"}})
+	v, _ = s.Get("WLB-2024010001")
+	assert.Equal(t, map[string]string{"a": "1", "b": "2"}, v.Extras)
+
+	// 未建模的字段同样逐个合并
+	s.Put(model.Vulnerability{ID: "WLB-2024010001", UnknownFields: map[string]string{"dork": "inurl:x"}})
+	s.Put(model.Vulnerability{ID: "WLB-2024010001", UnknownFields: map[string]string{"cvss_base_score": "5.4/10"}})
+	v, _ = s.Get("WLB-2024010001")
+	assert.Equal(t, map[string]string{"dork": "inurl:x", "cvss_base_score": "5.4/10"}, v.UnknownFields)
+
+	// 镜像跟随最近一次获取的详情页，列表记录不清除
+	s.Put(model.Vulnerability{ID: "WLB-2024010001", Content: "PoC", Mirror: "https://mirror.example.org"})
+	s.Put(model.Vulnerability{ID: "WLB-2024010001", Title: "Search"})
+	v, _ = s.Get("WLB-2024010001")
+	assert.Equal(t, "https://mirror.example.org", v.Mirror)
+	s.Put(model.Vulnerability{ID: "WLB-2024010001", Content: "PoC"})
+	v, _ = s.Get("WLB-2024010001")
+	assert.Empty(t, v.Mirror)
+
+	assert.Equal(t, Added, s.Put(model.Vulnerability{URL: "https://cxsecurity.com/issue/x", Title: "No ID"}))
+	assert.Equal(t, Skipped, s.Put(model.Vulnerability{Title: "Nothing"}))
+	require.NoError(t, s.Save())
+
+	// 存储文件可以作为结果文件读取
+	items, err := crawler.LoadResultFile(path)
+	require.NoError(t, err)
+	assert.Len(t, items, 2)
+	assert.Equal(t, "WLB-2024010001", items[0].ID)
+
+	reopened, err := Open(path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, reopened.Len())
+}
+
+func TestStoreImport(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	write("list.json", `{"items": [{"id": "WLB-1", "title": "A"}, {"id": "WLB-2", "title": "B"}], "current_page": 1}`)
+	write("old/search.json", `{"keyword": "xss", "vulnerabilities": [{"id": "WLB-2", "date": "2024-01-02", "title": "B"}, {"id": "WLB-3", "title": "C"}]}`)
+	write("old/detail.json", `{"id": "WLB-1", "title": "A", "tags": ["RCE"]}`)
+	write("notes.json", `{"hello": "world"}`)
+	write("readme.txt", `ignored`)
+
+	s, err := Open(filepath.Join(dir, DefaultFile))
+	require.NoError(t, err)
+	reports, err := s.Import(dir)
+	require.NoError(t, err)
+	require.Len(t, reports, 4)
+
+	byFile := make(map[string]FileReport)
+	for _, r := range reports {
+		byFile[filepath.Base(r.File)] = r
+	}
+	assert.Equal(t, 2, byFile["list.json"].Added)
+	assert.NotEmpty(t, byFile["notes.json"].Error)
+	assert.Equal(t, 1, byFile["detail.json"].Updated)
+	assert.Equal(t, 1, byFile["search.json"].Added)
+	assert.Equal(t, 1, byFile["search.json"].Updated)
+	assert.Equal(t, 3, s.Len())
+
+	// 存储文件本身在导入目录中时会被跳过
+	require.NoError(t, s.Save())
+	reports, err = s.Import(dir)
+	require.NoError(t, err)
+	assert.Len(t, reports, 4)
+	for _, r := range reports {
+		assert.Zero(t, r.Added+r.Updated, r.File)
+	}
+
+	_, err = s.Import(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
+
+func TestStoreByAuthor(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), DefaultFile))
+	require.NoError(t, err)
+	day := func(d int) time.Time { return time.Date(2024, 4, d, 0, 0, 0, 0, time.UTC) }
+	s.Put(model.Vulnerability{ID: "WLB-1", Date: day(1), Author: "hyp3rlinx", AuthorURL: "https://cxsecurity.com/author/hyp3rlinx/1/"})
+	s.Put(model.Vulnerability{ID: "WLB-2", Date: day(3), Author: "John Page", AuthorURL: "https://cxsecurity.com/author/HYP3RLINX/1/"})
+	s.Put(model.Vulnerability{ID: "WLB-3", Date: day(2), Author: "hyp3rlinx"})
+	s.Put(model.Vulnerability{ID: "WLB-4", Date: day(4), Author: "rgod", AuthorURL: "https://cxsecurity.com/author/rgod/1/"})
+	s.Put(model.Vulnerability{ID: "WLB-5", Date: day(5), Author: "indoushka", AuthorURL: "/author/Red%20Team/1/"})
+
+	var ids []string
+	for _, v := range s.ByAuthor(" Hyp3rlinx ") {
+		ids = append(ids, v.ID)
+	}
+	assert.Equal(t, []string{"WLB-2", "WLB-3", "WLB-1"}, ids)
+	assert.Len(t, s.ByAuthor("red team"), 1)
+	assert.Empty(t, s.ByAuthor("indoushka"), "有作者URL时按ID匹配")
+	assert.Empty(t, s.ByAuthor(""))
+	assert.Equal(t, "rgod", AuthorID(model.Vulnerability{AuthorURL: "https://cxsecurity.com/author/rgod/1/"}))
+	assert.Empty(t, AuthorID(model.Vulnerability{AuthorURL: "https://cxsecurity.com/issue/WLB-1"}))
+}